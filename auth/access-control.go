@@ -80,6 +80,9 @@ type AccessOptions struct {
 	Readonly        bool
 	IsPublicRequest bool
 	DisableACL      bool
+	// RequestTags are the object tags sent with the request,
+	// evaluated by the 's3:RequestObjectTag' policy conditions
+	RequestTags map[string]string
 }
 
 func VerifyAccess(ctx context.Context, be backend.Backend, opts AccessOptions) error {
@@ -105,16 +108,54 @@ func VerifyAccess(ctx context.Context, be backend.Backend, opts AccessOptions) e
 			return policyErr
 		}
 	} else {
-		return VerifyBucketPolicy(policy, opts.Acc.Access, opts.Bucket, opts.Object, opts.Action)
+		if opts.Acc.Access != opts.Acl.Owner {
+			if err := verifyPublicPolicyNotRestricted(ctx, be, opts.Bucket, policy); err != nil {
+				return err
+			}
+		}
+		pctx := NewPolicyContext(ctx, be, opts.Acc, opts.Bucket, opts.Object, opts.RequestTags)
+		return VerifyBucketPolicy(policy, pctx, opts.Bucket, opts.Object, opts.Action)
+	}
+
+	acl := opts.Acl
+	if acl.hasPublicGrants() {
+		pab, err := GetPublicAccessBlock(ctx, be, opts.Bucket)
+		if err != nil {
+			return err
+		}
+		if pab.IgnorePublicAcls {
+			acl = acl.withoutPublicGrants()
+		}
 	}
 
-	if err := verifyACL(opts.Acl, opts.Acc.Access, opts.AclPermission, opts.DisableACL); err != nil {
+	if err := verifyACL(acl, opts.Acc, opts.AclPermission, opts.DisableACL); err != nil {
 		return err
 	}
 
 	return nil
 }
 
+// verifyPublicPolicyNotRestricted denies the access of the accounts other
+// than the bucket owner to the bucket with a public policy, if the public
+// buckets are restricted
+func verifyPublicPolicyNotRestricted(ctx context.Context, be backend.Backend, bucket string, policy []byte) error {
+	bucketPolicy, err := ParsePolicyDocument(policy)
+	if err != nil || !bucketPolicy.IsPublic() {
+		// the invalid policies are reported by the policy check
+		return nil
+	}
+
+	pab, err := GetPublicAccessBlock(ctx, be, bucket)
+	if err != nil {
+		return err
+	}
+	if pab.RestrictPublicBuckets {
+		return s3err.GetAPIError(s3err.ErrAccessDenied)
+	}
+
+	return nil
+}
+
 // Detects if the action is policy related
 // e.g.
 // 'GetBucketPolicy', 'PutBucketPolicy'
@@ -122,15 +163,24 @@ func isPolicyAction(action Action) bool {
 	return action == GetBucketPolicyAction || action == PutBucketPolicyAction
 }
 
-// VerifyPublicAccess checks if the bucket is publically accessible by ACL or Policy
+// VerifyPublicAccess checks if the bucket is publically accessible by ACL or Policy,
+// unless the public access is blocked by the bucket public access block settings
 func VerifyPublicAccess(ctx context.Context, be backend.Backend, action Action, permission Permission, bucket, object string) error {
 	// ACL disabled
 	policy, err := be.GetBucketPolicy(ctx, bucket)
 	if err != nil && !errors.Is(err, s3err.GetAPIError(s3err.ErrNoSuchBucketPolicy)) {
 		return err
 	}
-	if err == nil {
-		err = VerifyPublicBucketPolicy(policy, bucket, object, action)
+	policyFound := err == nil
+
+	pab, err := GetPublicAccessBlock(ctx, be, bucket)
+	if err != nil {
+		return err
+	}
+
+	if policyFound && !pab.RestrictPublicBuckets {
+		pctx := NewPolicyContext(ctx, be, Account{}, bucket, object, nil)
+		err = VerifyPublicBucketPolicy(policy, pctx, bucket, object, action)
 		if err == nil {
 			// if ACLs are disabled, and the bucket grants public access,
 			// policy actions should return 'MethodNotAllowed'
@@ -144,7 +194,7 @@ func VerifyPublicAccess(ctx context.Context, be backend.Backend, action Action,
 
 	// if the action is not in the ACL whitelist the access is denied
 	_, ok := publicACLAllowedActions[action]
-	if !ok {
+	if !ok || pab.IgnorePublicAcls {
 		return s3err.GetAPIError(s3err.ErrAccessDenied)
 	}
 
@@ -20,6 +20,7 @@ import (
 	"encoding/xml"
 	"errors"
 	"fmt"
+	"strconv"
 	"strings"
 
 	"github.com/aws/aws-sdk-go-v2/service/s3"
@@ -51,6 +52,80 @@ type Grantee struct {
 	Type       types.Type
 }
 
+const (
+	allUsersURI           = "http://acs.amazonaws.com/groups/global/AllUsers"
+	authenticatedUsersURI = "http://acs.amazonaws.com/groups/global/AuthenticatedUsers"
+	logDeliveryURI        = "http://acs.amazonaws.com/groups/s3/LogDelivery"
+)
+
+// predefinedGroups maps the URIs of the predefined group grantees to the
+// access they are stored with
+var predefinedGroups = map[string]string{
+	allUsersURI:           "all-users",
+	authenticatedUsersURI: "authenticated-users",
+	logDeliveryURI:        "log-delivery",
+}
+
+// bucketCannedACLLogDeliveryWrite is missing from the SDK bucket canned ACLs
+const bucketCannedACLLogDeliveryWrite types.BucketCannedACL = "log-delivery-write"
+
+// GroupURI returns the URI of a group grantee. The IAM groups are
+// granted with their group ID, which is their URI.
+func GroupURI(access string) string {
+	for uri, acs := range predefinedGroups {
+		if acs == access {
+			return uri
+		}
+	}
+	return access
+}
+
+// GroupAccess returns the access of the group grantee with the uri, which
+// is either one of the predefined group URIs or the ID of an IAM group
+func GroupAccess(uri string) (string, error) {
+	if access, ok := predefinedGroups[uri]; ok {
+		return access, nil
+	}
+	for _, access := range predefinedGroups {
+		if access == uri {
+			return access, nil
+		}
+	}
+
+	// the accounts without a group have the group ID 0
+	gid, err := strconv.Atoi(uri)
+	if err != nil || gid <= 0 {
+		debuglogger.Logf("invalid group grantee: %q", uri)
+		return "", s3err.GetAPIError(s3err.ErrInvalidArgument)
+	}
+	return strconv.Itoa(gid), nil
+}
+
+// grants checks if the grantee grants the permission to the account
+func (g Grantee) grants(acct Account, permission Permission) bool {
+	if g.Permission != permission && g.Permission != PermissionFullControl {
+		return false
+	}
+
+	switch g.Type {
+	case types.TypeCanonicalUser:
+		return g.Access == acct.Access
+	case types.TypeGroup:
+		switch g.Access {
+		case "all-users", "authenticated-users":
+			// the ACLs are only checked for the authenticated requests
+			return true
+		case "log-delivery":
+			// the gateway doesn't deliver the server access logs to the buckets
+			return false
+		default:
+			return acct.GroupID != 0 && g.Access == strconv.Itoa(acct.GroupID)
+		}
+	default:
+		return false
+	}
+}
+
 type GetBucketAclOutput struct {
 	XMLName           xml.Name `xml:"http://s3.amazonaws.com/doc/2006-03-01/ AccessControlPolicy"`
 	Owner             *types.Owner
@@ -136,9 +211,11 @@ func (g *Grant) isValid() bool {
 }
 
 type Grt struct {
-	XMLNS string     `xml:"xmlns:xsi,attr"`
-	Type  types.Type `xml:"xsi:type,attr"`
-	ID    string     `xml:"ID"`
+	XMLNS        string     `xml:"xmlns:xsi,attr"`
+	Type         types.Type `xml:"xsi:type,attr"`
+	ID           string     `xml:"ID"`
+	URI          string     `xml:"URI,omitempty"`
+	EmailAddress string     `xml:"EmailAddress,omitempty"`
 }
 
 // Custom Unmarshalling for Grt to parse xsi:type properly
@@ -164,8 +241,17 @@ func (g *Grt) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
 
 		switch se := t.(type) {
 		case xml.StartElement:
-			if se.Name.Local == "ID" {
-				if err := d.DecodeElement(&g.ID, &se); err != nil {
+			var dest *string
+			switch se.Name.Local {
+			case "ID":
+				dest = &g.ID
+			case "URI":
+				dest = &g.URI
+			case "EmailAddress":
+				dest = &g.EmailAddress
+			}
+			if dest != nil {
+				if err := d.DecodeElement(dest, &se); err != nil {
 					return err
 				}
 			}
@@ -179,18 +265,44 @@ func (g *Grt) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
 
 // Validates Grt
 func (g *Grt) isValid() bool {
-	// Validate the Type
-	// Only these 2 types are supported in the gateway
-	if g.Type != types.TypeCanonicalUser && g.Type != types.TypeGroup {
+	switch g.Type {
+	case types.TypeCanonicalUser:
+		return g.ID != ""
+	case types.TypeGroup:
+		// the gateway used to identify the groups by ID
+		return g.URI != "" || g.ID != ""
+	case types.TypeAmazonCustomerByEmail:
+		return g.EmailAddress != ""
+	default:
 		return false
 	}
+}
 
-	// The ID prop shouldn't be empty
-	if g.ID == "" {
-		return false
+// grantee converts the request grantee to the stored grantee, the email
+// grantees are resolved with ResolveGrantees
+func (g *Grt) grantee(permission Permission) (Grantee, error) {
+	switch g.Type {
+	case types.TypeGroup:
+		uri := g.URI
+		if uri == "" {
+			uri = g.ID
+		}
+		access, err := GroupAccess(uri)
+		if err != nil {
+			return Grantee{}, err
+		}
+		return Grantee{Permission: permission, Access: access, Type: types.TypeGroup}, nil
+	case types.TypeAmazonCustomerByEmail:
+		if g.EmailAddress == "" {
+			return Grantee{}, s3err.GetAPIError(s3err.ErrInvalidRequest)
+		}
+		return Grantee{Permission: permission, Access: g.EmailAddress, Type: types.TypeAmazonCustomerByEmail}, nil
+	default:
+		if g.ID == "" {
+			return Grantee{}, s3err.GetAPIError(s3err.ErrInvalidRequest)
+		}
+		return Grantee{Permission: permission, Access: g.ID, Type: types.TypeCanonicalUser}, nil
 	}
-
-	return true
 }
 
 func ParseACL(data []byte) (ACL, error) {
@@ -225,13 +337,16 @@ func ParseACLOutput(data []byte, owner string) (GetBucketAclOutput, error) {
 	}
 
 	for _, elem := range acl.Grantees {
-		acs := elem.Access
+		grantee := &Grt{
+			XMLNS: "http://www.w3.org/2001/XMLSchema-instance",
+			ID:    elem.Access,
+			Type:  elem.Type,
+		}
+		if elem.Type == types.TypeGroup {
+			grantee.URI = GroupURI(elem.Access)
+		}
 		grants = append(grants, Grant{
-			Grantee: &Grt{
-				XMLNS: "http://www.w3.org/2001/XMLSchema-instance",
-				ID:    acs,
-				Type:  elem.Type,
-			},
+			Grantee:    grantee,
 			Permission: elem.Permission,
 		})
 	}
@@ -281,93 +396,68 @@ func UpdateACL(input *PutBucketAclInput, acl ACL, iam IAMService) ([]byte, error
 					Type:       types.TypeGroup,
 				},
 			}...)
+		case types.BucketCannedACLAuthenticatedRead:
+			defaultGrantees = append(defaultGrantees, Grantee{
+				Permission: PermissionRead,
+				Access:     "authenticated-users",
+				Type:       types.TypeGroup,
+			})
+		case bucketCannedACLLogDeliveryWrite:
+			defaultGrantees = append(defaultGrantees, []Grantee{
+				{
+					Permission: PermissionWrite,
+					Access:     "log-delivery",
+					Type:       types.TypeGroup,
+				},
+				{
+					Permission: PermissionReadAcp,
+					Access:     "log-delivery",
+					Type:       types.TypeGroup,
+				},
+			}...)
 		}
 	} else {
-		accs := []string{}
+		grantees := []Grantee{}
 
 		if input.GrantRead != nil || input.GrantReadACP != nil || input.GrantFullControl != nil || input.GrantWrite != nil || input.GrantWriteACP != nil {
-			fullControlList, readList, readACPList, writeList, writeACPList := []string{}, []string{}, []string{}, []string{}, []string{}
-
-			if input.GrantFullControl != nil && *input.GrantFullControl != "" {
-				fullControlList = splitUnique(*input.GrantFullControl, ",")
-				for _, str := range fullControlList {
-					defaultGrantees = append(defaultGrantees, Grantee{
-						Access:     str,
-						Permission: PermissionFullControl,
-						Type:       types.TypeCanonicalUser,
-					})
-				}
-			}
-			if input.GrantRead != nil && *input.GrantRead != "" {
-				readList = splitUnique(*input.GrantRead, ",")
-				for _, str := range readList {
-					defaultGrantees = append(defaultGrantees, Grantee{
-						Access:     str,
-						Permission: PermissionRead,
-						Type:       types.TypeCanonicalUser,
-					})
-				}
-			}
-			if input.GrantReadACP != nil && *input.GrantReadACP != "" {
-				readACPList = splitUnique(*input.GrantReadACP, ",")
-				for _, str := range readACPList {
-					defaultGrantees = append(defaultGrantees, Grantee{
-						Access:     str,
-						Permission: PermissionReadAcp,
-						Type:       types.TypeCanonicalUser,
-					})
-				}
-			}
-			if input.GrantWrite != nil && *input.GrantWrite != "" {
-				writeList = splitUnique(*input.GrantWrite, ",")
-				for _, str := range writeList {
-					defaultGrantees = append(defaultGrantees, Grantee{
-						Access:     str,
-						Permission: PermissionWrite,
-						Type:       types.TypeCanonicalUser,
-					})
+			for _, gh := range []struct {
+				grant      *string
+				permission Permission
+			}{
+				{input.GrantFullControl, PermissionFullControl},
+				{input.GrantRead, PermissionRead},
+				{input.GrantReadACP, PermissionReadAcp},
+				{input.GrantWrite, PermissionWrite},
+				{input.GrantWriteACP, PermissionWriteAcp},
+			} {
+				if gh.grant == nil || *gh.grant == "" {
+					continue
 				}
-			}
-			if input.GrantWriteACP != nil && *input.GrantWriteACP != "" {
-				writeACPList = splitUnique(*input.GrantWriteACP, ",")
-				for _, str := range writeACPList {
-					defaultGrantees = append(defaultGrantees, Grantee{
-						Access:     str,
-						Permission: PermissionWriteAcp,
-						Type:       types.TypeCanonicalUser,
-					})
+				grts, err := ParseGrantHeader(*gh.grant, gh.permission)
+				if err != nil {
+					return nil, err
 				}
+				grantees = append(grantees, grts...)
 			}
-
-			accs = append(append(append(append(fullControlList, readList...), writeACPList...), readACPList...), writeList...)
 		} else {
-			cache := make(map[string]bool)
 			for _, grt := range input.AccessControlPolicy.AccessControlList.Grants {
-				if grt.Grantee == nil || grt.Grantee.ID == "" || grt.Permission == "" {
+				if grt.Grantee == nil || grt.Permission == "" {
 					return nil, s3err.GetAPIError(s3err.ErrInvalidRequest)
 				}
 
-				access := grt.Grantee.ID
-				defaultGrantees = append(defaultGrantees, Grantee{
-					Access:     access,
-					Permission: grt.Permission,
-					Type:       types.TypeCanonicalUser,
-				})
-				if _, ok := cache[access]; !ok {
-					cache[access] = true
-					accs = append(accs, access)
+				grantee, err := grt.Grantee.grantee(grt.Permission)
+				if err != nil {
+					return nil, err
 				}
+				grantees = append(grantees, grantee)
 			}
 		}
 
-		// Check if the specified accounts exist
-		accList, err := CheckIfAccountsExist(accs, iam)
+		grantees, err := ResolveGrantees(grantees, iam)
 		if err != nil {
 			return nil, err
 		}
-		if len(accList) > 0 {
-			return nil, fmt.Errorf("accounts does not exist: %s", strings.Join(accList, ", "))
-		}
+		defaultGrantees = append(defaultGrantees, grantees...)
 	}
 
 	acl.Grantees = defaultGrantees
@@ -380,6 +470,125 @@ func UpdateACL(input *PutBucketAclInput, acl ACL, iam IAMService) ([]byte, error
 	return result, nil
 }
 
+// ParseGrantHeader parses the grantees of a x-amz-grant-* header: a comma
+// separated list of 'id="<access>"', 'uri="<group uri>"' and
+// 'emailAddress="<email>"' grantees, where the grantees without a type are
+// account access keys. The email grantees are resolved with ResolveGrantees.
+func ParseGrantHeader(header string, permission Permission) ([]Grantee, error) {
+	grantees := []Grantee{}
+	seen := map[Grantee]bool{}
+
+	for elem := range strings.SplitSeq(header, ",") {
+		elem = strings.TrimSpace(elem)
+		if elem == "" {
+			continue
+		}
+
+		grantee := Grantee{
+			Permission: permission,
+			Access:     elem,
+			Type:       types.TypeCanonicalUser,
+		}
+		if key, value, ok := strings.Cut(elem, "="); ok {
+			value = strings.Trim(strings.TrimSpace(value), `"`)
+			switch strings.ToLower(strings.TrimSpace(key)) {
+			case "id":
+				grantee.Access = value
+			case "uri":
+				access, err := GroupAccess(value)
+				if err != nil {
+					return nil, err
+				}
+				grantee.Access, grantee.Type = access, types.TypeGroup
+			case "emailaddress":
+				grantee.Access, grantee.Type = value, types.TypeAmazonCustomerByEmail
+			}
+		}
+		if grantee.Access == "" {
+			debuglogger.Logf("invalid grantee: %q", elem)
+			return nil, s3err.GetAPIError(s3err.ErrInvalidArgument)
+		}
+
+		if !seen[grantee] {
+			seen[grantee] = true
+			grantees = append(grantees, grantee)
+		}
+	}
+
+	return grantees, nil
+}
+
+// ResolveGrantees resolves the email grantees to the accounts with the
+// email addresses, and checks if the granted accounts exist
+func ResolveGrantees(grantees []Grantee, iam IAMService) ([]Grantee, error) {
+	result := make([]Grantee, 0, len(grantees))
+	accs := []string{}
+	cache := make(map[string]bool)
+
+	for _, grt := range grantees {
+		switch grt.Type {
+		case types.TypeAmazonCustomerByEmail:
+			acc, err := GetUserAccountByEmail(iam, grt.Access)
+			if err == ErrNoSuchUser {
+				debuglogger.Logf("no account with the email address %q", grt.Access)
+				return nil, s3err.GetAPIError(s3err.ErrUnresolvableGrantByEmailAddress)
+			}
+			if errors.Is(err, s3err.GetAPIError(s3err.ErrAdminMethodNotSupported)) {
+				return nil, err
+			}
+			if err != nil {
+				return nil, fmt.Errorf("resolve email grantee: %w", err)
+			}
+			grt.Access, grt.Type = acc.Access, types.TypeCanonicalUser
+		case types.TypeCanonicalUser:
+			if !cache[grt.Access] {
+				cache[grt.Access] = true
+				accs = append(accs, grt.Access)
+			}
+		}
+		result = append(result, grt)
+	}
+
+	// Check if the specified accounts exist
+	accList, err := CheckIfAccountsExist(accs, iam)
+	if err != nil {
+		return nil, err
+	}
+	if len(accList) > 0 {
+		return nil, fmt.Errorf("accounts does not exist: %s", strings.Join(accList, ", "))
+	}
+
+	return result, nil
+}
+
+// ResolveGrantHeader resolves the email grantees of a grant header to the
+// granted accounts, for the backends storing the grant headers
+func ResolveGrantHeader(header string, iam IAMService) (string, error) {
+	if header == "" {
+		return header, nil
+	}
+
+	grantees, err := ParseGrantHeader(header, "")
+	if err != nil {
+		return "", err
+	}
+	grantees, err = ResolveGrantees(grantees, iam)
+	if err != nil {
+		return "", err
+	}
+
+	elems := make([]string, 0, len(grantees))
+	for _, grt := range grantees {
+		if grt.Type == types.TypeGroup {
+			elems = append(elems, `uri="`+GroupURI(grt.Access)+`"`)
+			continue
+		}
+		elems = append(elems, `id="`+grt.Access+`"`)
+	}
+
+	return strings.Join(elems, ", "), nil
+}
+
 func CheckIfAccountsExist(accs []string, iam IAMService) ([]string, error) {
 	result := []string{}
 
@@ -399,61 +608,23 @@ func CheckIfAccountsExist(accs []string, iam IAMService) ([]string, error) {
 	return result, nil
 }
 
-func splitUnique(s, divider string) []string {
-	elements := strings.Split(s, divider)
-	uniqueElements := make(map[string]bool)
-	result := make([]string, 0, len(elements))
-
-	for _, element := range elements {
-		if _, ok := uniqueElements[element]; !ok {
-			result = append(result, element)
-			uniqueElements[element] = true
-		}
-	}
-
-	return result
-}
-
-func verifyACL(acl ACL, access string, permission Permission, disableACL bool) error {
+func verifyACL(acl ACL, acct Account, permission Permission, disableACL bool) error {
 	if disableACL {
 		// only the bucket owner should have access to the bucket
 		// as bucket ACLs are disabled and no grantee check is necessary
-		if acl.Owner != access {
+		if acl.Owner != acct.Access {
 			return s3err.GetAPIError(s3err.ErrAccessDenied)
 		}
 
 		return nil
 	}
 
-	grantee := Grantee{
-		Access:     access,
-		Permission: permission,
-		Type:       types.TypeCanonicalUser,
-	}
-	granteeFullCtrl := Grantee{
-		Access:     access,
-		Permission: PermissionFullControl,
-		Type:       types.TypeCanonicalUser,
-	}
-	granteeAllUsers := Grantee{
-		Access:     "all-users",
-		Permission: permission,
-		Type:       types.TypeGroup,
-	}
-
-	isFound := false
-
 	for _, grt := range acl.Grantees {
-		if grt == grantee || grt == granteeFullCtrl || grt == granteeAllUsers {
-			isFound = true
-			break
+		if grt.grants(acct, permission) {
+			return nil
 		}
 	}
 
-	if isFound {
-		return nil
-	}
-
 	return s3err.GetAPIError(s3err.ErrAccessDenied)
 }
 
@@ -508,7 +679,8 @@ func UpdateBucketACLOwner(ctx context.Context, be backend.Backend, bucket, newOw
 // ValidateCannedACL validates bucket canned acl value
 func ValidateCannedACL(acl types.BucketCannedACL) error {
 	switch types.BucketCannedACL(acl) {
-	case types.BucketCannedACLPrivate, types.BucketCannedACLPublicRead, types.BucketCannedACLPublicReadWrite, "":
+	case types.BucketCannedACLPrivate, types.BucketCannedACLPublicRead, types.BucketCannedACLPublicReadWrite,
+		types.BucketCannedACLAuthenticatedRead, bucketCannedACLLogDeliveryWrite, "":
 		return nil
 	default:
 		debuglogger.Logf("invalid bucket canned acl: %v", acl)
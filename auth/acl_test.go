@@ -0,0 +1,245 @@
+// Copyright 2026 Versity Software
+// This file is licensed under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package auth
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/versity/versitygw/backend"
+	"github.com/versity/versitygw/s3err"
+)
+
+// testIAM is an IAM service with a fixed list of accounts
+type testIAM struct {
+	IAMService
+	accounts []Account
+}
+
+func (t testIAM) GetUserAccount(access string) (Account, error) {
+	for _, acc := range t.accounts {
+		if acc.Access == access {
+			return acc, nil
+		}
+	}
+	return Account{}, ErrNoSuchUser
+}
+
+func (t testIAM) ListUserAccounts() ([]Account, error) {
+	return t.accounts, nil
+}
+
+var aclTestIAM = testIAM{
+	accounts: []Account{
+		{Access: "owner"},
+		{Access: "user", Email: "User@Example.com", GroupID: 1000},
+	},
+}
+
+func TestParseGrantHeader(t *testing.T) {
+	tests := []struct {
+		name    string
+		header  string
+		want    []Grantee
+		wantErr error
+	}{
+		{
+			name:   "access keys",
+			header: "user,owner,user",
+			want: []Grantee{
+				{Permission: PermissionRead, Access: "user", Type: types.TypeCanonicalUser},
+				{Permission: PermissionRead, Access: "owner", Type: types.TypeCanonicalUser},
+			},
+		},
+		{
+			name:   "typed grantees",
+			header: `id="user", uri="http://acs.amazonaws.com/groups/global/AuthenticatedUsers", emailAddress="user@example.com", uri="1000"`,
+			want: []Grantee{
+				{Permission: PermissionRead, Access: "user", Type: types.TypeCanonicalUser},
+				{Permission: PermissionRead, Access: "authenticated-users", Type: types.TypeGroup},
+				{Permission: PermissionRead, Access: "user@example.com", Type: types.TypeAmazonCustomerByEmail},
+				{Permission: PermissionRead, Access: "1000", Type: types.TypeGroup},
+			},
+		},
+		{
+			name:    "invalid group",
+			header:  `uri="http://acs.amazonaws.com/groups/global/Nobody"`,
+			wantErr: s3err.GetAPIError(s3err.ErrInvalidArgument),
+		},
+		{
+			name:    "group without ID",
+			header:  `uri="0"`,
+			wantErr: s3err.GetAPIError(s3err.ErrInvalidArgument),
+		},
+		{
+			name:    "empty id",
+			header:  `id=""`,
+			wantErr: s3err.GetAPIError(s3err.ErrInvalidArgument),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseGrantHeader(tt.header, PermissionRead)
+			if tt.wantErr != nil {
+				assert.Equal(t, tt.wantErr, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestUpdateACL(t *testing.T) {
+	ownerGrantee := Grantee{Permission: PermissionFullControl, Access: "owner", Type: types.TypeCanonicalUser}
+	tests := []struct {
+		name    string
+		input   *PutBucketAclInput
+		want    []Grantee
+		wantErr error
+	}{
+		{
+			name:  "canned authenticated-read",
+			input: &PutBucketAclInput{ACL: types.BucketCannedACLAuthenticatedRead},
+			want: []Grantee{
+				ownerGrantee,
+				{Permission: PermissionRead, Access: "authenticated-users", Type: types.TypeGroup},
+			},
+		},
+		{
+			name:  "canned log-delivery-write",
+			input: &PutBucketAclInput{ACL: "log-delivery-write"},
+			want: []Grantee{
+				ownerGrantee,
+				{Permission: PermissionWrite, Access: "log-delivery", Type: types.TypeGroup},
+				{Permission: PermissionReadAcp, Access: "log-delivery", Type: types.TypeGroup},
+			},
+		},
+		{
+			name: "email grant header",
+			input: &PutBucketAclInput{
+				GrantWrite: backend.GetPtrFromString(`emailAddress="user@example.com"`),
+			},
+			want: []Grantee{
+				ownerGrantee,
+				{Permission: PermissionWrite, Access: "user", Type: types.TypeCanonicalUser},
+			},
+		},
+		{
+			name: "unresolvable email grant header",
+			input: &PutBucketAclInput{
+				GrantWrite: backend.GetPtrFromString(`emailAddress="nobody@example.com"`),
+			},
+			wantErr: s3err.GetAPIError(s3err.ErrUnresolvableGrantByEmailAddress),
+		},
+		{
+			name: "access control policy groups",
+			input: &PutBucketAclInput{
+				AccessControlPolicy: &AccessControlPolicy{
+					AccessControlList: AccessControlList{
+						Grants: []Grant{
+							{
+								Grantee:    &Grt{Type: types.TypeGroup, URI: "http://acs.amazonaws.com/groups/s3/LogDelivery"},
+								Permission: PermissionWrite,
+							},
+							{
+								Grantee:    &Grt{Type: types.TypeGroup, URI: "1000"},
+								Permission: PermissionRead,
+							},
+							{
+								Grantee:    &Grt{Type: types.TypeAmazonCustomerByEmail, EmailAddress: "user@example.com"},
+								Permission: PermissionReadAcp,
+							},
+						},
+					},
+				},
+			},
+			want: []Grantee{
+				ownerGrantee,
+				{Permission: PermissionWrite, Access: "log-delivery", Type: types.TypeGroup},
+				{Permission: PermissionRead, Access: "1000", Type: types.TypeGroup},
+				{Permission: PermissionReadAcp, Access: "user", Type: types.TypeCanonicalUser},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := UpdateACL(tt.input, ACL{Owner: "owner"}, aclTestIAM)
+			if tt.wantErr != nil {
+				assert.Equal(t, tt.wantErr, err)
+				return
+			}
+			assert.NoError(t, err)
+
+			acl, err := ParseACL(data)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, acl.Grantees)
+		})
+	}
+}
+
+func TestVerifyACL_groups(t *testing.T) {
+	acl := ACL{
+		Owner: "owner",
+		Grantees: []Grantee{
+			{Permission: PermissionRead, Access: "authenticated-users", Type: types.TypeGroup},
+			{Permission: PermissionFullControl, Access: "1000", Type: types.TypeGroup},
+			{Permission: PermissionWrite, Access: "log-delivery", Type: types.TypeGroup},
+		},
+	}
+
+	// any authenticated account can read
+	assert.NoError(t, verifyACL(acl, Account{Access: "other"}, PermissionRead, false))
+	assert.Error(t, verifyACL(acl, Account{Access: "other"}, PermissionWrite, false))
+	// the IAM group members have full control
+	assert.NoError(t, verifyACL(acl, Account{Access: "member", GroupID: 1000}, PermissionWrite, false))
+	// the accounts without a group are not in the group 0
+	acl.Grantees = append(acl.Grantees, Grantee{Permission: PermissionWrite, Access: "0", Type: types.TypeGroup})
+	assert.Error(t, verifyACL(acl, Account{Access: "other"}, PermissionWrite, false))
+}
+
+func TestParseACLOutput_groups(t *testing.T) {
+	data, err := json.Marshal(ACL{
+		Owner: "owner",
+		Grantees: []Grantee{
+			{Permission: PermissionRead, Access: "all-users", Type: types.TypeGroup},
+			{Permission: PermissionRead, Access: "1000", Type: types.TypeGroup},
+		},
+	})
+	assert.NoError(t, err)
+
+	out, err := ParseACLOutput(data, "owner")
+	assert.NoError(t, err)
+
+	grants := out.AccessControlList.Grants
+	assert.Len(t, grants, 2)
+	assert.Equal(t, "all-users", grants[0].Grantee.ID)
+	assert.Equal(t, "http://acs.amazonaws.com/groups/global/AllUsers", grants[0].Grantee.URI)
+	assert.Equal(t, "1000", grants[1].Grantee.URI)
+
+	// the group URIs round trip through the access control policy
+	body, err := xml.Marshal(AccessControlPolicy{
+		AccessControlList: out.AccessControlList,
+		Owner:             out.Owner,
+	})
+	assert.NoError(t, err)
+	var acp AccessControlPolicy
+	assert.NoError(t, xml.Unmarshal(body, &acp))
+	assert.NoError(t, acp.Validate())
+	assert.Equal(t, "http://acs.amazonaws.com/groups/global/AllUsers", acp.AccessControlList.Grants[0].Grantee.URI)
+}
@@ -41,6 +41,8 @@ const (
 	policyErrEmptyStatement       = policyErr("Could not parse the policy: Statement is empty!")
 	policyErrMissingStatmentField = policyErr("Missing required field Statement")
 	policyErrInvalidVersion       = policyErr("The policy must contain a valid version string")
+	policyErrInvalidCondition     = policyErr("Invalid Condition type")
+	policyErrInvalidConditionKey  = policyErr("Policy has an invalid condition key")
 )
 
 type BucketPolicy struct {
@@ -89,10 +91,10 @@ func (bp *BucketPolicy) Validate(bucket string, iam IAMService) error {
 	return nil
 }
 
-func (bp *BucketPolicy) isAllowed(principal string, action Action, resource string) bool {
+func (bp *BucketPolicy) isAllowed(principal string, action Action, resource string, pctx *PolicyContext) bool {
 	var isAllowed bool
 	for _, statement := range bp.Statement {
-		if statement.findMatch(principal, action, resource) {
+		if statement.findMatch(principal, action, resource, pctx) {
 			switch statement.Effect {
 			case BucketPolicyAccessTypeAllow:
 				isAllowed = true
@@ -107,10 +109,10 @@ func (bp *BucketPolicy) isAllowed(principal string, action Action, resource stri
 
 // IsPublicFor checks if the bucket policy statements contain
 // an entity granting public access to the given resource and action
-func (bp *BucketPolicy) isPublicFor(resource string, action Action) bool {
+func (bp *BucketPolicy) isPublicFor(resource string, action Action, pctx *PolicyContext) bool {
 	var isAllowed bool
 	for _, statement := range bp.Statement {
-		if statement.isPublicFor(resource, action) {
+		if statement.isPublicFor(resource, action, pctx) {
 			switch statement.Effect {
 			case BucketPolicyAccessTypeAllow:
 				isAllowed = true
@@ -140,6 +142,7 @@ type BucketPolicyItem struct {
 	Principals Principals             `json:"Principal"`
 	Actions    Actions                `json:"Action"`
 	Resources  Resources              `json:"Resource"`
+	Conditions Conditions             `json:"Condition,omitempty"`
 }
 
 func (bpi *BucketPolicyItem) Validate(bucket string, iam IAMService) error {
@@ -152,6 +155,9 @@ func (bpi *BucketPolicyItem) Validate(bucket string, iam IAMService) error {
 	if err := bpi.Resources.Validate(bucket); err != nil {
 		return err
 	}
+	if err := bpi.Conditions.Validate(); err != nil {
+		return err
+	}
 
 	containsObjectAction := bpi.Resources.ContainsObjectPattern()
 	containsBucketAction := bpi.Resources.ContainsBucketPattern()
@@ -172,9 +178,11 @@ func (bpi *BucketPolicyItem) Validate(bucket string, iam IAMService) error {
 	return nil
 }
 
-func (bpi *BucketPolicyItem) findMatch(principal string, action Action, resource string) bool {
+func (bpi *BucketPolicyItem) findMatch(principal string, action Action, resource string, pctx *PolicyContext) bool {
 	if bpi.Principals.Contains(principal) && bpi.Actions.FindMatch(action) && bpi.Resources.FindMatch(resource) {
-		return true
+		// the conditions are evaluated last, as they
+		// may need the tags from the backend
+		return bpi.Conditions.evaluate(pctx, bpi.Effect == BucketPolicyAccessTypeDeny)
 	}
 
 	return false
@@ -182,8 +190,9 @@ func (bpi *BucketPolicyItem) findMatch(principal string, action Action, resource
 
 // isPublicFor checks if the bucket policy statemant grants public access
 // for given resource and action
-func (bpi *BucketPolicyItem) isPublicFor(resource string, action Action) bool {
-	return bpi.Principals.isPublic() && bpi.Actions.FindMatch(action) && bpi.Resources.FindMatch(resource)
+func (bpi *BucketPolicyItem) isPublicFor(resource string, action Action, pctx *PolicyContext) bool {
+	return bpi.Principals.isPublic() && bpi.Actions.FindMatch(action) && bpi.Resources.FindMatch(resource) &&
+		bpi.Conditions.evaluate(pctx, bpi.Effect == BucketPolicyAccessTypeDeny)
 }
 
 // isPublic checks if the statement grants public access
@@ -234,7 +243,9 @@ func ValidatePolicyDocument(policyBin []byte, bucket string, iam IAMService) err
 	return nil
 }
 
-func VerifyBucketPolicy(policy []byte, access, bucket, object string, action Action) error {
+// VerifyBucketPolicy checks if the bucket policy allows the action for the
+// requester in pctx. The statement conditions are evaluated against pctx.
+func VerifyBucketPolicy(policy []byte, pctx *PolicyContext, bucket, object string, action Action) error {
 	var bucketPolicy BucketPolicy
 	if err := json.Unmarshal(policy, &bucketPolicy); err != nil {
 		return fmt.Errorf("failed to parse the bucket policy: %w", err)
@@ -245,7 +256,13 @@ func VerifyBucketPolicy(policy []byte, access, bucket, object string, action Act
 		resource += "/" + object
 	}
 
-	if !bucketPolicy.isAllowed(access, action, resource) {
+	isAllowed := bucketPolicy.isAllowed(pctx.Account.Access, action, resource, pctx)
+	// a failed tag lookup may have kept a Deny statement from matching,
+	// so the access is not granted by the Allow statements
+	if err := pctx.err(); err != nil {
+		return err
+	}
+	if !isAllowed {
 		return s3err.GetAPIError(s3err.ErrAccessDenied)
 	}
 
@@ -253,7 +270,7 @@ func VerifyBucketPolicy(policy []byte, access, bucket, object string, action Act
 }
 
 // Checks if the bucket policy grants public access
-func VerifyPublicBucketPolicy(policy []byte, bucket, object string, action Action) error {
+func VerifyPublicBucketPolicy(policy []byte, pctx *PolicyContext, bucket, object string, action Action) error {
 	var bucketPolicy BucketPolicy
 	if err := json.Unmarshal(policy, &bucketPolicy); err != nil {
 		return err
@@ -264,7 +281,11 @@ func VerifyPublicBucketPolicy(policy []byte, bucket, object string, action Actio
 		resource += "/" + object
 	}
 
-	if !bucketPolicy.isPublicFor(resource, action) {
+	isPublic := bucketPolicy.isPublicFor(resource, action, pctx)
+	if err := pctx.err(); err != nil {
+		return err
+	}
+	if !isPublic {
 		return ErrAccessDenied
 	}
 
@@ -0,0 +1,487 @@
+// Copyright 2026 Versity Software
+// This file is licensed under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/versity/versitygw/backend"
+	"github.com/versity/versitygw/s3err"
+)
+
+type ConditionOperator string
+
+const (
+	ConditionStringEquals              ConditionOperator = "StringEquals"
+	ConditionStringNotEquals           ConditionOperator = "StringNotEquals"
+	ConditionStringEqualsIgnoreCase    ConditionOperator = "StringEqualsIgnoreCase"
+	ConditionStringNotEqualsIgnoreCase ConditionOperator = "StringNotEqualsIgnoreCase"
+	ConditionStringLike                ConditionOperator = "StringLike"
+	ConditionStringNotLike             ConditionOperator = "StringNotLike"
+	ConditionNumericEquals             ConditionOperator = "NumericEquals"
+	ConditionNumericNotEquals          ConditionOperator = "NumericNotEquals"
+	ConditionNumericLessThan           ConditionOperator = "NumericLessThan"
+	ConditionNumericLessThanEquals     ConditionOperator = "NumericLessThanEquals"
+	ConditionNumericGreaterThan        ConditionOperator = "NumericGreaterThan"
+	ConditionNumericGreaterThanEquals  ConditionOperator = "NumericGreaterThanEquals"
+	ConditionNull                      ConditionOperator = "Null"
+)
+
+// ifExistsSuffix may be appended to any operator except 'Null'
+// to make the condition match when the key is not present
+const ifExistsSuffix = "IfExists"
+
+var supportedConditionOperators = map[ConditionOperator]struct{}{
+	ConditionStringEquals:              {},
+	ConditionStringNotEquals:           {},
+	ConditionStringEqualsIgnoreCase:    {},
+	ConditionStringNotEqualsIgnoreCase: {},
+	ConditionStringLike:                {},
+	ConditionStringNotLike:             {},
+	ConditionNumericEquals:             {},
+	ConditionNumericNotEquals:          {},
+	ConditionNumericLessThan:           {},
+	ConditionNumericLessThanEquals:     {},
+	ConditionNumericGreaterThan:        {},
+	ConditionNumericGreaterThanEquals:  {},
+	ConditionNull:                      {},
+}
+
+// The supported condition keys. The tag keys are prefixes
+// followed by the tag key, e.g. 's3:ExistingObjectTag/project'
+const (
+	conditionKeyExistingObjectTag = "s3:existingobjecttag/"
+	conditionKeyRequestObjectTag  = "s3:requestobjecttag/"
+	conditionKeyResourceTag       = "aws:resourcetag/"
+	conditionKeyUsername          = "aws:username"
+	conditionKeyUserID            = "vgw:userid"
+	conditionKeyGroupID           = "vgw:groupid"
+	conditionKeyProjectID         = "vgw:projectid"
+	conditionKeyTenant            = "vgw:tenant"
+)
+
+var conditionTagKeyPrefixes = []string{
+	conditionKeyExistingObjectTag,
+	conditionKeyRequestObjectTag,
+	conditionKeyResourceTag,
+}
+
+var conditionAccountKeys = map[string]struct{}{
+	conditionKeyUsername:  {},
+	conditionKeyUserID:    {},
+	conditionKeyGroupID:   {},
+	conditionKeyProjectID: {},
+	conditionKeyTenant:    {},
+}
+
+// Conditions is the statement 'Condition' block:
+// operator -> condition key -> values
+// The values in a single key are ORed, all the keys and
+// operators are ANDed
+type Conditions map[ConditionOperator]map[string][]string
+
+// Override UnmarshalJSON method to decode both single and
+// list condition values of strings, numbers and booleans
+func (c *Conditions) UnmarshalJSON(data []byte) error {
+	var raw map[ConditionOperator]map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return policyErrInvalidCondition
+	}
+
+	*c = make(Conditions, len(raw))
+	for op, keys := range raw {
+		if len(keys) == 0 {
+			return policyErrInvalidCondition
+		}
+		values := make(map[string][]string, len(keys))
+		for key, rawValue := range keys {
+			vals, err := parseConditionValues(rawValue)
+			if err != nil {
+				return err
+			}
+			values[key] = vals
+		}
+		(*c)[op] = values
+	}
+
+	return nil
+}
+
+func parseConditionValues(data json.RawMessage) ([]string, error) {
+	var list []json.RawMessage
+	if err := json.Unmarshal(data, &list); err != nil {
+		list = []json.RawMessage{data}
+	}
+	if len(list) == 0 {
+		return nil, policyErrInvalidCondition
+	}
+
+	result := make([]string, 0, len(list))
+	for _, item := range list {
+		var s string
+		if err := json.Unmarshal(item, &s); err == nil {
+			result = append(result, s)
+			continue
+		}
+		var n json.Number
+		if err := json.Unmarshal(item, &n); err == nil {
+			result = append(result, n.String())
+			continue
+		}
+		var b bool
+		if err := json.Unmarshal(item, &b); err == nil {
+			result = append(result, strconv.FormatBool(b))
+			continue
+		}
+		return nil, policyErrInvalidCondition
+	}
+
+	return result, nil
+}
+
+// Validate checks the condition operators and keys to be supported
+func (c Conditions) Validate() error {
+	for op, keys := range c {
+		base, ifExists := op.split()
+		if _, ok := supportedConditionOperators[base]; !ok {
+			return policyErrInvalidCondition
+		}
+		if base == ConditionNull && ifExists {
+			return policyErrInvalidCondition
+		}
+		for key, values := range keys {
+			if !isValidConditionKey(key) {
+				return policyErrInvalidConditionKey
+			}
+			for _, value := range values {
+				if err := validateConditionValue(base, value); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+func isValidConditionKey(key string) bool {
+	key = strings.ToLower(key)
+	if _, ok := conditionAccountKeys[key]; ok {
+		return true
+	}
+	for _, prefix := range conditionTagKeyPrefixes {
+		if strings.HasPrefix(key, prefix) && len(key) > len(prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func validateConditionValue(op ConditionOperator, value string) error {
+	switch {
+	case op == ConditionNull:
+		if _, err := strconv.ParseBool(value); err != nil {
+			return policyErrInvalidCondition
+		}
+	case op.isNumeric() && !hasPolicyVariable(value):
+		if _, err := strconv.ParseFloat(value, 64); err != nil {
+			return policyErrInvalidCondition
+		}
+	}
+	return nil
+}
+
+// split separates the 'IfExists' suffix from the operator
+func (op ConditionOperator) split() (ConditionOperator, bool) {
+	base, found := strings.CutSuffix(string(op), ifExistsSuffix)
+	return ConditionOperator(base), found
+}
+
+func (op ConditionOperator) isNumeric() bool {
+	return strings.HasPrefix(string(op), "Numeric")
+}
+
+// isNegated reports the operators, which match when none
+// of the condition values match
+func (op ConditionOperator) isNegated() bool {
+	switch op {
+	case ConditionStringNotEquals, ConditionStringNotEqualsIgnoreCase,
+		ConditionStringNotLike, ConditionNumericNotEquals:
+		return true
+	}
+	return false
+}
+
+// evaluate checks if all the conditions are satisfied by the request.
+// The conditions with an unsupported operator or key, e.g. of a policy
+// stored before the conditions were evaluated, match in the Deny
+// statements, so these keep denying the access, and never match in the
+// Allow statements.
+func (c Conditions) evaluate(pctx *PolicyContext, deny bool) bool {
+	for op, keys := range c {
+		base, ifExists := op.split()
+		if _, ok := supportedConditionOperators[base]; !ok || (base == ConditionNull && ifExists) {
+			if deny {
+				continue
+			}
+			return false
+		}
+		for key, values := range keys {
+			if !isValidConditionKey(key) {
+				if deny {
+					continue
+				}
+				return false
+			}
+			actual, found := pctx.value(key)
+			if base == ConditionNull {
+				// "Null": "true" matches a missing key
+				if len(values) == 0 || strconv.FormatBool(!found) != strings.ToLower(values[0]) {
+					return false
+				}
+				continue
+			}
+			if !found {
+				if ifExists || base.isNegated() {
+					continue
+				}
+				return false
+			}
+			if !base.matchAny(actual, values, pctx) {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// matchAny compares the request value with the condition values.
+// The negated operators succeed only if none of the values match.
+func (op ConditionOperator) matchAny(actual string, values []string, pctx *PolicyContext) bool {
+	negated := op.isNegated()
+	for _, value := range values {
+		if op.match(actual, pctx.substitute(value)) {
+			return !negated
+		}
+	}
+	return negated
+}
+
+func (op ConditionOperator) match(actual, expected string) bool {
+	switch op {
+	case ConditionStringEquals, ConditionStringNotEquals:
+		return actual == expected
+	case ConditionStringEqualsIgnoreCase, ConditionStringNotEqualsIgnoreCase:
+		return strings.EqualFold(actual, expected)
+	case ConditionStringLike, ConditionStringNotLike:
+		return matchPattern(expected, actual)
+	}
+
+	a, err := strconv.ParseFloat(actual, 64)
+	if err != nil {
+		return false
+	}
+	e, err := strconv.ParseFloat(expected, 64)
+	if err != nil {
+		return false
+	}
+
+	switch op {
+	case ConditionNumericEquals, ConditionNumericNotEquals:
+		return a == e
+	case ConditionNumericLessThan:
+		return a < e
+	case ConditionNumericLessThanEquals:
+		return a <= e
+	case ConditionNumericGreaterThan:
+		return a > e
+	case ConditionNumericGreaterThanEquals:
+		return a >= e
+	}
+	return false
+}
+
+func hasPolicyVariable(value string) bool {
+	return strings.Contains(value, "${")
+}
+
+// PolicyContext holds the request attributes the bucket policy
+// conditions are evaluated against. The existing object and bucket
+// tags are loaded from the backend on first use and cached for
+// the rest of the request.
+type PolicyContext struct {
+	// Account is the requester account. It is empty for the
+	// anonymous requests.
+	Account Account
+	// RequestTags are the tags sent with the request
+	// (e.g. 'x-amz-tagging' header or PutObjectTagging body)
+	RequestTags map[string]string
+
+	ctx    context.Context
+	be     backend.Backend
+	bucket string
+	object string
+
+	objectTags tagCache
+	bucketTags tagCache
+}
+
+type tagCache struct {
+	loaded bool
+	tags   map[string]string
+	err    error
+}
+
+// NewPolicyContext creates the condition evaluation context of
+// a request on the given bucket and object
+func NewPolicyContext(ctx context.Context, be backend.Backend, acct Account, bucket, object string, requestTags map[string]string) *PolicyContext {
+	return &PolicyContext{
+		Account:     acct,
+		RequestTags: requestTags,
+		ctx:         ctx,
+		be:          be,
+		bucket:      bucket,
+		object:      object,
+	}
+}
+
+// err returns the first tag lookup failure, that is not
+// a missing object or tag set
+func (pctx *PolicyContext) err() error {
+	if pctx == nil {
+		return nil
+	}
+	if pctx.objectTags.err != nil {
+		return pctx.objectTags.err
+	}
+	return pctx.bucketTags.err
+}
+
+// value returns the request value of the condition key
+// and whether the key is present in the request
+func (pctx *PolicyContext) value(key string) (string, bool) {
+	if pctx == nil {
+		return "", false
+	}
+
+	lower := strings.ToLower(key)
+	switch lower {
+	case conditionKeyUsername:
+		return pctx.Account.Access, pctx.Account.Access != ""
+	case conditionKeyUserID:
+		return strconv.Itoa(pctx.Account.UserID), pctx.Account.Access != ""
+	case conditionKeyGroupID:
+		return strconv.Itoa(pctx.Account.GroupID), pctx.Account.Access != ""
+	case conditionKeyProjectID:
+		return strconv.Itoa(pctx.Account.ProjectID), pctx.Account.Access != ""
+	case conditionKeyTenant:
+		return GetTenantID(pctx.Account.Access), pctx.Account.Access != ""
+	}
+
+	// the tag keys are case sensitive
+	var tags map[string]string
+	switch {
+	case strings.HasPrefix(lower, conditionKeyRequestObjectTag):
+		key = key[len(conditionKeyRequestObjectTag):]
+		tags = pctx.RequestTags
+	case strings.HasPrefix(lower, conditionKeyExistingObjectTag):
+		key = key[len(conditionKeyExistingObjectTag):]
+		tags = pctx.loadObjectTags()
+	case strings.HasPrefix(lower, conditionKeyResourceTag):
+		key = key[len(conditionKeyResourceTag):]
+		tags = pctx.loadBucketTags()
+	default:
+		return "", false
+	}
+
+	val, ok := tags[key]
+	return val, ok
+}
+
+func (pctx *PolicyContext) loadObjectTags() map[string]string {
+	if pctx.object == "" || pctx.be == nil {
+		return nil
+	}
+	if !pctx.objectTags.loaded {
+		pctx.objectTags.tags, pctx.objectTags.err = pctx.be.GetObjectTagging(pctx.ctx, pctx.bucket, pctx.object, "")
+		pctx.objectTags.err = ignoreMissingTags(pctx.objectTags.err)
+		pctx.objectTags.loaded = true
+	}
+	return pctx.objectTags.tags
+}
+
+func (pctx *PolicyContext) loadBucketTags() map[string]string {
+	if pctx.be == nil {
+		return nil
+	}
+	if !pctx.bucketTags.loaded {
+		pctx.bucketTags.tags, pctx.bucketTags.err = pctx.be.GetBucketTagging(pctx.ctx, pctx.bucket)
+		pctx.bucketTags.err = ignoreMissingTags(pctx.bucketTags.err)
+		pctx.bucketTags.loaded = true
+	}
+	return pctx.bucketTags.tags
+}
+
+// ignoreMissingTags treats a missing object or tag set, and the
+// backends without tagging support, as no tags
+func ignoreMissingTags(err error) error {
+	for _, code := range []s3err.ErrorCode{
+		s3err.ErrNoSuchKey,
+		s3err.ErrBucketTaggingNotFound,
+		s3err.ErrNotImplemented,
+	} {
+		if errors.Is(err, s3err.GetAPIError(code)) {
+			return nil
+		}
+	}
+	return err
+}
+
+// substitute replaces the policy variables (e.g. '${vgw:ProjectID}')
+// in the condition value with the request values
+func (pctx *PolicyContext) substitute(value string) string {
+	if !hasPolicyVariable(value) {
+		return value
+	}
+
+	var sb strings.Builder
+	for {
+		start := strings.Index(value, "${")
+		if start == -1 {
+			break
+		}
+		end := strings.IndexByte(value[start:], '}')
+		if end == -1 {
+			break
+		}
+		sb.WriteString(value[:start])
+		name := value[start+2 : start+end]
+		if v, ok := pctx.value(name); ok {
+			sb.WriteString(v)
+		} else {
+			// keep the unknown variables unchanged
+			sb.WriteString(value[start : start+end+1])
+		}
+		value = value[start+end+1:]
+	}
+	sb.WriteString(value)
+
+	return sb.String()
+}
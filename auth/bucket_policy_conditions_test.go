@@ -0,0 +1,315 @@
+// Copyright 2026 Versity Software
+// This file is licensed under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/versity/versitygw/backend"
+	"github.com/versity/versitygw/s3err"
+)
+
+// tagBackend serves fixed object and bucket tags and
+// counts the backend calls
+type tagBackend struct {
+	backend.BackendUnsupported
+	objectTags  map[string]map[string]string
+	bucketTags  map[string]string
+	objectCalls int
+	bucketCalls int
+	err         error
+}
+
+func (b *tagBackend) GetObjectTagging(_ context.Context, _, object, _ string) (map[string]string, error) {
+	b.objectCalls++
+	if b.err != nil {
+		return nil, b.err
+	}
+	tags, ok := b.objectTags[object]
+	if !ok {
+		return nil, s3err.GetAPIError(s3err.ErrNoSuchKey)
+	}
+	return tags, nil
+}
+
+func (b *tagBackend) GetBucketTagging(context.Context, string) (map[string]string, error) {
+	b.bucketCalls++
+	if b.bucketTags == nil {
+		return nil, s3err.GetAPIError(s3err.ErrBucketTaggingNotFound)
+	}
+	return b.bucketTags, nil
+}
+
+func TestConditions_UnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    Conditions
+		wantErr bool
+	}{
+		{"string value", `{"StringEquals":{"s3:ExistingObjectTag/project":"p1"}}`,
+			Conditions{ConditionStringEquals: {"s3:ExistingObjectTag/project": {"p1"}}}, false},
+		{"list value", `{"StringLike":{"aws:username":["a*","b"]}}`,
+			Conditions{ConditionStringLike: {"aws:username": {"a*", "b"}}}, false},
+		{"number and bool values", `{"NumericEquals":{"vgw:UserID":[1000, 1.5]},"Null":{"vgw:Tenant":false}}`,
+			Conditions{
+				ConditionNumericEquals: {"vgw:UserID": {"1000", "1.5"}},
+				ConditionNull:          {"vgw:Tenant": {"false"}},
+			}, false},
+		{"empty list", `{"StringEquals":{"aws:username":[]}}`, nil, true},
+		{"empty operator", `{"StringEquals":{}}`, nil, true},
+		{"object value", `{"StringEquals":{"aws:username":{"a":"b"}}}`, nil, true},
+		{"invalid json", `"StringEquals"`, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var c Conditions
+			err := json.Unmarshal([]byte(tt.input), &c)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, c)
+		})
+	}
+}
+
+func TestConditions_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   Conditions
+		wantErr error
+	}{
+		{"valid", Conditions{
+			ConditionStringEquals:                        {"s3:ExistingObjectTag/project": {"${vgw:ProjectID}"}},
+			ConditionOperator("StringNotLikeIfExists"):   {"s3:RequestObjectTag/team": {"a*"}},
+			ConditionNumericLessThan:                     {"vgw:GroupID": {"10"}},
+			ConditionOperator("NumericEqualsIfExists"):   {"aws:ResourceTag/level": {"${vgw:UserID}"}},
+			ConditionNull:                                {"vgw:Tenant": {"true"}},
+			ConditionOperator("StringEqualsIgnoreCase"):  {"AWS:UserName": {"admin"}},
+			ConditionOperator("StringNotEqualsIfExists"): {"vgw:projectid": {"1"}},
+		}, nil},
+		{"unknown operator", Conditions{"DateEquals": {"aws:username": {"a"}}}, policyErrInvalidCondition},
+		{"null if exists", Conditions{"NullIfExists": {"aws:username": {"true"}}}, policyErrInvalidCondition},
+		{"invalid null value", Conditions{ConditionNull: {"aws:username": {"yes"}}}, policyErrInvalidCondition},
+		{"invalid number", Conditions{ConditionNumericEquals: {"vgw:UserID": {"abc"}}}, policyErrInvalidCondition},
+		{"unknown key", Conditions{ConditionStringEquals: {"aws:SourceIp": {"a"}}}, policyErrInvalidConditionKey},
+		{"empty tag key", Conditions{ConditionStringEquals: {"s3:ExistingObjectTag/": {"a"}}}, policyErrInvalidConditionKey},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.wantErr, tt.input.Validate())
+		})
+	}
+}
+
+func TestConditions_evaluate(t *testing.T) {
+	be := &tagBackend{
+		objectTags: map[string]map[string]string{
+			"obj": {"project": "42", "Team": "Storage"},
+		},
+		bucketTags: map[string]string{"env": "prod"},
+	}
+	acct := Account{Access: "user", UserID: 1000, GroupID: 100, ProjectID: 42}
+
+	tests := []struct {
+		name   string
+		cond   Conditions
+		object string
+		acct   Account
+		want   bool
+	}{
+		{"no conditions", nil, "obj", acct, true},
+		{"project tag matches", Conditions{ConditionStringEquals: {"s3:ExistingObjectTag/project": {"${vgw:ProjectID}"}}}, "obj", acct, true},
+		{"project tag mismatch", Conditions{ConditionStringEquals: {"s3:ExistingObjectTag/project": {"${vgw:ProjectID}"}}}, "obj", Account{Access: "other", ProjectID: 7}, false},
+		{"tag keys are case sensitive", Conditions{ConditionStringEquals: {"s3:ExistingObjectTag/team": {"Storage"}}}, "obj", acct, false},
+		{"ignore case values", Conditions{ConditionStringEqualsIgnoreCase: {"s3:existingobjecttag/Team": {"storage"}}}, "obj", acct, true},
+		{"like", Conditions{ConditionStringLike: {"s3:ExistingObjectTag/Team": {"Sto*"}}}, "obj", acct, true},
+		{"values are ORed", Conditions{ConditionStringEquals: {"aws:username": {"a", "user"}}}, "obj", acct, true},
+		{"keys are ANDed", Conditions{ConditionStringEquals: {"aws:username": {"user"}, "aws:ResourceTag/env": {"dev"}}}, "obj", acct, false},
+		{"bucket tag", Conditions{ConditionStringEquals: {"aws:ResourceTag/env": {"prod"}}}, "", acct, true},
+		{"numeric", Conditions{ConditionNumericGreaterThanEquals: {"vgw:UserID": {"1000"}}, ConditionNumericLessThan: {"vgw:GroupID": {"101"}}}, "obj", acct, true},
+		{"numeric not a number", Conditions{ConditionNumericEquals: {"s3:ExistingObjectTag/Team": {"1"}}}, "obj", acct, false},
+		{"missing key", Conditions{ConditionStringEquals: {"s3:ExistingObjectTag/owner": {"user"}}}, "obj", acct, false},
+		{"missing object", Conditions{ConditionStringEquals: {"s3:ExistingObjectTag/project": {"42"}}}, "new", acct, false},
+		{"missing key negated", Conditions{ConditionStringNotEquals: {"s3:ExistingObjectTag/owner": {"user"}}}, "obj", acct, true},
+		{"missing key if exists", Conditions{"StringEqualsIfExists": {"s3:ExistingObjectTag/owner": {"user"}}}, "obj", acct, true},
+		{"present key if exists", Conditions{"StringEqualsIfExists": {"s3:ExistingObjectTag/project": {"1"}}}, "obj", acct, false},
+		{"not equals", Conditions{ConditionStringNotEquals: {"s3:ExistingObjectTag/project": {"1", "42"}}}, "obj", acct, false},
+		{"null", Conditions{ConditionNull: {"s3:ExistingObjectTag/owner": {"true"}, "s3:ExistingObjectTag/project": {"false"}}}, "obj", acct, true},
+		{"anonymous", Conditions{ConditionNull: {"aws:username": {"true"}}}, "obj", Account{}, true},
+		{"tenant", Conditions{ConditionStringEquals: {"vgw:Tenant": {"${aws:username}"}}}, "obj", acct, true},
+		{"unknown variable", Conditions{ConditionStringEquals: {"aws:username": {"${vgw:Unknown}"}}}, "obj", acct, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pctx := NewPolicyContext(context.Background(), be, tt.acct, "bucket", tt.object, nil)
+			assert.Equal(t, tt.want, tt.cond.evaluate(pctx, false))
+		})
+	}
+}
+
+func TestVerifyBucketPolicy_conditions(t *testing.T) {
+	policy := []byte(`{
+		"Statement": [
+			{
+				"Effect": "Allow",
+				"Principal": "*",
+				"Action": ["s3:GetObject", "s3:PutObject", "s3:PutObjectTagging"],
+				"Resource": "arn:aws:s3:::bucket/*",
+				"Condition": {"StringEquals": {"s3:ExistingObjectTag/project": "${vgw:ProjectID}"}}
+			},
+			{
+				"Effect": "Allow",
+				"Principal": "*",
+				"Action": "s3:PutObject",
+				"Resource": "arn:aws:s3:::bucket/*",
+				"Condition": {"StringEquals": {"s3:RequestObjectTag/project": "${vgw:ProjectID}"}}
+			},
+			{
+				"Effect": "Deny",
+				"Principal": "*",
+				"Action": "s3:PutObjectTagging",
+				"Resource": "arn:aws:s3:::bucket/*",
+				"Condition": {"StringNotEquals": {"s3:RequestObjectTag/project": "${vgw:ProjectID}"}}
+			}
+		]
+	}`)
+	assert.NoError(t, ValidatePolicyDocument(policy, "bucket", nil))
+
+	member := Account{Access: "member", ProjectID: 42}
+	outsider := Account{Access: "outsider", ProjectID: 7}
+	denied := s3err.GetAPIError(s3err.ErrAccessDenied)
+
+	verify := func(be backend.Backend, acct Account, object string, action Action, tags map[string]string) error {
+		pctx := NewPolicyContext(context.Background(), be, acct, "bucket", object, tags)
+		return VerifyBucketPolicy(policy, pctx, "bucket", object, action)
+	}
+
+	t.Run("read by project", func(t *testing.T) {
+		be := &tagBackend{objectTags: map[string]map[string]string{"obj": {"project": "42"}}}
+		assert.NoError(t, verify(be, member, "obj", GetObjectAction, nil))
+		assert.Equal(t, denied, verify(be, outsider, "obj", GetObjectAction, nil))
+	})
+	t.Run("create with request tags", func(t *testing.T) {
+		be := &tagBackend{}
+		assert.NoError(t, verify(be, member, "new", PutObjectAction, map[string]string{"project": "42"}))
+		assert.Equal(t, denied, verify(be, member, "new", PutObjectAction, map[string]string{"project": "7"}))
+		assert.Equal(t, denied, verify(be, member, "new", PutObjectAction, nil))
+	})
+	t.Run("retagging is denied", func(t *testing.T) {
+		be := &tagBackend{objectTags: map[string]map[string]string{"obj": {"project": "42"}}}
+		assert.NoError(t, verify(be, member, "obj", PutObjectTaggingAction, map[string]string{"project": "42"}))
+		assert.Equal(t, denied, verify(be, member, "obj", PutObjectTaggingAction, map[string]string{"project": "7"}))
+	})
+	t.Run("tags are loaded once", func(t *testing.T) {
+		be := &tagBackend{objectTags: map[string]map[string]string{"obj": {"project": "42"}}}
+		pctx := NewPolicyContext(context.Background(), be, member, "bucket", "obj", map[string]string{"project": "42"})
+		assert.NoError(t, VerifyBucketPolicy(policy, pctx, "bucket", "obj", PutObjectTaggingAction))
+		assert.NoError(t, VerifyBucketPolicy(policy, pctx, "bucket", "obj", GetObjectAction))
+		assert.Equal(t, 1, be.objectCalls)
+		assert.Equal(t, 0, be.bucketCalls)
+	})
+	t.Run("tag lookup failure", func(t *testing.T) {
+		lookupErr := errors.New("io error")
+		be := &tagBackend{err: lookupErr}
+		assert.Equal(t, lookupErr, verify(be, member, "obj", GetObjectAction, nil))
+	})
+}
+
+func TestConditions_evaluateUnsupported(t *testing.T) {
+	pctx := NewPolicyContext(context.Background(), &tagBackend{}, Account{Access: "user"}, "bucket", "obj", nil)
+
+	tests := []struct {
+		name string
+		cond Conditions
+	}{
+		{"unsupported operator", Conditions{"Bool": {"aws:SecureTransport": {"false"}}}},
+		{"unsupported key", Conditions{ConditionStringEquals: {"aws:SourceIp": {"10.0.0.1"}}}},
+		{"null if exists", Conditions{"NullIfExists": {"aws:username": {"true"}}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.True(t, tt.cond.evaluate(pctx, true), "deny")
+			assert.False(t, tt.cond.evaluate(pctx, false), "allow")
+		})
+	}
+
+	// the supported conditions are still evaluated in the Deny statements
+	cond := Conditions{
+		"Bool":                {"aws:SecureTransport": {"false"}},
+		ConditionStringEquals: {"aws:username": {"other"}},
+	}
+	assert.False(t, cond.evaluate(pctx, true))
+}
+
+func TestVerifyBucketPolicy_failClosed(t *testing.T) {
+	denied := s3err.GetAPIError(s3err.ErrAccessDenied)
+	acct := Account{Access: "user"}
+
+	t.Run("deny with unsupported condition", func(t *testing.T) {
+		// stored before the conditions were supported
+		policy := []byte(`{
+			"Statement": [
+				{
+					"Effect": "Allow",
+					"Principal": "*",
+					"Action": "s3:GetObject",
+					"Resource": "arn:aws:s3:::bucket/*"
+				},
+				{
+					"Effect": "Deny",
+					"Principal": "*",
+					"Action": "s3:GetObject",
+					"Resource": "arn:aws:s3:::bucket/*",
+					"Condition": {"Bool": {"aws:SecureTransport": "false"}}
+				}
+			]
+		}`)
+		pctx := NewPolicyContext(context.Background(), &tagBackend{}, acct, "bucket", "obj", nil)
+		assert.Equal(t, denied, VerifyBucketPolicy(policy, pctx, "bucket", "obj", GetObjectAction))
+		pctx = NewPolicyContext(context.Background(), &tagBackend{}, Account{}, "bucket", "obj", nil)
+		assert.Equal(t, ErrAccessDenied, VerifyPublicBucketPolicy(policy, pctx, "bucket", "obj", GetObjectAction))
+	})
+	t.Run("deny with failed tag lookup", func(t *testing.T) {
+		policy := []byte(`{
+			"Statement": [
+				{
+					"Effect": "Allow",
+					"Principal": "*",
+					"Action": "s3:GetObject",
+					"Resource": "arn:aws:s3:::bucket/*"
+				},
+				{
+					"Effect": "Deny",
+					"Principal": "*",
+					"Action": "s3:GetObject",
+					"Resource": "arn:aws:s3:::bucket/*",
+					"Condition": {"StringEquals": {"s3:ExistingObjectTag/secret": "true"}}
+				}
+			]
+		}`)
+		lookupErr := errors.New("io error")
+		pctx := NewPolicyContext(context.Background(), &tagBackend{err: lookupErr}, acct, "bucket", "obj", nil)
+		assert.Equal(t, lookupErr, VerifyBucketPolicy(policy, pctx, "bucket", "obj", GetObjectAction))
+		pctx = NewPolicyContext(context.Background(), &tagBackend{err: lookupErr}, Account{}, "bucket", "obj", nil)
+		assert.Equal(t, lookupErr, VerifyPublicBucketPolicy(policy, pctx, "bucket", "obj", GetObjectAction))
+	})
+}
@@ -0,0 +1,154 @@
+// Copyright 2026 Versity Software
+// This file is licensed under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package auth
+
+import (
+	"bufio"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync/atomic"
+)
+
+// ErrNoCertMapping is returned when a client certificate is not mapped
+// to any account
+var ErrNoCertMapping = errors.New("no account mapped to the client certificate")
+
+// CertAccountMapper maps the verified client certificates of the mutual
+// TLS connections to the access keys of the gateway accounts
+type CertAccountMapper interface {
+	MapCertificate(cert *x509.Certificate) (string, error)
+}
+
+// CertIdentities returns the identities of the certificate in the order
+// they are matched against the mapping:
+//
+//	subject:<distinguished name>
+//	cn:<common name>
+//	dns:<DNS SAN>
+//	email:<email SAN>
+//	uri:<URI SAN>
+//	ip:<IP SAN>
+func CertIdentities(cert *x509.Certificate) []string {
+	ids := []string{"subject:" + cert.Subject.String()}
+	if cert.Subject.CommonName != "" {
+		ids = append(ids, "cn:"+cert.Subject.CommonName)
+	}
+	for _, name := range cert.DNSNames {
+		ids = append(ids, "dns:"+name)
+	}
+	for _, email := range cert.EmailAddresses {
+		ids = append(ids, "email:"+email)
+	}
+	for _, uri := range cert.URIs {
+		ids = append(ids, "uri:"+uri.String())
+	}
+	for _, ip := range cert.IPAddresses {
+		ids = append(ids, "ip:"+ip.String())
+	}
+	return ids
+}
+
+// CertMappingFile maps the client certificates with a mapping file of
+// "<identity> <access>" lines, where the identity is one of the
+// CertIdentities of the certificate, e.g.:
+//
+//	# HPC compute nodes
+//	dns:node01.cluster.example.com  hpcuser
+//	cn:backup-service               backup
+//	subject:CN=ingest,O=Example     ingest
+//
+// The subject identities may contain spaces, the access is the last field
+// of the line. Empty lines and lines starting with '#' are ignored.
+type CertMappingFile struct {
+	path    string
+	mapping atomic.Pointer[map[string]string]
+}
+
+var _ CertAccountMapper = &CertMappingFile{}
+
+// NewCertMappingFile loads the client certificate mapping file
+func NewCertMappingFile(path string) (*CertMappingFile, error) {
+	m := &CertMappingFile{path: path}
+	if err := m.Reload(); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// Reload re-reads the mapping file, the current mapping is kept when
+// the file is invalid
+func (m *CertMappingFile) Reload() error {
+	f, err := os.Open(m.path)
+	if err != nil {
+		return fmt.Errorf("open client cert mapping: %w", err)
+	}
+	defer f.Close()
+
+	mapping := map[string]string{}
+	scanner := bufio.NewScanner(f)
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		idx := strings.LastIndexAny(text, " \t")
+		if idx == -1 {
+			return fmt.Errorf("client cert mapping %v:%v: expected \"<identity> <access>\"", m.path, line)
+		}
+		id := strings.TrimSpace(text[:idx])
+		access := text[idx+1:]
+		if !strings.Contains(id, ":") {
+			return fmt.Errorf("client cert mapping %v:%v: invalid identity %q", m.path, line, id)
+		}
+		mapping[id] = access
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("read client cert mapping: %w", err)
+	}
+
+	m.mapping.Store(&mapping)
+	return nil
+}
+
+// MapCertificate returns the access of the first mapped identity of
+// the certificate
+func (m *CertMappingFile) MapCertificate(cert *x509.Certificate) (string, error) {
+	mapping := *m.mapping.Load()
+	for _, id := range CertIdentities(cert) {
+		if access, ok := mapping[id]; ok {
+			return access, nil
+		}
+	}
+	return "", ErrNoCertMapping
+}
+
+// CertCommonNameMapper maps the client certificates to the IAM account
+// with the access of the certificate common name
+type CertCommonNameMapper struct{}
+
+var _ CertAccountMapper = CertCommonNameMapper{}
+
+func (CertCommonNameMapper) MapCertificate(cert *x509.Certificate) (string, error) {
+	if cert.Subject.CommonName == "" {
+		return "", ErrNoCertMapping
+	}
+	return cert.Subject.CommonName, nil
+}
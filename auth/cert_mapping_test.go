@@ -0,0 +1,127 @@
+// Copyright 2026 Versity Software
+// This file is licensed under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package auth
+
+import (
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"errors"
+	"net"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func testCert() *x509.Certificate {
+	uri, _ := url.Parse("spiffe://example.com/ingest")
+	return &x509.Certificate{
+		Subject: pkix.Name{
+			CommonName:   "node01",
+			Organization: []string{"Example"},
+		},
+		DNSNames:       []string{"node01.cluster.example.com"},
+		EmailAddresses: []string{"ops@example.com"},
+		URIs:           []*url.URL{uri},
+		IPAddresses:    []net.IP{net.ParseIP("10.0.0.1")},
+	}
+}
+
+func TestCertIdentities(t *testing.T) {
+	expected := []string{
+		"subject:CN=node01,O=Example",
+		"cn:node01",
+		"dns:node01.cluster.example.com",
+		"email:ops@example.com",
+		"uri:spiffe://example.com/ingest",
+		"ip:10.0.0.1",
+	}
+	got := CertIdentities(testCert())
+	if !slices.Equal(got, expected) {
+		t.Errorf("expected identities %v, got %v", expected, got)
+	}
+}
+
+func TestCertMappingFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "certmap")
+	write := func(data string) {
+		t.Helper()
+		if err := os.WriteFile(path, []byte(data), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	write(`# compute nodes
+dns:node01.cluster.example.com	hpcuser
+
+subject:CN=node01,O=Example   subjectuser
+cn:other other
+`)
+	m, err := NewCertMappingFile(path)
+	if err != nil {
+		t.Fatalf("new mapping: %v", err)
+	}
+
+	// the subject is matched first
+	access, err := m.MapCertificate(testCert())
+	if err != nil || access != "subjectuser" {
+		t.Errorf("expected subjectuser, got %q, %v", access, err)
+	}
+
+	write("dns:node01.cluster.example.com hpcuser\n")
+	if err := m.Reload(); err != nil {
+		t.Fatalf("reload: %v", err)
+	}
+	access, err = m.MapCertificate(testCert())
+	if err != nil || access != "hpcuser" {
+		t.Errorf("expected hpcuser, got %q, %v", access, err)
+	}
+
+	// the invalid files keep the current mapping
+	write("hpcuser\n")
+	if err := m.Reload(); err == nil {
+		t.Errorf("expected error for a line without identity")
+	}
+	write("node01 hpcuser\n")
+	if err := m.Reload(); err == nil {
+		t.Errorf("expected error for an identity without type")
+	}
+	access, err = m.MapCertificate(testCert())
+	if err != nil || access != "hpcuser" {
+		t.Errorf("expected hpcuser, got %q, %v", access, err)
+	}
+
+	write("cn:other other\n")
+	if err := m.Reload(); err != nil {
+		t.Fatalf("reload: %v", err)
+	}
+	_, err = m.MapCertificate(testCert())
+	if !errors.Is(err, ErrNoCertMapping) {
+		t.Errorf("expected ErrNoCertMapping, got %v", err)
+	}
+}
+
+func TestCertCommonNameMapper(t *testing.T) {
+	access, err := CertCommonNameMapper{}.MapCertificate(testCert())
+	if err != nil || access != "node01" {
+		t.Errorf("expected node01, got %q, %v", access, err)
+	}
+
+	_, err = CertCommonNameMapper{}.MapCertificate(&x509.Certificate{})
+	if !errors.Is(err, ErrNoCertMapping) {
+		t.Errorf("expected ErrNoCertMapping, got %v", err)
+	}
+}
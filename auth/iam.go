@@ -17,6 +17,7 @@ package auth
 import (
 	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/versity/versitygw/s3err"
@@ -51,6 +52,7 @@ type Account struct {
 	UserID    int    `json:"userID"`
 	GroupID   int    `json:"groupID"`
 	ProjectID int    `json:"projectID"`
+	Email     string `json:"email,omitempty"`
 }
 
 type ListUserAccountsResult struct {
@@ -64,6 +66,7 @@ type MutableProps struct {
 	UserID    *int    `json:"userID"`
 	GroupID   *int    `json:"groupID"`
 	ProjectID *int    `json:"projectID"`
+	Email     *string `json:"email"`
 }
 
 func (m MutableProps) Validate() error {
@@ -90,6 +93,9 @@ func updateAcc(acc *Account, props MutableProps) {
 	if props.Role != "" {
 		acc.Role = props.Role
 	}
+	if props.Email != nil {
+		acc.Email = *props.Email
+	}
 }
 
 // IAMService is the interface for all IAM service implementations
@@ -111,6 +117,23 @@ var (
 	ErrNoSuchUser = errors.New("user not found")
 )
 
+// GetUserAccountByEmail looks up the account with the email address,
+// the email addresses are matched case insensitively
+func GetUserAccountByEmail(iam IAMService, email string) (Account, error) {
+	accs, err := iam.ListUserAccounts()
+	if err != nil {
+		return Account{}, err
+	}
+
+	for _, acc := range accs {
+		if acc.Email != "" && strings.EqualFold(acc.Email, email) {
+			return acc, nil
+		}
+	}
+
+	return Account{}, ErrNoSuchUser
+}
+
 type Opts struct {
 	RootAccount                 Account
 	Dir                         string
@@ -81,6 +81,22 @@ func (i *icache) update(k string, props MutableProps) {
 	}
 }
 
+// setExpire changes the expiration duration, the existing entries
+// are valid no longer than the new duration from now
+func (i *icache) setExpire(expire time.Duration) {
+	i.Lock()
+	defer i.Unlock()
+
+	i.expire = expire
+	maxExp := time.Now().Add(expire)
+	for k, v := range i.items {
+		if v.exp.After(maxExp) {
+			v.exp = maxExp
+			i.items[k] = v
+		}
+	}
+}
+
 func (i *icache) Delete(k string) {
 	i.Lock()
 	delete(i.items, k)
@@ -132,6 +148,11 @@ func NewCache(service IAMService, expireTime, cleanupInterval time.Duration) *IA
 	return i
 }
 
+// SetExpiration changes the duration a cache entry can be valid
+func (c *IAMCache) SetExpiration(expireTime time.Duration) {
+	c.iamcache.setExpire(expireTime)
+}
+
 // CreateAccount send create to IAM service and creates an account cache entry
 func (c *IAMCache) CreateAccount(account Account) error {
 	err := c.service.CreateAccount(account)
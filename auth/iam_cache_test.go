@@ -0,0 +1,46 @@
+// Copyright 2026 Versity Software
+// This file is licensed under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package auth
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIcache_SetExpire(t *testing.T) {
+	c := &icache{
+		items:  make(map[string]item),
+		expire: time.Hour,
+	}
+
+	c.set("user", Account{Access: "user"})
+	_, ok := c.get("user")
+	assert.True(t, ok)
+
+	// the existing entry is clamped to the shorter duration
+	c.setExpire(time.Millisecond)
+	time.Sleep(5 * time.Millisecond)
+	_, ok = c.get("user")
+	assert.False(t, ok)
+
+	// the new entries use the new duration
+	c.setExpire(time.Hour)
+	c.set("user", Account{Access: "user"})
+	acc, ok := c.get("user")
+	assert.True(t, ok)
+	assert.Equal(t, "user", acc.Access)
+}
@@ -200,6 +200,7 @@ func (s *IAMServiceInternal) ListUserAccounts() ([]Account, error) {
 			UserID:    conf.AccessAccounts[k].UserID,
 			GroupID:   conf.AccessAccounts[k].GroupID,
 			ProjectID: conf.AccessAccounts[k].ProjectID,
+			Email:     conf.AccessAccounts[k].Email,
 		})
 	}
 
@@ -133,6 +133,7 @@ func (ipa *IpaIAMService) GetUserAccount(access string) (Account, error) {
 		Gidnumber []string
 		Uidnumber []string
 		PidNumber []string
+		Mail      []string
 	}{}
 
 	err = ipa.rpc(req, &userResult)
@@ -160,6 +161,9 @@ func (ipa *IpaIAMService) GetUserAccount(access string) (Account, error) {
 		GroupID:   gid,
 		ProjectID: pId,
 	}
+	if len(userResult.Mail) > 0 {
+		account.Email = userResult.Mail[0]
+	}
 
 	session_key := make([]byte, 16)
 
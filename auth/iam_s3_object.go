@@ -211,6 +211,7 @@ func (s *IAMServiceS3) ListUserAccounts() ([]Account, error) {
 			UserID:    conf.AccessAccounts[k].UserID,
 			GroupID:   conf.AccessAccounts[k].GroupID,
 			ProjectID: conf.AccessAccounts[k].ProjectID,
+			Email:     conf.AccessAccounts[k].Email,
 		})
 	}
 
@@ -378,6 +378,9 @@ func parseVaultUserAccount(data map[string]any, access string) (acc Account, err
 		return acc, errInvalidUser
 	}
 
+	// the email is optional, for the accounts created without it
+	email, _ := usrAcc["email"].(string)
+
 	return Account{
 		Access:    acss,
 		Secret:    secret,
@@ -385,5 +388,6 @@ func parseVaultUserAccount(data map[string]any, access string) (acc Account, err
 		UserID:    int(userId),
 		GroupID:   int(groupId),
 		ProjectID: int(projectID),
+		Email:     email,
 	}, nil
 }
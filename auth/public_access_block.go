@@ -0,0 +1,204 @@
+// Copyright 2026 Versity Software
+// This file is licensed under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package auth
+
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"strings"
+	"sync/atomic"
+
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/versity/versitygw/backend"
+	"github.com/versity/versitygw/debuglogger"
+	"github.com/versity/versitygw/s3err"
+)
+
+// PublicAccessBlockConfiguration is the Block Public Access settings of
+// a bucket:
+//   - BlockPublicAcls rejects the bucket ACLs granting public access
+//   - IgnorePublicAcls ignores the public grants of the bucket ACLs
+//   - BlockPublicPolicy rejects the bucket policies granting public access
+//   - RestrictPublicBuckets denies the access granted by the public
+//     bucket policies to the anonymous requests and to the accounts
+//     other than the bucket owner
+type PublicAccessBlockConfiguration struct {
+	BlockPublicAcls       bool
+	IgnorePublicAcls      bool
+	BlockPublicPolicy     bool
+	RestrictPublicBuckets bool
+}
+
+var defaultPublicAccessBlock atomic.Pointer[PublicAccessBlockConfiguration]
+
+// SetDefaultPublicAccessBlock sets the gateway wide Block Public Access
+// settings, which apply to all the buckets in addition to the settings
+// of each bucket
+func SetDefaultPublicAccessBlock(cfg PublicAccessBlockConfiguration) {
+	defaultPublicAccessBlock.Store(&cfg)
+}
+
+// DefaultPublicAccessBlock returns the gateway wide Block Public Access
+// settings
+func DefaultPublicAccessBlock() PublicAccessBlockConfiguration {
+	cfg := defaultPublicAccessBlock.Load()
+	if cfg == nil {
+		return PublicAccessBlockConfiguration{}
+	}
+	return *cfg
+}
+
+// ParsePublicAccessBlockSettings parses a comma separated list of the
+// enabled settings, e.g. "BlockPublicAcls,BlockPublicPolicy", or "all"
+// to enable all of them
+func ParsePublicAccessBlockSettings(settings string) (PublicAccessBlockConfiguration, error) {
+	var cfg PublicAccessBlockConfiguration
+	for setting := range strings.SplitSeq(settings, ",") {
+		switch strings.ToLower(strings.TrimSpace(setting)) {
+		case "":
+		case "all":
+			cfg = PublicAccessBlockConfiguration{
+				BlockPublicAcls:       true,
+				IgnorePublicAcls:      true,
+				BlockPublicPolicy:     true,
+				RestrictPublicBuckets: true,
+			}
+		case "blockpublicacls":
+			cfg.BlockPublicAcls = true
+		case "ignorepublicacls":
+			cfg.IgnorePublicAcls = true
+		case "blockpublicpolicy":
+			cfg.BlockPublicPolicy = true
+		case "restrictpublicbuckets":
+			cfg.RestrictPublicBuckets = true
+		default:
+			return cfg, fmt.Errorf("invalid public access block setting %q", setting)
+		}
+	}
+	return cfg, nil
+}
+
+// ParsePublicAccessBlock parses the stored bucket settings
+func ParsePublicAccessBlock(data []byte) (PublicAccessBlockConfiguration, error) {
+	var cfg PublicAccessBlockConfiguration
+	if err := xml.Unmarshal(data, &cfg); err != nil {
+		return cfg, fmt.Errorf("parse public access block: %w", err)
+	}
+	return cfg, nil
+}
+
+// merge returns the most restrictive combination of the settings
+func (cfg PublicAccessBlockConfiguration) merge(other PublicAccessBlockConfiguration) PublicAccessBlockConfiguration {
+	return PublicAccessBlockConfiguration{
+		BlockPublicAcls:       cfg.BlockPublicAcls || other.BlockPublicAcls,
+		IgnorePublicAcls:      cfg.IgnorePublicAcls || other.IgnorePublicAcls,
+		BlockPublicPolicy:     cfg.BlockPublicPolicy || other.BlockPublicPolicy,
+		RestrictPublicBuckets: cfg.RestrictPublicBuckets || other.RestrictPublicBuckets,
+	}
+}
+
+// GetPublicAccessBlock returns the effective Block Public Access settings
+// of the bucket: the bucket settings combined with the gateway defaults.
+// The backends not supporting the bucket settings only get the defaults.
+func GetPublicAccessBlock(ctx context.Context, be backend.Backend, bucket string) (PublicAccessBlockConfiguration, error) {
+	cfg := DefaultPublicAccessBlock()
+
+	data, err := be.GetPublicAccessBlock(ctx, bucket)
+	if errors.Is(err, s3err.GetAPIError(s3err.ErrNoSuchPublicAccessBlockConfiguration)) ||
+		errors.Is(err, s3err.GetAPIError(s3err.ErrNotImplemented)) {
+		return cfg, nil
+	}
+	if err != nil {
+		return cfg, err
+	}
+
+	bucketCfg, err := ParsePublicAccessBlock(data)
+	if err != nil {
+		return cfg, err
+	}
+
+	return cfg.merge(bucketCfg), nil
+}
+
+// VerifyACL denies the ACLs granting public access, if the public ACLs
+// are blocked
+func (cfg PublicAccessBlockConfiguration) VerifyACL(acl []byte) error {
+	if !cfg.BlockPublicAcls {
+		return nil
+	}
+
+	parsed, err := ParseACL(acl)
+	if err != nil {
+		return err
+	}
+	if parsed.hasPublicGrants() {
+		debuglogger.Logf("public ACLs are blocked by the public access block settings")
+		return s3err.GetAPIError(s3err.ErrAccessDenied)
+	}
+
+	return nil
+}
+
+// VerifyPolicy denies the bucket policies granting public access, if the
+// public policies are blocked
+func (cfg PublicAccessBlockConfiguration) VerifyPolicy(policy []byte) error {
+	if !cfg.BlockPublicPolicy {
+		return nil
+	}
+
+	parsed, err := ParsePolicyDocument(policy)
+	if err != nil {
+		return err
+	}
+	if parsed.IsPublic() {
+		debuglogger.Logf("public bucket policies are blocked by the public access block settings")
+		return s3err.GetAPIError(s3err.ErrAccessDenied)
+	}
+
+	return nil
+}
+
+// isPublic checks if the grantee is public: like AWS, the grants to all
+// the authenticated users are public as well
+func (g Grantee) isPublic() bool {
+	return g.Type == types.TypeGroup &&
+		(g.Access == "all-users" || g.Access == "authenticated-users")
+}
+
+// hasPublicGrants checks if any of the acl grants is public
+func (acl *ACL) hasPublicGrants() bool {
+	for _, grt := range acl.Grantees {
+		if grt.isPublic() {
+			return true
+		}
+	}
+
+	return false
+}
+
+// withoutPublicGrants returns the acl without its public grants
+func (acl ACL) withoutPublicGrants() ACL {
+	grantees := make([]Grantee, 0, len(acl.Grantees))
+	for _, grt := range acl.Grantees {
+		if grt.isPublic() {
+			continue
+		}
+		grantees = append(grantees, grt)
+	}
+	acl.Grantees = grantees
+	return acl
+}
@@ -0,0 +1,196 @@
+// Copyright 2026 Versity Software
+// This file is licensed under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/versity/versitygw/backend"
+	"github.com/versity/versitygw/s3err"
+)
+
+// policyBackend serves a fixed bucket policy and public access block
+type policyBackend struct {
+	backend.BackendUnsupported
+	policy []byte
+	pab    *PublicAccessBlockConfiguration
+}
+
+func (b *policyBackend) GetBucketPolicy(context.Context, string) ([]byte, error) {
+	if b.policy == nil {
+		return nil, s3err.GetAPIError(s3err.ErrNoSuchBucketPolicy)
+	}
+	return b.policy, nil
+}
+
+func (b *policyBackend) GetPublicAccessBlock(context.Context, string) ([]byte, error) {
+	if b.pab == nil {
+		return nil, s3err.GetAPIError(s3err.ErrNoSuchPublicAccessBlockConfiguration)
+	}
+	return xml.Marshal(b.pab)
+}
+
+func TestParsePublicAccessBlockSettings(t *testing.T) {
+	tests := []struct {
+		name     string
+		settings string
+		want     PublicAccessBlockConfiguration
+		wantErr  bool
+	}{
+		{"empty", "", PublicAccessBlockConfiguration{}, false},
+		{"all", "all", PublicAccessBlockConfiguration{
+			BlockPublicAcls:       true,
+			IgnorePublicAcls:      true,
+			BlockPublicPolicy:     true,
+			RestrictPublicBuckets: true,
+		}, false},
+		{"list", "BlockPublicAcls, restrictpublicbuckets", PublicAccessBlockConfiguration{
+			BlockPublicAcls:       true,
+			RestrictPublicBuckets: true,
+		}, false},
+		{"invalid", "BlockPublicAcls,BlockEverything", PublicAccessBlockConfiguration{}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParsePublicAccessBlockSettings(tt.settings)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestPublicAccessBlockConfiguration_VerifyACL(t *testing.T) {
+	private, err := json.Marshal(ACL{
+		Owner: "owner",
+		Grantees: []Grantee{
+			{Permission: PermissionRead, Access: "user", Type: types.TypeCanonicalUser},
+		},
+	})
+	assert.NoError(t, err)
+	public, err := json.Marshal(ACL{
+		Owner: "owner",
+		Grantees: []Grantee{
+			{Permission: PermissionRead, Access: "all-users", Type: types.TypeGroup},
+		},
+	})
+	assert.NoError(t, err)
+
+	blocked := PublicAccessBlockConfiguration{BlockPublicAcls: true}
+
+	assert.NoError(t, PublicAccessBlockConfiguration{}.VerifyACL(public))
+	assert.NoError(t, blocked.VerifyACL(private))
+	assert.Equal(t, s3err.GetAPIError(s3err.ErrAccessDenied), blocked.VerifyACL(public))
+}
+
+func TestPublicAccessBlockConfiguration_VerifyPolicy(t *testing.T) {
+	public := []byte(`{
+		"Statement": [
+			{
+				"Effect": "Allow",
+				"Principal": "*",
+				"Action": "s3:GetObject",
+				"Resource": "arn:aws:s3:::bucket/*"
+			}
+		]
+	}`)
+	private := []byte(`{
+		"Statement": [
+			{
+				"Effect": "Allow",
+				"Principal": {"AWS": "user"},
+				"Action": "s3:GetObject",
+				"Resource": "arn:aws:s3:::bucket/*"
+			}
+		]
+	}`)
+
+	blocked := PublicAccessBlockConfiguration{BlockPublicPolicy: true}
+
+	assert.NoError(t, PublicAccessBlockConfiguration{}.VerifyPolicy(public))
+	assert.NoError(t, blocked.VerifyPolicy(private))
+	assert.Equal(t, s3err.GetAPIError(s3err.ErrAccessDenied), blocked.VerifyPolicy(public))
+}
+
+func TestACL_withoutPublicGrants(t *testing.T) {
+	acl := ACL{
+		Owner: "owner",
+		Grantees: []Grantee{
+			{Permission: PermissionRead, Access: "all-users", Type: types.TypeGroup},
+			{Permission: PermissionWrite, Access: "user", Type: types.TypeCanonicalUser},
+		},
+	}
+	assert.True(t, acl.hasPublicGrants())
+
+	stripped := acl.withoutPublicGrants()
+	assert.False(t, stripped.hasPublicGrants())
+	assert.Equal(t, []Grantee{
+		{Permission: PermissionWrite, Access: "user", Type: types.TypeCanonicalUser},
+	}, stripped.Grantees)
+	// the original acl is left unchanged
+	assert.Len(t, acl.Grantees, 2)
+}
+
+func TestVerifyAccess_RestrictPublicBuckets(t *testing.T) {
+	public := []byte(`{
+		"Statement": [
+			{
+				"Effect": "Allow",
+				"Principal": "*",
+				"Action": "s3:GetObject",
+				"Resource": "arn:aws:s3:::bucket/*"
+			}
+		]
+	}`)
+	private := []byte(`{
+		"Statement": [
+			{
+				"Effect": "Allow",
+				"Principal": {"AWS": ["owner", "user"]},
+				"Action": "s3:GetObject",
+				"Resource": "arn:aws:s3:::bucket/*"
+			}
+		]
+	}`)
+	denied := s3err.GetAPIError(s3err.ErrAccessDenied)
+	restricted := &PublicAccessBlockConfiguration{RestrictPublicBuckets: true}
+
+	verify := func(be backend.Backend, access string) error {
+		return VerifyAccess(context.Background(), be, AccessOptions{
+			Acl:           ACL{Owner: "owner"},
+			AclPermission: PermissionRead,
+			Acc:           Account{Access: access, Role: RoleUser},
+			Bucket:        "bucket",
+			Object:        "obj",
+			Action:        GetObjectAction,
+		})
+	}
+
+	// the public policy grants access to the other accounts unless restricted
+	assert.NoError(t, verify(&policyBackend{policy: public}, "user"))
+	assert.Equal(t, denied, verify(&policyBackend{policy: public, pab: restricted}, "user"))
+	// the bucket owner keeps the access
+	assert.NoError(t, verify(&policyBackend{policy: public, pab: restricted}, "owner"))
+	// the policies granting access to the accounts aren't public
+	assert.NoError(t, verify(&policyBackend{policy: private, pab: restricted}, "user"))
+}
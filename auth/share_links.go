@@ -0,0 +1,281 @@
+// Copyright 2026 Versity Software
+// This file is licensed under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package auth
+
+import (
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/versity/versitygw/s3err"
+)
+
+var (
+	// ErrNoSuchShareLink is returned when the share link does not exist
+	ErrNoSuchShareLink = errors.New("share link not found")
+)
+
+type SharePermission string
+
+const (
+	SharePermissionRead   SharePermission = "read"
+	SharePermissionUpload SharePermission = "upload"
+)
+
+func (p SharePermission) isValid() bool {
+	return p == SharePermissionRead || p == SharePermissionUpload
+}
+
+// ShareLink is an admin issued link granting its holders access
+// to a bucket, prefix or object without any credentials. Unlike
+// the presigned URLs, the share links may have any expiry and can be
+// revoked at any time.
+type ShareLink struct {
+	XMLName xml.Name `xml:"ShareLink" json:"-"`
+	// ID is the public identifier of the link used to revoke it
+	ID string `xml:"ID,omitempty" json:"id"`
+	// Token is the link secret token, only returned on creation
+	Token      string `xml:"Token,omitempty" json:"-"`
+	SecretHash string `xml:"-" json:"secretHash"`
+	Bucket     string `xml:"Bucket" json:"bucket"`
+	// Key is the shared object key, or the shared prefix if it
+	// is empty or ends with '/'
+	Key         string            `xml:"Key,omitempty" json:"key,omitempty"`
+	Permissions []SharePermission `xml:"Permission" json:"permissions"`
+	Creator     string            `xml:"Creator,omitempty" json:"creator"`
+	Created     time.Time         `xml:"Created,omitempty" json:"created"`
+	Expires     time.Time         `xml:"Expires" json:"expires"`
+	// Password is the optional link password, only accepted
+	// on creation and stored hashed
+	Password          string `xml:"Password,omitempty" json:"-"`
+	PasswordHash      string `xml:"-" json:"passwordHash,omitempty"`
+	PasswordProtected bool   `xml:"PasswordProtected" json:"-"`
+	// DownloadLimit is the maximum number of object downloads,
+	// 0 means unlimited
+	DownloadLimit int `xml:"DownloadLimit,omitempty" json:"downloadLimit,omitempty"`
+	Downloads     int `xml:"Downloads" json:"downloads"`
+}
+
+type ListShareLinksResult struct {
+	XMLName    xml.Name    `xml:"ListShareLinksResult"`
+	ShareLinks []ShareLink `xml:"ShareLink"`
+}
+
+// ShareStore stores the share links
+type ShareStore interface {
+	CreateShareLink(ShareLink) error
+	GetShareLink(id string) (ShareLink, error)
+	ListShareLinks() ([]ShareLink, error)
+	DeleteShareLink(id string) error
+	// RecordDownload counts an object download through the link.
+	// Returns ErrShareLinkExpired if the download limit is reached.
+	RecordDownload(id string) error
+}
+
+const (
+	shareIDLen     = 8
+	shareSecretLen = 32
+	// the token is '<id>.<secret>'
+	shareTokenSep = "."
+
+	sharePasswordIter    = 100000
+	sharePasswordKeyLen  = 32
+	sharePasswordSaltLen = 16
+	sharePasswordScheme  = "pbkdf2-sha256"
+)
+
+// NewShareLink validates the share link creation input and generates
+// the link ID and secret token. The password is replaced with its hash.
+func NewShareLink(input ShareLink, creator string, now time.Time) (ShareLink, error) {
+	if input.Bucket == "" || len(input.Permissions) == 0 ||
+		!input.Expires.After(now) || input.DownloadLimit < 0 {
+		return ShareLink{}, s3err.GetAPIError(s3err.ErrAdminInvalidShareLink)
+	}
+
+	perms := make([]SharePermission, 0, len(input.Permissions))
+	for _, p := range input.Permissions {
+		p = SharePermission(strings.ToLower(string(p)))
+		if !p.isValid() {
+			return ShareLink{}, s3err.GetAPIError(s3err.ErrAdminInvalidShareLink)
+		}
+		if !containsPermission(perms, p) {
+			perms = append(perms, p)
+		}
+	}
+
+	id := make([]byte, shareIDLen)
+	secret := make([]byte, shareSecretLen)
+	if _, err := rand.Read(id); err != nil {
+		return ShareLink{}, fmt.Errorf("generate share link id: %w", err)
+	}
+	if _, err := rand.Read(secret); err != nil {
+		return ShareLink{}, fmt.Errorf("generate share link secret: %w", err)
+	}
+
+	link := ShareLink{
+		ID:            hex.EncodeToString(id),
+		Bucket:        input.Bucket,
+		Key:           input.Key,
+		Permissions:   perms,
+		Creator:       creator,
+		Created:       now.UTC(),
+		Expires:       input.Expires.UTC(),
+		DownloadLimit: input.DownloadLimit,
+	}
+	secretStr := base64.RawURLEncoding.EncodeToString(secret)
+	link.Token = link.ID + shareTokenSep + secretStr
+	link.SecretHash = hashShareSecret(secretStr)
+
+	if input.Password != "" {
+		hash, err := hashSharePassword(input.Password)
+		if err != nil {
+			return ShareLink{}, err
+		}
+		link.PasswordHash = hash
+	}
+	link.PasswordProtected = link.PasswordHash != ""
+
+	return link, nil
+}
+
+func containsPermission(perms []SharePermission, p SharePermission) bool {
+	for _, perm := range perms {
+		if perm == p {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseShareToken splits the share link token into the link ID and secret
+func ParseShareToken(token string) (string, string, bool) {
+	id, secret, found := strings.Cut(token, shareTokenSep)
+	if !found || id == "" || secret == "" {
+		return "", "", false
+	}
+	return id, secret, true
+}
+
+func hashShareSecret(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
+
+// VerifySecret checks the token secret against the stored hash
+func (l ShareLink) VerifySecret(secret string) bool {
+	return subtle.ConstantTimeCompare([]byte(hashShareSecret(secret)), []byte(l.SecretHash)) == 1
+}
+
+func hashSharePassword(password string) (string, error) {
+	salt := make([]byte, sharePasswordSaltLen)
+	if _, err := rand.Read(salt); err != nil {
+		return "", fmt.Errorf("generate password salt: %w", err)
+	}
+	key, err := pbkdf2.Key(sha256.New, password, salt, sharePasswordIter, sharePasswordKeyLen)
+	if err != nil {
+		return "", fmt.Errorf("hash password: %w", err)
+	}
+
+	return strings.Join([]string{
+		sharePasswordScheme,
+		strconv.Itoa(sharePasswordIter),
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(key),
+	}, "$"), nil
+}
+
+// VerifyPassword checks the password of the password protected links.
+// The links without password accept any password.
+func (l ShareLink) VerifyPassword(password string) bool {
+	if l.PasswordHash == "" {
+		return true
+	}
+
+	parts := strings.Split(l.PasswordHash, "$")
+	if len(parts) != 4 || parts[0] != sharePasswordScheme {
+		return false
+	}
+	iter, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return false
+	}
+	salt, err := base64.RawStdEncoding.DecodeString(parts[2])
+	if err != nil {
+		return false
+	}
+	want, err := base64.RawStdEncoding.DecodeString(parts[3])
+	if err != nil {
+		return false
+	}
+	got, err := pbkdf2.Key(sha256.New, password, salt, iter, len(want))
+	if err != nil {
+		return false
+	}
+
+	return subtle.ConstantTimeCompare(got, want) == 1
+}
+
+// Allows checks if the link grants the permission
+func (l ShareLink) Allows(p SharePermission) bool {
+	return containsPermission(l.Permissions, p)
+}
+
+// IsPrefix reports whether the link shares a prefix rather than an object
+func (l ShareLink) IsPrefix() bool {
+	return l.Key == "" || strings.HasSuffix(l.Key, "/")
+}
+
+// ObjectKey maps the path relative to the link to the object key.
+// The object links only accept an empty path, the prefix links
+// any path without '.' and '..' segments.
+func (l ShareLink) ObjectKey(rel string) (string, bool) {
+	if !l.IsPrefix() {
+		return l.Key, rel == ""
+	}
+	if rel == "" {
+		return "", false
+	}
+	for _, seg := range strings.Split(rel, "/") {
+		if seg == "." || seg == ".." {
+			return "", false
+		}
+	}
+	return l.Key + rel, true
+}
+
+// IsExpired checks if the link expired or reached the download limit
+func (l ShareLink) IsExpired(now time.Time) bool {
+	if !now.Before(l.Expires) {
+		return true
+	}
+	return l.DownloadLimit > 0 && l.Downloads >= l.DownloadLimit
+}
+
+// Public returns the link without the secrets to be listed
+func (l ShareLink) Public() ShareLink {
+	l.Token = ""
+	l.Password = ""
+	l.PasswordProtected = l.PasswordHash != ""
+	return l
+}
@@ -0,0 +1,212 @@
+// Copyright 2026 Versity Software
+// This file is licensed under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package auth
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/versity/versitygw/s3err"
+)
+
+func TestNewShareLink(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name    string
+		input   ShareLink
+		wantErr bool
+	}{
+		{"valid", ShareLink{Bucket: "bucket", Permissions: []SharePermission{"read"}, Expires: now.Add(time.Hour)}, false},
+		{"mixed case permission", ShareLink{Bucket: "bucket", Permissions: []SharePermission{"Upload"}, Expires: now.Add(time.Hour)}, false},
+		{"missing bucket", ShareLink{Permissions: []SharePermission{"read"}, Expires: now.Add(time.Hour)}, true},
+		{"missing permissions", ShareLink{Bucket: "bucket", Expires: now.Add(time.Hour)}, true},
+		{"invalid permission", ShareLink{Bucket: "bucket", Permissions: []SharePermission{"delete"}, Expires: now.Add(time.Hour)}, true},
+		{"past expiry", ShareLink{Bucket: "bucket", Permissions: []SharePermission{"read"}, Expires: now.Add(-time.Hour)}, true},
+		{"negative limit", ShareLink{Bucket: "bucket", Permissions: []SharePermission{"read"}, Expires: now.Add(time.Hour), DownloadLimit: -1}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			link, err := NewShareLink(tt.input, "admin", now)
+			if tt.wantErr {
+				assert.EqualError(t, err, s3err.GetAPIError(s3err.ErrAdminInvalidShareLink).Error())
+				return
+			}
+			require.NoError(t, err)
+			assert.NotEmpty(t, link.ID)
+			assert.Equal(t, "admin", link.Creator)
+			assert.False(t, link.PasswordProtected)
+
+			id, secret, ok := ParseShareToken(link.Token)
+			require.True(t, ok)
+			assert.Equal(t, link.ID, id)
+			assert.True(t, link.VerifySecret(secret))
+			assert.False(t, link.VerifySecret(secret+"x"))
+		})
+	}
+}
+
+func TestShareLink_VerifyPassword(t *testing.T) {
+	link, err := NewShareLink(ShareLink{
+		Bucket:      "bucket",
+		Permissions: []SharePermission{SharePermissionRead},
+		Expires:     time.Now().Add(time.Hour),
+		Password:    "s3cret",
+	}, "admin", time.Now())
+	require.NoError(t, err)
+
+	assert.True(t, link.PasswordProtected)
+	assert.NotContains(t, link.PasswordHash, "s3cret")
+	assert.True(t, link.VerifyPassword("s3cret"))
+	assert.False(t, link.VerifyPassword("wrong"))
+	assert.False(t, link.VerifyPassword(""))
+
+	assert.True(t, ShareLink{}.VerifyPassword("anything"))
+}
+
+func TestShareLink_ObjectKey(t *testing.T) {
+	tests := []struct {
+		name   string
+		key    string
+		rel    string
+		want   string
+		wantOk bool
+	}{
+		{"object link", "dir/obj", "", "dir/obj", true},
+		{"object link with path", "dir/obj", "other", "dir/obj", false},
+		{"prefix link", "dir/", "sub/obj", "dir/sub/obj", true},
+		{"bucket link", "", "obj", "obj", true},
+		{"prefix link root", "dir/", "", "", false},
+		{"prefix link traversal", "dir/", "../obj", "", false},
+		{"prefix link dot segment", "dir/", "sub/./obj", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := ShareLink{Key: tt.key}.ObjectKey(tt.rel)
+			assert.Equal(t, tt.wantOk, ok)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestShareLink_IsExpired(t *testing.T) {
+	now := time.Now()
+	assert.False(t, ShareLink{Expires: now.Add(time.Minute)}.IsExpired(now))
+	assert.True(t, ShareLink{Expires: now}.IsExpired(now))
+	assert.False(t, ShareLink{Expires: now.Add(time.Minute), DownloadLimit: 2, Downloads: 1}.IsExpired(now))
+	assert.True(t, ShareLink{Expires: now.Add(time.Minute), DownloadLimit: 2, Downloads: 2}.IsExpired(now))
+}
+
+func TestShareStoreInternal(t *testing.T) {
+	dir := t.TempDir()
+	store, err := NewShareStoreInternal(dir)
+	require.NoError(t, err)
+
+	link, err := NewShareLink(ShareLink{
+		Bucket:        "bucket",
+		Permissions:   []SharePermission{SharePermissionRead},
+		Expires:       time.Now().Add(time.Hour),
+		Password:      "s3cret",
+		DownloadLimit: 1,
+	}, "admin", time.Now())
+	require.NoError(t, err)
+	require.NoError(t, store.CreateShareLink(link))
+
+	// the links persist across the store instances
+	store, err = NewShareStoreInternal(dir)
+	require.NoError(t, err)
+
+	got, err := store.GetShareLink(link.ID)
+	require.NoError(t, err)
+	assert.Empty(t, got.Token)
+	assert.Empty(t, got.Password)
+	assert.Equal(t, link.SecretHash, got.SecretHash)
+	assert.True(t, got.VerifyPassword("s3cret"))
+
+	links, err := store.ListShareLinks()
+	require.NoError(t, err)
+	assert.Len(t, links, 1)
+
+	require.NoError(t, store.RecordDownload(link.ID))
+	assert.EqualError(t, store.RecordDownload(link.ID), s3err.GetAPIError(s3err.ErrShareLinkExpired).Error())
+
+	require.NoError(t, store.DeleteShareLink(link.ID))
+	_, err = store.GetShareLink(link.ID)
+	assert.ErrorIs(t, err, ErrNoSuchShareLink)
+	assert.ErrorIs(t, store.DeleteShareLink(link.ID), ErrNoSuchShareLink)
+}
+
+func TestShareStoreInternal_Updates(t *testing.T) {
+	dir := t.TempDir()
+	store, err := NewShareStoreInternal(dir)
+	require.NoError(t, err)
+
+	newLink := func(expires time.Time) ShareLink {
+		link, err := NewShareLink(ShareLink{
+			Bucket:      "bucket",
+			Permissions: []SharePermission{SharePermissionRead},
+			Expires:     expires,
+		}, "admin", time.Now())
+		require.NoError(t, err)
+		return link
+	}
+
+	expiring := newLink(time.Now().Add(100 * time.Millisecond))
+	require.NoError(t, store.CreateShareLink(expiring))
+	link := newLink(time.Now().Add(time.Hour))
+	require.NoError(t, store.CreateShareLink(link))
+
+	fname := filepath.Join(dir, shareFile)
+	before, err := os.Stat(fname)
+	require.NoError(t, err)
+
+	// the lookups and the downloads of the links without a download
+	// limit don't rewrite the file
+	_, err = store.GetShareLink(link.ID)
+	require.NoError(t, err)
+	require.NoError(t, store.RecordDownload(link.ID))
+	require.NoError(t, store.RecordDownload(link.ID))
+	after, err := os.Stat(fname)
+	require.NoError(t, err)
+	assert.True(t, os.SameFile(before, after))
+
+	got, err := store.GetShareLink(link.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 2, got.Downloads)
+
+	// the links updated by another instance are read again
+	other, err := NewShareStoreInternal(dir)
+	require.NoError(t, err)
+	added := newLink(time.Now().Add(time.Hour))
+	require.NoError(t, other.CreateShareLink(added))
+	_, err = store.GetShareLink(added.ID)
+	require.NoError(t, err)
+
+	// the expired links are removed and the download counts written
+	// with the next update
+	time.Sleep(100 * time.Millisecond)
+	require.NoError(t, store.DeleteShareLink(added.ID))
+
+	store, err = NewShareStoreInternal(dir)
+	require.NoError(t, err)
+	_, err = store.GetShareLink(expiring.ID)
+	assert.ErrorIs(t, err, ErrNoSuchShareLink)
+	got, err = store.GetShareLink(link.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 2, got.Downloads)
+}
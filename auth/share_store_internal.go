@@ -0,0 +1,275 @@
+// Copyright 2026 Versity Software
+// This file is licensed under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package auth
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/versity/versitygw/s3err"
+)
+
+const (
+	shareFile = "shares.json"
+	shareMode = 0600
+)
+
+// ShareStoreInternal stores the share links in a json file within
+// a directory. Same as the internal IAM service, the mutex serializes
+// the updates from a single gateway instance only. All the link
+// updates should be sent to a single gateway instance if possible.
+//
+// The parsed file is cached and only read again once it was replaced
+// by another instance. The downloads of the links without a download
+// limit don't change the access to the link, so they are counted in
+// memory and written with the next update of the file.
+type ShareStoreInternal struct {
+	sync.Mutex
+	dir string
+
+	conf shareConfig
+	fi   fs.FileInfo
+	// downloads are the download counts not written to the file yet
+	downloads map[string]int
+}
+
+// shareConfig stores all the share links
+type shareConfig struct {
+	ShareLinks map[string]ShareLink `json:"shareLinks"`
+}
+
+var _ ShareStore = &ShareStoreInternal{}
+
+// NewShareStoreInternal creates a new share link store within dir
+func NewShareStoreInternal(dir string) (*ShareStoreInternal, error) {
+	s := &ShareStoreInternal{dir: dir, downloads: make(map[string]int)}
+
+	fname := filepath.Join(dir, shareFile)
+	_, err := os.Stat(fname)
+	if errors.Is(err, fs.ErrNotExist) {
+		err = s.write(shareConfig{ShareLinks: map[string]ShareLink{}})
+		if err != nil {
+			return nil, fmt.Errorf("write default share links: %w", err)
+		}
+		return s, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("stat share links file: %w", err)
+	}
+
+	return s, nil
+}
+
+// CreateShareLink stores a new share link
+func (s *ShareStoreInternal) CreateShareLink(link ShareLink) error {
+	s.Lock()
+	defer s.Unlock()
+
+	conf, err := s.read()
+	if err != nil {
+		return err
+	}
+
+	link.Token = ""
+	link.Password = ""
+	conf.ShareLinks[link.ID] = link
+
+	return s.write(conf)
+}
+
+// GetShareLink returns the share link. Returns ErrNoSuchShareLink
+// if the link does not exist.
+func (s *ShareStoreInternal) GetShareLink(id string) (ShareLink, error) {
+	s.Lock()
+	defer s.Unlock()
+
+	conf, err := s.read()
+	if err != nil {
+		return ShareLink{}, err
+	}
+
+	link, ok := conf.ShareLinks[id]
+	if !ok {
+		return ShareLink{}, ErrNoSuchShareLink
+	}
+	link.Downloads += s.downloads[id]
+
+	return link, nil
+}
+
+// ListShareLinks lists all the share links sorted by creation time
+func (s *ShareStoreInternal) ListShareLinks() ([]ShareLink, error) {
+	s.Lock()
+	defer s.Unlock()
+
+	conf, err := s.read()
+	if err != nil {
+		return nil, err
+	}
+
+	links := make([]ShareLink, 0, len(conf.ShareLinks))
+	for id, link := range conf.ShareLinks {
+		link.Downloads += s.downloads[id]
+		links = append(links, link)
+	}
+	sort.Slice(links, func(i, j int) bool {
+		if links[i].Created.Equal(links[j].Created) {
+			return links[i].ID < links[j].ID
+		}
+		return links[i].Created.Before(links[j].Created)
+	})
+
+	return links, nil
+}
+
+// DeleteShareLink revokes the share link. Returns ErrNoSuchShareLink
+// if the link does not exist.
+func (s *ShareStoreInternal) DeleteShareLink(id string) error {
+	s.Lock()
+	defer s.Unlock()
+
+	conf, err := s.read()
+	if err != nil {
+		return err
+	}
+
+	if _, ok := conf.ShareLinks[id]; !ok {
+		return ErrNoSuchShareLink
+	}
+	delete(conf.ShareLinks, id)
+	delete(s.downloads, id)
+
+	return s.write(conf)
+}
+
+// RecordDownload counts a download of the share link. Only the downloads
+// of the links with a download limit are written to the file right away.
+func (s *ShareStoreInternal) RecordDownload(id string) error {
+	s.Lock()
+	defer s.Unlock()
+
+	conf, err := s.read()
+	if err != nil {
+		return err
+	}
+
+	link, ok := conf.ShareLinks[id]
+	if !ok {
+		return ErrNoSuchShareLink
+	}
+	if link.DownloadLimit == 0 {
+		s.downloads[id]++
+		return nil
+	}
+	if link.Downloads >= link.DownloadLimit {
+		return s3err.GetAPIError(s3err.ErrShareLinkExpired)
+	}
+
+	link.Downloads++
+	conf.ShareLinks[id] = link
+
+	return s.write(conf)
+}
+
+// read returns the share links, parsing the file only if it was
+// replaced since it was last read or written
+func (s *ShareStoreInternal) read() (shareConfig, error) {
+	fname := filepath.Join(s.dir, shareFile)
+	fi, err := os.Stat(fname)
+	if err != nil {
+		return shareConfig{}, fmt.Errorf("stat share links file: %w", err)
+	}
+	if s.fi != nil && os.SameFile(s.fi, fi) && s.fi.ModTime().Equal(fi.ModTime()) {
+		return s.conf, nil
+	}
+
+	b, err := os.ReadFile(fname)
+	if err != nil {
+		return shareConfig{}, fmt.Errorf("read share links file: %w", err)
+	}
+
+	var conf shareConfig
+	if err := json.Unmarshal(b, &conf); err != nil {
+		return shareConfig{}, fmt.Errorf("parse share links file: %w", err)
+	}
+	if conf.ShareLinks == nil {
+		conf.ShareLinks = make(map[string]ShareLink)
+	}
+
+	s.conf = conf
+	s.fi = fi
+	return conf, nil
+}
+
+// write replaces the share links file with a renamed temp file,
+// so the readers always see a consistent file. The links past their
+// expiry are removed, and the pending download counts are added.
+func (s *ShareStoreInternal) write(conf shareConfig) error {
+	// the cached links are updated in place, so these have to be
+	// parsed again if the write fails
+	s.fi = nil
+
+	now := time.Now()
+	for id, link := range conf.ShareLinks {
+		if !now.Before(link.Expires) {
+			delete(conf.ShareLinks, id)
+			continue
+		}
+		link.Downloads += s.downloads[id]
+		conf.ShareLinks[id] = link
+	}
+
+	b, err := json.Marshal(conf)
+	if err != nil {
+		return fmt.Errorf("serialize share links: %w", err)
+	}
+
+	f, err := os.CreateTemp(s.dir, shareFile)
+	if err != nil {
+		return fmt.Errorf("create temp file: %w", err)
+	}
+	defer os.Remove(f.Name())
+
+	_, err = f.Write(b)
+	if err == nil {
+		err = f.Chmod(shareMode)
+	}
+	var fi fs.FileInfo
+	if err == nil {
+		// the renamed file keeps the modification time
+		fi, err = f.Stat()
+	}
+	f.Close()
+	if err != nil {
+		return fmt.Errorf("write temp file: %w", err)
+	}
+
+	err = os.Rename(f.Name(), filepath.Join(s.dir, shareFile))
+	if err != nil {
+		return fmt.Errorf("rename temp file: %w", err)
+	}
+	clear(s.downloads)
+	s.conf = conf
+	s.fi = fi
+
+	return nil
+}
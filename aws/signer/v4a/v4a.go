@@ -0,0 +1,541 @@
+// Copyright 2026 Versity Software
+// This file is licensed under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+// Package v4a implements signing and verification for the AWS SigV4A
+// signatures.
+//
+// SigV4A is the asymmetric variant of SigV4 used by the multi-region
+// access points. The canonical request is built the same way as for
+// SigV4, but the string to sign is signed with an ECDSA P-256 key derived
+// from the access key pair, and the credential scope has no region.
+// Instead the set of regions the signature is valid in is signed in the
+// X-Amz-Region-Set header, or query parameter for the presigned requests.
+//
+// As the ECDSA signatures are randomized, the signatures can't be compared
+// with a signature generated by the verifier, so the signature is verified
+// with the public key derived from the secret access key instead.
+package v4a
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/textproto"
+	"net/url"
+	"path"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/smithy-go/encoding/httpbinding"
+	"github.com/aws/smithy-go/logging"
+	v4Internal "github.com/versity/versitygw/aws/signer/internal/v4"
+)
+
+const (
+	// Algorithm is the SigV4A signing algorithm
+	Algorithm = "AWS4-ECDSA-P256-SHA256"
+
+	// AmzRegionSetKey is the header, or query parameter for the presigned
+	// requests, with the comma separated set of regions the signature is
+	// valid in
+	AmzRegionSetKey = "X-Amz-Region-Set"
+
+	authorizationHeader = "Authorization"
+	amzSignatureKey     = "X-Amz-Signature"
+
+	// Version of signing v4a
+	Version = "SigV4A"
+)
+
+// ErrSignatureMismatch is returned when the signature doesn't verify
+// with the key derived from the credentials
+var ErrSignatureMismatch = errors.New("signature does not match")
+
+var (
+	p256          = elliptic.P256()
+	nMinusTwoP256 = new(big.Int).Sub(p256.Params().N, big.NewInt(2))
+)
+
+// SignerOptions is the SigV4A Signer options.
+type SignerOptions struct {
+	// Disables the automatic escaping of the URI path of the request for the
+	// siganture's canonical string's path. S3 doesn't need the additional
+	// escaping.
+	DisableURIPathEscaping bool
+
+	// The logger to send log messages to.
+	Logger logging.Logger
+
+	// Enable logging of the canonical request and the string to sign.
+	LogSigning bool
+}
+
+// Signer applies and verifies the AWS SigV4A signatures of the requests
+type Signer struct {
+	options SignerOptions
+}
+
+// NewSigner returns a new SigV4A Signer
+func NewSigner(optFns ...func(signer *SignerOptions)) *Signer {
+	options := SignerOptions{}
+
+	for _, fn := range optFns {
+		fn(&options)
+	}
+
+	return &Signer{options: options}
+}
+
+type keyCacheEntry struct {
+	secret string
+	key    *ecdsa.PrivateKey
+}
+
+// keyCache caches the keys derived per access key, as the derivation is
+// needed for every request
+var keyCache = struct {
+	sync.RWMutex
+	keys map[string]keyCacheEntry
+}{keys: make(map[string]keyCacheEntry)}
+
+// DeriveKey derives the ECDSA P-256 signing key of the access key pair,
+// with the NIST SP 800-108 KDF in counter mode with HMAC-SHA256 as the PRF
+func DeriveKey(accessKeyID, secret string) (*ecdsa.PrivateKey, error) {
+	keyCache.RLock()
+	e, ok := keyCache.keys[accessKeyID]
+	keyCache.RUnlock()
+	if ok && e.secret == secret {
+		return e.key, nil
+	}
+
+	key, err := deriveKey(accessKeyID, secret)
+	if err != nil {
+		return nil, err
+	}
+
+	keyCache.Lock()
+	keyCache.keys[accessKeyID] = keyCacheEntry{secret: secret, key: key}
+	keyCache.Unlock()
+
+	return key, nil
+}
+
+func deriveKey(accessKeyID, secret string) (*ecdsa.PrivateKey, error) {
+	inputKey := []byte("AWS4A" + secret)
+	bitLen := p256.Params().BitSize
+
+	// the candidates must be within [1, n-1], the candidates above n-2
+	// are skipped by incrementing the external counter
+	for counter := 1; counter <= 0xFF; counter++ {
+		kdfContext := append([]byte(accessKeyID), byte(counter))
+		candidate := hmacKeyDerivation(bitLen, inputKey, []byte(Algorithm), kdfContext)
+
+		c := new(big.Int).SetBytes(candidate)
+		if c.Cmp(nMinusTwoP256) > 0 {
+			continue
+		}
+
+		d := c.Add(c, big.NewInt(1)).FillBytes(make([]byte, bitLen/8))
+		return ecdsa.ParseRawPrivateKey(p256, d)
+	}
+
+	return nil, errors.New("exhausted single byte external counter")
+}
+
+// hmacKeyDerivation is the NIST SP 800-108 KDF in counter mode
+func hmacKeyDerivation(bitLen int, key, label, context []byte) []byte {
+	var fixedInput bytes.Buffer
+	fixedInput.Write(label)
+	fixedInput.WriteByte(0x00)
+	fixedInput.Write(context)
+	binary.Write(&fixedInput, binary.BigEndian, int32(bitLen))
+
+	h := hmac.New(sha256.New, key)
+	n := (bitLen/8 + h.Size() - 1) / h.Size()
+
+	var output []byte
+	for i := 1; i <= n; i++ {
+		h.Reset()
+		binary.Write(h, binary.BigEndian, int32(i))
+		h.Write(fixedInput.Bytes())
+		output = h.Sum(output)
+	}
+
+	return output[:bitLen/8]
+}
+
+// BuildCredentialScope returns the SigV4A credential scope of the signing
+// time and service
+func BuildCredentialScope(signingTime time.Time, service string) string {
+	return strings.Join([]string{
+		signingTime.UTC().Format(v4Internal.ShortTimeFormat),
+		service,
+		"aws4_request",
+	}, "/")
+}
+
+// SignString returns the hex encoded signature of the string to sign
+func SignString(key *ecdsa.PrivateKey, strToSign string) (string, error) {
+	hash := sha256.Sum256([]byte(strToSign))
+	sig, err := ecdsa.SignASN1(rand.Reader, key, hash[:])
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(sig), nil
+}
+
+// VerifyString reports if the hex encoded signature is a valid signature
+// of the string to sign
+func VerifyString(key *ecdsa.PublicKey, strToSign, signature string) bool {
+	sig, err := hex.DecodeString(signature)
+	if err != nil {
+		return false
+	}
+	hash := sha256.Sum256([]byte(strToSign))
+	return ecdsa.VerifyASN1(key, hash[:], sig)
+}
+
+// RegionSetContains reports if the region is in the comma separated region
+// set. The region set entries may contain the "*" wildcard, e.g. "*" or
+// "us-*".
+func RegionSetContains(regionSet, region string) bool {
+	for entry := range strings.SplitSeq(regionSet, ",") {
+		ok, err := path.Match(strings.TrimSpace(entry), region)
+		if err == nil && ok {
+			return true
+		}
+	}
+	return false
+}
+
+type httpSigner struct {
+	Request     *http.Request
+	ServiceName string
+	RegionSet   string
+	Time        time.Time
+	AccessKeyID string
+	IsPreSign   bool
+	SignedHdrs  []string
+
+	PayloadHash string
+
+	DisableURIPathEscaping bool
+}
+
+// Build returns the canonical request and the string to sign of the
+// request, and sets the signing fields of the request
+func (s *httpSigner) Build() (signedRequest, error) {
+	req := s.Request
+
+	query := req.URL.Query()
+	headers := req.Header
+
+	s.setRequiredSigningFields(headers, query)
+
+	// Sort Each Query Key's Values
+	for key := range query {
+		sort.Strings(query[key])
+	}
+
+	v4Internal.SanitizeHostForHeader(req)
+
+	credentialScope := BuildCredentialScope(s.Time, s.ServiceName)
+	credentialStr := s.AccessKeyID + "/" + credentialScope
+	if s.IsPreSign {
+		query.Set(v4Internal.AmzCredentialKey, credentialStr)
+	}
+
+	host := req.URL.Host
+	if len(req.Host) > 0 {
+		host = req.Host
+	}
+
+	signedHeaders, signedHeadersStr, canonicalHeaderStr := s.buildCanonicalHeaders(host, v4Internal.IgnoredHeaders, headers, s.Request.ContentLength)
+
+	if s.IsPreSign {
+		query.Set(v4Internal.AmzSignedHeadersKey, signedHeadersStr)
+	}
+
+	rawQuery := strings.ReplaceAll(query.Encode(), "+", "%20")
+
+	canonicalURI := v4Internal.GetURIPath(req.URL)
+	if !s.DisableURIPathEscaping {
+		canonicalURI = httpbinding.EscapePath(canonicalURI, false)
+	}
+
+	canonicalString := strings.Join([]string{
+		req.Method,
+		canonicalURI,
+		rawQuery,
+		canonicalHeaderStr,
+		signedHeadersStr,
+		s.PayloadHash,
+	}, "\n")
+
+	hash := sha256.Sum256([]byte(canonicalString))
+	strToSign := strings.Join([]string{
+		Algorithm,
+		s.Time.Format(v4Internal.TimeFormat),
+		credentialScope,
+		hex.EncodeToString(hash[:]),
+	}, "\n")
+
+	req.URL.RawQuery = rawQuery
+
+	return signedRequest{
+		Request:          req,
+		SignedHeaders:    signedHeaders,
+		SignedHeadersStr: signedHeadersStr,
+		CredentialStr:    credentialStr,
+		CanonicalString:  canonicalString,
+		StringToSign:     strToSign,
+		PreSigned:        s.IsPreSign,
+	}, nil
+}
+
+func (s *httpSigner) setRequiredSigningFields(headers http.Header, query url.Values) {
+	amzDate := s.Time.Format(v4Internal.TimeFormat)
+
+	if s.IsPreSign {
+		query.Set(AmzRegionSetKey, s.RegionSet)
+		query.Set(v4Internal.AmzDateKey, amzDate)
+		query.Set(v4Internal.AmzAlgorithmKey, Algorithm)
+		return
+	}
+
+	headers.Set(AmzRegionSetKey, s.RegionSet)
+	headers.Set(v4Internal.AmzDateKey, amzDate)
+}
+
+func (s *httpSigner) buildCanonicalHeaders(host string, rule v4Internal.Rule, header http.Header, length int64) (signed http.Header, signedHeaders, canonicalHeadersStr string) {
+	signed = make(http.Header)
+
+	var headers []string
+	const hostHeader = "host"
+	headers = append(headers, hostHeader)
+	signed[hostHeader] = append(signed[hostHeader], host)
+
+	const contentLengthHeader = "content-length"
+	if slices.Contains(s.SignedHdrs, contentLengthHeader) {
+		headers = append(headers, contentLengthHeader)
+		signed[contentLengthHeader] = append(signed[contentLengthHeader], strconv.FormatInt(length, 10))
+	}
+
+	for k, v := range header {
+		if !rule.IsValid(k) {
+			continue // ignored header
+		}
+		if strings.EqualFold(k, contentLengthHeader) {
+			// prevent signing already handled content-length header.
+			continue
+		}
+
+		lowerCaseKey := strings.ToLower(k)
+		if _, ok := signed[lowerCaseKey]; ok {
+			// include additional values
+			signed[lowerCaseKey] = append(signed[lowerCaseKey], v...)
+			continue
+		}
+
+		headers = append(headers, lowerCaseKey)
+		signed[lowerCaseKey] = v
+	}
+	sort.Strings(headers)
+
+	signedHeaders = strings.Join(headers, ";")
+
+	var canonicalHeaders strings.Builder
+	for _, h := range headers {
+		canonicalHeaders.WriteString(h)
+		canonicalHeaders.WriteRune(':')
+		if h == hostHeader {
+			canonicalHeaders.WriteString(v4Internal.StripExcessSpaces(host))
+		} else {
+			// Trim out leading, trailing, and dedup inner spaces from signed header values.
+			values := signed[h]
+			for j, v := range values {
+				canonicalHeaders.WriteString(strings.TrimSpace(v4Internal.StripExcessSpaces(v)))
+				if j < len(values)-1 {
+					canonicalHeaders.WriteRune(',')
+				}
+			}
+		}
+		canonicalHeaders.WriteRune('\n')
+	}
+	canonicalHeadersStr = canonicalHeaders.String()
+
+	return signed, signedHeaders, canonicalHeadersStr
+}
+
+func (s *Signer) newHTTPSigner(credentials aws.Credentials, r *http.Request, payloadHash, service, regionSet string, signingTime time.Time, signedHdrs []string, isPresign bool, optFns []func(*SignerOptions)) (*httpSigner, SignerOptions) {
+	options := s.options
+	for _, fn := range optFns {
+		fn(&options)
+	}
+
+	return &httpSigner{
+		Request:                r,
+		PayloadHash:            payloadHash,
+		ServiceName:            service,
+		RegionSet:              regionSet,
+		AccessKeyID:            credentials.AccessKeyID,
+		Time:                   signingTime.UTC(),
+		IsPreSign:              isPresign,
+		SignedHdrs:             signedHdrs,
+		DisableURIPathEscaping: options.DisableURIPathEscaping,
+	}, options
+}
+
+// SignHTTP signs the request with SigV4A for the region set, and sets the
+// Authorization header. The passed in request is modified in place.
+func (s *Signer) SignHTTP(ctx context.Context, credentials aws.Credentials, r *http.Request, payloadHash, service, regionSet string, signingTime time.Time, signedHdrs []string, optFns ...func(*SignerOptions)) error {
+	key, err := DeriveKey(credentials.AccessKeyID, credentials.SecretAccessKey)
+	if err != nil {
+		return err
+	}
+
+	signer, options := s.newHTTPSigner(credentials, r, payloadHash, service, regionSet, signingTime, signedHdrs, false, optFns)
+	signedRequest, err := signer.Build()
+	if err != nil {
+		return err
+	}
+
+	signature, err := SignString(key, signedRequest.StringToSign)
+	if err != nil {
+		return err
+	}
+
+	r.Header.Set(authorizationHeader, fmt.Sprintf("%s Credential=%s, SignedHeaders=%s, Signature=%s",
+		Algorithm, signedRequest.CredentialStr, signedRequest.SignedHeadersStr, signature))
+
+	logSigningInfo(ctx, options, &signedRequest)
+
+	return nil
+}
+
+// PresignHTTP signs the request with SigV4A for the region set with the
+// query parameters, and returns the signed URL and the signed headers.
+// The X-Amz-Expires query parameter must be set on the request. This
+// method does not modify the provided request.
+func (s *Signer) PresignHTTP(ctx context.Context, credentials aws.Credentials, r *http.Request, payloadHash, service, regionSet string, signingTime time.Time, signedHdrs []string, optFns ...func(*SignerOptions)) (string, http.Header, error) {
+	key, err := DeriveKey(credentials.AccessKeyID, credentials.SecretAccessKey)
+	if err != nil {
+		return "", nil, err
+	}
+
+	signer, options := s.newHTTPSigner(credentials, r.Clone(r.Context()), payloadHash, service, regionSet, signingTime, signedHdrs, true, optFns)
+	signedRequest, err := signer.Build()
+	if err != nil {
+		return "", nil, err
+	}
+
+	signature, err := SignString(key, signedRequest.StringToSign)
+	if err != nil {
+		return "", nil, err
+	}
+	signedRequest.Request.URL.RawQuery += "&" + amzSignatureKey + "=" + signature
+
+	logSigningInfo(ctx, options, &signedRequest)
+
+	signedHeaders := make(http.Header)
+	for k, v := range signedRequest.SignedHeaders {
+		key := textproto.CanonicalMIMEHeaderKey(k)
+		signedHeaders[key] = append(signedHeaders[key], v...)
+	}
+
+	return signedRequest.Request.URL.String(), signedHeaders, nil
+}
+
+// VerifyHTTP verifies the SigV4A signature of the request signed with the
+// Authorization header. ErrSignatureMismatch is returned if the signature
+// isn't a signature of the request by the credentials.
+func (s *Signer) VerifyHTTP(ctx context.Context, credentials aws.Credentials, r *http.Request, payloadHash, service, regionSet string, signingTime time.Time, signedHdrs []string, signature string, optFns ...func(*SignerOptions)) error {
+	return s.verify(ctx, credentials, r, payloadHash, service, regionSet, signingTime, signedHdrs, signature, false, optFns)
+}
+
+// VerifyPresignedHTTP verifies the SigV4A signature of the request signed
+// with the query parameters, the request URL must not contain the
+// X-Amz-Signature query parameter. ErrSignatureMismatch is returned if the
+// signature isn't a signature of the request by the credentials.
+func (s *Signer) VerifyPresignedHTTP(ctx context.Context, credentials aws.Credentials, r *http.Request, payloadHash, service, regionSet string, signingTime time.Time, signedHdrs []string, signature string, optFns ...func(*SignerOptions)) error {
+	return s.verify(ctx, credentials, r, payloadHash, service, regionSet, signingTime, signedHdrs, signature, true, optFns)
+}
+
+func (s *Signer) verify(ctx context.Context, credentials aws.Credentials, r *http.Request, payloadHash, service, regionSet string, signingTime time.Time, signedHdrs []string, signature string, isPresign bool, optFns []func(*SignerOptions)) error {
+	key, err := DeriveKey(credentials.AccessKeyID, credentials.SecretAccessKey)
+	if err != nil {
+		return err
+	}
+
+	signer, options := s.newHTTPSigner(credentials, r, payloadHash, service, regionSet, signingTime, signedHdrs, isPresign, optFns)
+	signedRequest, err := signer.Build()
+	if err != nil {
+		return err
+	}
+
+	logSigningInfo(ctx, options, &signedRequest)
+
+	if !VerifyString(&key.PublicKey, signedRequest.StringToSign, signature) {
+		return ErrSignatureMismatch
+	}
+
+	return nil
+}
+
+func logSigningInfo(ctx context.Context, options SignerOptions, request *signedRequest) {
+	if !options.LogSigning {
+		return
+	}
+	signedURLMsg := ""
+	if request.PreSigned {
+		signedURLMsg = fmt.Sprintf(logSignedURLMsg, request.Request.URL.String())
+	}
+	logger := logging.WithContext(ctx, options.Logger)
+	logger.Logf(logging.Debug, logSignInfoMsg, request.CanonicalString, request.StringToSign, signedURLMsg)
+}
+
+type signedRequest struct {
+	Request          *http.Request
+	SignedHeaders    http.Header
+	SignedHeadersStr string
+	CredentialStr    string
+	CanonicalString  string
+	StringToSign     string
+	PreSigned        bool
+}
+
+const logSignInfoMsg = `Request Signature:
+---[ CANONICAL STRING  ]-----------------------------
+%s
+---[ STRING TO SIGN ]--------------------------------
+%s%s
+-----------------------------------------------------`
+const logSignedURLMsg = `
+---[ SIGNED URL ]------------------------------------
+%s`
@@ -0,0 +1,172 @@
+// Copyright 2026 Versity Software
+// This file is licensed under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package v4a
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+)
+
+const (
+	accessKey = "AKISORANDOMAASORANDOM"
+	secretKey = "q+jcrXGc+0zWN6uzclKVhvMmUsIfRPa4rlRandom"
+)
+
+func TestDeriveKey(t *testing.T) {
+	key, err := DeriveKey(accessKey, secretKey)
+	if err != nil {
+		t.Fatalf("derive key: %v", err)
+	}
+
+	expectedX, _ := new(big.Int).SetString("15D242CEEBF8D8169FD6A8B5A746C41140414C3B07579038DA06AF89190FFFCB", 16)
+	expectedY, _ := new(big.Int).SetString("515242CEDD82E94799482E4C0514B505AFCCF2C0C98D6A553BF539F424C5EC0", 16)
+	if key.X.Cmp(expectedX) != 0 {
+		t.Errorf("expected X %X, got %X", expectedX, key.X)
+	}
+	if key.Y.Cmp(expectedY) != 0 {
+		t.Errorf("expected Y %X, got %X", expectedY, key.Y)
+	}
+
+	// the cached key is replaced when the secret changes
+	other, err := DeriveKey(accessKey, "other")
+	if err != nil {
+		t.Fatalf("derive key: %v", err)
+	}
+	if other.Equal(key) {
+		t.Errorf("expected a different key for a different secret")
+	}
+}
+
+func newRequest(t *testing.T) *http.Request {
+	req, err := http.NewRequest(http.MethodGet, "https://example.com/bucket/some%20key?list-type=2", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("X-Amz-Content-Sha256", "UNSIGNED-PAYLOAD")
+	return req
+}
+
+func TestSignVerifyHTTP(t *testing.T) {
+	creds := aws.Credentials{AccessKeyID: accessKey, SecretAccessKey: secretKey}
+	signedHdrs := []string{"host", "x-amz-content-sha256", "x-amz-date", "x-amz-region-set"}
+	now := time.Now()
+	signer := NewSigner(func(o *SignerOptions) { o.DisableURIPathEscaping = true })
+
+	req := newRequest(t)
+	err := signer.SignHTTP(context.Background(), creds, req, "UNSIGNED-PAYLOAD", "s3", "us-*", now, signedHdrs)
+	if err != nil {
+		t.Fatalf("sign: %v", err)
+	}
+
+	auth := req.Header.Get("Authorization")
+	prefix := Algorithm + " Credential=" + accessKey + "/" + now.UTC().Format("20060102") + "/s3/aws4_request, SignedHeaders=" + strings.Join(signedHdrs, ";") + ", Signature="
+	signature, ok := strings.CutPrefix(auth, prefix)
+	if !ok {
+		t.Fatalf("unexpected authorization %q", auth)
+	}
+
+	verify := func(r *http.Request, creds aws.Credentials, regionSet string) error {
+		return signer.VerifyHTTP(context.Background(), creds, r, "UNSIGNED-PAYLOAD", "s3", regionSet, now, signedHdrs, signature)
+	}
+
+	if err := verify(req, creds, "us-*"); err != nil {
+		t.Errorf("verify: %v", err)
+	}
+	if err := verify(req, creds, "*"); !errors.Is(err, ErrSignatureMismatch) {
+		t.Errorf("expected mismatch for a different region set, got %v", err)
+	}
+	if err := verify(req, aws.Credentials{AccessKeyID: accessKey, SecretAccessKey: "wrong"}, "us-*"); !errors.Is(err, ErrSignatureMismatch) {
+		t.Errorf("expected mismatch for a different secret, got %v", err)
+	}
+
+	req.URL.RawQuery = "list-type=1"
+	if err := verify(req, creds, "us-*"); !errors.Is(err, ErrSignatureMismatch) {
+		t.Errorf("expected mismatch for a modified request, got %v", err)
+	}
+}
+
+func TestPresignVerifyHTTP(t *testing.T) {
+	creds := aws.Credentials{AccessKeyID: accessKey, SecretAccessKey: secretKey}
+	signedHdrs := []string{"host"}
+	now := time.Now()
+	signer := NewSigner(func(o *SignerOptions) { o.DisableURIPathEscaping = true })
+
+	req := newRequest(t)
+	req.Header.Del("X-Amz-Content-Sha256")
+	q := req.URL.Query()
+	q.Set("X-Amz-Expires", "900")
+	req.URL.RawQuery = q.Encode()
+
+	uri, _, err := signer.PresignHTTP(context.Background(), creds, req, "UNSIGNED-PAYLOAD", "s3", "*", now, signedHdrs)
+	if err != nil {
+		t.Fatalf("presign: %v", err)
+	}
+
+	u, err := url.Parse(uri)
+	if err != nil {
+		t.Fatal(err)
+	}
+	query := u.Query()
+	if query.Get("X-Amz-Algorithm") != Algorithm || query.Get(AmzRegionSetKey) != "*" {
+		t.Fatalf("unexpected presigned query %v", query)
+	}
+	signature := query.Get("X-Amz-Signature")
+	query.Del("X-Amz-Signature")
+	u.RawQuery = query.Encode()
+
+	vreq, err := http.NewRequest(http.MethodGet, u.String(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = signer.VerifyPresignedHTTP(context.Background(), creds, vreq, "UNSIGNED-PAYLOAD", "s3", "*", now, signedHdrs, signature)
+	if err != nil {
+		t.Errorf("verify presigned: %v", err)
+	}
+
+	err = signer.VerifyPresignedHTTP(context.Background(), creds, vreq, "UNSIGNED-PAYLOAD", "s3", "*", now.Add(time.Second), signedHdrs, signature)
+	if !errors.Is(err, ErrSignatureMismatch) {
+		t.Errorf("expected mismatch for a different date, got %v", err)
+	}
+}
+
+func TestRegionSetContains(t *testing.T) {
+	tests := []struct {
+		set    string
+		region string
+		want   bool
+	}{
+		{"us-east-1", "us-east-1", true},
+		{"us-west-2,us-east-1", "us-east-1", true},
+		{"us-west-2, us-east-1", "us-east-1", true},
+		{"us-west-2", "us-east-1", false},
+		{"*", "eu-central-1", true},
+		{"us-*", "us-east-1", true},
+		{"us-*", "eu-west-1", false},
+		{"", "us-east-1", false},
+	}
+	for _, tt := range tests {
+		if got := RegionSetContains(tt.set, tt.region); got != tt.want {
+			t.Errorf("RegionSetContains(%q, %q) = %v, expected %v", tt.set, tt.region, got, tt.want)
+		}
+	}
+}
@@ -25,6 +25,7 @@ import (
 	"fmt"
 	"io"
 	"math"
+	"net/http"
 	"net/url"
 	"os"
 	"path/filepath"
@@ -63,13 +64,23 @@ const (
 	keyTags                key = "Tags"
 	keyPolicy              key = "Policy"
 	keyCors                key = "Cors"
+	keyPublicAccessBlock   key = "Publicaccessblock"
 	keyBucketLock          key = "Bucketlock"
 	keyObjRetention        key = "Objectretention"
 	keyObjLegalHold        key = "Objectlegalhold"
+	keyObjAcl              key = "Objectacl"
+	keyVersioning          key = "Versioning"
+	keyObjGeneration       key = "Vgwgeneration"
 	keyExpires             key = "Vgwexpires"
 	onameAttr              key = "Objname"
 	onameAttrLower         key = "objname"
 	metaTmpMultipartPrefix key = ".sgwtmp" + "/multipart"
+	// metaTmpObjAclPrefix holds the object acls, one blob per object
+	// generation, as setting the blob metadata creates a new blob version
+	metaTmpObjAclPrefix key = ".sgwtmp" + "/objacl"
+	// metaTmpPrefix is the namespace of the gateway blobs in the
+	// containers, it is hidden from the object requests and listings
+	metaTmpPrefix = ".sgwtmp/"
 	// keyMpZeroBytesParts tracks zero-byte upload parts in the sgwtmp metadata.
 	// Azure StageBlock rejects Content-Length: 0, so zero-byte parts are stored here.
 	keyMpZeroBytesParts key = "Zerobytesparts"
@@ -87,8 +98,11 @@ func (key) Table() map[string]struct{} {
 		"objectretention":   {},
 		"vgwexpires":        {},
 		"objectlegalhold":   {},
+		"objectacl":         {},
+		"vgwgeneration":     {},
 		"objname":           {},
 		".sgwtmp/multipart": {},
+		".sgwtmp/objacl":    {},
 	}
 }
 
@@ -100,6 +114,8 @@ type Azure struct {
 	defaultCreds   *azidentity.DefaultAzureCredential
 	serviceURL     string
 	sasToken       string
+
+	versioning versioningCache
 }
 
 var _ backend.Backend = &Azure{}
@@ -152,6 +168,25 @@ func New(accountName, accountKey, serviceURL, sasToken string) (*Azure, error) {
 
 func (az *Azure) Shutdown() {}
 
+var _ backend.HealthChecker = &Azure{}
+
+// CheckHealth checks the storage account service is reachable. The SAS
+// tokens might not allow the service requests, so with a SAS token any
+// response other than a server error is healthy.
+func (az *Azure) CheckHealth(ctx context.Context) error {
+	_, err := az.client.ServiceClient().GetProperties(ctx, nil)
+	if err == nil {
+		return nil
+	}
+
+	var azErr *azcore.ResponseError
+	if az.sasToken != "" && errors.As(err, &azErr) &&
+		azErr.StatusCode < http.StatusInternalServerError {
+		return nil
+	}
+	return fmt.Errorf("get service properties: %w", err)
+}
+
 func (az *Azure) String() string {
 	return "Azure Blob Gateway"
 }
@@ -288,16 +323,28 @@ func (az *Azure) HeadBucket(ctx context.Context, input *s3.HeadBucketInput) (*s3
 func (az *Azure) DeleteBucket(ctx context.Context, bucket string) error {
 	pager := az.client.NewListBlobsFlatPager(bucket, nil)
 
-	pg, err := pager.NextPage(ctx)
+	// the object acls left behind by the removed objects are
+	// deleted with the container
+	for pager.More() {
+		pg, err := pager.NextPage(ctx)
+		if err != nil {
+			return azureErrToS3Err(err)
+		}
+
+		for _, v := range pg.Segment.BlobItems {
+			if !isObjectAclBlob(v.Name) {
+				return s3err.GetAPIError(s3err.ErrBucketNotEmpty)
+			}
+		}
+	}
+
+	_, err := az.client.DeleteContainer(ctx, bucket, nil)
 	if err != nil {
 		return azureErrToS3Err(err)
 	}
 
-	if len(pg.Segment.BlobItems) > 0 {
-		return s3err.GetAPIError(s3err.ErrBucketNotEmpty)
-	}
-	_, err = az.client.DeleteContainer(ctx, bucket, nil)
-	return azureErrToS3Err(err)
+	az.versioning.remove(bucket)
+	return nil
 }
 
 func (az *Azure) PutBucketOwnershipControls(ctx context.Context, bucket string, ownership types.ObjectOwnership) error {
@@ -322,6 +369,10 @@ func (az *Azure) DeleteBucketOwnershipControls(ctx context.Context, bucket strin
 }
 
 func (az *Azure) PutObject(ctx context.Context, po s3response.PutObjectInput) (s3response.PutObjectOutput, error) {
+	if err := checkObjectKey(getString(po.Key)); err != nil {
+		return s3response.PutObjectOutput{}, err
+	}
+
 	tags, err := backend.ParseObjectTags(getString(po.Tagging))
 	if err != nil {
 		return s3response.PutObjectOutput{}, err
@@ -332,7 +383,7 @@ func (az *Azure) PutObject(ctx context.Context, po s3response.PutObjectInput) (s
 		return s3response.PutObjectOutput{}, err
 	}
 
-	metadata := parseMetadata(po.Metadata)
+	metadata := setObjectGeneration(parseMetadata(po.Metadata))
 
 	// Store the "Expires" property in the object metadata
 	if getString(po.Expires) != "" {
@@ -398,9 +449,34 @@ func (az *Azure) PutObject(ctx context.Context, po s3response.PutObjectInput) (s
 		}
 	}
 
+	var versionId string
+	if uploadResp.VersionID != nil {
+		status, err := az.getVersioningStatus(ctx, *po.Bucket)
+		if err != nil {
+			return s3response.PutObjectOutput{}, err
+		}
+		if status == types.BucketVersioningStatusEnabled {
+			azVersionId := uploadResp.VersionID
+			if po.ObjectLockLegalHoldStatus == types.ObjectLockLegalHoldStatusOn || po.ObjectLockMode != "" {
+				// setting the object lock metadata created a new blob version
+				client, err := az.getBlobClient(*po.Bucket, *po.Key)
+				if err != nil {
+					return s3response.PutObjectOutput{}, err
+				}
+				props, err := client.GetProperties(ctx, nil)
+				if err != nil {
+					return s3response.PutObjectOutput{}, azureErrToS3Err(err)
+				}
+				azVersionId = props.VersionID
+			}
+			versionId = backend.GetStringFromPtr(getVersionIdPtr(azVersionId))
+		}
+	}
+
 	return s3response.PutObjectOutput{
-		ETag: convertAzureEtag(uploadResp.ETag),
-		Size: po.ContentLength,
+		ETag:      convertAzureEtag(uploadResp.ETag),
+		Size:      po.ContentLength,
+		VersionID: versionId,
 	}, nil
 }
 
@@ -441,19 +517,24 @@ func (az *Azure) DeleteBucketTagging(ctx context.Context, bucket string) error {
 }
 
 func (az *Azure) GetObject(ctx context.Context, input *s3.GetObjectInput) (*s3.GetObjectOutput, error) {
+	if err := checkObjectKey(getString(input.Key)); err != nil {
+		return nil, err
+	}
+
 	if input.PartNumber != nil {
 		// querying an object with part number is not supported
 		return nil, s3err.GetAPIError(s3err.ErrNotImplemented)
 	}
 
-	client, err := az.getBlobClient(*input.Bucket, *input.Key)
+	versionId := getString(input.VersionId)
+	client, err := az.getObjectVersionClient(*input.Bucket, *input.Key, versionId)
 	if err != nil {
 		return nil, err
 	}
 
 	resp, err := client.GetProperties(ctx, nil)
 	if err != nil {
-		return nil, azureErrToS3Err(err)
+		return nil, azureVersionErrToS3Err(err, versionId)
 	}
 
 	if resp.ETag != nil && resp.LastModified != nil {
@@ -469,14 +550,14 @@ func (az *Azure) GetObject(ctx context.Context, input *s3.GetObjectInput) (*s3.G
 		}
 	}
 
-	var opts *azblob.DownloadStreamOptions
-	if *input.Range != "" {
+	var opts *blob.DownloadStreamOptions
+	if getString(input.Range) != "" {
 		offset, count, isValid, err := backend.ParseObjectRange(*resp.ContentLength, *input.Range)
 		if err != nil {
 			return nil, err
 		}
 		if isValid {
-			opts = &azblob.DownloadStreamOptions{
+			opts = &blob.DownloadStreamOptions{
 				Range: blob.HTTPRange{
 					Count:  count,
 					Offset: offset,
@@ -484,9 +565,9 @@ func (az *Azure) GetObject(ctx context.Context, input *s3.GetObjectInput) (*s3.G
 			}
 		}
 	}
-	blobDownloadResponse, err := az.client.DownloadStream(ctx, *input.Bucket, *input.Key, opts)
+	blobDownloadResponse, err := client.DownloadStream(ctx, opts)
 	if err != nil {
-		return nil, azureErrToS3Err(err)
+		return nil, azureVersionErrToS3Err(err, versionId)
 	}
 
 	var tagcount int32
@@ -510,23 +591,29 @@ func (az *Azure) GetObject(ctx context.Context, input *s3.GetObjectInput) (*s3.G
 		ContentRange:       blobDownloadResponse.ContentRange,
 		Body:               blobDownloadResponse.Body,
 		StorageClass:       types.StorageClassStandard,
+		VersionId:          getVersionIdPtr(blobDownloadResponse.VersionID),
 	}, nil
 }
 
 func (az *Azure) HeadObject(ctx context.Context, input *s3.HeadObjectInput) (*s3.HeadObjectOutput, error) {
+	if err := checkObjectKey(getString(input.Key)); err != nil {
+		return nil, err
+	}
+
 	if input.PartNumber != nil {
 		// querying an object with part number is not supported
 		return nil, s3err.GetAPIError(s3err.ErrNotImplemented)
 	}
 
-	client, err := az.getBlobClient(*input.Bucket, *input.Key)
+	versionId := getString(input.VersionId)
+	client, err := az.getObjectVersionClient(*input.Bucket, *input.Key, versionId)
 	if err != nil {
 		return nil, err
 	}
 
 	resp, err := client.GetProperties(ctx, nil)
 	if err != nil {
-		return nil, azureErrToS3Err(err)
+		return nil, azureVersionErrToS3Err(err, versionId)
 	}
 
 	if resp.ETag != nil && resp.LastModified != nil {
@@ -572,6 +659,7 @@ func (az *Azure) HeadObject(ctx context.Context, input *s3.HeadObjectInput) (*s3
 		LastModified:       resp.LastModified,
 		Metadata:           parseAndFilterAzMetadata(resp.Metadata),
 		StorageClass:       types.StorageClassStandard,
+		VersionId:          getVersionIdPtr(resp.VersionID),
 	}
 
 	status, ok := resp.Metadata[string(keyObjLegalHold)]
@@ -603,8 +691,9 @@ func (az *Azure) HeadObject(ctx context.Context, input *s3.HeadObjectInput) (*s3
 
 func (az *Azure) GetObjectAttributes(ctx context.Context, input *s3.GetObjectAttributesInput) (s3response.GetObjectAttributesResponse, error) {
 	data, err := az.HeadObject(ctx, &s3.HeadObjectInput{
-		Bucket: input.Bucket,
-		Key:    input.Key,
+		Bucket:    input.Bucket,
+		Key:       input.Key,
+		VersionId: input.VersionId,
 	})
 	if err != nil {
 		return s3response.GetObjectAttributesResponse{}, err
@@ -674,6 +763,9 @@ func (az *Azure) ListObjects(ctx context.Context, input *s3.ListObjectsInput) (s
 			if markerFilter != "" && *v.Name <= markerFilter {
 				continue
 			}
+			if isMetaTmpBlob(v.Name) {
+				continue
+			}
 
 			pageObjects = append(pageObjects, s3response.Object{
 				ETag:         backend.GetPtrFromString(convertAzureEtag(v.Properties.ETag)),
@@ -703,6 +795,9 @@ func (az *Azure) ListObjects(ctx context.Context, input *s3.ListObjectsInput) (s
 			if markerFilter != "" && *v.Name <= markerFilter {
 				continue
 			}
+			if isMetaTmpBlob(v.Name) {
+				continue
+			}
 
 			cPrefixes = append(cPrefixes, types.CommonPrefix{
 				Prefix: v.Name,
@@ -775,6 +870,9 @@ func (az *Azure) ListObjectsV2(ctx context.Context, input *s3.ListObjectsV2Input
 		// Convert Azure objects to S3 objects
 		var pageObjects []s3response.Object
 		for _, v := range resp.Segment.BlobItems {
+			if isMetaTmpBlob(v.Name) {
+				continue
+			}
 			pageObjects = append(pageObjects, s3response.Object{
 				ETag:         backend.GetPtrFromString(convertAzureEtag(v.Properties.ETag)),
 				Key:          v.Name,
@@ -821,6 +919,9 @@ func (az *Azure) ListObjectsV2(ctx context.Context, input *s3.ListObjectsV2Input
 
 	var cPrefixes []types.CommonPrefix
 	for _, v := range resp.Segment.BlobPrefixes {
+		if isMetaTmpBlob(v.Name) {
+			continue
+		}
 		cPrefixes = append(cPrefixes, types.CommonPrefix{
 			Prefix: v.Name,
 		})
@@ -849,13 +950,20 @@ func (az *Azure) ListObjectsV2(ctx context.Context, input *s3.ListObjectsV2Input
 }
 
 func (az *Azure) DeleteObject(ctx context.Context, input *s3.DeleteObjectInput) (*s3.DeleteObjectOutput, error) {
+	if err := checkObjectKey(getString(input.Key)); err != nil {
+		return nil, err
+	}
+
 	if input.IfMatch != nil || input.IfMatchLastModifiedTime != nil || input.IfMatchSize != nil {
 		// evaluate the preconditions before deleting the object
 		props, err := az.HeadObject(ctx, &s3.HeadObjectInput{
-			Bucket: input.Bucket,
-			Key:    input.Key,
+			Bucket:    input.Bucket,
+			Key:       input.Key,
+			VersionId: input.VersionId,
 		})
-		if err != nil && !errors.Is(err, s3err.GetAPIError(s3err.ErrNoSuchKey)) {
+		if err != nil && !errors.Is(err, s3err.GetAPIError(s3err.ErrNoSuchKey)) &&
+			!errors.Is(err, s3err.GetAPIError(s3err.ErrNoSuchVersion)) &&
+			!errors.Is(err, s3err.GetAPIError(s3err.ErrMethodNotAllowed)) {
 			// if object doesn't exist, skip preconditions
 			// if unexpected error shows up, return the error
 			return nil, err
@@ -885,26 +993,257 @@ func (az *Azure) DeleteObject(ctx context.Context, input *s3.DeleteObjectInput)
 		}
 	}
 
-	_, err := az.client.DeleteBlob(ctx, *input.Bucket, *input.Key, nil)
+	versionId := getString(input.VersionId)
+	if versionId != "" && versionId != nullVersionId {
+		return az.deleteObjectVersion(ctx, *input.Bucket, *input.Key, versionId)
+	}
+
+	status, err := az.getVersioningStatus(ctx, *input.Bucket)
+	if err != nil {
+		return nil, err
+	}
+
+	if status != types.BucketVersioningStatusEnabled || versionId == nullVersionId {
+		_, err = az.client.DeleteBlob(ctx, *input.Bucket, *input.Key, nil)
+		if err != nil {
+			azerr, ok := err.(*azcore.ResponseError)
+			if ok && azerr.StatusCode == 404 {
+				// if the object does not exist, S3 returns success
+				return &s3.DeleteObjectOutput{}, nil
+			}
+			return &s3.DeleteObjectOutput{}, azureErrToS3Err(err)
+		}
+		az.deleteObjectAcls(ctx, *input.Bucket, *input.Key)
+		return &s3.DeleteObjectOutput{}, nil
+	}
+
+	// With blob versioning, deleting the base blob turns the current
+	// version into a previous version, which is reported as a delete marker
+	client, err := az.getBlobClient(*input.Bucket, *input.Key)
+	if err != nil {
+		return nil, err
+	}
+	props, err := client.GetProperties(ctx, nil)
 	if err != nil {
-		azerr, ok := err.(*azcore.ResponseError)
-		if ok && azerr.StatusCode == 404 {
-			// if the object does not exist, S3 returns success
+		err = azureErrToS3Err(err)
+		if errors.Is(err, s3err.GetAPIError(s3err.ErrNoSuchKey)) {
 			return &s3.DeleteObjectOutput{}, nil
 		}
+		return nil, err
+	}
+
+	_, err = client.Delete(ctx, nil)
+	if err != nil {
+		return nil, azureErrToS3Err(err)
+	}
+
+	out := &s3.DeleteObjectOutput{}
+	if props.VersionID != nil {
+		markerId, err := encodeVersionId(*props.VersionID, true)
+		if err == nil {
+			deleteMarker := true
+			out.DeleteMarker = &deleteMarker
+			out.VersionId = &markerId
+		}
+	}
+
+	return out, nil
+}
+
+// deleteObjectVersion permanently removes the object version.
+// Removing the delete marker or the current version promotes the
+// newest remaining version to the base blob, as the base blob is the
+// only version azure serves without a version id.
+func (az *Azure) deleteObjectVersion(ctx context.Context, bucket, object, versionId string) (*s3.DeleteObjectOutput, error) {
+	azVersionId, deleteMarker, err := decodeVersionId(versionId)
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := az.getBlobClient(bucket, object)
+	if err != nil {
+		return nil, err
+	}
+	versionClient, err := client.WithVersionID(azVersionId)
+	if err != nil {
+		return nil, err
+	}
+
+	out := &s3.DeleteObjectOutput{
+		VersionId: &versionId,
+	}
+	if deleteMarker {
+		out.DeleteMarker = &deleteMarker
+	}
+
+	props, err := versionClient.GetProperties(ctx, nil)
+	if err != nil {
+		err = azureErrToS3Err(err)
+		if errors.Is(err, s3err.GetAPIError(s3err.ErrNoSuchKey)) {
+			// S3 returns success for missing versions
+			return out, nil
+		}
+		return nil, err
+	}
+
+	if deleteMarker {
+		// the delete marker exists as long as the object has
+		// no current version and this is the newest version
+		_, err := client.GetProperties(ctx, nil)
+		if err == nil {
+			return out, nil
+		}
+		err = azureErrToS3Err(err)
+		if !errors.Is(err, s3err.GetAPIError(s3err.ErrNoSuchKey)) {
+			return nil, err
+		}
+		latest, err := az.getLatestBlobVersion(ctx, bucket, object)
+		if err != nil {
+			return nil, err
+		}
+		if latest != azVersionId {
+			return out, nil
+		}
+
+		return out, az.promoteBlobVersion(ctx, client, versionClient)
+	}
+
+	if props.IsCurrentVersion != nil && *props.IsCurrentVersion {
+		// the current version can't be deleted by version id,
+		// deleting the base blob first turns it into a previous version
+		_, err = client.Delete(ctx, nil)
+		if err != nil {
+			return nil, azureErrToS3Err(err)
+		}
+		_, err = versionClient.Delete(ctx, nil)
+		if err != nil {
+			return nil, azureErrToS3Err(err)
+		}
+
+		latest, err := az.getLatestBlobVersion(ctx, bucket, object)
+		if err != nil {
+			return nil, err
+		}
+		if latest == "" {
+			return out, nil
+		}
+		latestClient, err := client.WithVersionID(latest)
+		if err != nil {
+			return nil, err
+		}
+		return out, az.promoteBlobVersion(ctx, client, latestClient)
+	}
+
+	_, err = versionClient.Delete(ctx, nil)
+	if err != nil {
+		return nil, azureErrToS3Err(err)
+	}
+
+	return out, nil
+}
+
+// promoteBlobVersion restores the blob version as the base blob.
+// Azure can only restore a version by copying it over the base blob,
+// so the restored blob is listed with the new version id of the copy.
+// The copy is awaited and the promoted version removed afterwards to
+// avoid listing the version twice, copies that don't complete in time
+// are aborted.
+func (az *Azure) promoteBlobVersion(ctx context.Context, client, versionClient *blob.Client) error {
+	ctx, cancel := context.WithTimeout(ctx, promoteCopyTimeout)
+	defer cancel()
+
+	resp, err := client.StartCopyFromURL(ctx, versionClient.URL(), nil)
+	if err != nil {
+		return azureErrToS3Err(err)
+	}
+
+	status := resp.CopyStatus
+	interval := promoteCopyMinInterval
+	for status == nil || *status == blob.CopyStatusTypePending {
+		select {
+		case <-ctx.Done():
+			if resp.CopyID != nil {
+				client.AbortCopyFromURL(context.WithoutCancel(ctx), *resp.CopyID, nil)
+			}
+			return fmt.Errorf("promote blob version: %w", ctx.Err())
+		case <-time.After(interval):
+		}
+		interval = min(interval*2, promoteCopyMaxInterval)
+
+		props, err := client.GetProperties(ctx, nil)
+		if err != nil {
+			return azureErrToS3Err(err)
+		}
+		if props.CopyStatus == nil || getString(props.CopyID) != getString(resp.CopyID) {
+			return fmt.Errorf("promote blob version: copy %v was replaced", getString(resp.CopyID))
+		}
+		status = props.CopyStatus
+	}
+
+	if *status != blob.CopyStatusTypeSuccess {
+		return fmt.Errorf("promote blob version: copy %v", *status)
+	}
+
+	_, err = versionClient.Delete(ctx, nil)
+	if err != nil {
+		return azureErrToS3Err(err)
+	}
+
+	return nil
+}
+
+// getLatestBlobVersion returns the azure version id of the newest
+// version of the blob, or an empty string if the blob has no versions
+func (az *Azure) getLatestBlobVersion(ctx context.Context, bucket, object string) (string, error) {
+	client, err := az.getContainerClient(bucket)
+	if err != nil {
+		return "", err
+	}
+
+	pager := client.NewListBlobsFlatPager(&container.ListBlobsFlatOptions{
+		Include: container.ListBlobsInclude{Versions: true},
+		Prefix:  &object,
+	})
+
+	var latest string
+	for pager.More() {
+		resp, err := pager.NextPage(ctx)
+		if err != nil {
+			return "", azureErrToS3Err(err)
+		}
+		for _, v := range resp.Segment.BlobItems {
+			if getString(v.Name) != object {
+				continue
+			}
+			// the version ids are timestamps of the same format,
+			// so they sort lexically in chronological order
+			if vid := getString(v.VersionID); vid > latest {
+				latest = vid
+			}
+		}
 	}
-	return &s3.DeleteObjectOutput{}, azureErrToS3Err(err)
+
+	return latest, nil
 }
 
 func (az *Azure) DeleteObjects(ctx context.Context, input *s3.DeleteObjectsInput) (s3response.DeleteResult, error) {
 	delResult, errs := []types.DeletedObject{}, []types.Error{}
 	for _, obj := range input.Delete.Objects {
-		_, err := az.DeleteObject(ctx, &s3.DeleteObjectInput{
-			Bucket: input.Bucket,
-			Key:    obj.Key,
+		res, err := az.DeleteObject(ctx, &s3.DeleteObjectInput{
+			Bucket:    input.Bucket,
+			Key:       obj.Key,
+			VersionId: obj.VersionId,
 		})
 		if err == nil {
-			delResult = append(delResult, types.DeletedObject{Key: obj.Key})
+			deleted := types.DeletedObject{
+				Key:          obj.Key,
+				VersionId:    obj.VersionId,
+				DeleteMarker: res.DeleteMarker,
+			}
+			if getString(obj.VersionId) == "" && res.DeleteMarker != nil && *res.DeleteMarker {
+				deleted.DeleteMarkerVersionId = res.VersionId
+			}
+			delResult = append(delResult, deleted)
 		} else {
 			serr, ok := err.(s3err.APIError)
 			if ok {
@@ -930,6 +1269,10 @@ func (az *Azure) DeleteObjects(ctx context.Context, input *s3.DeleteObjectsInput
 }
 
 func (az *Azure) CopyObject(ctx context.Context, input s3response.CopyObjectInput) (s3response.CopyObjectOutput, error) {
+	if err := checkObjectKey(getString(input.Key)); err != nil {
+		return s3response.CopyObjectOutput{}, err
+	}
+
 	dstClient, err := az.getBlobClient(*input.Bucket, *input.Key)
 	if err != nil {
 		return s3response.CopyObjectOutput{}, err
@@ -939,6 +1282,9 @@ func (az *Azure) CopyObject(ctx context.Context, input s3response.CopyObjectInpu
 	if err != nil {
 		return s3response.CopyObjectOutput{}, err
 	}
+	if err := checkObjectKey(srcObj); err != nil {
+		return s3response.CopyObjectOutput{}, err
+	}
 
 	if !areNils(input.CopySourceIfMatch, input.CopySourceIfNoneMatch) || !areNils(input.CopySourceIfModifiedSince, input.CopySourceIfUnmodifiedSince) {
 		_, err = az.HeadObject(ctx, &s3.HeadObjectInput{
@@ -981,7 +1327,7 @@ func (az *Azure) CopyObject(ctx context.Context, input s3response.CopyObjectInpu
 			meta[string(keyExpires)] = *input.Expires
 		}
 		// Set object metadata
-		_, err = dstClient.SetMetadata(ctx, parseMetadata(meta), nil)
+		_, err = dstClient.SetMetadata(ctx, setObjectGeneration(parseMetadata(meta)), nil)
 		if err != nil {
 			return s3response.CopyObjectOutput{}, azureErrToS3Err(err)
 		}
@@ -1108,6 +1454,10 @@ func (az *Azure) CopyObject(ctx context.Context, input s3response.CopyObjectInpu
 }
 
 func (az *Azure) PutObjectTagging(ctx context.Context, bucket, object, _ string, tags map[string]string) error {
+	if err := checkObjectKey(object); err != nil {
+		return err
+	}
+
 	client, err := az.getBlobClient(bucket, object)
 	if err != nil {
 		return err
@@ -1122,6 +1472,10 @@ func (az *Azure) PutObjectTagging(ctx context.Context, bucket, object, _ string,
 }
 
 func (az *Azure) GetObjectTagging(ctx context.Context, bucket, object, _ string) (map[string]string, error) {
+	if err := checkObjectKey(object); err != nil {
+		return nil, err
+	}
+
 	client, err := az.getBlobClient(bucket, object)
 	if err != nil {
 		return nil, err
@@ -1136,6 +1490,10 @@ func (az *Azure) GetObjectTagging(ctx context.Context, bucket, object, _ string)
 }
 
 func (az *Azure) DeleteObjectTagging(ctx context.Context, bucket, object, _ string) error {
+	if err := checkObjectKey(object); err != nil {
+		return err
+	}
+
 	client, err := az.getBlobClient(bucket, object)
 	if err != nil {
 		return err
@@ -1150,6 +1508,10 @@ func (az *Azure) DeleteObjectTagging(ctx context.Context, bucket, object, _ stri
 }
 
 func (az *Azure) CreateMultipartUpload(ctx context.Context, input s3response.CreateMultipartUploadInput) (s3response.InitiateMultipartUploadResult, error) {
+	if err := checkObjectKey(getString(input.Key)); err != nil {
+		return s3response.InitiateMultipartUploadResult{}, err
+	}
+
 	if input.ObjectLockLegalHoldStatus != "" || input.ObjectLockMode != "" {
 		bucketLock, err := az.getContainerMetaData(ctx, *input.Bucket, string(keyBucketLock))
 		if err != nil {
@@ -1174,7 +1536,7 @@ func (az *Azure) CreateMultipartUpload(ctx context.Context, input s3response.Cre
 		}
 	}
 
-	meta := parseMetadata(input.Metadata)
+	meta := setObjectGeneration(parseMetadata(input.Metadata))
 	meta[string(onameAttr)] = input.Key
 
 	if getString(input.Expires) != "" {
@@ -1738,6 +2100,29 @@ func (az *Azure) DeleteBucketCors(ctx context.Context, bucket string) error {
 	return az.PutBucketCors(ctx, bucket, nil)
 }
 
+func (az *Azure) PutPublicAccessBlock(ctx context.Context, bucket string, config []byte) error {
+	if config == nil {
+		return az.deleteContainerMetaData(ctx, bucket, string(keyPublicAccessBlock))
+	}
+
+	return az.setContainerMetaData(ctx, bucket, string(keyPublicAccessBlock), config)
+}
+
+func (az *Azure) GetPublicAccessBlock(ctx context.Context, bucket string) ([]byte, error) {
+	p, err := az.getContainerMetaData(ctx, bucket, string(keyPublicAccessBlock))
+	if err != nil {
+		return nil, err
+	}
+	if len(p) == 0 {
+		return nil, s3err.GetAPIError(s3err.ErrNoSuchPublicAccessBlockConfiguration)
+	}
+	return p, nil
+}
+
+func (az *Azure) DeletePublicAccessBlock(ctx context.Context, bucket string) error {
+	return az.PutPublicAccessBlock(ctx, bucket, nil)
+}
+
 func (az *Azure) PutObjectLockConfiguration(ctx context.Context, bucket string, config []byte) error {
 	return az.setContainerMetaData(ctx, bucket, string(keyBucketLock), config)
 }
@@ -1756,6 +2141,10 @@ func (az *Azure) GetObjectLockConfiguration(ctx context.Context, bucket string)
 }
 
 func (az *Azure) PutObjectRetention(ctx context.Context, bucket, object, versionId string, retention []byte) error {
+	if err := checkObjectKey(object); err != nil {
+		return err
+	}
+
 	err := az.isBucketObjectLockEnabled(ctx, bucket)
 	if err != nil {
 		return err
@@ -1812,6 +2201,10 @@ func (az *Azure) GetObjectRetention(ctx context.Context, bucket, object, version
 }
 
 func (az *Azure) PutObjectLegalHold(ctx context.Context, bucket, object, versionId string, status bool) error {
+	if err := checkObjectKey(object); err != nil {
+		return err
+	}
+
 	err := az.isBucketObjectLockEnabled(ctx, bucket)
 	if err != nil {
 		return err
@@ -1876,42 +2269,526 @@ func (az *Azure) GetObjectLegalHold(ctx context.Context, bucket, object, version
 	return &status, nil
 }
 
-func (az *Azure) ChangeBucketOwner(ctx context.Context, bucket, owner string) error {
-	return auth.UpdateBucketACLOwner(ctx, az, bucket, owner)
-}
-
-// The action actually returns the containers owned by the user, who initialized the gateway
-// TODO: Not sure if there's a way to list all the containers and owners?
-func (az *Azure) ListBucketsAndOwners(ctx context.Context) (buckets []s3response.Bucket, err error) {
-	opts := &service.ListContainersOptions{
-		Include: service.ListContainersInclude{Metadata: true},
-	}
-	pager := az.client.NewListContainersPager(opts)
-
-	for pager.More() {
-		resp, err := pager.NextPage(ctx)
-		if err != nil {
-			return buckets, azureErrToS3Err(err)
+// PutBucketVersioning stores the bucket versioning status in the container
+// metadata. Blob versioning is a storage account setting in azure, it has
+// to be enabled on the storage account for the object versions to be kept.
+func (az *Azure) PutBucketVersioning(ctx context.Context, bucket string, status types.BucketVersioningStatus) error {
+	if status == types.BucketVersioningStatusSuspended {
+		err := az.isBucketObjectLockEnabled(ctx, bucket)
+		if err == nil {
+			return s3err.GetAPIError(s3err.ErrSuspendedVersioningNotAllowed)
 		}
-		for _, v := range resp.ContainerItems {
-			acl, err := getAclFromMetadata(v.Metadata, keyAclLower)
-			if err != nil {
-				return buckets, err
-			}
-
-			buckets = append(buckets, s3response.Bucket{
-				Name:  *v.Name,
-				Owner: acl.Owner,
-			})
+		if !errors.Is(err, s3err.GetAPIError(s3err.ErrMissingObjectLockConfiguration)) {
+			return err
 		}
 	}
-	return buckets, nil
-}
 
-func (az *Azure) isBucketObjectLockEnabled(ctx context.Context, bucket string) error {
-	cfg, err := az.getContainerMetaData(ctx, bucket, string(keyBucketLock))
+	err := az.setContainerMetaData(ctx, bucket, string(keyVersioning), []byte(status))
 	if err != nil {
-		return azureErrToS3Err(err)
+		return err
+	}
+
+	az.versioning.store(bucket, status)
+	return nil
+}
+
+func (az *Azure) GetBucketVersioning(ctx context.Context, bucket string) (s3response.GetBucketVersioningOutput, error) {
+	status, err := az.getVersioningStatus(ctx, bucket)
+	if err != nil {
+		return s3response.GetBucketVersioningOutput{}, err
+	}
+
+	if status == "" {
+		return s3response.GetBucketVersioningOutput{}, nil
+	}
+
+	return s3response.GetBucketVersioningOutput{
+		Status: &status,
+	}, nil
+}
+
+// getVersioningStatus returns the bucket versioning status, the status
+// is cached for a short time as it is checked on every object write
+func (az *Azure) getVersioningStatus(ctx context.Context, bucket string) (types.BucketVersioningStatus, error) {
+	if status, ok := az.versioning.load(bucket); ok {
+		return status, nil
+	}
+
+	data, err := az.getContainerMetaData(ctx, bucket, string(keyVersioning))
+	if err != nil {
+		return "", err
+	}
+
+	status := types.BucketVersioningStatus(data)
+	az.versioning.store(bucket, status)
+	return status, nil
+}
+
+func (az *Azure) ListObjectVersions(ctx context.Context, input *s3.ListObjectVersionsInput) (s3response.ListVersionsResult, error) {
+	var prefix, delim, keyMarker, versionIdMarker string
+	if input.Prefix != nil {
+		prefix = *input.Prefix
+	}
+	if input.Delimiter != nil {
+		delim = *input.Delimiter
+	}
+	if input.KeyMarker != nil {
+		keyMarker = *input.KeyMarker
+	}
+	if input.VersionIdMarker != nil {
+		versionIdMarker = *input.VersionIdMarker
+	}
+	var maxKeys int32 = defaultListingMaxKeys
+	if input.MaxKeys != nil {
+		maxKeys = *input.MaxKeys
+	}
+
+	// Retrieve the bucket acl to get the bucket owner
+	// All the objects in the bucket are owner by the bucket owner
+	aclBytes, err := az.getContainerMetaData(ctx, *input.Bucket, string(keyAclCapital))
+	if err != nil {
+		return s3response.ListVersionsResult{}, azureErrToS3Err(err)
+	}
+
+	acl, err := auth.ParseACL(aclBytes)
+	if err != nil {
+		return s3response.ListVersionsResult{}, err
+	}
+	owner := &types.Owner{
+		ID: &acl.Owner,
+	}
+
+	client, err := az.getContainerClient(*input.Bucket)
+	if err != nil {
+		return s3response.ListVersionsResult{}, err
+	}
+
+	pager := client.NewListBlobsHierarchyPager(delim, &container.ListBlobsHierarchyOptions{
+		Include: container.ListBlobsInclude{Versions: true},
+		Prefix:  &prefix,
+	})
+
+	var versions []s3response.ObjectVersion
+	var delMarkers []types.DeleteMarkerEntry
+	var cPrefixes []types.CommonPrefix
+	var count int32
+	var isTruncated bool
+	var nextKeyMarker, nextVersionIdMarker string
+
+	// the versions of a blob are listed next to each other, and are
+	// added once all the versions of the blob have been collected
+	var group []*container.BlobItem
+	addGroup := func() {
+		if len(group) == 0 {
+			return
+		}
+		key := getString(group[0].Name)
+		entries := blobVersionEntries(group, owner)
+		group = nil
+
+		if key < keyMarker || (key == keyMarker && versionIdMarker == "") {
+			return
+		}
+		skip := key == keyMarker
+		for _, entry := range entries {
+			if skip {
+				skip = entry.versionId != versionIdMarker
+				continue
+			}
+			if count >= maxKeys {
+				isTruncated = true
+				return
+			}
+			if entry.marker != nil {
+				delMarkers = append(delMarkers, *entry.marker)
+			} else {
+				versions = append(versions, *entry.version)
+			}
+			count++
+			nextKeyMarker, nextVersionIdMarker = key, entry.versionId
+		}
+	}
+
+	for pager.More() && !isTruncated {
+		resp, err := pager.NextPage(ctx)
+		if err != nil {
+			return s3response.ListVersionsResult{}, azureErrToS3Err(err)
+		}
+
+		for _, v := range resp.Segment.BlobItems {
+			if isMetaTmpBlob(v.Name) {
+				continue
+			}
+			if len(group) > 0 && getString(group[0].Name) != getString(v.Name) {
+				addGroup()
+				if isTruncated {
+					break
+				}
+			}
+			group = append(group, v)
+		}
+
+		for _, v := range resp.Segment.BlobPrefixes {
+			// Skip prefixes that come before or equal to marker
+			if keyMarker != "" && getString(v.Name) <= keyMarker {
+				continue
+			}
+			if isMetaTmpBlob(v.Name) {
+				continue
+			}
+
+			cPrefixes = append(cPrefixes, types.CommonPrefix{
+				Prefix: v.Name,
+			})
+		}
+	}
+	if !isTruncated {
+		addGroup()
+	}
+
+	result := s3response.ListVersionsResult{
+		CommonPrefixes:  cPrefixes,
+		DeleteMarkers:   delMarkers,
+		Delimiter:       &delim,
+		IsTruncated:     &isTruncated,
+		KeyMarker:       &keyMarker,
+		MaxKeys:         &maxKeys,
+		Name:            input.Bucket,
+		Prefix:          &prefix,
+		VersionIdMarker: &versionIdMarker,
+		Versions:        versions,
+	}
+	if isTruncated {
+		result.NextKeyMarker = &nextKeyMarker
+		result.NextVersionIdMarker = &nextVersionIdMarker
+	}
+
+	return result, nil
+}
+
+type versionEntry struct {
+	versionId string
+	version   *s3response.ObjectVersion
+	marker    *types.DeleteMarkerEntry
+}
+
+// blobVersionEntries converts the versions of a single blob to the
+// listing entries, newest first. Blobs without a current version have
+// been deleted and get a delete marker as the latest version.
+func blobVersionEntries(items []*container.BlobItem, owner *types.Owner) []versionEntry {
+	slices.SortFunc(items, func(a, b *container.BlobItem) int {
+		return strings.Compare(getString(b.VersionID), getString(a.VersionID))
+	})
+
+	hasCurrent := false
+	for _, v := range items {
+		if getString(v.VersionID) == "" || (v.IsCurrentVersion != nil && *v.IsCurrentVersion) {
+			hasCurrent = true
+			break
+		}
+	}
+
+	entries := make([]versionEntry, 0, len(items)+1)
+	if !hasCurrent {
+		markerId, err := encodeVersionId(getString(items[0].VersionID), true)
+		if err == nil {
+			isLatest := true
+			entries = append(entries, versionEntry{
+				versionId: markerId,
+				marker: &types.DeleteMarkerEntry{
+					IsLatest:     &isLatest,
+					Key:          items[0].Name,
+					LastModified: items[0].Properties.LastModified,
+					Owner:        owner,
+					VersionId:    &markerId,
+				},
+			})
+		}
+	}
+
+	for _, v := range items {
+		versionId := nullVersionId
+		isLatest := true
+		if getString(v.VersionID) != "" {
+			id, err := encodeVersionId(*v.VersionID, false)
+			if err != nil {
+				continue
+			}
+			versionId = id
+			isLatest = v.IsCurrentVersion != nil && *v.IsCurrentVersion
+		}
+
+		entries = append(entries, versionEntry{
+			versionId: versionId,
+			version: &s3response.ObjectVersion{
+				ETag:         backend.GetPtrFromString(convertAzureEtag(v.Properties.ETag)),
+				IsLatest:     &isLatest,
+				Key:          v.Name,
+				LastModified: v.Properties.LastModified,
+				Owner:        owner,
+				Size:         v.Properties.ContentLength,
+				StorageClass: types.ObjectVersionStorageClassStandard,
+				VersionId:    &versionId,
+			},
+		})
+	}
+
+	return entries
+}
+
+func (az *Azure) PutObjectAcl(ctx context.Context, input *s3.PutObjectAclInput) error {
+	if err := checkObjectKey(getString(input.Key)); err != nil {
+		return err
+	}
+
+	client, err := az.getObjectVersionClient(*input.Bucket, *input.Key, getString(input.VersionId))
+	if err != nil {
+		return err
+	}
+
+	props, err := client.GetProperties(ctx, nil)
+	if err != nil {
+		return azureVersionErrToS3Err(err, getString(input.VersionId))
+	}
+
+	// The objects are owned by the bucket owner
+	aclBytes, err := az.getContainerMetaData(ctx, *input.Bucket, string(keyAclCapital))
+	if err != nil {
+		return err
+	}
+	bucketAcl, err := auth.ParseACL(aclBytes)
+	if err != nil {
+		return err
+	}
+
+	acl, err := buildObjectAcl(input, bucketAcl.Owner)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(acl)
+	if err != nil {
+		return fmt.Errorf("marshal object acl: %w", err)
+	}
+
+	// The acl is kept in a separate blob, setting the blob metadata
+	// would create a new version of the object
+	_, err = az.client.UploadBuffer(ctx, *input.Bucket,
+		getObjectAclPath(*input.Key, props.Metadata, props.ETag), data, nil)
+	if err != nil {
+		return azureErrToS3Err(err)
+	}
+
+	return nil
+}
+
+func (az *Azure) GetObjectAcl(ctx context.Context, input *s3.GetObjectAclInput) (*s3.GetObjectAclOutput, error) {
+	if err := checkObjectKey(getString(input.Key)); err != nil {
+		return nil, err
+	}
+
+	client, err := az.getObjectVersionClient(*input.Bucket, *input.Key, getString(input.VersionId))
+	if err != nil {
+		return nil, err
+	}
+
+	props, err := client.GetProperties(ctx, nil)
+	if err != nil {
+		return nil, azureVersionErrToS3Err(err, getString(input.VersionId))
+	}
+
+	acl, err := az.getObjectAcl(ctx, *input.Bucket, *input.Key, props.Metadata, props.ETag)
+	if err != nil {
+		return nil, err
+	}
+
+	if acl.Owner == "" {
+		// Objects without an acl grant full control to the bucket owner
+		aclBytes, err := az.getContainerMetaData(ctx, *input.Bucket, string(keyAclCapital))
+		if err != nil {
+			return nil, err
+		}
+		bucketAcl, err := auth.ParseACL(aclBytes)
+		if err != nil {
+			return nil, err
+		}
+		acl.Owner = bucketAcl.Owner
+		acl.Grantees = []auth.Grantee{
+			{
+				Permission: auth.PermissionFullControl,
+				Access:     bucketAcl.Owner,
+				Type:       types.TypeCanonicalUser,
+			},
+		}
+	}
+
+	grants := make([]types.Grant, 0, len(acl.Grantees))
+	for _, grt := range acl.Grantees {
+		grantee := &types.Grantee{
+			Type: grt.Type,
+		}
+		if grt.Type == types.TypeGroup {
+			grantee.URI = backend.GetPtrFromString(auth.GroupURI(grt.Access))
+		} else {
+			grantee.ID = backend.GetPtrFromString(grt.Access)
+		}
+		grants = append(grants, types.Grant{
+			Grantee:    grantee,
+			Permission: types.Permission(grt.Permission),
+		})
+	}
+
+	return &s3.GetObjectAclOutput{
+		Owner: &types.Owner{
+			ID: &acl.Owner,
+		},
+		Grants: grants,
+	}, nil
+}
+
+// buildObjectAcl creates the object acl from the canned acl, the grant
+// headers or the access control policy of the request
+func buildObjectAcl(input *s3.PutObjectAclInput, owner string) (auth.ACL, error) {
+	acl := auth.ACL{
+		Owner: owner,
+		Grantees: []auth.Grantee{
+			{
+				Permission: auth.PermissionFullControl,
+				Access:     owner,
+				Type:       types.TypeCanonicalUser,
+			},
+		},
+	}
+
+	if input.ACL != "" {
+		switch input.ACL {
+		case types.ObjectCannedACLPrivate, types.ObjectCannedACLBucketOwnerFullControl:
+		case types.ObjectCannedACLPublicRead:
+			acl.Grantees = append(acl.Grantees, auth.Grantee{
+				Permission: auth.PermissionRead,
+				Access:     "all-users",
+				Type:       types.TypeGroup,
+			})
+		case types.ObjectCannedACLPublicReadWrite:
+			acl.Grantees = append(acl.Grantees,
+				auth.Grantee{
+					Permission: auth.PermissionRead,
+					Access:     "all-users",
+					Type:       types.TypeGroup,
+				},
+				auth.Grantee{
+					Permission: auth.PermissionWrite,
+					Access:     "all-users",
+					Type:       types.TypeGroup,
+				})
+		case types.ObjectCannedACLAuthenticatedRead:
+			acl.Grantees = append(acl.Grantees, auth.Grantee{
+				Permission: auth.PermissionRead,
+				Access:     "authenticated-users",
+				Type:       types.TypeGroup,
+			})
+		default:
+			return auth.ACL{}, s3err.GetAPIError(s3err.ErrInvalidArgument)
+		}
+		return acl, nil
+	}
+
+	grantHeaders := []struct {
+		grant      *string
+		permission auth.Permission
+	}{
+		{input.GrantFullControl, auth.PermissionFullControl},
+		{input.GrantRead, auth.PermissionRead},
+		{input.GrantReadACP, auth.PermissionReadAcp},
+		{input.GrantWrite, auth.PermissionWrite},
+		{input.GrantWriteACP, auth.PermissionWriteAcp},
+	}
+	hasGrants := false
+	for _, gh := range grantHeaders {
+		if getString(gh.grant) == "" {
+			continue
+		}
+		hasGrants = true
+		grantees, err := auth.ParseGrantHeader(*gh.grant, gh.permission)
+		if err != nil {
+			return auth.ACL{}, err
+		}
+		for _, grt := range grantees {
+			// the email grantees are resolved by the gateway
+			if grt.Type == types.TypeAmazonCustomerByEmail {
+				return auth.ACL{}, s3err.GetAPIError(s3err.ErrUnresolvableGrantByEmailAddress)
+			}
+		}
+		acl.Grantees = append(acl.Grantees, grantees...)
+	}
+	if hasGrants || input.AccessControlPolicy == nil {
+		return acl, nil
+	}
+
+	for _, grt := range input.AccessControlPolicy.Grants {
+		if grt.Grantee == nil || grt.Permission == "" {
+			return auth.ACL{}, s3err.GetAPIError(s3err.ErrMalformedACL)
+		}
+		grantee := auth.Grantee{
+			Permission: auth.Permission(grt.Permission),
+			Type:       grt.Grantee.Type,
+		}
+		switch grt.Grantee.Type {
+		case types.TypeGroup:
+			access, err := auth.GroupAccess(getString(grt.Grantee.URI))
+			if err != nil {
+				return auth.ACL{}, err
+			}
+			grantee.Access = access
+		default:
+			grantee.Type = types.TypeCanonicalUser
+			grantee.Access = getString(grt.Grantee.ID)
+		}
+		if grantee.Access == "" {
+			return auth.ACL{}, s3err.GetAPIError(s3err.ErrMalformedACL)
+		}
+		acl.Grantees = append(acl.Grantees, grantee)
+	}
+
+	return acl, nil
+}
+
+func (az *Azure) ChangeBucketOwner(ctx context.Context, bucket, owner string) error {
+	return auth.UpdateBucketACLOwner(ctx, az, bucket, owner)
+}
+
+// The action actually returns the containers owned by the user, who initialized the gateway
+// TODO: Not sure if there's a way to list all the containers and owners?
+func (az *Azure) ListBucketsAndOwners(ctx context.Context) (buckets []s3response.Bucket, err error) {
+	opts := &service.ListContainersOptions{
+		Include: service.ListContainersInclude{Metadata: true},
+	}
+	pager := az.client.NewListContainersPager(opts)
+
+	for pager.More() {
+		resp, err := pager.NextPage(ctx)
+		if err != nil {
+			return buckets, azureErrToS3Err(err)
+		}
+		for _, v := range resp.ContainerItems {
+			acl, err := getAclFromMetadata(v.Metadata, keyAclLower)
+			if err != nil {
+				return buckets, err
+			}
+
+			buckets = append(buckets, s3response.Bucket{
+				Name:  *v.Name,
+				Owner: acl.Owner,
+			})
+		}
+	}
+	return buckets, nil
+}
+
+func (az *Azure) isBucketObjectLockEnabled(ctx context.Context, bucket string) error {
+	cfg, err := az.getContainerMetaData(ctx, bucket, string(keyBucketLock))
+	if err != nil {
+		return azureErrToS3Err(err)
 	}
 
 	if len(cfg) == 0 {
@@ -1975,6 +2852,29 @@ func (az *Azure) getBlockBlobClient(cntr, blb string) (*blockblob.Client, error)
 	return blockblob.NewClientWithSharedKeyCredential(blobURL, az.sharedkeyCreds, nil)
 }
 
+// getObjectVersionClient returns the blob client of the object version,
+// the empty and "null" version ids refer to the base blob
+func (az *Azure) getObjectVersionClient(cntr, blb, versionId string) (*blob.Client, error) {
+	client, err := az.getBlobClient(cntr, blb)
+	if err != nil {
+		return nil, err
+	}
+	if versionId == "" || versionId == nullVersionId {
+		return client, nil
+	}
+
+	azVersionId, deleteMarker, err := decodeVersionId(versionId)
+	if err != nil {
+		return nil, err
+	}
+	if deleteMarker {
+		// delete markers have no data, S3 rejects the requests on them
+		return nil, s3err.GetAPIError(s3err.ErrMethodNotAllowed)
+	}
+
+	return client.WithVersionID(azVersionId)
+}
+
 func parseMetadata(m map[string]string) map[string]*string {
 	if m == nil {
 		return nil
@@ -2200,6 +3100,99 @@ func getAclFromMetadata(meta map[string]*string, key key) (*auth.ACL, error) {
 	return &acl, nil
 }
 
+// setObjectGeneration stamps the new object with a unique generation,
+// the object acls are stored per object generation
+func setObjectGeneration(meta map[string]*string) map[string]*string {
+	if meta == nil {
+		meta = map[string]*string{}
+	}
+	meta[string(keyObjGeneration)] = backend.GetPtrFromString(uuid.NewString())
+	return meta
+}
+
+// getObjectAclPath returns the path of the acl blob of the object
+// generation. Objects created before the generations were introduced
+// use their etag instead.
+func getObjectAclPath(obj string, meta map[string]*string, etag *azcore.ETag) string {
+	gen := getString(meta[string(keyObjGeneration)])
+	if gen == "" && etag != nil {
+		gen = "etag-" + strings.Trim(string(*etag), `"`)
+	}
+	objNameSum := sha256.Sum256([]byte(obj))
+	return filepath.Join(string(metaTmpObjAclPrefix), fmt.Sprintf("%x", objNameSum), gen)
+}
+
+// isMetaTmpBlob reports if the blob or the common prefix is in the
+// gateway namespace
+func isMetaTmpBlob(name *string) bool {
+	return strings.HasPrefix(getString(name), metaTmpPrefix)
+}
+
+// checkObjectKey rejects the object keys in the gateway namespace, so
+// the multipart and acl blobs can't be read, forged or removed with the
+// object requests
+func checkObjectKey(key string) error {
+	if strings.HasPrefix(key, metaTmpPrefix) {
+		return s3err.GetAPIError(s3err.ErrAccessDenied)
+	}
+	return nil
+}
+
+// isObjectAclBlob reports if the blob stores an object acl
+func isObjectAclBlob(name *string) bool {
+	return strings.HasPrefix(getString(name), string(metaTmpObjAclPrefix)+"/")
+}
+
+// getObjectAcl reads the acl of the object generation, falling back
+// to the acl stored in the blob metadata by the older gateways
+func (az *Azure) getObjectAcl(ctx context.Context, bucket, obj string, meta map[string]*string, etag *azcore.ETag) (*auth.ACL, error) {
+	resp, err := az.client.DownloadStream(ctx, bucket, getObjectAclPath(obj, meta, etag), nil)
+	if err != nil {
+		err = azureErrToS3Err(err)
+		if errors.Is(err, s3err.GetAPIError(s3err.ErrNoSuchKey)) {
+			return getAclFromMetadata(meta, keyObjAcl)
+		}
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("read object acl: %w", err)
+	}
+
+	acl, err := auth.ParseACL(data)
+	if err != nil {
+		return nil, err
+	}
+
+	return &acl, nil
+}
+
+// deleteObjectAcls removes the acls of all the object generations,
+// the acls are only looked up by generation, so failures are ignored
+func (az *Azure) deleteObjectAcls(ctx context.Context, bucket, obj string) {
+	client, err := az.getContainerClient(bucket)
+	if err != nil {
+		return
+	}
+
+	objNameSum := sha256.Sum256([]byte(obj))
+	prefix := filepath.Join(string(metaTmpObjAclPrefix), fmt.Sprintf("%x", objNameSum)) + "/"
+	pager := client.NewListBlobsFlatPager(&container.ListBlobsFlatOptions{
+		Prefix: &prefix,
+	})
+	for pager.More() {
+		resp, err := pager.NextPage(ctx)
+		if err != nil {
+			return
+		}
+		for _, v := range resp.Segment.BlobItems {
+			az.client.DeleteBlob(ctx, bucket, getString(v.Name), nil)
+		}
+	}
+}
+
 func createMetaTmpPath(obj, uploadId string) string {
 	objNameSum := sha256.Sum256([]byte(obj))
 	return filepath.Join(string(metaTmpMultipartPrefix), uploadId, fmt.Sprintf("%x", objNameSum))
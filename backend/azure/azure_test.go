@@ -0,0 +1,161 @@
+// Copyright 2026 Versity Software
+// This file is licensed under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package azure
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/versity/versitygw/s3err"
+	"github.com/versity/versitygw/s3response"
+)
+
+func TestIsMetaTmpBlob(t *testing.T) {
+	tests := []struct {
+		name string
+		want bool
+	}{
+		{".sgwtmp/", true},
+		{".sgwtmp/objacl/", true},
+		{".sgwtmp/objacl/abc/gen", true},
+		{".sgwtmp/multipart/upload/abc", true},
+		{".sgwtmp", false},
+		{".sgwtmpx/", false},
+		{"dir/.sgwtmp/", false},
+		{"obj", false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		if got := isMetaTmpBlob(&tt.name); got != tt.want {
+			t.Errorf("%q: expected %v, got %v", tt.name, tt.want, got)
+		}
+	}
+	if isMetaTmpBlob(nil) {
+		t.Errorf("expected a nil name not in the gateway namespace")
+	}
+}
+
+func TestCheckObjectKey(t *testing.T) {
+	denied := s3err.GetAPIError(s3err.ErrAccessDenied)
+	for _, key := range []string{".sgwtmp/", ".sgwtmp/objacl/x/etag-1", ".sgwtmp/multipart/id/x"} {
+		if err := checkObjectKey(key); !errors.Is(err, denied) {
+			t.Errorf("%q: expected access denied, got %v", key, err)
+		}
+	}
+	for _, key := range []string{"obj", ".sgwtmp", "dir/.sgwtmp/obj", ".sgwtmpobj"} {
+		if err := checkObjectKey(key); err != nil {
+			t.Errorf("%q: expected the key allowed, got %v", key, err)
+		}
+	}
+}
+
+func TestGetObjectAclPath(t *testing.T) {
+	sum := fmt.Sprintf("%x", sha256.Sum256([]byte("dir/obj")))
+	gen := "5f0c7b4e-7b1e-4c55-8a3e-1f7c8a2b9d10"
+	etag := azcore.ETag(`"0x8DC1234ABCD"`)
+
+	got := getObjectAclPath("dir/obj", map[string]*string{
+		string(keyObjGeneration): &gen,
+	}, &etag)
+	if want := ".sgwtmp/objacl/" + sum + "/" + gen; got != want {
+		t.Errorf("expected the generation path %v, got %v", want, got)
+	}
+
+	// the objects without a generation use the etag
+	got = getObjectAclPath("dir/obj", nil, &etag)
+	if want := ".sgwtmp/objacl/" + sum + "/etag-0x8DC1234ABCD"; got != want {
+		t.Errorf("expected the etag path %v, got %v", want, got)
+	}
+
+	if !isObjectAclBlob(&got) || !isMetaTmpBlob(&got) {
+		t.Errorf("expected %v to be a gateway acl blob", got)
+	}
+	// the acl blob paths can't be written with the object requests
+	if err := checkObjectKey(got); err == nil {
+		t.Errorf("expected the acl blob path %v rejected as an object key", got)
+	}
+
+	other := getObjectAclPath("dir/obj2", nil, &etag)
+	if other == got || !strings.HasPrefix(other, string(metaTmpObjAclPrefix)+"/") {
+		t.Errorf("unexpected acl path %v of another object", other)
+	}
+}
+
+func TestAzure_MetaTmpKeys(t *testing.T) {
+	az := &Azure{}
+	ctx := context.Background()
+	bucket := "bucket"
+	key := ".sgwtmp/objacl/" + fmt.Sprintf("%x", sha256.Sum256([]byte("obj"))) + "/etag-1"
+	denied := s3err.GetAPIError(s3err.ErrAccessDenied)
+
+	tests := []struct {
+		name string
+		fn   func() error
+	}{
+		{"PutObject", func() error {
+			_, err := az.PutObject(ctx, s3response.PutObjectInput{Bucket: &bucket, Key: &key})
+			return err
+		}},
+		{"GetObject", func() error {
+			_, err := az.GetObject(ctx, &s3.GetObjectInput{Bucket: &bucket, Key: &key})
+			return err
+		}},
+		{"HeadObject", func() error {
+			_, err := az.HeadObject(ctx, &s3.HeadObjectInput{Bucket: &bucket, Key: &key})
+			return err
+		}},
+		{"DeleteObject", func() error {
+			_, err := az.DeleteObject(ctx, &s3.DeleteObjectInput{Bucket: &bucket, Key: &key})
+			return err
+		}},
+		{"CopyObject", func() error {
+			src := bucket + "/obj"
+			_, err := az.CopyObject(ctx, s3response.CopyObjectInput{Bucket: &bucket, Key: &key, CopySource: &src})
+			return err
+		}},
+		{"CopyObject source", func() error {
+			dst := "obj"
+			src := bucket + "/" + key
+			_, err := az.CopyObject(ctx, s3response.CopyObjectInput{Bucket: &bucket, Key: &dst, CopySource: &src})
+			return err
+		}},
+		{"CreateMultipartUpload", func() error {
+			_, err := az.CreateMultipartUpload(ctx, s3response.CreateMultipartUploadInput{Bucket: &bucket, Key: &key})
+			return err
+		}},
+		{"PutObjectTagging", func() error {
+			return az.PutObjectTagging(ctx, bucket, key, "", nil)
+		}},
+		{"DeleteObjectTagging", func() error {
+			return az.DeleteObjectTagging(ctx, bucket, key, "")
+		}},
+		{"PutObjectAcl", func() error {
+			return az.PutObjectAcl(ctx, &s3.PutObjectAclInput{Bucket: &bucket, Key: &key})
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.fn(); !errors.Is(err, denied) {
+				t.Errorf("expected access denied, got %v", err)
+			}
+		})
+	}
+}
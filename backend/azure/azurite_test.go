@@ -0,0 +1,341 @@
+// Copyright 2026 Versity Software
+// This file is licensed under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package azure
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/google/uuid"
+	"github.com/versity/versitygw/auth"
+	"github.com/versity/versitygw/backend"
+	"github.com/versity/versitygw/s3err"
+	"github.com/versity/versitygw/s3response"
+)
+
+// The azurite tests run against the blob service url in
+// VGW_TEST_AZURE_URL, e.g. http://127.0.0.1:10000/devstoreaccount1,
+// with the AZ_ACCOUNT_NAME and AZ_ACCOUNT_KEY credentials (the azurite
+// default account when unset). The tests are skipped without the url.
+
+const (
+	azuriteAccount = "devstoreaccount1"
+	azuriteKey     = "Eby8vdM02xNOcqFlqUwJPLlmEtlCDXJ1OUzFT50uSRZ6IFsuFq2UVErCz4I6tq/K1SZFPTOtr/KBHBeksoGMGw=="
+	azuriteOwner   = "owner"
+)
+
+func newAzuriteBucket(t *testing.T) (*Azure, string) {
+	t.Helper()
+
+	url := os.Getenv("VGW_TEST_AZURE_URL")
+	if url == "" {
+		t.Skip("VGW_TEST_AZURE_URL is not set")
+	}
+	account, key := os.Getenv("AZ_ACCOUNT_NAME"), os.Getenv("AZ_ACCOUNT_KEY")
+	if account == "" {
+		account, key = azuriteAccount, azuriteKey
+	}
+
+	az, err := New(account, key, url, "")
+	if err != nil {
+		t.Fatalf("init azure backend: %v", err)
+	}
+
+	acl, err := json.Marshal(auth.ACL{Owner: azuriteOwner})
+	if err != nil {
+		t.Fatalf("marshal bucket acl: %v", err)
+	}
+
+	bucket := "vgw-test-" + strings.ReplaceAll(uuid.NewString(), "-", "")[:16]
+	err = az.CreateBucket(context.Background(), &s3.CreateBucketInput{
+		Bucket: &bucket,
+	}, acl)
+	if err != nil {
+		t.Fatalf("create bucket: %v", err)
+	}
+	t.Cleanup(func() {
+		az.client.DeleteContainer(context.Background(), bucket, nil)
+	})
+
+	return az, bucket
+}
+
+func azuritePut(t *testing.T, az *Azure, bucket, key, data string) string {
+	t.Helper()
+
+	size := int64(len(data))
+	out, err := az.PutObject(context.Background(), s3response.PutObjectInput{
+		Bucket:        &bucket,
+		Key:           &key,
+		ContentLength: &size,
+		Body:          strings.NewReader(data),
+	})
+	if err != nil {
+		t.Fatalf("put object %v: %v", key, err)
+	}
+	return out.VersionID
+}
+
+func azuriteGet(az *Azure, bucket, key, versionId string) (string, error) {
+	input := &s3.GetObjectInput{
+		Bucket: &bucket,
+		Key:    &key,
+	}
+	if versionId != "" {
+		input.VersionId = &versionId
+	}
+	out, err := az.GetObject(context.Background(), input)
+	if err != nil {
+		return "", err
+	}
+	defer out.Body.Close()
+
+	data, err := io.ReadAll(out.Body)
+	return string(data), err
+}
+
+func azuriteVersions(t *testing.T, az *Azure, bucket string) s3response.ListVersionsResult {
+	t.Helper()
+
+	empty := ""
+	out, err := az.ListObjectVersions(context.Background(), &s3.ListObjectVersionsInput{
+		Bucket:          &bucket,
+		Prefix:          &empty,
+		Delimiter:       &empty,
+		KeyMarker:       &empty,
+		VersionIdMarker: &empty,
+	})
+	if err != nil {
+		t.Fatalf("list object versions: %v", err)
+	}
+	return out
+}
+
+// newAzuriteVersionedBucket skips the test if the storage account has
+// no blob versioning, azurite doesn't support blob versioning
+func newAzuriteVersionedBucket(t *testing.T) (*Azure, string) {
+	t.Helper()
+
+	az, bucket := newAzuriteBucket(t)
+	err := az.PutBucketVersioning(context.Background(), bucket, types.BucketVersioningStatusEnabled)
+	if err != nil {
+		t.Fatalf("put bucket versioning: %v", err)
+	}
+	if azuritePut(t, az, bucket, "probe", "probe") == "" {
+		t.Skip("blob versioning is not enabled on the storage account")
+	}
+	return az, bucket
+}
+
+func hasPublicRead(acl *s3.GetObjectAclOutput) bool {
+	for _, grant := range acl.Grants {
+		if grant.Grantee != nil && grant.Grantee.Type == types.TypeGroup &&
+			grant.Permission == types.PermissionRead {
+			return true
+		}
+	}
+	return false
+}
+
+func TestAzurite_ObjectAcl(t *testing.T) {
+	az, bucket := newAzuriteBucket(t)
+	ctx := context.Background()
+	key := "dir/obj"
+
+	azuritePut(t, az, bucket, key, "data")
+
+	err := az.PutObjectAcl(ctx, &s3.PutObjectAclInput{
+		Bucket: &bucket,
+		Key:    &key,
+		ACL:    types.ObjectCannedACLPublicRead,
+	})
+	if err != nil {
+		t.Fatalf("put object acl: %v", err)
+	}
+
+	acl, err := az.GetObjectAcl(ctx, &s3.GetObjectAclInput{Bucket: &bucket, Key: &key})
+	if err != nil {
+		t.Fatalf("get object acl: %v", err)
+	}
+	if !hasPublicRead(acl) || backend.GetStringFromPtr(acl.Owner.ID) != azuriteOwner {
+		t.Errorf("expected public read acl owned by %v, got %+v", azuriteOwner, acl)
+	}
+
+	// the acl is not stored in the object metadata
+	head, err := az.HeadObject(ctx, &s3.HeadObjectInput{Bucket: &bucket, Key: &key})
+	if err != nil {
+		t.Fatalf("head object: %v", err)
+	}
+	if len(head.Metadata) != 0 {
+		t.Errorf("expected no user metadata, got %v", head.Metadata)
+	}
+
+	empty := ""
+	list, err := az.ListObjectsV2(ctx, &s3.ListObjectsV2Input{
+		Bucket:            &bucket,
+		Prefix:            &empty,
+		Delimiter:         &empty,
+		ContinuationToken: &empty,
+		StartAfter:        &empty,
+	})
+	if err != nil {
+		t.Fatalf("list objects: %v", err)
+	}
+	if len(list.Contents) != 1 || backend.GetStringFromPtr(list.Contents[0].Key) != key {
+		t.Errorf("expected only %v listed, got %+v", key, list.Contents)
+	}
+
+	// overwriting the object resets the acl
+	azuritePut(t, az, bucket, key, "new data")
+	acl, err = az.GetObjectAcl(ctx, &s3.GetObjectAclInput{Bucket: &bucket, Key: &key})
+	if err != nil {
+		t.Fatalf("get object acl: %v", err)
+	}
+	if hasPublicRead(acl) {
+		t.Error("expected the overwritten object to have the default acl")
+	}
+
+	_, err = az.DeleteObject(ctx, &s3.DeleteObjectInput{Bucket: &bucket, Key: &key})
+	if err != nil {
+		t.Fatalf("delete object: %v", err)
+	}
+	err = az.DeleteBucket(ctx, bucket)
+	if err != nil {
+		t.Errorf("expected the bucket with only object acls left to be deleted: %v", err)
+	}
+}
+
+func TestAzurite_DeleteMarker(t *testing.T) {
+	az, bucket := newAzuriteVersionedBucket(t)
+	ctx := context.Background()
+	key := "obj"
+
+	v1 := azuritePut(t, az, bucket, key, "v1")
+	v2 := azuritePut(t, az, bucket, key, "v2")
+	if v1 == v2 {
+		t.Fatalf("expected new version id, got %v twice", v1)
+	}
+
+	out, err := az.DeleteObject(ctx, &s3.DeleteObjectInput{Bucket: &bucket, Key: &key})
+	if err != nil {
+		t.Fatalf("delete object: %v", err)
+	}
+	if out.DeleteMarker == nil || !*out.DeleteMarker || out.VersionId == nil {
+		t.Fatalf("expected delete marker, got %+v", out)
+	}
+	azId, deleteMarker, err := decodeVersionId(*out.VersionId)
+	if err != nil || !deleteMarker {
+		t.Fatalf("expected delete marker version id, got %v %v %v", azId, deleteMarker, err)
+	}
+
+	_, err = azuriteGet(az, bucket, key, "")
+	if !errors.Is(err, s3err.GetAPIError(s3err.ErrNoSuchKey)) {
+		t.Errorf("expected ErrNoSuchKey behind the delete marker, got %v", err)
+	}
+	data, err := azuriteGet(az, bucket, key, v1)
+	if err != nil || data != "v1" {
+		t.Errorf("expected version %v data v1, got %q %v", v1, data, err)
+	}
+
+	versions := azuriteVersions(t, az, bucket)
+	var markers int
+	for _, dm := range versions.DeleteMarkers {
+		if backend.GetStringFromPtr(dm.Key) != key {
+			continue
+		}
+		markers++
+		if backend.GetStringFromPtr(dm.VersionId) != *out.VersionId {
+			t.Errorf("expected delete marker %v, got %v", *out.VersionId, backend.GetStringFromPtr(dm.VersionId))
+		}
+		if dm.IsLatest == nil || !*dm.IsLatest {
+			t.Error("expected the delete marker to be the latest version")
+		}
+	}
+	if markers != 1 {
+		t.Fatalf("expected 1 delete marker, got %v", markers)
+	}
+
+	// removing the delete marker restores the newest version
+	_, err = az.DeleteObject(ctx, &s3.DeleteObjectInput{
+		Bucket:    &bucket,
+		Key:       &key,
+		VersionId: out.VersionId,
+	})
+	if err != nil {
+		t.Fatalf("delete the delete marker: %v", err)
+	}
+	data, err = azuriteGet(az, bucket, key, "")
+	if err != nil || data != "v2" {
+		t.Errorf("expected restored data v2, got %q %v", data, err)
+	}
+
+	var count int
+	for _, v := range azuriteVersions(t, az, bucket).Versions {
+		if backend.GetStringFromPtr(v.Key) == key {
+			count++
+		}
+	}
+	if count != 2 {
+		t.Errorf("expected 2 versions after the restore, got %v", count)
+	}
+}
+
+func TestAzurite_VersionAcl(t *testing.T) {
+	az, bucket := newAzuriteVersionedBucket(t)
+	ctx := context.Background()
+	key := "obj"
+
+	v1 := azuritePut(t, az, bucket, key, "v1")
+	azuritePut(t, az, bucket, key, "v2")
+	before := len(azuriteVersions(t, az, bucket).Versions)
+
+	// the acl of a non current version can be set
+	err := az.PutObjectAcl(ctx, &s3.PutObjectAclInput{
+		Bucket:    &bucket,
+		Key:       &key,
+		VersionId: &v1,
+		ACL:       types.ObjectCannedACLPublicRead,
+	})
+	if err != nil {
+		t.Fatalf("put object acl: %v", err)
+	}
+
+	if after := len(azuriteVersions(t, az, bucket).Versions); after != before {
+		t.Errorf("expected %v versions after setting the acl, got %v", before, after)
+	}
+
+	acl, err := az.GetObjectAcl(ctx, &s3.GetObjectAclInput{Bucket: &bucket, Key: &key, VersionId: &v1})
+	if err != nil {
+		t.Fatalf("get object acl: %v", err)
+	}
+	if !hasPublicRead(acl) {
+		t.Error("expected public read acl on the non current version")
+	}
+
+	acl, err = az.GetObjectAcl(ctx, &s3.GetObjectAclInput{Bucket: &bucket, Key: &key})
+	if err != nil {
+		t.Fatalf("get object acl: %v", err)
+	}
+	if hasPublicRead(acl) {
+		t.Error("expected the current version to keep the default acl")
+	}
+}
@@ -61,3 +61,13 @@ func parseMpError(mpErr error) error {
 
 	return s3err.GetAPIError(s3err.ErrNoSuchUpload)
 }
+
+// azureVersionErrToS3Err reports the missing blobs as missing versions
+// when a specific object version was requested
+func azureVersionErrToS3Err(apiErr error, versionId string) error {
+	err := azureErrToS3Err(apiErr)
+	if versionId != "" && versionId != nullVersionId && errors.Is(err, s3err.GetAPIError(s3err.ErrNoSuchKey)) {
+		return s3err.GetAPIError(s3err.ErrNoSuchVersion)
+	}
+	return err
+}
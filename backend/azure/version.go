@@ -0,0 +1,169 @@
+// Copyright 2023 Versity Software
+// This file is licensed under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package azure
+
+import (
+	"encoding/binary"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/oklog/ulid/v2"
+	"github.com/versity/versitygw/s3err"
+)
+
+// Azure blob version ids are the blob modification timestamps with
+// 100ns precision, e.g. "2024-06-04T18:32:01.7423985Z". The gateway
+// requires ULID version ids, so the azure version ids are mapped to
+// ULIDs holding the millisecond timestamp in the ULID time and the
+// remaining 100ns ticks in the entropy. The mapping is reversible and
+// keeps the chronological order of the versions.
+//
+// Azure has no delete markers: deleting the base blob of a versioned
+// blob leaves only previous versions. Such keys are reported with a
+// delete marker whose id is the id of the newest version with the
+// delete marker flag set in the entropy.
+
+const (
+	azVersionIdFormat = "2006-01-02T15:04:05.0000000Z"
+	nullVersionId     = "null"
+
+	versionFlagDeleteMarker byte = 1
+
+	// the bucket versioning status is cached for versioningCacheTTL,
+	// gateways sharing the storage account see the status changes
+	// of the other gateways after that time
+	versioningCacheTTL  = 10 * time.Second
+	versioningCacheSize = 1024
+
+	// promoting a version waits for the copy of the version to the
+	// base blob to complete
+	promoteCopyTimeout     = 5 * time.Minute
+	promoteCopyMinInterval = 100 * time.Millisecond
+	promoteCopyMaxInterval = 5 * time.Second
+)
+
+// encodeVersionId converts the azure version id to a ULID version id
+func encodeVersionId(azVersionId string, deleteMarker bool) (string, error) {
+	t, err := time.Parse(time.RFC3339Nano, azVersionId)
+	if err != nil {
+		return "", err
+	}
+
+	var id ulid.ULID
+	err = id.SetTime(ulid.Timestamp(t))
+	if err != nil {
+		return "", err
+	}
+
+	entropy := make([]byte, 10)
+	if deleteMarker {
+		entropy[0] = versionFlagDeleteMarker
+	}
+	ticks := t.Nanosecond() % int(time.Millisecond) / 100
+	binary.BigEndian.PutUint16(entropy[1:3], uint16(ticks))
+
+	err = id.SetEntropy(entropy)
+	if err != nil {
+		return "", err
+	}
+
+	return id.String(), nil
+}
+
+// decodeVersionId converts the ULID version id back to the azure
+// version id, and reports if the id refers to a delete marker
+func decodeVersionId(versionId string) (string, bool, error) {
+	id, err := ulid.Parse(versionId)
+	if err != nil {
+		return "", false, s3err.GetAPIError(s3err.ErrInvalidVersionId)
+	}
+
+	entropy := id.Entropy()
+	ticks := binary.BigEndian.Uint16(entropy[1:3])
+	if ticks >= uint16(time.Millisecond/100) {
+		return "", false, s3err.GetAPIError(s3err.ErrInvalidVersionId)
+	}
+
+	t := ulid.Time(id.Time()).Add(time.Duration(ticks) * 100)
+	return t.UTC().Format(azVersionIdFormat), entropy[0] == versionFlagDeleteMarker, nil
+}
+
+// getVersionIdPtr returns the ULID version id of the azure version id,
+// or nil if the blob has no (valid) version id
+func getVersionIdPtr(azVersionId *string) *string {
+	if azVersionId == nil || *azVersionId == "" {
+		return nil
+	}
+	versionId, err := encodeVersionId(*azVersionId, false)
+	if err != nil {
+		return nil
+	}
+	return &versionId
+}
+
+type versioningEntry struct {
+	status  types.BucketVersioningStatus
+	expires time.Time
+}
+
+// versioningCache caches the bucket versioning status, the zero value
+// is an empty cache
+type versioningCache struct {
+	mu      sync.Mutex
+	entries map[string]versioningEntry
+}
+
+func (c *versioningCache) load(bucket string) (types.BucketVersioningStatus, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[bucket]
+	if !ok || time.Now().After(entry.expires) {
+		return "", false
+	}
+	return entry.status, true
+}
+
+func (c *versioningCache) store(bucket string, status types.BucketVersioningStatus) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	if c.entries == nil {
+		c.entries = make(map[string]versioningEntry)
+	}
+	if len(c.entries) >= versioningCacheSize {
+		for b, entry := range c.entries {
+			if now.After(entry.expires) {
+				delete(c.entries, b)
+			}
+		}
+		if len(c.entries) >= versioningCacheSize {
+			clear(c.entries)
+		}
+	}
+	c.entries[bucket] = versioningEntry{
+		status:  status,
+		expires: now.Add(versioningCacheTTL),
+	}
+}
+
+func (c *versioningCache) remove(bucket string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.entries, bucket)
+}
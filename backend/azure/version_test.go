@@ -0,0 +1,159 @@
+// Copyright 2026 Versity Software
+// This file is licensed under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package azure
+
+import (
+	"errors"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/versity/versitygw/s3err"
+)
+
+func TestEncodeVersionId(t *testing.T) {
+	tests := []string{
+		"2024-06-04T18:32:01.7423985Z",
+		"2024-06-04T18:32:01.0000000Z",
+		"2024-06-04T18:32:01.0009999Z",
+		"1999-12-31T23:59:59.9999999Z",
+	}
+
+	for _, azVersionId := range tests {
+		for _, deleteMarker := range []bool{false, true} {
+			versionId, err := encodeVersionId(azVersionId, deleteMarker)
+			if err != nil {
+				t.Fatalf("encode %v: %v", azVersionId, err)
+			}
+			if len(versionId) != 26 {
+				t.Errorf("encode %v: expected a ULID, got %q", azVersionId, versionId)
+			}
+
+			got, marker, err := decodeVersionId(versionId)
+			if err != nil {
+				t.Fatalf("decode %v: %v", versionId, err)
+			}
+			if got != azVersionId {
+				t.Errorf("expected azure version id %v, got %v", azVersionId, got)
+			}
+			if marker != deleteMarker {
+				t.Errorf("%v: expected delete marker %v, got %v", azVersionId, deleteMarker, marker)
+			}
+		}
+	}
+}
+
+func TestEncodeVersionId_Order(t *testing.T) {
+	start := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	var azIds, ids []string
+	for _, d := range []time.Duration{0, 100, 200, time.Microsecond, time.Millisecond - 100, time.Millisecond, time.Second} {
+		azId := start.Add(d).Format(azVersionIdFormat)
+		id, err := encodeVersionId(azId, false)
+		if err != nil {
+			t.Fatalf("encode %v: %v", azId, err)
+		}
+		azIds = append(azIds, azId)
+		ids = append(ids, id)
+	}
+
+	if !sort.StringsAreSorted(azIds) {
+		t.Fatalf("test azure version ids are not sorted: %v", azIds)
+	}
+	if !sort.StringsAreSorted(ids) {
+		t.Errorf("version ids don't keep the version order: %v", ids)
+	}
+}
+
+func TestDecodeVersionId_Invalid(t *testing.T) {
+	tests := []string{
+		"",
+		"null",
+		"not-a-version-id",
+		// valid ULID with more than a millisecond of ticks
+		"01HZG8W8Y80FFFF00000000000",
+	}
+
+	for _, versionId := range tests {
+		_, _, err := decodeVersionId(versionId)
+		if !errors.Is(err, s3err.GetAPIError(s3err.ErrInvalidVersionId)) {
+			t.Errorf("%q: expected ErrInvalidVersionId, got %v", versionId, err)
+		}
+	}
+}
+
+func TestGetVersionIdPtr(t *testing.T) {
+	if getVersionIdPtr(nil) != nil {
+		t.Error("expected nil version id for nil azure version id")
+	}
+	empty := ""
+	if getVersionIdPtr(&empty) != nil {
+		t.Error("expected nil version id for empty azure version id")
+	}
+	invalid := "invalid"
+	if getVersionIdPtr(&invalid) != nil {
+		t.Error("expected nil version id for invalid azure version id")
+	}
+
+	azVersionId := "2024-06-04T18:32:01.7423985Z"
+	id := getVersionIdPtr(&azVersionId)
+	if id == nil {
+		t.Fatal("expected a version id")
+	}
+	want, _ := encodeVersionId(azVersionId, false)
+	if *id != want {
+		t.Errorf("expected version id %v, got %v", want, *id)
+	}
+}
+
+func TestVersioningCache(t *testing.T) {
+	var c versioningCache
+
+	if _, ok := c.load("bucket"); ok {
+		t.Fatal("expected empty cache")
+	}
+
+	c.store("bucket", types.BucketVersioningStatusEnabled)
+	status, ok := c.load("bucket")
+	if !ok || status != types.BucketVersioningStatusEnabled {
+		t.Errorf("expected cached status Enabled, got %q %v", status, ok)
+	}
+
+	// the unversioned status is cached as well
+	c.store("other", "")
+	if status, ok := c.load("other"); !ok || status != "" {
+		t.Errorf("expected cached empty status, got %q %v", status, ok)
+	}
+
+	c.remove("bucket")
+	if _, ok := c.load("bucket"); ok {
+		t.Error("expected removed bucket to be uncached")
+	}
+
+	c.entries["expired"] = versioningEntry{
+		status:  types.BucketVersioningStatusSuspended,
+		expires: time.Now().Add(-time.Second),
+	}
+	if _, ok := c.load("expired"); ok {
+		t.Error("expected expired entry to be ignored")
+	}
+
+	for i := range versioningCacheSize + 10 {
+		c.store(string(rune('a'+i%26))+time.Duration(i).String(), "")
+	}
+	if len(c.entries) > versioningCacheSize {
+		t.Errorf("expected at most %v cached buckets, got %v", versioningCacheSize, len(c.entries))
+	}
+}
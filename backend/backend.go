@@ -49,6 +49,9 @@ type Backend interface {
 	PutBucketCors(_ context.Context, bucket string, cors []byte) error
 	GetBucketCors(_ context.Context, bucket string) ([]byte, error)
 	DeleteBucketCors(_ context.Context, bucket string) error
+	PutPublicAccessBlock(_ context.Context, bucket string, config []byte) error
+	GetPublicAccessBlock(_ context.Context, bucket string) ([]byte, error)
+	DeletePublicAccessBlock(_ context.Context, bucket string) error
 
 	// multipart operations
 	CreateMultipartUpload(context.Context, s3response.CreateMultipartUploadInput) (s3response.InitiateMultipartUploadResult, error)
@@ -98,6 +101,28 @@ type Backend interface {
 	// non AWS actions
 	ChangeBucketOwner(_ context.Context, bucket, owner string) error
 	ListBucketsAndOwners(context.Context) ([]s3response.Bucket, error)
+	SnapshotBucket(_ context.Context, bucket, snapshot string) error
+	SnapshotBucketStatus(_ context.Context, snapshot string) (s3response.SnapshotStatus, error)
+	ScrubBucket(_ context.Context, bucket string) (s3response.ScrubReport, error)
+	TransitionObject(_ context.Context, bucket, object, versionId string) error
+}
+
+// ScrubNotifier is implemented by the backends verifying the stored
+// object data in the background, to report the results of the scrubs
+type ScrubNotifier interface {
+	SetScrubReporter(fn func(s3response.ScrubReport))
+}
+
+// ChangeNotifier is implemented by the backends detecting the objects
+// changed outside of the gateway, to report the changes
+type ChangeNotifier interface {
+	SetChangeReporter(fn func(s3response.ObjectChange))
+}
+
+// HealthChecker is implemented by the backends able to check the
+// storage is usable, for the gateway readiness
+type HealthChecker interface {
+	CheckHealth(ctx context.Context) error
 }
 
 type BackendUnsupported struct{}
@@ -162,6 +187,15 @@ func (BackendUnsupported) GetBucketCors(_ context.Context, bucket string) ([]byt
 func (BackendUnsupported) DeleteBucketCors(_ context.Context, bucket string) error {
 	return s3err.GetAPIError(s3err.ErrNotImplemented)
 }
+func (BackendUnsupported) PutPublicAccessBlock(_ context.Context, bucket string, config []byte) error {
+	return s3err.GetAPIError(s3err.ErrNotImplemented)
+}
+func (BackendUnsupported) GetPublicAccessBlock(_ context.Context, bucket string) ([]byte, error) {
+	return nil, s3err.GetAPIError(s3err.ErrNotImplemented)
+}
+func (BackendUnsupported) DeletePublicAccessBlock(_ context.Context, bucket string) error {
+	return s3err.GetAPIError(s3err.ErrNotImplemented)
+}
 
 func (BackendUnsupported) CreateMultipartUpload(context.Context, s3response.CreateMultipartUploadInput) (s3response.InitiateMultipartUploadResult, error) {
 	return s3response.InitiateMultipartUploadResult{}, s3err.GetAPIError(s3err.ErrNotImplemented)
@@ -286,3 +320,15 @@ func (BackendUnsupported) ChangeBucketOwner(_ context.Context, bucket, owner str
 func (BackendUnsupported) ListBucketsAndOwners(context.Context) ([]s3response.Bucket, error) {
 	return []s3response.Bucket{}, s3err.GetAPIError(s3err.ErrNotImplemented)
 }
+func (BackendUnsupported) SnapshotBucket(_ context.Context, bucket, snapshot string) error {
+	return s3err.GetAPIError(s3err.ErrNotImplemented)
+}
+func (BackendUnsupported) SnapshotBucketStatus(_ context.Context, snapshot string) (s3response.SnapshotStatus, error) {
+	return s3response.SnapshotStatus{}, s3err.GetAPIError(s3err.ErrNotImplemented)
+}
+func (BackendUnsupported) ScrubBucket(_ context.Context, bucket string) (s3response.ScrubReport, error) {
+	return s3response.ScrubReport{}, s3err.GetAPIError(s3err.ErrNotImplemented)
+}
+func (BackendUnsupported) TransitionObject(_ context.Context, bucket, object, versionId string) error {
+	return s3err.GetAPIError(s3err.ErrNotImplemented)
+}
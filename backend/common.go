@@ -15,6 +15,7 @@
 package backend
 
 import (
+	"context"
 	"crypto/md5"
 	"encoding/hex"
 	"errors"
@@ -678,3 +679,22 @@ func IsValidDirectoryName(name string) bool {
 
 	return true
 }
+
+// define a private key type
+type ctxKey int
+
+const (
+	ctxKeyBypassGovernance ctxKey = iota
+)
+
+// WithBypassGovernance lets the backends forwarding the request know
+// that the gateway allowed it to bypass the governance mode retention
+func WithBypassGovernance(ctx context.Context) context.Context {
+	return context.WithValue(ctx, ctxKeyBypassGovernance, struct{}{})
+}
+
+// IsBypassGovernance reports if the request is allowed to bypass
+// the governance mode retention
+func IsBypassGovernance(ctx context.Context) bool {
+	return ctx.Value(ctxKeyBypassGovernance) != nil
+}
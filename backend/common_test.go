@@ -15,6 +15,7 @@
 package backend
 
 import (
+	"context"
 	"errors"
 	"testing"
 
@@ -148,3 +149,17 @@ func TestParseCopySource(t *testing.T) {
 		})
 	}
 }
+
+func TestBypassGovernance(t *testing.T) {
+	ctx := context.Background()
+	if IsBypassGovernance(ctx) {
+		t.Error("expected no governance bypass by default")
+	}
+	// the plain string key of the request locals doesn't match
+	if IsBypassGovernance(context.WithValue(ctx, "bypass-governance-retention", true)) {
+		t.Error("expected string context keys to be ignored")
+	}
+	if !IsBypassGovernance(WithBypassGovernance(ctx)) {
+		t.Error("expected governance bypass")
+	}
+}
@@ -100,6 +100,16 @@ type LustreConfig struct {
 	StripeCount int      `json:"stripe_count"`
 	StripeSize  int64    `json:"stripe_size"`
 	Options     []string `json:"options"`
+	// HSM reports the released files as GLACIER objects, restored
+	// with RestoreObject and archived with the transition action
+	HSM bool `json:"hsm"`
+	// HSMArchiveID is the archive the files are archived to, the
+	// default archive is used when zero
+	HSMArchiveID int `json:"hsm_archive_id"`
+	// Pool is the OST pool the bucket data is striped over
+	Pool string `json:"pool"`
+	// BucketPools are the OST pools of the buckets, overriding Pool
+	BucketPools map[string]string `json:"bucket_pools"`
 }
 
 type MinIOConfig struct {
@@ -290,9 +300,15 @@ func (dm *DynamicBackendManager) createMinIOBackend(ctx context.Context, config
 	// Create user-specific bucket prefix
 	metaBucket := fmt.Sprintf("%s-meta-%s", minioConfig.BucketPrefix, config.UserID)
 
-	return s3proxy.New(ctx, minioConfig.AccessKey, minioConfig.SecretKey,
-		minioConfig.Endpoint, minioConfig.Region, metaBucket,
-		false, !minioConfig.SSL, minioConfig.UsePathStyle, false)
+	return s3proxy.New(ctx, s3proxy.Options{
+		Access:        minioConfig.AccessKey,
+		Secret:        minioConfig.SecretKey,
+		Endpoint:      minioConfig.Endpoint,
+		Region:        minioConfig.Region,
+		MetaBucket:    metaBucket,
+		SslSkipVerify: !minioConfig.SSL,
+		UsePathStyle:  minioConfig.UsePathStyle,
+	})
 }
 
 // createRustFSBackend creates a RustFS backend (placeholder)
@@ -509,11 +525,16 @@ func (dm *DynamicBackendManager) createLustreEnhancedBackend(config *UserBackend
 		NewDirPerm:  0755,
 	}
 
+	// The Lustre backend writes the large uploads in parallel
+	lustre := NewLustreEnhancedBackend(nil, config.MountPoint, lustreConfig)
+	opts.NewDataWriter = lustre.NewDataWriter
+
 	backend, err := posix.New(config.MountPoint, metastore, opts)
 	if err != nil {
 		return nil, err
 	}
 
 	// Wrap with Lustre-specific enhancements
-	return NewLustreEnhancedBackend(backend, lustreConfig), nil
+	lustre.Backend = backend
+	return lustre, nil
 }
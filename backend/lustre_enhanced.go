@@ -16,12 +16,14 @@ package backend
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
+	"io/fs"
 	"os"
 	"os/exec"
 	"path/filepath"
-	"runtime"
+	"slices"
 	"strconv"
 	"strings"
 	"sync"
@@ -30,19 +32,50 @@ import (
 	"github.com/versity/versitygw/s3response"
 )
 
+const (
+	// lustreDefaultStripeSize is the Lustre default stripe size, used
+	// when the stripe size is not configured
+	lustreDefaultStripeSize = 1024 * 1024
+	// lustreMaxIOWorkers bounds the parallel reads and writes of a request
+	lustreMaxIOWorkers = 16
+	// lustreTmpDir is the directory of the buckets the posix backend
+	// writes the uploaded data to before it is linked in the bucket,
+	// posix.MetaTmpDir
+	lustreTmpDir = ".sgwtmp"
+)
+
+// lustreLayoutExtents are the file extents of the bucket layouts and the
+// stripe count of each extent, the files are striped over more OSTs as
+// these grow
+var lustreLayoutExtents = []struct {
+	end   int64
+	count int
+}{
+	{1 * 1024 * 1024, 1},
+	{100 * 1024 * 1024, 2},
+	{1 * 1024 * 1024 * 1024, 4},
+	{-1, 8},
+}
+
 // LustreEnhancedBackend wraps a POSIX backend with Lustre-specific optimizations
 type LustreEnhancedBackend struct {
 	Backend
+	root         string
 	lustreConfig *LustreConfig
-	mu           sync.RWMutex
+	runner       LustreCommandRunner
+
+	// mu protects layouts, the buckets the layout was set on
+	mu      sync.RWMutex
+	layouts map[string]bool
 }
 
 // LustreStripeInfo contains Lustre striping information
 type LustreStripeInfo struct {
-	StripeCount int   `json:"stripe_count"`
-	StripeSize  int64 `json:"stripe_size"`
-	StripeIndex int   `json:"stripe_index"`
-	OSTs        []int `json:"osts"`
+	StripeCount int    `json:"stripe_count"`
+	StripeSize  int64  `json:"stripe_size"`
+	StripeIndex int    `json:"stripe_index"`
+	Pool        string `json:"pool"`
+	OSTs        []int  `json:"osts"`
 }
 
 // LustrePoolInfo contains Lustre pool information
@@ -51,426 +84,700 @@ type LustrePoolInfo struct {
 	OSTs     []int  `json:"osts"`
 }
 
-// NewLustreEnhancedBackend creates a new Lustre-enhanced backend
-func NewLustreEnhancedBackend(backend Backend, config *LustreConfig) *LustreEnhancedBackend {
+// NewLustreEnhancedBackend creates a new Lustre-enhanced backend for the
+// backend serving the buckets in the root directory
+func NewLustreEnhancedBackend(backend Backend, root string, config *LustreConfig) *LustreEnhancedBackend {
+	return NewLustreEnhancedBackendWithRunner(backend, root, config, execCommandRunner{})
+}
+
+// NewLustreEnhancedBackendWithRunner creates a new Lustre-enhanced backend
+// running the Lustre utilities with the runner
+func NewLustreEnhancedBackendWithRunner(backend Backend, root string, config *LustreConfig, runner LustreCommandRunner) *LustreEnhancedBackend {
 	return &LustreEnhancedBackend{
 		Backend:      backend,
+		root:         root,
 		lustreConfig: config,
+		runner:       runner,
+		layouts:      make(map[string]bool),
 	}
 }
 
-// PutObject implements optimized PutObject with Lustre striping
-func (l *LustreEnhancedBackend) PutObject(ctx context.Context, input s3response.PutObjectInput) (s3response.PutObjectOutput, error) {
-	// Get the target file path
-	bucket := *input.Bucket
-	key := *input.Key
-	filePath := filepath.Join(bucket, key)
+// CreateBucket sets the Lustre layout of the new bucket
+func (l *LustreEnhancedBackend) CreateBucket(ctx context.Context, input *s3.CreateBucketInput, defaultACL []byte) error {
+	err := l.Backend.CreateBucket(ctx, input, defaultACL)
+	if err != nil {
+		return err
+	}
 
-	// Determine optimal striping based on object size
-	contentLength := input.ContentLength
-	if contentLength == nil {
-		// Use default backend for unknown size
-		return l.Backend.PutObject(ctx, input)
+	if err := l.ensureBucketLayout(ctx, *input.Bucket); err != nil {
+		fmt.Printf("Warning: Failed to set bucket layout: %v\n", err)
 	}
+	return nil
+}
 
-	stripeConfig := l.calculateOptimalStriping(*contentLength)
+// PutObject implements optimized PutObject with Lustre striping, the object
+// data is written in parallel by the writer returned by NewDataWriter
+func (l *LustreEnhancedBackend) PutObject(ctx context.Context, input s3response.PutObjectInput) (s3response.PutObjectOutput, error) {
+	if err := l.ensureBucketLayout(ctx, *input.Bucket); err != nil {
+		// Log warning but continue with the current layout
+		fmt.Printf("Warning: Failed to set bucket layout: %v\n", err)
+	}
 
-	// Set Lustre striping for the directory if it doesn't exist
-	dir := filepath.Dir(filePath)
-	if err := l.ensureDirectoryStriping(dir, stripeConfig); err != nil {
-		// Log warning but continue with default backend
-		fmt.Printf("Warning: Failed to set directory striping: %v\n", err)
+	return l.Backend.PutObject(ctx, input)
+}
+
+// UploadPart implements optimized UploadPart with Lustre striping, the part
+// data is written in parallel by the writer returned by NewDataWriter
+func (l *LustreEnhancedBackend) UploadPart(ctx context.Context, input *s3.UploadPartInput) (*s3.UploadPartOutput, error) {
+	if err := l.ensureBucketLayout(ctx, *input.Bucket); err != nil {
+		fmt.Printf("Warning: Failed to set bucket layout: %v\n", err)
 	}
 
-	// For large files, use parallel writing
-	if *contentLength > l.getLargeFileThreshold() {
-		return l.putLargeObjectWithStriping(ctx, input, stripeConfig)
+	return l.Backend.UploadPart(ctx, input)
+}
+
+// NewDataWriter returns the parallel writer of the uploaded data of the
+// size written to the file, or nil if the data is written sequentially.
+// It is the data writer of the wrapped posix backend, see
+// posix.PosixOpts.NewDataWriter.
+func (l *LustreEnhancedBackend) NewDataWriter(f *os.File, bucket string, size int64) io.WriteCloser {
+	if size <= l.getLargeFileThreshold() {
+		return nil
 	}
 
-	// Use default backend for small files
-	return l.Backend.PutObject(ctx, input)
+	stripeInfo := l.calculateOptimalStriping(bucket, size)
+	if stripeInfo.StripeCount <= 1 {
+		return nil
+	}
+	return NewParallelWriter(f, size, stripeInfo)
 }
 
 // GetObject implements optimized GetObject with parallel reading
 func (l *LustreEnhancedBackend) GetObject(ctx context.Context, input *s3.GetObjectInput) (*s3.GetObjectOutput, error) {
-	// Get file info first
 	bucket := *input.Bucket
 	key := *input.Key
-	filePath := filepath.Join(bucket, key)
 
-	stat, err := os.Stat(filePath)
+	// Released files must be restored from the HSM archive first
+	if err := l.checkReleased(ctx, bucket, key, input.VersionId); err != nil {
+		return nil, err
+	}
+
+	res, err := l.Backend.GetObject(ctx, input)
 	if err != nil {
-		return l.Backend.GetObject(ctx, input)
+		return nil, err
+	}
+
+	// The object versions are not stored at the object path, these
+	// are read sequentially
+	if res.Body == nil || res.ContentLength == nil ||
+		*res.ContentLength <= l.getLargeFileThreshold() ||
+		(input.VersionId != nil && *input.VersionId != "") {
+		return res, nil
+	}
+
+	// Only the uncompressed object data is read in parallel
+	file, offset, ok := objectFileSection(res.Body)
+	if !ok {
+		return res, nil
+	}
+
+	stripeInfo, err := l.getFileStripeInfo(ctx, l.objectPath(bucket, key))
+	if err != nil || stripeInfo.StripeCount <= 1 {
+		return res, nil
 	}
 
-	// For large files, use parallel reading
-	if stat.Size() > l.getLargeFileThreshold() {
-		stripeInfo, err := l.getFileStripeInfo(filePath)
-		if err == nil && stripeInfo.StripeCount > 1 {
-			return l.getLargeObjectWithStriping(ctx, input, stripeInfo)
+	res.Body = NewParallelReader(res.Body, file, offset, *res.ContentLength, stripeInfo)
+	return res, nil
+}
+
+// objectFileSection returns the object file and the offset of the data
+// read by the posix object body, if the body reads the file data as is
+func objectFileSection(body io.ReadCloser) (*os.File, int64, bool) {
+	switch b := body.(type) {
+	case *os.File:
+		return b, 0, true
+	case *FileSectionReadCloser:
+		sr, ok := b.R.(*io.SectionReader)
+		if !ok {
+			return nil, 0, false
 		}
+		_, offset, _ := sr.Outer()
+		return b.F, offset, true
 	}
+	return nil, 0, false
+}
 
-	// Use default backend for small files or non-striped files
-	return l.Backend.GetObject(ctx, input)
+// GetObjectAttributes adds the Lustre layout of the object file
+func (l *LustreEnhancedBackend) GetObjectAttributes(ctx context.Context, input *s3.GetObjectAttributesInput) (s3response.GetObjectAttributesResponse, error) {
+	res, err := l.Backend.GetObjectAttributes(ctx, input)
+	if err != nil || (input.VersionId != nil && *input.VersionId != "") ||
+		strings.HasSuffix(*input.Key, "/") {
+		return res, err
+	}
+
+	// The layout is informational, the attributes are returned without
+	// it if the layout can't be read
+	stripeInfo, err := l.getFileStripeInfo(ctx, l.objectPath(*input.Bucket, *input.Key))
+	if err == nil {
+		res.Layout = &s3response.ObjectLayout{
+			StripeCount: stripeInfo.StripeCount,
+			StripeSize:  stripeInfo.StripeSize,
+			Pool:        stripeInfo.Pool,
+		}
+	}
+
+	return res, nil
 }
 
 // calculateOptimalStriping determines optimal striping based on file size
-func (l *LustreEnhancedBackend) calculateOptimalStriping(size int64) *LustreStripeInfo {
+func (l *LustreEnhancedBackend) calculateOptimalStriping(bucket string, size int64) *LustreStripeInfo {
 	stripeInfo := &LustreStripeInfo{
-		StripeCount: l.lustreConfig.StripeCount,
-		StripeSize:  l.lustreConfig.StripeSize,
+		StripeSize:  l.stripeSize(),
 		StripeIndex: -1, // Let Lustre choose
+		Pool:        l.bucketPool(bucket),
 	}
 
 	// Adjust stripe count based on file size
-	if size < 1*1024*1024 { // < 1MB
-		stripeInfo.StripeCount = 1
-	} else if size < 100*1024*1024 { // < 100MB
-		stripeInfo.StripeCount = 2
-	} else if size < 1*1024*1024*1024 { // < 1GB
-		stripeInfo.StripeCount = 4
-	} else { // >= 1GB
-		stripeInfo.StripeCount = 8
-	}
-
-	// Don't exceed configured maximum
-	if l.lustreConfig.StripeCount > 0 && stripeInfo.StripeCount > l.lustreConfig.StripeCount {
-		stripeInfo.StripeCount = l.lustreConfig.StripeCount
+	for _, ext := range lustreLayoutExtents {
+		stripeInfo.StripeCount = l.capStripeCount(ext.count)
+		if ext.end < 0 || size < ext.end {
+			break
+		}
 	}
 
 	return stripeInfo
 }
 
-// ensureDirectoryStriping sets up Lustre striping for a directory
-func (l *LustreEnhancedBackend) ensureDirectoryStriping(dirPath string, stripeInfo *LustreStripeInfo) error {
-	// Check if directory exists
-	if _, err := os.Stat(dirPath); os.IsNotExist(err) {
-		// Create directory first
-		if err := os.MkdirAll(dirPath, 0755); err != nil {
-			return fmt.Errorf("failed to create directory: %w", err)
-		}
+// capStripeCount limits the stripe count to the configured maximum
+func (l *LustreEnhancedBackend) capStripeCount(count int) int {
+	if l.lustreConfig.StripeCount > 0 && count > l.lustreConfig.StripeCount {
+		return l.lustreConfig.StripeCount
 	}
+	return count
+}
 
-	// Set Lustre striping using lfs setstripe
-	cmd := []string{"lfs", "setstripe"}
+// stripeSize returns the configured stripe size
+func (l *LustreEnhancedBackend) stripeSize() int64 {
+	if l.lustreConfig.StripeSize > 0 {
+		return l.lustreConfig.StripeSize
+	}
+	return lustreDefaultStripeSize
+}
 
-	if stripeInfo.StripeCount > 0 {
-		cmd = append(cmd, "-c", strconv.Itoa(stripeInfo.StripeCount))
+// bucketPool returns the OST pool of the bucket
+func (l *LustreEnhancedBackend) bucketPool(bucket string) string {
+	if pool, ok := l.lustreConfig.BucketPools[bucket]; ok {
+		return pool
 	}
+	return l.lustreConfig.Pool
+}
 
-	if stripeInfo.StripeSize > 0 {
-		cmd = append(cmd, "-S", strconv.FormatInt(stripeInfo.StripeSize, 10))
+// bucketLayoutArgs returns the lfs setstripe arguments of the bucket
+// default layout. The uploaded data is written to temp files that are
+// linked in the bucket once complete, so the layout can't be set per
+// object. The bucket layout is a progressive file layout instead,
+// striping the files over more OSTs as these grow, following
+// calculateOptimalStriping.
+func (l *LustreEnhancedBackend) bucketLayoutArgs(bucket string) []string {
+	stripeSize := l.stripeSize()
+	pool := l.bucketPool(bucket)
+
+	type component struct {
+		end   int64
+		count int
+	}
+	var comps []component
+	for _, ext := range lustreLayoutExtents {
+		end := ext.end
+		if end > 0 {
+			// the component extents are stripe size aligned
+			end = (end + stripeSize - 1) / stripeSize * stripeSize
+		}
+		count := l.capStripeCount(ext.count)
+
+		last := len(comps) - 1
+		if last >= 0 && (comps[last].count == count ||
+			(end > 0 && end <= comps[last].end)) {
+			comps[last].end = end
+			comps[last].count = count
+			continue
+		}
+		comps = append(comps, component{end: end, count: count})
 	}
 
-	if stripeInfo.StripeIndex >= 0 {
-		cmd = append(cmd, "-i", strconv.Itoa(stripeInfo.StripeIndex))
+	args := []string{"setstripe"}
+	for _, c := range comps {
+		if len(comps) > 1 {
+			args = append(args, "-E", strconv.FormatInt(c.end, 10))
+		}
+		args = append(args, "-c", strconv.Itoa(c.count),
+			"-S", strconv.FormatInt(stripeSize, 10))
+		if pool != "" {
+			args = append(args, "-p", pool)
+		}
 	}
+	return args
+}
 
-	cmd = append(cmd, dirPath)
+// ensureBucketLayout sets the default layout of the bucket directory and
+// of the bucket temp directory the uploaded data is written to, once per
+// bucket. The temp directory inherits the bucket layout when it is
+// created after the bucket layout is set.
+func (l *LustreEnhancedBackend) ensureBucketLayout(ctx context.Context, bucket string) error {
+	l.mu.RLock()
+	done := l.layouts[bucket]
+	l.mu.RUnlock()
+	if done {
+		return nil
+	}
+
+	for _, dir := range []string{bucket, filepath.Join(bucket, lustreTmpDir)} {
+		path := filepath.Join(l.root, dir)
+		_, err := os.Stat(path)
+		if errors.Is(err, fs.ErrNotExist) {
+			if dir == bucket {
+				// the backend reports the missing bucket
+				return nil
+			}
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("stat %v: %w", path, err)
+		}
 
-	// Execute command
-	execCmd := exec.Command(cmd[0], cmd[1:]...)
-	output, err := execCmd.CombinedOutput()
-	if err != nil {
-		return fmt.Errorf("lfs setstripe failed: %s: %w", string(output), err)
+		err = l.setStripe(ctx, path, l.bucketLayoutArgs(bucket))
+		if err != nil {
+			return err
+		}
 	}
 
+	l.mu.Lock()
+	l.layouts[bucket] = true
+	l.mu.Unlock()
 	return nil
 }
 
-// getFileStripeInfo gets striping information for a file
-func (l *LustreEnhancedBackend) getFileStripeInfo(filePath string) (*LustreStripeInfo, error) {
-	cmd := exec.Command("lfs", "getstripe", "-c", "-S", "-i", filePath)
-	output, err := cmd.Output()
+// setStripe sets the layout of the path with lfs setstripe
+func (l *LustreEnhancedBackend) setStripe(ctx context.Context, path string, args []string) error {
+	_, err := l.runner.Run(ctx, "lfs", append(args, path)...)
 	if err != nil {
-		return nil, fmt.Errorf("lfs getstripe failed: %w", err)
+		return fmt.Errorf("lfs setstripe failed: %w", err)
 	}
+	return nil
+}
 
-	lines := strings.Split(strings.TrimSpace(string(output)), "\n")
-	if len(lines) < 3 {
-		return nil, fmt.Errorf("unexpected lfs getstripe output")
+// stripeArgs returns the lfs setstripe arguments of the striping
+func stripeArgs(stripeInfo *LustreStripeInfo) []string {
+	args := []string{"setstripe"}
+
+	if stripeInfo.StripeCount > 0 {
+		args = append(args, "-c", strconv.Itoa(stripeInfo.StripeCount))
 	}
 
-	stripeCount, err := strconv.Atoi(strings.TrimSpace(lines[0]))
-	if err != nil {
-		return nil, fmt.Errorf("invalid stripe count: %w", err)
+	if stripeInfo.StripeSize > 0 {
+		args = append(args, "-S", strconv.FormatInt(stripeInfo.StripeSize, 10))
 	}
 
-	stripeSize, err := strconv.ParseInt(strings.TrimSpace(lines[1]), 10, 64)
-	if err != nil {
-		return nil, fmt.Errorf("invalid stripe size: %w", err)
+	if stripeInfo.StripeIndex >= 0 {
+		args = append(args, "-i", strconv.Itoa(stripeInfo.StripeIndex))
 	}
 
-	stripeIndex, err := strconv.Atoi(strings.TrimSpace(lines[2]))
-	if err != nil {
-		return nil, fmt.Errorf("invalid stripe index: %w", err)
+	if stripeInfo.Pool != "" {
+		args = append(args, "-p", stripeInfo.Pool)
 	}
 
-	return &LustreStripeInfo{
-		StripeCount: stripeCount,
-		StripeSize:  stripeSize,
-		StripeIndex: stripeIndex,
-	}, nil
+	return args
 }
 
-// putLargeObjectWithStriping implements parallel writing for large objects
-func (l *LustreEnhancedBackend) putLargeObjectWithStriping(ctx context.Context, input s3response.PutObjectInput, stripeInfo *LustreStripeInfo) (s3response.PutObjectOutput, error) {
-	// For now, delegate to the underlying backend
-	// In a full implementation, this would implement parallel chunk writing
-	// across multiple OSTs based on the stripe configuration
+// getFileStripeInfo gets striping information for a file
+func (l *LustreEnhancedBackend) getFileStripeInfo(ctx context.Context, filePath string) (*LustreStripeInfo, error) {
+	output, err := l.runner.Run(ctx, "lfs", "getstripe", filePath)
+	if err != nil {
+		return nil, fmt.Errorf("lfs getstripe failed: %w", err)
+	}
 
-	// Get the target file path
-	bucket := *input.Bucket
-	key := *input.Key
-	filePath := filepath.Join(bucket, key)
+	return parseLustreLayout(output)
+}
 
-	// Ensure directory has proper striping
-	dir := filepath.Dir(filePath)
-	if err := l.ensureDirectoryStriping(dir, stripeInfo); err != nil {
-		fmt.Printf("Warning: Failed to set directory striping: %v\n", err)
+// parseLustreLayout parses the lfs getstripe layout of a file. The plain
+// layouts are reported as:
+//
+//	lmm_stripe_count:  4
+//	lmm_stripe_size:   1048576
+//	lmm_stripe_offset: 1
+//	lmm_pool:          flash
+//		obdidx		 objid		 objid		 group
+//		     1	             3	          0x3	             0
+//
+// and the composite layouts as a list of components with the lcme_flags
+// and the same lmm_ fields, and the OST objects as:
+//
+//   - 0: { l_ost_idx: 1, l_fid: [0x100010000:0x3:0x0] }
+//
+// The stripe count of a composite layout is the largest stripe count of
+// the instantiated components.
+func parseLustreLayout(out []byte) (*LustreStripeInfo, error) {
+	info := &LustreStripeInfo{StripeIndex: -1}
+	// the plain layouts have no components
+	instantiated := true
+	objects := false
+	found := false
+
+	addOST := func(s string) {
+		ost, err := strconv.Atoi(strings.TrimSpace(s))
+		if err == nil && !slices.Contains(info.OSTs, ost) {
+			info.OSTs = append(info.OSTs, ost)
+		}
 	}
 
-	// For now, use the default backend
-	// TODO: Implement parallel writing across stripes
-	return l.Backend.PutObject(ctx, input)
-}
+	for line := range strings.SplitSeq(string(out), "\n") {
+		line = strings.TrimSpace(line)
 
-// getLargeObjectWithStriping implements parallel reading for large objects
-func (l *LustreEnhancedBackend) getLargeObjectWithStriping(ctx context.Context, input *s3.GetObjectInput, stripeInfo *LustreStripeInfo) (*s3.GetObjectOutput, error) {
-	// Get the file path
-	bucket := *input.Bucket
-	key := *input.Key
-	filePath := filepath.Join(bucket, key)
+		if _, rest, ok := strings.Cut(line, "l_ost_idx:"); ok {
+			if instantiated {
+				ost, _, _ := strings.Cut(rest, ",")
+				addOST(ost)
+			}
+			continue
+		}
 
-	// Open the file
-	file, err := os.Open(filePath)
-	if err != nil {
-		return l.Backend.GetObject(ctx, input)
+		field, value, ok := strings.Cut(line, ":")
+		if !ok {
+			fields := strings.Fields(line)
+			switch {
+			case len(fields) > 0 && fields[0] == "obdidx":
+				objects = true
+			case objects && instantiated && len(fields) == 4:
+				addOST(fields[0])
+			}
+			continue
+		}
+		value = strings.TrimSpace(value)
+
+		switch field {
+		case "lcme_flags":
+			instantiated = strings.Contains(value, "init")
+		case "lmm_stripe_count":
+			if !instantiated {
+				continue
+			}
+			count, err := strconv.Atoi(value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid stripe count: %w", err)
+			}
+			info.StripeCount = max(info.StripeCount, count)
+			found = true
+		case "lmm_stripe_size":
+			if !instantiated {
+				continue
+			}
+			size, err := strconv.ParseInt(value, 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid stripe size: %w", err)
+			}
+			info.StripeSize = max(info.StripeSize, size)
+		case "lmm_stripe_offset":
+			if !instantiated || info.StripeIndex >= 0 {
+				continue
+			}
+			index, err := strconv.Atoi(value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid stripe index: %w", err)
+			}
+			info.StripeIndex = index
+		case "lmm_pool":
+			if instantiated && info.Pool == "" {
+				info.Pool = value
+			}
+		}
 	}
-	defer file.Close()
 
-	// Get file info
-	stat, err := file.Stat()
-	if err != nil {
-		return l.Backend.GetObject(ctx, input)
+	if !found {
+		return nil, fmt.Errorf("unexpected lfs getstripe output")
 	}
 
-	// For now, use default backend
-	// TODO: Implement parallel reading using stripe information
-	// This would involve:
-	// 1. Reading from multiple OSTs in parallel
-	// 2. Assembling the data in correct order
-	// 3. Handling byte ranges properly across stripes
+	return info, nil
+}
 
-	return l.Backend.GetObject(ctx, input)
+// getLargeFileThreshold returns the threshold for considering a file "large"
+func (l *LustreEnhancedBackend) getLargeFileThreshold() int64 {
+	// Default to 10MB
+	threshold := int64(10 * 1024 * 1024)
+
+	// If stripe size is configured, use 2x stripe size as threshold
+	if l.lustreConfig.StripeSize > 0 {
+		threshold = l.lustreConfig.StripeSize * 2
+	}
+
+	return threshold
 }
 
 // Parallel I/O implementation for Lustre striping
 
-// ParallelReader implements parallel reading across Lustre stripes
+// ioWorkers returns the number of parallel reads or writes of the striping
+func ioWorkers(stripeInfo *LustreStripeInfo) int {
+	return min(max(stripeInfo.StripeCount, 1), lustreMaxIOWorkers)
+}
+
+// ioStripeSize returns the stripe size the reads and writes are aligned to
+func ioStripeSize(stripeInfo *LustreStripeInfo) int64 {
+	if stripeInfo.StripeSize > 0 {
+		return stripeInfo.StripeSize
+	}
+	return lustreDefaultStripeSize
+}
+
+// ParallelReader implements parallel reading across Lustre stripes. The
+// stripe size aligned chunks following the chunk being read are read ahead
+// concurrently with pread, one per stripe, so consecutive chunks are read
+// from different OSTs.
 type ParallelReader struct {
+	body       io.Closer
 	file       *os.File
 	stripeInfo *LustreStripeInfo
-	fileSize   int64
-	currentPos int64
-	mu         sync.Mutex
+	workers    int
+	next       int64
+	end        int64
+	pending    []*readChunk
+	cur        *readChunk
+	pos        int
+	wg         sync.WaitGroup
 }
 
-// NewParallelReader creates a new parallel reader for striped files
-func NewParallelReader(file *os.File, stripeInfo *LustreStripeInfo) (*ParallelReader, error) {
-	stat, err := file.Stat()
-	if err != nil {
-		return nil, err
-	}
+// readChunk is a chunk of the file read ahead
+type readChunk struct {
+	done chan struct{}
+	buf  []byte
+	err  error
+}
 
-	return &ParallelReader{
+// NewParallelReader creates a new parallel reader of the length bytes of
+// the file at the offset, closing the body when closed
+func NewParallelReader(body io.Closer, file *os.File, offset, length int64, stripeInfo *LustreStripeInfo) *ParallelReader {
+	pr := &ParallelReader{
+		body:       body,
 		file:       file,
 		stripeInfo: stripeInfo,
-		fileSize:   stat.Size(),
-		currentPos: 0,
-	}, nil
+		workers:    ioWorkers(stripeInfo),
+		next:       offset,
+		end:        offset + length,
+	}
+	pr.readAhead(nil)
+	return pr
+}
+
+// readAhead starts reading the chunks following the pending chunks, up to
+// a chunk per worker, reusing the consumed chunk buffer
+func (pr *ParallelReader) readAhead(buf []byte) {
+	stripeSize := ioStripeSize(pr.stripeInfo)
+
+	for len(pr.pending) < pr.workers && pr.next < pr.end {
+		// the first chunk ends at the stripe boundary
+		size := min(stripeSize-pr.next%stripeSize, pr.end-pr.next)
+		if int64(cap(buf)) < size {
+			buf = make([]byte, stripeSize)
+		}
+
+		c := &readChunk{
+			done: make(chan struct{}),
+			buf:  buf[:size],
+		}
+		buf = nil
+		offset := pr.next
+		pr.next += size
+		pr.pending = append(pr.pending, c)
+
+		pr.wg.Add(1)
+		go func() {
+			defer pr.wg.Done()
+			n, err := pr.file.ReadAt(c.buf, offset)
+			switch {
+			case n == len(c.buf):
+				err = nil
+			case err == nil || errors.Is(err, io.EOF):
+				// the file was truncated since it was opened
+				err = io.ErrUnexpectedEOF
+			}
+			c.err = err
+			close(c.done)
+		}()
+	}
 }
 
 // Read implements io.Reader with parallel stripe reading
 func (pr *ParallelReader) Read(p []byte) (n int, err error) {
-	pr.mu.Lock()
-	defer pr.mu.Unlock()
-
-	if pr.currentPos >= pr.fileSize {
-		return 0, io.EOF
+	if pr.cur == nil {
+		if len(pr.pending) == 0 {
+			return 0, io.EOF
+		}
+		pr.cur = pr.pending[0]
+		pr.pending = pr.pending[1:]
+		pr.pos = 0
+		<-pr.cur.done
+	}
+	if pr.cur.err != nil {
+		return 0, pr.cur.err
 	}
 
-	// Calculate read size
-	remaining := pr.fileSize - pr.currentPos
-	readSize := int64(len(p))
-	if readSize > remaining {
-		readSize = remaining
+	n = copy(p, pr.cur.buf[pr.pos:])
+	pr.pos += n
+	if pr.pos == len(pr.cur.buf) {
+		buf := pr.cur.buf
+		pr.cur = nil
+		pr.readAhead(buf)
 	}
 
-	// For now, use simple sequential read
-	// TODO: Implement parallel reading across stripes
-	n, err = pr.file.ReadAt(p[:readSize], pr.currentPos)
-	pr.currentPos += int64(n)
+	return n, nil
+}
 
-	return n, err
+// Close waits for the running reads and closes the body
+func (pr *ParallelReader) Close() error {
+	pr.wg.Wait()
+	pr.pending = nil
+	return pr.body.Close()
 }
 
-// ParallelWriter implements parallel writing across Lustre stripes
+// ParallelWriter implements parallel writing across Lustre stripes. The
+// data is buffered in stripe size aligned chunks, written concurrently
+// with pwrite, one per stripe, so consecutive chunks are written to
+// different OSTs. The data is hashed by the caller as it is read, before
+// it is written, so the etag and the checksums don't depend on the write
+// order.
 type ParallelWriter struct {
 	file       *os.File
 	stripeInfo *LustreStripeInfo
-	currentPos int64
-	mu         sync.Mutex
+	size       int64
+	written    int64
+	offset     int64
+	buf        []byte
+	free       chan []byte
+	chunks     chan writeChunk
+	wg         sync.WaitGroup
+	closed     bool
+
+	mu  sync.Mutex
+	err error
+}
+
+// writeChunk is a chunk of the data written at the file offset
+type writeChunk struct {
+	buf    []byte
+	offset int64
 }
 
-// NewParallelWriter creates a new parallel writer for striped files
-func NewParallelWriter(file *os.File, stripeInfo *LustreStripeInfo) *ParallelWriter {
-	return &ParallelWriter{
+// NewParallelWriter creates a new parallel writer of the size bytes
+// written to the file
+func NewParallelWriter(file *os.File, size int64, stripeInfo *LustreStripeInfo) *ParallelWriter {
+	workers := ioWorkers(stripeInfo)
+	pw := &ParallelWriter{
 		file:       file,
 		stripeInfo: stripeInfo,
-		currentPos: 0,
+		size:       size,
+		// a chunk is filled while the workers write the others, the
+		// buffers are allocated when first used
+		free:   make(chan []byte, workers+1),
+		chunks: make(chan writeChunk, workers),
 	}
+	for range workers + 1 {
+		pw.free <- nil
+	}
+
+	pw.wg.Add(workers)
+	for range workers {
+		go pw.worker()
+	}
+	return pw
 }
 
-// Write implements io.Writer with parallel stripe writing
-func (pw *ParallelWriter) Write(p []byte) (n int, err error) {
+func (pw *ParallelWriter) worker() {
+	defer pw.wg.Done()
+	for c := range pw.chunks {
+		if pw.firstErr() == nil {
+			_, err := pw.file.WriteAt(c.buf, c.offset)
+			if err != nil {
+				pw.setErr(err)
+			}
+		}
+		pw.free <- c.buf[:0]
+	}
+}
+
+func (pw *ParallelWriter) firstErr() error {
 	pw.mu.Lock()
 	defer pw.mu.Unlock()
-
-	// For now, use simple sequential write
-	// TODO: Implement parallel writing across stripes
-	n, err = pw.file.WriteAt(p, pw.currentPos)
-	pw.currentPos += int64(n)
-
-	return n, err
+	return pw.err
 }
 
-// Advanced parallel I/O implementation
-
-// parallelReadStripes reads data from multiple stripes in parallel
-func (l *LustreEnhancedBackend) parallelReadStripes(file *os.File, offset, size int64, stripeInfo *LustreStripeInfo) ([]byte, error) {
-	if stripeInfo.StripeCount <= 1 {
-		// Not striped, use regular read
-		data := make([]byte, size)
-		_, err := file.ReadAt(data, offset)
-		return data, err
+func (pw *ParallelWriter) setErr(err error) {
+	pw.mu.Lock()
+	defer pw.mu.Unlock()
+	if pw.err == nil {
+		pw.err = err
 	}
+}
 
-	// Calculate stripe-aligned chunks
-	chunks := l.calculateStripeChunks(offset, size, stripeInfo)
-
-	// Read chunks in parallel
-	results := make([][]byte, len(chunks))
-	errors := make([]error, len(chunks))
-	var wg sync.WaitGroup
-
-	// Limit concurrency
-	maxConcurrency := runtime.NumCPU()
-	if maxConcurrency > stripeInfo.StripeCount {
-		maxConcurrency = stripeInfo.StripeCount
+// Write implements io.Writer with parallel stripe writing, the write
+// errors are returned by the following writes and by Close
+func (pw *ParallelWriter) Write(p []byte) (n int, err error) {
+	if err := pw.firstErr(); err != nil {
+		return 0, err
 	}
-
-	sem := make(chan struct{}, maxConcurrency)
-
-	for i, chunk := range chunks {
-		wg.Add(1)
-		go func(index int, c StripeChunk) {
-			defer wg.Done()
-			sem <- struct{}{}
-			defer func() { <-sem }()
-
-			data := make([]byte, c.Size)
-			_, err := file.ReadAt(data, c.Offset)
-			results[index] = data
-			errors[index] = err
-		}(i, chunk)
+	if int64(len(p)) > pw.size-pw.written {
+		return 0, fmt.Errorf("write exceeds content length %v", pw.size)
 	}
 
-	wg.Wait()
-
-	// Check for errors
-	for _, err := range errors {
-		if err != nil {
-			return nil, err
+	stripeSize := ioStripeSize(pw.stripeInfo)
+	for len(p) > 0 {
+		if pw.buf == nil {
+			pw.buf = <-pw.free
+			if pw.buf == nil {
+				pw.buf = make([]byte, 0, stripeSize)
+			}
 		}
-	}
 
-	// Combine results
-	totalSize := int64(0)
-	for _, chunk := range chunks {
-		totalSize += chunk.Size
-	}
+		c := copy(pw.buf[len(pw.buf):cap(pw.buf)], p)
+		pw.buf = pw.buf[:len(pw.buf)+c]
+		p = p[c:]
+		n += c
 
-	result := make([]byte, 0, totalSize)
-	for _, data := range results {
-		result = append(result, data...)
+		if len(pw.buf) == cap(pw.buf) {
+			pw.flush()
+		}
 	}
 
-	return result, nil
+	pw.written += int64(n)
+	return n, nil
 }
 
-// StripeChunk represents a chunk of data within a stripe
-type StripeChunk struct {
-	Offset int64
-	Size   int64
-	Stripe int
+// flush queues the buffered chunk to be written
+func (pw *ParallelWriter) flush() {
+	pw.chunks <- writeChunk{buf: pw.buf, offset: pw.offset}
+	pw.offset += int64(len(pw.buf))
+	pw.buf = nil
 }
 
-// calculateStripeChunks calculates the chunks to read based on striping
-func (l *LustreEnhancedBackend) calculateStripeChunks(offset, size int64, stripeInfo *LustreStripeInfo) []StripeChunk {
-	var chunks []StripeChunk
-
-	stripeSize := stripeInfo.StripeSize
-	stripeCount := int64(stripeInfo.StripeCount)
-
-	currentOffset := offset
-	remaining := size
-
-	for remaining > 0 {
-		// Calculate which stripe this offset belongs to
-		stripeIndex := (currentOffset / stripeSize) % stripeCount
-
-		// Calculate offset within the stripe
-		stripeOffset := currentOffset % stripeSize
-
-		// Calculate how much to read from this stripe
-		chunkSize := stripeSize - stripeOffset
-		if chunkSize > remaining {
-			chunkSize = remaining
-		}
-
-		chunks = append(chunks, StripeChunk{
-			Offset: currentOffset,
-			Size:   chunkSize,
-			Stripe: int(stripeIndex),
-		})
-
-		currentOffset += chunkSize
-		remaining -= chunkSize
+// Close writes the buffered data, waits for the running writes and returns
+// the first write error
+func (pw *ParallelWriter) Close() error {
+	if pw.closed {
+		return pw.firstErr()
 	}
+	pw.closed = true
 
-	return chunks
-}
-
-// getLargeFileThreshold returns the threshold for considering a file "large"
-func (l *LustreEnhancedBackend) getLargeFileThreshold() int64 {
-	// Default to 10MB
-	threshold := int64(10 * 1024 * 1024)
-
-	// If stripe size is configured, use 2x stripe size as threshold
-	if l.lustreConfig.StripeSize > 0 {
-		threshold = l.lustreConfig.StripeSize * 2
+	if len(pw.buf) > 0 {
+		pw.flush()
 	}
+	close(pw.chunks)
+	pw.wg.Wait()
 
-	return threshold
+	return pw.firstErr()
 }
 
 // Lustre-specific utilities
 
 // GetLustreFileStats returns Lustre-specific file statistics
 func (l *LustreEnhancedBackend) GetLustreFileStats(filePath string) (*LustreStripeInfo, error) {
-	return l.getFileStripeInfo(filePath)
+	return l.getFileStripeInfo(context.Background(), filePath)
 }
 
 // SetLustreFileStriping sets striping for a specific file
@@ -484,7 +791,7 @@ func (l *LustreEnhancedBackend) SetLustreFileStriping(filePath string, stripeInf
 
 	// Set striping for the directory
 	dir := filepath.Dir(filePath)
-	return l.ensureDirectoryStriping(dir, stripeInfo)
+	return l.setStripe(context.Background(), dir, stripeArgs(stripeInfo))
 }
 
 // LustrePoolManager manages Lustre OST pools
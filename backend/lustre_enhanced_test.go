@@ -0,0 +1,408 @@
+// Copyright 2026 Versity Software
+// This file is licensed under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package backend_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"testing/iotest"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/versity/versitygw/backend"
+	"github.com/versity/versitygw/s3response"
+)
+
+const plainLayout = `/mnt/lustre/bucket/obj
+lmm_stripe_count:  4
+lmm_stripe_size:   4096
+lmm_pattern:       raid0
+lmm_layout_gen:    0
+lmm_stripe_offset: 1
+lmm_pool:          flash
+	obdidx		 objid		 objid		 group
+	     1	             3	          0x3	             0
+	     2	             3	          0x3	             0
+	     3	             3	          0x3	             0
+	     0	             4	          0x4	             0
+`
+
+const compositeLayout = `/mnt/lustre/bucket/obj
+  lcm_layout_gen:    3
+  lcm_mirror_count:  1
+  lcm_entry_count:   3
+    lcme_id:             1
+    lcme_mirror_id:      0
+    lcme_flags:          init
+    lcme_extent.e_start: 0
+    lcme_extent.e_end:   1048576
+      lmm_stripe_count:  1
+      lmm_stripe_size:   1048576
+      lmm_pattern:       raid0
+      lmm_layout_gen:    0
+      lmm_stripe_offset: 0
+      lmm_objects:
+      - 0: { l_ost_idx: 0, l_fid: [0x100000000:0x2:0x0] }
+
+    lcme_id:             2
+    lcme_mirror_id:      0
+    lcme_flags:          init
+    lcme_extent.e_start: 1048576
+    lcme_extent.e_end:   104857600
+      lmm_stripe_count:  2
+      lmm_stripe_size:   1048576
+      lmm_pattern:       raid0
+      lmm_layout_gen:    0
+      lmm_stripe_offset: 1
+      lmm_objects:
+      - 0: { l_ost_idx: 1, l_fid: [0x100010000:0x2:0x0] }
+      - 1: { l_ost_idx: 2, l_fid: [0x100020000:0x2:0x0] }
+
+    lcme_id:             3
+    lcme_mirror_id:      0
+    lcme_flags:          0
+    lcme_extent.e_start: 104857600
+    lcme_extent.e_end:   EOF
+      lmm_stripe_count:  8
+      lmm_stripe_size:   1048576
+      lmm_pattern:       raid0
+      lmm_layout_gen:    0
+      lmm_stripe_offset: -1
+`
+
+// layoutLfs emulates lfs getstripe and records the lfs commands
+type layoutLfs struct {
+	layout string
+	calls  [][]string
+}
+
+func (f *layoutLfs) Run(_ context.Context, name string, args ...string) ([]byte, error) {
+	f.calls = append(f.calls, append([]string{name}, args...))
+	if len(args) > 0 && args[0] == "getstripe" {
+		return []byte(f.layout), nil
+	}
+	return nil, nil
+}
+
+// layoutBackend serves the object data of a single file
+type layoutBackend struct {
+	backend.BackendUnsupported
+	path string
+}
+
+func (b *layoutBackend) CreateBucket(context.Context, *s3.CreateBucketInput, []byte) error {
+	return nil
+}
+
+func (b *layoutBackend) GetObject(_ context.Context, input *s3.GetObjectInput) (*s3.GetObjectOutput, error) {
+	f, err := os.Open(b.path)
+	if err != nil {
+		return nil, err
+	}
+	fi, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+
+	length := fi.Size()
+	var body io.ReadCloser = f
+	if input.Range != nil {
+		// bytes=1000-
+		length -= 1000
+		body = &backend.FileSectionReadCloser{R: io.NewSectionReader(f, 1000, length), F: f}
+	}
+	return &s3.GetObjectOutput{ContentLength: &length, Body: body}, nil
+}
+
+func (b *layoutBackend) GetObjectAttributes(context.Context, *s3.GetObjectAttributesInput) (s3response.GetObjectAttributesResponse, error) {
+	return s3response.GetObjectAttributesResponse{}, nil
+}
+
+func randomData(t *testing.T, size int) []byte {
+	t.Helper()
+	data := make([]byte, size)
+	_, err := rand.Read(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func TestParallelWriter(t *testing.T) {
+	stripeInfo := &backend.LustreStripeInfo{StripeCount: 4, StripeSize: 4096}
+
+	for _, size := range []int{1, 4096, 4097, 5*4096 + 123, 64*4096 - 1} {
+		f, err := os.CreateTemp(t.TempDir(), "obj")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer f.Close()
+
+		data := randomData(t, size)
+		pw := backend.NewParallelWriter(f, int64(size), stripeInfo)
+		// the odd sized writes don't align to the stripes
+		_, err = io.CopyBuffer(pw, iotest.OneByteReader(bytes.NewReader(data[:size/2])), make([]byte, 1))
+		if err == nil {
+			_, err = io.CopyBuffer(pw, bytes.NewReader(data[size/2:]), make([]byte, 1000))
+		}
+		if err != nil {
+			t.Fatalf("size %v: write: %v", size, err)
+		}
+		err = pw.Close()
+		if err != nil {
+			t.Fatalf("size %v: close: %v", size, err)
+		}
+
+		got, err := os.ReadFile(f.Name())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(got, data) {
+			t.Fatalf("size %v: written data mismatch", size)
+		}
+	}
+}
+
+func TestParallelWriterExceedsSize(t *testing.T) {
+	f, err := os.CreateTemp(t.TempDir(), "obj")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	pw := backend.NewParallelWriter(f, 10, &backend.LustreStripeInfo{StripeCount: 2, StripeSize: 4})
+	_, err = pw.Write(make([]byte, 8))
+	if err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	_, err = pw.Write(make([]byte, 3))
+	if err == nil {
+		t.Fatal("expected write exceeding the size to fail")
+	}
+	if err := pw.Close(); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+}
+
+func TestParallelReader(t *testing.T) {
+	data := randomData(t, 37*4096+17)
+	path := filepath.Join(t.TempDir(), "obj")
+	err := os.WriteFile(path, data, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	stripeInfo := &backend.LustreStripeInfo{StripeCount: 4, StripeSize: 4096}
+	for _, section := range [][2]int{{0, len(data)}, {1000, 20000}, {4096, 4096}, {5, 0}, {len(data) - 1, 1}} {
+		f, err := os.Open(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		off, length := section[0], section[1]
+		pr := backend.NewParallelReader(f, f, int64(off), int64(length), stripeInfo)
+		got, err := io.ReadAll(iotest.HalfReader(pr))
+		if err != nil {
+			t.Fatalf("section %v: read: %v", section, err)
+		}
+		if !bytes.Equal(got, data[off:off+length]) {
+			t.Fatalf("section %v: read data mismatch", section)
+		}
+		if err := pr.Close(); err != nil {
+			t.Fatalf("section %v: close: %v", section, err)
+		}
+	}
+}
+
+func TestParallelReaderTruncated(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "obj")
+	err := os.WriteFile(path, make([]byte, 10000), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pr := backend.NewParallelReader(f, f, 0, 20000, &backend.LustreStripeInfo{StripeCount: 2, StripeSize: 4096})
+	_, err = io.ReadAll(pr)
+	if err != io.ErrUnexpectedEOF {
+		t.Fatalf("expected unexpected EOF, got %v", err)
+	}
+	pr.Close()
+}
+
+func TestLustreGetObjectParallel(t *testing.T) {
+	data := randomData(t, 100*1024)
+	path := filepath.Join(t.TempDir(), "obj")
+	err := os.WriteFile(path, data, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	lfs := &layoutLfs{layout: plainLayout}
+	l := backend.NewLustreEnhancedBackendWithRunner(&layoutBackend{path: path}, "/mnt/lustre",
+		&backend.LustreConfig{StripeSize: 4096}, lfs)
+
+	tests := []struct {
+		name  string
+		rng   *string
+		bytes []byte
+	}{
+		{"full object", nil, data},
+		{"range", aws.String("bytes=1000-"), data[1000:]},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := l.GetObject(context.Background(), &s3.GetObjectInput{
+				Bucket: aws.String("bucket"),
+				Key:    aws.String("obj"),
+				Range:  tt.rng,
+			})
+			if err != nil {
+				t.Fatalf("get object: %v", err)
+			}
+			defer res.Body.Close()
+
+			if _, ok := res.Body.(*backend.ParallelReader); !ok {
+				t.Fatalf("expected parallel reader, got %T", res.Body)
+			}
+			got, err := io.ReadAll(res.Body)
+			if err != nil {
+				t.Fatalf("read: %v", err)
+			}
+			if !bytes.Equal(got, tt.bytes) {
+				t.Fatal("read data mismatch")
+			}
+		})
+	}
+
+	if !slices.Equal(lfs.calls[0], []string{"lfs", "getstripe", "/mnt/lustre/bucket/obj"}) {
+		t.Errorf("unexpected lfs command %v", lfs.calls[0])
+	}
+}
+
+func TestLustreGetObjectAttributesLayout(t *testing.T) {
+	tests := []struct {
+		name   string
+		layout string
+		expect s3response.ObjectLayout
+	}{
+		{"plain", plainLayout, s3response.ObjectLayout{StripeCount: 4, StripeSize: 4096, Pool: "flash"}},
+		{"composite", compositeLayout, s3response.ObjectLayout{StripeCount: 2, StripeSize: 1048576}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := backend.NewLustreEnhancedBackendWithRunner(&layoutBackend{}, "/mnt/lustre",
+				&backend.LustreConfig{}, &layoutLfs{layout: tt.layout})
+
+			res, err := l.GetObjectAttributes(context.Background(), &s3.GetObjectAttributesInput{
+				Bucket: aws.String("bucket"),
+				Key:    aws.String("obj"),
+			})
+			if err != nil {
+				t.Fatalf("get object attributes: %v", err)
+			}
+			if res.Layout == nil || *res.Layout != tt.expect {
+				t.Fatalf("expected layout %+v, got %+v", tt.expect, res.Layout)
+			}
+
+			stats, err := l.GetLustreFileStats("/mnt/lustre/bucket/obj")
+			if err != nil {
+				t.Fatalf("get file stats: %v", err)
+			}
+			if len(stats.OSTs) == 0 || stats.OSTs[0] != stats.StripeIndex {
+				t.Errorf("expected OSTs from stripe index %v, got %v", stats.StripeIndex, stats.OSTs)
+			}
+		})
+	}
+}
+
+func TestLustreBucketLayout(t *testing.T) {
+	root := t.TempDir()
+	for _, dir := range []string{"bucket", "bucket/.sgwtmp", "other"} {
+		if err := os.Mkdir(filepath.Join(root, dir), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	lfs := &layoutLfs{}
+	l := backend.NewLustreEnhancedBackendWithRunner(&layoutBackend{}, root, &backend.LustreConfig{
+		StripeCount: 4,
+		Pool:        "default",
+		BucketPools: map[string]string{"bucket": "flash"},
+	}, lfs)
+
+	ctx := context.Background()
+	for _, bucket := range []string{"bucket", "other"} {
+		err := l.CreateBucket(ctx, &s3.CreateBucketInput{Bucket: aws.String(bucket)}, nil)
+		if err != nil {
+			t.Fatalf("create bucket: %v", err)
+		}
+	}
+
+	// the layout is only set once per bucket
+	_, err := l.UploadPart(ctx, &s3.UploadPartInput{Bucket: aws.String("bucket")})
+	if err == nil {
+		t.Fatal("expected unsupported upload part")
+	}
+
+	layout := func(pool string) []string {
+		return []string{"setstripe",
+			"-E", "1048576", "-c", "1", "-S", "1048576", "-p", pool,
+			"-E", "104857600", "-c", "2", "-S", "1048576", "-p", pool,
+			"-E", "-1", "-c", "4", "-S", "1048576", "-p", pool,
+		}
+	}
+	expected := [][]string{
+		append(append([]string{"lfs"}, layout("flash")...), filepath.Join(root, "bucket")),
+		append(append([]string{"lfs"}, layout("flash")...), filepath.Join(root, "bucket", ".sgwtmp")),
+		append(append([]string{"lfs"}, layout("default")...), filepath.Join(root, "other")),
+	}
+	if !slices.EqualFunc(lfs.calls, expected, slices.Equal) {
+		t.Fatalf("expected lfs commands\n%v\ngot\n%v", expected, lfs.calls)
+	}
+}
+
+func TestLustreNewDataWriter(t *testing.T) {
+	l := backend.NewLustreEnhancedBackendWithRunner(&layoutBackend{}, "/mnt/lustre",
+		&backend.LustreConfig{StripeSize: 1024 * 1024}, &layoutLfs{})
+
+	if w := l.NewDataWriter(nil, "bucket", 1024*1024); w != nil {
+		t.Errorf("expected small uploads to be written sequentially")
+	}
+
+	f, err := os.CreateTemp(t.TempDir(), "obj")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	w := l.NewDataWriter(f, "bucket", 200*1024*1024)
+	if w == nil {
+		t.Fatal("expected large uploads to be written in parallel")
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+}
@@ -0,0 +1,398 @@
+// Copyright 2026 Versity Software
+// This file is licensed under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package backend
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/versity/versitygw/s3err"
+	"github.com/versity/versitygw/s3response"
+)
+
+// Lustre HSM behavior, when enabled in the LustreConfig:
+//
+// HEAD object: if file released, set obj storage class to GLACIER
+//              if file released and restoring, x-amz-restore: ongoing-request="true"
+//              if file released and not restoring, x-amz-restore: ongoing-request="false"
+//              if file archived and online, x-amz-restore: ongoing-request="false", expiry-date="Fri, 2 Dec 2050 00:00:00 GMT"
+// GET object:  if file released, return InvalidObjectState
+// ListObjects: if file released, set obj storage class to GLACIER
+// RestoreObject: request hsm_restore of the file if released
+// TransitionObject: request hsm_archive of the file, and hsm_release once archived
+//
+// The object versions are resolved to the version files by the wrapped
+// backend, see objectVersionPather.
+
+const (
+	hsmRestoreComplete      = "ongoing-request=\"false\", expiry-date=\"Fri, 2 Dec 2050 00:00:00 GMT\""
+	hsmRestoreInProgress    = "ongoing-request=\"true\""
+	hsmRestoreNotInProgress = "ongoing-request=\"false\""
+
+	// hsmStateBatch is the number of files queried per lfs hsm_state
+	// command when listing objects
+	hsmStateBatch = 256
+)
+
+// LustreCommandRunner runs the Lustre utilities, the default runner
+// executes the commands, tests can replace it with a fake lfs
+type LustreCommandRunner interface {
+	// Run runs the command and returns its standard output, the output
+	// is returned along with the error if the command fails
+	Run(ctx context.Context, name string, args ...string) ([]byte, error)
+}
+
+type execCommandRunner struct{}
+
+func (execCommandRunner) Run(ctx context.Context, name string, args ...string) ([]byte, error) {
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return out, fmt.Errorf("%v: %w: %v", strings.Join(append([]string{name}, args...), " "),
+			err, strings.TrimSpace(stderr.String()))
+	}
+	return out, nil
+}
+
+// hsmState is the HSM state of a file reported by lfs hsm_state
+type hsmState struct {
+	exists    bool
+	archived  bool
+	released  bool
+	dirty     bool
+	lost      bool
+	archiveID int
+}
+
+// parseHSMStates parses the lfs hsm_state output lines in the form:
+// <path>: (0x0000000d) released exists archived, archive_id:1
+func parseHSMStates(out []byte) map[string]hsmState {
+	states := make(map[string]hsmState)
+	for line := range strings.SplitSeq(string(out), "\n") {
+		idx := strings.LastIndex(line, ": (0x")
+		if idx < 0 {
+			continue
+		}
+		path := line[:idx]
+		fields := strings.Fields(strings.ReplaceAll(line[idx+2:], ",", " "))
+
+		var st hsmState
+		for _, f := range fields[1:] {
+			switch {
+			case f == "exists":
+				st.exists = true
+			case f == "archived":
+				st.archived = true
+			case f == "released":
+				st.released = true
+			case f == "dirty":
+				st.dirty = true
+			case f == "lost":
+				st.lost = true
+			case strings.HasPrefix(f, "archive_id:"):
+				st.archiveID, _ = strconv.Atoi(strings.TrimPrefix(f, "archive_id:"))
+			}
+		}
+		states[path] = st
+	}
+	return states
+}
+
+// hsmEnabled reports if the Lustre HSM integration is enabled
+func (l *LustreEnhancedBackend) hsmEnabled() bool {
+	return l.lustreConfig != nil && l.lustreConfig.HSM
+}
+
+// objectPath returns the filesystem path of the object
+func (l *LustreEnhancedBackend) objectPath(bucket, object string) string {
+	return filepath.Join(l.root, bucket, object)
+}
+
+// objectVersionPather is implemented by the backends storing the object
+// versions in files, posix stores the older versions outside the bucket
+type objectVersionPather interface {
+	ObjectVersionPath(bucket, object, versionId string) (string, error)
+}
+
+// versionPath returns the filesystem path of the object version, or the
+// object path if no version is requested
+func (l *LustreEnhancedBackend) versionPath(bucket, object string, versionId *string) (string, error) {
+	if versionId == nil || *versionId == "" {
+		return l.objectPath(bucket, object), nil
+	}
+	vp, ok := l.Backend.(objectVersionPather)
+	if !ok {
+		return "", s3err.GetAPIError(s3err.ErrNotImplemented)
+	}
+	return vp.ObjectVersionPath(bucket, object, *versionId)
+}
+
+// hsmState returns the HSM state of the file
+func (l *LustreEnhancedBackend) hsmState(ctx context.Context, path string) (hsmState, error) {
+	out, err := l.runner.Run(ctx, "lfs", "hsm_state", path)
+	if err != nil {
+		return hsmState{}, fmt.Errorf("lfs hsm_state: %w", err)
+	}
+	st, ok := parseHSMStates(out)[path]
+	if !ok {
+		return hsmState{}, fmt.Errorf("unexpected lfs hsm_state output: %q", out)
+	}
+	return st, nil
+}
+
+// isRestoring reports if a restore of the file is waiting or running,
+// lfs hsm_action prints the current action in the form:
+// <path>: RESTORE running (from 0 to EOF)
+func (l *LustreEnhancedBackend) isRestoring(ctx context.Context, path string) (bool, error) {
+	out, err := l.runner.Run(ctx, "lfs", "hsm_action", path)
+	if err != nil {
+		return false, fmt.Errorf("lfs hsm_action: %w", err)
+	}
+	action, ok := strings.CutPrefix(strings.TrimSpace(string(out)), path+": ")
+	if !ok {
+		return false, fmt.Errorf("unexpected lfs hsm_action output: %q", out)
+	}
+	return strings.HasPrefix(action, "RESTORE"), nil
+}
+
+// HeadObject sets the GLACIER storage class and the restore status of
+// the released files
+func (l *LustreEnhancedBackend) HeadObject(ctx context.Context, input *s3.HeadObjectInput) (*s3.HeadObjectOutput, error) {
+	res, err := l.Backend.HeadObject(ctx, input)
+	if err != nil || !l.hsmEnabled() || strings.HasSuffix(*input.Key, "/") {
+		return res, err
+	}
+
+	path, err := l.versionPath(*input.Bucket, *input.Key, input.VersionId)
+	if err != nil {
+		return nil, err
+	}
+	st, err := l.hsmState(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case st.released:
+		restore := hsmRestoreNotInProgress
+		ok, err := l.isRestoring(ctx, path)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			restore = hsmRestoreInProgress
+		}
+		res.StorageClass = types.StorageClassGlacier
+		res.Restore = &restore
+	case st.archived:
+		restore := hsmRestoreComplete
+		res.Restore = &restore
+	}
+
+	return res, nil
+}
+
+// checkReleased returns InvalidObjectState if the file is released, the
+// released files must be restored before these are read
+func (l *LustreEnhancedBackend) checkReleased(ctx context.Context, bucket, object string, versionId *string) error {
+	if !l.hsmEnabled() || strings.HasSuffix(object, "/") {
+		return nil
+	}
+
+	_, err := l.Backend.HeadObject(ctx, &s3.HeadObjectInput{
+		Bucket:    &bucket,
+		Key:       &object,
+		VersionId: versionId,
+	})
+	if err != nil {
+		return err
+	}
+
+	path, err := l.versionPath(bucket, object, versionId)
+	if err != nil {
+		return err
+	}
+	st, err := l.hsmState(ctx, path)
+	if err != nil {
+		return err
+	}
+	if st.released {
+		return s3err.GetAPIError(s3err.ErrInvalidObjectState)
+	}
+	return nil
+}
+
+// ListObjects sets the GLACIER storage class of the released files
+func (l *LustreEnhancedBackend) ListObjects(ctx context.Context, input *s3.ListObjectsInput) (s3response.ListObjectsResult, error) {
+	res, err := l.Backend.ListObjects(ctx, input)
+	if err != nil || !l.hsmEnabled() {
+		return res, err
+	}
+
+	err = l.setReleasedStorageClass(ctx, *input.Bucket, res.Contents)
+	if err != nil {
+		return s3response.ListObjectsResult{}, err
+	}
+	return res, nil
+}
+
+// ListObjectsV2 sets the GLACIER storage class of the released files
+func (l *LustreEnhancedBackend) ListObjectsV2(ctx context.Context, input *s3.ListObjectsV2Input) (s3response.ListObjectsV2Result, error) {
+	res, err := l.Backend.ListObjectsV2(ctx, input)
+	if err != nil || !l.hsmEnabled() {
+		return res, err
+	}
+
+	err = l.setReleasedStorageClass(ctx, *input.Bucket, res.Contents)
+	if err != nil {
+		return s3response.ListObjectsV2Result{}, err
+	}
+	return res, nil
+}
+
+// setReleasedStorageClass queries the HSM state of the listed files in
+// batches, and sets the GLACIER storage class of the released files. The
+// files removed since these were listed are missing from the lfs output
+// and are left unchanged.
+func (l *LustreEnhancedBackend) setReleasedStorageClass(ctx context.Context, bucket string, objs []s3response.Object) error {
+	idx := make(map[string]int, len(objs))
+	paths := make([]string, 0, len(objs))
+	for i, obj := range objs {
+		if obj.Key == nil || strings.HasSuffix(*obj.Key, "/") {
+			continue
+		}
+		path := l.objectPath(bucket, *obj.Key)
+		idx[path] = i
+		paths = append(paths, path)
+	}
+
+	for len(paths) > 0 {
+		batch := paths[:min(len(paths), hsmStateBatch)]
+		paths = paths[len(batch):]
+
+		out, err := l.runner.Run(ctx, "lfs", append([]string{"hsm_state"}, batch...)...)
+		states := parseHSMStates(out)
+		if err != nil && len(states) == 0 {
+			return fmt.Errorf("lfs hsm_state: %w", err)
+		}
+		for path, st := range states {
+			i, ok := idx[path]
+			if ok && st.released {
+				objs[i].StorageClass = types.ObjectStorageClassGlacier
+			}
+		}
+	}
+
+	return nil
+}
+
+// RestoreObject requests the restore of the file from the HSM archive if
+// the file is released, and does nothing if the file is online
+func (l *LustreEnhancedBackend) RestoreObject(ctx context.Context, input *s3.RestoreObjectInput) error {
+	if !l.hsmEnabled() {
+		return l.Backend.RestoreObject(ctx, input)
+	}
+
+	_, err := l.Backend.HeadObject(ctx, &s3.HeadObjectInput{
+		Bucket:    input.Bucket,
+		Key:       input.Key,
+		VersionId: input.VersionId,
+	})
+	if err != nil {
+		return err
+	}
+
+	path, err := l.versionPath(*input.Bucket, *input.Key, input.VersionId)
+	if err != nil {
+		return err
+	}
+	st, err := l.hsmState(ctx, path)
+	if err != nil {
+		return err
+	}
+	if !st.released {
+		return nil
+	}
+
+	_, err = l.runner.Run(ctx, "lfs", "hsm_restore", path)
+	if err != nil {
+		return fmt.Errorf("lfs hsm_restore: %w", err)
+	}
+
+	return nil
+}
+
+// TransitionObject transitions the object to the HSM archive. The archive
+// is asynchronous, so the file is released once the HSM state reports the
+// archive is complete: the first call requests the archive of the file,
+// and a later call releases the archived file. The admin transition-object
+// request is repeated until the file is released.
+func (l *LustreEnhancedBackend) TransitionObject(ctx context.Context, bucket, object, versionId string) error {
+	if !l.hsmEnabled() {
+		return s3err.GetAPIError(s3err.ErrNotImplemented)
+	}
+	if strings.HasSuffix(object, "/") {
+		return s3err.GetAPIError(s3err.ErrInvalidObjectState)
+	}
+
+	vId := GetPtrFromString(versionId)
+	_, err := l.Backend.HeadObject(ctx, &s3.HeadObjectInput{
+		Bucket:    &bucket,
+		Key:       &object,
+		VersionId: vId,
+	})
+	if err != nil {
+		return err
+	}
+
+	path, err := l.versionPath(bucket, object, vId)
+	if err != nil {
+		return err
+	}
+	st, err := l.hsmState(ctx, path)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case st.released:
+		return nil
+	case !st.archived || st.dirty:
+		args := []string{"hsm_archive"}
+		if l.lustreConfig.HSMArchiveID > 0 {
+			args = append(args, "--archive", strconv.Itoa(l.lustreConfig.HSMArchiveID))
+		}
+		_, err = l.runner.Run(ctx, "lfs", append(args, path)...)
+		if err != nil {
+			return fmt.Errorf("lfs hsm_archive: %w", err)
+		}
+	default:
+		_, err = l.runner.Run(ctx, "lfs", "hsm_release", path)
+		if err != nil {
+			return fmt.Errorf("lfs hsm_release: %w", err)
+		}
+	}
+
+	return nil
+}
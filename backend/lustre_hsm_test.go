@@ -0,0 +1,382 @@
+// Copyright 2026 Versity Software
+// This file is licensed under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package backend_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/versity/versitygw/backend"
+	"github.com/versity/versitygw/s3err"
+	"github.com/versity/versitygw/s3response"
+)
+
+const (
+	hsmRoot        = "/mnt/lustre"
+	hsmVersionsDir = "/mnt/lustre-versions"
+)
+
+// fakeLfs emulates the lfs HSM commands with the HSM state flags of the
+// files, the archive completes immediately
+type fakeLfs struct {
+	states    map[string][]string
+	restoring map[string]bool
+	calls     [][]string
+}
+
+func (f *fakeLfs) has(path, flag string) bool {
+	return slices.Contains(f.states[path], flag)
+}
+
+func (f *fakeLfs) Run(_ context.Context, name string, args ...string) ([]byte, error) {
+	f.calls = append(f.calls, append([]string{name}, args...))
+	if name != "lfs" || len(args) < 2 {
+		return nil, fmt.Errorf("unexpected command %v %v", name, args)
+	}
+
+	path := args[len(args)-1]
+	switch args[0] {
+	case "hsm_state":
+		var out strings.Builder
+		for _, p := range args[1:] {
+			flags, ok := f.states[p]
+			if !ok {
+				continue
+			}
+			fmt.Fprintf(&out, "%v: (0x%08x) %v", p, len(flags), strings.Join(flags, " "))
+			if len(flags) > 0 {
+				out.WriteString(", archive_id:1")
+			}
+			out.WriteString("\n")
+		}
+		return []byte(out.String()), nil
+	case "hsm_action":
+		if f.restoring[path] {
+			return []byte(path + ": RESTORE running (from 0 to EOF)\n"), nil
+		}
+		return []byte(path + ": NOOP\n"), nil
+	case "hsm_restore":
+		f.restoring[path] = true
+	case "hsm_archive":
+		f.states[path] = []string{"exists", "archived"}
+	case "hsm_release":
+		if !f.has(path, "archived") {
+			return nil, errors.New("Operation not permitted")
+		}
+		f.states[path] = []string{"released", "exists", "archived"}
+	default:
+		return nil, fmt.Errorf("unexpected lfs command %v", args[0])
+	}
+	return nil, nil
+}
+
+func (f *fakeLfs) commands(cmd string) int {
+	n := 0
+	for _, c := range f.calls {
+		if c[1] == cmd {
+			n++
+		}
+	}
+	return n
+}
+
+// hsmBackend serves the objects of a single bucket
+type hsmBackend struct {
+	backend.BackendUnsupported
+	keys []string
+}
+
+func (b *hsmBackend) HeadObject(_ context.Context, input *s3.HeadObjectInput) (*s3.HeadObjectOutput, error) {
+	if !slices.Contains(b.keys, *input.Key) {
+		return nil, s3err.GetAPIError(s3err.ErrNoSuchKey)
+	}
+	return &s3.HeadObjectOutput{StorageClass: types.StorageClassStandard}, nil
+}
+
+func (b *hsmBackend) GetObject(_ context.Context, input *s3.GetObjectInput) (*s3.GetObjectOutput, error) {
+	if !slices.Contains(b.keys, *input.Key) {
+		return nil, s3err.GetAPIError(s3err.ErrNoSuchKey)
+	}
+	return &s3.GetObjectOutput{}, nil
+}
+
+func (b *hsmBackend) ListObjectsV2(context.Context, *s3.ListObjectsV2Input) (s3response.ListObjectsV2Result, error) {
+	var res s3response.ListObjectsV2Result
+	for _, key := range b.keys {
+		res.Contents = append(res.Contents, s3response.Object{
+			Key:          aws.String(key),
+			StorageClass: types.ObjectStorageClassStandard,
+		})
+	}
+	return res, nil
+}
+
+// ObjectVersionPath stores the object versions outside the bucket, as
+// the posix versioning directory
+func (b *hsmBackend) ObjectVersionPath(bucket, object, versionId string) (string, error) {
+	return filepath.Join(hsmVersionsDir, bucket, object, versionId), nil
+}
+
+func newHSMBackend(states map[string][]string) (*backend.LustreEnhancedBackend, *fakeLfs) {
+	lfs := &fakeLfs{
+		states:    make(map[string][]string),
+		restoring: make(map[string]bool),
+	}
+	be := &hsmBackend{}
+	for key, flags := range states {
+		be.keys = append(be.keys, key)
+		lfs.states[filepath.Join(hsmRoot, "bucket", key)] = flags
+	}
+	slices.Sort(be.keys)
+
+	return backend.NewLustreEnhancedBackendWithRunner(be, hsmRoot,
+		&backend.LustreConfig{HSM: true}, lfs), lfs
+}
+
+func TestLustreHSMHeadObject(t *testing.T) {
+	l, _ := newHSMBackend(map[string][]string{
+		"new":      {},
+		"archived": {"exists", "archived"},
+		"released": {"released", "exists", "archived"},
+	})
+
+	tests := []struct {
+		key     string
+		class   types.StorageClass
+		restore string
+	}{
+		{"new", types.StorageClassStandard, ""},
+		{"archived", types.StorageClassStandard, `ongoing-request="false", expiry-date="Fri, 2 Dec 2050 00:00:00 GMT"`},
+		{"released", types.StorageClassGlacier, `ongoing-request="false"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			res, err := l.HeadObject(context.Background(), &s3.HeadObjectInput{
+				Bucket: aws.String("bucket"),
+				Key:    aws.String(tt.key),
+			})
+			if err != nil {
+				t.Fatalf("head object: %v", err)
+			}
+			if res.StorageClass != tt.class {
+				t.Errorf("expected storage class %v, got %v", tt.class, res.StorageClass)
+			}
+			if aws.ToString(res.Restore) != tt.restore {
+				t.Errorf("expected restore %q, got %q", tt.restore, aws.ToString(res.Restore))
+			}
+		})
+	}
+
+	_, err := l.HeadObject(context.Background(), &s3.HeadObjectInput{
+		Bucket: aws.String("bucket"),
+		Key:    aws.String("missing"),
+	})
+	if !errors.Is(err, s3err.GetAPIError(s3err.ErrNoSuchKey)) {
+		t.Errorf("expected NoSuchKey, got %v", err)
+	}
+}
+
+func TestLustreHSMRestoreObject(t *testing.T) {
+	l, lfs := newHSMBackend(map[string][]string{
+		"archived": {"exists", "archived"},
+		"released": {"released", "exists", "archived"},
+	})
+	ctx := context.Background()
+
+	_, err := l.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String("bucket"),
+		Key:    aws.String("released"),
+	})
+	if !errors.Is(err, s3err.GetAPIError(s3err.ErrInvalidObjectState)) {
+		t.Fatalf("expected InvalidObjectState, got %v", err)
+	}
+
+	err = l.RestoreObject(ctx, &s3.RestoreObjectInput{
+		Bucket: aws.String("bucket"),
+		Key:    aws.String("archived"),
+	})
+	if err != nil {
+		t.Fatalf("restore online object: %v", err)
+	}
+	if n := lfs.commands("hsm_restore"); n != 0 {
+		t.Fatalf("expected no hsm_restore of an online file, got %v", n)
+	}
+
+	err = l.RestoreObject(ctx, &s3.RestoreObjectInput{
+		Bucket: aws.String("bucket"),
+		Key:    aws.String("released"),
+	})
+	if err != nil {
+		t.Fatalf("restore released object: %v", err)
+	}
+	if n := lfs.commands("hsm_restore"); n != 1 {
+		t.Fatalf("expected one hsm_restore, got %v", n)
+	}
+
+	res, err := l.HeadObject(ctx, &s3.HeadObjectInput{
+		Bucket: aws.String("bucket"),
+		Key:    aws.String("released"),
+	})
+	if err != nil {
+		t.Fatalf("head object: %v", err)
+	}
+	if aws.ToString(res.Restore) != `ongoing-request="true"` {
+		t.Errorf("expected restore in progress, got %q", aws.ToString(res.Restore))
+	}
+
+	err = l.RestoreObject(ctx, &s3.RestoreObjectInput{
+		Bucket: aws.String("bucket"),
+		Key:    aws.String("missing"),
+	})
+	if !errors.Is(err, s3err.GetAPIError(s3err.ErrNoSuchKey)) {
+		t.Errorf("expected NoSuchKey, got %v", err)
+	}
+}
+
+func TestLustreHSMTransitionObject(t *testing.T) {
+	l, lfs := newHSMBackend(map[string][]string{
+		"obj": {},
+	})
+	ctx := context.Background()
+	path := filepath.Join(hsmRoot, "bucket", "obj")
+
+	for range 3 {
+		err := l.TransitionObject(ctx, "bucket", "obj", "")
+		if err != nil {
+			t.Fatalf("transition object: %v", err)
+		}
+	}
+	if !lfs.has(path, "released") {
+		t.Fatalf("expected released file, got %v", lfs.states[path])
+	}
+	if n := lfs.commands("hsm_archive"); n != 1 {
+		t.Errorf("expected one hsm_archive, got %v", n)
+	}
+	if n := lfs.commands("hsm_release"); n != 1 {
+		t.Errorf("expected one hsm_release, got %v", n)
+	}
+
+	res, err := l.ListObjectsV2(ctx, &s3.ListObjectsV2Input{
+		Bucket: aws.String("bucket"),
+	})
+	if err != nil {
+		t.Fatalf("list objects: %v", err)
+	}
+	if len(res.Contents) != 1 || res.Contents[0].StorageClass != types.ObjectStorageClassGlacier {
+		t.Errorf("expected GLACIER object, got %+v", res.Contents)
+	}
+}
+
+func TestLustreHSMObjectVersion(t *testing.T) {
+	l, lfs := newHSMBackend(map[string][]string{
+		"obj": {},
+	})
+	ctx := context.Background()
+	path := filepath.Join(hsmRoot, "bucket", "obj")
+	versionPath := filepath.Join(hsmVersionsDir, "bucket", "obj", "v1")
+	lfs.states[versionPath] = []string{}
+
+	for range 2 {
+		err := l.TransitionObject(ctx, "bucket", "obj", "v1")
+		if err != nil {
+			t.Fatalf("transition object version: %v", err)
+		}
+	}
+	if !lfs.has(versionPath, "released") {
+		t.Fatalf("expected released version file, got %v", lfs.states[versionPath])
+	}
+	if len(lfs.states[path]) != 0 {
+		t.Fatalf("expected online object file, got %v", lfs.states[path])
+	}
+
+	res, err := l.HeadObject(ctx, &s3.HeadObjectInput{
+		Bucket:    aws.String("bucket"),
+		Key:       aws.String("obj"),
+		VersionId: aws.String("v1"),
+	})
+	if err != nil {
+		t.Fatalf("head object version: %v", err)
+	}
+	if res.StorageClass != types.StorageClassGlacier {
+		t.Errorf("expected GLACIER version, got %v", res.StorageClass)
+	}
+
+	_, err = l.GetObject(ctx, &s3.GetObjectInput{
+		Bucket:    aws.String("bucket"),
+		Key:       aws.String("obj"),
+		VersionId: aws.String("v1"),
+	})
+	if !errors.Is(err, s3err.GetAPIError(s3err.ErrInvalidObjectState)) {
+		t.Fatalf("expected InvalidObjectState, got %v", err)
+	}
+	_, err = l.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String("bucket"),
+		Key:    aws.String("obj"),
+	})
+	if err != nil {
+		t.Fatalf("get online object: %v", err)
+	}
+
+	err = l.RestoreObject(ctx, &s3.RestoreObjectInput{
+		Bucket:    aws.String("bucket"),
+		Key:       aws.String("obj"),
+		VersionId: aws.String("v1"),
+	})
+	if err != nil {
+		t.Fatalf("restore object version: %v", err)
+	}
+	if !lfs.restoring[versionPath] {
+		t.Errorf("expected restore of the version file")
+	}
+}
+
+func TestLustreHSMListObjectsBatch(t *testing.T) {
+	states := make(map[string][]string)
+	for i := range 600 {
+		var flags []string
+		if i%3 == 0 {
+			flags = []string{"released", "exists", "archived"}
+		}
+		states[fmt.Sprintf("obj%04d", i)] = flags
+	}
+	l, lfs := newHSMBackend(states)
+
+	res, err := l.ListObjectsV2(context.Background(), &s3.ListObjectsV2Input{
+		Bucket: aws.String("bucket"),
+	})
+	if err != nil {
+		t.Fatalf("list objects: %v", err)
+	}
+	if n := lfs.commands("hsm_state"); n != 3 {
+		t.Errorf("expected 3 hsm_state batches, got %v", n)
+	}
+	for i, obj := range res.Contents {
+		class := types.ObjectStorageClassStandard
+		if i%3 == 0 {
+			class = types.ObjectStorageClassGlacier
+		}
+		if obj.StorageClass != class {
+			t.Errorf("%v: expected storage class %v, got %v", *obj.Key, class, obj.StorageClass)
+		}
+	}
+}
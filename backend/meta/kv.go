@@ -0,0 +1,276 @@
+// Copyright 2025 Versity Software
+// This file is licensed under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package meta
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// KVMeta is a metadata storer that uses an embedded transactional
+// key-value database to store metadata. All attributes are stored in
+// a single database file, which avoids the many small files of the
+// sidecar metadata.
+//
+// The attributes are keyed by the object path and the attribute name
+// separated by a NUL byte, so all attributes of an object are stored
+// next to each other and can be listed with a prefix scan.
+type KVMeta struct {
+	db *bolt.DB
+}
+
+var kvAttrBucket = []byte("attributes")
+
+const (
+	kvSeparator = 0
+	// kvOpenTimeout is how long to wait for the database file lock,
+	// the database can only be opened by one process at a time
+	kvOpenTimeout = 5 * time.Second
+)
+
+// NewKV creates a new KVMeta metadata storer with the database file at
+// the given path. The database is created if it doesn't exist.
+func NewKV(path string) (KVMeta, error) {
+	fi, err := os.Stat(filepath.Dir(path))
+	if err != nil {
+		return KVMeta{}, fmt.Errorf("failed to stat directory: %v", err)
+	}
+	if !fi.IsDir() {
+		return KVMeta{}, fmt.Errorf("not a directory")
+	}
+
+	db, err := bolt.Open(path, 0600, &bolt.Options{Timeout: kvOpenTimeout})
+	if err != nil {
+		return KVMeta{}, fmt.Errorf("failed to open metadata database: %v", err)
+	}
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(kvAttrBucket)
+		return err
+	})
+	if err != nil {
+		db.Close()
+		return KVMeta{}, fmt.Errorf("failed to init metadata database: %v", err)
+	}
+
+	return KVMeta{db: db}, nil
+}
+
+// Close closes the metadata database.
+func (k KVMeta) Close() error {
+	return k.db.Close()
+}
+
+// kvPath returns the object path the attributes are stored under,
+// consistent with the path of the object in the filesystem
+func kvPath(bucket, object string) string {
+	return filepath.Join(bucket, object)
+}
+
+func kvPrefix(path string) []byte {
+	return append([]byte(path), kvSeparator)
+}
+
+func kvKey(bucket, object, attribute string) []byte {
+	return append(kvPrefix(kvPath(bucket, object)), attribute...)
+}
+
+// RetrieveAttribute retrieves the value of a specific attribute for an object or a bucket.
+func (k KVMeta) RetrieveAttribute(_ *os.File, bucket, object, attribute string) ([]byte, error) {
+	var value []byte
+	err := k.db.View(func(tx *bolt.Tx) error {
+		v := tx.Bucket(kvAttrBucket).Get(kvKey(bucket, object, attribute))
+		if v == nil {
+			return ErrNoSuchKey
+		}
+		// the value is only valid for the life of the transaction
+		value = bytes.Clone(v)
+		return nil
+	})
+	if err == ErrNoSuchKey {
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read attribute: %v", err)
+	}
+
+	return value, nil
+}
+
+// StoreAttribute stores the value of a specific attribute for an object or a bucket.
+func (k KVMeta) StoreAttribute(_ *os.File, bucket, object, attribute string, value []byte) error {
+	err := k.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(kvAttrBucket).Put(kvKey(bucket, object, attribute), value)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to write attribute: %v", err)
+	}
+	return nil
+}
+
+// StoreAttributes stores the values of multiple attributes for an object
+// or a bucket in a single transaction.
+func (k KVMeta) StoreAttributes(_ *os.File, bucket, object string, attrs map[string][]byte) error {
+	err := k.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(kvAttrBucket)
+		for attr, value := range attrs {
+			err := b.Put(kvKey(bucket, object, attr), value)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to write attributes: %v", err)
+	}
+	return nil
+}
+
+// DeleteAttribute removes the value of a specific attribute for an object or a bucket.
+func (k KVMeta) DeleteAttribute(bucket, object, attribute string) error {
+	err := k.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(kvAttrBucket)
+		key := kvKey(bucket, object, attribute)
+		if b.Get(key) == nil {
+			return ErrNoSuchKey
+		}
+		return b.Delete(key)
+	})
+	if err == ErrNoSuchKey {
+		return err
+	}
+	if err != nil {
+		return fmt.Errorf("failed to remove attribute: %v", err)
+	}
+	return nil
+}
+
+// ListAttributes lists all attributes for an object or a bucket.
+func (k KVMeta) ListAttributes(bucket, object string) ([]string, error) {
+	attrs := []string{}
+	prefix := kvPrefix(kvPath(bucket, object))
+	err := k.db.View(func(tx *bolt.Tx) error {
+		c := tx.Bucket(kvAttrBucket).Cursor()
+		for key, _ := c.Seek(prefix); key != nil && bytes.HasPrefix(key, prefix); key, _ = c.Next() {
+			attrs = append(attrs, string(key[len(prefix):]))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list attributes: %v", err)
+	}
+
+	return attrs, nil
+}
+
+// DeleteAttributes removes all attributes for an object or a bucket.
+func (k KVMeta) DeleteAttributes(bucket, object string) error {
+	err := k.db.Update(func(tx *bolt.Tx) error {
+		return deletePrefix(tx.Bucket(kvAttrBucket), kvPrefix(kvPath(bucket, object)))
+	})
+	if err != nil {
+		return fmt.Errorf("failed to remove attributes: %v", err)
+	}
+	return nil
+}
+
+// MoveAttributes replaces all attributes of the destination object with
+// the attributes of the source object in a single transaction.
+func (k KVMeta) MoveAttributes(srcBucket, srcObject, dstBucket, dstObject string) error {
+	srcPrefix := kvPrefix(kvPath(srcBucket, srcObject))
+	dstPrefix := kvPrefix(kvPath(dstBucket, dstObject))
+	if bytes.Equal(srcPrefix, dstPrefix) {
+		return nil
+	}
+
+	err := k.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(kvAttrBucket)
+		err := deletePrefix(b, dstPrefix)
+		if err != nil {
+			return err
+		}
+
+		attrs := map[string][]byte{}
+		c := b.Cursor()
+		for key, value := c.Seek(srcPrefix); key != nil && bytes.HasPrefix(key, srcPrefix); key, value = c.Next() {
+			attrs[string(key[len(srcPrefix):])] = bytes.Clone(value)
+		}
+
+		err = deletePrefix(b, srcPrefix)
+		if err != nil {
+			return err
+		}
+
+		for attr, value := range attrs {
+			err := b.Put(append(bytes.Clone(dstPrefix), attr...), value)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to move attributes: %v", err)
+	}
+	return nil
+}
+
+// WalkPaths calls fn for each object or bucket path with stored attributes.
+func (k KVMeta) WalkPaths(fn func(path string) error) error {
+	var paths []string
+	err := k.db.View(func(tx *bolt.Tx) error {
+		var last []byte
+		c := tx.Bucket(kvAttrBucket).Cursor()
+		for key, _ := c.First(); key != nil; key, _ = c.Next() {
+			i := bytes.IndexByte(key, kvSeparator)
+			if i < 0 || bytes.Equal(key[:i], last) {
+				continue
+			}
+			last = bytes.Clone(key[:i])
+			paths = append(paths, string(last))
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to list metadata: %v", err)
+	}
+
+	// the callback is called outside of the transaction, so it
+	// can modify the metadata
+	for _, path := range paths {
+		err := fn(path)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func deletePrefix(b *bolt.Bucket, prefix []byte) error {
+	c := b.Cursor()
+	for key, _ := c.Seek(prefix); key != nil && bytes.HasPrefix(key, prefix); key, _ = c.Seek(prefix) {
+		err := c.Delete()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,208 @@
+// Copyright 2026 Versity Software
+// This file is licensed under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package meta
+
+import (
+	"errors"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func newTestKV(t *testing.T) (KVMeta, string) {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "meta.db")
+	kv, err := NewKV(path)
+	if err != nil {
+		t.Fatalf("open kv metadata: %v", err)
+	}
+	t.Cleanup(func() { kv.Close() })
+	return kv, path
+}
+
+func TestKVMeta_Attributes(t *testing.T) {
+	kv, _ := newTestKV(t)
+
+	_, err := kv.RetrieveAttribute(nil, "bucket", "obj", "etag")
+	if !errors.Is(err, ErrNoSuchKey) {
+		t.Fatalf("expected ErrNoSuchKey, got %v", err)
+	}
+
+	err = kv.StoreAttribute(nil, "bucket", "obj", "etag", []byte("abc"))
+	if err != nil {
+		t.Fatalf("store attribute: %v", err)
+	}
+	value, err := kv.RetrieveAttribute(nil, "bucket", "obj", "etag")
+	if err != nil || string(value) != "abc" {
+		t.Fatalf("expected etag abc, got %q %v", value, err)
+	}
+
+	err = kv.DeleteAttribute("bucket", "obj", "etag")
+	if err != nil {
+		t.Fatalf("delete attribute: %v", err)
+	}
+	err = kv.DeleteAttribute("bucket", "obj", "etag")
+	if !errors.Is(err, ErrNoSuchKey) {
+		t.Errorf("expected ErrNoSuchKey deleting missing attribute, got %v", err)
+	}
+}
+
+func TestKVMeta_StoreAttributes(t *testing.T) {
+	kv, _ := newTestKV(t)
+
+	attrs := map[string][]byte{
+		"etag":         []byte("abc"),
+		"content-type": []byte("text/plain"),
+		"checksums":    []byte("{}"),
+	}
+	err := StoreAttributes(kv, nil, "bucket", "dir/obj", attrs)
+	if err != nil {
+		t.Fatalf("store attributes: %v", err)
+	}
+
+	names, err := kv.ListAttributes("bucket", "dir/obj")
+	if err != nil {
+		t.Fatalf("list attributes: %v", err)
+	}
+	slices.Sort(names)
+	if want := []string{"checksums", "content-type", "etag"}; !slices.Equal(names, want) {
+		t.Errorf("expected attributes %v, got %v", want, names)
+	}
+
+	for name, want := range attrs {
+		value, err := kv.RetrieveAttribute(nil, "bucket", "dir/obj", name)
+		if err != nil || string(value) != string(want) {
+			t.Errorf("%v: expected %q, got %q %v", name, want, value, err)
+		}
+	}
+}
+
+func TestKVMeta_ListPrefix(t *testing.T) {
+	kv, _ := newTestKV(t)
+
+	// the attributes of the objects sharing the path prefix are
+	// listed separately
+	for _, obj := range []string{"a", "a/b", "ab", ""} {
+		err := kv.StoreAttribute(nil, "bucket", obj, "key-"+obj, []byte(obj))
+		if err != nil {
+			t.Fatalf("store attribute: %v", err)
+		}
+	}
+
+	for _, obj := range []string{"a", "a/b", "ab", ""} {
+		names, err := kv.ListAttributes("bucket", obj)
+		if err != nil {
+			t.Fatalf("list attributes: %v", err)
+		}
+		if want := []string{"key-" + obj}; !slices.Equal(names, want) {
+			t.Errorf("%q: expected attributes %v, got %v", obj, want, names)
+		}
+	}
+
+	err := kv.DeleteAttributes("bucket", "a")
+	if err != nil {
+		t.Fatalf("delete attributes: %v", err)
+	}
+	if names, _ := kv.ListAttributes("bucket", "a"); len(names) != 0 {
+		t.Errorf("expected no attributes, got %v", names)
+	}
+	if names, _ := kv.ListAttributes("bucket", "a/b"); len(names) != 1 {
+		t.Errorf("expected the a/b attributes to be kept, got %v", names)
+	}
+}
+
+func TestKVMeta_MoveAttributes(t *testing.T) {
+	kv, _ := newTestKV(t)
+
+	err := StoreAttributes(kv, nil, "bucket", "src", map[string][]byte{
+		"etag": []byte("src"),
+		"tags": []byte("t"),
+	})
+	if err != nil {
+		t.Fatalf("store attributes: %v", err)
+	}
+	err = StoreAttributes(kv, nil, "bucket", "dst", map[string][]byte{
+		"etag":  []byte("dst"),
+		"stale": []byte("x"),
+	})
+	if err != nil {
+		t.Fatalf("store attributes: %v", err)
+	}
+
+	err = MoveAttributes(kv, "bucket", "src", "bucket", "dst")
+	if err != nil {
+		t.Fatalf("move attributes: %v", err)
+	}
+
+	if names, _ := kv.ListAttributes("bucket", "src"); len(names) != 0 {
+		t.Errorf("expected the source attributes to be removed, got %v", names)
+	}
+	names, _ := kv.ListAttributes("bucket", "dst")
+	slices.Sort(names)
+	if want := []string{"etag", "tags"}; !slices.Equal(names, want) {
+		t.Errorf("expected destination attributes %v, got %v", want, names)
+	}
+	value, _ := kv.RetrieveAttribute(nil, "bucket", "dst", "etag")
+	if string(value) != "src" {
+		t.Errorf("expected moved etag src, got %q", value)
+	}
+}
+
+func TestKVMeta_WalkPaths(t *testing.T) {
+	kv, path := newTestKV(t)
+
+	for _, obj := range []string{"b", "a/b", "a"} {
+		err := StoreAttributes(kv, nil, "bucket", obj, map[string][]byte{
+			"etag": []byte(obj),
+			"tags": []byte(obj),
+		})
+		if err != nil {
+			t.Fatalf("store attributes: %v", err)
+		}
+	}
+
+	// the attributes persist across reopening the database
+	kv.Close()
+	kv, err := NewKV(path)
+	if err != nil {
+		t.Fatalf("reopen kv metadata: %v", err)
+	}
+	defer kv.Close()
+
+	var paths []string
+	err = kv.WalkPaths(func(path string) error {
+		paths = append(paths, path)
+		// the callback can modify the metadata
+		return kv.DeleteAttributes(path, "")
+	})
+	if err != nil {
+		t.Fatalf("walk paths: %v", err)
+	}
+
+	want := []string{"bucket/a", "bucket/a/b", "bucket/b"}
+	if !slices.Equal(paths, want) {
+		t.Errorf("expected paths %v, got %v", want, paths)
+	}
+
+	paths = nil
+	kv.WalkPaths(func(path string) error {
+		paths = append(paths, path)
+		return nil
+	})
+	if len(paths) != 0 {
+		t.Errorf("expected no paths after the deletes, got %v", paths)
+	}
+}
@@ -14,7 +14,10 @@
 
 package meta
 
-import "os"
+import (
+	"fmt"
+	"os"
+)
 
 // MetadataStorer defines the interface for managing metadata.
 // When object == "", the operation is on the bucket.
@@ -40,3 +43,102 @@ type MetadataStorer interface {
 	// Returns an error if the operation fails.
 	DeleteAttributes(bucket, object string) error
 }
+
+// AttributeMover is implemented by metadata storers that keep the metadata
+// apart from the objects, so it doesn't follow the object renames.
+type AttributeMover interface {
+	// MoveAttributes replaces all attributes of the destination object
+	// with the attributes of the source object, and removes the
+	// attributes of the source object.
+	MoveAttributes(srcBucket, srcObject, dstBucket, dstObject string) error
+}
+
+// BatchStorer is implemented by metadata storers that can store multiple
+// attributes atomically.
+type BatchStorer interface {
+	// StoreAttributes stores the values of multiple attributes for an
+	// object or a bucket.
+	StoreAttributes(f *os.File, bucket, object string, attrs map[string][]byte) error
+}
+
+// PathWalker is implemented by metadata storers that can list the
+// object and bucket paths having metadata, e.g. to find orphaned metadata.
+type PathWalker interface {
+	// WalkPaths calls fn for each object or bucket path with stored
+	// attributes. The path is the bucket and object joined with the
+	// path separator.
+	WalkPaths(fn func(path string) error) error
+}
+
+// FileOpener opens the files of the relative bucket and object paths
+// beneath the gateway root directory.
+type FileOpener interface {
+	Open(name string) (*os.File, error)
+}
+
+// RootedStorer is implemented by metadata storers that access the
+// metadata with the object files in the filesystem, so the relative
+// bucket and object paths have to be resolved from the gateway root
+// directory.
+type RootedStorer interface {
+	// WithRoot returns a metadata storer that opens the relative
+	// bucket and object paths with the root opener.
+	WithRoot(root FileOpener) MetadataStorer
+}
+
+// WithRoot returns the metadata storer opening the relative bucket
+// and object paths with the root opener if the metadata storer
+// implements RootedStorer, otherwise the metadata storer is returned
+// as is.
+func WithRoot(ms MetadataStorer, root FileOpener) MetadataStorer {
+	if r, ok := ms.(RootedStorer); ok {
+		return r.WithRoot(root)
+	}
+	return ms
+}
+
+// MoveAttributes moves all attributes of the source object to the
+// destination object. If the metadata storer doesn't implement
+// AttributeMover, the attributes are copied one by one.
+func MoveAttributes(ms MetadataStorer, srcBucket, srcObject, dstBucket, dstObject string) error {
+	if m, ok := ms.(AttributeMover); ok {
+		return m.MoveAttributes(srcBucket, srcObject, dstBucket, dstObject)
+	}
+
+	attrs, err := ms.ListAttributes(srcBucket, srcObject)
+	if err != nil {
+		return fmt.Errorf("list attributes: %w", err)
+	}
+
+	for _, attr := range attrs {
+		data, err := ms.RetrieveAttribute(nil, srcBucket, srcObject, attr)
+		if err != nil {
+			return fmt.Errorf("load %v attribute: %w", attr, err)
+		}
+
+		err = ms.StoreAttribute(nil, dstBucket, dstObject, attr, data)
+		if err != nil {
+			return fmt.Errorf("store %v attribute: %w", attr, err)
+		}
+	}
+
+	return ms.DeleteAttributes(srcBucket, srcObject)
+}
+
+// StoreAttributes stores multiple attributes of an object or a bucket.
+// If the metadata storer doesn't implement BatchStorer, the attributes
+// are stored one by one.
+func StoreAttributes(ms MetadataStorer, f *os.File, bucket, object string, attrs map[string][]byte) error {
+	if b, ok := ms.(BatchStorer); ok {
+		return b.StoreAttributes(f, bucket, object, attrs)
+	}
+
+	for attr, data := range attrs {
+		err := ms.StoreAttribute(f, bucket, object, attr, data)
+		if err != nil {
+			return fmt.Errorf("store %v attribute: %w", attr, err)
+		}
+	}
+
+	return nil
+}
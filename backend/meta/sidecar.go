@@ -153,6 +153,76 @@ func (s SideCar) DeleteAttributes(bucket, object string) error {
 	return nil
 }
 
+// MoveAttributes replaces all attributes of the destination object with
+// the attributes of the source object by renaming the metadata directory.
+func (s SideCar) MoveAttributes(srcBucket, srcObject, dstBucket, dstObject string) error {
+	srcdir := filepath.Join(s.dir, srcBucket, srcObject, sidecarmeta)
+	dstdir := filepath.Join(s.dir, dstBucket, dstObject, sidecarmeta)
+	if srcdir == dstdir {
+		return nil
+	}
+
+	err := os.RemoveAll(dstdir)
+	if err != nil {
+		return fmt.Errorf("failed to remove attributes: %v", err)
+	}
+
+	_, err = os.Lstat(srcdir)
+	if errors.Is(err, os.ErrNotExist) {
+		s.cleanupEmptyDirs(dstdir, dstBucket, dstObject)
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to stat metadata directory: %v", err)
+	}
+
+	err = os.MkdirAll(filepath.Dir(dstdir), 0777)
+	if err != nil {
+		return fmt.Errorf("failed to create metadata directory: %v", err)
+	}
+
+	err = os.Rename(srcdir, dstdir)
+	if err != nil {
+		return fmt.Errorf("failed to move attributes: %v", err)
+	}
+
+	s.cleanupEmptyDirs(srcdir, srcBucket, srcObject)
+	return nil
+}
+
+// WalkPaths calls fn for each object or bucket path with stored attributes.
+func (s SideCar) WalkPaths(fn func(path string) error) error {
+	var paths []string
+	err := filepath.WalkDir(s.dir, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() || d.Name() != sidecarmeta || path == s.dir {
+			return nil
+		}
+
+		rel, err := filepath.Rel(s.dir, filepath.Dir(path))
+		if err != nil {
+			return err
+		}
+		paths = append(paths, rel)
+		return filepath.SkipDir
+	})
+	if err != nil {
+		return fmt.Errorf("failed to list metadata: %v", err)
+	}
+
+	// the callback is called after the walk, so it can
+	// modify the metadata
+	for _, path := range paths {
+		err := fn(path)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (s SideCar) cleanupEmptyDirs(metadir, bucket, object string) {
 	removeIfEmpty(metadir)
 	if bucket == "" {
@@ -31,19 +31,60 @@ var (
 	ErrNoSuchKey = errors.New("no such key")
 )
 
-type XattrMeta struct{}
+type XattrMeta struct {
+	// root opens the relative bucket and object paths
+	// beneath the gateway root directory
+	root FileOpener
+}
+
+// WithRoot returns a copy of the XattrMeta that accesses the attributes
+// of the relative bucket and object paths through the files opened with
+// the root opener instead of the working directory paths, so symlinks
+// can't be used to access the attributes of files outside of the root.
+func (x XattrMeta) WithRoot(root FileOpener) MetadataStorer {
+	return XattrMeta{root: root}
+}
+
+// withFile calls fn with the file of the bucket and object opened
+// through the root opener, or the path of the file when there is no
+// root opener.
+func (x XattrMeta) withFile(bucket, object string, fn func(f *os.File, path string) error) error {
+	name := filepath.Join(bucket, object)
+	if x.root == nil {
+		return fn(nil, name)
+	}
+
+	var f *os.File
+	var err error
+	if filepath.IsAbs(name) {
+		f, err = os.Open(name)
+	} else {
+		f, err = x.root.Open(name)
+	}
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return fn(f, name)
+}
 
 // RetrieveAttribute retrieves the value of a specific attribute for an object in a bucket.
 func (x XattrMeta) RetrieveAttribute(f *os.File, bucket, object, attribute string) ([]byte, error) {
+	var b []byte
+	var err error
 	if f != nil {
-		b, err := xattr.FGet(f, xattrPrefix+attribute)
-		if errors.Is(err, xattr.ENOATTR) {
-			return nil, ErrNoSuchKey
-		}
-		return b, err
+		b, err = xattr.FGet(f, xattrPrefix+attribute)
+	} else {
+		err = x.withFile(bucket, object, func(f *os.File, path string) error {
+			var err error
+			if f != nil {
+				b, err = xattr.FGet(f, xattrPrefix+attribute)
+			} else {
+				b, err = xattr.Get(path, xattrPrefix+attribute)
+			}
+			return err
+		})
 	}
-
-	b, err := xattr.Get(filepath.Join(bucket, object), xattrPrefix+attribute)
 	if errors.Is(err, xattr.ENOATTR) {
 		return nil, ErrNoSuchKey
 	}
@@ -52,15 +93,17 @@ func (x XattrMeta) RetrieveAttribute(f *os.File, bucket, object, attribute strin
 
 // StoreAttribute stores the value of a specific attribute for an object in a bucket.
 func (x XattrMeta) StoreAttribute(f *os.File, bucket, object, attribute string, value []byte) error {
+	var err error
 	if f != nil {
-		err := xattr.FSet(f, xattrPrefix+attribute, value)
-		if errors.Is(err, syscall.EROFS) {
-			return s3err.GetAPIError(s3err.ErrMethodNotAllowed)
-		}
-		return err
+		err = xattr.FSet(f, xattrPrefix+attribute, value)
+	} else {
+		err = x.withFile(bucket, object, func(f *os.File, path string) error {
+			if f != nil {
+				return xattr.FSet(f, xattrPrefix+attribute, value)
+			}
+			return xattr.Set(path, xattrPrefix+attribute, value)
+		})
 	}
-
-	err := xattr.Set(filepath.Join(bucket, object), xattrPrefix+attribute, value)
 	if errors.Is(err, syscall.EROFS) {
 		return s3err.GetAPIError(s3err.ErrMethodNotAllowed)
 	}
@@ -69,7 +112,12 @@ func (x XattrMeta) StoreAttribute(f *os.File, bucket, object, attribute string,
 
 // DeleteAttribute removes the value of a specific attribute for an object in a bucket.
 func (x XattrMeta) DeleteAttribute(bucket, object, attribute string) error {
-	err := xattr.Remove(filepath.Join(bucket, object), xattrPrefix+attribute)
+	err := x.withFile(bucket, object, func(f *os.File, path string) error {
+		if f != nil {
+			return xattr.FRemove(f, xattrPrefix+attribute)
+		}
+		return xattr.Remove(path, xattrPrefix+attribute)
+	})
 	if errors.Is(err, xattr.ENOATTR) {
 		return ErrNoSuchKey
 	}
@@ -87,7 +135,16 @@ func (x XattrMeta) DeleteAttributes(bucket, object string) error {
 
 // ListAttributes lists all attributes for an object in a bucket.
 func (x XattrMeta) ListAttributes(bucket, object string) ([]string, error) {
-	attrs, err := xattr.List(filepath.Join(bucket, object))
+	var attrs []string
+	err := x.withFile(bucket, object, func(f *os.File, path string) error {
+		var err error
+		if f != nil {
+			attrs, err = xattr.FList(f)
+		} else {
+			attrs, err = xattr.List(path)
+		}
+		return err
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,96 @@
+// Copyright 2026 Versity Software
+// This file is licensed under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package meta
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+
+	"github.com/pkg/xattr"
+)
+
+func TestXattrMeta_SymlinkEscape(t *testing.T) {
+	dir := t.TempDir()
+	outside := t.TempDir()
+
+	if err := (XattrMeta{}).Test(dir); err != nil {
+		t.Skip(err)
+	}
+
+	err := os.Mkdir(filepath.Join(dir, "bucket"), 0o755)
+	if err != nil {
+		t.Fatalf("create bucket: %v", err)
+	}
+	err = os.WriteFile(filepath.Join(dir, "bucket", "obj"), nil, 0o644)
+	if err != nil {
+		t.Fatalf("create object: %v", err)
+	}
+	secret := filepath.Join(outside, "secret")
+	err = os.WriteFile(secret, nil, 0o644)
+	if err != nil {
+		t.Fatalf("create outside file: %v", err)
+	}
+	err = xattr.Set(secret, xattrPrefix+"etag", []byte("secret"))
+	if errors.Is(err, syscall.ENOTSUP) {
+		t.Skipf("user xattrs are not supported: %v", err)
+	}
+	if err != nil {
+		t.Fatalf("set outside attribute: %v", err)
+	}
+	err = os.Symlink(outside, filepath.Join(dir, "bucket", "escape"))
+	if err != nil {
+		t.Fatalf("create symlink: %v", err)
+	}
+
+	root, err := os.OpenRoot(dir)
+	if err != nil {
+		t.Fatalf("open root: %v", err)
+	}
+	defer root.Close()
+	x := WithRoot(XattrMeta{}, root)
+
+	err = x.StoreAttribute(nil, "bucket", "obj", "etag", []byte("abc"))
+	if err != nil {
+		t.Fatalf("store attribute: %v", err)
+	}
+	value, err := x.RetrieveAttribute(nil, "bucket", "obj", "etag")
+	if err != nil || string(value) != "abc" {
+		t.Fatalf("expected etag abc, got %q %v", value, err)
+	}
+
+	_, err = x.RetrieveAttribute(nil, "bucket", "escape/secret", "etag")
+	if err == nil {
+		t.Errorf("expected retrieve through the escaping symlink to fail")
+	}
+	if _, err := x.ListAttributes("bucket", "escape/secret"); err == nil {
+		t.Errorf("expected list through the escaping symlink to fail")
+	}
+	err = x.StoreAttribute(nil, "bucket", "escape/secret", "etag", []byte("changed"))
+	if err == nil {
+		t.Errorf("expected store through the escaping symlink to fail")
+	}
+	err = x.DeleteAttribute("bucket", "escape/secret", "etag")
+	if err == nil {
+		t.Errorf("expected delete through the escaping symlink to fail")
+	}
+
+	value, err = xattr.Get(secret, xattrPrefix+"etag")
+	if err != nil || string(value) != "secret" {
+		t.Errorf("expected the outside attribute to be unchanged, got %q %v", value, err)
+	}
+}
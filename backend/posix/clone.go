@@ -0,0 +1,122 @@
+// Copyright 2026 Versity Software
+// This file is licensed under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package posix
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/versity/versitygw/backend"
+	"github.com/versity/versitygw/s3response"
+)
+
+var (
+	errCloneNotSupported = errors.New("file clone not supported")
+	errShortCopy         = errors.New("short copy")
+)
+
+// the in-filesystem copy methods, in the order they are tried
+var (
+	reflinkRangeFunc = reflinkRange
+	copyRangeFunc    = copyRange
+)
+
+// cloneFileData copies the length bytes at srcOff of src to the current
+// offset of dst within the filesystem, and advances the dst offset past
+// the copied data. The data is reflinked if the filesystem supports it,
+// so the copy is instant and shares the data blocks with the source until
+// either file is modified. Otherwise the data is copied with
+// copy_file_range. It returns false if the data could not be copied
+// within the filesystem, and has to be copied through userspace.
+func (p *Posix) cloneFileData(dst, src *os.File, srcOff, length int64) bool {
+	if length == 0 {
+		return false
+	}
+
+	dstOff, err := dst.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return false
+	}
+
+	err = reflinkRangeFunc(dst, dstOff, src, srcOff, length)
+	if err != nil {
+		if p.forceNoCopyFileRange {
+			return false
+		}
+		err = copyRangeFunc(dst, dstOff, src, srcOff, length)
+		if err != nil {
+			return false
+		}
+	}
+
+	_, err = dst.Seek(dstOff+length, io.SeekStart)
+	return err == nil
+}
+
+// copySource is the etag and checksum of the source object of a copy
+type copySource struct {
+	etag     string
+	checksum s3response.Checksum
+}
+
+// withCtxCopySource is a context wrapper with the source object etag and
+// checksum of the objects copied within the gateway
+func withCtxCopySource(ctx context.Context, etag string, checksum s3response.Checksum) context.Context {
+	return context.WithValue(ctx, ctxKeyCopySource, copySource{
+		etag:     etag,
+		checksum: checksum,
+	})
+}
+
+// getCopySourceSums returns the copy source object etag and checksum, if
+// they are valid for the copied object data. The multipart upload etags
+// and composite checksums are not, as the copy is a single part object.
+// Empty strings are returned if the etag and checksum have to be
+// calculated from the object data.
+func getCopySourceSums(ctx context.Context, algo types.ChecksumAlgorithm, expected string) (string, string) {
+	src, ok := ctx.Value(ctxKeyCopySource).(copySource)
+	if !ok || expected != "" {
+		return "", ""
+	}
+	if src.etag == "" || strings.Contains(src.etag, "-") {
+		return "", ""
+	}
+	if src.checksum.Type != types.ChecksumTypeFullObject || src.checksum.Algorithm != algo {
+		return "", ""
+	}
+
+	var sum *string
+	switch algo {
+	case types.ChecksumAlgorithmCrc32:
+		sum = src.checksum.CRC32
+	case types.ChecksumAlgorithmCrc32c:
+		sum = src.checksum.CRC32C
+	case types.ChecksumAlgorithmSha1:
+		sum = src.checksum.SHA1
+	case types.ChecksumAlgorithmSha256:
+		sum = src.checksum.SHA256
+	case types.ChecksumAlgorithmCrc64nvme:
+		sum = src.checksum.CRC64NVME
+	}
+
+	if backend.GetStringFromPtr(sum) == "" {
+		return "", ""
+	}
+	return src.etag, *sum
+}
@@ -0,0 +1,54 @@
+// Copyright 2026 Versity Software
+// This file is licensed under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+//go:build linux
+
+package posix
+
+import (
+	"os"
+
+	"golang.org/x/sys/unix"
+)
+
+// reflinkRange clones the length bytes at srcOff of src to dstOff of dst
+// with FICLONERANGE, so the files share the data blocks. This is supported
+// by filesystems with copy-on-write data, e.g. XFS and Btrfs, when both
+// files are in the same filesystem and the offsets are block aligned.
+func reflinkRange(dst *os.File, dstOff int64, src *os.File, srcOff, length int64) error {
+	return unix.IoctlFileCloneRange(int(dst.Fd()), &unix.FileCloneRange{
+		Src_fd:      int64(src.Fd()),
+		Src_offset:  uint64(srcOff),
+		Src_length:  uint64(length),
+		Dest_offset: uint64(dstOff),
+	})
+}
+
+// copyRange copies the length bytes at srcOff of src to dstOff of dst with
+// copy_file_range, so the data is copied within the kernel (or offloaded to
+// the storage/server) instead of passing through userspace.
+func copyRange(dst *os.File, dstOff int64, src *os.File, srcOff, length int64) error {
+	for length > 0 {
+		n, err := unix.CopyFileRange(int(src.Fd()), &srcOff, int(dst.Fd()), &dstOff, int(length), 0)
+		if err != nil {
+			return err
+		}
+		if n == 0 {
+			// the source file was truncated
+			return errShortCopy
+		}
+		length -= int64(n)
+	}
+	return nil
+}
@@ -0,0 +1,239 @@
+// Copyright 2026 Versity Software
+// This file is licensed under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package posix
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/versity/versitygw/s3response"
+)
+
+// stubCloneFuncs replaces the in-filesystem copy methods for the test,
+// the successful methods copy the data through userspace. It returns
+// the names of the called methods.
+func stubCloneFuncs(t *testing.T, reflinkErr, copyRangeErr error) *[]string {
+	t.Helper()
+
+	var calls []string
+	copyData := func(dst *os.File, dstOff int64, src *os.File, srcOff, length int64) error {
+		buf := make([]byte, length)
+		_, err := src.ReadAt(buf, srcOff)
+		if err != nil {
+			return err
+		}
+		_, err = dst.WriteAt(buf, dstOff)
+		return err
+	}
+
+	origReflink, origCopyRange := reflinkRangeFunc, copyRangeFunc
+	t.Cleanup(func() {
+		reflinkRangeFunc, copyRangeFunc = origReflink, origCopyRange
+	})
+
+	reflinkRangeFunc = func(dst *os.File, dstOff int64, src *os.File, srcOff, length int64) error {
+		calls = append(calls, "reflink")
+		if reflinkErr != nil {
+			return reflinkErr
+		}
+		return copyData(dst, dstOff, src, srcOff, length)
+	}
+	copyRangeFunc = func(dst *os.File, dstOff int64, src *os.File, srcOff, length int64) error {
+		calls = append(calls, "copy_file_range")
+		if copyRangeErr != nil {
+			return copyRangeErr
+		}
+		return copyData(dst, dstOff, src, srcOff, length)
+	}
+	return &calls
+}
+
+func newCloneFiles(t *testing.T, data string) (*os.File, *os.File) {
+	t.Helper()
+
+	dir := t.TempDir()
+	src, err := os.Create(filepath.Join(dir, "src"))
+	if err != nil {
+		t.Fatalf("create source: %v", err)
+	}
+	t.Cleanup(func() { src.Close() })
+	_, err = src.WriteString(data)
+	if err != nil {
+		t.Fatalf("write source: %v", err)
+	}
+
+	dst, err := os.Create(filepath.Join(dir, "dst"))
+	if err != nil {
+		t.Fatalf("create destination: %v", err)
+	}
+	t.Cleanup(func() { dst.Close() })
+	return dst, src
+}
+
+func TestCloneFileData(t *testing.T) {
+	errNotSupported := errors.New("not supported")
+
+	tests := []struct {
+		name                 string
+		reflinkErr           error
+		copyRangeErr         error
+		forceNoCopyFileRange bool
+		cloned               bool
+		calls                []string
+	}{
+		{
+			name:   "reflink",
+			cloned: true,
+			calls:  []string{"reflink"},
+		},
+		{
+			name:       "copy_file_range fallback",
+			reflinkErr: errNotSupported,
+			cloned:     true,
+			calls:      []string{"reflink", "copy_file_range"},
+		},
+		{
+			name:         "userspace copy fallback",
+			reflinkErr:   errNotSupported,
+			copyRangeErr: errNotSupported,
+			calls:        []string{"reflink", "copy_file_range"},
+		},
+		{
+			name:                 "copy_file_range disabled",
+			reflinkErr:           errNotSupported,
+			forceNoCopyFileRange: true,
+			calls:                []string{"reflink"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := stubCloneFuncs(t, tt.reflinkErr, tt.copyRangeErr)
+			p := &Posix{forceNoCopyFileRange: tt.forceNoCopyFileRange}
+
+			dst, src := newCloneFiles(t, "0123456789")
+			_, err := dst.WriteString("ab")
+			if err != nil {
+				t.Fatalf("write destination: %v", err)
+			}
+
+			cloned := p.cloneFileData(dst, src, 2, 6)
+			if cloned != tt.cloned {
+				t.Fatalf("expected cloned %v, got %v", tt.cloned, cloned)
+			}
+			if !slices.Equal(*calls, tt.calls) {
+				t.Errorf("expected calls %v, got %v", tt.calls, *calls)
+			}
+			if !cloned {
+				return
+			}
+
+			// the data is appended at the destination offset
+			_, err = dst.WriteString("cd")
+			if err != nil {
+				t.Fatalf("write destination: %v", err)
+			}
+			data, err := os.ReadFile(dst.Name())
+			if err != nil {
+				t.Fatalf("read destination: %v", err)
+			}
+			if string(data) != "ab234567cd" {
+				t.Errorf("expected data ab234567cd, got %q", data)
+			}
+		})
+	}
+}
+
+func TestCloneFileData_Filesystem(t *testing.T) {
+	data := bytes.Repeat([]byte("0123456789abcdef"), 64*1024)
+	dst, src := newCloneFiles(t, string(data))
+
+	p := &Posix{}
+	if !p.cloneFileData(dst, src, 0, int64(len(data))) {
+		t.Skip("in-filesystem copy is not supported")
+	}
+
+	got, err := os.ReadFile(dst.Name())
+	if err != nil {
+		t.Fatalf("read destination: %v", err)
+	}
+	if !bytes.Equal(got, data) {
+		t.Errorf("cloned data differs from the source data")
+	}
+
+	// the copy fails if the source is shorter than the length
+	if p.cloneFileData(dst, src, int64(len(data))-10, 20) {
+		t.Errorf("expected the short copy to fail")
+	}
+}
+
+func TestCopyObject_CloneFallbacks(t *testing.T) {
+	errNotSupported := errors.New("not supported")
+
+	tests := []struct {
+		name         string
+		reflinkErr   error
+		copyRangeErr error
+		calls        []string
+	}{
+		{
+			name:  "reflink",
+			calls: []string{"reflink"},
+		},
+		{
+			name:       "copy_file_range fallback",
+			reflinkErr: errNotSupported,
+			calls:      []string{"reflink", "copy_file_range"},
+		},
+		{
+			name:         "userspace copy fallback",
+			reflinkErr:   errNotSupported,
+			copyRangeErr: errNotSupported,
+			calls:        []string{"reflink", "copy_file_range"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := newTestPosix(t, PosixOpts{})
+			createTestBucket(t, p, "bucket")
+			data := string(bytes.Repeat([]byte("object data "), 1000))
+			putTestObject(t, p, "bucket", "src", data)
+
+			calls := stubCloneFuncs(t, tt.reflinkErr, tt.copyRangeErr)
+
+			bucket, key, source, owner := "bucket", "dst", "bucket/src", ""
+			_, err := p.CopyObject(context.Background(), s3response.CopyObjectInput{
+				Bucket:              &bucket,
+				Key:                 &key,
+				CopySource:          &source,
+				ExpectedBucketOwner: &owner,
+			})
+			if err != nil {
+				t.Fatalf("copy object: %v", err)
+			}
+
+			if !slices.Equal(*calls, tt.calls) {
+				t.Errorf("expected calls %v, got %v", tt.calls, *calls)
+			}
+			if got := getTestObject(t, p, "bucket", "dst"); got != data {
+				t.Errorf("copied object data differs from the source data")
+			}
+		})
+	}
+}
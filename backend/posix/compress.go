@@ -0,0 +1,601 @@
+// Copyright 2026 Versity Software
+// This file is licensed under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package posix
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/klauspost/compress/s2"
+	"github.com/klauspost/compress/zstd"
+	"github.com/versity/versitygw/backend/meta"
+)
+
+// The objects of the buckets with the compression tag are compressed at
+// rest. The object data is compressed in independent frames, so ranged
+// reads only need to decompress the frames within the range. The object
+// file holds the compressed frames followed by the frame index, and the
+// compression attribute of the object records the uncompressed size and
+// the location of the frame index. The object etag and checksums are
+// calculated on the uncompressed data as usual.
+
+const (
+	// compressionTagKey is the bucket tag that enables the compression
+	// of the new objects in the bucket, the tag value is the algorithm
+	compressionTagKey = "versitygw:compression"
+	// compressionKey is the attribute of the compressed objects, and of
+	// the multipart uploads with compressed parts
+	compressionKey = "compression"
+	// compressedBucketKey is the attribute of the buckets that may have
+	// compressed objects, set when the compression is enabled and kept
+	// when it is disabled, as the existing objects stay compressed
+	compressedBucketKey = "compressed"
+
+	compressionNone = "none"
+	compressionZstd = "zstd"
+	compressionS2   = "s2"
+
+	// compressionFrameSize is the uncompressed size of the frames
+	compressionFrameSize = 1 << 20
+	// compressFrameEntrySize is the size of the frame index entries
+	compressFrameEntrySize = 16
+)
+
+var errCorruptCompressedData = errors.New("corrupt compressed object data")
+
+// compressedContentTypes are the content types of the data formats that
+// are already compressed, and are stored as is
+var compressedContentTypes = []string{
+	"application/gzip",
+	"application/x-gzip",
+	"application/zip",
+	"application/x-bzip2",
+	"application/x-xz",
+	"application/zstd",
+	"application/x-7z-compressed",
+	"application/vnd.rar",
+	"application/x-rar-compressed",
+	"application/x-lz4",
+	"application/x-snappy-framed",
+	"image/jpeg",
+	"image/png",
+	"image/gif",
+	"image/webp",
+	"image/avif",
+	"image/heic",
+}
+
+// compressedContentTypePrefixes are the content type prefixes of the
+// media formats that are already compressed
+var compressedContentTypePrefixes = []string{
+	"audio/",
+	"video/",
+}
+
+// compressionInfo describes the data layout of a compressed object
+type compressionInfo struct {
+	Algorithm string `json:"algorithm"`
+	// Size is the uncompressed object size
+	Size int64 `json:"size"`
+	// IndexOffset is the file offset of the frame index, which is also
+	// the size of the compressed frames
+	IndexOffset int64 `json:"indexOffset"`
+	Frames      int   `json:"frames"`
+}
+
+// compressFrame is a frame index entry, with the end offsets of the
+// frame in the compressed and uncompressed data
+type compressFrame struct {
+	compressedEnd   int64
+	uncompressedEnd int64
+}
+
+func newCompressionInfo(algo string, frames []compressFrame) compressionInfo {
+	info := compressionInfo{
+		Algorithm: algo,
+		Frames:    len(frames),
+	}
+	if len(frames) > 0 {
+		info.Size = frames[len(frames)-1].uncompressedEnd
+		info.IndexOffset = frames[len(frames)-1].compressedEnd
+	}
+	return info
+}
+
+type compressionCodec struct {
+	encode func(dst, src []byte) []byte
+	decode func(dst, src []byte) ([]byte, error)
+}
+
+var zstdCodec = sync.OnceValues(func() (compressionCodec, error) {
+	// the encoder and decoder are only used with EncodeAll and
+	// DecodeAll, which are safe for concurrent use
+	enc, err := zstd.NewWriter(nil)
+	if err != nil {
+		return compressionCodec{}, fmt.Errorf("init zstd encoder: %w", err)
+	}
+	dec, err := zstd.NewReader(nil)
+	if err != nil {
+		return compressionCodec{}, fmt.Errorf("init zstd decoder: %w", err)
+	}
+
+	return compressionCodec{
+		encode: func(dst, src []byte) []byte {
+			return enc.EncodeAll(src, dst[:0])
+		},
+		decode: func(dst, src []byte) ([]byte, error) {
+			return dec.DecodeAll(src, dst[:0])
+		},
+	}, nil
+})
+
+var s2Codec = compressionCodec{
+	encode: func(dst, src []byte) []byte {
+		return s2.Encode(dst[:cap(dst)], src)
+	},
+	decode: func(dst, src []byte) ([]byte, error) {
+		return s2.Decode(dst[:cap(dst)], src)
+	},
+}
+
+func getCompressionCodec(algo string) (compressionCodec, error) {
+	switch algo {
+	case compressionZstd:
+		return zstdCodec()
+	case compressionS2:
+		return s2Codec, nil
+	default:
+		return compressionCodec{}, fmt.Errorf("unsupported compression algorithm %q", algo)
+	}
+}
+
+func isValidCompressionTag(value string) bool {
+	switch value {
+	case compressionNone, compressionZstd, compressionS2:
+		return true
+	default:
+		return false
+	}
+}
+
+// isCompressedContent reports if the object data is already compressed
+// based on the content type and encoding
+func isCompressedContent(contentType, contentEncoding *string) bool {
+	for enc := range strings.SplitSeq(getString(contentEncoding), ",") {
+		enc = strings.ToLower(strings.TrimSpace(enc))
+		if enc != "" && enc != "identity" && enc != "aws-chunked" {
+			return true
+		}
+	}
+
+	ct, _, _ := strings.Cut(getString(contentType), ";")
+	ct = strings.ToLower(strings.TrimSpace(ct))
+	if slices.Contains(compressedContentTypes, ct) {
+		return true
+	}
+	for _, prefix := range compressedContentTypePrefixes {
+		if strings.HasPrefix(ct, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// getBucketCompression returns the compression algorithm of the bucket
+// compression tag, or an empty string if compression is not enabled
+func (p *Posix) getBucketCompression(bucket string) (string, error) {
+	b, err := p.meta.RetrieveAttribute(nil, bucket, "", tagHdr)
+	if errors.Is(err, meta.ErrNoSuchKey) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("get bucket tags: %w", err)
+	}
+
+	var tags map[string]string
+	err = json.Unmarshal(b, &tags)
+	if err != nil {
+		return "", fmt.Errorf("unmarshal bucket tags: %w", err)
+	}
+
+	algo := tags[compressionTagKey]
+	if algo == compressionNone || !isValidCompressionTag(algo) {
+		return "", nil
+	}
+	return algo, nil
+}
+
+// markBucketCompressed records that the bucket may have compressed
+// objects, if the bucket tags enable the compression
+func (p *Posix) markBucketCompressed(bucket string, tags map[string]string) error {
+	algo := tags[compressionTagKey]
+	if algo == compressionNone || !isValidCompressionTag(algo) {
+		return nil
+	}
+	err := p.meta.StoreAttribute(nil, bucket, "", compressedBucketKey, []byte{})
+	if err != nil {
+		return fmt.Errorf("set bucket compressed: %w", err)
+	}
+	return nil
+}
+
+// isBucketCompressed reports if the bucket may have compressed objects,
+// so the object sizes have to be read from the compression info instead
+// of the file sizes. The object sizes are read if it can't be determined.
+func (p *Posix) isBucketCompressed(bucket string) bool {
+	_, err := p.meta.RetrieveAttribute(nil, bucket, "", compressedBucketKey)
+	if !errors.Is(err, meta.ErrNoSuchKey) {
+		return true
+	}
+	// the compression may have been enabled by the bucket tag
+	// without recording the bucket attribute
+	algo, err := p.getBucketCompression(bucket)
+	return err != nil || algo != ""
+}
+
+// getObjectCompression returns the compression algorithm of a new
+// object in the bucket, or an empty string if the object is stored
+// uncompressed
+func (p *Posix) getObjectCompression(bucket string, contentType, contentEncoding *string) (string, error) {
+	if isCompressedContent(contentType, contentEncoding) {
+		return "", nil
+	}
+	return p.getBucketCompression(bucket)
+}
+
+// getCompressionInfo returns the compression info of the object, or nil
+// if the object is not compressed
+func (p *Posix) getCompressionInfo(f *os.File, bucket, object string) (*compressionInfo, error) {
+	b, err := p.meta.RetrieveAttribute(f, bucket, object, compressionKey)
+	if errors.Is(err, meta.ErrNoSuchKey) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get compression info: %w", err)
+	}
+
+	var info compressionInfo
+	err = json.Unmarshal(b, &info)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshal compression info: %w", err)
+	}
+	return &info, nil
+}
+
+func (p *Posix) storeCompressionInfo(f *os.File, bucket, object string, info compressionInfo) error {
+	b, err := json.Marshal(info)
+	if err != nil {
+		return fmt.Errorf("marshal compression info: %w", err)
+	}
+	err = p.meta.StoreAttribute(f, bucket, object, compressionKey, b)
+	if err != nil {
+		return fmt.Errorf("set compression info: %w", err)
+	}
+	return nil
+}
+
+// listedObjectSize returns the size of a listed object or part, reading
+// the compression info only if the listed data may be compressed
+func (p *Posix) listedObjectSize(compressed bool, bucket, object string, fi fs.FileInfo) (int64, error) {
+	if !compressed {
+		return fi.Size(), nil
+	}
+	return p.objectSize(nil, bucket, object, fi)
+}
+
+// objectSize returns the size of the object data, which is the
+// uncompressed size for the compressed objects
+func (p *Posix) objectSize(f *os.File, bucket, object string, fi fs.FileInfo) (int64, error) {
+	info, err := p.getCompressionInfo(f, bucket, object)
+	if err != nil {
+		return 0, err
+	}
+	if info == nil {
+		return fi.Size(), nil
+	}
+	return info.Size, nil
+}
+
+// objectDataReader returns the reader of the length bytes at offset of
+// the object data, decompressing the data of the compressed objects
+func objectDataReader(f *os.File, info *compressionInfo, offset, length int64) (io.Reader, error) {
+	if info == nil {
+		return io.NewSectionReader(f, offset, length), nil
+	}
+	return newDecompressReader(f, *info, offset, length)
+}
+
+// compressWriter compresses the data written to it in frames, and
+// writes the compressed frames to the underlying writer
+type compressWriter struct {
+	w      io.Writer
+	algo   string
+	codec  compressionCodec
+	limit  int64
+	buf    []byte
+	out    []byte
+	frames []compressFrame
+	offset int64
+	size   int64
+}
+
+// newCompressWriter returns a compressWriter that accepts up to limit
+// bytes of uncompressed data
+func newCompressWriter(w io.Writer, algo string, limit int64) (*compressWriter, error) {
+	codec, err := getCompressionCodec(algo)
+	if err != nil {
+		return nil, err
+	}
+
+	return &compressWriter{
+		w:     w,
+		algo:  algo,
+		codec: codec,
+		limit: limit,
+		buf:   make([]byte, 0, min(limit, compressionFrameSize)),
+	}, nil
+}
+
+func (cw *compressWriter) Write(b []byte) (int, error) {
+	if int64(len(b)) > cw.limit {
+		return 0, fmt.Errorf("write exceeds content length %v", cw.limit)
+	}
+
+	var n int
+	for len(b) > 0 {
+		if len(cw.buf) == cap(cw.buf) {
+			cw.buf = slices.Grow(cw.buf, min(len(b), compressionFrameSize-len(cw.buf)))
+		}
+		c := copy(cw.buf[len(cw.buf):cap(cw.buf)], b)
+		cw.buf = cw.buf[:len(cw.buf)+c]
+		cw.limit -= int64(c)
+		b = b[c:]
+		n += c
+
+		if len(cw.buf) == compressionFrameSize {
+			err := cw.flush()
+			if err != nil {
+				return n, err
+			}
+		}
+	}
+	return n, nil
+}
+
+func (cw *compressWriter) flush() error {
+	if len(cw.buf) == 0 {
+		return nil
+	}
+
+	cw.out = cw.codec.encode(cw.out, cw.buf)
+	_, err := cw.w.Write(cw.out)
+	if err != nil {
+		return err
+	}
+
+	cw.offset += int64(len(cw.out))
+	cw.size += int64(len(cw.buf))
+	cw.frames = append(cw.frames, compressFrame{
+		compressedEnd:   cw.offset,
+		uncompressedEnd: cw.size,
+	})
+	cw.buf = cw.buf[:0]
+	return nil
+}
+
+// finish compresses the buffered data and writes the frame index
+func (cw *compressWriter) finish() (compressionInfo, error) {
+	err := cw.flush()
+	if err != nil {
+		return compressionInfo{}, err
+	}
+
+	err = writeCompressIndex(cw.w, cw.frames)
+	if err != nil {
+		return compressionInfo{}, err
+	}
+
+	return newCompressionInfo(cw.algo, cw.frames), nil
+}
+
+func writeCompressIndex(w io.Writer, frames []compressFrame) error {
+	b := make([]byte, 0, len(frames)*compressFrameEntrySize)
+	for _, frame := range frames {
+		b = binary.LittleEndian.AppendUint64(b, uint64(frame.compressedEnd))
+		b = binary.LittleEndian.AppendUint64(b, uint64(frame.uncompressedEnd))
+	}
+	_, err := w.Write(b)
+	if err != nil {
+		return fmt.Errorf("write compression index: %w", err)
+	}
+	return nil
+}
+
+func readCompressIndex(r io.ReaderAt, info compressionInfo) ([]compressFrame, error) {
+	b := make([]byte, info.Frames*compressFrameEntrySize)
+	_, err := io.ReadFull(io.NewSectionReader(r, info.IndexOffset, int64(len(b))), b)
+	if err != nil {
+		return nil, fmt.Errorf("read compression index: %w", err)
+	}
+
+	frames := make([]compressFrame, info.Frames)
+	var prev compressFrame
+	for i := range frames {
+		frames[i] = compressFrame{
+			compressedEnd:   int64(binary.LittleEndian.Uint64(b[i*compressFrameEntrySize:])),
+			uncompressedEnd: int64(binary.LittleEndian.Uint64(b[i*compressFrameEntrySize+8:])),
+		}
+		if frames[i].compressedEnd <= prev.compressedEnd || frames[i].uncompressedEnd <= prev.uncompressedEnd {
+			return nil, errCorruptCompressedData
+		}
+		prev = frames[i]
+	}
+
+	if newCompressionInfo(info.Algorithm, frames) != info {
+		return nil, errCorruptCompressedData
+	}
+	return frames, nil
+}
+
+// appendCompressIndex appends the frames of the data appended at the
+// end of the compressed data of the index frames
+func appendCompressIndex(frames, appended []compressFrame) []compressFrame {
+	var base compressFrame
+	if len(frames) > 0 {
+		base = frames[len(frames)-1]
+	}
+	for _, frame := range appended {
+		frames = append(frames, compressFrame{
+			compressedEnd:   base.compressedEnd + frame.compressedEnd,
+			uncompressedEnd: base.uncompressedEnd + frame.uncompressedEnd,
+		})
+	}
+	return frames
+}
+
+// decompressReader reads a range of the uncompressed object data, only
+// the frames within the range are read and decompressed
+type decompressReader struct {
+	f         io.ReaderAt
+	codec     compressionCodec
+	frames    []compressFrame
+	next      int
+	skip      int64
+	remaining int64
+	in        []byte
+	buf       []byte
+	data      []byte
+}
+
+func newDecompressReader(f io.ReaderAt, info compressionInfo, offset, length int64) (*decompressReader, error) {
+	codec, err := getCompressionCodec(info.Algorithm)
+	if err != nil {
+		return nil, err
+	}
+
+	frames, err := readCompressIndex(f, info)
+	if err != nil {
+		return nil, err
+	}
+
+	next := sort.Search(len(frames), func(i int) bool {
+		return frames[i].uncompressedEnd > offset
+	})
+	var start int64
+	if next > 0 {
+		start = frames[next-1].uncompressedEnd
+	}
+
+	return &decompressReader{
+		f:         f,
+		codec:     codec,
+		frames:    frames,
+		next:      next,
+		skip:      offset - start,
+		remaining: length,
+	}, nil
+}
+
+func (d *decompressReader) Read(b []byte) (int, error) {
+	if d.remaining <= 0 {
+		return 0, io.EOF
+	}
+
+	if len(d.data) == 0 {
+		err := d.readFrame()
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	n := copy(b, d.data[:min(int64(len(d.data)), d.remaining)])
+	d.data = d.data[n:]
+	d.remaining -= int64(n)
+	return n, nil
+}
+
+func (d *decompressReader) readFrame() error {
+	if d.next >= len(d.frames) {
+		return io.ErrUnexpectedEOF
+	}
+
+	var start compressFrame
+	if d.next > 0 {
+		start = d.frames[d.next-1]
+	}
+	frame := d.frames[d.next]
+
+	d.in = slices.Grow(d.in[:0], int(frame.compressedEnd-start.compressedEnd))
+	d.in = d.in[:frame.compressedEnd-start.compressedEnd]
+	n, err := d.f.ReadAt(d.in, start.compressedEnd)
+	if err != nil && !(errors.Is(err, io.EOF) && n == len(d.in)) {
+		return fmt.Errorf("read compressed frame: %w", err)
+	}
+
+	d.buf, err = d.codec.decode(d.buf, d.in)
+	if err != nil {
+		return fmt.Errorf("%w: decompress frame: %v", errCorruptCompressedData, err)
+	}
+	if int64(len(d.buf)) != frame.uncompressedEnd-start.uncompressedEnd || d.skip > int64(len(d.buf)) {
+		return errCorruptCompressedData
+	}
+
+	d.data = d.buf[d.skip:]
+	d.skip = 0
+	d.next++
+	return nil
+}
+
+// clearCompressionInfo removes the compression info of an overwritten
+// compressed object once the uncompressed object data is in place
+func (p *Posix) clearCompressionInfo(bucket, object string) error {
+	err := p.meta.DeleteAttribute(bucket, object, compressionKey)
+	if err != nil && !errors.Is(err, meta.ErrNoSuchKey) && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("remove compression info: %w", err)
+	}
+	return nil
+}
+
+// appendCompressedFrames appends the compressed frames of a part to the
+// compressed object data, and returns the object frame index with the
+// part frames appended
+func (p *Posix) appendCompressedFrames(dst, src *os.File, info *compressionInfo, frames []compressFrame) ([]compressFrame, error) {
+	if info == nil {
+		return nil, errors.New("part of compressed upload is not compressed")
+	}
+
+	partFrames, err := readCompressIndex(src, *info)
+	if err != nil {
+		return nil, err
+	}
+
+	if !p.cloneFileData(dst, src, 0, info.IndexOffset) {
+		_, err = io.Copy(dst, io.NewSectionReader(src, 0, info.IndexOffset))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return appendCompressIndex(frames, partFrames), nil
+}
@@ -0,0 +1,356 @@
+// Copyright 2026 Versity Software
+// This file is licensed under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package posix
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+)
+
+// compressibleData returns size bytes of text with random runs,
+// so the data compresses but isn't all the same frame content
+func compressibleData(size int) []byte {
+	rnd := rand.New(rand.NewSource(int64(size)))
+	words := []string{"versity ", "gateway ", "object ", "frame ", "data "}
+
+	var b bytes.Buffer
+	for b.Len() < size {
+		if rnd.Intn(8) == 0 {
+			fmt.Fprintf(&b, "%x", rnd.Int63())
+			continue
+		}
+		b.WriteString(words[rnd.Intn(len(words))])
+	}
+	return b.Bytes()[:size]
+}
+
+// compressTestData compresses the data with the algorithm, writing the
+// data in chunks not aligned to the frames
+func compressTestData(t *testing.T, algo string, data []byte) ([]byte, compressionInfo) {
+	t.Helper()
+
+	var out bytes.Buffer
+	cw, err := newCompressWriter(&out, algo, int64(len(data)))
+	if err != nil {
+		t.Fatalf("new compress writer: %v", err)
+	}
+	for b := data; len(b) > 0; {
+		n := min(len(b), 77777)
+		_, err := cw.Write(b[:n])
+		if err != nil {
+			t.Fatalf("compress: %v", err)
+		}
+		b = b[n:]
+	}
+	info, err := cw.finish()
+	if err != nil {
+		t.Fatalf("finish compression: %v", err)
+	}
+	return out.Bytes(), info
+}
+
+func readCompressedRange(t *testing.T, compressed []byte, info compressionInfo, offset, length int64) []byte {
+	t.Helper()
+
+	r, err := newDecompressReader(bytes.NewReader(compressed), info, offset, length)
+	if err != nil {
+		t.Fatalf("new decompress reader: %v", err)
+	}
+	got, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatalf("decompress range %v-%v: %v", offset, offset+length, err)
+	}
+	return got
+}
+
+func TestCompressFraming(t *testing.T) {
+	sizes := []int{
+		1,
+		compressionFrameSize - 1,
+		compressionFrameSize,
+		compressionFrameSize + 1,
+		3*compressionFrameSize + 12345,
+	}
+
+	for _, algo := range []string{compressionZstd, compressionS2} {
+		for _, size := range sizes {
+			t.Run(fmt.Sprintf("%v/%v", algo, size), func(t *testing.T) {
+				data := compressibleData(size)
+				compressed, info := compressTestData(t, algo, data)
+
+				frames := (size + compressionFrameSize - 1) / compressionFrameSize
+				if info.Algorithm != algo || info.Size != int64(size) || info.Frames != frames {
+					t.Fatalf("unexpected compression info %+v", info)
+				}
+				if int64(len(compressed)) != info.IndexOffset+int64(frames*compressFrameEntrySize) {
+					t.Fatalf("expected the frame index at %v, got %v bytes of compressed data",
+						info.IndexOffset, len(compressed))
+				}
+				if info.IndexOffset >= int64(size) && size > 1 {
+					t.Errorf("expected the data to be compressed, got %v bytes of %v", info.IndexOffset, size)
+				}
+
+				index, err := readCompressIndex(bytes.NewReader(compressed), info)
+				if err != nil {
+					t.Fatalf("read frame index: %v", err)
+				}
+				for i, frame := range index {
+					if want := int64(min((i+1)*compressionFrameSize, size)); frame.uncompressedEnd != want {
+						t.Errorf("frame %v: expected uncompressed end %v, got %v", i, want, frame.uncompressedEnd)
+					}
+				}
+
+				got := readCompressedRange(t, compressed, info, 0, int64(size))
+				if !bytes.Equal(got, data) {
+					t.Errorf("decompressed data differs from the data")
+				}
+			})
+		}
+	}
+}
+
+func TestCompressRangedReads(t *testing.T) {
+	const frame = compressionFrameSize
+	size := 3*frame + 1000
+
+	for _, algo := range []string{compressionZstd, compressionS2} {
+		data := compressibleData(size)
+		compressed, info := compressTestData(t, algo, data)
+
+		ranges := []struct {
+			offset, length int64
+		}{
+			{0, 1},
+			{0, frame},
+			{frame - 10, 20},
+			{frame, frame},
+			{frame - 1, frame + 2},
+			{10, 3 * frame},
+			{3 * frame, 1000},
+			{int64(size) - 1, 1},
+			{int64(size) / 2, 0},
+		}
+		for _, rng := range ranges {
+			t.Run(fmt.Sprintf("%v/%v-%v", algo, rng.offset, rng.length), func(t *testing.T) {
+				got := readCompressedRange(t, compressed, info, rng.offset, rng.length)
+				want := data[rng.offset : rng.offset+rng.length]
+				if !bytes.Equal(got, want) {
+					t.Errorf("expected %v bytes of the data range, got %v differing bytes", len(want), len(got))
+				}
+			})
+		}
+	}
+}
+
+func TestCompressAppendFrames(t *testing.T) {
+	// the multipart upload parts are compressed separately, and the
+	// object is the concatenated frames with the combined index
+	part1 := compressibleData(compressionFrameSize + 100)
+	part2 := compressibleData(2000)
+
+	c1, info1 := compressTestData(t, compressionZstd, part1)
+	c2, info2 := compressTestData(t, compressionZstd, part2)
+	frames1, err := readCompressIndex(bytes.NewReader(c1), info1)
+	if err != nil {
+		t.Fatalf("read part 1 index: %v", err)
+	}
+	frames2, err := readCompressIndex(bytes.NewReader(c2), info2)
+	if err != nil {
+		t.Fatalf("read part 2 index: %v", err)
+	}
+
+	var obj bytes.Buffer
+	obj.Write(c1[:info1.IndexOffset])
+	obj.Write(c2[:info2.IndexOffset])
+	frames := appendCompressIndex(appendCompressIndex(nil, frames1), frames2)
+	err = writeCompressIndex(&obj, frames)
+	if err != nil {
+		t.Fatalf("write index: %v", err)
+	}
+	info := newCompressionInfo(compressionZstd, frames)
+
+	data := append(append([]byte{}, part1...), part2...)
+	if info.Size != int64(len(data)) || info.Frames != 3 {
+		t.Fatalf("unexpected compression info %+v", info)
+	}
+	got := readCompressedRange(t, obj.Bytes(), info, 0, info.Size)
+	if !bytes.Equal(got, data) {
+		t.Errorf("decompressed data differs from the parts data")
+	}
+	got = readCompressedRange(t, obj.Bytes(), info, int64(len(part1))-5, 10)
+	if !bytes.Equal(got, data[len(part1)-5:len(part1)+5]) {
+		t.Errorf("decompressed range across the parts differs from the data")
+	}
+}
+
+func TestCompressCorruptData(t *testing.T) {
+	data := compressibleData(2*compressionFrameSize + 10)
+	compressed, info := compressTestData(t, compressionS2, data)
+
+	// the frame index doesn't match the compression info
+	bad := bytes.Clone(compressed)
+	bad[len(bad)-compressFrameEntrySize] ^= 0xff
+	_, err := newDecompressReader(bytes.NewReader(bad), info, 0, info.Size)
+	if !errors.Is(err, errCorruptCompressedData) {
+		t.Errorf("expected corrupt index error, got %v", err)
+	}
+
+	// the frame data can't be decompressed
+	bad = bytes.Clone(compressed)
+	for i := range 64 {
+		bad[i] ^= 0xff
+	}
+	r, err := newDecompressReader(bytes.NewReader(bad), info, 0, info.Size)
+	if err != nil {
+		t.Fatalf("new decompress reader: %v", err)
+	}
+	_, err = io.ReadAll(r)
+	if !errors.Is(err, errCorruptCompressedData) {
+		t.Errorf("expected corrupt frame error, got %v", err)
+	}
+
+	cw, err := newCompressWriter(io.Discard, compressionZstd, 10)
+	if err != nil {
+		t.Fatalf("new compress writer: %v", err)
+	}
+	_, err = cw.Write(make([]byte, 11))
+	if err == nil {
+		t.Errorf("expected writing past the content length to fail")
+	}
+}
+
+func TestIsCompressedContent(t *testing.T) {
+	tests := []struct {
+		contentType     string
+		contentEncoding string
+		compressed      bool
+	}{
+		{"text/plain", "", false},
+		{"application/octet-stream", "aws-chunked", false},
+		{"text/plain", "gzip", true},
+		{"application/zip", "", true},
+		{"image/png", "", true},
+		{"Video/MP4; codecs=avc1", "", true},
+		{"image/svg+xml", "identity", false},
+	}
+	for _, tt := range tests {
+		got := isCompressedContent(&tt.contentType, &tt.contentEncoding)
+		if got != tt.compressed {
+			t.Errorf("%q %q: expected compressed %v, got %v",
+				tt.contentType, tt.contentEncoding, tt.compressed, got)
+		}
+	}
+}
+
+func TestCompressedBucket(t *testing.T) {
+	p := newTestPosix(t, PosixOpts{})
+	ctx := context.Background()
+	createTestBucket(t, p, "bucket")
+
+	err := p.PutBucketTagging(ctx, "bucket", map[string]string{compressionTagKey: compressionZstd})
+	if err != nil {
+		t.Fatalf("put bucket tagging: %v", err)
+	}
+
+	data := string(compressibleData(2*compressionFrameSize + 100))
+	putTestObject(t, p, "bucket", "obj", data)
+
+	fi, err := p.rootfs.Stat("bucket/obj")
+	if err != nil {
+		t.Fatalf("stat object: %v", err)
+	}
+	if fi.Size() >= int64(len(data)) {
+		t.Errorf("expected the object to be stored compressed, got %v bytes", fi.Size())
+	}
+	if got := getTestObject(t, p, "bucket", "obj"); got != data {
+		t.Errorf("object data differs from the uploaded data")
+	}
+
+	bucket, key, rng := "bucket", "obj", fmt.Sprintf("bytes=%v-%v", compressionFrameSize-5, compressionFrameSize+4)
+	out, err := p.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: &bucket,
+		Key:    &key,
+		Range:  &rng,
+	})
+	if err != nil {
+		t.Fatalf("get object range: %v", err)
+	}
+	got, err := io.ReadAll(out.Body)
+	out.Body.Close()
+	if err != nil || string(got) != data[compressionFrameSize-5:compressionFrameSize+5] {
+		t.Errorf("object range differs from the uploaded data: %v", err)
+	}
+
+	// the compressed objects are listed with the uncompressed
+	// size after the compression is disabled
+	err = p.PutBucketTagging(ctx, "bucket", nil)
+	if err != nil {
+		t.Fatalf("remove bucket tagging: %v", err)
+	}
+	putTestObject(t, p, "bucket", "plain", "uncompressed")
+
+	startAfter, maxKeys := "", int32(1000)
+	res, err := p.ListObjectsV2(ctx, &s3.ListObjectsV2Input{
+		Bucket:     &bucket,
+		StartAfter: &startAfter,
+		MaxKeys:    &maxKeys,
+	})
+	if err != nil {
+		t.Fatalf("list objects: %v", err)
+	}
+	sizes := map[string]int64{}
+	for _, obj := range res.Contents {
+		sizes[*obj.Key] = *obj.Size
+	}
+	if sizes["obj"] != int64(len(data)) || sizes["plain"] != int64(len("uncompressed")) {
+		t.Errorf("unexpected listed object sizes %v", sizes)
+	}
+}
+
+func TestUncompressedBucket(t *testing.T) {
+	p := newTestPosix(t, PosixOpts{})
+	createTestBucket(t, p, "bucket")
+
+	if p.isBucketCompressed("bucket") {
+		t.Errorf("expected the bucket without compression to not be compressed")
+	}
+
+	err := p.PutBucketTagging(context.Background(), "bucket", map[string]string{compressionTagKey: compressionNone})
+	if err != nil {
+		t.Fatalf("put bucket tagging: %v", err)
+	}
+	if p.isBucketCompressed("bucket") {
+		t.Errorf("expected the bucket with compression none to not be compressed")
+	}
+
+	err = p.PutBucketTagging(context.Background(), "bucket", map[string]string{compressionTagKey: compressionS2})
+	if err != nil {
+		t.Fatalf("put bucket tagging: %v", err)
+	}
+	err = p.PutBucketTagging(context.Background(), "bucket", map[string]string{"other": "tag"})
+	if err != nil {
+		t.Fatalf("put bucket tagging: %v", err)
+	}
+	if !p.isBucketCompressed("bucket") {
+		t.Errorf("expected the bucket to stay compressed after the compression is disabled")
+	}
+}
@@ -0,0 +1,93 @@
+// Copyright 2026 Versity Software
+// This file is licensed under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package posix
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/versity/versitygw/backend"
+	"github.com/versity/versitygw/backend/meta"
+)
+
+const (
+	// healthCheckPrefix is the name prefix of the files created by the
+	// health checks in the gateway root directory. The top level files
+	// are not listed as buckets.
+	healthCheckPrefix = ".vgw-health-"
+	healthCheckAttr   = "vgw-health"
+)
+
+var _ backend.HealthChecker = &Posix{}
+
+// CheckHealth checks the gateway root directory is writable and the
+// metadata storage is usable with a short lived file. The read only
+// root directories are only checked to be readable. The filesystem
+// calls can't be interrupted, the caller has to bound the wait.
+func (p *Posix) CheckHealth(_ context.Context) error {
+	f, err := os.CreateTemp(p.rootfs.Dir(), healthCheckPrefix)
+	if errors.Is(err, syscall.EROFS) {
+		_, err = p.rootfs.ReadDir(".")
+		if err != nil {
+			return fmt.Errorf("read root directory: %w", err)
+		}
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("create file in root directory: %w", err)
+	}
+
+	name := filepath.Base(f.Name())
+	defer p.rootfs.Remove(name)
+
+	value := []byte(strconv.FormatInt(time.Now().UnixNano(), 10))
+	_, err = f.Write(value)
+	if err != nil {
+		f.Close()
+		return fmt.Errorf("write file in root directory: %w", err)
+	}
+	err = f.Close()
+	if err != nil {
+		return fmt.Errorf("close file in root directory: %w", err)
+	}
+
+	err = p.meta.StoreAttribute(nil, name, "", healthCheckAttr, value)
+	if err != nil {
+		return fmt.Errorf("store metadata: %w", err)
+	}
+	defer p.meta.DeleteAttribute(name, "", healthCheckAttr)
+
+	got, err := p.meta.RetrieveAttribute(nil, name, "", healthCheckAttr)
+	if errors.Is(err, meta.ErrNoSuchKey) {
+		if _, ok := p.meta.(meta.NoMeta); ok {
+			return nil
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("retrieve metadata: %w", err)
+	}
+	if !bytes.Equal(got, value) {
+		return fmt.Errorf("retrieve metadata: unexpected value")
+	}
+
+	return nil
+}
@@ -0,0 +1,1005 @@
+// Copyright 2026 Versity Software
+// This file is licensed under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package posix
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/versity/versitygw/backend"
+	bolt "go.etcd.io/bbolt"
+)
+
+var (
+	// listIndexKeysBucket holds a nested bucket per gateway bucket,
+	// with the object keys of the bucket as the keys
+	listIndexKeysBucket = []byte("keys")
+	// listIndexStateBucket holds the index state of each gateway bucket
+	listIndexStateBucket = []byte("state")
+	// watchStateBucket holds a nested bucket per gateway bucket watched,
+	// with the object file states seen last by the watcher
+	watchStateBucket = []byte("watch")
+)
+
+const (
+	// listIndexBatchSize is the number of keys written or removed by
+	// the reconciler in a single database transaction
+	listIndexBatchSize = 10000
+	// listIndexOpenTimeout is how long to wait for the database file
+	// lock, the database can only be opened by one process at a time
+	listIndexOpenTimeout = 5 * time.Second
+)
+
+// listIndexState is the index state of a bucket
+type listIndexState struct {
+	// Generation is incremented by each reconcile of the bucket index,
+	// the keys not found by the reconcile keep the old generation and
+	// are removed once the reconcile has walked the bucket
+	Generation uint64 `json:"generation"`
+	// Ready is set once the index has all bucket objects, the bucket
+	// is listed from the filesystem until then
+	Ready        bool      `json:"ready"`
+	ReconciledAt time.Time `json:"reconciledAt"`
+}
+
+// listIndex is the persistent sorted key index of the bucket objects. The
+// listings of the large buckets read and sort the bucket directories on
+// every list request, so each page of a paginated listing re-scans the
+// directories up to the marker. The listings from the index seek straight
+// to the marker instead. The index is maintained by the object writes and
+// deletes of the gateway, and reconciled with the filesystem by a
+// background reconciler, so the objects added or removed outside of the
+// gateway show up in the listings once the reconciler has run.
+type listIndex struct {
+	db       *bolt.DB
+	interval time.Duration
+
+	// dirty are the buckets queued for a reconcile
+	mu    sync.Mutex
+	dirty map[string]struct{}
+	wake  chan struct{}
+
+	// locks serialize the index updates of each bucket, so the keys
+	// found by a reconcile are not written after the objects are removed
+	locksMu sync.Mutex
+	locks   map[string]*bucketIndexLock
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func newListIndex(path string, interval time.Duration) (*listIndex, error) {
+	db, err := bolt.Open(path, 0600, &bolt.Options{Timeout: listIndexOpenTimeout})
+	if err != nil {
+		return nil, fmt.Errorf("open list index database: %w", err)
+	}
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(listIndexKeysBucket)
+		if err != nil {
+			return err
+		}
+		_, err = tx.CreateBucketIfNotExists(listIndexStateBucket)
+		if err != nil {
+			return err
+		}
+		_, err = tx.CreateBucketIfNotExists(watchStateBucket)
+		return err
+	})
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("init list index database: %w", err)
+	}
+
+	return &listIndex{
+		db:       db,
+		interval: interval,
+		dirty:    make(map[string]struct{}),
+		wake:     make(chan struct{}, 1),
+		locks:    make(map[string]*bucketIndexLock),
+	}, nil
+}
+
+// bucketIndexLock is the index lock of a bucket, removed once it has
+// no more holders or waiters
+type bucketIndexLock struct {
+	sync.Mutex
+	refs int
+}
+
+// lockBucket locks the index updates of the bucket, the returned
+// function unlocks it
+func (li *listIndex) lockBucket(bucket string) func() {
+	li.locksMu.Lock()
+	l, ok := li.locks[bucket]
+	if !ok {
+		l = &bucketIndexLock{}
+		li.locks[bucket] = l
+	}
+	l.refs++
+	li.locksMu.Unlock()
+
+	l.Lock()
+	return func() {
+		l.Unlock()
+
+		li.locksMu.Lock()
+		l.refs--
+		if l.refs == 0 {
+			delete(li.locks, bucket)
+		}
+		li.locksMu.Unlock()
+	}
+}
+
+func getListIndexState(tx *bolt.Tx, bucket string) (listIndexState, error) {
+	var state listIndexState
+	b := tx.Bucket(listIndexStateBucket).Get([]byte(bucket))
+	if b == nil {
+		return state, nil
+	}
+	err := json.Unmarshal(b, &state)
+	if err != nil {
+		return state, fmt.Errorf("unmarshal list index state: %w", err)
+	}
+	return state, nil
+}
+
+func putListIndexState(tx *bolt.Tx, bucket string, state listIndexState) error {
+	b, err := json.Marshal(state)
+	if err != nil {
+		return fmt.Errorf("marshal list index state: %w", err)
+	}
+	return tx.Bucket(listIndexStateBucket).Put([]byte(bucket), b)
+}
+
+func encodeGeneration(gen uint64) []byte {
+	return binary.BigEndian.AppendUint64(nil, gen)
+}
+
+// update adds the key to the bucket index if exists is set, otherwise
+// removes the key from the index
+func (li *listIndex) update(bucket, key string, exists bool) error {
+	return li.db.Update(func(tx *bolt.Tx) error {
+		if !exists {
+			keys := tx.Bucket(listIndexKeysBucket).Bucket([]byte(bucket))
+			if keys == nil {
+				return nil
+			}
+			return keys.Delete([]byte(key))
+		}
+
+		keys, err := tx.Bucket(listIndexKeysBucket).CreateBucketIfNotExists([]byte(bucket))
+		if err != nil {
+			return err
+		}
+
+		state, err := getListIndexState(tx, bucket)
+		if err != nil {
+			return err
+		}
+		return keys.Put([]byte(key), encodeGeneration(state.Generation))
+	})
+}
+
+// initBucket sets up the empty ready index of a new bucket
+func (li *listIndex) initBucket(bucket string) error {
+	return li.db.Update(func(tx *bolt.Tx) error {
+		keys := tx.Bucket(listIndexKeysBucket)
+		err := keys.DeleteBucket([]byte(bucket))
+		if err != nil && !errors.Is(err, bolt.ErrBucketNotFound) {
+			return err
+		}
+		_, err = keys.CreateBucket([]byte(bucket))
+		if err != nil {
+			return err
+		}
+		return putListIndexState(tx, bucket, listIndexState{
+			Ready:        true,
+			ReconciledAt: time.Now().UTC(),
+		})
+	})
+}
+
+// removeBucket removes the index and the watcher state of a deleted bucket
+func (li *listIndex) removeBucket(bucket string) error {
+	return li.db.Update(func(tx *bolt.Tx) error {
+		err := tx.Bucket(listIndexKeysBucket).DeleteBucket([]byte(bucket))
+		if err != nil && !errors.Is(err, bolt.ErrBucketNotFound) {
+			return err
+		}
+		err = tx.Bucket(watchStateBucket).DeleteBucket([]byte(bucket))
+		if err != nil && !errors.Is(err, bolt.ErrBucketNotFound) {
+			return err
+		}
+		return tx.Bucket(listIndexStateBucket).Delete([]byte(bucket))
+	})
+}
+
+// markDirty queues a reconcile of the bucket index, e.g. when the
+// bucket is known to be changed outside of the gateway
+func (li *listIndex) markDirty(bucket string) {
+	li.mu.Lock()
+	li.dirty[bucket] = struct{}{}
+	li.mu.Unlock()
+
+	select {
+	case li.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (li *listIndex) takeDirty() []string {
+	li.mu.Lock()
+	defer li.mu.Unlock()
+
+	buckets := make([]string, 0, len(li.dirty))
+	for bucket := range li.dirty {
+		buckets = append(buckets, bucket)
+	}
+	clear(li.dirty)
+	return buckets
+}
+
+func (li *listIndex) close() error {
+	if li.cancel != nil {
+		li.cancel()
+		li.wg.Wait()
+	}
+	return li.db.Close()
+}
+
+// prefixEnd returns the first key after all of the keys with the prefix,
+// or nil if there is none
+func prefixEnd(prefix string) []byte {
+	end := []byte(prefix)
+	for i := len(end) - 1; i >= 0; i-- {
+		if end[i] < 0xff {
+			end[i]++
+			return end[:i+1]
+		}
+	}
+	return nil
+}
+
+// seekPastPrefix moves the cursor to the first key after all of the
+// keys with the prefix
+func seekPastPrefix(c *bolt.Cursor, prefix string) []byte {
+	end := prefixEnd(prefix)
+	if end == nil {
+		return nil
+	}
+	k, _ := c.Seek(end)
+	return k
+}
+
+// seekStart returns the first key of the listings with the prefix
+// starting at the marker
+func seekStart(prefix, marker string) []byte {
+	if marker > prefix {
+		return []byte(marker)
+	}
+	return []byte(prefix)
+}
+
+// listedKey returns the key and whether it is listed as a common prefix
+func listedKey(key, prefix, delimiter string) (string, bool) {
+	if delimiter == "" {
+		return key, false
+	}
+	before, _, found := strings.Cut(key[len(prefix):], delimiter)
+	if !found {
+		return key, false
+	}
+	return prefix + before + delimiter, true
+}
+
+// syncListIndex updates the bucket index with the current state of the
+// object in the filesystem. It is called after the object is written or
+// removed, regardless of the result, as the index only has to follow
+// the filesystem.
+func (p *Posix) syncListIndex(bucket, object string) {
+	if p.listIndex == nil {
+		return
+	}
+
+	unlock := p.listIndex.lockBucket(bucket)
+	defer unlock()
+
+	err := p.listIndex.update(bucket, object, p.isListedObject(bucket, object))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "list index: update %v/%v: %v\n", bucket, object, err)
+		p.listIndex.markDirty(bucket)
+	}
+}
+
+// initListIndexBucket sets up the index of a new bucket
+func (p *Posix) initListIndexBucket(bucket string) {
+	if p.listIndex == nil {
+		return
+	}
+
+	unlock := p.listIndex.lockBucket(bucket)
+	defer unlock()
+
+	err := p.listIndex.initBucket(bucket)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "list index: init %v: %v\n", bucket, err)
+		p.listIndex.markDirty(bucket)
+	}
+}
+
+// removeListIndexBucket removes the index of a deleted bucket
+func (p *Posix) removeListIndexBucket(bucket string) {
+	if p.listIndex == nil {
+		return
+	}
+
+	err := p.lockedRemoveListIndexBucket(bucket)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "list index: remove %v: %v\n", bucket, err)
+	}
+}
+
+func (p *Posix) lockedRemoveListIndexBucket(bucket string) error {
+	unlock := p.listIndex.lockBucket(bucket)
+	defer unlock()
+	return p.listIndex.removeBucket(bucket)
+}
+
+// markListIndexDirty queues a reconcile of the bucket index
+func (p *Posix) markListIndexDirty(bucket string) {
+	if p.listIndex == nil {
+		return
+	}
+	p.listIndex.markDirty(bucket)
+}
+
+// isListedObject reports if the object exists in the filesystem as an
+// object listed by the bucket listings
+func (p *Posix) isListedObject(bucket, object string) bool {
+	fi, err := p.rootfs.Lstat(filepath.Join(bucket, object))
+	if err != nil {
+		return false
+	}
+	if !strings.HasSuffix(object, "/") {
+		return !fi.IsDir()
+	}
+	if !fi.IsDir() {
+		return false
+	}
+	// the directories are only objects if created as directory objects
+	_, err = p.meta.RetrieveAttribute(nil, bucket, object, etagkey)
+	return err == nil
+}
+
+// indexDirEntry returns the directory entry of the indexed object for
+// the object info functions of the listings
+func (p *Posix) indexDirEntry(bucket, key string) (fs.DirEntry, error) {
+	fi, err := p.rootfs.Lstat(filepath.Join(bucket, key))
+	if err != nil {
+		return nil, err
+	}
+	return fs.FileInfoToDirEntry(fi), nil
+}
+
+// walkObjects returns the bucket objects for the object listings, from the
+// list index if the bucket index is ready, otherwise from the filesystem
+func (p *Posix) walkObjects(ctx context.Context, bucket, prefix, delim, marker string, max int32, getObj backend.GetObjFunc) (backend.WalkResults, error) {
+	if p.listIndex != nil && max > 0 {
+		results, ok, err := p.walkListIndex(ctx, bucket, prefix, delim, marker, max, getObj)
+		if err != nil || ok {
+			return results, err
+		}
+	}
+
+	fileSystem := os.DirFS(p.rootfs.Path(bucket))
+	return backend.Walk(ctx, fileSystem, prefix, delim, marker, max,
+		getObj, []string{MetaTmpDir})
+}
+
+// walkListIndex lists the bucket objects from the list index, it returns
+// false if the bucket index is not ready
+func (p *Posix) walkListIndex(ctx context.Context, bucket, prefix, delim, marker string, max int32, getObj backend.GetObjFunc) (backend.WalkResults, bool, error) {
+	var results backend.WalkResults
+	var ready, stale bool
+
+	err := p.listIndex.db.View(func(tx *bolt.Tx) error {
+		state, err := getListIndexState(tx, bucket)
+		if err != nil {
+			return err
+		}
+		keys := tx.Bucket(listIndexKeysBucket).Bucket([]byte(bucket))
+		if !state.Ready || keys == nil {
+			return nil
+		}
+		ready = true
+
+		count := int32(0)
+		c := keys.Cursor()
+		k, _ := c.Seek(seekStart(prefix, marker))
+		for k != nil {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+
+			key := string(k)
+			if !strings.HasPrefix(key, prefix) {
+				break
+			}
+			if key <= marker {
+				k, _ = c.Next()
+				continue
+			}
+
+			name, isCommonPrefix := listedKey(key, prefix, delim)
+			if isCommonPrefix && name <= marker {
+				// the common prefix was listed up to the marker
+				k = seekPastPrefix(c, name)
+				continue
+			}
+			if count == max {
+				results.Truncated = true
+				break
+			}
+
+			if isCommonPrefix {
+				results.CommonPrefixes = append(results.CommonPrefixes, types.CommonPrefix{
+					Prefix: &name,
+				})
+			} else {
+				d, err := p.indexDirEntry(bucket, key)
+				if errors.Is(err, fs.ErrNotExist) || errors.Is(err, syscall.ENOTDIR) {
+					// the object was removed outside of the gateway
+					stale = true
+					k, _ = c.Next()
+					continue
+				}
+				if err != nil {
+					return fmt.Errorf("stat %q: %w", key, err)
+				}
+
+				obj, err := getObj(key, d)
+				if err == backend.ErrSkipObj {
+					k, _ = c.Next()
+					continue
+				}
+				if err != nil {
+					return fmt.Errorf("file to object %q: %w", key, err)
+				}
+				results.Objects = append(results.Objects, obj)
+			}
+
+			count++
+			if count == max {
+				results.NextMarker = name
+			}
+
+			if isCommonPrefix {
+				k = seekPastPrefix(c, name)
+			} else {
+				k, _ = c.Next()
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return backend.WalkResults{}, false, fmt.Errorf("list index: %w", err)
+	}
+
+	if stale {
+		p.listIndex.markDirty(bucket)
+	}
+
+	return results, ready, nil
+}
+
+// walkObjectVersions returns the bucket object versions for the version
+// listings, from the list index if the bucket index is ready, otherwise
+// from the filesystem
+func (p *Posix) walkObjectVersions(ctx context.Context, bucket, prefix, delim, keyMarker, versionIdMarker string, max int, getObj backend.GetVersionsFunc) (backend.WalkVersioningResults, error) {
+	if p.listIndex != nil && max > 0 {
+		results, ok, err := p.walkListIndexVersions(ctx, bucket, prefix, delim, keyMarker, versionIdMarker, max, getObj)
+		if err != nil || ok {
+			return results, err
+		}
+	}
+
+	fileSystem := os.DirFS(p.rootfs.Path(bucket))
+	return backend.WalkVersions(ctx, fileSystem, prefix, delim, keyMarker, versionIdMarker, max,
+		getObj, []string{MetaTmpDir})
+}
+
+// walkListIndexVersions lists the bucket object versions from the list
+// index, it returns false if the bucket index is not ready. The index keys
+// are replayed as the directory and file visits of the filesystem walk,
+// so the listings have the same results and markers as the filesystem
+// listings, while the directories skipped by the walk are seeked past.
+func (p *Posix) walkListIndexVersions(ctx context.Context, bucket, prefix, delim, keyMarker, versionIdMarker string, max int, getObj backend.GetVersionsFunc) (backend.WalkVersioningResults, bool, error) {
+	var results backend.WalkVersioningResults
+	var ready, stale bool
+
+	pastMarker := keyMarker == ""
+	pastVersionIdMarker := versionIdMarker == ""
+	cps := make(map[string]struct{})
+
+	count := func() int {
+		return len(results.ObjectVersions) + len(results.DelMarkers) + len(results.CommonPrefixes)
+	}
+	addCommonPrefix := func(cp string) {
+		if _, ok := cps[cp]; ok {
+			return
+		}
+		cps[cp] = struct{}{}
+		results.CommonPrefixes = append(results.CommonPrefixes, types.CommonPrefix{
+			Prefix: &cp,
+		})
+	}
+	getVersions := func(path, key string) error {
+		d, err := p.indexDirEntry(bucket, key)
+		if errors.Is(err, fs.ErrNotExist) || errors.Is(err, syscall.ENOTDIR) {
+			// the object was removed outside of the gateway
+			stale = true
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("stat %q: %w", key, err)
+		}
+
+		res, err := getObj(path, versionIdMarker, &pastVersionIdMarker, max-count(), d)
+		if err == backend.ErrSkipObj {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("file to object %q: %w", path, err)
+		}
+		results.ObjectVersions = append(results.ObjectVersions, res.ObjectVersions...)
+		results.DelMarkers = append(results.DelMarkers, res.DelMarkers...)
+		if res.Truncated {
+			results.Truncated = true
+			results.NextMarker = path
+			results.NextVersionIdMarker = res.NextVersionIdMarker
+			return fs.SkipAll
+		}
+		return nil
+	}
+	// visit handles the directory and file visits as the filesystem walk
+	visit := func(path string, isDir bool) error {
+		if !pastMarker {
+			if path == keyMarker {
+				pastMarker = true
+			}
+			if path < keyMarker {
+				return nil
+			}
+		}
+
+		if isDir {
+			if prefix != "" &&
+				!strings.HasPrefix(path+"/", prefix) &&
+				!strings.HasPrefix(prefix, path+"/") {
+				return fs.SkipDir
+			}
+			if delim == "/" &&
+				prefix != path+"/" &&
+				strings.HasPrefix(path+"/", prefix) {
+				addCommonPrefix(path + "/")
+				return fs.SkipDir
+			}
+			if prefix != "" && strings.HasPrefix(prefix, path+"/") && path+"/" != prefix {
+				return nil
+			}
+			return getVersions(path, path+"/")
+		}
+
+		if prefix != "" && !strings.HasPrefix(path, prefix) {
+			return nil
+		}
+		if delim != "" {
+			before, _, found := strings.Cut(strings.TrimPrefix(path, prefix), delim)
+			if found {
+				addCommonPrefix(prefix + before + delim)
+				if len(results.ObjectVersions)+len(results.CommonPrefixes) == max {
+					results.NextMarker = path
+					results.Truncated = true
+					return fs.SkipAll
+				}
+				return nil
+			}
+		}
+		return getVersions(path, path)
+	}
+
+	err := p.listIndex.db.View(func(tx *bolt.Tx) error {
+		state, err := getListIndexState(tx, bucket)
+		if err != nil {
+			return err
+		}
+		keys := tx.Bucket(listIndexKeysBucket).Bucket([]byte(bucket))
+		if !state.Ready || keys == nil {
+			return nil
+		}
+		ready = true
+
+		c := keys.Cursor()
+		if !pastMarker && keyMarker < prefix {
+			// the marker is before the seeked keys, and would be
+			// visited by the walk before these
+			if keys.Get([]byte(keyMarker)) != nil {
+				pastMarker = true
+			} else if k, _ := c.Seek([]byte(keyMarker + "/")); k != nil &&
+				strings.HasPrefix(string(k), keyMarker+"/") {
+				pastMarker = true
+			}
+		}
+
+		// open are the visited directories of the current key
+		var open []string
+		k, _ := c.Seek(seekStart(prefix, keyMarker))
+	keys:
+		for k != nil {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+
+			key := string(k)
+			if !strings.HasPrefix(key, prefix) {
+				break
+			}
+
+			for len(open) > 0 && !strings.HasPrefix(key, open[len(open)-1]) {
+				open = open[:len(open)-1]
+			}
+
+			for i := 0; i < len(key); i++ {
+				if key[i] != '/' {
+					continue
+				}
+				dir := key[:i+1]
+				if len(open) > 0 && len(dir) <= len(open[len(open)-1]) {
+					continue
+				}
+				open = append(open, dir)
+
+				err := visit(key[:i], true)
+				if err == fs.SkipDir {
+					open = open[:len(open)-1]
+					k = seekPastPrefix(c, dir)
+					continue keys
+				}
+				if err == fs.SkipAll {
+					return nil
+				}
+				if err != nil {
+					return err
+				}
+			}
+
+			if !strings.HasSuffix(key, "/") {
+				err := visit(key, false)
+				if err == fs.SkipAll {
+					return nil
+				}
+				if err != nil {
+					return err
+				}
+			}
+
+			k, _ = c.Next()
+		}
+
+		return nil
+	})
+	if err != nil {
+		return backend.WalkVersioningResults{}, false, fmt.Errorf("list index: %w", err)
+	}
+
+	if stale {
+		p.listIndex.markDirty(bucket)
+	}
+
+	return results, ready, nil
+}
+
+// startListIndexReconciler starts the background reconciler of the list
+// indexes. The indexes of the buckets not indexed yet are built at start,
+// then all indexes are reconciled at the reconcile interval, and the
+// buckets marked dirty are reconciled as they are marked.
+func (p *Posix) startListIndexReconciler() {
+	if p.listIndex == nil {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	p.listIndex.cancel = cancel
+
+	p.listIndex.wg.Add(1)
+	go func() {
+		defer p.listIndex.wg.Done()
+
+		p.reconcileListIndexes(ctx, false)
+
+		var tick <-chan time.Time
+		if p.listIndex.interval > 0 {
+			ticker := time.NewTicker(p.listIndex.interval)
+			defer ticker.Stop()
+			tick = ticker.C
+		}
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-tick:
+				p.reconcileListIndexes(ctx, true)
+			case <-p.listIndex.wake:
+				for _, bucket := range p.listIndex.takeDirty() {
+					p.reconcileListIndexBucket(ctx, bucket)
+				}
+			}
+		}
+	}()
+}
+
+// reconcileListIndexes reconciles the bucket indexes, or only the indexes
+// not ready if all is not set
+func (p *Posix) reconcileListIndexes(ctx context.Context, all bool) {
+	fis, err := listBucketFileInfos(p.rootfs, p.bucketlinks)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "list index: list buckets: %v\n", err)
+		return
+	}
+
+	for _, fi := range fis {
+		if ctx.Err() != nil {
+			return
+		}
+		if !all {
+			var state listIndexState
+			err := p.listIndex.db.View(func(tx *bolt.Tx) error {
+				state, err = getListIndexState(tx, fi.Name())
+				return err
+			})
+			if err == nil && state.Ready {
+				continue
+			}
+		}
+		p.reconcileListIndexBucket(ctx, fi.Name())
+	}
+}
+
+func (p *Posix) reconcileListIndexBucket(ctx context.Context, bucket string) {
+	err := p.reconcileListIndex(ctx, bucket)
+	if err != nil && ctx.Err() == nil {
+		fmt.Fprintf(os.Stderr, "list index: reconcile %v: %v\n", bucket, err)
+	}
+}
+
+// reconcileListIndex rebuilds the bucket index from the filesystem. The
+// keys found in the bucket are stored with a new generation, then the
+// keys left with an older generation are removed. The objects written
+// while the bucket is reconciled are indexed with the new generation,
+// so these are kept.
+func (p *Posix) reconcileListIndex(ctx context.Context, bucket string) error {
+	release, err := p.acquireActionSlot(ctx)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	_, err = p.rootfs.Stat(bucket)
+	if errors.Is(err, fs.ErrNotExist) {
+		return p.lockedRemoveListIndexBucket(bucket)
+	}
+	if err != nil {
+		return fmt.Errorf("stat bucket: %w", err)
+	}
+
+	var state listIndexState
+	err = p.listIndex.db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.Bucket(listIndexKeysBucket).CreateBucketIfNotExists([]byte(bucket))
+		if err != nil {
+			return err
+		}
+		state, err = getListIndexState(tx, bucket)
+		if err != nil {
+			return err
+		}
+		state.Generation++
+		return putListIndexState(tx, bucket, state)
+	})
+	if err != nil {
+		return err
+	}
+
+	gen := encodeGeneration(state.Generation)
+	batch := make([]string, 0, listIndexBatchSize)
+	flush := func() error {
+		err := p.flushListIndexKeys(bucket, gen, batch)
+		batch = batch[:0]
+		return err
+	}
+
+	fileSystem := os.DirFS(p.rootfs.Path(bucket))
+	err = fs.WalkDir(fileSystem, ".", func(path string, d fs.DirEntry, err error) error {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+		if errors.Is(err, fs.ErrNotExist) {
+			// removed while the bucket is reconciled
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if path == "." {
+			return nil
+		}
+		if d.IsDir() && d.Name() == MetaTmpDir {
+			return fs.SkipDir
+		}
+
+		key := path
+		if d.IsDir() {
+			key += "/"
+			_, err := p.meta.RetrieveAttribute(nil, bucket, key, etagkey)
+			if err != nil {
+				// not a directory object
+				return nil
+			}
+		}
+
+		batch = append(batch, key)
+		if len(batch) < listIndexBatchSize {
+			return nil
+		}
+		return flush()
+	})
+	if err == nil {
+		err = flush()
+	}
+	if err != nil {
+		return fmt.Errorf("walk bucket: %w", err)
+	}
+
+	err = p.sweepListIndex(ctx, bucket, gen)
+	if err != nil {
+		return fmt.Errorf("sweep index: %w", err)
+	}
+
+	return p.listIndex.db.Update(func(tx *bolt.Tx) error {
+		cur, err := getListIndexState(tx, bucket)
+		if err != nil {
+			return err
+		}
+		cur.Ready = true
+		cur.ReconciledAt = time.Now().UTC()
+		return putListIndexState(tx, bucket, cur)
+	})
+}
+
+// flushListIndexKeys stores the keys found by the reconcile walk with the
+// reconcile generation. The walk has no lock on the bucket, so the objects
+// may have been removed since they were walked, and the removals already
+// synced to the index. The keys are checked again with the bucket index
+// locked, so the removed objects are not indexed again.
+func (p *Posix) flushListIndexKeys(bucket string, gen []byte, batch []string) error {
+	if len(batch) == 0 {
+		return nil
+	}
+
+	unlock := p.listIndex.lockBucket(bucket)
+	defer unlock()
+
+	listed := make([]string, 0, len(batch))
+	for _, key := range batch {
+		if p.isListedObject(bucket, key) {
+			listed = append(listed, key)
+		}
+	}
+
+	return p.listIndex.db.Update(func(tx *bolt.Tx) error {
+		keys := tx.Bucket(listIndexKeysBucket).Bucket([]byte(bucket))
+		if keys == nil {
+			// the bucket was removed while reconciled
+			return nil
+		}
+		for _, key := range listed {
+			err := keys.Put([]byte(key), gen)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// sweepListIndex removes the keys with older generations than gen
+func (p *Posix) sweepListIndex(ctx context.Context, bucket string, gen []byte) error {
+	var start []byte
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		var stale [][]byte
+		var next []byte
+		err := p.listIndex.db.View(func(tx *bolt.Tx) error {
+			keys := tx.Bucket(listIndexKeysBucket).Bucket([]byte(bucket))
+			if keys == nil {
+				return nil
+			}
+			c := keys.Cursor()
+			k, v := c.First()
+			if start != nil {
+				k, v = c.Seek(start)
+			}
+			for n := 0; k != nil; k, v = c.Next() {
+				if n == listIndexBatchSize {
+					next = append([]byte(nil), k...)
+					break
+				}
+				if string(v) != string(gen) {
+					stale = append(stale, append([]byte(nil), k...))
+				}
+				n++
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		if len(stale) > 0 {
+			err = p.listIndex.db.Update(func(tx *bolt.Tx) error {
+				keys := tx.Bucket(listIndexKeysBucket).Bucket([]byte(bucket))
+				if keys == nil {
+					return nil
+				}
+				for _, k := range stale {
+					// the keys indexed since have the new generation
+					if string(keys.Get(k)) == string(gen) {
+						continue
+					}
+					err := keys.Delete(k)
+					if err != nil {
+						return err
+					}
+				}
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		if next == nil {
+			return nil
+		}
+		start = next
+	}
+}
@@ -0,0 +1,290 @@
+// Copyright 2026 Versity Software
+// This file is licensed under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package posix
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/versity/versitygw/backend"
+	bolt "go.etcd.io/bbolt"
+)
+
+func newTestListIndexPosix(t *testing.T) *Posix {
+	t.Helper()
+	return newTestPosix(t, PosixOpts{
+		ListIndexPath: filepath.Join(t.TempDir(), "listindex.db"),
+	})
+}
+
+func indexedKeys(t *testing.T, p *Posix, bucket string) []string {
+	t.Helper()
+
+	var keys []string
+	err := p.listIndex.db.View(func(tx *bolt.Tx) error {
+		b := tx.Bucket(listIndexKeysBucket).Bucket([]byte(bucket))
+		if b == nil {
+			return nil
+		}
+		return b.ForEach(func(k, _ []byte) error {
+			keys = append(keys, string(k))
+			return nil
+		})
+	})
+	if err != nil {
+		t.Fatalf("read index keys: %v", err)
+	}
+	return keys
+}
+
+// listPages lists all of the pages with the list function and returns
+// the listed objects and common prefixes, with the markers of the pages.
+// The filesystem walk may truncate a full page with nothing left to
+// list, so the marker of a page followed by an empty page is dropped.
+func listPages(t *testing.T, max int32, list func(marker string) (backend.WalkResults, error)) []string {
+	t.Helper()
+
+	var listed []string
+	marker := ""
+	for range 1000 {
+		res, err := list(marker)
+		if err != nil {
+			t.Fatalf("list: %v", err)
+		}
+		if marker != "" && len(res.Objects) == 0 && len(res.CommonPrefixes) == 0 {
+			listed = listed[:len(listed)-1]
+		}
+		for _, obj := range res.Objects {
+			listed = append(listed, *obj.Key)
+		}
+		for _, cp := range res.CommonPrefixes {
+			listed = append(listed, "cp:"+*cp.Prefix)
+		}
+		if !res.Truncated {
+			return listed
+		}
+		listed = append(listed, "marker:"+res.NextMarker)
+		marker = res.NextMarker
+	}
+	t.Fatalf("listing with max %v did not end", max)
+	return nil
+}
+
+// versionKeys returns the listed object versions and common prefixes
+func versionKeys(res backend.WalkVersioningResults) []string {
+	var listed []string
+	for _, ver := range res.ObjectVersions {
+		listed = append(listed, *ver.Key)
+	}
+	for _, cp := range res.CommonPrefixes {
+		listed = append(listed, "cp:"+*cp.Prefix)
+	}
+	if res.Truncated {
+		listed = append(listed, "marker:"+res.NextMarker)
+	}
+	return listed
+}
+
+func TestListIndex_MatchesWalk(t *testing.T) {
+	p := newTestListIndexPosix(t)
+	ctx := context.Background()
+	bucket := "bucket"
+	createTestBucket(t, p, bucket)
+
+	for _, key := range []string{
+		"a/b/c", "a/b/d", "a/c", "ab", "b/", "b/x", "c/d/e/f",
+		"dir/", "dir/sub/", "dir/sub/obj", "z",
+	} {
+		data := "data"
+		if strings.HasSuffix(key, "/") {
+			data = ""
+		}
+		putTestObject(t, p, bucket, key, data)
+	}
+
+	// objects added outside of the gateway are indexed by the reconcile
+	bucketDir := p.rootfs.Path(bucket)
+	err := os.MkdirAll(filepath.Join(bucketDir, "ext", "nested"), 0o755)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"ext/one", "ext/nested/two", "zz"} {
+		err := os.WriteFile(filepath.Join(bucketDir, name), []byte("data"), 0o644)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	err = p.reconcileListIndex(ctx, bucket)
+	if err != nil {
+		t.Fatalf("reconcile: %v", err)
+	}
+
+	getObj := p.FileToObj(bucket, false)
+	getVersions := p.fileToObjVersions(bucket)
+
+	for _, prefix := range []string{"", "a", "a/", "a/b", "dir/", "ext/", "none"} {
+		for _, delim := range []string{"", "/"} {
+			for _, max := range []int32{1, 2, 3, 1000} {
+				name := fmt.Sprintf("prefix=%q,delim=%q,max=%v", prefix, delim, max)
+
+				fromIndex := listPages(t, max, func(marker string) (backend.WalkResults, error) {
+					res, ok, err := p.walkListIndex(ctx, bucket, prefix, delim, marker, max, getObj)
+					if err == nil && !ok {
+						t.Fatalf("%v: index not ready", name)
+					}
+					return res, err
+				})
+				fromWalk := listPages(t, max, func(marker string) (backend.WalkResults, error) {
+					return backend.Walk(ctx, os.DirFS(bucketDir), prefix, delim, marker, max,
+						getObj, []string{MetaTmpDir})
+				})
+				if !reflect.DeepEqual(fromIndex, fromWalk) {
+					t.Errorf("%v: index listing %q, walk listing %q", name, fromIndex, fromWalk)
+				}
+
+				res, ok, err := p.walkListIndexVersions(ctx, bucket, prefix, delim, "", "", int(max), getVersions)
+				if err != nil || !ok {
+					t.Fatalf("%v: index versions: ready %v, %v", name, ok, err)
+				}
+				vIndex := versionKeys(res)
+				res, err = backend.WalkVersions(ctx, os.DirFS(bucketDir), prefix, delim, "", "", int(max),
+					getVersions, []string{MetaTmpDir})
+				if err != nil {
+					t.Fatalf("%v: walk versions: %v", name, err)
+				}
+				vWalk := versionKeys(res)
+				if !reflect.DeepEqual(vIndex, vWalk) {
+					t.Errorf("%v: index versions %q, walk versions %q", name, vIndex, vWalk)
+				}
+			}
+		}
+	}
+}
+
+func TestListIndex_ReconcileRemovesStale(t *testing.T) {
+	p := newTestListIndexPosix(t)
+	ctx := context.Background()
+	bucket := "bucket"
+	createTestBucket(t, p, bucket)
+
+	putTestObject(t, p, bucket, "keep", "data")
+	putTestObject(t, p, bucket, "gone/obj", "data")
+
+	// removed outside of the gateway
+	err := os.RemoveAll(filepath.Join(p.rootfs.Path(bucket), "gone"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = p.reconcileListIndex(ctx, bucket)
+	if err != nil {
+		t.Fatalf("reconcile: %v", err)
+	}
+
+	got := indexedKeys(t, p, bucket)
+	if want := []string{"keep"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("expected index keys %q, got %q", want, got)
+	}
+}
+
+func TestListIndex_FlushAfterDelete(t *testing.T) {
+	p := newTestListIndexPosix(t)
+	ctx := context.Background()
+	bucket := "bucket"
+	createTestBucket(t, p, bucket)
+
+	putTestObject(t, p, bucket, "deleted", "data")
+	putTestObject(t, p, bucket, "kept", "data")
+
+	// the reconcile walk found both objects, then one is deleted
+	// before the walked keys are flushed to the index
+	gen := encodeGeneration(1)
+	key := "deleted"
+	_, err := p.DeleteObject(ctx, &s3.DeleteObjectInput{
+		Bucket: &bucket,
+		Key:    &key,
+	})
+	if err != nil {
+		t.Fatalf("delete object: %v", err)
+	}
+
+	err = p.flushListIndexKeys(bucket, gen, []string{"deleted", "kept"})
+	if err != nil {
+		t.Fatalf("flush: %v", err)
+	}
+
+	got := indexedKeys(t, p, bucket)
+	if want := []string{"kept"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("expected index keys %q, got %q", want, got)
+	}
+}
+
+func TestListIndex_ConcurrentDeletes(t *testing.T) {
+	p := newTestListIndexPosix(t)
+	ctx := context.Background()
+	bucket := "bucket"
+	createTestBucket(t, p, bucket)
+
+	var keys []string
+	for i := range 200 {
+		key := fmt.Sprintf("dir%v/obj%03v", i%10, i)
+		keys = append(keys, key)
+		putTestObject(t, p, bucket, key, "data")
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		for range 5 {
+			err := p.reconcileListIndex(ctx, bucket)
+			if err != nil {
+				done <- err
+				return
+			}
+		}
+		done <- nil
+	}()
+
+	for _, key := range keys[:100] {
+		_, err := p.DeleteObject(ctx, &s3.DeleteObjectInput{
+			Bucket: &bucket,
+			Key:    &key,
+		})
+		if err != nil {
+			t.Fatalf("delete object %v: %v", key, err)
+		}
+	}
+	if err := <-done; err != nil {
+		t.Fatalf("reconcile: %v", err)
+	}
+
+	for _, key := range indexedKeys(t, p, bucket) {
+		if !strings.HasPrefix(key, "dir") {
+			t.Errorf("unexpected index key %q", key)
+			continue
+		}
+		if _, err := os.Lstat(filepath.Join(p.rootfs.Path(bucket), key)); err != nil {
+			t.Errorf("deleted object %q is indexed", key)
+		}
+	}
+	if got := len(indexedKeys(t, p, bucket)); got != 100 {
+		t.Errorf("expected 100 index keys, got %v", got)
+	}
+}
@@ -53,7 +53,9 @@ type Posix struct {
 	// bucket/object metadata storage facility
 	meta meta.MetadataStorer
 
-	rootfd  *os.File
+	// rootfs resolves the bucket and object paths relative to
+	// the gateway root directory
+	rootfs  *RootFS
 	rootdir string
 
 	// chownuid/gid enable chowning of files to the account uid/gid
@@ -98,6 +100,26 @@ type Posix struct {
 	// execute blocking syscalls (stat, readdir, xattr, open, etc.), this limiter
 	// constrains parallelism to prevent excessive thread creation under load.
 	actionLimiter *semaphore.Weighted
+
+	// scrub is the background data scrubber verifying the stored
+	// object checksums and etags
+	scrub scrubber
+
+	// snapshots are the bucket snapshots being created
+	// in the background
+	snapshots snapshotJobs
+
+	// listIndex is the optional persistent sorted key index used
+	// for the bucket listings
+	listIndex *listIndex
+
+	// watch is the watcher of the objects changed outside of
+	// the gateway
+	watch watcher
+
+	// newDataWriter is the optional writer of the uploaded object
+	// data, used by the filesystems with parallel data paths
+	newDataWriter func(f *os.File, bucket string, size int64) io.WriteCloser
 }
 
 var _ backend.Backend = &Posix{}
@@ -125,6 +147,7 @@ const (
 	objectRetentionKey  = "object-retention"
 	objectLegalHoldKey  = "object-legal-hold"
 	corskey             = "cors"
+	accessBlockKey      = "public-access-block"
 	versioningKey       = "versioning"
 	deleteMarkerKey     = "delete-marker"
 	versionIdKey        = "version-id"
@@ -153,6 +176,8 @@ type PosixOpts struct {
 	NewDirPerm fs.FileMode
 	// SideCarDir sets the directory to store sidecar metadata
 	SideCarDir string
+	// MetaDBPath sets the path of the key-value metadata database
+	MetaDBPath string
 	// ForceNoTmpFile disables the use of O_TMPFILE even if the filesystem
 	// supports it
 	ForceNoTmpFile bool
@@ -171,27 +196,55 @@ type PosixOpts struct {
 	// queue depth grows under sustained load, request latency increases and
 	// upstream timeouts may occur.
 	Concurrency int
+	// ScrubInterval enables the background data scrubber, verifying the
+	// object data of all buckets against the stored checksums and etags
+	// at the interval
+	ScrubInterval time.Duration
+	// ScrubRate limits the object data read by the scrubber in bytes per
+	// second, 0 is unlimited
+	ScrubRate int64
+	// ScrubQuarantine moves the objects failing verification out of the
+	// bucket namespace to the bucket temporary directory
+	ScrubQuarantine bool
+	// ScrubReportBucket sets the bucket the scrub reports are stored in
+	ScrubReportBucket string
+	// ListIndexPath enables the persistent listing index of the bucket
+	// objects, stored in the key-value database at the path
+	ListIndexPath string
+	// ListIndexReconcileInterval sets the interval the listing indexes
+	// are reconciled with the filesystem at, to pick up the objects
+	// changed outside of the gateway. 0 only reconciles at startup and
+	// when stale index entries are found.
+	ListIndexReconcileInterval time.Duration
+	// NewDataWriter returns the writer of the uncompressed object and
+	// part data of the size uploaded to the bucket, written to the
+	// temp file f. The data is written to f as it is received when nil,
+	// or when it returns nil. The writer is closed once the data is
+	// written, and returns the first write error.
+	NewDataWriter func(f *os.File, bucket string, size int64) io.WriteCloser
+	// WatchInterval enables the watcher of the objects created, modified
+	// or removed outside of the gateway, scanning the buckets for the
+	// changes at the interval. The changes are also detected as these
+	// are made with the filesystem notifications where available. The
+	// watcher stores the object states in the list index database, so
+	// ListIndexPath must be set.
+	WatchInterval time.Duration
 }
 
-func New(rootdir string, meta meta.MetadataStorer, opts PosixOpts) (*Posix, error) {
+func New(rootdir string, ms meta.MetadataStorer, opts PosixOpts) (*Posix, error) {
 	if opts.SideCarDir != "" && strings.HasPrefix(opts.SideCarDir, rootdir) {
 		return nil, fmt.Errorf("sidecar directory cannot be inside the gateway root directory")
 	}
 
-	err := os.Chdir(rootdir)
+	// The paths are resolved relative to the root directory instead of
+	// changing the process working directory, so multiple posix backends
+	// with different root directories can run in the same process.
+	rootfs, err := NewRootFS(rootdir, opts.BucketLinks)
 	if err != nil {
-		return nil, fmt.Errorf("chdir %v: %w", rootdir, err)
-	}
-
-	f, err := os.Open(rootdir)
-	if err != nil {
-		return nil, fmt.Errorf("open %v: %w", rootdir, err)
+		return nil, err
 	}
 
-	rootdirAbs, err := filepath.Abs(rootdir)
-	if err != nil {
-		return nil, fmt.Errorf("get absolute path of %v: %w", rootdir, err)
-	}
+	rootdirAbs := rootfs.Dir()
 
 	var versioningdirAbs string
 	// Ensure the versioning directory isn't within the root directory
@@ -219,10 +272,63 @@ func New(rootdir string, meta meta.MetadataStorer, opts PosixOpts) (*Posix, erro
 		fmt.Println("Using sidecar directory for metadata:", sidecardirAbs)
 	}
 
-	return &Posix{
-		meta:                 meta,
-		rootfd:               f,
-		rootdir:              rootdir,
+	// Ensure the metadata database isn't within the root directory
+	if opts.MetaDBPath != "" {
+		metadbAbs, err := filepath.Abs(opts.MetaDBPath)
+		if err != nil {
+			return nil, fmt.Errorf("get absolute path of %v: %w", opts.MetaDBPath, err)
+		}
+		if isDirBelowRoot(rootdirAbs, filepath.Dir(metadbAbs)) {
+			return nil, fmt.Errorf("the root directory %v contains the metadata database %v",
+				rootdirAbs, opts.MetaDBPath)
+		}
+		fmt.Println("Using key-value database for metadata:", metadbAbs)
+	}
+
+	if opts.ScrubInterval < 0 {
+		return nil, fmt.Errorf("scrub interval must not be negative")
+	}
+	if opts.ScrubRate < 0 {
+		return nil, fmt.Errorf("scrub rate must not be negative")
+	}
+	if opts.ListIndexReconcileInterval < 0 {
+		return nil, fmt.Errorf("list index reconcile interval must not be negative")
+	}
+	if opts.WatchInterval < 0 {
+		return nil, fmt.Errorf("watch interval must not be negative")
+	}
+	if opts.WatchInterval > 0 && opts.ListIndexPath == "" {
+		return nil, fmt.Errorf("the watcher requires the list index database")
+	}
+
+	var lindex *listIndex
+	// Ensure the listing index database isn't within the root directory
+	if opts.ListIndexPath != "" {
+		lindexAbs, err := filepath.Abs(opts.ListIndexPath)
+		if err != nil {
+			return nil, fmt.Errorf("get absolute path of %v: %w", opts.ListIndexPath, err)
+		}
+		if isDirBelowRoot(rootdirAbs, filepath.Dir(lindexAbs)) {
+			return nil, fmt.Errorf("the root directory %v contains the list index database %v",
+				rootdirAbs, opts.ListIndexPath)
+		}
+		if opts.MetaDBPath != "" {
+			metadbAbs, err := filepath.Abs(opts.MetaDBPath)
+			if err == nil && metadbAbs == lindexAbs {
+				return nil, fmt.Errorf("the list index database must not be the metadata database")
+			}
+		}
+		lindex, err = newListIndex(lindexAbs, opts.ListIndexReconcileInterval)
+		if err != nil {
+			return nil, err
+		}
+		fmt.Println("Using listing index database:", lindexAbs)
+	}
+
+	p := &Posix{
+		meta:                 meta.WithRoot(ms, rootfs),
+		rootfs:               rootfs,
+		rootdir:              rootdirAbs,
 		euid:                 os.Geteuid(),
 		egid:                 os.Getegid(),
 		chownuid:             opts.ChownUID,
@@ -234,7 +340,22 @@ func New(rootdir string, meta meta.MetadataStorer, opts PosixOpts) (*Posix, erro
 		forceNoCopyFileRange: opts.ForceNoCopyFileRange,
 		validateBucketName:   opts.ValidateBucketNames,
 		actionLimiter:        semaphore.NewWeighted(int64(concurrencyOrDefault(opts.Concurrency))),
-	}, nil
+		scrub: scrubber{
+			interval:     opts.ScrubInterval,
+			rate:         opts.ScrubRate,
+			quarantine:   opts.ScrubQuarantine,
+			reportBucket: opts.ScrubReportBucket,
+		},
+		listIndex:     lindex,
+		watch:         watcher{interval: opts.WatchInterval},
+		newDataWriter: opts.NewDataWriter,
+	}
+
+	p.startScrubber()
+	p.startListIndexReconciler()
+	p.startWatcher()
+
+	return p, nil
 }
 
 // concurrencyOrDefault returns n if it is positive, otherwise defaultConcurrency.
@@ -245,6 +366,29 @@ func concurrencyOrDefault(n int) int {
 	return defaultConcurrency
 }
 
+// dataWriter returns the configured writer of the uploaded data written to
+// the temp file, or nil if the data is written to the temp file directly.
+// The compressed data is written by the compression writer instead.
+func (p *Posix) dataWriter(f *tmpfile, bucket string, size int64, uncompressed bool) io.WriteCloser {
+	if p.newDataWriter == nil || !uncompressed || size <= 0 {
+		return nil
+	}
+	return p.newDataWriter(f.File(), bucket, size)
+}
+
+// closeDataWriter closes the data writer once the data is copied, waiting
+// for the pending writes, and returns the copy error or the write error
+func closeDataWriter(dw io.WriteCloser, err error) error {
+	if dw == nil {
+		return err
+	}
+	cerr := dw.Close()
+	if err != nil {
+		return err
+	}
+	return cerr
+}
+
 func validateSubDir(root, dir string) (string, error) {
 	absDir, err := filepath.Abs(dir)
 	if err != nil {
@@ -288,7 +432,16 @@ func isDirBelowRoot(root, dir string) bool {
 }
 
 func (p *Posix) Shutdown() {
-	p.rootfd.Close()
+	p.stopSnapshots()
+	p.stopScrubber()
+	p.stopWatcher()
+	if p.listIndex != nil {
+		p.listIndex.close()
+	}
+	p.rootfs.Close()
+	if c, ok := p.meta.(io.Closer); ok {
+		c.Close()
+	}
 }
 
 func (p *Posix) String() string {
@@ -300,6 +453,7 @@ type ctxKey int
 
 const (
 	ctxKeyNoAcquireSlot ctxKey = iota
+	ctxKeyCopySource
 )
 
 // withCtxNoSlot is a context wrapper with ctxKeyNoAcquireSlot key
@@ -335,7 +489,7 @@ func (p *Posix) versioningEnabled() bool {
 }
 
 func (p *Posix) doesBucketAndObjectExist(bucket, object string) error {
-	_, err := os.Stat(bucket)
+	_, err := p.rootfs.Stat(bucket)
 	if errors.Is(err, fs.ErrNotExist) {
 		return s3err.GetAPIError(s3err.ErrNoSuchBucket)
 	}
@@ -343,7 +497,7 @@ func (p *Posix) doesBucketAndObjectExist(bucket, object string) error {
 		return fmt.Errorf("stat bucket: %w", err)
 	}
 
-	_, err = os.Stat(filepath.Join(bucket, object))
+	_, err = p.rootfs.Stat(filepath.Join(bucket, object))
 	if errors.Is(err, fs.ErrNotExist) || errors.Is(err, syscall.ENOTDIR) {
 		return s3err.GetAPIError(s3err.ErrNoSuchKey)
 	}
@@ -361,7 +515,7 @@ func (p *Posix) ListBuckets(ctx context.Context, input s3response.ListBucketsInp
 	}
 	defer release()
 
-	fis, err := listBucketFileInfos(p.bucketlinks)
+	fis, err := listBucketFileInfos(p.rootfs, p.bucketlinks)
 	if err != nil {
 		return s3response.ListAllMyBucketsResult{}, fmt.Errorf("listBucketFileInfos : %w", err)
 	}
@@ -447,7 +601,7 @@ func (p *Posix) HeadBucket(ctx context.Context, input *s3.HeadBucketInput) (*s3.
 	if !p.isBucketValid(*input.Bucket) {
 		return nil, s3err.GetAPIError(s3err.ErrInvalidBucketName)
 	}
-	_, err = os.Lstat(*input.Bucket)
+	_, err = p.rootfs.Lstat(*input.Bucket)
 	if errors.Is(err, fs.ErrNotExist) {
 		return nil, s3err.GetAPIError(s3err.ErrNoSuchBucket)
 	}
@@ -483,7 +637,7 @@ func (p *Posix) CreateBucket(ctx context.Context, input *s3.CreateBucketInput, a
 		return err
 	}
 
-	err = os.Mkdir(bucket, p.newDirPerm)
+	err = p.rootfs.Mkdir(bucket, p.newDirPerm)
 	if err != nil && os.IsExist(err) {
 		aclJSON, err := p.meta.RetrieveAttribute(nil, bucket, "", aclkey)
 		if err != nil {
@@ -508,12 +662,15 @@ func (p *Posix) CreateBucket(ctx context.Context, input *s3.CreateBucketInput, a
 	}
 
 	if doChown {
-		err := os.Chown(bucket, uid, gid)
+		err := p.rootfs.Chown(bucket, uid, gid)
 		if err != nil {
 			return fmt.Errorf("chown bucket: %w", err)
 		}
 	}
 
+	p.initListIndexBucket(bucket)
+	p.watchBucketCreated(bucket)
+
 	err = p.meta.StoreAttribute(nil, bucket, "", aclkey, acl)
 	if err != nil {
 		return fmt.Errorf("set acl: %w", err)
@@ -531,6 +688,10 @@ func (p *Posix) CreateBucket(ctx context.Context, input *s3.CreateBucketInput, a
 		if err != nil {
 			return fmt.Errorf("set tags: %w", err)
 		}
+		err = p.markBucketCompressed(bucket, tagging)
+		if err != nil {
+			return err
+		}
 	}
 
 	if input.ObjectLockEnabledForBucket != nil && *input.ObjectLockEnabledForBucket {
@@ -565,7 +726,7 @@ func (p *Posix) CreateBucket(ctx context.Context, input *s3.CreateBucketInput, a
 
 func (p *Posix) isBucketEmpty(bucket string) error {
 	if p.versioningEnabled() {
-		ents, err := os.ReadDir(filepath.Join(p.versioningDir, bucket))
+		ents, err := p.rootfs.ReadDir(filepath.Join(p.versioningDir, bucket))
 		if err != nil && !errors.Is(err, fs.ErrNotExist) {
 			return fmt.Errorf("readdir bucket: %w", err)
 		}
@@ -578,7 +739,7 @@ func (p *Posix) isBucketEmpty(bucket string) error {
 		}
 	}
 
-	ents, err := os.ReadDir(bucket)
+	ents, err := p.rootfs.ReadDir(bucket)
 	if err != nil && !errors.Is(err, fs.ErrNotExist) {
 		return fmt.Errorf("readdir bucket: %w", err)
 	}
@@ -604,25 +765,39 @@ func (p *Posix) DeleteBucket(ctx context.Context, bucket string) error {
 	if !p.isBucketValid(bucket) {
 		return s3err.GetAPIError(s3err.ErrInvalidBucketName)
 	}
-	// Check if the bucket is empty
-	err = p.isBucketEmpty(bucket)
+	// The snapshot buckets are read-only, so they
+	// are removed along with the snapshot objects
+	isSnapshot, err := p.isBucketSnapshot(bucket)
 	if err != nil {
 		return err
 	}
+	if isSnapshot && p.isSnapshotRunning(bucket) {
+		// the snapshot objects are still being added
+		return s3err.GetAPIError(s3err.ErrBucketNotEmpty)
+	}
+	if !isSnapshot {
+		// Check if the bucket is empty
+		err = p.isBucketEmpty(bucket)
+		if err != nil {
+			return err
+		}
+	}
 
 	// Remove the bucket
-	err = os.RemoveAll(bucket)
+	err = p.rootfs.RemoveAll(bucket)
 	if err != nil {
 		return fmt.Errorf("remove bucket: %w", err)
 	}
 	// Remove the bucket from versioning directory
 	if p.versioningEnabled() {
-		err = os.RemoveAll(filepath.Join(p.versioningDir, bucket))
+		err = p.rootfs.RemoveAll(filepath.Join(p.versioningDir, bucket))
 		if err != nil && !errors.Is(err, fs.ErrNotExist) {
 			return fmt.Errorf("remove bucket version: %w", err)
 		}
 	}
 
+	p.removeListIndexBucket(bucket)
+
 	return nil
 }
 
@@ -636,7 +811,7 @@ func (p *Posix) PutBucketOwnershipControls(ctx context.Context, bucket string, o
 	if !p.isBucketValid(bucket) {
 		return s3err.GetAPIError(s3err.ErrInvalidBucketName)
 	}
-	_, err = os.Stat(bucket)
+	_, err = p.rootfs.Stat(bucket)
 	if errors.Is(err, fs.ErrNotExist) {
 		return s3err.GetAPIError(s3err.ErrNoSuchBucket)
 	}
@@ -662,7 +837,7 @@ func (p *Posix) GetBucketOwnershipControls(ctx context.Context, bucket string) (
 	if !p.isBucketValid(bucket) {
 		return ownship, s3err.GetAPIError(s3err.ErrInvalidBucketName)
 	}
-	_, err = os.Stat(bucket)
+	_, err = p.rootfs.Stat(bucket)
 	if errors.Is(err, fs.ErrNotExist) {
 		return ownship, s3err.GetAPIError(s3err.ErrNoSuchBucket)
 	}
@@ -690,7 +865,7 @@ func (p *Posix) DeleteBucketOwnershipControls(ctx context.Context, bucket string
 	if !p.isBucketValid(bucket) {
 		return s3err.GetAPIError(s3err.ErrInvalidBucketName)
 	}
-	_, err = os.Stat(bucket)
+	_, err = p.rootfs.Stat(bucket)
 	if errors.Is(err, fs.ErrNotExist) {
 		return s3err.GetAPIError(s3err.ErrNoSuchBucket)
 	}
@@ -723,7 +898,7 @@ func (p *Posix) PutBucketVersioning(ctx context.Context, bucket string, status t
 	if !p.versioningEnabled() {
 		return s3err.GetAPIError(s3err.ErrVersioningNotConfigured)
 	}
-	_, err = os.Stat(bucket)
+	_, err = p.rootfs.Stat(bucket)
 	if errors.Is(err, fs.ErrNotExist) {
 		return s3err.GetAPIError(s3err.ErrNoSuchBucket)
 	}
@@ -779,7 +954,7 @@ func (p *Posix) GetBucketVersioning(ctx context.Context, bucket string) (s3respo
 		return s3response.GetBucketVersioningOutput{}, s3err.GetAPIError(s3err.ErrInvalidBucketName)
 	}
 
-	_, err = os.Stat(bucket)
+	_, err = p.rootfs.Stat(bucket)
 	if errors.Is(err, fs.ErrNotExist) {
 		return s3response.GetBucketVersioningOutput{}, s3err.GetAPIError(s3err.ErrNoSuchBucket)
 	}
@@ -841,6 +1016,33 @@ func (p *Posix) genObjVersionPath(bucket, key string) string {
 	return filepath.Join(p.versioningDir, bucket, genObjVersionKey(key))
 }
 
+// ObjectVersionPath returns the path of the file storing the object
+// version data. The current version is stored at the object path, and
+// the older versions in the versioning directory.
+func (p *Posix) ObjectVersionPath(bucket, object, versionId string) (string, error) {
+	objPath := filepath.Join(p.rootfs.Dir(), bucket, object)
+	if versionId == "" {
+		return objPath, nil
+	}
+	if !p.versioningEnabled() {
+		return "", s3err.GetAPIError(s3err.ErrInvalidVersionId)
+	}
+
+	vId, err := p.meta.RetrieveAttribute(nil, bucket, object, versionIdKey)
+	if errors.Is(err, meta.ErrNoSuchKey) {
+		vId = []byte(nullVersionId)
+		err = nil
+	}
+	if err != nil && !errors.Is(err, fs.ErrNotExist) && !errors.Is(err, syscall.ENOTDIR) {
+		return "", fmt.Errorf("get obj versionId: %w", err)
+	}
+	if err == nil && string(vId) == versionId {
+		return objPath, nil
+	}
+
+	return filepath.Join(p.genObjVersionPath(bucket, object), versionId), nil
+}
+
 // Generates the versioning path for the given object key
 func genObjVersionKey(key string) string {
 	sum := fmt.Sprintf("%x", sha256.Sum256([]byte(key)))
@@ -852,7 +1054,7 @@ func genObjVersionKey(key string) string {
 func (p *Posix) deleteNullVersionIdObject(bucket, key string) error {
 	versionPath := filepath.Join(p.genObjVersionPath(bucket, key), nullVersionId)
 
-	err := os.Remove(versionPath)
+	err := p.rootfs.Remove(versionPath)
 	if errors.Is(err, fs.ErrNotExist) {
 		return nil
 	}
@@ -871,7 +1073,7 @@ func isRemovableAttr(attr string) bool {
 
 // Creates a new copy(version) of an object in the versioning directory
 func (p *Posix) createObjVersion(bucket, key string, size int64, acc auth.Account, removeAttributes bool) (versionPath string, err error) {
-	sf, err := os.Open(filepath.Join(bucket, key))
+	sf, err := p.rootfs.Open(filepath.Join(bucket, key))
 	if err != nil {
 		return "", err
 	}
@@ -903,9 +1105,11 @@ func (p *Posix) createObjVersion(bucket, key string, size int64, acc auth.Accoun
 	}
 	defer f.cleanup()
 
-	_, err = io.Copy(f.File(), sf)
-	if err != nil {
-		return versionPath, err
+	if !p.cloneFileData(f.File(), sf, 0, size) {
+		_, err = io.Copy(f.File(), sf)
+		if err != nil {
+			return versionPath, err
+		}
 	}
 
 	versionPath = filepath.Join(versionBucketPath, versioningKey)
@@ -916,19 +1120,26 @@ func (p *Posix) createObjVersion(bucket, key string, size int64, acc auth.Accoun
 	}
 
 	// Copy the object attributes(metadata)
+	versionAttrs := make(map[string][]byte, len(attrs))
 	for _, attr := range attrs {
 		data, err := p.meta.RetrieveAttribute(sf, bucket, key, attr)
 		if err != nil {
 			return versionPath, fmt.Errorf("list %v attribute: %w", attr, err)
 		}
+		versionAttrs[attr] = data
+	}
 
-		err = p.meta.StoreAttribute(f.File(), versionPath, "", attr, data)
-		if err != nil {
-			return versionPath, fmt.Errorf("store %v attribute: %w", attr, err)
-		}
+	err = meta.StoreAttributes(p.meta, f.File(), versionPath, "", versionAttrs)
+	if err != nil {
+		return versionPath, err
+	}
 
-		// remove object lock attributes in delete marker
-		if removeAttributes && isRemovableAttr(attr) {
+	// remove object lock attributes in delete marker
+	if removeAttributes {
+		for _, attr := range attrs {
+			if !isRemovableAttr(attr) {
+				continue
+			}
 			err := p.meta.DeleteAttribute(bucket, key, attr)
 			if err != nil {
 				return versionPath, fmt.Errorf("remove %s attribute: %w", attr, err)
@@ -974,7 +1185,7 @@ func (p *Posix) ListObjectVersions(ctx context.Context, input *s3.ListObjectVers
 		max = int(*input.MaxKeys)
 	}
 
-	_, err = os.Stat(bucket)
+	_, err = p.rootfs.Stat(bucket)
 	if errors.Is(err, fs.ErrNotExist) {
 		return s3response.ListVersionsResult{}, s3err.GetAPIError(s3err.ErrNoSuchBucket)
 	}
@@ -982,9 +1193,8 @@ func (p *Posix) ListObjectVersions(ctx context.Context, input *s3.ListObjectVers
 		return s3response.ListVersionsResult{}, fmt.Errorf("stat bucket: %w", err)
 	}
 
-	fileSystem := os.DirFS(bucket)
-	results, err := backend.WalkVersions(ctx, fileSystem, prefix, delim, keyMarker, versionIdMarker, max,
-		p.fileToObjVersions(bucket), []string{MetaTmpDir})
+	results, err := p.walkObjectVersions(ctx, bucket, prefix, delim, keyMarker, versionIdMarker, max,
+		p.fileToObjVersions(bucket))
 	if err != nil {
 		return s3response.ListVersionsResult{}, fmt.Errorf("walk %v: %w", bucket, err)
 	}
@@ -1052,6 +1262,8 @@ func (p *Posix) isObjDeleteMarker(bucket, object string) (bool, error) {
 // Converts the file to object version. Finds all the object versions,
 // delete markers from the versioning directory and returns
 func (p *Posix) fileToObjVersions(bucket string) backend.GetVersionsFunc {
+	compressed := p.isBucketCompressed(bucket)
+
 	return func(path, versionIdMarker string, pastVersionIdMarker *bool, availableObjCount int, d fs.DirEntry) (*backend.ObjVersionFuncResult, error) {
 		var objects []s3response.ObjectVersion
 		var delMarkers []types.DeleteMarkerEntry
@@ -1135,7 +1347,10 @@ func (p *Posix) fileToObjVersions(bucket string) backend.GetVersionsFunc {
 				return nil, fmt.Errorf("get fileinfo: %w", err)
 			}
 
-			size := fi.Size()
+			size, err := p.listedObjectSize(compressed, bucket, path, fi)
+			if err != nil {
+				return nil, err
+			}
 
 			isDel, err := p.isObjDeleteMarker(bucket, path)
 			if err != nil {
@@ -1189,7 +1404,7 @@ func (p *Posix) fileToObjVersions(bucket string) backend.GetVersionsFunc {
 
 		// List all the versions of the object in the versioning directory
 		versionPath := p.genObjVersionPath(bucket, path)
-		dirEnts, err := os.ReadDir(versionPath)
+		dirEnts, err := p.rootfs.ReadDir(versionPath)
 		if errors.Is(err, fs.ErrNotExist) {
 			return &backend.ObjVersionFuncResult{
 				ObjectVersions: objects,
@@ -1211,7 +1426,7 @@ func (p *Posix) fileToObjVersions(bucket string) backend.GetVersionsFunc {
 		// before starting the object versions listing
 		var nullVersionIdObj *s3response.ObjectVersion
 		var nullObjDelMarker *types.DeleteMarkerEntry
-		nf, err := os.Stat(filepath.Join(versionPath, nullVersionId))
+		nf, err := p.rootfs.Stat(filepath.Join(versionPath, nullVersionId))
 		if err != nil && !errors.Is(err, fs.ErrNotExist) {
 			return nil, err
 		}
@@ -1240,7 +1455,10 @@ func (p *Posix) fileToObjVersions(bucket string) backend.GetVersionsFunc {
 				// note: meta.ErrNoSuchKey will return etagBytes = []byte{}
 				// so this will just set etag to "" if its not already set
 				etag := string(etagBytes)
-				size := nf.Size()
+				size, err := p.listedObjectSize(compressed, versionPath, nullVersionId, nf)
+				if err != nil {
+					return nil, err
+				}
 				// Retrieve checksum
 				checksum, err := p.retrieveChecksums(nil, versionPath, nullVersionId)
 				if err != nil && !errors.Is(err, meta.ErrNoSuchKey) {
@@ -1330,7 +1548,10 @@ func (p *Posix) fileToObjVersions(bucket string) backend.GetVersionsFunc {
 				}
 			}
 			versionId := f.Name()
-			size := f.Size()
+			size, err := p.listedObjectSize(compressed, versionPath, versionId, f)
+			if err != nil {
+				return nil, err
+			}
 
 			if !*pastVersionIdMarker {
 				if versionId == versionIdMarker {
@@ -1438,7 +1659,7 @@ func (p *Posix) CreateMultipartUpload(ctx context.Context, mpu s3response.Create
 		return s3response.InitiateMultipartUploadResult{}, s3err.GetAPIError(s3err.ErrInvalidBucketName)
 	}
 
-	_, err = os.Stat(bucket)
+	_, err = p.rootfs.Stat(bucket)
 	if errors.Is(err, fs.ErrNotExist) {
 		return s3response.InitiateMultipartUploadResult{}, s3err.GetAPIError(s3err.ErrNoSuchBucket)
 	}
@@ -1446,6 +1667,11 @@ func (p *Posix) CreateMultipartUpload(ctx context.Context, mpu s3response.Create
 		return s3response.InitiateMultipartUploadResult{}, fmt.Errorf("stat bucket: %w", err)
 	}
 
+	err = p.checkBucketWritable(bucket)
+	if err != nil {
+		return s3response.InitiateMultipartUploadResult{}, err
+	}
+
 	if strings.HasSuffix(*mpu.Key, "/") {
 		// directory objects can't be uploaded with multipart uploads
 		// because posix directories can't contain data
@@ -1468,7 +1694,7 @@ func (p *Posix) CreateMultipartUpload(ctx context.Context, mpu s3response.Create
 	tmppath := filepath.Join(bucket, objdir)
 	// the unique upload id is a directory for all of the parts
 	// associated with this specific multipart upload
-	err = os.MkdirAll(filepath.Join(tmppath, uploadID), 0755)
+	err = p.rootfs.MkdirAll(filepath.Join(tmppath, uploadID), 0, 0, false, 0755)
 	if err != nil {
 		return s3response.InitiateMultipartUploadResult{}, fmt.Errorf("create upload temp dir: %w", err)
 	}
@@ -1480,8 +1706,8 @@ func (p *Posix) CreateMultipartUpload(ctx context.Context, mpu s3response.Create
 		// if we fail, cleanup the container directories
 		// but ignore errors because there might still be
 		// other uploads for the same object name outstanding
-		os.RemoveAll(filepath.Join(tmppath, uploadID))
-		os.Remove(tmppath)
+		p.rootfs.RemoveAll(filepath.Join(tmppath, uploadID))
+		p.rootfs.Remove(tmppath)
 		return s3response.InitiateMultipartUploadResult{}, fmt.Errorf("set name attr for upload: %w", err)
 	}
 
@@ -1490,8 +1716,8 @@ func (p *Posix) CreateMultipartUpload(ctx context.Context, mpu s3response.Create
 		err := p.PutObjectTagging(withCtxNoSlot(ctx), bucket, filepath.Join(objdir, uploadID), "", tags)
 		if err != nil {
 			// cleanup object if returning error
-			os.RemoveAll(filepath.Join(tmppath, uploadID))
-			os.Remove(tmppath)
+			p.rootfs.RemoveAll(filepath.Join(tmppath, uploadID))
+			p.rootfs.Remove(tmppath)
 			return s3response.InitiateMultipartUploadResult{}, err
 		}
 	}
@@ -1508,8 +1734,8 @@ func (p *Posix) CreateMultipartUpload(ctx context.Context, mpu s3response.Create
 		})
 	if err != nil {
 		// cleanup object if returning error
-		os.RemoveAll(filepath.Join(tmppath, uploadID))
-		os.Remove(tmppath)
+		p.rootfs.RemoveAll(filepath.Join(tmppath, uploadID))
+		p.rootfs.Remove(tmppath)
 		return s3response.InitiateMultipartUploadResult{}, err
 	}
 
@@ -1521,8 +1747,8 @@ func (p *Posix) CreateMultipartUpload(ctx context.Context, mpu s3response.Create
 				err = s3err.GetAPIError(s3err.ErrMissingObjectLockConfigurationNoSpaces)
 			}
 			// cleanup object if returning error
-			os.RemoveAll(filepath.Join(tmppath, uploadID))
-			os.Remove(tmppath)
+			p.rootfs.RemoveAll(filepath.Join(tmppath, uploadID))
+			p.rootfs.Remove(tmppath)
 			return s3response.InitiateMultipartUploadResult{}, err
 		}
 	}
@@ -1536,8 +1762,8 @@ func (p *Posix) CreateMultipartUpload(ctx context.Context, mpu s3response.Create
 		retParsed, err := json.Marshal(retention)
 		if err != nil {
 			// cleanup object if returning error
-			os.RemoveAll(filepath.Join(tmppath, uploadID))
-			os.Remove(tmppath)
+			p.rootfs.RemoveAll(filepath.Join(tmppath, uploadID))
+			p.rootfs.Remove(tmppath)
 			return s3response.InitiateMultipartUploadResult{}, fmt.Errorf("parse object lock retention: %w", err)
 		}
 		err = p.PutObjectRetention(withCtxNoSlot(ctx), bucket, filepath.Join(objdir, uploadID), "", retParsed)
@@ -1546,8 +1772,8 @@ func (p *Posix) CreateMultipartUpload(ctx context.Context, mpu s3response.Create
 				err = s3err.GetAPIError(s3err.ErrMissingObjectLockConfigurationNoSpaces)
 			}
 			// cleanup object if returning error
-			os.RemoveAll(filepath.Join(tmppath, uploadID))
-			os.Remove(tmppath)
+			p.rootfs.RemoveAll(filepath.Join(tmppath, uploadID))
+			p.rootfs.Remove(tmppath)
 			return s3response.InitiateMultipartUploadResult{}, err
 		}
 	}
@@ -1560,12 +1786,26 @@ func (p *Posix) CreateMultipartUpload(ctx context.Context, mpu s3response.Create
 		})
 		if err != nil {
 			// cleanup object if returning error
-			_ = os.RemoveAll(filepath.Join(tmppath, uploadID))
-			_ = os.Remove(tmppath)
+			_ = p.rootfs.RemoveAll(filepath.Join(tmppath, uploadID))
+			_ = p.rootfs.Remove(tmppath)
 			return s3response.InitiateMultipartUploadResult{}, fmt.Errorf("store mp checksum algorithm: %w", err)
 		}
 	}
 
+	// the compression of the upload parts is decided on the upload
+	// creation, so all parts are compressed the same way
+	compression, err := p.getObjectCompression(bucket, mpu.ContentType, mpu.ContentEncoding)
+	if err == nil && compression != "" {
+		err = p.storeCompressionInfo(nil, bucket, filepath.Join(objdir, uploadID),
+			compressionInfo{Algorithm: compression})
+	}
+	if err != nil {
+		// cleanup object if returning error
+		_ = p.rootfs.RemoveAll(filepath.Join(tmppath, uploadID))
+		_ = p.rootfs.Remove(tmppath)
+		return s3response.InitiateMultipartUploadResult{}, err
+	}
+
 	return s3response.InitiateMultipartUploadResult{
 		Bucket:   bucket,
 		Key:      object,
@@ -1592,6 +1832,22 @@ func (p *Posix) getChownIDs(acct auth.Account) (int, int, bool) {
 	return uid, gid, needsChown
 }
 
+// getTmpFilePaths returns the filesystem paths of the temp file directory
+// and the bucket the temp file is linked into, after validating that the
+// paths don't escape the gateway root directory.
+func (p *Posix) getTmpFilePaths(dir, bucket, obj string) (string, string, error) {
+	err := p.rootfs.CheckPath(dir)
+	if err != nil {
+		return "", "", err
+	}
+	err = p.rootfs.CheckPath(filepath.Join(bucket, obj))
+	if err != nil {
+		return "", "", err
+	}
+
+	return p.rootfs.Path(dir), p.rootfs.Path(bucket), nil
+}
+
 func getPartChecksum(algo types.ChecksumAlgorithm, part types.CompletedPart) string {
 	switch algo {
 	case types.ChecksumAlgorithmCrc32:
@@ -1657,7 +1913,7 @@ func (p *Posix) CompleteMultipartUploadWithCopy(ctx context.Context, input *s3.C
 		return res, "", s3err.GetAPIError(s3err.ErrInvalidBucketName)
 	}
 
-	_, err := os.Stat(bucket)
+	_, err := p.rootfs.Stat(bucket)
 	if errors.Is(err, fs.ErrNotExist) {
 		return res, "", s3err.GetAPIError(s3err.ErrNoSuchBucket)
 	}
@@ -1665,6 +1921,12 @@ func (p *Posix) CompleteMultipartUploadWithCopy(ctx context.Context, input *s3.C
 		return res, "", fmt.Errorf("stat bucket: %w", err)
 	}
 
+	err = p.checkBucketWritable(bucket)
+	if err != nil {
+		return res, "", err
+	}
+	defer p.objectChanged(bucket, object)
+
 	sum, err := p.checkUploadIDExists(bucket, object, uploadID)
 	if err != nil {
 		return res, "", err
@@ -1698,6 +1960,11 @@ func (p *Posix) CompleteMultipartUploadWithCopy(ctx context.Context, input *s3.C
 	// mpChecksumType holds the multipart upload checksum type
 	mpChecksumType := checksums.Type
 
+	mpCompression, err := p.getCompressionInfo(nil, bucket, filepath.Join(objdir, uploadID))
+	if err != nil {
+		return res, "", err
+	}
+
 	// The checksum type/algorithm should default to FULL_OBJECT(crc64nvme)
 	if checksums.Type == "" {
 		checksums.Type = types.ChecksumTypeFullObject
@@ -1725,15 +1992,20 @@ func (p *Posix) CompleteMultipartUploadWithCopy(ctx context.Context, input *s3.C
 
 		partObjPath := filepath.Join(objdir, uploadID, fmt.Sprintf("%v", *part.PartNumber))
 		fullPartPath := filepath.Join(bucket, partObjPath)
-		fi, err := os.Lstat(fullPartPath)
+		fi, err := p.rootfs.Lstat(fullPartPath)
 		if err != nil {
 			return res, "", s3err.GetAPIError(s3err.ErrInvalidPart)
 		}
 
-		totalsize += fi.Size()
+		partSize, err := p.objectSize(nil, bucket, partObjPath, fi)
+		if err != nil {
+			return res, "", err
+		}
+
+		totalsize += partSize
 		// all parts except the last need to be greater, than or equal to
 		// the minimum allowed size (5 Mib)
-		if i < last && fi.Size() < backend.MinPartSize {
+		if i < last && partSize < backend.MinPartSize {
 			return res, "", s3err.GetAPIError(s3err.ErrEntityTooSmall)
 		}
 
@@ -1783,10 +2055,11 @@ func (p *Posix) CompleteMultipartUploadWithCopy(ctx context.Context, input *s3.C
 
 	var composableCsum string
 	var abortOnErrSet bool
+	var frames []compressFrame
 	for i, part := range parts {
 		partObjPath := filepath.Join(objdir, uploadID, fmt.Sprintf("%v", *part.PartNumber))
 		fullPartPath := filepath.Join(bucket, partObjPath)
-		pf, err := os.Open(fullPartPath)
+		pf, err := p.rootfs.Open(fullPartPath)
 		if err != nil {
 			return res, "", fmt.Errorf("open part %v: %v", *part.PartNumber, err)
 		}
@@ -1796,6 +2069,17 @@ func (p *Posix) CompleteMultipartUploadWithCopy(ctx context.Context, input *s3.C
 			return res, "", fmt.Errorf("stat part %v: %v", *part.PartNumber, err)
 		}
 
+		partCompression, err := p.getCompressionInfo(pf, bucket, partObjPath)
+		if err != nil {
+			pf.Close()
+			return res, "", err
+		}
+
+		partSize := pfi.Size()
+		if partCompression != nil {
+			partSize = partCompression.Size
+		}
+
 		switch checksums.Type {
 		case types.ChecksumTypeFullObject:
 			var partChecksum string
@@ -1817,7 +2101,7 @@ func (p *Posix) CompleteMultipartUploadWithCopy(ctx context.Context, input *s3.C
 				composableCsum = partChecksum
 				break
 			}
-			composableCsum, err = utils.AddCRCChecksum(checksums.Algorithm, composableCsum, partChecksum, pfi.Size())
+			composableCsum, err = utils.AddCRCChecksum(checksums.Algorithm, composableCsum, partChecksum, partSize)
 			if err != nil {
 				pf.Close()
 				return res, "", fmt.Errorf("add part %v checksum: %w",
@@ -1832,7 +2116,11 @@ func (p *Posix) CompleteMultipartUploadWithCopy(ctx context.Context, input *s3.C
 			}
 		}
 
-		if customCopy != nil {
+		if mpCompression != nil {
+			// the compressed frames of the parts are concatenated, and
+			// the object frame index is built from the part indexes
+			frames, err = p.appendCompressedFrames(f.File(), pf, partCompression, frames)
+		} else if customCopy != nil {
 			idemp, err := customCopy(pf, f.File())
 			if err != nil {
 				// Fail back to standard copy
@@ -1853,16 +2141,16 @@ func (p *Posix) CompleteMultipartUploadWithCopy(ctx context.Context, input *s3.C
 				if !abortOnErrSet {
 					defer func() {
 						// cleanup tmp dirs
-						os.RemoveAll(filepath.Join(bucket, objdir, uploadID))
+						p.rootfs.RemoveAll(filepath.Join(bucket, objdir, uploadID))
 						// use Remove for objdir in case there are still other
 						// uploads for same object name outstanding, this will
 						// fail if there are any
-						os.Remove(filepath.Join(bucket, objdir))
+						p.rootfs.Remove(filepath.Join(bucket, objdir))
 					}()
 				}
 				abortOnErrSet = true
 			}
-		} else {
+		} else if !p.cloneFileData(f.File(), pf, 0, pfi.Size()) {
 			if p.forceNoCopyFileRange {
 				_, err = io.Copy(f.File(), &onlyRead{pf})
 			} else {
@@ -1878,6 +2166,18 @@ func (p *Posix) CompleteMultipartUploadWithCopy(ctx context.Context, input *s3.C
 		}
 	}
 
+	if mpCompression != nil {
+		err = writeCompressIndex(f.File(), frames)
+		if err != nil {
+			return res, "", err
+		}
+		err = p.storeCompressionInfo(f.File(), bucket, object,
+			newCompressionInfo(mpCompression.Algorithm, frames))
+		if err != nil {
+			return res, "", err
+		}
+	}
+
 	upiddir := filepath.Join(objdir, uploadID)
 
 	objMeta := p.loadObjectMetaProperties(nil, bucket, upiddir, nil)
@@ -1890,7 +2190,7 @@ func (p *Posix) CompleteMultipartUploadWithCopy(ctx context.Context, input *s3.C
 	dir := filepath.Dir(objname)
 	if dir != "" {
 		uid, gid, doChown := p.getChownIDs(acct)
-		err = backend.MkdirAll(dir, uid, gid, doChown, p.newDirPerm)
+		err = p.rootfs.MkdirAll(dir, uid, gid, doChown, p.newDirPerm)
 		if err != nil {
 			return res, "", err
 		}
@@ -1902,7 +2202,7 @@ func (p *Posix) CompleteMultipartUploadWithCopy(ctx context.Context, input *s3.C
 	}
 	vEnabled := p.isBucketVersioningEnabled(vStatus)
 
-	d, err := os.Stat(objname)
+	d, err := p.rootfs.Stat(objname)
 
 	// if the versioning is enabled first create the file object version
 	if p.versioningEnabled() && vEnabled && err == nil && !d.IsDir() {
@@ -2030,11 +2330,18 @@ func (p *Posix) CompleteMultipartUploadWithCopy(ctx context.Context, input *s3.C
 		return res, "", fmt.Errorf("link object in namespace: %w", err)
 	}
 
+	if mpCompression == nil {
+		err = p.clearCompressionInfo(bucket, object)
+		if err != nil {
+			return res, "", err
+		}
+	}
+
 	// cleanup tmp dirs
-	os.RemoveAll(filepath.Join(bucket, objdir, uploadID))
+	p.rootfs.RemoveAll(filepath.Join(bucket, objdir, uploadID))
 	// use Remove for objdir in case there are still other uploads
 	// for same object name outstanding, this will fail if there are any
-	os.Remove(filepath.Join(bucket, objdir))
+	p.rootfs.Remove(filepath.Join(bucket, objdir))
 
 	return s3response.CompleteMultipartUploadResult{
 		Bucket:            &bucket,
@@ -2131,7 +2438,7 @@ func (p *Posix) checkUploadIDExists(bucket, object, uploadID string) ([32]byte,
 	sum := sha256.Sum256([]byte(object))
 	objdir := filepath.Join(bucket, MetaTmpMultipartDir, fmt.Sprintf("%x", sum))
 
-	_, err := os.Stat(filepath.Join(objdir, uploadID))
+	_, err := p.rootfs.Stat(filepath.Join(objdir, uploadID))
 	if errors.Is(err, fs.ErrNotExist) {
 		return [32]byte{}, s3err.GetAPIError(s3err.ErrNoSuchUpload)
 	}
@@ -2270,10 +2577,15 @@ func (p *Posix) storeObjectMetadata(f *os.File, bucket, object string, meta map[
 		return err
 	}
 
-	// cleanup any previously set legacy metadata
+	p.removeLegacyMetadata(bucket, object)
+	return nil
+}
+
+// removeLegacyMetadata cleans up any previously set legacy metadata
+func (p *Posix) removeLegacyMetadata(bucket, object string) {
 	ents, err := p.meta.ListAttributes(bucket, object)
 	if err != nil || len(ents) == 0 {
-		return nil
+		return
 	}
 
 	for _, ent := range ents {
@@ -2283,56 +2595,58 @@ func (p *Posix) storeObjectMetadata(f *os.File, bucket, object string, meta map[
 
 		_ = p.meta.DeleteAttribute(bucket, object, ent)
 	}
-
-	return nil
 }
 
-// storeObjectMetaProperties stores the object meta properties like:
-// Content-Type, Content-Encoding, object Metadata ...
-func (p *Posix) storeObjectMetaProperties(f *os.File, bucket, object string, m metaProperties) error {
-	if getString(m.ContentType) != "" {
-		err := p.meta.StoreAttribute(f, bucket, object, contentTypeHdr, []byte(*m.ContentType))
-		if err != nil {
-			return fmt.Errorf("set content-type: %w", err)
-		}
-	}
-	if getString(m.ContentEncoding) != "" {
-		err := p.meta.StoreAttribute(f, bucket, object, contentEncHdr, []byte(*m.ContentEncoding))
-		if err != nil {
-			return fmt.Errorf("set content-encoding: %w", err)
-		}
+// attributes returns the meta properties as the object attributes
+func (m metaProperties) attributes(attrs map[string][]byte) error {
+	props := []struct {
+		key   string
+		value *string
+	}{
+		{contentTypeHdr, m.ContentType},
+		{contentEncHdr, m.ContentEncoding},
+		{contentDispHdr, m.ContentDisposition},
+		{contentLangHdr, m.ContentLanguage},
+		{cacheCtrlHdr, m.CacheControl},
+		{expiresHdr, m.Expires},
 	}
-	if getString(m.ContentDisposition) != "" {
-		err := p.meta.StoreAttribute(f, bucket, object, contentDispHdr, []byte(*m.ContentDisposition))
-		if err != nil {
-			return fmt.Errorf("set content-disposition: %w", err)
+	for _, prop := range props {
+		if getString(prop.value) != "" {
+			attrs[prop.key] = []byte(*prop.value)
 		}
 	}
-	if getString(m.ContentLanguage) != "" {
-		err := p.meta.StoreAttribute(f, bucket, object, contentLangHdr, []byte(*m.ContentLanguage))
+
+	if len(m.Metadata) != 0 {
+		parsed, err := json.Marshal(m.Metadata)
 		if err != nil {
-			return fmt.Errorf("set content-language: %w", err)
+			return fmt.Errorf("marshal metadata: %w", err)
 		}
+		attrs[metadataHdr] = parsed
 	}
-	if getString(m.CacheControl) != "" {
-		err := p.meta.StoreAttribute(f, bucket, object, cacheCtrlHdr, []byte(*m.CacheControl))
-		if err != nil {
-			return fmt.Errorf("set cache-control: %w", err)
-		}
+
+	return nil
+}
+
+// storeObjectMetaProperties stores the object meta properties like:
+// Content-Type, Content-Encoding, object Metadata ...
+func (p *Posix) storeObjectMetaProperties(f *os.File, bucket, object string, m metaProperties) error {
+	attrs := map[string][]byte{}
+	err := m.attributes(attrs)
+	if err != nil {
+		return err
 	}
-	if getString(m.Expires) != "" {
-		err := p.meta.StoreAttribute(f, bucket, object, expiresHdr, []byte(*m.Expires))
-		if err != nil {
-			return fmt.Errorf("set expires: %w", err)
-		}
+	if len(attrs) == 0 {
+		return nil
 	}
-	if m.Metadata != nil {
-		err := p.storeObjectMetadata(f, bucket, object, m.Metadata)
-		if err != nil {
-			return fmt.Errorf("set metadata: %w", err)
-		}
+
+	err = meta.StoreAttributes(p.meta, f, bucket, object, attrs)
+	if err != nil {
+		return fmt.Errorf("set meta properties: %w", err)
 	}
 
+	if len(m.Metadata) != 0 {
+		p.removeLegacyMetadata(bucket, object)
+	}
 	return nil
 }
 
@@ -2362,7 +2676,7 @@ func (p *Posix) AbortMultipartUpload(ctx context.Context, mpu *s3.AbortMultipart
 		return s3err.GetAPIError(s3err.ErrInvalidBucketName)
 	}
 
-	_, err = os.Stat(bucket)
+	_, err = p.rootfs.Stat(bucket)
 	if errors.Is(err, fs.ErrNotExist) {
 		return s3err.GetAPIError(s3err.ErrNoSuchBucket)
 	}
@@ -2373,7 +2687,7 @@ func (p *Posix) AbortMultipartUpload(ctx context.Context, mpu *s3.AbortMultipart
 	sum := sha256.Sum256([]byte(object))
 	objdir := filepath.Join(bucket, MetaTmpMultipartDir, fmt.Sprintf("%x", sum))
 
-	f, err := os.Stat(filepath.Join(objdir, uploadID))
+	f, err := p.rootfs.Stat(filepath.Join(objdir, uploadID))
 	if err != nil {
 		return s3err.GetAPIError(s3err.ErrNoSuchUpload)
 	}
@@ -2384,11 +2698,11 @@ func (p *Posix) AbortMultipartUpload(ctx context.Context, mpu *s3.AbortMultipart
 		}
 	}
 
-	err = os.RemoveAll(filepath.Join(objdir, uploadID))
+	err = p.rootfs.RemoveAll(filepath.Join(objdir, uploadID))
 	if err != nil {
 		return fmt.Errorf("remove multipart upload container: %w", err)
 	}
-	os.Remove(objdir)
+	p.rootfs.Remove(objdir)
 
 	return nil
 }
@@ -2426,7 +2740,7 @@ func (p *Posix) ListMultipartUploads(ctx context.Context, mpu *s3.ListMultipartU
 	}
 	maxUploads := int(*mpu.MaxUploads)
 
-	_, err = os.Stat(bucket)
+	_, err = p.rootfs.Stat(bucket)
 	if errors.Is(err, fs.ErrNotExist) {
 		return lmu, s3err.GetAPIError(s3err.ErrNoSuchBucket)
 	}
@@ -2435,7 +2749,7 @@ func (p *Posix) ListMultipartUploads(ctx context.Context, mpu *s3.ListMultipartU
 	}
 
 	// ignore readdir error and use the empty list returned
-	objs, _ := os.ReadDir(filepath.Join(bucket, MetaTmpMultipartDir))
+	objs, _ := p.rootfs.ReadDir(filepath.Join(bucket, MetaTmpMultipartDir))
 
 	var uploads []s3response.Upload
 
@@ -2458,7 +2772,7 @@ func (p *Posix) ListMultipartUploads(ctx context.Context, mpu *s3.ListMultipartU
 			continue
 		}
 
-		upids, err := os.ReadDir(filepath.Join(bucket, MetaTmpMultipartDir, obj.Name()))
+		upids, err := p.rootfs.ReadDir(filepath.Join(bucket, MetaTmpMultipartDir, obj.Name()))
 		if err != nil {
 			continue
 		}
@@ -2558,7 +2872,7 @@ func (p *Posix) ListParts(ctx context.Context, input *s3.ListPartsInput) (s3resp
 		}
 	}
 
-	_, err = os.Stat(bucket)
+	_, err = p.rootfs.Stat(bucket)
 	if errors.Is(err, fs.ErrNotExist) {
 		return lpr, s3err.GetAPIError(s3err.ErrNoSuchBucket)
 	}
@@ -2574,7 +2888,7 @@ func (p *Posix) ListParts(ctx context.Context, input *s3.ListPartsInput) (s3resp
 	objdir := filepath.Join(MetaTmpMultipartDir, fmt.Sprintf("%x", sum))
 	tmpdir := filepath.Join(bucket, objdir)
 
-	ents, err := os.ReadDir(filepath.Join(tmpdir, uploadID))
+	ents, err := p.rootfs.ReadDir(filepath.Join(tmpdir, uploadID))
 	if errors.Is(err, fs.ErrNotExist) {
 		return lpr, s3err.GetAPIError(s3err.ErrNoSuchUpload)
 	}
@@ -2593,6 +2907,13 @@ func (p *Posix) ListParts(ctx context.Context, input *s3.ListPartsInput) (s3resp
 		checksum.Type = types.ChecksumType("null")
 	}
 
+	// the part sizes are read from the compression info
+	// only if the upload parts are compressed
+	mpCompression, err := p.getCompressionInfo(nil, bucket, filepath.Join(objdir, uploadID))
+	if err != nil {
+		return lpr, err
+	}
+
 	parts := make([]s3response.Part, 0, len(ents))
 	for i, e := range ents {
 		if i%128 == 0 {
@@ -2623,7 +2944,12 @@ func (p *Posix) ListParts(ctx context.Context, input *s3.ListPartsInput) (s3resp
 			continue
 		}
 
-		fi, err := os.Lstat(filepath.Join(bucket, partPath))
+		fi, err := p.rootfs.Lstat(filepath.Join(bucket, partPath))
+		if err != nil {
+			continue
+		}
+
+		size, err := p.listedObjectSize(mpCompression != nil, bucket, partPath, fi)
 		if err != nil {
 			continue
 		}
@@ -2632,7 +2958,7 @@ func (p *Posix) ListParts(ctx context.Context, input *s3.ListPartsInput) (s3resp
 			PartNumber:        pn,
 			ETag:              etag,
 			LastModified:      fi.ModTime(),
-			Size:              fi.Size(),
+			Size:              size,
 			ChecksumCRC32:     checksum.CRC32,
 			ChecksumCRC32C:    checksum.CRC32C,
 			ChecksumSHA1:      checksum.SHA1,
@@ -2709,7 +3035,7 @@ func (p *Posix) UploadPartWithPostFunc(ctx context.Context, input *s3.UploadPart
 	}
 	r := input.Body
 
-	_, err := os.Stat(bucket)
+	_, err := p.rootfs.Stat(bucket)
 	if errors.Is(err, fs.ErrNotExist) {
 		return nil, s3err.GetAPIError(s3err.ErrNoSuchBucket)
 	}
@@ -2717,11 +3043,16 @@ func (p *Posix) UploadPartWithPostFunc(ctx context.Context, input *s3.UploadPart
 		return nil, fmt.Errorf("stat bucket: %w", err)
 	}
 
+	err = p.checkBucketWritable(bucket)
+	if err != nil {
+		return nil, err
+	}
+
 	sum := sha256.Sum256([]byte(object))
 	objdir := filepath.Join(MetaTmpMultipartDir, fmt.Sprintf("%x", sum))
 	mpPath := filepath.Join(objdir, uploadID)
 
-	_, err = os.Stat(filepath.Join(bucket, mpPath))
+	_, err = p.rootfs.Stat(filepath.Join(bucket, mpPath))
 	if errors.Is(err, fs.ErrNotExist) {
 		return nil, s3err.GetAPIError(s3err.ErrNoSuchUpload)
 	}
@@ -2731,8 +3062,18 @@ func (p *Posix) UploadPartWithPostFunc(ctx context.Context, input *s3.UploadPart
 
 	partPath := filepath.Join(mpPath, fmt.Sprintf("%v", *part))
 
+	mpCompression, err := p.getCompressionInfo(nil, bucket, mpPath)
+	if err != nil {
+		return nil, err
+	}
+
+	falloc := doFalloc
+	if mpCompression != nil {
+		falloc = skipFalloc
+	}
+
 	f, err := p.openTmpFile(filepath.Join(bucket, objdir),
-		bucket, partPath, length, acct, doFalloc, p.forceNoTmpFile)
+		bucket, partPath, length, acct, falloc, p.forceNoTmpFile)
 	if err != nil {
 		if errors.Is(err, syscall.EDQUOT) {
 			return nil, s3err.GetAPIError(s3err.ErrQuotaExceeded)
@@ -2741,6 +3082,20 @@ func (p *Posix) UploadPartWithPostFunc(ctx context.Context, input *s3.UploadPart
 	}
 	defer f.cleanup()
 
+	var dst io.Writer = f
+	var cw *compressWriter
+	if mpCompression != nil {
+		cw, err = newCompressWriter(f.File(), mpCompression.Algorithm, length)
+		if err != nil {
+			return nil, err
+		}
+		dst = cw
+	}
+	dw := p.dataWriter(f, bucket, length, cw == nil)
+	if dw != nil {
+		dst = dw
+	}
+
 	hash := md5.New()
 	tr := io.TeeReader(r, hash)
 
@@ -2844,7 +3199,15 @@ func (p *Posix) UploadPartWithPostFunc(ctx context.Context, input *s3.UploadPart
 		}
 	}
 
-	_, err = io.Copy(f, tr)
+	_, err = io.Copy(dst, tr)
+	err = closeDataWriter(dw, err)
+	if err == nil && cw != nil {
+		var info compressionInfo
+		info, err = cw.finish()
+		if err == nil {
+			err = p.storeCompressionInfo(f.File(), bucket, partPath, info)
+		}
+	}
 	if err != nil {
 		if errors.Is(err, syscall.EDQUOT) {
 			return nil, s3err.GetAPIError(s3err.ErrQuotaExceeded)
@@ -2980,7 +3343,7 @@ func (p *Posix) UploadPartCopy(ctx context.Context, upi *s3.UploadPartCopyInput)
 		return s3response.CopyPartResult{}, s3err.GetAPIError(s3err.ErrInvalidBucketName)
 	}
 
-	_, err = os.Stat(*upi.Bucket)
+	_, err = p.rootfs.Stat(*upi.Bucket)
 	if errors.Is(err, fs.ErrNotExist) {
 		return s3response.CopyPartResult{}, s3err.GetAPIError(s3err.ErrNoSuchBucket)
 	}
@@ -2988,10 +3351,15 @@ func (p *Posix) UploadPartCopy(ctx context.Context, upi *s3.UploadPartCopyInput)
 		return s3response.CopyPartResult{}, fmt.Errorf("stat bucket: %w", err)
 	}
 
+	err = p.checkBucketWritable(*upi.Bucket)
+	if err != nil {
+		return s3response.CopyPartResult{}, err
+	}
+
 	sum := sha256.Sum256([]byte(*upi.Key))
 	objdir := filepath.Join(MetaTmpMultipartDir, fmt.Sprintf("%x", sum))
 
-	_, err = os.Stat(filepath.Join(*upi.Bucket, objdir, *upi.UploadId))
+	_, err = p.rootfs.Stat(filepath.Join(*upi.Bucket, objdir, *upi.UploadId))
 	if errors.Is(err, fs.ErrNotExist) {
 		return s3response.CopyPartResult{}, s3err.GetAPIError(s3err.ErrNoSuchUpload)
 	}
@@ -3009,7 +3377,7 @@ func (p *Posix) UploadPartCopy(ctx context.Context, upi *s3.UploadPartCopyInput)
 		return s3response.CopyPartResult{}, err
 	}
 
-	_, err = os.Stat(srcBucket)
+	_, err = p.rootfs.Stat(srcBucket)
 	if errors.Is(err, fs.ErrNotExist) {
 		return s3response.CopyPartResult{}, s3err.GetAPIError(s3err.ErrNoSuchBucket)
 	}
@@ -3042,7 +3410,7 @@ func (p *Posix) UploadPartCopy(ctx context.Context, upi *s3.UploadPartCopyInput)
 	}
 
 	objPath := filepath.Join(srcBucket, srcObject)
-	fi, err := os.Stat(objPath)
+	fi, err := p.rootfs.Stat(objPath)
 	if errors.Is(err, fs.ErrNotExist) {
 		if p.versioningEnabled() && vEnabled {
 			return s3response.CopyPartResult{}, s3err.GetAPIError(s3err.ErrNoSuchVersion)
@@ -3056,12 +3424,22 @@ func (p *Posix) UploadPartCopy(ctx context.Context, upi *s3.UploadPartCopyInput)
 		return s3response.CopyPartResult{}, fmt.Errorf("stat object: %w", err)
 	}
 
-	startOffset, length, err := backend.ParseCopySourceRange(fi.Size(), *upi.CopySourceRange)
+	srcCompression, err := p.getCompressionInfo(nil, srcBucket, srcObject)
+	if err != nil {
+		return s3response.CopyPartResult{}, err
+	}
+
+	srcSize := fi.Size()
+	if srcCompression != nil {
+		srcSize = srcCompression.Size
+	}
+
+	startOffset, length, err := backend.ParseCopySourceRange(srcSize, *upi.CopySourceRange)
 	if err != nil {
 		return s3response.CopyPartResult{}, err
 	}
 
-	srcf, err := os.Open(objPath)
+	srcf, err := p.rootfs.Open(objPath)
 	if errors.Is(err, fs.ErrNotExist) {
 		return s3response.CopyPartResult{}, s3err.GetAPIError(s3err.ErrNoSuchKey)
 	}
@@ -3087,8 +3465,18 @@ func (p *Posix) UploadPartCopy(ctx context.Context, upi *s3.UploadPartCopyInput)
 		return s3response.CopyPartResult{}, err
 	}
 
+	mpCompression, err := p.getCompressionInfo(nil, *upi.Bucket, filepath.Join(objdir, *upi.UploadId))
+	if err != nil {
+		return s3response.CopyPartResult{}, err
+	}
+
+	falloc := doFalloc
+	if mpCompression != nil {
+		falloc = skipFalloc
+	}
+
 	f, err := p.openTmpFile(filepath.Join(*upi.Bucket, objdir),
-		*upi.Bucket, partPath, length, acct, doFalloc, p.forceNoTmpFile)
+		*upi.Bucket, partPath, length, acct, falloc, p.forceNoTmpFile)
 	if err != nil {
 		if errors.Is(err, syscall.EDQUOT) {
 			return s3response.CopyPartResult{}, s3err.GetAPIError(s3err.ErrQuotaExceeded)
@@ -3097,7 +3485,20 @@ func (p *Posix) UploadPartCopy(ctx context.Context, upi *s3.UploadPartCopyInput)
 	}
 	defer f.cleanup()
 
-	rdr := io.NewSectionReader(srcf, startOffset, length)
+	var dst io.Writer = f
+	var cw *compressWriter
+	if mpCompression != nil {
+		cw, err = newCompressWriter(f.File(), mpCompression.Algorithm, length)
+		if err != nil {
+			return s3response.CopyPartResult{}, err
+		}
+		dst = cw
+	}
+
+	rdr, err := objectDataReader(srcf, srcCompression, startOffset, length)
+	if err != nil {
+		return s3response.CopyPartResult{}, err
+	}
 	hash := md5.New()
 	tr := io.TeeReader(rdr, hash)
 
@@ -3133,7 +3534,20 @@ func (p *Posix) UploadPartCopy(ctx context.Context, upi *s3.UploadPartCopyInput)
 		tr = crc64nvmeRdr
 	}
 
-	_, err = io.Copy(f, tr)
+	if srcCompression == nil && cw == nil && p.cloneFileData(f.File(), srcf, startOffset, length) {
+		// the part data is cloned from the source object, the source
+		// range is only read to calculate the part etag and checksums
+		_, err = io.Copy(io.Discard, tr)
+	} else {
+		_, err = io.Copy(dst, tr)
+	}
+	if err == nil && cw != nil {
+		var info compressionInfo
+		info, err = cw.finish()
+		if err == nil {
+			err = p.storeCompressionInfo(f.File(), *upi.Bucket, partPath, info)
+		}
+	}
 	if err != nil {
 		if errors.Is(err, syscall.EDQUOT) {
 			return s3response.CopyPartResult{}, s3err.GetAPIError(s3err.ErrQuotaExceeded)
@@ -3200,7 +3614,7 @@ func (p *Posix) UploadPartCopy(ctx context.Context, upi *s3.UploadPartCopyInput)
 		return s3response.CopyPartResult{}, fmt.Errorf("link object in namespace: %w", err)
 	}
 
-	fi, err = os.Stat(filepath.Join(*upi.Bucket, partPath))
+	fi, err = p.rootfs.Stat(filepath.Join(*upi.Bucket, partPath))
 	if err != nil {
 		return s3response.CopyPartResult{}, fmt.Errorf("stat part path: %w", err)
 	}
@@ -3257,7 +3671,7 @@ func (p *Posix) PutObjectWithPostFunc(ctx context.Context, po s3response.PutObje
 	if !p.isBucketValid(*po.Bucket) {
 		return s3response.PutObjectOutput{}, s3err.GetAPIError(s3err.ErrInvalidBucketName)
 	}
-	_, err := os.Stat(*po.Bucket)
+	_, err := p.rootfs.Stat(*po.Bucket)
 	if errors.Is(err, fs.ErrNotExist) {
 		return s3response.PutObjectOutput{}, s3err.GetAPIError(s3err.ErrNoSuchBucket)
 	}
@@ -3265,6 +3679,12 @@ func (p *Posix) PutObjectWithPostFunc(ctx context.Context, po s3response.PutObje
 		return s3response.PutObjectOutput{}, fmt.Errorf("stat bucket: %w", err)
 	}
 
+	err = p.checkBucketWritable(*po.Bucket)
+	if err != nil {
+		return s3response.PutObjectOutput{}, err
+	}
+	defer p.objectChanged(*po.Bucket, *po.Key)
+
 	tags, err := backend.ParseObjectTags(getString(po.Tagging))
 	if err != nil {
 		return s3response.PutObjectOutput{}, err
@@ -3329,7 +3749,7 @@ func (p *Posix) PutObjectWithPostFunc(ctx context.Context, po s3response.PutObje
 			return s3response.PutObjectOutput{}, s3err.GetAPIError(s3err.ErrDirectoryObjectContainsData)
 		}
 
-		err = backend.MkdirAll(name, uid, gid, doChown, p.newDirPerm)
+		err = p.rootfs.MkdirAll(name, uid, gid, doChown, p.newDirPerm)
 		if err != nil {
 			if errors.Is(err, syscall.EDQUOT) {
 				return s3response.PutObjectOutput{}, s3err.GetAPIError(s3err.ErrQuotaExceeded)
@@ -3414,7 +3834,7 @@ func (p *Posix) PutObjectWithPostFunc(ctx context.Context, po s3response.PutObje
 	vEnabled := p.isBucketVersioningEnabled(vStatus)
 
 	// object is file
-	d, err := os.Stat(name)
+	d, err := p.rootfs.Stat(name)
 	if err == nil && d.IsDir() {
 		return s3response.PutObjectOutput{}, s3err.GetAPIError(s3err.ErrExistingObjectIsDirectory)
 	}
@@ -3440,7 +3860,7 @@ func (p *Posix) PutObjectWithPostFunc(ctx context.Context, po s3response.PutObje
 		return s3response.PutObjectOutput{}, s3err.GetAPIError(s3err.ErrKeyTooLong)
 	}
 	if errors.Is(err, syscall.ENOTDIR) {
-		parentErr := handleParentDirError(name)
+		parentErr := handleParentDirError(p.rootfs.Path(name))
 		if parentErr != nil {
 			return s3response.PutObjectOutput{}, parentErr
 		}
@@ -3449,8 +3869,22 @@ func (p *Posix) PutObjectWithPostFunc(ctx context.Context, po s3response.PutObje
 		return s3response.PutObjectOutput{}, fmt.Errorf("stat object: %w", err)
 	}
 
+	var compression string
+	if contentLength > 0 {
+		compression, err = p.getObjectCompression(*po.Bucket, po.ContentType, po.ContentEncoding)
+		if err != nil {
+			return s3response.PutObjectOutput{}, err
+		}
+	}
+
+	// the compressed data size is not known in advance
+	falloc := doFalloc
+	if compression != "" {
+		falloc = skipFalloc
+	}
+
 	f, err := p.openTmpFile(filepath.Join(*po.Bucket, MetaTmpDir),
-		*po.Bucket, *po.Key, contentLength, acct, doFalloc, p.forceNoTmpFile)
+		*po.Bucket, *po.Key, contentLength, acct, falloc, p.forceNoTmpFile)
 	if err != nil {
 		if errors.Is(err, syscall.EDQUOT) {
 			return s3response.PutObjectOutput{}, s3err.GetAPIError(s3err.ErrQuotaExceeded)
@@ -3461,6 +3895,20 @@ func (p *Posix) PutObjectWithPostFunc(ctx context.Context, po s3response.PutObje
 
 	objsize := f.size
 
+	var dst io.Writer = f
+	var cw *compressWriter
+	if compression != "" {
+		cw, err = newCompressWriter(f.File(), compression, contentLength)
+		if err != nil {
+			return s3response.PutObjectOutput{}, err
+		}
+		dst = cw
+	}
+	dw := p.dataWriter(f, *po.Bucket, contentLength, cw == nil)
+	if dw != nil {
+		dst = dw
+	}
+
 	hash := md5.New()
 	rdr := io.TeeReader(po.Body, hash)
 
@@ -3474,7 +3922,36 @@ func (p *Posix) PutObjectWithPostFunc(ctx context.Context, po s3response.PutObje
 		rdr = hashRdr
 	}
 
-	_, err = io.Copy(f, rdr)
+	// the data of the objects copied within the gateway is cloned
+	// from the source object file
+	var cloned bool
+	if src, ok := po.Body.(*os.File); ok && !isTrailingChecksum && compression == "" {
+		cloned = p.cloneFileData(f.File(), src, 0, contentLength)
+	}
+
+	// the source object etag and checksum remain valid for the cloned
+	// data, so the data doesn't need to be read to calculate them
+	var srcEtag, srcSum string
+	if cloned {
+		srcEtag, srcSum = getCopySourceSums(ctx, checksumAlgorithm, checksumValue)
+	}
+
+	switch {
+	case srcEtag != "":
+	case cloned:
+		_, err = io.Copy(io.Discard, rdr)
+	default:
+		_, err = io.Copy(dst, rdr)
+	}
+	err = closeDataWriter(dw, err)
+	var compressed []byte
+	if err == nil && cw != nil {
+		var info compressionInfo
+		info, err = cw.finish()
+		if err == nil {
+			compressed, err = json.Marshal(info)
+		}
+	}
 	if err != nil {
 		if errors.Is(err, syscall.EDQUOT) {
 			return s3response.PutObjectOutput{}, s3err.GetAPIError(s3err.ErrQuotaExceeded)
@@ -3488,13 +3965,16 @@ func (p *Posix) PutObjectWithPostFunc(ctx context.Context, po s3response.PutObje
 
 	dir := filepath.Dir(name)
 	if dir != "" {
-		err = backend.MkdirAll(dir, uid, gid, doChown, p.newDirPerm)
+		err = p.rootfs.MkdirAll(dir, uid, gid, doChown, p.newDirPerm)
 		if err != nil {
 			return s3response.PutObjectOutput{}, s3err.GetAPIError(s3err.ErrExistingObjectIsDirectory)
 		}
 	}
 
-	etag := backend.GenerateEtag(hash)
+	etag := srcEtag
+	if etag == "" {
+		etag = backend.GenerateEtag(hash)
+	}
 
 	// if the versioning is enabled, generate a new versionID for the object
 	var versionID string
@@ -3513,11 +3993,10 @@ func (p *Posix) PutObjectWithPostFunc(ctx context.Context, po s3response.PutObje
 		versionID = nullVersionId
 	}
 
-	var sum string
-
+	sum := srcSum
 	if isTrailingChecksum {
 		sum = chRdr.Checksum()
-	} else {
+	} else if sum == "" {
 		sum = hashRdr.Sum()
 	}
 
@@ -3539,35 +4018,43 @@ func (p *Posix) PutObjectWithPostFunc(ctx context.Context, po s3response.PutObje
 	case types.ChecksumAlgorithmCrc64nvme:
 		checksum.CRC64NVME = &sum
 	}
-	err = p.storeChecksums(f.File(), *po.Bucket, *po.Key, checksum)
+	checksums, err := json.Marshal(checksum)
 	if err != nil {
-		return s3response.PutObjectOutput{}, fmt.Errorf("store checksum: %w", err)
+		return s3response.PutObjectOutput{}, fmt.Errorf("parse checksum: %w", err)
 	}
 
-	err = p.meta.StoreAttribute(f.File(), *po.Bucket, *po.Key, etagkey, []byte(etag))
-	if err != nil {
-		return s3response.PutObjectOutput{}, fmt.Errorf("set etag attr: %w", err)
+	// the object attributes are stored at once, so the metadata
+	// stores with transactions only need a single transaction
+	attrs := map[string][]byte{
+		checksumsKey: checksums,
+		etagkey:      []byte(etag),
 	}
-
-	err = p.storeObjectMetaProperties(f.File(), *po.Bucket, *po.Key,
-		metaProperties{
-			ContentType:        po.ContentType,
-			ContentEncoding:    po.ContentEncoding,
-			ContentLanguage:    po.ContentLanguage,
-			ContentDisposition: po.ContentDisposition,
-			CacheControl:       po.CacheControl,
-			Expires:            po.Expires,
-			Metadata:           po.Metadata,
-		})
+	if compressed != nil {
+		attrs[compressionKey] = compressed
+	}
+	if versionID != "" && versionID != nullVersionId {
+		attrs[versionIdKey] = []byte(versionID)
+	}
+	objMeta := metaProperties{
+		ContentType:        po.ContentType,
+		ContentEncoding:    po.ContentEncoding,
+		ContentLanguage:    po.ContentLanguage,
+		ContentDisposition: po.ContentDisposition,
+		CacheControl:       po.CacheControl,
+		Expires:            po.Expires,
+		Metadata:           po.Metadata,
+	}
+	err = objMeta.attributes(attrs)
 	if err != nil {
 		return s3response.PutObjectOutput{}, err
 	}
 
-	if versionID != "" && versionID != nullVersionId {
-		err := p.meta.StoreAttribute(f.File(), *po.Bucket, *po.Key, versionIdKey, []byte(versionID))
-		if err != nil {
-			return s3response.PutObjectOutput{}, fmt.Errorf("set versionId attr: %w", err)
-		}
+	err = meta.StoreAttributes(p.meta, f.File(), *po.Bucket, *po.Key, attrs)
+	if err != nil {
+		return s3response.PutObjectOutput{}, fmt.Errorf("store object attributes: %w", err)
+	}
+	if len(po.Metadata) != 0 {
+		p.removeLegacyMetadata(*po.Bucket, *po.Key)
 	}
 
 	err = postprocess(f.File())
@@ -3587,6 +4074,13 @@ func (p *Posix) PutObjectWithPostFunc(ctx context.Context, po s3response.PutObje
 		return s3response.PutObjectOutput{}, s3err.GetAPIError(s3err.ErrExistingObjectIsDirectory)
 	}
 
+	if cw == nil {
+		err = p.clearCompressionInfo(*po.Bucket, *po.Key)
+		if err != nil {
+			return s3response.PutObjectOutput{}, err
+		}
+	}
+
 	// Set object tagging
 	if tags != nil {
 		err := p.PutObjectTagging(withCtxNoSlot(ctx), *po.Bucket, *po.Key, "", tags)
@@ -3663,7 +4157,7 @@ func (p *Posix) DeleteObject(ctx context.Context, input *s3.DeleteObjectInput) (
 		return nil, s3err.GetAPIError(s3err.ErrInvalidBucketName)
 	}
 
-	_, err = os.Stat(bucket)
+	_, err = p.rootfs.Stat(bucket)
 	if errors.Is(err, fs.ErrNotExist) {
 		return nil, s3err.GetAPIError(s3err.ErrNoSuchBucket)
 	}
@@ -3671,6 +4165,12 @@ func (p *Posix) DeleteObject(ctx context.Context, input *s3.DeleteObjectInput) (
 		return nil, fmt.Errorf("stat bucket: %w", err)
 	}
 
+	err = p.checkBucketWritable(bucket)
+	if err != nil {
+		return nil, err
+	}
+	defer p.objectChanged(bucket, object)
+
 	objpath := filepath.Join(bucket, object)
 
 	vStatus, err := p.getBucketVersioningStatus(ctx, bucket)
@@ -3681,7 +4181,7 @@ func (p *Posix) DeleteObject(ctx context.Context, input *s3.DeleteObjectInput) (
 	evalPreconditions := func(f os.FileInfo, bucket, object string) error {
 		var err error
 		if f == nil {
-			f, err = os.Stat(filepath.Join(bucket, object))
+			f, err = p.rootfs.Stat(filepath.Join(bucket, object))
 			if err != nil {
 				return nil
 			}
@@ -3693,8 +4193,13 @@ func (p *Posix) DeleteObject(ctx context.Context, input *s3.DeleteObjectInput) (
 			etag = ""
 		}
 
+		size, err := p.objectSize(nil, bucket, object, f)
+		if err != nil {
+			return err
+		}
+
 		// evaluate preconditions
-		return backend.EvaluateObjectDeletePreconditions(etag, f.ModTime(), f.Size(),
+		return backend.EvaluateObjectDeletePreconditions(etag, f.ModTime(), size,
 			backend.ObjectDeletePreconditions{
 				IfMatch:            input.IfMatch,
 				IfMatchLastModTime: input.IfMatchLastModifiedTime,
@@ -3706,7 +4211,7 @@ func (p *Posix) DeleteObject(ctx context.Context, input *s3.DeleteObjectInput) (
 	if !isDir && p.versioningEnabled() && vStatus != "" {
 		if getString(input.VersionId) == "" {
 			// if the versionId is not specified, make the current version a delete marker
-			fi, err := os.Stat(objpath)
+			fi, err := p.rootfs.Stat(objpath)
 			if errors.Is(err, fs.ErrNotExist) || errors.Is(err, syscall.ENOTDIR) {
 				// AWS returns success if the object does not exist
 				return &s3.DeleteObjectOutput{}, nil
@@ -3800,12 +4305,12 @@ func (p *Posix) DeleteObject(ctx context.Context, input *s3.DeleteObjectInput) (
 				if err != nil {
 					return nil, err
 				}
-				err = os.Remove(objpath)
+				err = p.rootfs.Remove(objpath)
 				if err != nil {
 					return nil, fmt.Errorf("remove obj version: %w", err)
 				}
 
-				ents, err := os.ReadDir(versionPath)
+				ents, err := p.rootfs.ReadDir(versionPath)
 				if errors.Is(err, fs.ErrNotExist) {
 					p.removeParents(bucket, object)
 					return &s3.DeleteObjectOutput{
@@ -3830,7 +4335,7 @@ func (p *Posix) DeleteObject(ctx context.Context, input *s3.DeleteObjectInput) (
 					return nil, fmt.Errorf("get file info: %w", err)
 				}
 				srcVersionId := srcObjVersion.Name()
-				sf, err := os.Open(filepath.Join(versionPath, srcVersionId))
+				sf, err := p.rootfs.Open(filepath.Join(versionPath, srcVersionId))
 				if err != nil {
 					return nil, fmt.Errorf("open obj version: %w", err)
 				}
@@ -3857,24 +4362,14 @@ func (p *Posix) DeleteObject(ctx context.Context, input *s3.DeleteObjectInput) (
 					return nil, fmt.Errorf("link tmp file: %w", err)
 				}
 
-				attrs, err := p.meta.ListAttributes(versionPath, srcVersionId)
+				// the metadata storers keeping the metadata apart from
+				// the objects move the version attributes atomically
+				err = meta.MoveAttributes(p.meta, versionPath, srcVersionId, bucket, object)
 				if err != nil {
-					return nil, fmt.Errorf("list object attributes: %w", err)
+					return nil, fmt.Errorf("move object attributes: %w", err)
 				}
 
-				for _, attr := range attrs {
-					data, err := p.meta.RetrieveAttribute(nil, versionPath, srcVersionId, attr)
-					if err != nil {
-						return nil, fmt.Errorf("load %v attribute", attr)
-					}
-
-					err = p.meta.StoreAttribute(nil, bucket, object, attr, data)
-					if err != nil {
-						return nil, fmt.Errorf("store %v attribute", attr)
-					}
-				}
-
-				err = os.Remove(filepath.Join(versionPath, srcVersionId))
+				err = p.rootfs.Remove(filepath.Join(versionPath, srcVersionId))
 				if err != nil {
 					return nil, fmt.Errorf("remove obj version %w", err)
 				}
@@ -3894,7 +4389,7 @@ func (p *Posix) DeleteObject(ctx context.Context, input *s3.DeleteObjectInput) (
 
 			isDelMarker, _ := p.isObjDeleteMarker(versionPath, *input.VersionId)
 
-			err = os.Remove(filepath.Join(versionPath, *input.VersionId))
+			err = p.rootfs.Remove(filepath.Join(versionPath, *input.VersionId))
 			if errors.Is(err, syscall.ENAMETOOLONG) {
 				return nil, s3err.GetAPIError(s3err.ErrKeyTooLong)
 			}
@@ -3914,7 +4409,7 @@ func (p *Posix) DeleteObject(ctx context.Context, input *s3.DeleteObjectInput) (
 		}
 	}
 
-	fi, err := os.Stat(objpath)
+	fi, err := p.rootfs.Stat(objpath)
 	if errors.Is(err, syscall.ENAMETOOLONG) {
 		return nil, s3err.GetAPIError(s3err.ErrKeyTooLong)
 	}
@@ -3944,7 +4439,7 @@ func (p *Posix) DeleteObject(ctx context.Context, input *s3.DeleteObjectInput) (
 		return nil, err
 	}
 
-	err = os.Remove(objpath)
+	err = p.rootfs.Remove(objpath)
 	if errors.Is(err, fs.ErrNotExist) {
 		return nil, s3err.GetAPIError(s3err.ErrNoSuchKey)
 	}
@@ -4005,7 +4500,7 @@ func (p *Posix) removeParents(bucket, object string) {
 			break
 		}
 
-		err = os.Remove(filepath.Join(bucket, parent))
+		err = p.rootfs.Remove(filepath.Join(bucket, parent))
 		if err != nil {
 			break
 		}
@@ -4096,7 +4591,7 @@ func (p *Posix) GetObject(ctx context.Context, input *s3.GetObjectInput) (*s3.Ge
 	if !p.isBucketValid(bucket) {
 		return nil, s3err.GetAPIError(s3err.ErrInvalidBucketName)
 	}
-	_, err = os.Stat(bucket)
+	_, err = p.rootfs.Stat(bucket)
 	if errors.Is(err, fs.ErrNotExist) {
 		return nil, s3err.GetAPIError(s3err.ErrNoSuchBucket)
 	}
@@ -4125,7 +4620,7 @@ func (p *Posix) GetObject(ctx context.Context, input *s3.GetObjectInput) (*s3.Ge
 
 	objPath := filepath.Join(bucket, object)
 
-	fid, err := os.Stat(objPath)
+	fid, err := p.rootfs.Stat(objPath)
 	if errors.Is(err, fs.ErrNotExist) || errors.Is(err, syscall.ENOTDIR) {
 		if versionId != "" {
 			return nil, s3err.GetAPIError(s3err.ErrNoSuchVersion)
@@ -4247,7 +4742,7 @@ func (p *Posix) GetObject(ctx context.Context, input *s3.GetObjectInput) (*s3.Ge
 		versionId = string(vId)
 	}
 
-	f, err := os.Open(objPath)
+	f, err := p.rootfs.Open(objPath)
 	if errors.Is(err, fs.ErrNotExist) {
 		return nil, s3err.GetAPIError(s3err.ErrNoSuchKey)
 	}
@@ -4269,7 +4764,17 @@ func (p *Posix) GetObject(ctx context.Context, input *s3.GetObjectInput) (*s3.Ge
 		return nil, fmt.Errorf("stat object: %w", err)
 	}
 
+	compression, err := p.getCompressionInfo(f, bucket, object)
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+
 	objSize := fi.Size()
+	if compression != nil {
+		objSize = compression.Size
+	}
+
 	startOffset, length, isValid, err := backend.ParseObjectRange(objSize, *input.Range)
 	if err != nil {
 		return nil, err
@@ -4304,8 +4809,12 @@ func (p *Posix) GetObject(ctx context.Context, input *s3.GetObjectInput) (*s3.Ge
 
 	// using an os.File allows zero-copy sendfile via io.Copy(os.File, net.Conn)
 	var body io.ReadCloser = f
-	if startOffset != 0 || length != objSize {
-		rdr := io.NewSectionReader(f, startOffset, length)
+	if startOffset != 0 || length != objSize || compression != nil {
+		rdr, err := objectDataReader(f, compression, startOffset, length)
+		if err != nil {
+			f.Close()
+			return nil, err
+		}
 		body = &backend.FileSectionReadCloser{R: rdr, F: f}
 	}
 
@@ -4364,7 +4873,7 @@ func (p *Posix) HeadObject(ctx context.Context, input *s3.HeadObjectInput) (*s3.
 		return nil, s3err.GetAPIError(s3err.ErrInvalidBucketName)
 	}
 
-	_, err = os.Stat(bucket)
+	_, err = p.rootfs.Stat(bucket)
 	if errors.Is(err, fs.ErrNotExist) {
 		return nil, s3err.GetAPIError(s3err.ErrNoSuchBucket)
 	}
@@ -4393,7 +4902,7 @@ func (p *Posix) HeadObject(ctx context.Context, input *s3.HeadObjectInput) (*s3.
 
 	objPath := filepath.Join(bucket, object)
 
-	fi, err := os.Stat(objPath)
+	fi, err := p.rootfs.Stat(objPath)
 	if errors.Is(err, fs.ErrNotExist) || errors.Is(err, syscall.ENOTDIR) {
 		if versionId != "" {
 			return nil, s3err.GetAPIError(s3err.ErrNoSuchVersion)
@@ -4460,9 +4969,12 @@ func (p *Posix) HeadObject(ctx context.Context, input *s3.HeadObjectInput) (*s3.
 		return nil, err
 	}
 
-	size := fi.Size()
-	if fi.IsDir() {
-		size = 0
+	var size int64
+	if !fi.IsDir() {
+		size, err = p.objectSize(nil, bucket, object, fi)
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	startOffset, length, isValid, err := backend.ParseObjectRange(size, getString(input.Range))
@@ -4616,7 +5128,7 @@ func (p *Posix) CopyObject(ctx context.Context, input s3response.CopyObjectInput
 		return s3response.CopyObjectOutput{}, s3err.GetAPIError(s3err.ErrInvalidBucketName)
 	}
 
-	_, err = os.Stat(srcBucket)
+	_, err = p.rootfs.Stat(srcBucket)
 	if errors.Is(err, fs.ErrNotExist) {
 		return s3response.CopyObjectOutput{}, s3err.GetAPIError(s3err.ErrNoSuchBucket)
 	}
@@ -4648,7 +5160,7 @@ func (p *Posix) CopyObject(ctx context.Context, input s3response.CopyObjectInput
 		}
 	}
 
-	_, err = os.Stat(dstBucket)
+	_, err = p.rootfs.Stat(dstBucket)
 	if errors.Is(err, fs.ErrNotExist) {
 		return s3response.CopyObjectOutput{}, s3err.GetAPIError(s3err.ErrNoSuchBucket)
 	}
@@ -4656,8 +5168,13 @@ func (p *Posix) CopyObject(ctx context.Context, input s3response.CopyObjectInput
 		return s3response.CopyObjectOutput{}, fmt.Errorf("stat bucket: %w", err)
 	}
 
+	err = p.checkBucketWritable(dstBucket)
+	if err != nil {
+		return s3response.CopyObjectOutput{}, err
+	}
+
 	objPath := joinPathWithTrailer(srcBucket, srcObject)
-	f, err := os.Open(objPath)
+	f, err := p.rootfs.Open(objPath)
 	if errors.Is(err, fs.ErrNotExist) || errors.Is(err, syscall.ENOTDIR) {
 		if p.versioningEnabled() && vEnabled {
 			return s3response.CopyObjectOutput{}, s3err.GetAPIError(s3err.ErrNoSuchVersion)
@@ -4731,13 +5248,26 @@ func (p *Posix) CopyObject(ctx context.Context, input s3response.CopyObjectInput
 			// If a different checksum algorith is specified
 			// first caclculate and store the checksum
 			if checksums.Algorithm != input.ChecksumAlgorithm {
-				f, err := os.Open(dstObjdPath)
+				f, err := p.rootfs.Open(dstObjdPath)
 				if err != nil {
 					return s3response.CopyObjectOutput{}, fmt.Errorf("open obj file: %w", err)
 				}
 				defer f.Close()
 
-				hashReader, err := utils.NewHashReader(f, "", utils.HashType(strings.ToLower(string(input.ChecksumAlgorithm))))
+				compression, err := p.getCompressionInfo(f, dstBucket, dstObject)
+				if err != nil {
+					return s3response.CopyObjectOutput{}, err
+				}
+
+				var rdr io.Reader = f
+				if compression != nil {
+					rdr, err = newDecompressReader(f, *compression, 0, compression.Size)
+					if err != nil {
+						return s3response.CopyObjectOutput{}, err
+					}
+				}
+
+				hashReader, err := utils.NewHashReader(rdr, "", utils.HashType(strings.ToLower(string(input.ChecksumAlgorithm))))
 				if err != nil {
 					return s3response.CopyObjectOutput{}, fmt.Errorf("initialize hash reader: %w", err)
 				}
@@ -4814,12 +5344,29 @@ func (p *Posix) CopyObject(ctx context.Context, input s3response.CopyObjectInput
 			}
 		}
 	} else {
+		compression, err := p.getCompressionInfo(f, srcBucket, srcObject)
+		if err != nil {
+			return s3response.CopyObjectOutput{}, err
+		}
+
+		// the source object file is passed as is to clone the data, the
+		// compressed objects are decompressed and compressed again based
+		// on the destination bucket
 		contentLength := fi.Size()
+		var body io.Reader = f
+		if compression != nil {
+			contentLength = compression.Size
+			body, err = newDecompressReader(f, *compression, 0, compression.Size)
+			if err != nil {
+				return s3response.CopyObjectOutput{}, err
+			}
+		}
 
 		checksums, err := p.retrieveChecksums(f, srcBucket, srcObject)
 		if err != nil && !errors.Is(err, meta.ErrNoSuchKey) {
 			return s3response.CopyObjectOutput{}, fmt.Errorf("get obj checksum: %w", err)
 		}
+		srcChecksums := checksums
 
 		// If any checksum algorithm is provided, replace, otherwise
 		// use the existing one
@@ -4830,7 +5377,7 @@ func (p *Posix) CopyObject(ctx context.Context, input s3response.CopyObjectInput
 		putObjectInput := s3response.PutObjectInput{
 			Bucket:                    &dstBucket,
 			Key:                       &dstObject,
-			Body:                      f,
+			Body:                      body,
 			ContentLength:             &contentLength,
 			ChecksumAlgorithm:         checksums.Algorithm,
 			ContentType:               input.ContentType,
@@ -4862,7 +5409,7 @@ func (p *Posix) CopyObject(ctx context.Context, input s3response.CopyObjectInput
 			putObjectInput.Tagging = input.Tagging
 		}
 
-		res, err := p.PutObject(withCtxNoSlot(ctx), putObjectInput)
+		res, err := p.PutObject(withCtxCopySource(withCtxNoSlot(ctx), srcEtag, srcChecksums), putObjectInput)
 		if err != nil {
 			return s3response.CopyObjectOutput{}, err
 		}
@@ -4892,7 +5439,7 @@ func (p *Posix) CopyObject(ctx context.Context, input s3response.CopyObjectInput
 		chType = res.ChecksumType
 	}
 
-	fi, err = os.Stat(dstObjdPath)
+	fi, err = p.rootfs.Stat(dstObjdPath)
 	if err != nil {
 		return s3response.CopyObjectOutput{}, fmt.Errorf("stat dst object: %w", err)
 	}
@@ -4946,7 +5493,7 @@ func (p *Posix) ListObjectsParametrized(ctx context.Context, input *s3.ListObjec
 		return s3response.ListObjectsResult{}, s3err.GetAPIError(s3err.ErrInvalidBucketName)
 	}
 
-	_, err := os.Stat(bucket)
+	_, err := p.rootfs.Stat(bucket)
 	if errors.Is(err, fs.ErrNotExist) {
 		return s3response.ListObjectsResult{}, s3err.GetAPIError(s3err.ErrNoSuchBucket)
 	}
@@ -4954,9 +5501,8 @@ func (p *Posix) ListObjectsParametrized(ctx context.Context, input *s3.ListObjec
 		return s3response.ListObjectsResult{}, fmt.Errorf("stat bucket: %w", err)
 	}
 
-	fileSystem := os.DirFS(bucket)
-	results, err := backend.Walk(ctx, fileSystem, prefix, delim, marker, maxkeys,
-		customFileToObj(bucket, true), []string{MetaTmpDir})
+	results, err := p.walkObjects(ctx, bucket, prefix, delim, marker, maxkeys,
+		customFileToObj(bucket, true))
 	if err != nil {
 		return s3response.ListObjectsResult{}, fmt.Errorf("walk %v: %w", bucket, err)
 	}
@@ -4981,6 +5527,8 @@ func (p *Posix) FileToObj(bucket string, fetchOwner bool) backend.GetObjFunc {
 		}
 	}
 
+	compressed := p.isBucketCompressed(bucket)
+
 	return func(path string, d fs.DirEntry) (s3response.Object, error) {
 		var owner *types.Owner
 		// Retrieve the object owner data from bucket ACL, if fetchOwner is true
@@ -5066,7 +5614,13 @@ func (p *Posix) FileToObj(bucket string, fetchOwner bool) backend.GetObjFunc {
 			return s3response.Object{}, fmt.Errorf("get fileinfo: %w", err)
 		}
 
-		size := fi.Size()
+		size, err := p.listedObjectSize(compressed, bucket, path, fi)
+		if errors.Is(err, fs.ErrNotExist) {
+			return s3response.Object{}, backend.ErrSkipObj
+		}
+		if err != nil {
+			return s3response.Object{}, err
+		}
 		mtime := fi.ModTime()
 
 		return s3response.Object{
@@ -5123,7 +5677,7 @@ func (p *Posix) ListObjectsV2Parametrized(ctx context.Context, input *s3.ListObj
 		return s3response.ListObjectsV2Result{}, s3err.GetAPIError(s3err.ErrInvalidBucketName)
 	}
 
-	_, err := os.Stat(bucket)
+	_, err := p.rootfs.Stat(bucket)
 	if errors.Is(err, fs.ErrNotExist) {
 		return s3response.ListObjectsV2Result{}, s3err.GetAPIError(s3err.ErrNoSuchBucket)
 	}
@@ -5131,9 +5685,8 @@ func (p *Posix) ListObjectsV2Parametrized(ctx context.Context, input *s3.ListObj
 		return s3response.ListObjectsV2Result{}, fmt.Errorf("stat bucket: %w", err)
 	}
 
-	fileSystem := os.DirFS(bucket)
-	results, err := backend.Walk(ctx, fileSystem, prefix, delim, marker, maxkeys,
-		customFileToObj(bucket, fetchOwner), []string{MetaTmpDir})
+	results, err := p.walkObjects(ctx, bucket, prefix, delim, marker, maxkeys,
+		customFileToObj(bucket, fetchOwner))
 	if err != nil {
 		return s3response.ListObjectsV2Result{}, fmt.Errorf("walk %v: %w", bucket, err)
 	}
@@ -5165,7 +5718,7 @@ func (p *Posix) PutBucketAcl(ctx context.Context, bucket string, data []byte) er
 	if !p.isBucketValid(bucket) {
 		return s3err.GetAPIError(s3err.ErrInvalidBucketName)
 	}
-	_, err = os.Stat(bucket)
+	_, err = p.rootfs.Stat(bucket)
 	if errors.Is(err, fs.ErrNotExist) {
 		return s3err.GetAPIError(s3err.ErrNoSuchBucket)
 	}
@@ -5191,7 +5744,7 @@ func (p *Posix) GetBucketAcl(ctx context.Context, input *s3.GetBucketAclInput) (
 	if !p.isBucketValid(*input.Bucket) {
 		return nil, s3err.GetAPIError(s3err.ErrInvalidBucketName)
 	}
-	_, err = os.Stat(*input.Bucket)
+	_, err = p.rootfs.Stat(*input.Bucket)
 	if errors.Is(err, fs.ErrNotExist) {
 		return nil, s3err.GetAPIError(s3err.ErrNoSuchBucket)
 	}
@@ -5219,7 +5772,7 @@ func (p *Posix) PutBucketTagging(ctx context.Context, bucket string, tags map[st
 	if !p.isBucketValid(bucket) {
 		return s3err.GetAPIError(s3err.ErrInvalidBucketName)
 	}
-	_, err = os.Stat(bucket)
+	_, err = p.rootfs.Stat(bucket)
 	if errors.Is(err, fs.ErrNotExist) {
 		return s3err.GetAPIError(s3err.ErrNoSuchBucket)
 	}
@@ -5236,6 +5789,10 @@ func (p *Posix) PutBucketTagging(ctx context.Context, bucket string, tags map[st
 		return nil
 	}
 
+	if algo, ok := tags[compressionTagKey]; ok && !isValidCompressionTag(algo) {
+		return s3err.GetAPIError(s3err.ErrInvalidTagValue)
+	}
+
 	b, err := json.Marshal(tags)
 	if err != nil {
 		return fmt.Errorf("marshal tags: %w", err)
@@ -5246,7 +5803,7 @@ func (p *Posix) PutBucketTagging(ctx context.Context, bucket string, tags map[st
 		return fmt.Errorf("set tags: %w", err)
 	}
 
-	return nil
+	return p.markBucketCompressed(bucket, tags)
 }
 
 func (p *Posix) GetBucketTagging(ctx context.Context, bucket string) (map[string]string, error) {
@@ -5259,7 +5816,7 @@ func (p *Posix) GetBucketTagging(ctx context.Context, bucket string) (map[string
 	if !p.isBucketValid(bucket) {
 		return nil, s3err.GetAPIError(s3err.ErrInvalidBucketName)
 	}
-	_, err = os.Stat(bucket)
+	_, err = p.rootfs.Stat(bucket)
 	if errors.Is(err, fs.ErrNotExist) {
 		return nil, s3err.GetAPIError(s3err.ErrNoSuchBucket)
 	}
@@ -5292,7 +5849,7 @@ func (p *Posix) GetObjectTagging(ctx context.Context, bucket, object, versionId
 	if !p.isBucketValid(bucket) {
 		return nil, s3err.GetAPIError(s3err.ErrInvalidBucketName)
 	}
-	_, err = os.Stat(bucket)
+	_, err = p.rootfs.Stat(bucket)
 	if errors.Is(err, fs.ErrNotExist) {
 		return nil, s3err.GetAPIError(s3err.ErrNoSuchBucket)
 	}
@@ -5365,7 +5922,7 @@ func (p *Posix) PutObjectTagging(ctx context.Context, bucket, object, versionId
 	if !p.isBucketValid(bucket) {
 		return s3err.GetAPIError(s3err.ErrInvalidBucketName)
 	}
-	_, err = os.Stat(bucket)
+	_, err = p.rootfs.Stat(bucket)
 	if errors.Is(err, fs.ErrNotExist) {
 		return s3err.GetAPIError(s3err.ErrNoSuchBucket)
 	}
@@ -5373,6 +5930,11 @@ func (p *Posix) PutObjectTagging(ctx context.Context, bucket, object, versionId
 		return fmt.Errorf("stat bucket: %w", err)
 	}
 
+	err = p.checkBucketWritable(bucket)
+	if err != nil {
+		return err
+	}
+
 	if versionId != "" {
 		if !p.versioningEnabled() {
 			//TODO: Maybe we need to return our custom error here?
@@ -5450,7 +6012,7 @@ func (p *Posix) PutBucketPolicy(ctx context.Context, bucket string, policy []byt
 	if !p.isBucketValid(bucket) {
 		return s3err.GetAPIError(s3err.ErrInvalidBucketName)
 	}
-	_, err = os.Stat(bucket)
+	_, err = p.rootfs.Stat(bucket)
 	if errors.Is(err, fs.ErrNotExist) {
 		return s3err.GetAPIError(s3err.ErrNoSuchBucket)
 	}
@@ -5489,7 +6051,7 @@ func (p *Posix) GetBucketPolicy(ctx context.Context, bucket string) ([]byte, err
 	if !p.isBucketValid(bucket) {
 		return nil, s3err.GetAPIError(s3err.ErrInvalidBucketName)
 	}
-	_, err = os.Stat(bucket)
+	_, err = p.rootfs.Stat(bucket)
 	if errors.Is(err, fs.ErrNotExist) {
 		return nil, s3err.GetAPIError(s3err.ErrNoSuchBucket)
 	}
@@ -5528,7 +6090,7 @@ func (p *Posix) PutBucketCors(ctx context.Context, bucket string, cors []byte) e
 	if !p.isBucketValid(bucket) {
 		return s3err.GetAPIError(s3err.ErrInvalidBucketName)
 	}
-	_, err = os.Stat(bucket)
+	_, err = p.rootfs.Stat(bucket)
 	if errors.Is(err, fs.ErrNotExist) {
 		return s3err.GetAPIError(s3err.ErrNoSuchBucket)
 	}
@@ -5563,7 +6125,7 @@ func (p *Posix) GetBucketCors(ctx context.Context, bucket string) ([]byte, error
 	if !p.isBucketValid(bucket) {
 		return nil, s3err.GetAPIError(s3err.ErrInvalidBucketName)
 	}
-	_, err = os.Stat(bucket)
+	_, err = p.rootfs.Stat(bucket)
 	if errors.Is(err, fs.ErrNotExist) {
 		return nil, s3err.GetAPIError(s3err.ErrNoSuchBucket)
 	}
@@ -5589,6 +6151,77 @@ func (p *Posix) DeleteBucketCors(ctx context.Context, bucket string) error {
 	return p.PutBucketCors(ctx, bucket, nil)
 }
 
+func (p *Posix) PutPublicAccessBlock(ctx context.Context, bucket string, config []byte) error {
+	release, err := p.acquireActionSlot(ctx)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	if !p.isBucketValid(bucket) {
+		return s3err.GetAPIError(s3err.ErrInvalidBucketName)
+	}
+	_, err = p.rootfs.Stat(bucket)
+	if errors.Is(err, fs.ErrNotExist) {
+		return s3err.GetAPIError(s3err.ErrNoSuchBucket)
+	}
+	if err != nil {
+		return fmt.Errorf("stat bucket: %w", err)
+	}
+
+	if config == nil {
+		err = p.meta.DeleteAttribute(bucket, "", accessBlockKey)
+		if err != nil && !errors.Is(err, meta.ErrNoSuchKey) {
+			return fmt.Errorf("remove public access block: %w", err)
+		}
+
+		return nil
+	}
+
+	err = p.meta.StoreAttribute(nil, bucket, "", accessBlockKey, config)
+	if err != nil {
+		return fmt.Errorf("set public access block: %w", err)
+	}
+
+	return nil
+}
+
+func (p *Posix) GetPublicAccessBlock(ctx context.Context, bucket string) ([]byte, error) {
+	release, err := p.acquireActionSlot(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	if !p.isBucketValid(bucket) {
+		return nil, s3err.GetAPIError(s3err.ErrInvalidBucketName)
+	}
+	_, err = p.rootfs.Stat(bucket)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, s3err.GetAPIError(s3err.ErrNoSuchBucket)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("stat bucket: %w", err)
+	}
+
+	config, err := p.meta.RetrieveAttribute(nil, bucket, "", accessBlockKey)
+	if errors.Is(err, meta.ErrNoSuchKey) {
+		return nil, s3err.GetAPIError(s3err.ErrNoSuchPublicAccessBlockConfiguration)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return config, nil
+}
+
+func (p *Posix) DeletePublicAccessBlock(ctx context.Context, bucket string) error {
+	if !p.isBucketValid(bucket) {
+		return s3err.GetAPIError(s3err.ErrInvalidBucketName)
+	}
+	return p.PutPublicAccessBlock(ctx, bucket, nil)
+}
+
 func (p *Posix) isBucketObjectLockEnabled(bucket string) error {
 	cfg, err := p.meta.RetrieveAttribute(nil, bucket, "", bucketLockKey)
 	if errors.Is(err, fs.ErrNotExist) {
@@ -5623,7 +6256,7 @@ func (p *Posix) PutObjectLockConfiguration(ctx context.Context, bucket string, c
 	if !p.isBucketValid(bucket) {
 		return s3err.GetAPIError(s3err.ErrInvalidBucketName)
 	}
-	_, err = os.Stat(bucket)
+	_, err = p.rootfs.Stat(bucket)
 	if errors.Is(err, fs.ErrNotExist) {
 		return s3err.GetAPIError(s3err.ErrNoSuchBucket)
 	}
@@ -5666,7 +6299,7 @@ func (p *Posix) GetObjectLockConfiguration(ctx context.Context, bucket string) (
 	if !p.isBucketValid(bucket) {
 		return nil, s3err.GetAPIError(s3err.ErrInvalidBucketName)
 	}
-	_, err = os.Stat(bucket)
+	_, err = p.rootfs.Stat(bucket)
 	if errors.Is(err, fs.ErrNotExist) {
 		return nil, s3err.GetAPIError(s3err.ErrNoSuchBucket)
 	}
@@ -5699,6 +6332,10 @@ func (p *Posix) PutObjectLegalHold(ctx context.Context, bucket, object, versionI
 	if err != nil {
 		return err
 	}
+	err = p.checkBucketWritable(bucket)
+	if err != nil {
+		return err
+	}
 	err = p.isBucketObjectLockEnabled(bucket)
 	if err != nil {
 		return err
@@ -5825,6 +6462,10 @@ func (p *Posix) PutObjectRetention(ctx context.Context, bucket, object, versionI
 	if err != nil {
 		return err
 	}
+	err = p.checkBucketWritable(bucket)
+	if err != nil {
+		return err
+	}
 	err = p.isBucketObjectLockEnabled(bucket)
 	if err != nil {
 		return err
@@ -5935,8 +6576,8 @@ func (p *Posix) ChangeBucketOwner(ctx context.Context, bucket, owner string) err
 	return auth.UpdateBucketACLOwner(ctx, p, bucket, owner)
 }
 
-func listBucketFileInfos(bucketlinks bool) ([]fs.FileInfo, error) {
-	entries, err := os.ReadDir(".")
+func listBucketFileInfos(rootfs *RootFS, bucketlinks bool) ([]fs.FileInfo, error) {
+	entries, err := rootfs.ReadDir(".")
 	if err != nil {
 		return nil, fmt.Errorf("readdir buckets: %w", err)
 	}
@@ -5949,7 +6590,7 @@ func listBucketFileInfos(bucketlinks bool) ([]fs.FileInfo, error) {
 		}
 
 		if bucketlinks && entry.Type() == fs.ModeSymlink {
-			fi, err = os.Stat(entry.Name())
+			fi, err = rootfs.Stat(entry.Name())
 			if err != nil {
 				// skip entries returning errors
 				continue
@@ -5974,7 +6615,7 @@ func (p *Posix) ListBucketsAndOwners(ctx context.Context) (buckets []s3response.
 	}
 	defer release()
 
-	fis, err := listBucketFileInfos(p.bucketlinks)
+	fis, err := listBucketFileInfos(p.rootfs, p.bucketlinks)
 	if err != nil {
 		return buckets, fmt.Errorf("listBucketFileInfos: %w", err)
 	}
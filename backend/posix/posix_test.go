@@ -0,0 +1,143 @@
+// Copyright 2026 Versity Software
+// This file is licensed under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package posix
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/versity/versitygw/backend/meta"
+	"github.com/versity/versitygw/s3response"
+)
+
+// newTestPosix returns a posix backend with a new root directory and
+// a key-value metadata database, shut down when the test completes
+func newTestPosix(t *testing.T, opts PosixOpts) *Posix {
+	t.Helper()
+
+	kv, err := meta.NewKV(filepath.Join(t.TempDir(), "meta.db"))
+	if err != nil {
+		t.Fatalf("open kv metadata: %v", err)
+	}
+	if opts.NewDirPerm == 0 {
+		opts.NewDirPerm = 0o755
+	}
+
+	p, err := New(t.TempDir(), kv, opts)
+	if err != nil {
+		kv.Close()
+		t.Fatalf("new posix: %v", err)
+	}
+	t.Cleanup(p.Shutdown)
+	return p
+}
+
+func createTestBucket(t *testing.T, p *Posix, bucket string) {
+	t.Helper()
+
+	err := p.CreateBucket(context.Background(), &s3.CreateBucketInput{
+		Bucket:                    &bucket,
+		CreateBucketConfiguration: &types.CreateBucketConfiguration{},
+	}, []byte("{}"))
+	if err != nil {
+		t.Fatalf("create bucket %v: %v", bucket, err)
+	}
+}
+
+func putTestObject(t *testing.T, p *Posix, bucket, key, data string) {
+	t.Helper()
+
+	_, err := putObject(p, bucket, key, data)
+	if err != nil {
+		t.Fatalf("put object %v: %v", key, err)
+	}
+}
+
+func putObject(p *Posix, bucket, key, data string) (s3response.PutObjectOutput, error) {
+	size := int64(len(data))
+	return p.PutObject(context.Background(), s3response.PutObjectInput{
+		Bucket:        &bucket,
+		Key:           &key,
+		ContentLength: &size,
+		Body:          strings.NewReader(data),
+	})
+}
+
+func getTestObject(t *testing.T, p *Posix, bucket, key string) string {
+	t.Helper()
+
+	rng := ""
+	out, err := p.GetObject(context.Background(), &s3.GetObjectInput{
+		Bucket: &bucket,
+		Key:    &key,
+		Range:  &rng,
+	})
+	if err != nil {
+		t.Fatalf("get object %v: %v", key, err)
+	}
+	defer out.Body.Close()
+
+	data, err := io.ReadAll(out.Body)
+	if err != nil {
+		t.Fatalf("read object %v: %v", key, err)
+	}
+	return string(data)
+}
+
+func TestObjectVersionPath(t *testing.T) {
+	p := newTestPosix(t, PosixOpts{VersioningDir: t.TempDir()})
+	createTestBucket(t, p, "bucket")
+	err := p.PutBucketVersioning(context.Background(), "bucket", types.BucketVersioningStatusEnabled)
+	if err != nil {
+		t.Fatalf("enable versioning: %v", err)
+	}
+
+	first, err := putObject(p, "bucket", "obj", "first")
+	if err != nil {
+		t.Fatalf("put object: %v", err)
+	}
+	second, err := putObject(p, "bucket", "obj", "second")
+	if err != nil {
+		t.Fatalf("put object: %v", err)
+	}
+
+	tests := []struct {
+		versionId string
+		data      string
+	}{
+		{"", "second"},
+		{second.VersionID, "second"},
+		{first.VersionID, "first"},
+	}
+	for _, tt := range tests {
+		path, err := p.ObjectVersionPath("bucket", "obj", tt.versionId)
+		if err != nil {
+			t.Fatalf("version %q path: %v", tt.versionId, err)
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatalf("read version %q: %v", tt.versionId, err)
+		}
+		if string(data) != tt.data {
+			t.Errorf("version %q: expected %q, got %q", tt.versionId, tt.data, data)
+		}
+	}
+}
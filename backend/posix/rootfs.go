@@ -0,0 +1,342 @@
+// Copyright 2025 Versity Software
+// This file is licensed under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package posix
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/versity/versitygw/backend"
+	"github.com/versity/versitygw/s3err"
+)
+
+// RootFS resolves the bucket and object paths relative to the gateway
+// root directory instead of the process working directory, so multiple
+// backends with different root directories can be used in one process.
+//
+// The relative paths are resolved beneath the root directory, and can't
+// escape it with ".." or symlinks. When bucket links are enabled, the
+// bucket level symlinks may point outside of the root directory, and the
+// object paths of these buckets are resolved beneath the linked bucket
+// directory instead. Absolute paths (e.g. the versioning directory paths)
+// are used as is.
+type RootFS struct {
+	root        *os.Root
+	dir         string
+	bucketlinks bool
+}
+
+// NewRootFS opens the root directory for the path resolution.
+func NewRootFS(dir string, bucketlinks bool) (*RootFS, error) {
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, fmt.Errorf("get absolute path of %v: %w", dir, err)
+	}
+
+	root, err := os.OpenRoot(absDir)
+	if err != nil {
+		return nil, fmt.Errorf("open %v: %w", dir, err)
+	}
+
+	return &RootFS{
+		root:        root,
+		dir:         absDir,
+		bucketlinks: bucketlinks,
+	}, nil
+}
+
+// Close closes the root directory.
+func (r *RootFS) Close() error {
+	return r.root.Close()
+}
+
+// Dir returns the absolute root directory path.
+func (r *RootFS) Dir() string {
+	return r.dir
+}
+
+// Path returns the filesystem path of the joined path elements, for the
+// operations that can't be done relative to the root directory. These
+// paths are not protected against symlinks escaping the root directory,
+// use CheckPath to validate the paths first.
+func (r *RootFS) Path(elem ...string) string {
+	name := filepath.Join(elem...)
+	if filepath.IsAbs(name) {
+		return name
+	}
+	return filepath.Join(r.dir, name)
+}
+
+// resolve returns the root and the path relative to the root the name is
+// resolved beneath. The returned release func must be called when the
+// root is no longer used.
+func (r *RootFS) resolve(name string) (*os.Root, string, func(), error) {
+	noop := func() {}
+	if !r.bucketlinks {
+		return r.root, name, noop, nil
+	}
+
+	bucket, object, _ := strings.Cut(filepath.Clean(name), string(filepath.Separator))
+	if object == "" {
+		// the bucket itself is resolved in the root, so the bucket
+		// link can be removed without following it
+		return r.root, name, noop, nil
+	}
+
+	fi, err := r.root.Lstat(bucket)
+	if err != nil || fi.Mode()&fs.ModeSymlink == 0 {
+		return r.root, name, noop, nil
+	}
+
+	broot, err := os.OpenRoot(filepath.Join(r.dir, bucket))
+	if err != nil {
+		return nil, "", noop, err
+	}
+	return broot, object, func() { broot.Close() }, nil
+}
+
+// isBucketLink reports if the name is a bucket symlink that
+// has to be followed outside of the root directory
+func (r *RootFS) isBucketLink(name string) bool {
+	if !r.bucketlinks || strings.ContainsRune(filepath.Clean(name), filepath.Separator) {
+		return false
+	}
+	fi, err := r.root.Lstat(name)
+	return err == nil && fi.Mode()&fs.ModeSymlink != 0
+}
+
+// Stat returns the file info of the named file, following symlinks.
+func (r *RootFS) Stat(name string) (fs.FileInfo, error) {
+	if filepath.IsAbs(name) || r.isBucketLink(name) {
+		return os.Stat(r.Path(name))
+	}
+	root, rel, release, err := r.resolve(name)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	return root.Stat(rel)
+}
+
+// Lstat returns the file info of the named file, without following
+// the symlink of the last path element.
+func (r *RootFS) Lstat(name string) (fs.FileInfo, error) {
+	if filepath.IsAbs(name) {
+		return os.Lstat(name)
+	}
+	root, rel, release, err := r.resolve(name)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	return root.Lstat(rel)
+}
+
+// Open opens the named file for reading.
+func (r *RootFS) Open(name string) (*os.File, error) {
+	if filepath.IsAbs(name) || r.isBucketLink(name) {
+		return os.Open(r.Path(name))
+	}
+	root, rel, release, err := r.resolve(name)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	return root.Open(rel)
+}
+
+// ReadDir reads the named directory, and returns the directory
+// entries sorted by file name like os.ReadDir.
+func (r *RootFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	f, err := r.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	ents, err := f.ReadDir(-1)
+	slices.SortFunc(ents, func(a, b fs.DirEntry) int {
+		return strings.Compare(a.Name(), b.Name())
+	})
+	return ents, err
+}
+
+// Mkdir creates the named directory.
+func (r *RootFS) Mkdir(name string, perm fs.FileMode) error {
+	if filepath.IsAbs(name) {
+		return os.Mkdir(name, perm)
+	}
+	root, rel, release, err := r.resolve(name)
+	if err != nil {
+		return err
+	}
+	defer release()
+	return root.Mkdir(rel, perm)
+}
+
+// MkdirAll creates the named directory along with any missing parents,
+// and optionally sets the ownership of the created directories.
+func (r *RootFS) MkdirAll(name string, uid, gid int, doChown bool, perm fs.FileMode) error {
+	if filepath.IsAbs(name) || r.isBucketLink(name) {
+		return backend.MkdirAll(r.Path(name), uid, gid, doChown, perm)
+	}
+	root, rel, release, err := r.resolve(name)
+	if err != nil {
+		return err
+	}
+	defer release()
+	return mkdirAllRoot(root, rel, uid, gid, doChown, perm)
+}
+
+// mkdirAllRoot is backend.MkdirAll with the directories
+// created and checked beneath the root
+func mkdirAllRoot(root *os.Root, path string, uid, gid int, doChown bool, perm fs.FileMode) error {
+	dir, err := root.Stat(path)
+	if err == nil {
+		if dir.IsDir() {
+			return nil
+		}
+		return s3err.GetAPIError(s3err.ErrObjectParentIsFile)
+	}
+
+	parent := filepath.Dir(filepath.Clean(path))
+	if parent != "." && parent != string(filepath.Separator) {
+		err = mkdirAllRoot(root, parent, uid, gid, doChown, perm)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = root.Mkdir(path, perm)
+	if err != nil {
+		// the directory may have been created concurrently
+		dir, err1 := root.Lstat(path)
+		if err1 == nil && dir.IsDir() {
+			return nil
+		}
+		return err
+	}
+	if doChown {
+		return root.Chown(path, uid, gid)
+	}
+	return nil
+}
+
+// Chown changes the ownership of the named file.
+func (r *RootFS) Chown(name string, uid, gid int) error {
+	if filepath.IsAbs(name) || r.isBucketLink(name) {
+		return os.Chown(r.Path(name), uid, gid)
+	}
+	root, rel, release, err := r.resolve(name)
+	if err != nil {
+		return err
+	}
+	defer release()
+	return root.Chown(rel, uid, gid)
+}
+
+// Remove removes the named file or empty directory.
+func (r *RootFS) Remove(name string) error {
+	if filepath.IsAbs(name) {
+		return os.Remove(name)
+	}
+	root, rel, release, err := r.resolve(name)
+	if err != nil {
+		return err
+	}
+	defer release()
+	return root.Remove(rel)
+}
+
+// RemoveAll removes the named file or directory and any children.
+func (r *RootFS) RemoveAll(name string) error {
+	if filepath.IsAbs(name) {
+		return os.RemoveAll(name)
+	}
+	root, rel, release, err := r.resolve(name)
+	if err != nil {
+		return err
+	}
+	defer release()
+	return root.RemoveAll(rel)
+}
+
+// Rename renames the named file beneath the root directory. The paths
+// that resolve beneath different directories (e.g. an absolute path and
+// a path relative to the root, or paths in different bucket links) are
+// renamed with their filesystem paths after validating them with
+// CheckPath.
+func (r *RootFS) Rename(oldname, newname string) error {
+	if filepath.IsAbs(oldname) && filepath.IsAbs(newname) {
+		return os.Rename(oldname, newname)
+	}
+
+	if !filepath.IsAbs(oldname) && !filepath.IsAbs(newname) {
+		oldbucket, _, _ := strings.Cut(filepath.Clean(oldname), string(filepath.Separator))
+		newbucket, newrel, _ := strings.Cut(filepath.Clean(newname), string(filepath.Separator))
+		root, oldrel, release, err := r.resolve(oldname)
+		if err != nil {
+			return err
+		}
+		defer release()
+
+		switch {
+		case root == r.root && (newrel == "" || !r.isBucketLink(newbucket)):
+			return root.Rename(oldrel, newname)
+		case root != r.root && oldbucket == newbucket && newrel != "":
+			return root.Rename(oldrel, newrel)
+		}
+	}
+
+	err := r.CheckPath(oldname)
+	if err != nil {
+		return err
+	}
+	err = r.CheckPath(newname)
+	if err != nil {
+		return err
+	}
+	return os.Rename(r.Path(oldname), r.Path(newname))
+}
+
+// CheckPath validates that the existing parent directories of the named
+// file don't escape the root directory, before the file is accessed with
+// its filesystem path.
+func (r *RootFS) CheckPath(name string) error {
+	if filepath.IsAbs(name) {
+		return nil
+	}
+	root, rel, release, err := r.resolve(name)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	for dir := rel; dir != "." && dir != string(filepath.Separator); dir = filepath.Dir(dir) {
+		_, err := root.Lstat(dir)
+		if err == nil {
+			return nil
+		}
+		if !errors.Is(err, fs.ErrNotExist) && !errors.Is(err, fs.ErrInvalid) {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,179 @@
+// Copyright 2026 Versity Software
+// This file is licensed under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package posix
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/versity/versitygw/s3err"
+)
+
+// newTestRootFS returns the root filesystem of a new gateway root with
+// a bucket containing an object and a symlink to a directory outside of
+// the root, and the outside directory containing a secret file.
+func newTestRootFS(t *testing.T, bucketlinks bool) (*RootFS, string) {
+	t.Helper()
+
+	root := t.TempDir()
+	outside := t.TempDir()
+
+	err := os.Mkdir(filepath.Join(root, "bucket"), 0o755)
+	if err != nil {
+		t.Fatalf("create bucket: %v", err)
+	}
+	err = os.WriteFile(filepath.Join(root, "bucket", "obj"), []byte("data"), 0o644)
+	if err != nil {
+		t.Fatalf("create object: %v", err)
+	}
+	err = os.WriteFile(filepath.Join(outside, "secret"), []byte("secret"), 0o644)
+	if err != nil {
+		t.Fatalf("create outside file: %v", err)
+	}
+	err = os.Symlink(outside, filepath.Join(root, "bucket", "escape"))
+	if err != nil {
+		t.Fatalf("create symlink: %v", err)
+	}
+
+	rootfs, err := NewRootFS(root, bucketlinks)
+	if err != nil {
+		t.Fatalf("open root: %v", err)
+	}
+	t.Cleanup(func() { rootfs.Close() })
+	return rootfs, outside
+}
+
+func TestRootFS_SymlinkEscape(t *testing.T) {
+	rootfs, outside := newTestRootFS(t, false)
+
+	if f, err := rootfs.Open("bucket/escape/secret"); err == nil {
+		f.Close()
+		t.Errorf("expected open through the escaping symlink to fail")
+	}
+	if _, err := rootfs.Stat("bucket/escape/secret"); err == nil {
+		t.Errorf("expected stat through the escaping symlink to fail")
+	}
+
+	err := rootfs.MkdirAll("bucket/escape/dir/sub", 0, 0, false, 0o755)
+	if err == nil {
+		t.Errorf("expected mkdir through the escaping symlink to fail")
+	}
+	if _, err := os.Stat(filepath.Join(outside, "dir")); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("expected no directory outside of the root, got %v", err)
+	}
+
+	err = rootfs.Rename("bucket/obj", "bucket/escape/obj")
+	if err == nil {
+		t.Errorf("expected rename into the escaping symlink to fail")
+	}
+	if _, err := os.Stat(filepath.Join(outside, "obj")); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("expected no object outside of the root, got %v", err)
+	}
+
+	err = rootfs.Rename("bucket/escape/secret", "bucket/secret")
+	if err == nil {
+		t.Errorf("expected rename from the escaping symlink to fail")
+	}
+	if _, err := os.Stat(filepath.Join(outside, "secret")); err != nil {
+		t.Errorf("expected the outside file to be kept, got %v", err)
+	}
+
+	if err := rootfs.RemoveAll("bucket/escape/secret"); err == nil {
+		t.Errorf("expected remove through the escaping symlink to fail")
+	}
+}
+
+func TestRootFS_MkdirAllRename(t *testing.T) {
+	rootfs, _ := newTestRootFS(t, false)
+
+	err := rootfs.MkdirAll("bucket/a/b/c", 0, 0, false, 0o755)
+	if err != nil {
+		t.Fatalf("mkdir all: %v", err)
+	}
+	if fi, err := rootfs.Stat("bucket/a/b/c"); err != nil || !fi.IsDir() {
+		t.Fatalf("expected directory bucket/a/b/c, got %v", err)
+	}
+	// existing directories are not an error
+	err = rootfs.MkdirAll("bucket/a/b", 0, 0, false, 0o755)
+	if err != nil {
+		t.Errorf("mkdir all of existing directory: %v", err)
+	}
+
+	err = rootfs.MkdirAll("bucket/obj/dir", 0, 0, false, 0o755)
+	if !errors.Is(err, s3err.GetAPIError(s3err.ErrObjectParentIsFile)) {
+		t.Errorf("expected ErrObjectParentIsFile, got %v", err)
+	}
+
+	err = rootfs.Rename("bucket/obj", "bucket/a/b/c/obj")
+	if err != nil {
+		t.Fatalf("rename: %v", err)
+	}
+	if _, err := rootfs.Stat("bucket/a/b/c/obj"); err != nil {
+		t.Errorf("expected the renamed object, got %v", err)
+	}
+	if _, err := rootfs.Stat("bucket/obj"); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("expected the old object to be removed, got %v", err)
+	}
+}
+
+func TestRootFS_BucketLinks(t *testing.T) {
+	rootfs, outside := newTestRootFS(t, true)
+
+	linked := t.TempDir()
+	err := os.Symlink(linked, filepath.Join(rootfs.Dir(), "linked"))
+	if err != nil {
+		t.Fatalf("create bucket link: %v", err)
+	}
+	err = os.Symlink(outside, filepath.Join(linked, "escape"))
+	if err != nil {
+		t.Fatalf("create symlink: %v", err)
+	}
+
+	// the bucket links are followed
+	err = rootfs.MkdirAll("linked/dir", 0, 0, false, 0o755)
+	if err != nil {
+		t.Fatalf("mkdir all in linked bucket: %v", err)
+	}
+	err = os.WriteFile(filepath.Join(linked, "obj"), nil, 0o644)
+	if err != nil {
+		t.Fatalf("create object: %v", err)
+	}
+	err = rootfs.Rename("linked/obj", "linked/dir/obj")
+	if err != nil {
+		t.Fatalf("rename in linked bucket: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(linked, "dir", "obj")); err != nil {
+		t.Errorf("expected the renamed object in the linked bucket, got %v", err)
+	}
+
+	// the object symlinks can't escape the linked bucket
+	if f, err := rootfs.Open("linked/escape/secret"); err == nil {
+		f.Close()
+		t.Errorf("expected open through the escaping symlink to fail")
+	}
+	err = rootfs.MkdirAll("linked/escape/dir", 0, 0, false, 0o755)
+	if err == nil {
+		t.Errorf("expected mkdir through the escaping symlink to fail")
+	}
+	err = rootfs.Rename("linked/dir/obj", "linked/escape/obj")
+	if err == nil {
+		t.Errorf("expected rename into the escaping symlink to fail")
+	}
+	if _, err := os.Stat(filepath.Join(outside, "obj")); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("expected no object outside of the linked bucket, got %v", err)
+	}
+}
@@ -0,0 +1,600 @@
+// Copyright 2026 Versity Software
+// This file is licensed under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package posix
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/versity/versitygw/auth"
+	"github.com/versity/versitygw/backend"
+	"github.com/versity/versitygw/backend/meta"
+	"github.com/versity/versitygw/s3api/utils"
+	"github.com/versity/versitygw/s3err"
+	"github.com/versity/versitygw/s3response"
+)
+
+const (
+	// scrubQuarantineDir is the directory the objects failing the
+	// verification are moved to when quarantine is enabled
+	scrubQuarantineDir = MetaTmpDir + "/quarantine"
+
+	// the algorithms reported for the mismatches not found by the
+	// object checksum
+	scrubAlgorithmETag = "ETAG"
+	scrubAlgorithmData = "DATA"
+)
+
+var _ backend.ScrubNotifier = &Posix{}
+
+// scrubber is the state of the background data scrubber
+type scrubber struct {
+	// interval is the time between the background scrubs,
+	// the background scrubber is disabled if 0
+	interval time.Duration
+	// rate limits the object data read in bytes per second
+	rate int64
+	// quarantine enables moving the corrupted objects out of
+	// the bucket namespace
+	quarantine bool
+	// reportBucket is the bucket the scrub reports are stored in
+	reportBucket string
+
+	reporter atomic.Pointer[func(s3response.ScrubReport)]
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// SetScrubReporter sets the function called with the report of each
+// scrubbed bucket, e.g. to send the metrics and the events
+func (p *Posix) SetScrubReporter(fn func(s3response.ScrubReport)) {
+	p.scrub.reporter.Store(&fn)
+}
+
+// startScrubber starts the background scrubber, scrubbing all buckets
+// at the scrub interval until the backend is shut down
+func (p *Posix) startScrubber() {
+	if p.scrub.interval <= 0 {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	p.scrub.cancel = cancel
+
+	p.scrub.wg.Add(1)
+	go func() {
+		defer p.scrub.wg.Done()
+
+		ticker := time.NewTicker(p.scrub.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				p.scrubBuckets(ctx)
+			}
+		}
+	}()
+}
+
+// stopScrubber stops the background scrubber, and waits for the
+// running scrub to return
+func (p *Posix) stopScrubber() {
+	if p.scrub.cancel == nil {
+		return
+	}
+	p.scrub.cancel()
+	p.scrub.wg.Wait()
+}
+
+func (p *Posix) scrubBuckets(ctx context.Context) {
+	fis, err := listBucketFileInfos(p.rootfs, p.bucketlinks)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "scrub: list buckets: %v\n", err)
+		return
+	}
+
+	for _, fi := range fis {
+		if ctx.Err() != nil {
+			return
+		}
+		_, err := p.scrubBucket(ctx, fi.Name())
+		if err != nil && ctx.Err() == nil {
+			fmt.Fprintf(os.Stderr, "scrub bucket %v: %v\n", fi.Name(), err)
+		}
+	}
+}
+
+// ScrubBucket verifies the data of the bucket objects against the stored
+// checksums and etags. The object versions are verified along with the
+// objects if versioning is enabled. The multipart upload etags and the
+// composite checksums can't be verified from the object data, so the
+// objects without any other checksum are skipped. The concurrency slots
+// are acquired per object, so the scrub doesn't hold back the requests.
+func (p *Posix) ScrubBucket(ctx context.Context, bucket string) (s3response.ScrubReport, error) {
+	if !p.isBucketValid(bucket) {
+		return s3response.ScrubReport{}, s3err.GetAPIError(s3err.ErrInvalidBucketName)
+	}
+
+	_, err := p.rootfs.Stat(bucket)
+	if errors.Is(err, fs.ErrNotExist) {
+		return s3response.ScrubReport{}, s3err.GetAPIError(s3err.ErrNoSuchBucket)
+	}
+	if err != nil {
+		return s3response.ScrubReport{}, fmt.Errorf("stat bucket: %w", err)
+	}
+
+	return p.scrubBucket(ctx, bucket)
+}
+
+func (p *Posix) scrubBucket(ctx context.Context, bucket string) (s3response.ScrubReport, error) {
+	report := s3response.ScrubReport{
+		Bucket:     bucket,
+		StartTime:  time.Now().UTC(),
+		Mismatches: []s3response.ScrubMismatch{},
+	}
+
+	// the objects of the snapshot buckets can't be modified
+	quarantine := p.scrub.quarantine
+	if quarantine {
+		isSnapshot, err := p.isBucketSnapshot(bucket)
+		if err != nil {
+			return report, err
+		}
+		quarantine = !isSnapshot
+	}
+
+	s := &scrubRun{
+		p:          p,
+		ctx:        ctx,
+		bucket:     bucket,
+		report:     &report,
+		quarantine: quarantine,
+		qdir:       filepath.Join(scrubQuarantineDir, strconv.FormatInt(report.StartTime.UnixNano(), 10)),
+		throttle:   newScrubThrottle(p.scrub.rate),
+	}
+
+	fileSystem := os.DirFS(p.rootfs.Path(bucket))
+	err := fs.WalkDir(fileSystem, ".", func(path string, d fs.DirEntry, err error) error {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+		if errors.Is(err, fs.ErrNotExist) {
+			// the object was removed while the bucket is scrubbed
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if d.IsDir() && path == MetaTmpDir {
+			return fs.SkipDir
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+
+		return s.scrubKey(path)
+	})
+	if err != nil {
+		return report, err
+	}
+
+	report.EndTime = time.Now().UTC()
+	p.sendScrubReport(ctx, report)
+
+	return report, nil
+}
+
+// sendScrubReport calls the scrub reporter, and stores the report
+// object in the report bucket if configured
+func (p *Posix) sendScrubReport(ctx context.Context, report s3response.ScrubReport) {
+	if fn := p.scrub.reporter.Load(); fn != nil {
+		(*fn)(report)
+	}
+
+	if p.scrub.reportBucket == "" {
+		return
+	}
+
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "scrub: marshal report: %v\n", err)
+		return
+	}
+
+	// the reports are owned by the gateway, the same as the snapshots
+	ctx = context.WithValue(ctx, "account", auth.Account{UserID: p.euid, GroupID: p.egid})
+	key := fmt.Sprintf("%v/%v.json", report.Bucket, report.StartTime.Format("20060102T150405.000000000Z"))
+	_, err = p.PutObject(ctx, s3response.PutObjectInput{
+		Bucket:        &p.scrub.reportBucket,
+		Key:           &key,
+		Body:          bytes.NewReader(data),
+		ContentLength: aws.Int64(int64(len(data))),
+		ContentType:   aws.String("application/json"),
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "scrub: store report %v/%v: %v\n", p.scrub.reportBucket, key, err)
+	}
+}
+
+// scrubRun is the state of a bucket scrub
+type scrubRun struct {
+	p          *Posix
+	ctx        context.Context
+	bucket     string
+	report     *s3response.ScrubReport
+	quarantine bool
+	qdir       string
+	throttle   *scrubThrottle
+}
+
+// scrubKey verifies the object and the object versions
+func (s *scrubRun) scrubKey(object string) error {
+	versionId := ""
+	if s.p.versioningEnabled() {
+		b, err := s.p.meta.RetrieveAttribute(nil, s.bucket, object, versionIdKey)
+		if err == nil {
+			versionId = string(b)
+		}
+	}
+
+	err := s.scrubObject(s.bucket, object, object, versionId)
+	if err != nil {
+		return err
+	}
+
+	if !s.p.versioningEnabled() {
+		return nil
+	}
+
+	versionBucket := filepath.Join(s.p.versioningDir, s.bucket)
+	versionKey := genObjVersionKey(object)
+	ents, err := s.p.rootfs.ReadDir(filepath.Join(versionBucket, versionKey))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("read %v versions: %w", object, err)
+	}
+
+	for _, ent := range ents {
+		if !ent.Type().IsRegular() {
+			continue
+		}
+		err := s.scrubObject(versionBucket, filepath.Join(versionKey, ent.Name()), object, ent.Name())
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// scrubObject verifies the object data at the bucket and object path
+// against the stored checksum and etag. The key and versionId are the
+// object key and version reported for the mismatches.
+func (s *scrubRun) scrubObject(bucket, object, key, versionId string) error {
+	release, err := s.p.acquireActionSlot(s.ctx)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	objPath := filepath.Join(bucket, object)
+	f, err := s.p.rootfs.Open(objPath)
+	if errors.Is(err, fs.ErrNotExist) || errors.Is(err, syscall.ENOTDIR) {
+		// the object was removed while the bucket is scrubbed
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("open %v: %w", key, err)
+	}
+	defer f.Close()
+
+	fi, err := f.Stat()
+	if err != nil {
+		return fmt.Errorf("stat %v: %w", key, err)
+	}
+
+	_, err = s.p.meta.RetrieveAttribute(f, bucket, object, deleteMarkerKey)
+	if err == nil {
+		// the delete markers don't have data
+		return nil
+	}
+
+	etag, checksum, err := s.p.scrubSums(f, bucket, object)
+	if err != nil {
+		return fmt.Errorf("%v: %w", key, err)
+	}
+	if etag == "" && checksum == nil {
+		s.report.Skipped++
+		return nil
+	}
+
+	info, err := s.p.getCompressionInfo(f, bucket, object)
+	if err != nil {
+		return fmt.Errorf("%v: %w", key, err)
+	}
+	size := fi.Size()
+	if info != nil {
+		size = info.Size
+	}
+
+	mismatch, err := s.verify(f, info, size, etag, checksum)
+	if err != nil {
+		return fmt.Errorf("verify %v: %w", key, err)
+	}
+
+	s.report.Objects++
+	s.report.Bytes += size
+
+	if mismatch == nil {
+		return nil
+	}
+
+	// the object is not reported if it was replaced while verified,
+	// as the stored sums may belong to the new object
+	cur, err := s.p.rootfs.Stat(objPath)
+	if err != nil || !os.SameFile(fi, cur) {
+		return nil
+	}
+
+	mismatch.Key = key
+	mismatch.VersionId = versionId
+	mismatch.Size = size
+
+	if s.quarantine {
+		err := s.p.quarantineObject(bucket, object, s.qdir)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "scrub: quarantine %v/%v: %v\n", s.bucket, key, err)
+		} else {
+			mismatch.Quarantined = true
+		}
+		if bucket == s.bucket {
+			s.p.objectChanged(bucket, object)
+		}
+	}
+
+	s.report.Mismatches = append(s.report.Mismatches, *mismatch)
+	return nil
+}
+
+// scrubSums returns the stored etag and checksum of the object that can
+// be verified from the object data, or empty values if there are none
+func (p *Posix) scrubSums(f *os.File, bucket, object string) (string, *scrubChecksum, error) {
+	var etag string
+	b, err := p.meta.RetrieveAttribute(f, bucket, object, etagkey)
+	if err != nil && !errors.Is(err, meta.ErrNoSuchKey) {
+		return "", nil, fmt.Errorf("get etag: %w", err)
+	}
+	// the multipart upload etags are not the md5 of the object data
+	if err == nil && !strings.Contains(string(b), "-") {
+		etag = string(b)
+	}
+
+	checksums, err := p.retrieveChecksums(f, bucket, object)
+	if errors.Is(err, meta.ErrNoSuchKey) {
+		return etag, nil, nil
+	}
+	if err != nil {
+		return "", nil, fmt.Errorf("get checksums: %w", err)
+	}
+	// the composite checksums are calculated from the part checksums
+	if checksums.Type == types.ChecksumTypeComposite {
+		return etag, nil, nil
+	}
+
+	var sum *string
+	switch checksums.Algorithm {
+	case types.ChecksumAlgorithmCrc32:
+		sum = checksums.CRC32
+	case types.ChecksumAlgorithmCrc32c:
+		sum = checksums.CRC32C
+	case types.ChecksumAlgorithmSha1:
+		sum = checksums.SHA1
+	case types.ChecksumAlgorithmSha256:
+		sum = checksums.SHA256
+	case types.ChecksumAlgorithmCrc64nvme:
+		sum = checksums.CRC64NVME
+	}
+	if getString(sum) == "" {
+		return etag, nil, nil
+	}
+
+	return etag, &scrubChecksum{
+		algorithm: checksums.Algorithm,
+		value:     *sum,
+	}, nil
+}
+
+// scrubChecksum is the stored full object checksum of an object
+type scrubChecksum struct {
+	algorithm types.ChecksumAlgorithm
+	value     string
+}
+
+// verify reads the object data, and returns the mismatch if the data
+// doesn't match the checksum or the etag
+func (s *scrubRun) verify(f *os.File, info *compressionInfo, size int64, etag string, checksum *scrubChecksum) (*s3response.ScrubMismatch, error) {
+	rdr, err := objectDataReader(f, info, 0, size)
+	if errors.Is(err, errCorruptCompressedData) {
+		return &s3response.ScrubMismatch{
+			Algorithm: scrubAlgorithmData,
+			Expected:  strconv.FormatInt(size, 10),
+			Actual:    "0",
+		}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	rdr = s.throttle.reader(s.ctx, rdr)
+
+	var hashRdr *utils.HashReader
+	if checksum != nil {
+		hashRdr, err = utils.NewHashReader(rdr, "", utils.HashType(strings.ToLower(string(checksum.algorithm))))
+		if err != nil {
+			return nil, err
+		}
+		rdr = hashRdr
+	}
+
+	var md5Hash hash.Hash
+	w := io.Discard
+	if etag != "" {
+		md5Hash = md5.New()
+		w = md5Hash
+	}
+
+	n, err := io.Copy(w, rdr)
+	if errors.Is(err, errCorruptCompressedData) || (err == nil && n != size) {
+		return &s3response.ScrubMismatch{
+			Algorithm: scrubAlgorithmData,
+			Expected:  strconv.FormatInt(size, 10),
+			Actual:    strconv.FormatInt(n, 10),
+		}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if hashRdr != nil && hashRdr.Sum() != checksum.value {
+		return &s3response.ScrubMismatch{
+			Algorithm: string(checksum.algorithm),
+			Expected:  checksum.value,
+			Actual:    hashRdr.Sum(),
+		}, nil
+	}
+
+	if md5Hash != nil {
+		actual := backend.GenerateEtag(md5Hash)
+		if !backend.AreEtagsSame(actual, etag) {
+			return &s3response.ScrubMismatch{
+				Algorithm: scrubAlgorithmETag,
+				Expected:  etag,
+				Actual:    actual,
+			}, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// quarantineObject moves the object at the bucket and object path to the
+// quarantine directory of the bucket path, out of the bucket namespace
+func (p *Posix) quarantineObject(bucket, object, qdir string) error {
+	qobject := filepath.Join(qdir, object)
+
+	err := p.rootfs.MkdirAll(filepath.Dir(filepath.Join(bucket, qobject)), p.euid, p.egid, false, p.newDirPerm)
+	if err != nil {
+		return fmt.Errorf("make quarantine dir: %w", err)
+	}
+
+	err = p.rootfs.Rename(filepath.Join(bucket, object), filepath.Join(bucket, qobject))
+	if err != nil {
+		return fmt.Errorf("move object: %w", err)
+	}
+
+	// the attributes stored in the file xattrs are moved with the file
+	if _, ok := p.meta.(meta.AttributeMover); ok {
+		err = meta.MoveAttributes(p.meta, bucket, object, bucket, qobject)
+		if err != nil {
+			return fmt.Errorf("move object attributes: %w", err)
+		}
+	}
+
+	p.removeParents(bucket, object)
+	return nil
+}
+
+// scrubThrottle limits the rate the scrubber reads the object data
+type scrubThrottle struct {
+	rate  int64
+	start time.Time
+	bytes int64
+}
+
+func newScrubThrottle(rate int64) *scrubThrottle {
+	return &scrubThrottle{
+		rate:  rate,
+		start: time.Now(),
+	}
+}
+
+// wait accounts for the n bytes read, and sleeps until the read rate
+// is within the rate limit
+func (t *scrubThrottle) wait(ctx context.Context, n int) error {
+	if t.rate <= 0 {
+		return nil
+	}
+
+	t.bytes += int64(n)
+	ahead := time.Duration(float64(t.bytes)/float64(t.rate)*float64(time.Second)) - time.Since(t.start)
+	if ahead <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(ahead)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+func (t *scrubThrottle) reader(ctx context.Context, r io.Reader) io.Reader {
+	if t.rate <= 0 {
+		return r
+	}
+	return &throttledReader{r: r, ctx: ctx, t: t}
+}
+
+type throttledReader struct {
+	r   io.Reader
+	ctx context.Context
+	t   *scrubThrottle
+}
+
+func (tr *throttledReader) Read(b []byte) (int, error) {
+	n, err := tr.r.Read(b)
+	if n > 0 {
+		if werr := tr.t.wait(tr.ctx, n); werr != nil {
+			return n, werr
+		}
+	}
+	return n, err
+}
@@ -0,0 +1,220 @@
+// Copyright 2026 Versity Software
+// This file is licensed under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package posix
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/versity/versitygw/s3response"
+)
+
+// corruptObject flips a byte of the object file in place, so the file and
+// the stored sums are kept
+func corruptObject(t *testing.T, p *Posix, bucket, key string, offset int64) {
+	t.Helper()
+
+	f, err := os.OpenFile(filepath.Join(p.rootfs.Path(bucket), key), os.O_RDWR, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	b := make([]byte, 1)
+	_, err = f.ReadAt(b, offset)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b[0] ^= 0xff
+	_, err = f.WriteAt(b, offset)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func scrubTestBucket(t *testing.T, p *Posix, bucket string) s3response.ScrubReport {
+	t.Helper()
+
+	report, err := p.ScrubBucket(context.Background(), bucket)
+	if err != nil {
+		t.Fatalf("scrub bucket %v: %v", bucket, err)
+	}
+	return report
+}
+
+func TestScrubBucket_Corrupted(t *testing.T) {
+	for _, quarantine := range []bool{false, true} {
+		t.Run("quarantine="+strconv.FormatBool(quarantine), func(t *testing.T) {
+			p := newTestPosix(t, PosixOpts{ScrubQuarantine: quarantine})
+			bucket := "bucket"
+			createTestBucket(t, p, bucket)
+			putTestObject(t, p, bucket, "good", "good data")
+			putTestObject(t, p, bucket, "dir/bad", "bad data")
+
+			report := scrubTestBucket(t, p, bucket)
+			if report.Objects != 2 || report.Bytes != 17 || len(report.Mismatches) != 0 {
+				t.Fatalf("expected 2 verified objects, got %+v", report)
+			}
+
+			corruptObject(t, p, bucket, "dir/bad", 0)
+			report = scrubTestBucket(t, p, bucket)
+			if report.Objects != 2 || len(report.Mismatches) != 1 {
+				t.Fatalf("expected one mismatch, got %+v", report)
+			}
+			m := report.Mismatches[0]
+			if m.Key != "dir/bad" || m.Size != 8 || m.Expected == m.Actual || m.Quarantined != quarantine {
+				t.Errorf("unexpected mismatch %+v", m)
+			}
+
+			_, err := p.rootfs.Stat(filepath.Join(bucket, "dir/bad"))
+			if quarantine != os.IsNotExist(err) {
+				t.Errorf("expected the object quarantined %v, got stat error %v", quarantine, err)
+			}
+			if !quarantine {
+				return
+			}
+
+			qpath := filepath.Join(bucket, scrubQuarantineDir,
+				strconv.FormatInt(report.StartTime.UnixNano(), 10), "dir/bad")
+			_, err = p.rootfs.Stat(qpath)
+			if err != nil {
+				t.Errorf("expected the object in the quarantine dir: %v", err)
+			}
+
+			// the quarantined objects are out of the bucket namespace
+			report = scrubTestBucket(t, p, bucket)
+			if report.Objects != 1 || len(report.Mismatches) != 0 {
+				t.Errorf("expected the quarantined object not scrubbed, got %+v", report)
+			}
+		})
+	}
+}
+
+func TestScrubBucket_Compressed(t *testing.T) {
+	p := newTestPosix(t, PosixOpts{})
+	bucket := "bucket"
+	createTestBucket(t, p, bucket)
+
+	err := p.PutBucketTagging(context.Background(), bucket, map[string]string{compressionTagKey: compressionZstd})
+	if err != nil {
+		t.Fatalf("put bucket tagging: %v", err)
+	}
+	data := string(compressibleData(2*compressionFrameSize + 100))
+	putTestObject(t, p, bucket, "obj", data)
+
+	// the uncompressed object data is verified
+	report := scrubTestBucket(t, p, bucket)
+	if report.Objects != 1 || report.Bytes != int64(len(data)) || len(report.Mismatches) != 0 {
+		t.Fatalf("expected the compressed object verified, got %+v", report)
+	}
+
+	corruptObject(t, p, bucket, "obj", 100)
+	report = scrubTestBucket(t, p, bucket)
+	if len(report.Mismatches) != 1 || report.Mismatches[0].Key != "obj" ||
+		report.Mismatches[0].Size != int64(len(data)) {
+		t.Fatalf("expected the corrupted compressed object reported, got %+v", report)
+	}
+}
+
+func TestScrubBucket_Skipped(t *testing.T) {
+	p := newTestPosix(t, PosixOpts{})
+	bucket := "bucket"
+	createTestBucket(t, p, bucket)
+
+	// the multipart etag and the composite checksum can't be verified
+	// from the object data
+	putTestObject(t, p, bucket, "multipart", "data")
+	err := p.meta.StoreAttribute(nil, bucket, "multipart", etagkey, []byte(`"abc-2"`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	crc := "AAAAAA==-2"
+	err = p.storeChecksums(nil, bucket, "multipart", s3response.Checksum{
+		Type:      types.ChecksumTypeComposite,
+		Algorithm: types.ChecksumAlgorithmCrc32,
+		CRC32:     &crc,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	corruptObject(t, p, bucket, "multipart", 0)
+
+	// the multipart etag is skipped, the full object checksum is verified
+	putTestObject(t, p, bucket, "full", "data")
+	err = p.meta.StoreAttribute(nil, bucket, "full", etagkey, []byte(`"abc-2"`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	crc64 := "AAAAAAAAAAA="
+	err = p.storeChecksums(nil, bucket, "full", s3response.Checksum{
+		Type:      types.ChecksumTypeFullObject,
+		Algorithm: types.ChecksumAlgorithmCrc64nvme,
+		CRC64NVME: &crc64,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	report := scrubTestBucket(t, p, bucket)
+	if report.Skipped != 1 || report.Objects != 1 {
+		t.Fatalf("expected one skipped and one verified object, got %+v", report)
+	}
+	if len(report.Mismatches) != 1 {
+		t.Fatalf("expected the full object checksum mismatch, got %+v", report.Mismatches)
+	}
+	m := report.Mismatches[0]
+	if m.Key != "full" || m.Algorithm != string(types.ChecksumAlgorithmCrc64nvme) ||
+		m.Expected != crc64 || m.Actual == crc64 {
+		t.Errorf("unexpected mismatch %+v", m)
+	}
+}
+
+func TestScrubBucket_Report(t *testing.T) {
+	p := newTestPosix(t, PosixOpts{ScrubReportBucket: "reports"})
+	bucket := "bucket"
+	createTestBucket(t, p, bucket)
+	createTestBucket(t, p, "reports")
+	putTestObject(t, p, bucket, "obj", "data")
+	corruptObject(t, p, bucket, "obj", 0)
+
+	var reported []s3response.ScrubReport
+	p.SetScrubReporter(func(report s3response.ScrubReport) {
+		reported = append(reported, report)
+	})
+
+	report := scrubTestBucket(t, p, bucket)
+	if len(report.Mismatches) != 1 {
+		t.Fatalf("expected one mismatch, got %+v", report)
+	}
+	if !reflect.DeepEqual(reported, []s3response.ScrubReport{report}) {
+		t.Errorf("expected the report sent to the reporter, got %+v", reported)
+	}
+
+	key := bucket + "/" + report.StartTime.Format("20060102T150405.000000000Z") + ".json"
+	var stored s3response.ScrubReport
+	err := json.Unmarshal([]byte(getTestObject(t, p, "reports", key)), &stored)
+	if err != nil {
+		t.Fatalf("unmarshal report object: %v", err)
+	}
+	if !reflect.DeepEqual(stored, report) {
+		t.Errorf("expected the report object %+v, got %+v", report, stored)
+	}
+}
@@ -0,0 +1,468 @@
+// Copyright 2026 Versity Software
+// This file is licensed under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package posix
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"github.com/versity/versitygw/auth"
+	"github.com/versity/versitygw/backend/meta"
+	"github.com/versity/versitygw/s3err"
+	"github.com/versity/versitygw/s3response"
+)
+
+// snapshotKey is the bucket attribute of the snapshot buckets
+const snapshotKey = "snapshot"
+
+// snapshotStatusTTL is how long the status of the failed snapshots
+// is kept, the status of the created snapshots is kept with the
+// snapshot bucket
+const snapshotStatusTTL = 24 * time.Hour
+
+// bucketSnapshot describes the bucket a snapshot bucket was created from
+type bucketSnapshot struct {
+	Source      string    `json:"source"`
+	CreatedAt   time.Time `json:"createdAt"`
+	CompletedAt time.Time `json:"completedAt,omitzero"`
+	Objects     int64     `json:"objects,omitempty"`
+	// Pending is set while the snapshot objects are created
+	Pending bool `json:"pending,omitempty"`
+}
+
+// snapshotJobs are the snapshots being created in the background,
+// and the recently failed snapshots
+type snapshotJobs struct {
+	mu   sync.Mutex
+	jobs map[string]*snapshotJob
+
+	// ctx is canceled on shutdown to stop the running snapshots
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+type snapshotJob struct {
+	// status is guarded by the snapshotJobs mutex
+	status  s3response.SnapshotStatus
+	objects atomic.Int64
+}
+
+// SnapshotBucket starts creating a read-only snapshot bucket with the
+// objects and metadata of the bucket. The snapshot bucket is created and
+// marked read-only before returning, and the objects are added in the
+// background, use SnapshotBucketStatus to get the progress. The object
+// data is reflinked when the filesystem supports it, so the snapshot is
+// created without copying the data and only takes up space as the source
+// objects are overwritten. The objects are cloned one by one, so each
+// snapshot object is a consistent copy of the source object, but objects
+// modified while the snapshot is created may or may not be included. The
+// object versions and the incomplete multipart uploads are not included
+// in the snapshot.
+func (p *Posix) SnapshotBucket(ctx context.Context, bucket, snapshot string) error {
+	release, err := p.acquireActionSlot(ctx)
+	if err != nil {
+		return err
+	}
+
+	snap, err := p.createSnapshotBucket(bucket, snapshot)
+	if err != nil {
+		release()
+		return err
+	}
+
+	job := &snapshotJob{
+		status: s3response.SnapshotStatus{
+			Snapshot:  snapshot,
+			Source:    bucket,
+			Status:    s3response.SnapshotRunning,
+			StartTime: snap.CreatedAt,
+		},
+	}
+	jobCtx := p.addSnapshotJob(job)
+
+	// the action slot is held until the snapshot is created
+	go func() {
+		defer p.snapshots.wg.Done()
+		defer release()
+		p.runSnapshot(jobCtx, job, snap)
+	}()
+
+	return nil
+}
+
+// createSnapshotBucket creates the snapshot bucket marked read-only
+// and pending, before the snapshot objects are added
+func (p *Posix) createSnapshotBucket(bucket, snapshot string) (bucketSnapshot, error) {
+	if !p.isBucketValid(bucket) || !p.isBucketValid(snapshot) {
+		return bucketSnapshot{}, s3err.GetAPIError(s3err.ErrInvalidBucketName)
+	}
+
+	_, err := p.rootfs.Stat(bucket)
+	if errors.Is(err, fs.ErrNotExist) {
+		return bucketSnapshot{}, s3err.GetAPIError(s3err.ErrNoSuchBucket)
+	}
+	if err != nil {
+		return bucketSnapshot{}, fmt.Errorf("stat bucket: %w", err)
+	}
+
+	err = p.rootfs.Mkdir(snapshot, p.newDirPerm)
+	if errors.Is(err, fs.ErrExist) {
+		return bucketSnapshot{}, s3err.GetAPIError(s3err.ErrBucketAlreadyExists)
+	}
+	if errors.Is(err, syscall.EROFS) {
+		return bucketSnapshot{}, s3err.GetAPIError(s3err.ErrMethodNotAllowed)
+	}
+	if err != nil {
+		return bucketSnapshot{}, fmt.Errorf("mkdir snapshot bucket: %w", err)
+	}
+
+	// mark the snapshot bucket read-only before adding the objects,
+	// so the objects can't be modified while the snapshot is created
+	snap := bucketSnapshot{
+		Source:    bucket,
+		CreatedAt: time.Now().UTC(),
+		Pending:   true,
+	}
+	err = p.storeBucketSnapshot(snapshot, snap)
+	if err != nil {
+		p.removeSnapshot(snapshot)
+		return bucketSnapshot{}, err
+	}
+
+	return snap, nil
+}
+
+// SnapshotBucketStatus returns the progress of the snapshot creation
+func (p *Posix) SnapshotBucketStatus(_ context.Context, snapshot string) (s3response.SnapshotStatus, error) {
+	if !p.isBucketValid(snapshot) {
+		return s3response.SnapshotStatus{}, s3err.GetAPIError(s3err.ErrInvalidBucketName)
+	}
+
+	p.snapshots.mu.Lock()
+	job, ok := p.snapshots.jobs[snapshot]
+	if ok {
+		status := job.status
+		p.snapshots.mu.Unlock()
+		status.Objects = job.objects.Load()
+		return status, nil
+	}
+	p.snapshots.mu.Unlock()
+
+	_, err := p.rootfs.Stat(snapshot)
+	if errors.Is(err, fs.ErrNotExist) {
+		return s3response.SnapshotStatus{}, s3err.GetAPIError(s3err.ErrNoSuchBucket)
+	}
+	if err != nil {
+		return s3response.SnapshotStatus{}, fmt.Errorf("stat bucket: %w", err)
+	}
+
+	b, err := p.meta.RetrieveAttribute(nil, snapshot, "", snapshotKey)
+	if errors.Is(err, meta.ErrNoSuchKey) {
+		// the bucket is not a snapshot bucket
+		return s3response.SnapshotStatus{}, s3err.GetAPIError(s3err.ErrInvalidRequest)
+	}
+	if err != nil {
+		return s3response.SnapshotStatus{}, fmt.Errorf("get bucket snapshot: %w", err)
+	}
+
+	var snap bucketSnapshot
+	err = json.Unmarshal(b, &snap)
+	if err != nil {
+		return s3response.SnapshotStatus{}, fmt.Errorf("parse bucket snapshot: %w", err)
+	}
+
+	status := s3response.SnapshotStatus{
+		Snapshot:  snapshot,
+		Source:    snap.Source,
+		Status:    s3response.SnapshotComplete,
+		Objects:   snap.Objects,
+		StartTime: snap.CreatedAt,
+		EndTime:   snap.CompletedAt,
+	}
+	if snap.Pending {
+		// the gateway was stopped while the snapshot was created
+		status.Status = s3response.SnapshotFailed
+		status.Error = "snapshot creation was interrupted, the snapshot bucket is incomplete"
+	}
+	return status, nil
+}
+
+// addSnapshotJob registers the snapshot job, and returns the context
+// of the job canceled on shutdown
+func (p *Posix) addSnapshotJob(job *snapshotJob) context.Context {
+	p.snapshots.mu.Lock()
+	defer p.snapshots.mu.Unlock()
+
+	if p.snapshots.jobs == nil {
+		p.snapshots.jobs = make(map[string]*snapshotJob)
+		p.snapshots.ctx, p.snapshots.cancel = context.WithCancel(context.Background())
+	}
+
+	// drop the status of the failed snapshots
+	for name, j := range p.snapshots.jobs {
+		if j.status.Status == s3response.SnapshotFailed &&
+			time.Since(j.status.EndTime) > snapshotStatusTTL {
+			delete(p.snapshots.jobs, name)
+		}
+	}
+
+	p.snapshots.jobs[job.status.Snapshot] = job
+	p.snapshots.wg.Add(1)
+	return p.snapshots.ctx
+}
+
+// isSnapshotRunning reports if the snapshot is being created
+func (p *Posix) isSnapshotRunning(snapshot string) bool {
+	p.snapshots.mu.Lock()
+	defer p.snapshots.mu.Unlock()
+	job, ok := p.snapshots.jobs[snapshot]
+	return ok && job.status.Status == s3response.SnapshotRunning
+}
+
+// stopSnapshots stops the running snapshots, the partially created
+// snapshot buckets are removed
+func (p *Posix) stopSnapshots() {
+	p.snapshots.mu.Lock()
+	cancel := p.snapshots.cancel
+	p.snapshots.mu.Unlock()
+	if cancel == nil {
+		return
+	}
+	cancel()
+	p.snapshots.wg.Wait()
+}
+
+func (p *Posix) runSnapshot(ctx context.Context, job *snapshotJob, snap bucketSnapshot) {
+	snapshot := job.status.Snapshot
+
+	err := p.snapshotBucket(ctx, snap.Source, snapshot, &job.objects)
+	if err == nil {
+		snap.Pending = false
+		snap.CompletedAt = time.Now().UTC()
+		snap.Objects = job.objects.Load()
+		err = p.storeBucketSnapshot(snapshot, snap)
+	}
+	if err != nil {
+		p.removeSnapshot(snapshot)
+		if errors.Is(err, syscall.EDQUOT) {
+			err = s3err.GetAPIError(s3err.ErrQuotaExceeded)
+		}
+		fmt.Fprintf(os.Stderr, "snapshot %v of bucket %v: %v\n", snapshot, snap.Source, err)
+
+		p.snapshots.mu.Lock()
+		job.status.Status = s3response.SnapshotFailed
+		job.status.Error = err.Error()
+		job.status.EndTime = time.Now().UTC()
+		p.snapshots.mu.Unlock()
+		return
+	}
+
+	// the snapshot objects are indexed by the reconciler
+	p.markListIndexDirty(snapshot)
+
+	// the status of the created snapshot is read from the bucket
+	p.snapshots.mu.Lock()
+	delete(p.snapshots.jobs, snapshot)
+	p.snapshots.mu.Unlock()
+}
+
+// removeSnapshot removes the partially created snapshot bucket
+func (p *Posix) removeSnapshot(snapshot string) {
+	p.rootfs.RemoveAll(snapshot)
+	p.meta.DeleteAttributes(snapshot, "")
+}
+
+func (p *Posix) storeBucketSnapshot(snapshot string, snap bucketSnapshot) error {
+	b, err := json.Marshal(snap)
+	if err != nil {
+		return fmt.Errorf("marshal snapshot: %w", err)
+	}
+	err = p.meta.StoreAttribute(nil, snapshot, "", snapshotKey, b)
+	if errors.Is(err, syscall.EROFS) {
+		return s3err.GetAPIError(s3err.ErrMethodNotAllowed)
+	}
+	if err != nil {
+		return fmt.Errorf("set snapshot attr: %w", err)
+	}
+	return nil
+}
+
+func (p *Posix) snapshotBucket(ctx context.Context, bucket, snapshot string, objects *atomic.Int64) error {
+	// the bucket settings (acl, policy, tagging, etc.) are copied as is,
+	// except the versioning, as the object versions are not included
+	err := p.copyAttributes(nil, nil, bucket, "", snapshot, "", versioningKey, snapshotKey)
+	if err != nil {
+		return fmt.Errorf("copy bucket attributes: %w", err)
+	}
+
+	// the snapshot is owned by the gateway, regardless of the
+	// uid/gid of the account requesting the snapshot
+	acct := auth.Account{UserID: p.euid, GroupID: p.egid}
+
+	fileSystem := os.DirFS(p.rootfs.Path(bucket))
+	return fs.WalkDir(fileSystem, ".", func(path string, d fs.DirEntry, err error) error {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if errors.Is(err, fs.ErrNotExist) {
+			// the object was removed while the snapshot is created
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if path == "." {
+			return nil
+		}
+		if d.IsDir() && path == MetaTmpDir {
+			return fs.SkipDir
+		}
+
+		switch {
+		case d.IsDir():
+			return p.snapshotDir(bucket, snapshot, path)
+		case d.Type().IsRegular():
+			err := p.snapshotObject(bucket, snapshot, path, acct)
+			if err != nil {
+				return err
+			}
+			objects.Add(1)
+			return nil
+		default:
+			// only directories and regular files are objects
+			return nil
+		}
+	})
+}
+
+func (p *Posix) snapshotDir(bucket, snapshot, dir string) error {
+	err := p.rootfs.Mkdir(filepath.Join(snapshot, dir), p.newDirPerm)
+	if err != nil && !errors.Is(err, fs.ErrExist) {
+		return fmt.Errorf("mkdir %v: %w", dir, err)
+	}
+
+	// the directory objects and the parent directories of the
+	// objects may have metadata
+	err = p.copyAttributes(nil, nil, bucket, dir, snapshot, dir)
+	if err != nil {
+		return fmt.Errorf("copy %v attributes: %w", dir, err)
+	}
+
+	return nil
+}
+
+func (p *Posix) snapshotObject(bucket, snapshot, object string, acct auth.Account) error {
+	sf, err := p.rootfs.Open(filepath.Join(bucket, object))
+	if errors.Is(err, fs.ErrNotExist) {
+		// the object was removed while the snapshot is created
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("open %v: %w", object, err)
+	}
+	defer sf.Close()
+
+	fi, err := sf.Stat()
+	if err != nil {
+		return fmt.Errorf("stat %v: %w", object, err)
+	}
+
+	f, err := p.openTmpFile(filepath.Join(snapshot, MetaTmpDir), snapshot, object,
+		fi.Size(), acct, skipFalloc, p.forceNoTmpFile)
+	if err != nil {
+		return fmt.Errorf("open temp file: %w", err)
+	}
+	defer f.cleanup()
+
+	if !p.cloneFileData(f.File(), sf, 0, fi.Size()) {
+		_, err = io.Copy(f.File(), sf)
+		if err != nil {
+			return fmt.Errorf("copy %v data: %w", object, err)
+		}
+	}
+
+	err = p.copyAttributes(sf, f.File(), bucket, object, snapshot, object)
+	if err != nil {
+		return fmt.Errorf("copy %v attributes: %w", object, err)
+	}
+
+	err = f.link()
+	if err != nil {
+		return fmt.Errorf("link %v: %w", object, err)
+	}
+
+	return nil
+}
+
+// copyAttributes copies the attributes of the source object to the
+// destination object, except the excluded attributes. The src and dst
+// files are optional, and are used to access the attributes if set.
+func (p *Posix) copyAttributes(src, dst *os.File, srcBucket, srcObject, dstBucket, dstObject string, exclude ...string) error {
+	attrs, err := p.meta.ListAttributes(srcBucket, srcObject)
+	if err != nil {
+		return fmt.Errorf("list attributes: %w", err)
+	}
+
+	data := make(map[string][]byte, len(attrs))
+	for _, attr := range attrs {
+		if slices.Contains(exclude, attr) {
+			continue
+		}
+		value, err := p.meta.RetrieveAttribute(src, srcBucket, srcObject, attr)
+		if err != nil {
+			return fmt.Errorf("get %v attribute: %w", attr, err)
+		}
+		data[attr] = value
+	}
+
+	return meta.StoreAttributes(p.meta, dst, dstBucket, dstObject, data)
+}
+
+// isBucketSnapshot reports if the bucket is a snapshot bucket
+func (p *Posix) isBucketSnapshot(bucket string) (bool, error) {
+	_, err := p.meta.RetrieveAttribute(nil, bucket, "", snapshotKey)
+	if errors.Is(err, meta.ErrNoSuchKey) || errors.Is(err, fs.ErrNotExist) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("get bucket snapshot: %w", err)
+	}
+	return true, nil
+}
+
+// checkBucketWritable returns an error if the objects of the bucket
+// can't be modified, because the bucket is a read-only snapshot bucket
+func (p *Posix) checkBucketWritable(bucket string) error {
+	isSnapshot, err := p.isBucketSnapshot(bucket)
+	if err != nil {
+		return err
+	}
+	if isSnapshot {
+		return s3err.GetAPIError(s3err.ErrMethodNotAllowed)
+	}
+	return nil
+}
@@ -0,0 +1,278 @@
+// Copyright 2026 Versity Software
+// This file is licensed under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package posix
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/versity/versitygw/s3err"
+	"github.com/versity/versitygw/s3response"
+)
+
+// waitSnapshot waits for the snapshot to be created, and returns the
+// final snapshot status
+func waitSnapshot(t *testing.T, p *Posix, snapshot string) s3response.SnapshotStatus {
+	t.Helper()
+
+	deadline := time.Now().Add(10 * time.Second)
+	for {
+		status, err := p.SnapshotBucketStatus(context.Background(), snapshot)
+		if err != nil {
+			t.Fatalf("snapshot status: %v", err)
+		}
+		if status.Status != s3response.SnapshotRunning {
+			return status
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for snapshot %v", snapshot)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestSnapshotBucket(t *testing.T) {
+	p := newTestPosix(t, PosixOpts{})
+	createTestBucket(t, p, "bucket")
+	putTestObject(t, p, "bucket", "obj", "data")
+	putTestObject(t, p, "bucket", "dir/obj", "nested data")
+
+	ctx := context.Background()
+	err := p.SnapshotBucket(ctx, "bucket", "snap")
+	if err != nil {
+		t.Fatalf("snapshot bucket: %v", err)
+	}
+
+	status := waitSnapshot(t, p, "snap")
+	if status.Status != s3response.SnapshotComplete {
+		t.Fatalf("expected complete snapshot, got %+v", status)
+	}
+	if status.Source != "bucket" || status.Objects != 2 || status.EndTime.IsZero() {
+		t.Errorf("unexpected snapshot status %+v", status)
+	}
+
+	// the source objects modified after the snapshot
+	// don't change the snapshot objects
+	putTestObject(t, p, "bucket", "obj", "changed")
+	if got := getTestObject(t, p, "snap", "obj"); got != "data" {
+		t.Errorf("expected snapshot object data, got %q", got)
+	}
+	if got := getTestObject(t, p, "snap", "dir/obj"); got != "nested data" {
+		t.Errorf("expected snapshot object data, got %q", got)
+	}
+
+	err = p.SnapshotBucket(ctx, "bucket", "snap")
+	if !errors.Is(err, s3err.GetAPIError(s3err.ErrBucketAlreadyExists)) {
+		t.Errorf("expected ErrBucketAlreadyExists, got %v", err)
+	}
+	err = p.SnapshotBucket(ctx, "missing", "snap2")
+	if !errors.Is(err, s3err.GetAPIError(s3err.ErrNoSuchBucket)) {
+		t.Errorf("expected ErrNoSuchBucket, got %v", err)
+	}
+	_, err = p.SnapshotBucketStatus(ctx, "bucket")
+	if !errors.Is(err, s3err.GetAPIError(s3err.ErrInvalidRequest)) {
+		t.Errorf("expected ErrInvalidRequest for a bucket that is not a snapshot, got %v", err)
+	}
+	_, err = p.SnapshotBucketStatus(ctx, "missing")
+	if !errors.Is(err, s3err.GetAPIError(s3err.ErrNoSuchBucket)) {
+		t.Errorf("expected ErrNoSuchBucket, got %v", err)
+	}
+}
+
+func TestSnapshotBucket_ReadOnly(t *testing.T) {
+	p := newTestPosix(t, PosixOpts{})
+	createTestBucket(t, p, "bucket")
+	putTestObject(t, p, "bucket", "obj", "data")
+
+	ctx := context.Background()
+	err := p.SnapshotBucket(ctx, "bucket", "snap")
+	if err != nil {
+		t.Fatalf("snapshot bucket: %v", err)
+	}
+	waitSnapshot(t, p, "snap")
+
+	errNotAllowed := s3err.GetAPIError(s3err.ErrMethodNotAllowed)
+
+	_, err = putObject(p, "snap", "obj", "changed")
+	if !errors.Is(err, errNotAllowed) {
+		t.Errorf("expected put object to be not allowed, got %v", err)
+	}
+	_, err = putObject(p, "snap", "new", "new")
+	if !errors.Is(err, errNotAllowed) {
+		t.Errorf("expected put of a new object to be not allowed, got %v", err)
+	}
+
+	bucket, key := "snap", "obj"
+	_, err = p.DeleteObject(ctx, &s3.DeleteObjectInput{
+		Bucket: &bucket,
+		Key:    &key,
+	})
+	if !errors.Is(err, errNotAllowed) {
+		t.Errorf("expected delete object to be not allowed, got %v", err)
+	}
+
+	source, owner := "bucket/obj", ""
+	_, err = p.CopyObject(ctx, s3response.CopyObjectInput{
+		Bucket:              &bucket,
+		Key:                 &key,
+		CopySource:          &source,
+		ExpectedBucketOwner: &owner,
+	})
+	if !errors.Is(err, errNotAllowed) {
+		t.Errorf("expected copy into the snapshot to be not allowed, got %v", err)
+	}
+
+	if got := getTestObject(t, p, "snap", "obj"); got != "data" {
+		t.Errorf("expected the snapshot object to be unchanged, got %q", got)
+	}
+
+	// the snapshot buckets are deleted with the snapshot objects
+	err = p.DeleteBucket(ctx, "snap")
+	if err != nil {
+		t.Fatalf("delete snapshot bucket: %v", err)
+	}
+	if _, err := p.rootfs.Stat("snap"); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("expected the snapshot bucket to be removed, got %v", err)
+	}
+}
+
+func TestSnapshotBucket_Background(t *testing.T) {
+	p := newTestPosix(t, PosixOpts{})
+	createTestBucket(t, p, "bucket")
+	putTestObject(t, p, "bucket", "obj", "data")
+
+	// block the snapshot object copy until the status is checked
+	calls := stubCloneFuncs(t, nil, nil)
+	started := make(chan struct{})
+	unblock := make(chan struct{})
+	reflink := reflinkRangeFunc
+	reflinkRangeFunc = func(dst *os.File, dstOff int64, src *os.File, srcOff, length int64) error {
+		close(started)
+		<-unblock
+		return reflink(dst, dstOff, src, srcOff, length)
+	}
+
+	ctx := context.Background()
+	err := p.SnapshotBucket(ctx, "bucket", "snap")
+	if err != nil {
+		t.Fatalf("snapshot bucket: %v", err)
+	}
+	<-started
+
+	status, err := p.SnapshotBucketStatus(ctx, "snap")
+	if err != nil {
+		t.Fatalf("snapshot status: %v", err)
+	}
+	if status.Status != s3response.SnapshotRunning {
+		t.Errorf("expected running snapshot, got %+v", status)
+	}
+
+	// the snapshot is read-only and can't be deleted while created
+	_, err = putObject(p, "snap", "obj", "changed")
+	if !errors.Is(err, s3err.GetAPIError(s3err.ErrMethodNotAllowed)) {
+		t.Errorf("expected put object to be not allowed, got %v", err)
+	}
+	err = p.DeleteBucket(ctx, "snap")
+	if !errors.Is(err, s3err.GetAPIError(s3err.ErrBucketNotEmpty)) {
+		t.Errorf("expected ErrBucketNotEmpty deleting the running snapshot, got %v", err)
+	}
+
+	close(unblock)
+	status = waitSnapshot(t, p, "snap")
+	if status.Status != s3response.SnapshotComplete || status.Objects != 1 {
+		t.Errorf("expected complete snapshot, got %+v", status)
+	}
+	if len(*calls) != 1 {
+		t.Errorf("expected the object to be reflinked, got %v", *calls)
+	}
+}
+
+func TestSnapshotBucket_Shutdown(t *testing.T) {
+	p := newTestPosix(t, PosixOpts{})
+	createTestBucket(t, p, "bucket")
+	putTestObject(t, p, "bucket", "obj1", "data")
+	putTestObject(t, p, "bucket", "obj2", "data")
+
+	stubCloneFuncs(t, nil, nil)
+	started := make(chan struct{})
+	unblock := make(chan struct{})
+	reflink := reflinkRangeFunc
+	reflinkRangeFunc = func(dst *os.File, dstOff int64, src *os.File, srcOff, length int64) error {
+		select {
+		case <-started:
+		default:
+			close(started)
+		}
+		<-unblock
+		return reflink(dst, dstOff, src, srcOff, length)
+	}
+
+	err := p.SnapshotBucket(context.Background(), "bucket", "snap")
+	if err != nil {
+		t.Fatalf("snapshot bucket: %v", err)
+	}
+	<-started
+
+	// the running snapshot is stopped on shutdown
+	stopped := make(chan struct{})
+	go func() {
+		p.stopSnapshots()
+		close(stopped)
+	}()
+	for p.snapshots.ctx.Err() == nil {
+		time.Sleep(time.Millisecond)
+	}
+	close(unblock)
+	<-stopped
+
+	status, err := p.SnapshotBucketStatus(context.Background(), "snap")
+	if err != nil {
+		t.Fatalf("snapshot status: %v", err)
+	}
+	if status.Status != s3response.SnapshotFailed || status.Error == "" {
+		t.Errorf("expected failed snapshot, got %+v", status)
+	}
+	if _, err := p.rootfs.Stat("snap"); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("expected the partial snapshot bucket to be removed, got %v", err)
+	}
+}
+
+func TestSnapshotBucket_Interrupted(t *testing.T) {
+	p := newTestPosix(t, PosixOpts{})
+	createTestBucket(t, p, "snap")
+
+	// a pending snapshot without a running job was
+	// interrupted by stopping the gateway
+	err := p.storeBucketSnapshot("snap", bucketSnapshot{
+		Source:    "bucket",
+		CreatedAt: time.Now().UTC(),
+		Pending:   true,
+	})
+	if err != nil {
+		t.Fatalf("store snapshot: %v", err)
+	}
+
+	status, err := p.SnapshotBucketStatus(context.Background(), "snap")
+	if err != nil {
+		t.Fatalf("snapshot status: %v", err)
+	}
+	if status.Status != s3response.SnapshotFailed {
+		t.Errorf("expected failed snapshot, got %+v", status)
+	}
+}
@@ -0,0 +1,789 @@
+// Copyright 2026 Versity Software
+// This file is licensed under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package posix
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/versity/versitygw/backend"
+	"github.com/versity/versitygw/backend/meta"
+	"github.com/versity/versitygw/s3api/utils"
+	"github.com/versity/versitygw/s3response"
+	bolt "go.etcd.io/bbolt"
+)
+
+// watchSettle is the time a file must be left unmodified before the
+// change is processed, so the files still being written are not read
+const watchSettle = time.Second
+
+var _ backend.ChangeNotifier = &Posix{}
+
+// watcher is the state of the watcher of the objects changed outside of
+// the gateway, e.g. written by the NFS or local filesystem clients. The
+// changes are detected by comparing the object files with the state seen
+// last, either when the filesystem notifications report the changed
+// paths, or by scanning all buckets at the watch interval. The state seen
+// last is stored in the list index database, so it is not held in memory
+// and the changes made while the gateway is stopped are reported once it
+// is started. The changes made by the gateway update the seen state as
+// they are made, so only the other changes are reported.
+type watcher struct {
+	// interval is the time between the bucket scans, the watcher
+	// is disabled if 0
+	interval time.Duration
+
+	reporter atomic.Pointer[func(s3response.ObjectChange)]
+
+	// mu protects dirty, the bucket paths reported changed by the
+	// filesystem notifications that are not processed yet
+	mu    sync.Mutex
+	dirty map[string]map[string]struct{}
+	// rescan requests a scan of all buckets, when the notifications
+	// of some changes were lost
+	rescan atomic.Bool
+
+	notify changeNotify
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// fileState is the state of an object file used to detect the changes
+type fileState struct {
+	size int64
+	// mtime is the modification time in nanoseconds since the epoch
+	mtime int64
+}
+
+func newFileState(fi fs.FileInfo) fileState {
+	return fileState{
+		size:  fi.Size(),
+		mtime: fi.ModTime().UnixNano(),
+	}
+}
+
+func (s fileState) encode() []byte {
+	b := binary.BigEndian.AppendUint64(nil, uint64(s.size))
+	return binary.BigEndian.AppendUint64(b, uint64(s.mtime))
+}
+
+func decodeFileState(b []byte) (fileState, error) {
+	if len(b) != 16 {
+		return fileState{}, fmt.Errorf("invalid watch state length %v", len(b))
+	}
+	return fileState{
+		size:  int64(binary.BigEndian.Uint64(b)),
+		mtime: int64(binary.BigEndian.Uint64(b[8:])),
+	}, nil
+}
+
+// watchUpdate is an update of the state of an object seen by a scan, the
+// state is removed if state is nil. The update is only stored if the
+// stored state is still prev, so the states stored by the gateway writes
+// since the object was checked are kept.
+type watchUpdate struct {
+	object string
+	prev   []byte
+	state  []byte
+}
+
+// changeNotify is the filesystem notification of the changed paths
+type changeNotify interface {
+	// addBucket starts the notifications of the bucket changes
+	addBucket(bucket string)
+	close()
+}
+
+// SetChangeReporter sets the function called with each object changed
+// outside of the gateway, e.g. to send the events
+func (p *Posix) SetChangeReporter(fn func(s3response.ObjectChange)) {
+	p.watch.reporter.Store(&fn)
+}
+
+// startWatcher starts the watcher of the objects changed outside of the
+// gateway. The buckets without a stored state are scanned at start to
+// record the current state and to add the missing etags and checksums,
+// without reporting the objects as changed. The filesystem notifications
+// are used where available, the buckets are still scanned at the watch
+// interval for the changes not notified, e.g. made by other NFS clients.
+func (p *Posix) startWatcher() {
+	if p.watch.interval <= 0 {
+		return
+	}
+
+	p.watch.dirty = make(map[string]map[string]struct{})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	p.watch.cancel = cancel
+
+	notify, err := p.newChangeNotify(ctx)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "watch: filesystem notifications unavailable, scanning every %v: %v\n",
+			p.watch.interval, err)
+	}
+	p.watch.notify = notify
+
+	p.watch.wg.Add(1)
+	go func() {
+		defer p.watch.wg.Done()
+
+		p.watchBuckets(ctx)
+
+		ticker := time.NewTicker(p.watch.interval)
+		defer ticker.Stop()
+		settle := time.NewTicker(watchSettle)
+		defer settle.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				p.watchBuckets(ctx)
+			case <-settle.C:
+				if p.watch.rescan.Swap(false) {
+					p.watchBuckets(ctx)
+					continue
+				}
+				p.watchDirty(ctx)
+			}
+		}
+	}()
+}
+
+// stopWatcher stops the watcher, and waits for the running scan to return
+func (p *Posix) stopWatcher() {
+	if p.watch.cancel == nil {
+		return
+	}
+	p.watch.cancel()
+	p.watch.wg.Wait()
+	if p.watch.notify != nil {
+		p.watch.notify.close()
+	}
+}
+
+// objectChanged is called once the gateway changed the object, to update
+// the listing index and the state seen by the watcher
+func (p *Posix) objectChanged(bucket, object string) {
+	p.syncListIndex(bucket, object)
+
+	if p.watch.interval <= 0 {
+		return
+	}
+
+	var state []byte
+	fi, err := p.rootfs.Lstat(filepath.Join(bucket, object))
+	if err == nil && fi.Mode().IsRegular() {
+		state = newFileState(fi).encode()
+	}
+
+	err = p.listIndex.db.Update(func(tx *bolt.Tx) error {
+		seen := tx.Bucket(watchStateBucket).Bucket([]byte(bucket))
+		if seen == nil {
+			return nil
+		}
+		if state == nil {
+			return seen.Delete([]byte(object))
+		}
+		return seen.Put([]byte(object), state)
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "watch: update %v/%v: %v\n", bucket, object, err)
+	}
+}
+
+// watchBucketCreated records the bucket created by the gateway as empty,
+// so the objects later written outside of the gateway are reported
+func (p *Posix) watchBucketCreated(bucket string) {
+	if p.watch.interval <= 0 {
+		return
+	}
+
+	_, err := p.addWatchedBucket(bucket)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "watch: add bucket %v: %v\n", bucket, err)
+	}
+
+	if p.watch.notify != nil {
+		p.watch.notify.addBucket(bucket)
+	}
+}
+
+// addWatchedBucket records the bucket with no objects seen, it returns
+// false if the bucket state is already stored
+func (p *Posix) addWatchedBucket(bucket string) (bool, error) {
+	var added bool
+	err := p.listIndex.db.Update(func(tx *bolt.Tx) error {
+		buckets := tx.Bucket(watchStateBucket)
+		if buckets.Bucket([]byte(bucket)) != nil {
+			return nil
+		}
+		added = true
+		_, err := buckets.CreateBucket([]byte(bucket))
+		return err
+	})
+	return added, err
+}
+
+// isWatchedBucket reports if the bucket state is stored
+func (p *Posix) isWatchedBucket(bucket string) (bool, error) {
+	var ok bool
+	err := p.listIndex.db.View(func(tx *bolt.Tx) error {
+		ok = tx.Bucket(watchStateBucket).Bucket([]byte(bucket)) != nil
+		return nil
+	})
+	return ok, err
+}
+
+// getWatchState returns the stored state of the object, or nil if the
+// object was not seen. watched is false if the bucket state is not stored.
+func (p *Posix) getWatchState(bucket, object string) (state []byte, watched bool, err error) {
+	err = p.listIndex.db.View(func(tx *bolt.Tx) error {
+		seen := tx.Bucket(watchStateBucket).Bucket([]byte(bucket))
+		if seen == nil {
+			return nil
+		}
+		watched = true
+		if v := seen.Get([]byte(object)); v != nil {
+			state = append([]byte(nil), v...)
+		}
+		return nil
+	})
+	return state, watched, err
+}
+
+// flushWatchUpdates stores the object states updated by a scan
+func (p *Posix) flushWatchUpdates(bucket string, updates []watchUpdate) error {
+	if len(updates) == 0 {
+		return nil
+	}
+
+	return p.listIndex.db.Update(func(tx *bolt.Tx) error {
+		seen := tx.Bucket(watchStateBucket).Bucket([]byte(bucket))
+		if seen == nil {
+			// the bucket was removed while scanned
+			return nil
+		}
+		for _, u := range updates {
+			if !bytes.Equal(seen.Get([]byte(u.object)), u.prev) {
+				continue
+			}
+			var err error
+			if u.state == nil {
+				err = seen.Delete([]byte(u.object))
+			} else {
+				err = seen.Put([]byte(u.object), u.state)
+			}
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// pruneWatchedBuckets removes the state of the buckets not found
+func (p *Posix) pruneWatchedBuckets(found map[string]struct{}) error {
+	return p.listIndex.db.Update(func(tx *bolt.Tx) error {
+		buckets := tx.Bucket(watchStateBucket)
+		var removed [][]byte
+		err := buckets.ForEachBucket(func(k []byte) error {
+			if _, ok := found[string(k)]; !ok {
+				removed = append(removed, append([]byte(nil), k...))
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		for _, k := range removed {
+			err := buckets.DeleteBucket(k)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// markWatchDirty queues the bucket path reported changed by the
+// filesystem notifications
+func (p *Posix) markWatchDirty(bucket, path string) {
+	p.watch.mu.Lock()
+	defer p.watch.mu.Unlock()
+
+	paths, ok := p.watch.dirty[bucket]
+	if !ok {
+		paths = make(map[string]struct{})
+		p.watch.dirty[bucket] = paths
+	}
+	paths[path] = struct{}{}
+}
+
+// watchBuckets scans all buckets for the changed objects. The objects of
+// the buckets not seen before are recorded without being reported, e.g.
+// of the snapshots or of the buckets created outside of the gateway.
+func (p *Posix) watchBuckets(ctx context.Context) {
+	fis, err := listBucketFileInfos(p.rootfs, p.bucketlinks)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "watch: list buckets: %v\n", err)
+		return
+	}
+
+	buckets := make(map[string]struct{}, len(fis))
+	for _, fi := range fis {
+		if ctx.Err() != nil {
+			return
+		}
+		bucket := fi.Name()
+		buckets[bucket] = struct{}{}
+
+		added, err := p.addWatchedBucket(bucket)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "watch: add bucket %v: %v\n", bucket, err)
+			continue
+		}
+
+		err = p.watchTree(ctx, bucket, ".", added)
+		if err != nil && ctx.Err() == nil {
+			fmt.Fprintf(os.Stderr, "watch: scan bucket %v: %v\n", bucket, err)
+		}
+	}
+
+	// the deleted buckets aren't watched anymore
+	err = p.pruneWatchedBuckets(buckets)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "watch: remove deleted buckets: %v\n", err)
+	}
+}
+
+// watchDirty processes the paths reported changed by the filesystem
+// notifications. The changes of the buckets not scanned yet are picked up
+// by the bucket scans.
+func (p *Posix) watchDirty(ctx context.Context) {
+	p.watch.mu.Lock()
+	dirty := p.watch.dirty
+	p.watch.dirty = make(map[string]map[string]struct{})
+	p.watch.mu.Unlock()
+
+	for bucket, paths := range dirty {
+		watched, err := p.isWatchedBucket(bucket)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "watch: %v: %v\n", bucket, err)
+			continue
+		}
+		if !watched {
+			continue
+		}
+
+		for path := range paths {
+			if ctx.Err() != nil {
+				return
+			}
+
+			err := p.watchPath(ctx, bucket, path)
+			if err != nil && ctx.Err() == nil {
+				fmt.Fprintf(os.Stderr, "watch: %v/%v: %v\n", bucket, path, err)
+			}
+		}
+	}
+}
+
+// watchPath checks the bucket path reported changed for changes. The
+// directories are scanned, as the files may be moved in or out along
+// with the directory.
+func (p *Posix) watchPath(ctx context.Context, bucket, path string) error {
+	fi, err := p.rootfs.Lstat(filepath.Join(bucket, path))
+	if err == nil && fi.IsDir() {
+		return p.watchTree(ctx, bucket, path, false)
+	}
+
+	prev, _, serr := p.getWatchState(bucket, path)
+	if serr != nil {
+		return serr
+	}
+	if err != nil && prev == nil {
+		return p.watchTree(ctx, bucket, path, false)
+	}
+
+	var updates []watchUpdate
+	err = p.watchObject(ctx, bucket, path, false, &updates)
+	if ferr := p.flushWatchUpdates(bucket, updates); err == nil {
+		err = ferr
+	}
+	return err
+}
+
+// watchTree checks the objects in the dir of the bucket, and the objects
+// seen before in the dir, for changes. The changes are not reported if
+// initial is set, only the missing etags and checksums are added.
+func (p *Posix) watchTree(ctx context.Context, bucket, dir string, initial bool) error {
+	updates := make([]watchUpdate, 0, listIndexBatchSize)
+	check := func(key string) error {
+		err := p.watchObject(ctx, bucket, key, initial, &updates)
+		if err != nil && ctx.Err() == nil {
+			fmt.Fprintf(os.Stderr, "watch: %v/%v: %v\n", bucket, key, err)
+		}
+		if len(updates) < listIndexBatchSize {
+			return nil
+		}
+		err = p.flushWatchUpdates(bucket, updates)
+		updates = updates[:0]
+		return err
+	}
+
+	fileSystem := os.DirFS(p.rootfs.Path(bucket))
+	err := fs.WalkDir(fileSystem, dir, func(path string, d fs.DirEntry, err error) error {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+		if errors.Is(err, fs.ErrNotExist) {
+			// the path was removed while the bucket is scanned
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if path == MetaTmpDir {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+
+		return check(path)
+	})
+	if err == nil {
+		err = p.flushWatchUpdates(bucket, updates)
+		updates = updates[:0]
+	}
+	if err != nil {
+		return err
+	}
+
+	// the objects seen before that are not found anymore are removed
+	return p.watchRemoved(ctx, bucket, dir, check, func() error {
+		err := p.flushWatchUpdates(bucket, updates)
+		updates = updates[:0]
+		return err
+	})
+}
+
+// watchRemoved checks the objects seen before in the dir of the bucket
+// that are not found anymore, these are read from the stored state in
+// batches. The objects still found were checked by the scan of the dir,
+// or were written by the gateway since.
+func (p *Posix) watchRemoved(ctx context.Context, bucket, dir string, check func(string) error, flush func() error) error {
+	var prefix string
+	if dir != "." {
+		prefix = dir + "/"
+
+		// the dir itself may have been an object
+		err := p.checkRemoved(bucket, dir, check)
+		if err != nil {
+			return err
+		}
+	}
+
+	start := []byte(prefix)
+	for start != nil {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		var keys []string
+		var next []byte
+		err := p.listIndex.db.View(func(tx *bolt.Tx) error {
+			seen := tx.Bucket(watchStateBucket).Bucket([]byte(bucket))
+			if seen == nil {
+				return nil
+			}
+			c := seen.Cursor()
+			k, _ := c.Seek(start)
+			for ; k != nil && strings.HasPrefix(string(k), prefix); k, _ = c.Next() {
+				if len(keys) == listIndexBatchSize {
+					next = append([]byte(nil), k...)
+					break
+				}
+				keys = append(keys, string(k))
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, key := range keys {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			err := p.checkRemoved(bucket, key, check)
+			if err != nil {
+				return err
+			}
+		}
+		err = flush()
+		if err != nil {
+			return err
+		}
+		start = next
+	}
+
+	return nil
+}
+
+// checkRemoved checks the object with check if the object file is not
+// found anymore
+func (p *Posix) checkRemoved(bucket, key string, check func(string) error) error {
+	fi, err := p.rootfs.Lstat(filepath.Join(bucket, key))
+	if err == nil && fi.Mode().IsRegular() {
+		return nil
+	}
+	return check(key)
+}
+
+// watchObject compares the object file with the state seen last, and
+// reports the object if it was created, modified or removed. The etag
+// and checksum of the modified objects are calculated from the object
+// data, only the missing etag and checksum of the created objects are
+// added, and the listing index is updated. The object state update is
+// added to updates. The objects modified within the settle time are
+// checked again later.
+func (p *Posix) watchObject(ctx context.Context, bucket, object string, initial bool, updates *[]watchUpdate) error {
+	fi, err := p.rootfs.Lstat(filepath.Join(bucket, object))
+	if err != nil && !errors.Is(err, fs.ErrNotExist) && !errors.Is(err, syscall.ENOTDIR) {
+		return fmt.Errorf("stat: %w", err)
+	}
+	exists := err == nil && fi.Mode().IsRegular()
+
+	stored, watched, err := p.getWatchState(bucket, object)
+	if err != nil {
+		return fmt.Errorf("get watch state: %w", err)
+	}
+	if !watched {
+		return nil
+	}
+	known := stored != nil
+	var prev fileState
+	if known {
+		prev, err = decodeFileState(stored)
+		if err != nil {
+			return err
+		}
+	}
+
+	if !exists {
+		if !known {
+			return nil
+		}
+		*updates = append(*updates, watchUpdate{object: object, prev: stored})
+		if !initial {
+			p.syncListIndex(bucket, object)
+			p.reportChange(s3response.ObjectChange{
+				Bucket:    bucket,
+				Key:       object,
+				Removed:   true,
+				Size:      prev.size,
+				SizeDelta: -prev.size,
+			})
+		}
+		return nil
+	}
+
+	state := newFileState(fi)
+	if known && prev == state {
+		return nil
+	}
+
+	if time.Since(time.Unix(0, state.mtime)) < watchSettle {
+		p.markWatchDirty(bucket, object)
+		return nil
+	}
+
+	// the sums stored with the objects not seen before are kept, the
+	// objects may have been written by the gateway before the bucket
+	// was watched
+	etag, err := p.updateObjectSums(ctx, bucket, object, known)
+	if err != nil {
+		return err
+	}
+
+	// the object is checked again if it was modified while the sums
+	// were calculated
+	cur, err := p.rootfs.Lstat(filepath.Join(bucket, object))
+	if err != nil || newFileState(cur) != state {
+		p.markWatchDirty(bucket, object)
+		return nil
+	}
+
+	*updates = append(*updates, watchUpdate{
+		object: object,
+		prev:   stored,
+		state:  state.encode(),
+	})
+
+	if initial {
+		return nil
+	}
+
+	p.syncListIndex(bucket, object)
+	delta := state.size
+	if known {
+		delta -= prev.size
+	}
+	p.reportChange(s3response.ObjectChange{
+		Bucket:    bucket,
+		Key:       object,
+		Size:      state.size,
+		SizeDelta: delta,
+		ETag:      etag,
+	})
+	return nil
+}
+
+// updateObjectSums calculates the etag and the full object checksum of
+// the object from the object data and stores these, and returns the etag.
+// Unless the object was modified, only the missing etag or checksum is
+// stored and the stored sums are kept, e.g. the multipart etags and the
+// composite checksums of the gateway uploads. The checksum algorithm of
+// the previous object is kept if it's a full object checksum, otherwise
+// CRC64NVME is used.
+func (p *Posix) updateObjectSums(ctx context.Context, bucket, object string, modified bool) (string, error) {
+	release, err := p.acquireActionSlot(ctx)
+	if err != nil {
+		return "", err
+	}
+	defer release()
+
+	f, err := p.rootfs.Open(filepath.Join(bucket, object))
+	if err != nil {
+		return "", fmt.Errorf("open: %w", err)
+	}
+	defer f.Close()
+
+	checksums, err := p.retrieveChecksums(f, bucket, object)
+	hasChecksum := err == nil
+	if err != nil && !errors.Is(err, meta.ErrNoSuchKey) {
+		return "", fmt.Errorf("get checksum: %w", err)
+	}
+
+	var etag string
+	if !modified {
+		b, err := p.meta.RetrieveAttribute(f, bucket, object, etagkey)
+		if err != nil && !errors.Is(err, meta.ErrNoSuchKey) {
+			return "", fmt.Errorf("get etag: %w", err)
+		}
+		etag = string(b)
+		if etag != "" && hasChecksum {
+			return etag, nil
+		}
+
+		// the object data is only compressed by the gateway, the sums
+		// can't be calculated from the file data
+		info, err := p.getCompressionInfo(f, bucket, object)
+		if err != nil {
+			return "", err
+		}
+		if info != nil {
+			return etag, nil
+		}
+	}
+
+	algorithm := types.ChecksumAlgorithmCrc64nvme
+	if hasChecksum && checksums.Type == types.ChecksumTypeFullObject && checksums.Algorithm != "" {
+		algorithm = checksums.Algorithm
+	}
+
+	hashRdr, err := utils.NewHashReader(f, "", utils.HashType(strings.ToLower(string(algorithm))))
+	if err != nil {
+		return "", err
+	}
+	md5Hash := md5.New()
+	_, err = io.Copy(md5Hash, hashRdr)
+	if err != nil {
+		return "", fmt.Errorf("read: %w", err)
+	}
+
+	if modified {
+		// the data written outside of the gateway is not compressed
+		err = p.clearCompressionInfo(bucket, object)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	if etag == "" {
+		etag = backend.GenerateEtag(md5Hash)
+		err = p.meta.StoreAttribute(nil, bucket, object, etagkey, []byte(etag))
+		if err != nil {
+			return "", fmt.Errorf("set etag: %w", err)
+		}
+	}
+
+	if hasChecksum && !modified {
+		return etag, nil
+	}
+
+	sum := hashRdr.Sum()
+	checksum := s3response.Checksum{
+		Type:      types.ChecksumTypeFullObject,
+		Algorithm: algorithm,
+	}
+	switch algorithm {
+	case types.ChecksumAlgorithmCrc32:
+		checksum.CRC32 = &sum
+	case types.ChecksumAlgorithmCrc32c:
+		checksum.CRC32C = &sum
+	case types.ChecksumAlgorithmSha1:
+		checksum.SHA1 = &sum
+	case types.ChecksumAlgorithmSha256:
+		checksum.SHA256 = &sum
+	case types.ChecksumAlgorithmCrc64nvme:
+		checksum.CRC64NVME = &sum
+	}
+	err = p.storeChecksums(nil, bucket, object, checksum)
+	if err != nil {
+		return "", fmt.Errorf("store checksum: %w", err)
+	}
+
+	return etag, nil
+}
+
+// reportChange sends the object change to the change reporter if set
+func (p *Posix) reportChange(change s3response.ObjectChange) {
+	fn := p.watch.reporter.Load()
+	if fn != nil {
+		(*fn)(change)
+	}
+}
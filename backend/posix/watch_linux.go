@@ -0,0 +1,223 @@
+// Copyright 2026 Versity Software
+// This file is licensed under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package posix
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"unsafe"
+
+	"golang.org/x/sys/unix"
+)
+
+const (
+	// inotifyMask is the changes of the watched directories notified
+	inotifyMask = unix.IN_CLOSE_WRITE | unix.IN_MODIFY | unix.IN_CREATE |
+		unix.IN_DELETE | unix.IN_MOVED_FROM | unix.IN_MOVED_TO
+
+	// inotifyPollTimeout is the time in milliseconds the reader waits
+	// for the events before checking if the watcher is stopped
+	inotifyPollTimeout = 500
+)
+
+// inotify notifies the changes of the bucket directories with inotify.
+// The directories are watched recursively, as inotify only notifies the
+// changes of the directory entries.
+type inotify struct {
+	p  *Posix
+	fd int
+
+	mu sync.Mutex
+	// dirs is the root relative path of the watched directories
+	dirs map[int]string
+	// full is set once the watch limit is reached, the changes of the
+	// directories not watched are found by the bucket scans
+	full bool
+
+	wg sync.WaitGroup
+}
+
+func (p *Posix) newChangeNotify(ctx context.Context) (changeNotify, error) {
+	fd, err := unix.InotifyInit1(unix.IN_CLOEXEC | unix.IN_NONBLOCK)
+	if err != nil {
+		return nil, fmt.Errorf("inotify init: %w", err)
+	}
+
+	n := &inotify{
+		p:    p,
+		fd:   fd,
+		dirs: make(map[int]string),
+	}
+
+	// the root directory is watched for the new buckets
+	err = n.addDir("")
+	if err != nil {
+		unix.Close(fd)
+		return nil, err
+	}
+
+	fis, err := listBucketFileInfos(p.rootfs, p.bucketlinks)
+	if err != nil {
+		unix.Close(fd)
+		return nil, err
+	}
+	for _, fi := range fis {
+		n.addBucket(fi.Name())
+	}
+
+	n.wg.Add(1)
+	go func() {
+		defer n.wg.Done()
+		n.read(ctx)
+	}()
+
+	return n, nil
+}
+
+// addDir watches the directory at the root relative path
+func (n *inotify) addDir(dir string) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if n.full {
+		return nil
+	}
+
+	wd, err := unix.InotifyAddWatch(n.fd, filepath.Join(n.p.rootdir, dir), inotifyMask|unix.IN_ONLYDIR)
+	if errors.Is(err, unix.ENOSPC) {
+		n.full = true
+		fmt.Fprintf(os.Stderr, "watch: inotify watch limit reached, the directories not watched are scanned every %v\n",
+			n.p.watch.interval)
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("inotify watch %v: %w", dir, err)
+	}
+	n.dirs[wd] = dir
+	return nil
+}
+
+// addBucket watches the directories of the bucket
+func (n *inotify) addBucket(bucket string) {
+	n.addTree(bucket, ".")
+}
+
+// addTree watches the dir of the bucket and its subdirectories, except
+// the temporary directory of the bucket
+func (n *inotify) addTree(bucket, dir string) {
+	fileSystem := os.DirFS(n.p.rootfs.Path(bucket))
+	_ = fs.WalkDir(fileSystem, dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			// the directories removed since are skipped
+			return nil
+		}
+		if !d.IsDir() {
+			return nil
+		}
+		if path == MetaTmpDir {
+			return fs.SkipDir
+		}
+		err = n.addDir(filepath.Join(bucket, path))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "watch: %v\n", err)
+		}
+		return nil
+	})
+}
+
+// read reads the inotify events until the context is canceled, and
+// queues the changed paths
+func (n *inotify) read(ctx context.Context) {
+	buf := make([]byte, 64*1024)
+	fds := []unix.PollFd{{Fd: int32(n.fd), Events: unix.POLLIN}}
+
+	for ctx.Err() == nil {
+		_, err := unix.Poll(fds, inotifyPollTimeout)
+		if err != nil && !errors.Is(err, unix.EINTR) {
+			fmt.Fprintf(os.Stderr, "watch: inotify poll: %v\n", err)
+			return
+		}
+
+		nr, err := unix.Read(n.fd, buf)
+		if errors.Is(err, unix.EAGAIN) || errors.Is(err, unix.EINTR) {
+			continue
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "watch: inotify read: %v\n", err)
+			return
+		}
+
+		for off := 0; off+unix.SizeofInotifyEvent <= nr; {
+			ev := (*unix.InotifyEvent)(unsafe.Pointer(&buf[off]))
+			name := buf[off+unix.SizeofInotifyEvent : off+unix.SizeofInotifyEvent+int(ev.Len)]
+			off += unix.SizeofInotifyEvent + int(ev.Len)
+
+			n.event(ev.Wd, ev.Mask, strings.TrimRight(string(name), "\x00"))
+		}
+	}
+}
+
+// event queues the path of the inotify event, and watches the new
+// directories
+func (n *inotify) event(wd int32, mask uint32, name string) {
+	if mask&unix.IN_Q_OVERFLOW != 0 {
+		// the lost changes are found by scanning the buckets
+		n.p.watch.rescan.Store(true)
+		return
+	}
+
+	n.mu.Lock()
+	dir, ok := n.dirs[int(wd)]
+	if mask&unix.IN_IGNORED != 0 {
+		delete(n.dirs, int(wd))
+	}
+	n.mu.Unlock()
+	if !ok || name == "" {
+		return
+	}
+
+	isNewDir := mask&unix.IN_ISDIR != 0 && mask&(unix.IN_CREATE|unix.IN_MOVED_TO) != 0
+
+	if dir == "" {
+		// a bucket was created in the root directory, the objects of
+		// the new buckets are recorded by the next bucket scan
+		if isNewDir {
+			n.addBucket(name)
+		}
+		return
+	}
+
+	bucket, objdir, _ := strings.Cut(dir, string(filepath.Separator))
+	path := filepath.Join(objdir, name)
+	if path == MetaTmpDir {
+		return
+	}
+
+	if isNewDir {
+		n.addTree(bucket, path)
+	}
+	n.p.markWatchDirty(bucket, filepath.ToSlash(path))
+}
+
+func (n *inotify) close() {
+	n.wg.Wait()
+	unix.Close(n.fd)
+}
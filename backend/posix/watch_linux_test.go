@@ -0,0 +1,222 @@
+// Copyright 2026 Versity Software
+// This file is licensed under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package posix
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+// newTestInotify returns the inotify of the backend root, the events are
+// only read from the inotify if read is set
+func newTestInotify(t *testing.T, p *Posix, read bool) *inotify {
+	t.Helper()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	if !read {
+		cancel()
+	}
+	notify, err := p.newChangeNotify(ctx)
+	if err != nil {
+		cancel()
+		t.Fatalf("new change notify: %v", err)
+	}
+	t.Cleanup(func() {
+		cancel()
+		notify.close()
+	})
+	return notify.(*inotify)
+}
+
+// watchDescriptor returns the watch descriptor of the root relative dir
+func watchDescriptor(t *testing.T, n *inotify, dir string) int32 {
+	t.Helper()
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	for wd, d := range n.dirs {
+		if d == dir {
+			return int32(wd)
+		}
+	}
+	t.Fatalf("directory %q is not watched", dir)
+	return 0
+}
+
+func isWatchDirty(p *Posix, bucket, path string) bool {
+	p.watch.mu.Lock()
+	defer p.watch.mu.Unlock()
+	_, ok := p.watch.dirty[bucket][path]
+	return ok
+}
+
+func TestInotify_Events(t *testing.T) {
+	p, _ := newTestWatchPosix(t)
+	bucket := "bucket"
+	createTestBucket(t, p, bucket)
+	bucketDir := p.rootfs.Path(bucket)
+	err := os.MkdirAll(filepath.Join(bucketDir, "dir", MetaTmpDir), 0o755)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = os.MkdirAll(filepath.Join(bucketDir, MetaTmpDir), 0o755)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	n := newTestInotify(t, p, false)
+	root := watchDescriptor(t, n, "")
+	wd := watchDescriptor(t, n, bucket)
+	dirWd := watchDescriptor(t, n, filepath.Join(bucket, "dir"))
+	// only the bucket temporary directory is skipped
+	watchDescriptor(t, n, filepath.Join(bucket, "dir", MetaTmpDir))
+	n.mu.Lock()
+	for _, dir := range n.dirs {
+		if dir == filepath.Join(bucket, MetaTmpDir) {
+			t.Errorf("expected the bucket temporary directory not watched")
+		}
+	}
+	n.mu.Unlock()
+
+	// the events of the bucket directories are mapped to the object paths
+	n.event(wd, unix.IN_CLOSE_WRITE, "obj")
+	n.event(dirWd, unix.IN_MODIFY, "nested")
+	n.event(dirWd, unix.IN_DELETE, "removed")
+	n.event(wd, unix.IN_MOVED_FROM, "moved")
+	for _, path := range []string{"obj", "dir/nested", "dir/removed", "moved"} {
+		if !isWatchDirty(p, bucket, path) {
+			t.Errorf("expected %q queued, got %v", path, p.watch.dirty)
+		}
+	}
+
+	// the temporary directory and the unknown watches are ignored
+	n.event(wd, unix.IN_CREATE|unix.IN_ISDIR, MetaTmpDir)
+	n.event(wd+1000, unix.IN_CLOSE_WRITE, "unknown")
+	n.event(wd, unix.IN_CLOSE_WRITE, "")
+	if isWatchDirty(p, bucket, MetaTmpDir) || isWatchDirty(p, bucket, "unknown") || isWatchDirty(p, bucket, "") {
+		t.Errorf("expected the ignored events not queued, got %v", p.watch.dirty)
+	}
+
+	// the new directories are watched along with their subdirectories
+	err = os.MkdirAll(filepath.Join(bucketDir, "new", "sub"), 0o755)
+	if err != nil {
+		t.Fatal(err)
+	}
+	n.event(wd, unix.IN_CREATE|unix.IN_ISDIR, "new")
+	if !isWatchDirty(p, bucket, "new") {
+		t.Errorf("expected the new directory queued, got %v", p.watch.dirty)
+	}
+	subWd := watchDescriptor(t, n, filepath.Join(bucket, "new", "sub"))
+	n.event(subWd, unix.IN_CLOSE_WRITE, "obj")
+	if !isWatchDirty(p, bucket, "new/sub/obj") {
+		t.Errorf("expected new/sub/obj queued, got %v", p.watch.dirty)
+	}
+
+	// the buckets created in the root directory are watched, the
+	// bucket objects are recorded by the scans
+	err = os.MkdirAll(filepath.Join(p.rootdir, "newbucket"), 0o755)
+	if err != nil {
+		t.Fatal(err)
+	}
+	n.event(root, unix.IN_CREATE|unix.IN_ISDIR, "newbucket")
+	watchDescriptor(t, n, "newbucket")
+	n.event(root, unix.IN_CLOSE_WRITE, "file")
+	if _, ok := p.watch.dirty[""]; ok {
+		t.Errorf("expected the root directory events not queued, got %v", p.watch.dirty)
+	}
+
+	// the removed directories are not watched anymore
+	n.event(subWd, unix.IN_IGNORED, "")
+	n.mu.Lock()
+	_, ok := n.dirs[int(subWd)]
+	n.mu.Unlock()
+	if ok {
+		t.Errorf("expected the removed directory watch removed")
+	}
+
+	// the lost events are found by a scan of all buckets
+	n.event(-1, unix.IN_Q_OVERFLOW, "")
+	if !p.watch.rescan.Load() {
+		t.Errorf("expected a rescan after the event queue overflow")
+	}
+}
+
+func TestInotify_Notify(t *testing.T) {
+	p, _ := newTestWatchPosix(t)
+	bucket := "bucket"
+	createTestBucket(t, p, bucket)
+	n := newTestInotify(t, p, true)
+
+	waitDirty := func(path string) {
+		t.Helper()
+		deadline := time.Now().Add(5 * time.Second)
+		for !isWatchDirty(p, bucket, path) {
+			if time.Now().After(deadline) {
+				t.Fatalf("%q was not queued", path)
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+	}
+
+	bucketDir := p.rootfs.Path(bucket)
+	err := os.WriteFile(filepath.Join(bucketDir, "obj"), []byte("data"), 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	waitDirty("obj")
+
+	err = os.Mkdir(filepath.Join(bucketDir, "dir"), 0o755)
+	if err != nil {
+		t.Fatal(err)
+	}
+	waitDirty("dir")
+
+	// the files of the new directory are notified once it is watched
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		n.mu.Lock()
+		var watched bool
+		for _, dir := range n.dirs {
+			watched = watched || dir == filepath.Join(bucket, "dir")
+		}
+		n.mu.Unlock()
+		if watched {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("the new directory is not watched")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	err = os.WriteFile(filepath.Join(bucketDir, "dir", "obj"), []byte("data"), 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	waitDirty("dir/obj")
+
+	p.watch.mu.Lock()
+	clear(p.watch.dirty)
+	p.watch.mu.Unlock()
+	err = os.Remove(filepath.Join(bucketDir, "dir", "obj"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	waitDirty("dir/obj")
+}
@@ -0,0 +1,34 @@
+// Copyright 2026 Versity Software
+// This file is licensed under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+//go:build !linux
+
+package posix
+
+import (
+	"context"
+	"testing"
+)
+
+func TestChangeNotify_Unsupported(t *testing.T) {
+	p := newTestPosix(t, PosixOpts{})
+
+	notify, err := p.newChangeNotify(context.Background())
+	if err == nil {
+		t.Fatalf("expected the filesystem notifications unsupported")
+	}
+	if notify != nil {
+		t.Errorf("expected no change notify, got %v", notify)
+	}
+}
@@ -0,0 +1,475 @@
+// Copyright 2026 Versity Software
+// This file is licensed under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package posix
+
+import (
+	"context"
+	"crypto/md5"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/versity/versitygw/backend/meta"
+	"github.com/versity/versitygw/s3response"
+	bolt "go.etcd.io/bbolt"
+)
+
+// newTestWatchPosix returns a posix backend with the watcher state set up
+// but not started, so the scans are run by the tests. No filesystem
+// notifications are used, as when these are not supported.
+func newTestWatchPosix(t *testing.T) (*Posix, *[]s3response.ObjectChange) {
+	t.Helper()
+
+	p := newTestListIndexPosix(t)
+	p.watch.interval = time.Hour
+	p.watch.dirty = make(map[string]map[string]struct{})
+
+	var changes []s3response.ObjectChange
+	p.SetChangeReporter(func(change s3response.ObjectChange) {
+		changes = append(changes, change)
+	})
+	return p, &changes
+}
+
+// writeOutside writes the object file as a filesystem client would, with
+// the modification time past the settle time
+func writeOutside(t *testing.T, p *Posix, bucket, key, data string, age time.Duration) {
+	t.Helper()
+
+	path := filepath.Join(p.rootfs.Path(bucket), key)
+	err := os.MkdirAll(filepath.Dir(path), 0o755)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = os.WriteFile(path, []byte(data), 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	mtime := time.Now().Add(-age)
+	err = os.Chtimes(path, mtime, mtime)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+// unwatch removes the stored watcher state of the bucket object, or of the
+// bucket if object is empty, as if not seen yet
+func unwatch(t *testing.T, p *Posix, bucket, object string) {
+	t.Helper()
+
+	err := p.listIndex.db.Update(func(tx *bolt.Tx) error {
+		buckets := tx.Bucket(watchStateBucket)
+		if object == "" {
+			return buckets.DeleteBucket([]byte(bucket))
+		}
+		return buckets.Bucket([]byte(bucket)).Delete([]byte(object))
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func isWatched(t *testing.T, p *Posix, bucket string) bool {
+	t.Helper()
+
+	ok, err := p.isWatchedBucket(bucket)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return ok
+}
+
+func testEtag(data string) string {
+	return fmt.Sprintf("%q", fmt.Sprintf("%x", md5.Sum([]byte(data))))
+}
+
+// takeChanges returns the reported changes sorted by key, and clears these
+func takeChanges(changes *[]s3response.ObjectChange) []s3response.ObjectChange {
+	got := *changes
+	*changes = nil
+	slices.SortFunc(got, func(a, b s3response.ObjectChange) int {
+		return strings.Compare(a.Key, b.Key)
+	})
+	return got
+}
+
+func TestWatcher_ObjectChanges(t *testing.T) {
+	p, changes := newTestWatchPosix(t)
+	ctx := context.Background()
+	bucket := "bucket"
+	createTestBucket(t, p, bucket)
+
+	// the gateway writes are not reported
+	putTestObject(t, p, bucket, "gateway", "data")
+	p.watchBuckets(ctx)
+	if got := takeChanges(changes); len(got) != 0 {
+		t.Fatalf("expected no changes for the gateway writes, got %+v", got)
+	}
+
+	writeOutside(t, p, bucket, "dir/obj", "hello", time.Minute)
+	p.watchBuckets(ctx)
+	want := []s3response.ObjectChange{{
+		Bucket:    bucket,
+		Key:       "dir/obj",
+		Size:      5,
+		SizeDelta: 5,
+		ETag:      testEtag("hello"),
+	}}
+	if got := takeChanges(changes); !reflect.DeepEqual(got, want) {
+		t.Fatalf("created: expected %+v, got %+v", want, got)
+	}
+
+	// the etag is stored for the gateway requests
+	etag, err := p.meta.RetrieveAttribute(nil, bucket, "dir/obj", etagkey)
+	if err != nil || string(etag) != testEtag("hello") {
+		t.Errorf("expected stored etag %v, got %q, %v", testEtag("hello"), etag, err)
+	}
+
+	// nothing changed since the last scan
+	p.watchBuckets(ctx)
+	if got := takeChanges(changes); len(got) != 0 {
+		t.Fatalf("expected no changes, got %+v", got)
+	}
+
+	writeOutside(t, p, bucket, "dir/obj", "hello world", 2*time.Minute)
+	p.watchBuckets(ctx)
+	want = []s3response.ObjectChange{{
+		Bucket:    bucket,
+		Key:       "dir/obj",
+		Size:      11,
+		SizeDelta: 6,
+		ETag:      testEtag("hello world"),
+	}}
+	if got := takeChanges(changes); !reflect.DeepEqual(got, want) {
+		t.Fatalf("modified: expected %+v, got %+v", want, got)
+	}
+
+	err = os.Remove(filepath.Join(p.rootfs.Path(bucket), "dir/obj"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	p.watchBuckets(ctx)
+	want = []s3response.ObjectChange{{
+		Bucket:    bucket,
+		Key:       "dir/obj",
+		Removed:   true,
+		Size:      11,
+		SizeDelta: -11,
+	}}
+	if got := takeChanges(changes); !reflect.DeepEqual(got, want) {
+		t.Fatalf("removed: expected %+v, got %+v", want, got)
+	}
+
+	// the gateway deletes are not reported
+	key := "gateway"
+	_, err = p.DeleteObject(ctx, &s3.DeleteObjectInput{
+		Bucket: &bucket,
+		Key:    &key,
+	})
+	if err != nil {
+		t.Fatalf("delete object: %v", err)
+	}
+	p.watchBuckets(ctx)
+	if got := takeChanges(changes); len(got) != 0 {
+		t.Fatalf("expected no changes for the gateway deletes, got %+v", got)
+	}
+}
+
+func TestWatcher_InitialScan(t *testing.T) {
+	p, changes := newTestWatchPosix(t)
+	ctx := context.Background()
+	bucket := "bucket"
+	createTestBucket(t, p, bucket)
+
+	// the bucket is not seen yet, as at the first start
+	unwatch(t, p, bucket, "")
+	writeOutside(t, p, bucket, "existing", "data", time.Minute)
+
+	p.watchBuckets(ctx)
+	if got := takeChanges(changes); len(got) != 0 {
+		t.Fatalf("expected the existing objects not reported, got %+v", got)
+	}
+	etag, err := p.meta.RetrieveAttribute(nil, bucket, "existing", etagkey)
+	if err != nil || string(etag) != testEtag("data") {
+		t.Errorf("expected stored etag %v, got %q, %v", testEtag("data"), etag, err)
+	}
+
+	// the deleted buckets are not watched anymore
+	err = os.RemoveAll(p.rootfs.Path(bucket))
+	if err != nil {
+		t.Fatal(err)
+	}
+	p.watchBuckets(ctx)
+	if isWatched(t, p, bucket) {
+		t.Errorf("expected the deleted bucket not watched")
+	}
+	if got := takeChanges(changes); len(got) != 0 {
+		t.Fatalf("expected no changes for the deleted bucket, got %+v", got)
+	}
+}
+
+func TestWatcher_Settle(t *testing.T) {
+	p, changes := newTestWatchPosix(t)
+	ctx := context.Background()
+	bucket := "bucket"
+	createTestBucket(t, p, bucket)
+
+	// the object still being written is checked again later
+	writeOutside(t, p, bucket, "obj", "data", 0)
+	p.watchBuckets(ctx)
+	if got := takeChanges(changes); len(got) != 0 {
+		t.Fatalf("expected the unsettled object not reported, got %+v", got)
+	}
+	if _, ok := p.watch.dirty[bucket]["obj"]; !ok {
+		t.Fatalf("expected the unsettled object queued, got %v", p.watch.dirty)
+	}
+
+	writeOutside(t, p, bucket, "obj", "data", time.Minute)
+	p.watchDirty(ctx)
+	want := []s3response.ObjectChange{{
+		Bucket:    bucket,
+		Key:       "obj",
+		Size:      4,
+		SizeDelta: 4,
+		ETag:      testEtag("data"),
+	}}
+	if got := takeChanges(changes); !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %+v, got %+v", want, got)
+	}
+	if len(p.watch.dirty) != 0 {
+		t.Errorf("expected the queued paths processed, got %v", p.watch.dirty)
+	}
+}
+
+func TestWatcher_DirtyPaths(t *testing.T) {
+	p, changes := newTestWatchPosix(t)
+	ctx := context.Background()
+	bucket := "bucket"
+	createTestBucket(t, p, bucket)
+
+	writeOutside(t, p, bucket, "a/x", "x", time.Minute)
+	writeOutside(t, p, bucket, "a/y", "yy", time.Minute)
+	writeOutside(t, p, bucket, "other", "other", time.Minute)
+	p.watchBuckets(ctx)
+	takeChanges(changes)
+
+	// a directory moved within the bucket is reported as the objects
+	// removed from the old directory and created in the new one
+	bucketDir := p.rootfs.Path(bucket)
+	err := os.Rename(filepath.Join(bucketDir, "a"), filepath.Join(bucketDir, "b"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	p.markWatchDirty(bucket, "a")
+	p.markWatchDirty(bucket, "b")
+	p.watchDirty(ctx)
+
+	want := []s3response.ObjectChange{
+		{Bucket: bucket, Key: "a/x", Removed: true, Size: 1, SizeDelta: -1},
+		{Bucket: bucket, Key: "a/y", Removed: true, Size: 2, SizeDelta: -2},
+		{Bucket: bucket, Key: "b/x", Size: 1, SizeDelta: 1, ETag: testEtag("x")},
+		{Bucket: bucket, Key: "b/y", Size: 2, SizeDelta: 2, ETag: testEtag("yy")},
+	}
+	if got := takeChanges(changes); !reflect.DeepEqual(got, want) {
+		t.Fatalf("moved directory: expected %+v, got %+v", want, got)
+	}
+
+	// the removed object path is checked as an object
+	err = os.Remove(filepath.Join(bucketDir, "other"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	p.markWatchDirty(bucket, "other")
+	// the paths of the buckets not scanned yet are left to the scans
+	p.markWatchDirty("unknown", "obj")
+	p.watchDirty(ctx)
+
+	want = []s3response.ObjectChange{
+		{Bucket: bucket, Key: "other", Removed: true, Size: 5, SizeDelta: -5},
+	}
+	if got := takeChanges(changes); !reflect.DeepEqual(got, want) {
+		t.Fatalf("removed object: expected %+v, got %+v", want, got)
+	}
+}
+
+func TestWatcher_Background(t *testing.T) {
+	p := newTestPosix(t, PosixOpts{
+		ListIndexPath: filepath.Join(t.TempDir(), "listindex.db"),
+		WatchInterval: 50 * time.Millisecond,
+	})
+	bucket := "bucket"
+	createTestBucket(t, p, bucket)
+
+	reported := make(chan s3response.ObjectChange, 10)
+	p.SetChangeReporter(func(change s3response.ObjectChange) {
+		reported <- change
+	})
+
+	writeOutside(t, p, bucket, "obj", "data", time.Minute)
+
+	select {
+	case change := <-reported:
+		want := s3response.ObjectChange{
+			Bucket:    bucket,
+			Key:       "obj",
+			Size:      4,
+			SizeDelta: 4,
+			ETag:      testEtag("data"),
+		}
+		if change != want {
+			t.Errorf("expected %+v, got %+v", want, change)
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatalf("the change was not reported")
+	}
+}
+
+func TestWatcher_KeepsGatewaySums(t *testing.T) {
+	p, changes := newTestWatchPosix(t)
+	ctx := context.Background()
+	bucket := "bucket"
+	createTestBucket(t, p, bucket)
+
+	// the multipart etag and the composite checksum of a gateway upload
+	// the watcher has not seen, e.g. completed before it was started
+	putTestObject(t, p, bucket, "upload", "data")
+	err := p.meta.StoreAttribute(nil, bucket, "upload", etagkey, []byte(`"abc-2"`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	crc := "AAAAAA==-2"
+	composite := s3response.Checksum{
+		Type:      types.ChecksumTypeComposite,
+		Algorithm: types.ChecksumAlgorithmCrc32,
+		CRC32:     &crc,
+	}
+	err = p.storeChecksums(nil, bucket, "upload", composite)
+	if err != nil {
+		t.Fatal(err)
+	}
+	unwatch(t, p, bucket, "upload")
+	mtime := time.Now().Add(-time.Minute)
+	err = os.Chtimes(filepath.Join(p.rootfs.Path(bucket), "upload"), mtime, mtime)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the etag of an object without a checksum is kept, and only the
+	// checksum is added
+	writeOutside(t, p, bucket, "etag-only", "hello", time.Minute)
+	err = p.meta.StoreAttribute(nil, bucket, "etag-only", etagkey, []byte(`"custom"`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p.watchBuckets(ctx)
+	want := []s3response.ObjectChange{
+		{Bucket: bucket, Key: "etag-only", Size: 5, SizeDelta: 5, ETag: `"custom"`},
+		{Bucket: bucket, Key: "upload", Size: 4, SizeDelta: 4, ETag: `"abc-2"`},
+	}
+	if got := takeChanges(changes); !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %+v, got %+v", want, got)
+	}
+
+	etag, err := p.meta.RetrieveAttribute(nil, bucket, "upload", etagkey)
+	if err != nil || string(etag) != `"abc-2"` {
+		t.Errorf("expected the multipart etag kept, got %q, %v", etag, err)
+	}
+	checksum, err := p.retrieveChecksums(nil, bucket, "upload")
+	if err != nil || !reflect.DeepEqual(checksum, composite) {
+		t.Errorf("expected the composite checksum kept, got %+v, %v", checksum, err)
+	}
+
+	etag, err = p.meta.RetrieveAttribute(nil, bucket, "etag-only", etagkey)
+	if err != nil || string(etag) != `"custom"` {
+		t.Errorf("expected the etag kept, got %q, %v", etag, err)
+	}
+	checksum, err = p.retrieveChecksums(nil, bucket, "etag-only")
+	if err != nil || checksum.Type != types.ChecksumTypeFullObject || checksum.CRC64NVME == nil {
+		t.Errorf("expected the missing checksum added, got %+v, %v", checksum, err)
+	}
+
+	// the sums of the objects modified outside of the gateway are
+	// calculated from the new data
+	writeOutside(t, p, bucket, "upload", "new data", 2*time.Minute)
+	p.watchBuckets(ctx)
+	want = []s3response.ObjectChange{
+		{Bucket: bucket, Key: "upload", Size: 8, SizeDelta: 4, ETag: testEtag("new data")},
+	}
+	if got := takeChanges(changes); !reflect.DeepEqual(got, want) {
+		t.Fatalf("modified: expected %+v, got %+v", want, got)
+	}
+	checksum, err = p.retrieveChecksums(nil, bucket, "upload")
+	if err != nil || checksum.Type != types.ChecksumTypeFullObject {
+		t.Errorf("expected a full object checksum, got %+v, %v", checksum, err)
+	}
+}
+
+func TestWatcher_PersistentState(t *testing.T) {
+	p, changes := newTestWatchPosix(t)
+	ctx := context.Background()
+	bucket := "bucket"
+	createTestBucket(t, p, bucket)
+
+	writeOutside(t, p, bucket, "obj", "data", time.Minute)
+	p.watchBuckets(ctx)
+	takeChanges(changes)
+
+	// the object states are stored in the database, so the changes
+	// made while the gateway is stopped are found by the next scan
+	state, watched, err := p.getWatchState(bucket, "obj")
+	if err != nil || !watched || state == nil {
+		t.Fatalf("expected the object state stored, got %v, %v, %v", state, watched, err)
+	}
+	writeOutside(t, p, bucket, "obj", "new data", 2*time.Minute)
+	p.watchBuckets(ctx)
+	want := []s3response.ObjectChange{
+		{Bucket: bucket, Key: "obj", Size: 8, SizeDelta: 4, ETag: testEtag("new data")},
+	}
+	if got := takeChanges(changes); !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %+v, got %+v", want, got)
+	}
+
+	// the state of the deleted buckets is removed
+	key := "obj"
+	_, err = p.DeleteObject(ctx, &s3.DeleteObjectInput{
+		Bucket: &bucket,
+		Key:    &key,
+	})
+	if err != nil {
+		t.Fatalf("delete object: %v", err)
+	}
+	err = p.DeleteBucket(ctx, bucket)
+	if err != nil {
+		t.Fatalf("delete bucket: %v", err)
+	}
+	if isWatched(t, p, bucket) {
+		t.Errorf("expected the deleted bucket state removed")
+	}
+}
+
+func TestWatcher_RequiresListIndex(t *testing.T) {
+	_, err := New(t.TempDir(), meta.XattrMeta{}, PosixOpts{WatchInterval: time.Minute})
+	if err == nil {
+		t.Fatalf("expected the watcher without the list index rejected")
+	}
+}
@@ -57,6 +57,11 @@ var (
 func (p *Posix) openTmpFile(dir, bucket, obj string, size int64, acct auth.Account, dofalloc bool, forceNoTmpFile bool) (*tmpfile, error) {
 	uid, gid, doChown := p.getChownIDs(acct)
 
+	dir, bucket, err := p.getTmpFilePaths(dir, bucket, obj)
+	if err != nil {
+		return nil, err
+	}
+
 	if forceNoTmpFile {
 		return p.openMkTemp(dir, bucket, obj, size, dofalloc, uid, gid, doChown)
 	}
@@ -40,8 +40,12 @@ type tmpfile struct {
 func (p *Posix) openTmpFile(dir, bucket, obj string, size int64, acct auth.Account, _ bool, _ bool) (*tmpfile, error) {
 	uid, gid, doChown := p.getChownIDs(acct)
 
+	dir, bucket, err := p.getTmpFilePaths(dir, bucket, obj)
+	if err != nil {
+		return nil, err
+	}
+
 	// Create a temp file for upload while in progress (see link comments below).
-	var err error
 	err = backend.MkdirAll(dir, uid, gid, doChown, p.newDirPerm)
 	if err != nil {
 		if errors.Is(err, syscall.EROFS) {
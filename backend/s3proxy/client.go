@@ -15,10 +15,15 @@
 package s3proxy
 
 import (
+	"container/list"
 	"context"
 	"crypto/tls"
+	"encoding/json"
 	"fmt"
 	"net/http"
+	"os"
+	"sync"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
@@ -26,8 +31,180 @@ import (
 	"github.com/aws/aws-sdk-go-v2/credentials"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
 	"github.com/aws/smithy-go/middleware"
+	"github.com/versity/versitygw/auth"
+	"github.com/versity/versitygw/s3api/utils"
 )
 
+// upstreamCredentials are the upstream s3 service credentials
+// mapped to a gateway account
+type upstreamCredentials struct {
+	Access string `json:"access"`
+	Secret string `json:"secret"`
+}
+
+const (
+	// the upstream clients of the most recently active accounts are
+	// cached, the clients expire so the cache doesn't keep signing
+	// with the credentials of the removed accounts
+	userClientCacheSize = 1024
+	userClientTTL       = 15 * time.Minute
+)
+
+// userClient is a cached upstream client signing with the
+// credentials of a single gateway account
+type userClient struct {
+	access  string
+	secret  string
+	client  *s3.Client
+	expires time.Time
+}
+
+// clientCache is a bounded LRU cache of the upstream clients by
+// upstream access key
+type clientCache struct {
+	mu    sync.Mutex
+	size  int
+	ttl   time.Duration
+	lru   *list.List
+	items map[string]*list.Element
+}
+
+func newClientCache(size int, ttl time.Duration) *clientCache {
+	return &clientCache{
+		size:  size,
+		ttl:   ttl,
+		lru:   list.New(),
+		items: make(map[string]*list.Element),
+	}
+}
+
+// get returns the cached client of the access key, the client is
+// dropped if expired or the account secret has been changed
+func (c *clientCache) get(access, secret string) (*s3.Client, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.items[access]
+	if !ok {
+		return nil, false
+	}
+
+	uc := el.Value.(*userClient)
+	if uc.secret != secret || time.Now().After(uc.expires) {
+		c.lru.Remove(el)
+		delete(c.items, access)
+		return nil, false
+	}
+
+	c.lru.MoveToFront(el)
+	return uc.client, true
+}
+
+// add caches the client, evicting the least recently used clients
+// above the cache size
+func (c *clientCache) add(access, secret string, client *s3.Client) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	uc := &userClient{
+		access:  access,
+		secret:  secret,
+		client:  client,
+		expires: time.Now().Add(c.ttl),
+	}
+
+	if el, ok := c.items[access]; ok {
+		el.Value = uc
+		c.lru.MoveToFront(el)
+		return
+	}
+
+	c.items[access] = c.lru.PushFront(uc)
+	for c.lru.Len() > c.size {
+		el := c.lru.Back()
+		c.lru.Remove(el)
+		delete(c.items, el.Value.(*userClient).access)
+	}
+}
+
+func (c *clientCache) len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.lru.Len()
+}
+
+// parseCredentialMap reads the gateway account access key to upstream
+// credentials mapping from the JSON file
+func parseCredentialMap(path string) (map[string]upstreamCredentials, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read credential map: %w", err)
+	}
+
+	var credMap map[string]upstreamCredentials
+	err = json.Unmarshal(data, &credMap)
+	if err != nil {
+		return nil, fmt.Errorf("parse credential map: %w", err)
+	}
+
+	for access, creds := range credMap {
+		if creds.Access == "" || creds.Secret == "" {
+			return nil, fmt.Errorf("credential map: missing upstream access or secret for %q", access)
+		}
+	}
+
+	return credMap, nil
+}
+
+// getClient returns the client for the upstream requests. In credential
+// passthrough mode the requests are signed with the credentials of the
+// account making the request, either mapped with the credential map or
+// the gateway credentials of the account. Unauthenticated requests are
+// sent anonymously. The meta bucket requests always use the s.client.
+func (s *S3Proxy) getClient(ctx context.Context) *s3.Client {
+	if !s.credentialPassthrough {
+		return s.client
+	}
+
+	acct, ok := utils.ContextKeyAccount.Value(ctx).(auth.Account)
+	if !ok || acct.Access == "" {
+		return s.anonymousClient
+	}
+
+	access, secret := acct.Access, acct.Secret
+	if creds, ok := s.credentialMap[acct.Access]; ok {
+		access, secret = creds.Access, creds.Secret
+	}
+
+	if client, ok := s.userClients.get(access, secret); ok {
+		return client
+	}
+
+	client := s3.New(s.client.Options(), func(o *s3.Options) {
+		o.Credentials = aws.NewCredentialsCache(credentials.NewStaticCredentialsProvider(access, secret, ""))
+	})
+	s.userClients.add(access, secret, client)
+
+	return client
+}
+
+func (s *S3Proxy) initPassthroughClients(credentialMap string) error {
+	if credentialMap != "" {
+		credMap, err := parseCredentialMap(credentialMap)
+		if err != nil {
+			return err
+		}
+		s.credentialMap = credMap
+	}
+
+	s.userClients = newClientCache(userClientCacheSize, userClientTTL)
+	s.anonymousClient = s3.New(s.client.Options(), func(o *s3.Options) {
+		o.Credentials = aws.AnonymousCredentials{}
+	})
+
+	return nil
+}
+
 func (s *S3Proxy) getClientWithCtx(ctx context.Context) (*s3.Client, error) {
 	cfg, err := s.getConfig(ctx, s.access, s.secret)
 	if err != nil {
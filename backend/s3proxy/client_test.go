@@ -0,0 +1,128 @@
+// Copyright 2026 Versity Software
+// This file is licensed under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package s3proxy
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/versity/versitygw/auth"
+	"github.com/versity/versitygw/s3api/utils"
+)
+
+func TestClientCache(t *testing.T) {
+	c := newClientCache(2, time.Hour)
+	a, b, d := &s3.Client{}, &s3.Client{}, &s3.Client{}
+
+	c.add("a", "secret", a)
+	c.add("b", "secret", b)
+
+	if got, ok := c.get("a", "secret"); !ok || got != a {
+		t.Fatal("expected cached client a")
+	}
+
+	// b is the least recently used client
+	c.add("d", "secret", d)
+	if _, ok := c.get("b", "secret"); ok {
+		t.Error("expected client b to be evicted")
+	}
+	if got, ok := c.get("a", "secret"); !ok || got != a {
+		t.Error("expected client a to be kept")
+	}
+	if c.len() != 2 {
+		t.Errorf("expected 2 cached clients, got %v", c.len())
+	}
+
+	// a changed account secret drops the client
+	if _, ok := c.get("a", "changed"); ok {
+		t.Error("expected no client for the changed secret")
+	}
+	if _, ok := c.get("a", "secret"); ok {
+		t.Error("expected the client of the old secret to be dropped")
+	}
+}
+
+func TestClientCache_Expire(t *testing.T) {
+	c := newClientCache(10, time.Millisecond)
+	c.add("a", "secret", &s3.Client{})
+
+	time.Sleep(5 * time.Millisecond)
+	if _, ok := c.get("a", "secret"); ok {
+		t.Error("expected the client to expire")
+	}
+	if c.len() != 0 {
+		t.Errorf("expected the expired client to be removed, got %v cached", c.len())
+	}
+}
+
+func TestGetClient_Passthrough(t *testing.T) {
+	f, endpoint := newFakeS3(t, "bucket")
+
+	credMap := filepath.Join(t.TempDir(), "credentials.json")
+	err := os.WriteFile(credMap, []byte(`{"mapped":{"access":"upstream","secret":"upstream-secret"}}`), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := newTestProxy(t, endpoint, Options{
+		CredentialPassthrough: true,
+		CredentialMap:         credMap,
+	})
+
+	withAccount := func(acct auth.Account) context.Context {
+		return context.WithValue(context.Background(), string(utils.ContextKeyAccount), acct)
+	}
+
+	tests := []struct {
+		name   string
+		ctx    context.Context
+		access string
+	}{
+		{"anonymous", context.Background(), ""},
+		{"account", withAccount(auth.Account{Access: "user", Secret: "user-secret"}), "user"},
+		{"mapped", withAccount(auth.Account{Access: "mapped", Secret: "gateway-secret"}), "upstream"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f.mu.Lock()
+			f.access = nil
+			f.mu.Unlock()
+
+			_, err := s.getClient(tt.ctx).HeadBucket(tt.ctx, &s3.HeadBucketInput{Bucket: ptr("bucket")})
+			if err != nil {
+				t.Fatalf("head bucket: %v", err)
+			}
+
+			f.mu.Lock()
+			defer f.mu.Unlock()
+			if len(f.access) != 1 || f.access[0] != tt.access {
+				t.Errorf("expected request signed by %q, got %q", tt.access, f.access)
+			}
+		})
+	}
+
+	ctx := withAccount(auth.Account{Access: "user", Secret: "user-secret"})
+	if s.getClient(ctx) != s.getClient(ctx) {
+		t.Error("expected the account client to be cached")
+	}
+	if s.userClients.len() != 2 {
+		t.Errorf("expected 2 cached clients, got %v", s.userClients.len())
+	}
+}
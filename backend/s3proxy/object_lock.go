@@ -0,0 +1,335 @@
+// Copyright 2023 Versity Software
+// This file is licensed under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package s3proxy
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/aws/smithy-go"
+	"github.com/versity/versitygw/auth"
+	"github.com/versity/versitygw/s3err"
+)
+
+// The object lock fallback mode is used with upstream services that
+// don't support object lock. The bucket lock configuration, object
+// retention and legal hold are stored in the meta bucket instead, and
+// the gateway enforces the lock semantics based on them.
+
+const (
+	// the object lock metadata of the objects is stored under
+	// <prefix><bucket>/<version id>/<object>
+	metaPrefixRetention metaPrefix = "vgw-meta-retention-"
+	metaPrefixLegalHold metaPrefix = "vgw-meta-legal-hold-"
+	// the lock settings requested on multipart upload creation are
+	// stored until the upload is completed
+	metaPrefixMpLock metaPrefix = "vgw-meta-mp-lock-"
+
+	nullVersionId = "null"
+
+	legalHoldOn  = "1"
+	legalHoldOff = "0"
+)
+
+// objectLockSettings are the object lock request headers of the
+// object uploads
+type objectLockSettings struct {
+	Mode            types.ObjectLockMode
+	RetainUntilDate *time.Time
+	LegalHold       types.ObjectLockLegalHoldStatus
+}
+
+func (o objectLockSettings) isSet() bool {
+	return o.Mode != "" || o.LegalHold == types.ObjectLockLegalHoldStatusOn
+}
+
+// getObjectMetaKey generates the meta object key of the object version
+func getObjectMetaKey(bucket, object, versionId string, prefix metaPrefix) string {
+	if versionId == "" {
+		versionId = nullVersionId
+	}
+	return fmt.Sprintf("%s%s/%s/%s", prefix, bucket, versionId, object)
+}
+
+func (s *S3Proxy) putMetaObj(ctx context.Context, key string, data []byte) error {
+	_, err := s.client.PutObject(ctx, &s3.PutObjectInput{
+		Bucket: &s.metaBucket,
+		Key:    &key,
+		Body:   bytes.NewReader(data),
+	})
+	return err
+}
+
+// getMetaObj returns the meta object data, or nil if the
+// meta object doesn't exist
+func (s *S3Proxy) getMetaObj(ctx context.Context, key string) ([]byte, error) {
+	res, err := s.client.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: &s.metaBucket,
+		Key:    &key,
+	})
+	if areErrSame(err, s3err.GetAPIError(s3err.ErrNoSuchKey)) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	data, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, fmt.Errorf("read meta object data: %w", err)
+	}
+
+	return data, nil
+}
+
+func (s *S3Proxy) deleteMetaObj(ctx context.Context, key string) error {
+	_, err := s.client.DeleteObject(ctx, &s3.DeleteObjectInput{
+		Bucket: &s.metaBucket,
+		Key:    &key,
+	})
+	if err != nil && !areErrSame(err, s3err.GetAPIError(s3err.ErrNoSuchKey)) {
+		return err
+	}
+	return nil
+}
+
+// isBucketObjectLockEnabled checks the object lock configuration
+// stored in the meta bucket
+func (s *S3Proxy) isBucketObjectLockEnabled(ctx context.Context, bucket string) error {
+	data, err := s.getMetaObj(ctx, getMetaKey(bucket, metaPrefixObjectLock))
+	if err != nil {
+		return handleError(err)
+	}
+	if len(data) == 0 {
+		return s3err.GetAPIError(s3err.ErrMissingObjectLockConfiguration)
+	}
+
+	var config auth.BucketLockConfig
+	err = json.Unmarshal(data, &config)
+	if err != nil {
+		return fmt.Errorf("parse bucket lock config: %w", err)
+	}
+
+	if !config.Enabled {
+		return s3err.GetAPIError(s3err.ErrMissingObjectLockConfiguration)
+	}
+
+	return nil
+}
+
+// resolveObjectVersion checks that the object exists upstream and
+// returns the version id the object lock metadata is stored under
+func (s *S3Proxy) resolveObjectVersion(ctx context.Context, bucket, object, versionId string) (string, error) {
+	input := &s3.HeadObjectInput{
+		Bucket: &bucket,
+		Key:    &object,
+	}
+	if versionId != "" && versionId != nullVersionId {
+		input.VersionId = &versionId
+	}
+
+	out, err := s.getClient(ctx).HeadObject(ctx, input)
+	if err != nil {
+		if isNotFoundErr(err) {
+			if input.VersionId != nil {
+				return "", s3err.GetAPIError(s3err.ErrNoSuchVersion)
+			}
+			return "", s3err.GetAPIError(s3err.ErrNoSuchKey)
+		}
+		return "", handleError(err)
+	}
+
+	if out.VersionId != nil && *out.VersionId != "" {
+		return *out.VersionId, nil
+	}
+	return nullVersionId, nil
+}
+
+func (s *S3Proxy) putRetentionFallback(ctx context.Context, bucket, object, versionId string, retention []byte) error {
+	err := s.isBucketObjectLockEnabled(ctx, bucket)
+	if err != nil {
+		return err
+	}
+
+	versionId, err = s.resolveObjectVersion(ctx, bucket, object, versionId)
+	if err != nil {
+		return err
+	}
+
+	return handleError(s.putMetaObj(ctx, getObjectMetaKey(bucket, object, versionId, metaPrefixRetention), retention))
+}
+
+func (s *S3Proxy) getRetentionFallback(ctx context.Context, bucket, object, versionId string) ([]byte, error) {
+	versionId, err := s.resolveObjectVersion(ctx, bucket, object, versionId)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.isBucketObjectLockEnabled(ctx, bucket)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := s.getMetaObj(ctx, getObjectMetaKey(bucket, object, versionId, metaPrefixRetention))
+	if err != nil {
+		return nil, handleError(err)
+	}
+	if len(data) == 0 {
+		return nil, s3err.GetAPIError(s3err.ErrNoSuchObjectLockConfiguration)
+	}
+
+	return data, nil
+}
+
+func (s *S3Proxy) putLegalHoldFallback(ctx context.Context, bucket, object, versionId string, status bool) error {
+	err := s.isBucketObjectLockEnabled(ctx, bucket)
+	if err != nil {
+		return err
+	}
+
+	versionId, err = s.resolveObjectVersion(ctx, bucket, object, versionId)
+	if err != nil {
+		return err
+	}
+
+	data := legalHoldOff
+	if status {
+		data = legalHoldOn
+	}
+
+	return handleError(s.putMetaObj(ctx, getObjectMetaKey(bucket, object, versionId, metaPrefixLegalHold), []byte(data)))
+}
+
+func (s *S3Proxy) getLegalHoldFallback(ctx context.Context, bucket, object, versionId string) (*bool, error) {
+	versionId, err := s.resolveObjectVersion(ctx, bucket, object, versionId)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.isBucketObjectLockEnabled(ctx, bucket)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := s.getMetaObj(ctx, getObjectMetaKey(bucket, object, versionId, metaPrefixLegalHold))
+	if err != nil {
+		return nil, handleError(err)
+	}
+	if len(data) == 0 {
+		return nil, s3err.GetAPIError(s3err.ErrNoSuchObjectLockConfiguration)
+	}
+
+	status := string(data) == legalHoldOn
+	return &status, nil
+}
+
+// applyObjectLockSettings stores the object lock settings of a new
+// object version in the meta bucket
+func (s *S3Proxy) applyObjectLockSettings(ctx context.Context, bucket, object, versionId string, settings objectLockSettings) error {
+	if settings.LegalHold == types.ObjectLockLegalHoldStatusOn {
+		err := s.putMetaObj(ctx, getObjectMetaKey(bucket, object, versionId, metaPrefixLegalHold), []byte(legalHoldOn))
+		if err != nil {
+			return handleError(err)
+		}
+	}
+
+	if settings.Mode != "" {
+		retention, err := json.Marshal(types.ObjectLockRetention{
+			Mode:            types.ObjectLockRetentionMode(settings.Mode),
+			RetainUntilDate: settings.RetainUntilDate,
+		})
+		if err != nil {
+			return fmt.Errorf("parse object lock retention: %w", err)
+		}
+		err = s.putMetaObj(ctx, getObjectMetaKey(bucket, object, versionId, metaPrefixRetention), retention)
+		if err != nil {
+			return handleError(err)
+		}
+	}
+
+	return nil
+}
+
+// checkObjectLockSettings validates that the object lock settings
+// requested on upload can be applied to the bucket
+func (s *S3Proxy) checkObjectLockSettings(ctx context.Context, bucket string, settings objectLockSettings) error {
+	if !settings.isSet() {
+		return nil
+	}
+
+	err := s.isBucketObjectLockEnabled(ctx, bucket)
+	if errors.Is(err, s3err.GetAPIError(s3err.ErrMissingObjectLockConfiguration)) {
+		return s3err.GetAPIError(s3err.ErrMissingObjectLockConfigurationNoSpaces)
+	}
+	return err
+}
+
+// removeObjectLockMeta removes the object lock metadata of the
+// deleted object version
+func (s *S3Proxy) removeObjectLockMeta(ctx context.Context, bucket, object, versionId string) {
+	// the object lock metadata is best effort cleaned up, as the
+	// object is already gone
+	_ = s.deleteMetaObj(ctx, getObjectMetaKey(bucket, object, versionId, metaPrefixRetention))
+	_ = s.deleteMetaObj(ctx, getObjectMetaKey(bucket, object, versionId, metaPrefixLegalHold))
+}
+
+func (s *S3Proxy) storeMpLockSettings(ctx context.Context, uploadId string, settings objectLockSettings) error {
+	data, err := json.Marshal(settings)
+	if err != nil {
+		return fmt.Errorf("parse object lock settings: %w", err)
+	}
+	return handleError(s.putMetaObj(ctx, string(metaPrefixMpLock)+uploadId, data))
+}
+
+// popMpLockSettings returns and removes the object lock settings
+// of the multipart upload
+func (s *S3Proxy) popMpLockSettings(ctx context.Context, uploadId string) (objectLockSettings, error) {
+	key := string(metaPrefixMpLock) + uploadId
+	data, err := s.getMetaObj(ctx, key)
+	if err != nil {
+		return objectLockSettings{}, handleError(err)
+	}
+	if len(data) == 0 {
+		return objectLockSettings{}, nil
+	}
+
+	var settings objectLockSettings
+	err = json.Unmarshal(data, &settings)
+	if err != nil {
+		return objectLockSettings{}, fmt.Errorf("parse object lock settings: %w", err)
+	}
+
+	return settings, handleError(s.deleteMetaObj(ctx, key))
+}
+
+func isNotFoundErr(err error) bool {
+	var nf *types.NotFound
+	if errors.As(err, &nf) {
+		return true
+	}
+	var ae smithy.APIError
+	if errors.As(err, &ae) {
+		return ae.ErrorCode() == "NotFound" || ae.ErrorCode() == "NoSuchKey" || ae.ErrorCode() == "NoSuchVersion"
+	}
+	return false
+}
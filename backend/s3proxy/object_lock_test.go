@@ -0,0 +1,348 @@
+// Copyright 2026 Versity Software
+// This file is licensed under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package s3proxy
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/versity/versitygw/auth"
+	"github.com/versity/versitygw/s3err"
+	"github.com/versity/versitygw/s3response"
+)
+
+const testMetaBucket = "meta"
+
+// fakeS3 is a minimal unversioned path style s3 service without
+// object lock support
+type fakeS3 struct {
+	mu      sync.Mutex
+	buckets map[string]map[string][]byte
+	// access records the access key id of each request
+	access []string
+}
+
+func newFakeS3(t *testing.T, buckets ...string) (*fakeS3, string) {
+	t.Helper()
+
+	f := &fakeS3{buckets: map[string]map[string][]byte{}}
+	for _, b := range buckets {
+		f.buckets[b] = map[string][]byte{}
+	}
+
+	srv := httptest.NewServer(f)
+	t.Cleanup(srv.Close)
+	return f, srv.URL
+}
+
+func writeFakeS3Err(w http.ResponseWriter, r *http.Request, status int, code string) {
+	w.WriteHeader(status)
+	if r.Method != http.MethodHead {
+		fmt.Fprintf(w, "<Error><Code>%v</Code><Message>%v</Message></Error>", code, code)
+	}
+}
+
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	// Authorization: AWS4-HMAC-SHA256 Credential=<access>/...
+	cred, _, _ := strings.Cut(strings.TrimPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential="), "/")
+	f.access = append(f.access, cred)
+
+	bucket, key, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/"), "/")
+	objects, ok := f.buckets[bucket]
+	if !ok {
+		writeFakeS3Err(w, r, http.StatusNotFound, "NoSuchBucket")
+		return
+	}
+
+	if key == "" {
+		switch {
+		case r.Method == http.MethodHead:
+		case r.Method == http.MethodGet && r.URL.Query().Has("versioning"):
+			fmt.Fprint(w, `<VersioningConfiguration xmlns="http://s3.amazonaws.com/doc/2006-03-01/"></VersioningConfiguration>`)
+		case r.Method == http.MethodDelete:
+			if len(objects) > 0 {
+				writeFakeS3Err(w, r, http.StatusConflict, "BucketNotEmpty")
+				return
+			}
+			delete(f.buckets, bucket)
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			writeFakeS3Err(w, r, http.StatusNotImplemented, "NotImplemented")
+		}
+		return
+	}
+
+	switch r.Method {
+	case http.MethodPut:
+		data, err := io.ReadAll(r.Body)
+		if err != nil {
+			writeFakeS3Err(w, r, http.StatusBadRequest, "IncompleteBody")
+			return
+		}
+		objects[key] = data
+		w.Header().Set("ETag", `"etag"`)
+	case http.MethodGet, http.MethodHead:
+		data, ok := objects[key]
+		if !ok {
+			writeFakeS3Err(w, r, http.StatusNotFound, "NoSuchKey")
+			return
+		}
+		w.Header().Set("ETag", `"etag"`)
+		w.Header().Set("Content-Length", fmt.Sprint(len(data)))
+		if r.Method == http.MethodGet {
+			w.Write(data)
+		}
+	case http.MethodDelete:
+		delete(objects, key)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		writeFakeS3Err(w, r, http.StatusNotImplemented, "NotImplemented")
+	}
+}
+
+func (f *fakeS3) object(bucket, key string) ([]byte, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	data, ok := f.buckets[bucket][key]
+	return data, ok
+}
+
+func newTestProxy(t *testing.T, endpoint string, opts Options) *S3Proxy {
+	t.Helper()
+
+	// keep the environment out of the aws config
+	t.Setenv("AWS_CA_BUNDLE", "")
+	t.Setenv("AWS_CONFIG_FILE", t.TempDir()+"/config")
+	t.Setenv("AWS_SHARED_CREDENTIALS_FILE", t.TempDir()+"/credentials")
+
+	opts.Access, opts.Secret = "access", "secret"
+	opts.Endpoint = endpoint
+	opts.Region = "us-east-1"
+	opts.UsePathStyle = true
+	opts.DisableDataIntegrityCheck = true
+	s, err := New(context.Background(), opts)
+	if err != nil {
+		t.Fatalf("init s3 proxy: %v", err)
+	}
+	return s
+}
+
+func putTestObject(t *testing.T, s *S3Proxy, bucket, key string, input s3response.PutObjectInput) error {
+	t.Helper()
+
+	size := int64(4)
+	input.Bucket = &bucket
+	input.Key = &key
+	input.ContentLength = &size
+	input.Body = strings.NewReader("data")
+	_, err := s.PutObject(context.Background(), input)
+	return err
+}
+
+func enableObjectLock(t *testing.T, s *S3Proxy, bucket string) {
+	t.Helper()
+
+	config, err := json.Marshal(auth.BucketLockConfig{Enabled: true})
+	if err != nil {
+		t.Fatalf("marshal lock config: %v", err)
+	}
+	err = s.PutObjectLockConfiguration(context.Background(), bucket, config)
+	if err != nil {
+		t.Fatalf("put object lock configuration: %v", err)
+	}
+}
+
+func TestNew_ObjectLockFallbackRequiresMetaBucket(t *testing.T) {
+	_, err := New(context.Background(), Options{ObjectLockFallback: true})
+	if err == nil {
+		t.Error("expected object lock fallback without meta bucket to fail")
+	}
+}
+
+func TestObjectLockFallback_Configuration(t *testing.T) {
+	f, endpoint := newFakeS3(t, testMetaBucket, "bucket")
+	s := newTestProxy(t, endpoint, Options{MetaBucket: testMetaBucket, ObjectLockFallback: true})
+	ctx := context.Background()
+
+	err := s.PutObjectLockConfiguration(ctx, "missing", []byte("{}"))
+	if !errors.Is(err, s3err.GetAPIError(s3err.ErrNoSuchBucket)) {
+		t.Errorf("expected ErrNoSuchBucket, got %v", err)
+	}
+
+	enableObjectLock(t, s, "bucket")
+	if _, ok := f.object(testMetaBucket, getMetaKey("bucket", metaPrefixObjectLock)); !ok {
+		t.Fatal("expected the lock configuration in the meta bucket")
+	}
+
+	data, err := s.GetObjectLockConfiguration(ctx, "bucket")
+	if err != nil {
+		t.Fatalf("get object lock configuration: %v", err)
+	}
+	var config auth.BucketLockConfig
+	if err := json.Unmarshal(data, &config); err != nil || !config.Enabled {
+		t.Errorf("expected enabled lock configuration, got %s %v", data, err)
+	}
+
+	// a new bucket with the same name doesn't inherit the lock
+	err = s.DeleteBucket(ctx, "bucket")
+	if err != nil {
+		t.Fatalf("delete bucket: %v", err)
+	}
+	if _, ok := f.object(testMetaBucket, getMetaKey("bucket", metaPrefixObjectLock)); ok {
+		t.Error("expected the lock configuration to be removed with the bucket")
+	}
+}
+
+func TestObjectLockFallback_Retention(t *testing.T) {
+	f, endpoint := newFakeS3(t, testMetaBucket, "bucket", "nolock")
+	s := newTestProxy(t, endpoint, Options{MetaBucket: testMetaBucket, ObjectLockFallback: true})
+	ctx := context.Background()
+
+	if err := putTestObject(t, s, "nolock", "obj", s3response.PutObjectInput{}); err != nil {
+		t.Fatalf("put object: %v", err)
+	}
+	err := s.PutObjectRetention(ctx, "nolock", "obj", "", []byte("{}"))
+	if !errors.Is(err, s3err.GetAPIError(s3err.ErrMissingObjectLockConfiguration)) {
+		t.Errorf("expected ErrMissingObjectLockConfiguration, got %v", err)
+	}
+
+	enableObjectLock(t, s, "bucket")
+
+	err = s.PutObjectRetention(ctx, "bucket", "missing", "", []byte("{}"))
+	if !errors.Is(err, s3err.GetAPIError(s3err.ErrNoSuchKey)) {
+		t.Errorf("expected ErrNoSuchKey, got %v", err)
+	}
+
+	if err := putTestObject(t, s, "bucket", "obj", s3response.PutObjectInput{}); err != nil {
+		t.Fatalf("put object: %v", err)
+	}
+	_, err = s.GetObjectRetention(ctx, "bucket", "obj", "")
+	if !errors.Is(err, s3err.GetAPIError(s3err.ErrNoSuchObjectLockConfiguration)) {
+		t.Errorf("expected ErrNoSuchObjectLockConfiguration, got %v", err)
+	}
+
+	until := time.Now().Add(time.Hour).UTC().Truncate(time.Second)
+	retention, err := json.Marshal(types.ObjectLockRetention{
+		Mode:            types.ObjectLockRetentionModeGovernance,
+		RetainUntilDate: &until,
+	})
+	if err != nil {
+		t.Fatalf("marshal retention: %v", err)
+	}
+	err = s.PutObjectRetention(ctx, "bucket", "obj", "", retention)
+	if err != nil {
+		t.Fatalf("put object retention: %v", err)
+	}
+
+	// unversioned objects are stored under the null version
+	key := getObjectMetaKey("bucket", "obj", "", metaPrefixRetention)
+	if _, ok := f.object(testMetaBucket, key); !ok {
+		t.Fatalf("expected retention meta object %v", key)
+	}
+
+	data, err := s.GetObjectRetention(ctx, "bucket", "obj", nullVersionId)
+	if err != nil {
+		t.Fatalf("get object retention: %v", err)
+	}
+	if string(data) != string(retention) {
+		t.Errorf("expected retention %s, got %s", retention, data)
+	}
+
+	// the retention is removed with the object
+	_, err = s.DeleteObject(ctx, &s3.DeleteObjectInput{
+		Bucket: ptr("bucket"),
+		Key:    ptr("obj"),
+	})
+	if err != nil {
+		t.Fatalf("delete object: %v", err)
+	}
+	if _, ok := f.object(testMetaBucket, key); ok {
+		t.Error("expected the retention to be removed with the object")
+	}
+}
+
+func TestObjectLockFallback_LegalHold(t *testing.T) {
+	_, endpoint := newFakeS3(t, testMetaBucket, "bucket")
+	s := newTestProxy(t, endpoint, Options{MetaBucket: testMetaBucket, ObjectLockFallback: true})
+	ctx := context.Background()
+
+	enableObjectLock(t, s, "bucket")
+	if err := putTestObject(t, s, "bucket", "obj", s3response.PutObjectInput{}); err != nil {
+		t.Fatalf("put object: %v", err)
+	}
+
+	for _, status := range []bool{true, false} {
+		err := s.PutObjectLegalHold(ctx, "bucket", "obj", "", status)
+		if err != nil {
+			t.Fatalf("put legal hold %v: %v", status, err)
+		}
+		got, err := s.GetObjectLegalHold(ctx, "bucket", "obj", "")
+		if err != nil {
+			t.Fatalf("get legal hold: %v", err)
+		}
+		if got == nil || *got != status {
+			t.Errorf("expected legal hold %v, got %v", status, got)
+		}
+	}
+}
+
+func TestObjectLockFallback_PutObject(t *testing.T) {
+	f, endpoint := newFakeS3(t, testMetaBucket, "bucket", "nolock")
+	s := newTestProxy(t, endpoint, Options{MetaBucket: testMetaBucket, ObjectLockFallback: true})
+	ctx := context.Background()
+
+	lock := s3response.PutObjectInput{
+		ObjectLockLegalHoldStatus: types.ObjectLockLegalHoldStatusOn,
+	}
+
+	err := putTestObject(t, s, "nolock", "obj", lock)
+	if !errors.Is(err, s3err.GetAPIError(s3err.ErrMissingObjectLockConfigurationNoSpaces)) {
+		t.Errorf("expected ErrMissingObjectLockConfigurationNoSpaces, got %v", err)
+	}
+	if _, ok := f.object("nolock", "obj"); ok {
+		t.Error("expected the object not to be uploaded")
+	}
+
+	enableObjectLock(t, s, "bucket")
+	if err := putTestObject(t, s, "bucket", "obj", lock); err != nil {
+		t.Fatalf("put object: %v", err)
+	}
+
+	status, err := s.GetObjectLegalHold(ctx, "bucket", "obj", "")
+	if err != nil {
+		t.Fatalf("get legal hold: %v", err)
+	}
+	if status == nil || !*status {
+		t.Error("expected the legal hold of the upload to be stored")
+	}
+}
+
+func ptr(s string) *string {
+	return &s
+}
@@ -17,10 +17,13 @@ package s3proxy
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
+	"net/http"
 	"strconv"
+	"strings"
 	"time"
 
 	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
@@ -30,6 +33,7 @@ import (
 	"github.com/aws/smithy-go"
 	"github.com/versity/versitygw/auth"
 	"github.com/versity/versitygw/backend"
+	"github.com/versity/versitygw/s3api/utils"
 	"github.com/versity/versitygw/s3err"
 	"github.com/versity/versitygw/s3response"
 )
@@ -40,6 +44,10 @@ const (
 	metaPrefixAcl    metaPrefix = "vgw-meta-acl-"
 	metaPrefixPolicy metaPrefix = "vgw-meta-policy-"
 	metaPrefixCors   metaPrefix = "vgw-meta-cors-"
+	// bucket Block Public Access settings
+	metaPrefixPublicAccessBlock metaPrefix = "vgw-meta-public-access-block-"
+	// object lock configuration, only used in object lock fallback mode
+	metaPrefixObjectLock metaPrefix = "vgw-meta-object-lock-"
 )
 
 type S3Proxy struct {
@@ -57,7 +65,16 @@ type S3Proxy struct {
 	disableDataIntegrityCheck bool
 	sslSkipVerify             bool
 	usePathStyle              bool
-	debug                     bool
+	// objectLockFallback enables the object lock emulation with the
+	// meta bucket for upstream services without object lock support
+	objectLockFallback bool
+	// credentialPassthrough signs the upstream requests with the
+	// credentials of the requesting account
+	credentialPassthrough bool
+	credentialMap         map[string]upstreamCredentials
+	userClients           *clientCache
+	anonymousClient       *s3.Client
+	debug                 bool
 }
 
 var _ backend.Backend = &S3Proxy{}
@@ -70,25 +87,77 @@ func NewWithClient(ctx context.Context, client *s3.Client, metaBucket string) (*
 	return s, s.validate(ctx)
 }
 
-func New(ctx context.Context, access, secret, endpoint, region, metaBucket string, anonymousCredentials, disableChecksum, disableDataIntegrityCheck, sslSkipVerify, usePathStyle, debug bool) (*S3Proxy, error) {
+// Options configures the s3 proxy backend
+type Options struct {
+	// Access and Secret are the s3 service credentials, the AWS
+	// default credential chain is used when not set
+	Access string
+	Secret string
+	// Endpoint is the s3 service endpoint, AWS if not set
+	Endpoint string
+	// Region is the s3 service region, 'us-east-1' if not set
+	Region string
+	// MetaBucket stores the bucket acls and policies
+	MetaBucket string
+	// CredentialMap is the JSON file mapping the gateway access keys
+	// to s3 service credentials for the credential passthrough
+	CredentialMap string
+	// AnonymousCredentials forces anonymous credentials instead of
+	// the AWS default credential chain
+	AnonymousCredentials bool
+	// DisableChecksum disables the gateway to server object checksums
+	DisableChecksum bool
+	// DisableDataIntegrityCheck only calculates the request checksums
+	// when required by the operation
+	DisableDataIntegrityCheck bool
+	// SslSkipVerify skips the s3 service certificate verification
+	SslSkipVerify bool
+	// UsePathStyle uses path style addressing for the s3 service
+	UsePathStyle bool
+	// ObjectLockFallback stores and enforces the object lock in the
+	// gateway with the meta bucket
+	ObjectLockFallback bool
+	// CredentialPassthrough signs the s3 service requests with the
+	// credentials of the requesting account
+	CredentialPassthrough bool
+	// Debug outputs extra debug tracing
+	Debug bool
+}
+
+func New(ctx context.Context, opts Options) (*S3Proxy, error) {
+	if opts.ObjectLockFallback && opts.MetaBucket == "" {
+		return nil, fmt.Errorf("object lock fallback mode requires a meta bucket")
+	}
+	if opts.CredentialMap != "" && !opts.CredentialPassthrough {
+		return nil, fmt.Errorf("credential map requires credential passthrough mode")
+	}
+
 	s := &S3Proxy{
-		access:                    access,
-		secret:                    secret,
-		anonymousCredentials:      anonymousCredentials,
-		endpoint:                  endpoint,
-		awsRegion:                 region,
-		metaBucket:                metaBucket,
-		disableChecksum:           disableChecksum,
-		disableDataIntegrityCheck: disableDataIntegrityCheck,
-		sslSkipVerify:             sslSkipVerify,
-		usePathStyle:              usePathStyle,
-		debug:                     debug,
+		access:                    opts.Access,
+		secret:                    opts.Secret,
+		anonymousCredentials:      opts.AnonymousCredentials,
+		endpoint:                  opts.Endpoint,
+		awsRegion:                 opts.Region,
+		metaBucket:                opts.MetaBucket,
+		disableChecksum:           opts.DisableChecksum,
+		disableDataIntegrityCheck: opts.DisableDataIntegrityCheck,
+		sslSkipVerify:             opts.SslSkipVerify,
+		usePathStyle:              opts.UsePathStyle,
+		objectLockFallback:        opts.ObjectLockFallback,
+		credentialPassthrough:     opts.CredentialPassthrough,
+		debug:                     opts.Debug,
 	}
 	client, err := s.getClientWithCtx(ctx)
 	if err != nil {
 		return nil, err
 	}
 	s.client = client
+	if opts.CredentialPassthrough {
+		err = s.initPassthroughClients(opts.CredentialMap)
+		if err != nil {
+			return nil, err
+		}
+	}
 	return s, s.validate(ctx)
 }
 
@@ -99,8 +168,38 @@ func (s *S3Proxy) validate(ctx context.Context) error {
 	return nil
 }
 
+var _ backend.HealthChecker = &S3Proxy{}
+
+// CheckHealth checks the upstream service is reachable with the meta
+// bucket if configured, or by listing a single bucket otherwise. Without
+// the gateway credentials (anonymous or passthrough modes), any upstream
+// response other than a server error is healthy.
+func (s *S3Proxy) CheckHealth(ctx context.Context) error {
+	var err error
+	if s.metaBucket != "" {
+		_, err = s.client.HeadBucket(ctx, &s3.HeadBucketInput{
+			Bucket: &s.metaBucket,
+		})
+	} else {
+		maxBuckets := int32(1)
+		_, err = s.client.ListBuckets(ctx, &s3.ListBucketsInput{
+			MaxBuckets: &maxBuckets,
+		})
+	}
+	if err == nil {
+		return nil
+	}
+
+	var re *awshttp.ResponseError
+	if (s.anonymousCredentials || s.credentialPassthrough) &&
+		errors.As(err, &re) && re.HTTPStatusCode() < http.StatusInternalServerError {
+		return nil
+	}
+	return fmt.Errorf("upstream %v: %w", s.endpoint, err)
+}
+
 func (s *S3Proxy) ListBuckets(ctx context.Context, input s3response.ListBucketsInput) (s3response.ListAllMyBucketsResult, error) {
-	output, err := s.client.ListBuckets(ctx, &s3.ListBucketsInput{
+	output, err := s.getClient(ctx).ListBuckets(ctx, &s3.ListBucketsInput{
 		ContinuationToken: &input.ContinuationToken,
 		MaxBuckets:        &input.MaxBuckets,
 		Prefix:            &input.Prefix,
@@ -156,7 +255,7 @@ func (s *S3Proxy) HeadBucket(ctx context.Context, input *s3.HeadBucketInput) (*s
 	if input.ExpectedBucketOwner != nil && *input.ExpectedBucketOwner == "" {
 		input.ExpectedBucketOwner = nil
 	}
-	out, err := s.client.HeadBucket(ctx, input)
+	out, err := s.getClient(ctx).HeadBucket(ctx, input)
 	return out, handleError(err)
 }
 
@@ -180,7 +279,7 @@ func (s *S3Proxy) CreateBucket(ctx context.Context, input *s3.CreateBucketInput,
 		return s3err.GetAPIError(s3err.ErrBucketAlreadyExists)
 	}
 
-	acct, ok := ctx.Value("account").(auth.Account)
+	acct, ok := utils.ContextKeyAccount.Value(ctx).(auth.Account)
 	if !ok {
 		acct = auth.Account{}
 	}
@@ -200,7 +299,12 @@ func (s *S3Proxy) CreateBucket(ctx context.Context, input *s3.CreateBucketInput,
 		}
 	}
 
-	_, err := s.client.CreateBucket(ctx, input)
+	lockEnabled := input.ObjectLockEnabledForBucket != nil && *input.ObjectLockEnabledForBucket
+	if !lockEnabled || s.objectLockFallback {
+		input.ObjectLockEnabledForBucket = nil
+	}
+
+	_, err := s.getClient(ctx).CreateBucket(ctx, input)
 	if err != nil {
 		return handleError(err)
 	}
@@ -215,6 +319,23 @@ func (s *S3Proxy) CreateBucket(ctx context.Context, input *s3.CreateBucketInput,
 		}
 	}
 
+	if lockEnabled && s.objectLockFallback {
+		now := time.Now()
+		config, err := json.Marshal(auth.BucketLockConfig{
+			Enabled:   true,
+			CreatedAt: &now,
+		})
+		if err != nil {
+			return fmt.Errorf("parse object lock config: %w", err)
+		}
+		err = s.putMetaBucketObj(ctx, *input.Bucket, config, metaPrefixObjectLock)
+		if err != nil {
+			// attempt to cleanup
+			_ = s.DeleteBucket(ctx, *input.Bucket)
+			return handleError(err)
+		}
+	}
+
 	return nil
 }
 
@@ -222,17 +343,26 @@ func (s *S3Proxy) DeleteBucket(ctx context.Context, bucket string) error {
 	if bucket == s.metaBucket {
 		return s3err.GetAPIError(s3err.ErrAccessDenied)
 	}
-	_, err := s.client.DeleteBucket(ctx, &s3.DeleteBucketInput{
+	_, err := s.getClient(ctx).DeleteBucket(ctx, &s3.DeleteBucketInput{
 		Bucket: &bucket,
 	})
-	return handleError(err)
+	if err != nil {
+		return handleError(err)
+	}
+
+	if s.objectLockFallback {
+		// a new bucket with the same name must not inherit the lock
+		_ = s.deleteMetaObj(ctx, getMetaKey(bucket, metaPrefixObjectLock))
+	}
+
+	return nil
 }
 
 func (s *S3Proxy) PutBucketOwnershipControls(ctx context.Context, bucket string, ownership types.ObjectOwnership) error {
 	if bucket == s.metaBucket {
 		return s3err.GetAPIError(s3err.ErrAccessDenied)
 	}
-	_, err := s.client.PutBucketOwnershipControls(ctx, &s3.PutBucketOwnershipControlsInput{
+	_, err := s.getClient(ctx).PutBucketOwnershipControls(ctx, &s3.PutBucketOwnershipControlsInput{
 		Bucket: &bucket,
 		OwnershipControls: &types.OwnershipControls{
 			Rules: []types.OwnershipControlsRule{
@@ -250,7 +380,7 @@ func (s *S3Proxy) GetBucketOwnershipControls(ctx context.Context, bucket string)
 		return "", s3err.GetAPIError(s3err.ErrAccessDenied)
 	}
 	var ownship types.ObjectOwnership
-	resp, err := s.client.GetBucketOwnershipControls(ctx, &s3.GetBucketOwnershipControlsInput{
+	resp, err := s.getClient(ctx).GetBucketOwnershipControls(ctx, &s3.GetBucketOwnershipControlsInput{
 		Bucket: &bucket,
 	})
 	if err != nil {
@@ -262,7 +392,7 @@ func (s *S3Proxy) DeleteBucketOwnershipControls(ctx context.Context, bucket stri
 	if bucket == s.metaBucket {
 		return s3err.GetAPIError(s3err.ErrAccessDenied)
 	}
-	_, err := s.client.DeleteBucketOwnershipControls(ctx, &s3.DeleteBucketOwnershipControlsInput{
+	_, err := s.getClient(ctx).DeleteBucketOwnershipControls(ctx, &s3.DeleteBucketOwnershipControlsInput{
 		Bucket: &bucket,
 	})
 	return handleError(err)
@@ -272,7 +402,7 @@ func (s *S3Proxy) PutBucketVersioning(ctx context.Context, bucket string, status
 	if bucket == s.metaBucket {
 		return s3err.GetAPIError(s3err.ErrAccessDenied)
 	}
-	_, err := s.client.PutBucketVersioning(ctx, &s3.PutBucketVersioningInput{
+	_, err := s.getClient(ctx).PutBucketVersioning(ctx, &s3.PutBucketVersioningInput{
 		Bucket: &bucket,
 		VersioningConfiguration: &types.VersioningConfiguration{
 			Status: status,
@@ -286,7 +416,7 @@ func (s *S3Proxy) GetBucketVersioning(ctx context.Context, bucket string) (s3res
 	if bucket == s.metaBucket {
 		return s3response.GetBucketVersioningOutput{}, s3err.GetAPIError(s3err.ErrAccessDenied)
 	}
-	out, err := s.client.GetBucketVersioning(ctx, &s3.GetBucketVersioningInput{
+	out, err := s.getClient(ctx).GetBucketVersioning(ctx, &s3.GetBucketVersioningInput{
 		Bucket: &bucket,
 	})
 	if err != nil {
@@ -322,7 +452,7 @@ func (s *S3Proxy) ListObjectVersions(ctx context.Context, input *s3.ListObjectVe
 		input.ExpectedBucketOwner = nil
 	}
 
-	out, err := s.client.ListObjectVersions(ctx, input)
+	out, err := s.getClient(ctx).ListObjectVersions(ctx, input)
 	if err != nil {
 		return s3response.ListVersionsResult{}, handleError(err)
 	}
@@ -408,6 +538,24 @@ func (s *S3Proxy) CreateMultipartUpload(ctx context.Context, input s3response.Cr
 		input.WebsiteRedirectLocation = nil
 	}
 
+	var lockSettings objectLockSettings
+	if s.objectLockFallback {
+		// the object lock is applied by the gateway on completion
+		lockSettings = objectLockSettings{
+			Mode:            input.ObjectLockMode,
+			RetainUntilDate: input.ObjectLockRetainUntilDate,
+			LegalHold:       input.ObjectLockLegalHoldStatus,
+		}
+		input.ObjectLockRetainUntilDate = nil
+		input.ObjectLockMode = ""
+		input.ObjectLockLegalHoldStatus = ""
+
+		err := s.checkObjectLockSettings(ctx, *input.Bucket, lockSettings)
+		if err != nil {
+			return s3response.InitiateMultipartUploadResult{}, err
+		}
+	}
+
 	var expires *time.Time
 	if input.Expires != nil {
 		exp, err := time.Parse(time.RFC1123, *input.Expires)
@@ -416,7 +564,7 @@ func (s *S3Proxy) CreateMultipartUpload(ctx context.Context, input s3response.Cr
 		}
 	}
 
-	out, err := s.client.CreateMultipartUpload(ctx, &s3.CreateMultipartUploadInput{
+	out, err := s.getClient(ctx).CreateMultipartUpload(ctx, &s3.CreateMultipartUploadInput{
 		Bucket:                    input.Bucket,
 		Key:                       input.Key,
 		ExpectedBucketOwner:       input.ExpectedBucketOwner,
@@ -453,6 +601,19 @@ func (s *S3Proxy) CreateMultipartUpload(ctx context.Context, input s3response.Cr
 		return s3response.InitiateMultipartUploadResult{}, handleError(err)
 	}
 
+	if lockSettings.isSet() {
+		err = s.storeMpLockSettings(ctx, *out.UploadId, lockSettings)
+		if err != nil {
+			// attempt to cleanup
+			_, _ = s.getClient(ctx).AbortMultipartUpload(ctx, &s3.AbortMultipartUploadInput{
+				Bucket:   out.Bucket,
+				Key:      out.Key,
+				UploadId: out.UploadId,
+			})
+			return s3response.InitiateMultipartUploadResult{}, err
+		}
+	}
+
 	return s3response.InitiateMultipartUploadResult{
 		Bucket:   *out.Bucket,
 		Key:      *out.Key,
@@ -504,7 +665,7 @@ func (s *S3Proxy) CompleteMultipartUpload(ctx context.Context, input *s3.Complet
 	}
 
 	var versionid string
-	out, err := s.client.CompleteMultipartUpload(ctx, input)
+	out, err := s.getClient(ctx).CompleteMultipartUpload(ctx, input)
 	if out != nil {
 		res = s3response.CompleteMultipartUploadResult{
 			Location:          out.Location,
@@ -522,8 +683,24 @@ func (s *S3Proxy) CompleteMultipartUpload(ctx context.Context, input *s3.Complet
 			versionid = *out.VersionId
 		}
 	}
+	if err != nil {
+		return res, versionid, handleError(err)
+	}
 
-	return res, versionid, handleError(err)
+	if s.objectLockFallback {
+		lockSettings, err := s.popMpLockSettings(ctx, *input.UploadId)
+		if err != nil {
+			return res, versionid, err
+		}
+		if lockSettings.isSet() {
+			err = s.applyObjectLockSettings(ctx, *input.Bucket, *input.Key, versionid, lockSettings)
+			if err != nil {
+				return res, versionid, err
+			}
+		}
+	}
+
+	return res, versionid, nil
 }
 
 func (s *S3Proxy) AbortMultipartUpload(ctx context.Context, input *s3.AbortMultipartUploadInput) error {
@@ -536,8 +713,16 @@ func (s *S3Proxy) AbortMultipartUpload(ctx context.Context, input *s3.AbortMulti
 	if input.IfMatchInitiatedTime != nil && (*input.IfMatchInitiatedTime).Equal(defTime) {
 		input.IfMatchInitiatedTime = nil
 	}
-	_, err := s.client.AbortMultipartUpload(ctx, input)
-	return handleError(err)
+	_, err := s.getClient(ctx).AbortMultipartUpload(ctx, input)
+	if err != nil {
+		return handleError(err)
+	}
+
+	if s.objectLockFallback {
+		_ = s.deleteMetaObj(ctx, string(metaPrefixMpLock)+*input.UploadId)
+	}
+
+	return nil
 }
 
 func (s *S3Proxy) ListMultipartUploads(ctx context.Context, input *s3.ListMultipartUploadsInput) (s3response.ListMultipartUploadsResult, error) {
@@ -563,7 +748,7 @@ func (s *S3Proxy) ListMultipartUploads(ctx context.Context, input *s3.ListMultip
 		input.UploadIdMarker = nil
 	}
 
-	output, err := s.client.ListMultipartUploads(ctx, input)
+	output, err := s.getClient(ctx).ListMultipartUploads(ctx, input)
 	if err != nil {
 		return s3response.ListMultipartUploadsResult{}, handleError(err)
 	}
@@ -634,7 +819,7 @@ func (s *S3Proxy) ListParts(ctx context.Context, input *s3.ListPartsInput) (s3re
 		input.SSECustomerKeyMD5 = nil
 	}
 
-	output, err := s.client.ListParts(ctx, input)
+	output, err := s.getClient(ctx).ListParts(ctx, input)
 	if err != nil {
 		return s3response.ListPartsResult{}, handleError(err)
 	}
@@ -725,7 +910,7 @@ func (s *S3Proxy) UploadPart(ctx context.Context, input *s3.UploadPartInput) (*s
 
 	// streaming backend is not seekable,
 	// use unsigned payload for streaming ops
-	output, err := s.client.UploadPart(ctx, input, s3.WithAPIOptions(
+	output, err := s.getClient(ctx).UploadPart(ctx, input, s3.WithAPIOptions(
 		v4.SwapComputePayloadSHA256ForUnsignedPayloadMiddleware,
 	))
 	return output, handleError(err)
@@ -775,7 +960,7 @@ func (s *S3Proxy) UploadPartCopy(ctx context.Context, input *s3.UploadPartCopyIn
 		input.SSECustomerKeyMD5 = nil
 	}
 
-	output, err := s.client.UploadPartCopy(ctx, input)
+	output, err := s.getClient(ctx).UploadPartCopy(ctx, input)
 	if err != nil {
 		return s3response.CopyPartResult{}, handleError(err)
 	}
@@ -874,10 +1059,27 @@ func (s *S3Proxy) PutObject(ctx context.Context, input s3response.PutObjectInput
 		input.WebsiteRedirectLocation = nil
 	}
 
-	// no object lock for backend
-	input.ObjectLockRetainUntilDate = nil
-	input.ObjectLockMode = ""
-	input.ObjectLockLegalHoldStatus = ""
+	if input.ObjectLockRetainUntilDate != nil && (*input.ObjectLockRetainUntilDate).Equal(defTime) {
+		input.ObjectLockRetainUntilDate = nil
+	}
+
+	var lockSettings objectLockSettings
+	if s.objectLockFallback {
+		// the object lock is applied by the gateway
+		lockSettings = objectLockSettings{
+			Mode:            input.ObjectLockMode,
+			RetainUntilDate: input.ObjectLockRetainUntilDate,
+			LegalHold:       input.ObjectLockLegalHoldStatus,
+		}
+		input.ObjectLockRetainUntilDate = nil
+		input.ObjectLockMode = ""
+		input.ObjectLockLegalHoldStatus = ""
+
+		err := s.checkObjectLockSettings(ctx, *input.Bucket, lockSettings)
+		if err != nil {
+			return s3response.PutObjectOutput{}, err
+		}
+	}
 
 	var expire *time.Time
 	if input.Expires != nil {
@@ -889,7 +1091,7 @@ func (s *S3Proxy) PutObject(ctx context.Context, input s3response.PutObjectInput
 
 	// streaming backend is not seekable,
 	// use unsigned payload for streaming ops
-	output, err := s.client.PutObject(ctx, &s3.PutObjectInput{
+	output, err := s.getClient(ctx).PutObject(ctx, &s3.PutObjectInput{
 		Bucket:                    input.Bucket,
 		Key:                       input.Key,
 		ContentLength:             input.ContentLength,
@@ -937,6 +1139,13 @@ func (s *S3Proxy) PutObject(ctx context.Context, input s3response.PutObjectInput
 		versionID = *output.VersionId
 	}
 
+	if lockSettings.isSet() {
+		err = s.applyObjectLockSettings(ctx, *input.Bucket, *input.Key, versionID, lockSettings)
+		if err != nil {
+			return s3response.PutObjectOutput{}, err
+		}
+	}
+
 	return s3response.PutObjectOutput{
 		ETag:              *output.ETag,
 		VersionID:         versionID,
@@ -1005,7 +1214,7 @@ func (s *S3Proxy) HeadObject(ctx context.Context, input *s3.HeadObjectInput) (*s
 		input.VersionId = nil
 	}
 
-	out, err := s.client.HeadObject(ctx, input)
+	out, err := s.getClient(ctx).HeadObject(ctx, input)
 	return out, handleError(err)
 }
 
@@ -1065,7 +1274,7 @@ func (s *S3Proxy) GetObject(ctx context.Context, input *s3.GetObjectInput) (*s3.
 		input.VersionId = nil
 	}
 
-	output, err := s.client.GetObject(ctx, input)
+	output, err := s.getClient(ctx).GetObject(ctx, input)
 	if err != nil {
 		return nil, handleError(err)
 	}
@@ -1099,7 +1308,7 @@ func (s *S3Proxy) GetObjectAttributes(ctx context.Context, input *s3.GetObjectAt
 		input.VersionId = nil
 	}
 
-	out, err := s.client.GetObjectAttributes(ctx, input)
+	out, err := s.getClient(ctx).GetObjectAttributes(ctx, input)
 	if err != nil {
 		return s3response.GetObjectAttributesResponse{}, handleError(err)
 	}
@@ -1224,6 +1433,24 @@ func (s *S3Proxy) CopyObject(ctx context.Context, input s3response.CopyObjectInp
 		input.WebsiteRedirectLocation = nil
 	}
 
+	var lockSettings objectLockSettings
+	if s.objectLockFallback {
+		// the object lock is applied by the gateway
+		lockSettings = objectLockSettings{
+			Mode:            input.ObjectLockMode,
+			RetainUntilDate: input.ObjectLockRetainUntilDate,
+			LegalHold:       input.ObjectLockLegalHoldStatus,
+		}
+		input.ObjectLockRetainUntilDate = nil
+		input.ObjectLockMode = ""
+		input.ObjectLockLegalHoldStatus = ""
+
+		err := s.checkObjectLockSettings(ctx, *input.Bucket, lockSettings)
+		if err != nil {
+			return s3response.CopyObjectOutput{}, err
+		}
+	}
+
 	var expires *time.Time
 	if input.Expires != nil {
 		exp, err := time.Parse(time.RFC1123, *input.Expires)
@@ -1232,7 +1459,7 @@ func (s *S3Proxy) CopyObject(ctx context.Context, input s3response.CopyObjectInp
 		}
 	}
 
-	out, err := s.client.CopyObject(ctx,
+	out, err := s.getClient(ctx).CopyObject(ctx,
 		&s3.CopyObjectInput{
 			Metadata:                       input.Metadata,
 			Bucket:                         input.Bucket,
@@ -1282,6 +1509,16 @@ func (s *S3Proxy) CopyObject(ctx context.Context, input s3response.CopyObjectInp
 	if out.CopyObjectResult == nil {
 		out.CopyObjectResult = &types.CopyObjectResult{}
 	}
+	if lockSettings.isSet() {
+		var versionId string
+		if out.VersionId != nil {
+			versionId = *out.VersionId
+		}
+		err = s.applyObjectLockSettings(ctx, *input.Bucket, *input.Key, versionId, lockSettings)
+		if err != nil {
+			return s3response.CopyObjectOutput{}, err
+		}
+	}
 	return s3response.CopyObjectOutput{
 		BucketKeyEnabled: out.BucketKeyEnabled,
 		CopyObjectResult: &s3response.CopyObjectResult{
@@ -1325,7 +1562,7 @@ func (s *S3Proxy) ListObjects(ctx context.Context, input *s3.ListObjectsInput) (
 		input.Prefix = nil
 	}
 
-	out, err := s.client.ListObjects(ctx, input)
+	out, err := s.getClient(ctx).ListObjects(ctx, input)
 	if err != nil {
 		return s3response.ListObjectsResult{}, handleError(err)
 	}
@@ -1368,7 +1605,7 @@ func (s *S3Proxy) ListObjectsV2(ctx context.Context, input *s3.ListObjectsV2Inpu
 		input.StartAfter = nil
 	}
 
-	out, err := s.client.ListObjectsV2(ctx, input)
+	out, err := s.getClient(ctx).ListObjectsV2(ctx, input)
 	if err != nil {
 		return s3response.ListObjectsV2Result{}, handleError(err)
 	}
@@ -1411,9 +1648,24 @@ func (s *S3Proxy) DeleteObject(ctx context.Context, input *s3.DeleteObjectInput)
 	if input.VersionId != nil && *input.VersionId == "" {
 		input.VersionId = nil
 	}
+	if input.BypassGovernanceRetention != nil && (!*input.BypassGovernanceRetention || s.objectLockFallback) {
+		input.BypassGovernanceRetention = nil
+	}
+
+	res, err := s.getClient(ctx).DeleteObject(ctx, input)
+	if err != nil {
+		return res, handleError(err)
+	}
+
+	if s.objectLockFallback {
+		if input.VersionId != nil {
+			s.removeObjectLockMeta(ctx, *input.Bucket, *input.Key, *input.VersionId)
+		} else if res.DeleteMarker == nil || !*res.DeleteMarker {
+			s.removeObjectLockMeta(ctx, *input.Bucket, *input.Key, nullVersionId)
+		}
+	}
 
-	res, err := s.client.DeleteObject(ctx, input)
-	return res, handleError(err)
+	return res, nil
 }
 
 func (s *S3Proxy) DeleteObjects(ctx context.Context, input *s3.DeleteObjectsInput) (s3response.DeleteResult, error) {
@@ -1427,15 +1679,32 @@ func (s *S3Proxy) DeleteObjects(ctx context.Context, input *s3.DeleteObjectsInpu
 		input.MFA = nil
 	}
 
+	if input.BypassGovernanceRetention != nil && (!*input.BypassGovernanceRetention || s.objectLockFallback) {
+		input.BypassGovernanceRetention = nil
+	}
+
 	if len(input.Delete.Objects) == 0 {
 		input.Delete.Objects = []types.ObjectIdentifier{}
 	}
 
-	output, err := s.client.DeleteObjects(ctx, input)
+	output, err := s.getClient(ctx).DeleteObjects(ctx, input)
 	if err != nil {
 		return s3response.DeleteResult{}, handleError(err)
 	}
 
+	if s.objectLockFallback {
+		for _, obj := range output.Deleted {
+			if obj.Key == nil {
+				continue
+			}
+			if obj.VersionId != nil && *obj.VersionId != "" {
+				s.removeObjectLockMeta(ctx, *input.Bucket, *obj.Key, *obj.VersionId)
+			} else if obj.DeleteMarker == nil || !*obj.DeleteMarker {
+				s.removeObjectLockMeta(ctx, *input.Bucket, *obj.Key, nullVersionId)
+			}
+		}
+	}
+
 	return s3response.DeleteResult{
 		Deleted: output.Deleted,
 		Error:   output.Errors,
@@ -1469,7 +1738,7 @@ func (s *S3Proxy) PutObjectTagging(ctx context.Context, bucket, object, versionI
 		})
 	}
 
-	_, err := s.client.PutObjectTagging(ctx, &s3.PutObjectTaggingInput{
+	_, err := s.getClient(ctx).PutObjectTagging(ctx, &s3.PutObjectTaggingInput{
 		Bucket:    &bucket,
 		Key:       &object,
 		VersionId: &versionId,
@@ -1482,7 +1751,7 @@ func (s *S3Proxy) GetObjectTagging(ctx context.Context, bucket, object, versionI
 	if bucket == s.metaBucket {
 		return nil, s3err.GetAPIError(s3err.ErrAccessDenied)
 	}
-	output, err := s.client.GetObjectTagging(ctx, &s3.GetObjectTaggingInput{
+	output, err := s.getClient(ctx).GetObjectTagging(ctx, &s3.GetObjectTaggingInput{
 		Bucket:    &bucket,
 		Key:       &object,
 		VersionId: &versionId,
@@ -1503,7 +1772,7 @@ func (s *S3Proxy) DeleteObjectTagging(ctx context.Context, bucket, object, versi
 	if bucket == s.metaBucket {
 		return s3err.GetAPIError(s3err.ErrAccessDenied)
 	}
-	_, err := s.client.DeleteObjectTagging(ctx, &s3.DeleteObjectTaggingInput{
+	_, err := s.getClient(ctx).DeleteObjectTagging(ctx, &s3.DeleteObjectTaggingInput{
 		Bucket:    &bucket,
 		Key:       &object,
 		VersionId: &versionId,
@@ -1537,6 +1806,32 @@ func (s *S3Proxy) DeleteBucketCors(ctx context.Context, bucket string) error {
 	return nil
 }
 
+func (s *S3Proxy) PutPublicAccessBlock(ctx context.Context, bucket string, config []byte) error {
+	return handleError(s.putMetaBucketObj(ctx, bucket, config, metaPrefixPublicAccessBlock))
+}
+
+func (s *S3Proxy) GetPublicAccessBlock(ctx context.Context, bucket string) ([]byte, error) {
+	data, err := s.getMetaBucketObjData(ctx, bucket, metaPrefixPublicAccessBlock, false)
+	if err != nil {
+		return nil, handleError(err)
+	}
+
+	return data, nil
+}
+
+func (s *S3Proxy) DeletePublicAccessBlock(ctx context.Context, bucket string) error {
+	key := getMetaKey(bucket, metaPrefixPublicAccessBlock)
+	_, err := s.client.DeleteObject(ctx, &s3.DeleteObjectInput{
+		Bucket: &s.metaBucket,
+		Key:    &key,
+	})
+	if err != nil && !areErrSame(err, s3err.GetAPIError(s3err.ErrNoSuchKey)) {
+		return handleError(err)
+	}
+
+	return nil
+}
+
 func (s *S3Proxy) PutBucketPolicy(ctx context.Context, bucket string, policy []byte) error {
 	return handleError(s.putMetaBucketObj(ctx, bucket, policy, metaPrefixPolicy))
 }
@@ -1564,28 +1859,179 @@ func (s *S3Proxy) DeleteBucketPolicy(ctx context.Context, bucket string) error {
 }
 
 func (s *S3Proxy) PutObjectLockConfiguration(ctx context.Context, bucket string, config []byte) error {
-	return s3err.GetAPIError(s3err.ErrNotImplemented)
+	if s.objectLockFallback {
+		if !s.bucketExists(ctx, bucket) {
+			return s3err.GetAPIError(s3err.ErrNoSuchBucket)
+		}
+
+		out, err := s.getClient(ctx).GetBucketVersioning(ctx, &s3.GetBucketVersioningInput{
+			Bucket: &bucket,
+		})
+		if err != nil {
+			return handleError(err)
+		}
+		if out.Status == types.BucketVersioningStatusSuspended {
+			return s3err.GetAPIError(s3err.ErrObjectLockConfigurationNotAllowed)
+		}
+
+		return handleError(s.putMetaBucketObj(ctx, bucket, config, metaPrefixObjectLock))
+	}
+
+	lockConfig, err := auth.ParseBucketLockConfigurationOutput(config)
+	if err != nil {
+		return err
+	}
+	if lockConfig.Rule.DefaultRetention == nil {
+		lockConfig.Rule = nil
+	}
+
+	_, err = s.getClient(ctx).PutObjectLockConfiguration(ctx, &s3.PutObjectLockConfigurationInput{
+		Bucket:                  &bucket,
+		ObjectLockConfiguration: lockConfig,
+	})
+	return handleError(err)
 }
 
 func (s *S3Proxy) GetObjectLockConfiguration(ctx context.Context, bucket string) ([]byte, error) {
-	return nil, s3err.GetAPIError(s3err.ErrObjectLockConfigurationNotFound)
+	if s.objectLockFallback {
+		if !s.bucketExists(ctx, bucket) {
+			return nil, s3err.GetAPIError(s3err.ErrNoSuchBucket)
+		}
+
+		data, err := s.getMetaBucketObjData(ctx, bucket, metaPrefixObjectLock, false)
+		if err != nil {
+			return nil, handleError(err)
+		}
+		return data, nil
+	}
+
+	out, err := s.getClient(ctx).GetObjectLockConfiguration(ctx, &s3.GetObjectLockConfigurationInput{
+		Bucket: &bucket,
+	})
+	if err != nil {
+		return nil, handleError(err)
+	}
+
+	var config auth.BucketLockConfig
+	if out.ObjectLockConfiguration != nil {
+		config.Enabled = out.ObjectLockConfiguration.ObjectLockEnabled == types.ObjectLockEnabledEnabled
+		if out.ObjectLockConfiguration.Rule != nil {
+			config.DefaultRetention = out.ObjectLockConfiguration.Rule.DefaultRetention
+		}
+	}
+
+	data, err := json.Marshal(config)
+	if err != nil {
+		return nil, fmt.Errorf("parse object lock config: %w", err)
+	}
+
+	return data, nil
 }
 
 func (s *S3Proxy) PutObjectRetention(ctx context.Context, bucket, object, versionId string, retention []byte) error {
-	return s3err.GetAPIError(s3err.ErrNotImplemented)
+	if s.objectLockFallback {
+		return s.putRetentionFallback(ctx, bucket, object, versionId, retention)
+	}
+
+	ret, err := auth.ParseObjectLockRetentionOutput(retention)
+	if err != nil {
+		return err
+	}
+
+	input := &s3.PutObjectRetentionInput{
+		Bucket:    &bucket,
+		Key:       &object,
+		Retention: ret,
+	}
+	if versionId != "" {
+		input.VersionId = &versionId
+	}
+	if backend.IsBypassGovernance(ctx) {
+		bypass := true
+		input.BypassGovernanceRetention = &bypass
+	}
+
+	_, err = s.getClient(ctx).PutObjectRetention(ctx, input)
+	return handleError(err)
 }
 
 func (s *S3Proxy) GetObjectRetention(ctx context.Context, bucket, object, versionId string) ([]byte, error) {
-	return nil, s3err.GetAPIError(s3err.ErrNotImplemented)
+	if s.objectLockFallback {
+		return s.getRetentionFallback(ctx, bucket, object, versionId)
+	}
 
+	input := &s3.GetObjectRetentionInput{
+		Bucket: &bucket,
+		Key:    &object,
+	}
+	if versionId != "" {
+		input.VersionId = &versionId
+	}
+
+	out, err := s.getClient(ctx).GetObjectRetention(ctx, input)
+	if err != nil {
+		return nil, handleError(err)
+	}
+	if out.Retention == nil {
+		return nil, s3err.GetAPIError(s3err.ErrNoSuchObjectLockConfiguration)
+	}
+
+	data, err := json.Marshal(out.Retention)
+	if err != nil {
+		return nil, fmt.Errorf("parse object lock retention: %w", err)
+	}
+
+	return data, nil
 }
 
 func (s *S3Proxy) PutObjectLegalHold(ctx context.Context, bucket, object, versionId string, status bool) error {
-	return s3err.GetAPIError(s3err.ErrNotImplemented)
+	if s.objectLockFallback {
+		return s.putLegalHoldFallback(ctx, bucket, object, versionId, status)
+	}
+
+	legalHold := types.ObjectLockLegalHoldStatusOff
+	if status {
+		legalHold = types.ObjectLockLegalHoldStatusOn
+	}
+
+	input := &s3.PutObjectLegalHoldInput{
+		Bucket: &bucket,
+		Key:    &object,
+		LegalHold: &types.ObjectLockLegalHold{
+			Status: legalHold,
+		},
+	}
+	if versionId != "" {
+		input.VersionId = &versionId
+	}
+
+	_, err := s.getClient(ctx).PutObjectLegalHold(ctx, input)
+	return handleError(err)
 }
 
 func (s *S3Proxy) GetObjectLegalHold(ctx context.Context, bucket, object, versionId string) (*bool, error) {
-	return nil, s3err.GetAPIError(s3err.ErrNotImplemented)
+	if s.objectLockFallback {
+		return s.getLegalHoldFallback(ctx, bucket, object, versionId)
+	}
+
+	input := &s3.GetObjectLegalHoldInput{
+		Bucket: &bucket,
+		Key:    &object,
+	}
+	if versionId != "" {
+		input.VersionId = &versionId
+	}
+
+	out, err := s.getClient(ctx).GetObjectLegalHold(ctx, input)
+	if err != nil {
+		return nil, handleError(err)
+	}
+	if out.LegalHold == nil {
+		return nil, s3err.GetAPIError(s3err.ErrNoSuchObjectLockConfiguration)
+	}
+
+	status := out.LegalHold.Status == types.ObjectLockLegalHoldStatusOn
+	return &status, nil
 }
 
 func (s *S3Proxy) ChangeBucketOwner(ctx context.Context, bucket, owner string) error {
@@ -1695,6 +2141,10 @@ func handleMetaBucketObjectNotFoundErr(prefix metaPrefix) ([]byte, error) {
 		return nil, s3err.GetAPIError(s3err.ErrNoSuchBucketPolicy)
 	case metaPrefixCors:
 		return nil, s3err.GetAPIError(s3err.ErrNoSuchCORSConfiguration)
+	case metaPrefixPublicAccessBlock:
+		return nil, s3err.GetAPIError(s3err.ErrNoSuchPublicAccessBlockConfiguration)
+	case metaPrefixObjectLock:
+		return nil, s3err.GetAPIError(s3err.ErrObjectLockConfigurationNotFound)
 	}
 
 	return []byte{}, nil
@@ -1735,6 +2185,15 @@ func handleError(err error) error {
 
 	var ae smithy.APIError
 	if errors.As(err, &ae) {
+		// the gateway checks some of the errors (e.g. for the object lock
+		// enforcement), so return the gateway errors where they match
+		if code, ok := upstreamErrCodes[ae.ErrorCode()]; ok {
+			return s3err.GetAPIError(code)
+		}
+		if code, ok := translateObjectLockErr(ae); ok {
+			return s3err.GetAPIError(code)
+		}
+
 		apiErr := s3err.APIError{
 			Code:        ae.ErrorCode(),
 			Description: ae.ErrorMessage(),
@@ -1748,6 +2207,42 @@ func handleError(err error) error {
 	return err
 }
 
+// upstreamErrCodes are the upstream error codes that map to
+// a single gateway error
+var upstreamErrCodes = map[string]s3err.ErrorCode{
+	"NoSuchBucket":                         s3err.ErrNoSuchBucket,
+	"NoSuchKey":                            s3err.ErrNoSuchKey,
+	"NoSuchVersion":                        s3err.ErrNoSuchVersion,
+	"MethodNotAllowed":                     s3err.ErrMethodNotAllowed,
+	"ObjectLockConfigurationNotFoundError": s3err.ErrObjectLockConfigurationNotFound,
+	"NoSuchObjectLockConfiguration":        s3err.ErrNoSuchObjectLockConfiguration,
+}
+
+// translateObjectLockErr maps the generic upstream object lock
+// errors to the gateway errors by the error message
+func translateObjectLockErr(ae smithy.APIError) (s3err.ErrorCode, bool) {
+	msg := ae.ErrorMessage()
+	switch ae.ErrorCode() {
+	case "InvalidRequest":
+		if strings.Contains(msg, "Object Lock Configuration") {
+			return s3err.ErrMissingObjectLockConfiguration, true
+		}
+		if strings.Contains(msg, "ObjectLockConfiguration") {
+			return s3err.ErrMissingObjectLockConfigurationNoSpaces, true
+		}
+	case "AccessDenied":
+		if strings.Contains(strings.ToLower(msg), "object lock") {
+			return s3err.ErrObjectLocked, true
+		}
+	case "InvalidBucketState":
+		if strings.Contains(msg, "Object Lock") {
+			return s3err.ErrObjectLockConfigurationNotAllowed, true
+		}
+	}
+
+	return 0, false
+}
+
 func convertObjects(objs []types.Object) []s3response.Object {
 	result := make([]s3response.Object, 0, len(objs))
 
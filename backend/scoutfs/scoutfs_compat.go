@@ -46,6 +46,10 @@ type ScoutFS struct {
 	rootfd  *os.File
 	rootdir string
 
+	// rootfs resolves the bucket and object paths relative to
+	// the gateway root directory
+	rootfs *posix.RootFS
+
 	// glaciermode enables the following behavior:
 	// GET object:  if file offline, return invalid object state
 	// HEAD object: if file offline, set obj storage class to GLACIER
@@ -87,8 +91,14 @@ func New(rootdir string, opts ScoutfsOpts) (*ScoutFS, error) {
 		return nil, err
 	}
 
+	rootfs, err := posix.NewRootFS(rootdir, opts.BucketLinks)
+	if err != nil {
+		return nil, err
+	}
+
 	f, err := os.Open(rootdir)
 	if err != nil {
+		rootfs.Close()
 		return nil, fmt.Errorf("open %v: %w", rootdir, err)
 	}
 
@@ -105,7 +115,8 @@ func New(rootdir string, opts ScoutfsOpts) (*ScoutFS, error) {
 	return &ScoutFS{
 		Posix:            p,
 		rootfd:           f,
-		rootdir:          rootdir,
+		rootdir:          rootfs.Dir(),
+		rootfs:           rootfs,
 		glaciermode:      opts.GlacierMode,
 		disableNoArchive: opts.DisableNoArchive,
 		projectIDEnabled: setProjectID,
@@ -143,6 +154,7 @@ const (
 func (s *ScoutFS) Shutdown() {
 	s.Posix.Shutdown()
 	s.rootfd.Close()
+	s.rootfs.Close()
 }
 
 func (*ScoutFS) String() string {
@@ -167,7 +179,7 @@ func (s *ScoutFS) CreateBucket(ctx context.Context, input *s3.CreateBucketInput,
 			return nil
 		}
 
-		f, err := os.Open(*input.Bucket)
+		f, err := s.rootfs.Open(*input.Bucket)
 		if err != nil {
 			debuglogger.InternalError(fmt.Errorf("create bucket %q set project id - open: %v",
 				*input.Bucket, err))
@@ -192,7 +204,7 @@ func (s *ScoutFS) HeadObject(ctx context.Context, input *s3.HeadObjectInput) (*s
 	}
 
 	if s.glaciermode {
-		objPath := filepath.Join(*input.Bucket, *input.Key)
+		objPath := s.rootfs.Path(*input.Bucket, *input.Key)
 
 		stclass := types.StorageClassStandard
 		requestOngoing := ""
@@ -330,7 +342,7 @@ func (s *ScoutFS) GetObject(ctx context.Context, input *s3.GetObjectInput) (*s3.
 		return nil, s3err.GetAPIError(s3err.ErrInvalidBucketName)
 	}
 
-	_, err := os.Stat(bucket)
+	_, err := s.rootfs.Stat(bucket)
 	if errors.Is(err, fs.ErrNotExist) {
 		return nil, s3err.GetAPIError(s3err.ErrNoSuchBucket)
 	}
@@ -340,7 +352,7 @@ func (s *ScoutFS) GetObject(ctx context.Context, input *s3.GetObjectInput) (*s3.
 
 	objPath := filepath.Join(bucket, object)
 
-	fi, err := os.Stat(objPath)
+	fi, err := s.rootfs.Stat(objPath)
 	if errors.Is(err, fs.ErrNotExist) || errors.Is(err, syscall.ENOTDIR) {
 		return nil, s3err.GetAPIError(s3err.ErrNoSuchKey)
 	}
@@ -358,7 +370,7 @@ func (s *ScoutFS) GetObject(ctx context.Context, input *s3.GetObjectInput) (*s3.
 	if s.glaciermode {
 		// Check if there are any offline exents associated with this file.
 		// If so, we will return the InvalidObjectState error.
-		st, err := scoutfs.StatMore(objPath)
+		st, err := scoutfs.StatMore(s.rootfs.Path(objPath))
 		if errors.Is(err, fs.ErrNotExist) {
 			return nil, s3err.GetAPIError(s3err.ErrNoSuchKey)
 		}
@@ -398,7 +410,7 @@ func (s *ScoutFS) glacierFileToObj(bucket string, fetchOwner bool) backend.GetOb
 		if err != nil || d.IsDir() {
 			return res, err
 		}
-		objPath := filepath.Join(bucket, path)
+		objPath := s.rootfs.Path(bucket, path)
 		// Check if there are any offline exents associated with this file.
 		// If so, we will return the Glacier storage class
 		st, err := scoutfs.StatMore(objPath)
@@ -425,7 +437,7 @@ func (s *ScoutFS) RestoreObject(_ context.Context, input *s3.RestoreObjectInput)
 		return s3err.GetAPIError(s3err.ErrInvalidBucketName)
 	}
 
-	_, err := os.Stat(bucket)
+	_, err := s.rootfs.Stat(bucket)
 	if errors.Is(err, fs.ErrNotExist) {
 		return s3err.GetAPIError(s3err.ErrNoSuchBucket)
 	}
@@ -433,7 +445,13 @@ func (s *ScoutFS) RestoreObject(_ context.Context, input *s3.RestoreObjectInput)
 		return fmt.Errorf("stat bucket: %w", err)
 	}
 
-	err = setStaging(filepath.Join(bucket, object))
+	objPath := filepath.Join(bucket, object)
+	err = s.rootfs.CheckPath(objPath)
+	if err != nil {
+		return fmt.Errorf("check object path: %w", err)
+	}
+
+	err = setStaging(s.rootfs.Path(objPath))
 	if errors.Is(err, fs.ErrNotExist) {
 		return s3err.GetAPIError(s3err.ErrNoSuchKey)
 	}
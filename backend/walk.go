@@ -50,6 +50,7 @@ type walkState struct {
 	max        int32
 	getObj     GetObjFunc
 	skipdirs   []string
+	prefetcher *dirPrefetcher
 
 	// Mutable state
 	cpmap     cpMap
@@ -113,6 +114,8 @@ func Walk(ctx context.Context, fileSystem fs.FS, prefix, delimiter, marker strin
 		skipdirs:   skipdirs,
 		cpmap:      cpMap{},
 	}
+	state.prefetcher = newDirPrefetcher(ctx, fileSystem, state.readSubdirs)
+	defer state.prefetcher.stop()
 
 	qwErr := quickWalk(state)
 	if qwErr != nil {
@@ -343,8 +346,12 @@ func readDir(path string, walkstate *walkState) {
 		return
 	}
 
-	entries, err := fs.ReadDir(walkstate.fileSystem, path)
+	entries, err := walkstate.prefetcher.readDir(path)
 	if err != nil {
+		if ctxErr := walkstate.ctx.Err(); ctxErr != nil {
+			walkstate.walkErr = ctxErr
+			return
+		}
 		// Suppress not-found / not-a-dir errors: they indicate either a
 		// user-supplied prefix that doesn't exist on disk, or a directory
 		// that was removed concurrently during the walk. In both cases,
@@ -360,6 +367,7 @@ func readDir(path string, walkstate *walkState) {
 }
 
 func readDirEntries(path string, entries []fs.DirEntry, walkstate *walkState) {
+	walkstate.prefetchSubdirs(path, entries)
 
 	entriesIndex := 0
 	maxEntries := len(entries)
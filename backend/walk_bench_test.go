@@ -32,6 +32,10 @@
 //
 //	go test -bench='^BenchmarkWalkHuge' -benchtime=3x ./backend/
 //
+// Slow (the small trees with a simulated high-latency readdir):
+//
+//	go test -bench='^BenchmarkWalkSlow' -benchtime=3x -count=6 ./backend/
+//
 // Note: Large and Huge benchmarks create fixture files under /tmp on first run.
 // The fixtures are reused on subsequent runs as long as
 // /tmp/versitygw_walk_bench/large is present. Remove that directory to force a
@@ -398,6 +402,61 @@ func BenchmarkWalkWithMarker(b *testing.B) {
 	}
 }
 
+// ── Slow (small trees, high-latency readdir) ──────────────────────────────────
+
+// slowReadDirLatency simulates the readdir latency of a parallel or network
+// filesystem, where the walk time of deep trees is dominated by the serial
+// readdir latency rather than the number of entries.
+const slowReadDirLatency = time.Millisecond
+
+// slowReadDirFS adds slowReadDirLatency to every ReadDir of the wrapped FS.
+type slowReadDirFS struct {
+	fs.FS
+}
+
+func (s slowReadDirFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	time.Sleep(slowReadDirLatency)
+	return fs.ReadDir(s.FS, name)
+}
+
+// BenchmarkWalkSlowDeep lists all files of the deep tree (101 directories).
+func BenchmarkWalkSlowDeep(b *testing.B) {
+	setupBenchDir(b)
+	fsys := slowReadDirFS{os.DirFS(filepath.Join(benchRoot, "small", "deep"))}
+	for b.Loop() {
+		runWalk(b, fsys, "", "", "", 10000)
+	}
+}
+
+// BenchmarkWalkSlowWide lists all files of the wide tree (1201 directories).
+func BenchmarkWalkSlowWide(b *testing.B) {
+	setupBenchDir(b)
+	fsys := slowReadDirFS{os.DirFS(filepath.Join(benchRoot, "small", "wide"))}
+	for b.Loop() {
+		runWalk(b, fsys, "", "", "", 10000)
+	}
+}
+
+// BenchmarkWalkSlowWidePaged lists the first page of the wide tree, where
+// most of the directories read ahead are not walked.
+func BenchmarkWalkSlowWidePaged(b *testing.B) {
+	setupBenchDir(b)
+	fsys := slowReadDirFS{os.DirFS(filepath.Join(benchRoot, "small", "wide"))}
+	for b.Loop() {
+		runWalk(b, fsys, "", "", "", 1000)
+	}
+}
+
+// BenchmarkWalkSlowWideDelim collapses the wide tree to the top-level dirs,
+// where no directories are read ahead.
+func BenchmarkWalkSlowWideDelim(b *testing.B) {
+	setupBenchDir(b)
+	fsys := slowReadDirFS{os.DirFS(filepath.Join(benchRoot, "small", "wide"))}
+	for b.Loop() {
+		runWalk(b, fsys, "", "/", "", 10000)
+	}
+}
+
 // ── Large (100 k files) ──────────────────────────────────────────
 // Run with: go test -bench=WalkLarge -benchtime=10s ./backend/
 
@@ -0,0 +1,304 @@
+// Copyright 2026 Versity Software
+// This file is licensed under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package backend
+
+import (
+	"container/heap"
+	"context"
+	"io/fs"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// walkPrefetchWorkers is the number of directories read concurrently
+	// ahead of the walk
+	walkPrefetchWorkers = 8
+	// walkPrefetchWindow is the number of directories read ahead of the
+	// walk and not yet walked, this bounds the memory used by the read
+	// ahead directory entries
+	walkPrefetchWindow = 64
+	// walkPrefetchQueue is the number of subdirectories queued to be
+	// read ahead, the subdirectories found when the queue is full are
+	// read when walked
+	walkPrefetchQueue = 1024
+	// walkPrefetchMinLatency is the readdir latency the directories are
+	// read ahead of the walk from, the read ahead overhead is higher than
+	// the readdir latency of the local filesystems
+	walkPrefetchMinLatency = 200 * time.Microsecond
+)
+
+// dirPrefetcher reads the directories ahead of the walk. The walk itself
+// is sequential to keep the S3 ordering, marker and max-keys semantics,
+// but on high-latency filesystems the walk time of deep hierarchies is
+// dominated by the serial readdir latency. The subdirectories the walk
+// will descend into are queued as the directories are read, and read by
+// a bounded number of workers, lowest path first, which is the order the
+// walk descends into them. The read ahead window keeps the directories
+// closest to the walk, the farthest read ahead directory is dropped and
+// queued again when a closer directory is found. The directories are only
+// read ahead once a directory read of the walk is slow.
+type dirPrefetcher struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	fsys   fs.FS
+	// subdirs returns the subdirectories of the directory entries
+	// the walk will descend into
+	subdirs func(dir string, entries []fs.DirEntry) []string
+
+	mu       sync.Mutex
+	enabled  bool
+	queue    dirQueue
+	queued   map[string]struct{}
+	reads    map[string]*dirRead
+	inflight int
+	wg       sync.WaitGroup
+}
+
+// dirRead is a directory read ahead of the walk
+type dirRead struct {
+	done    chan struct{}
+	entries []fs.DirEntry
+	err     error
+}
+
+func newDirPrefetcher(ctx context.Context, fsys fs.FS, subdirs func(string, []fs.DirEntry) []string) *dirPrefetcher {
+	ctx, cancel := context.WithCancel(ctx)
+	return &dirPrefetcher{
+		ctx:     ctx,
+		cancel:  cancel,
+		fsys:    fsys,
+		subdirs: subdirs,
+		queued:  make(map[string]struct{}),
+		reads:   make(map[string]*dirRead),
+	}
+}
+
+// prefetch queues the directories to be read ahead of the walk
+func (p *dirPrefetcher) prefetch(dirs []string) {
+	if len(dirs) == 0 {
+		return
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if !p.enabled {
+		return
+	}
+	p.enqueue(dirs)
+	p.dispatch()
+}
+
+// enqueue queues the directories not read or queued yet, p.mu must be held
+func (p *dirPrefetcher) enqueue(dirs []string) {
+	for _, dir := range dirs {
+		if len(p.queue) == walkPrefetchQueue {
+			return
+		}
+		if _, ok := p.reads[dir]; ok {
+			continue
+		}
+		if _, ok := p.queued[dir]; ok {
+			continue
+		}
+		p.queued[dir] = struct{}{}
+		heap.Push(&p.queue, dir)
+	}
+}
+
+// dequeue removes the first queued directory, p.mu must be held
+func (p *dirPrefetcher) dequeue() string {
+	dir := heap.Pop(&p.queue).(string)
+	delete(p.queued, dir)
+	return dir
+}
+
+// dispatch starts reading the queued directories while there are free
+// workers and room in the read ahead window, p.mu must be held
+func (p *dirPrefetcher) dispatch() {
+	for len(p.queue) > 0 && p.inflight < walkPrefetchWorkers && p.ctx.Err() == nil {
+		if len(p.reads) >= walkPrefetchWindow && !p.evictFarthest(p.queue[0]) {
+			return
+		}
+
+		dir := p.dequeue()
+		r := &dirRead{done: make(chan struct{})}
+		p.reads[dir] = r
+		p.inflight++
+		p.wg.Add(1)
+		go func() {
+			defer p.wg.Done()
+			entries, err := fs.ReadDir(p.fsys, dir)
+
+			var subdirs []string
+			if err == nil {
+				subdirs = p.subdirs(dir, entries)
+			}
+
+			p.mu.Lock()
+			r.entries, r.err = entries, err
+			p.inflight--
+			close(r.done)
+			if _, ok := p.reads[dir]; ok {
+				p.enqueue(subdirs)
+			}
+			p.dispatch()
+			p.mu.Unlock()
+		}()
+	}
+}
+
+// evictFarthest drops the farthest completed read ahead directory from the
+// read ahead window and queues it again, if it is walked after the
+// directory, p.mu must be held
+func (p *dirPrefetcher) evictFarthest(dir string) bool {
+	farthest := ""
+	for d, r := range p.reads {
+		select {
+		case <-r.done:
+		default:
+			continue
+		}
+		if farthest == "" || d+pathSeparator > farthest+pathSeparator {
+			farthest = d
+		}
+	}
+	if farthest == "" || farthest+pathSeparator < dir+pathSeparator {
+		return false
+	}
+
+	delete(p.reads, farthest)
+	p.enqueue([]string{farthest})
+	return true
+}
+
+// readDir returns the entries of the directory, waiting for the read
+// ahead if the directory is being read, or reading the directory if it
+// is not read ahead. The directories are walked in lexical order, so the
+// directories before the walked directory that are not walked yet are
+// never walked, and are dropped from the read ahead.
+func (p *dirPrefetcher) readDir(dir string) ([]fs.DirEntry, error) {
+	key := dir + pathSeparator
+
+	p.mu.Lock()
+	for len(p.queue) > 0 && p.queue[0]+pathSeparator <= key {
+		p.dequeue()
+	}
+	for d := range p.reads {
+		if d != dir && d+pathSeparator < key {
+			delete(p.reads, d)
+		}
+	}
+	r, ok := p.reads[dir]
+	p.mu.Unlock()
+
+	if !ok {
+		start := time.Now()
+		entries, err := fs.ReadDir(p.fsys, dir)
+		if time.Since(start) >= walkPrefetchMinLatency {
+			p.mu.Lock()
+			p.enabled = true
+			p.mu.Unlock()
+		}
+		return entries, err
+	}
+
+	select {
+	case <-r.done:
+	case <-p.ctx.Done():
+		return nil, p.ctx.Err()
+	}
+
+	p.mu.Lock()
+	delete(p.reads, dir)
+	p.dispatch()
+	p.mu.Unlock()
+
+	return r.entries, r.err
+}
+
+// stop cancels the pending reads and waits for the running reads
+func (p *dirPrefetcher) stop() {
+	p.cancel()
+	p.wg.Wait()
+}
+
+// dirQueue is a min-heap of the directories ordered by their walk order
+type dirQueue []string
+
+func (q dirQueue) Len() int { return len(q) }
+
+func (q dirQueue) Less(i, j int) bool {
+	return q[i]+pathSeparator < q[j]+pathSeparator
+}
+
+func (q dirQueue) Swap(i, j int) { q[i], q[j] = q[j], q[i] }
+
+func (q *dirQueue) Push(x any) { *q = append(*q, x.(string)) }
+
+func (q *dirQueue) Pop() any {
+	old := *q
+	n := len(old)
+	x := old[n-1]
+	*q = old[:n-1]
+	return x
+}
+
+// prefetchSubdirs queues the subdirectories of the directory entries the
+// walk will descend into to be read ahead
+func (w *walkState) prefetchSubdirs(path string, entries []fs.DirEntry) {
+	if w.prefetcher == nil {
+		return
+	}
+	w.prefetcher.prefetch(w.readSubdirs(path, entries))
+}
+
+// readSubdirs returns the subdirectories of the directory entries the
+// walk will descend into
+func (w *walkState) readSubdirs(path string, entries []fs.DirEntry) []string {
+	var dirs []string
+	for _, e := range entries {
+		if !e.IsDir() || shouldSkip(e.Name(), w) {
+			continue
+		}
+		dir := makeFullObjectName(path, e.Name())
+		if w.willReadDir(dir) {
+			dirs = append(dirs, dir)
+		}
+	}
+	return dirs
+}
+
+// willReadDir reports if the walk descends into the directory, following
+// processDir. The directories that become common prefixes or sort before
+// the marker are not read, unless these are part of the prefix or the
+// marker.
+func (w *walkState) willReadDir(dir string) bool {
+	fullObjectName := dir + pathSeparator
+	if fullObjectName <= w.marker {
+		return strings.HasPrefix(fullObjectName, w.marker) ||
+			strings.HasPrefix(w.marker, fullObjectName)
+	}
+	if w.prefix != "" && !strings.HasPrefix(fullObjectName, w.prefix) {
+		return strings.HasPrefix(w.prefix, fullObjectName)
+	}
+	if w.delimiter != "" &&
+		strings.Contains(strings.TrimPrefix(fullObjectName, w.prefix), w.delimiter) {
+		return strings.HasPrefix(w.prefix, fullObjectName)
+	}
+	return true
+}
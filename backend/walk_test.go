@@ -20,6 +20,8 @@ import (
 	"encoding/hex"
 	"fmt"
 	"io/fs"
+	"math/rand"
+	"slices"
 	"strings"
 	"sync"
 	"testing"
@@ -636,6 +638,102 @@ func TestWalkDirectoryErrSkipObjContinues(t *testing.T) {
 	compareResultsOrdered("dir errskipobj continues", res, expected, t)
 }
 
+// latencyFS adds a random readdir latency, so the directories are read
+// ahead of the walk and complete out of order, and tracks the concurrent
+// readdirs
+type latencyFS struct {
+	fstest.MapFS
+
+	mu            sync.Mutex
+	rnd           *rand.Rand
+	running       int
+	maxConcurrent int
+}
+
+func (l *latencyFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	l.mu.Lock()
+	pause := time.Duration(250+l.rnd.Intn(500)) * time.Microsecond
+	l.running++
+	l.maxConcurrent = max(l.maxConcurrent, l.running)
+	l.mu.Unlock()
+
+	time.Sleep(pause)
+
+	l.mu.Lock()
+	l.running--
+	l.mu.Unlock()
+	return l.MapFS.ReadDir(name)
+}
+
+// TestWalkPrefetchOrder checks that the directories read ahead of the
+// walk don't change the listing order, markers and max-keys truncation,
+// by paging through a deep tree and comparing with the sorted keys.
+func TestWalkPrefetchOrder(t *testing.T) {
+	mapfs := fstest.MapFS{}
+	var keys []string
+	for i := range 6 {
+		for j := range 4 {
+			for k := range 3 {
+				for _, name := range []string{"f", "f.b", "g"} {
+					key := fmt.Sprintf("d%d/s%d/t%d/%s", i, j, k, name)
+					mapfs[key] = &fstest.MapFile{}
+					keys = append(keys, key)
+				}
+			}
+			// sorts before the directory s%d/ in the listing
+			key := fmt.Sprintf("d%d/s%d.x", i, j)
+			mapfs[key] = &fstest.MapFile{}
+			keys = append(keys, key)
+		}
+	}
+	slices.Sort(keys)
+
+	lfs := &latencyFS{MapFS: mapfs, rnd: rand.New(rand.NewSource(1))}
+
+	for _, prefix := range []string{"", "d2", "d3/s1/"} {
+		for _, maxObjs := range []int32{7, 50, 1000} {
+			var want []string
+			for _, key := range keys {
+				if strings.HasPrefix(key, prefix) {
+					want = append(want, key)
+				}
+			}
+
+			var got []string
+			marker := ""
+			for {
+				res, err := backend.Walk(context.Background(), lfs, prefix, "", marker, maxObjs, getObjSkipDirs, []string{})
+				if err != nil {
+					t.Fatalf("walk: %v", err)
+				}
+				for _, obj := range res.Objects {
+					got = append(got, *obj.Key)
+				}
+				if !res.Truncated {
+					break
+				}
+				if res.NextMarker == marker {
+					t.Fatalf("prefix %q max %v: marker %q not advanced", prefix, maxObjs, marker)
+				}
+				marker = res.NextMarker
+			}
+
+			if !slices.Equal(got, want) {
+				t.Fatalf("prefix %q max %v:\ngot  %v\nwant %v", prefix, maxObjs, got, want)
+			}
+		}
+	}
+
+	lfs.mu.Lock()
+	defer lfs.mu.Unlock()
+	if lfs.running != 0 {
+		t.Fatalf("%v directory reads still running after the walk", lfs.running)
+	}
+	if lfs.maxConcurrent < 2 {
+		t.Fatalf("directories not read concurrently")
+	}
+}
+
 // TestOrderWalk tests the lexicographic ordering of the object names
 // for the case where readdir sort order of a directory is different
 // than the lexicographic ordering of the full paths. The below has
@@ -25,7 +25,9 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 	"os"
+	"strconv"
 	"strings"
 	"text/tabwriter"
 	"time"
@@ -45,6 +47,7 @@ var (
 	adminRegion   string
 	adminEndpoint string
 	allowInsecure bool
+	adminJSONAPI  bool
 )
 
 func adminCommand() *cli.Command {
@@ -91,6 +94,10 @@ func adminCommand() *cli.Command {
 						Usage:   "projectID for the new user",
 						Aliases: []string{"pi"},
 					},
+					&cli.StringFlag{
+						Name:  "email",
+						Usage: "email address for the new user, used by the emailAddress ACL grants",
+					},
 				},
 			},
 			{
@@ -129,6 +136,10 @@ func adminCommand() *cli.Command {
 						Usage:   "projectID for the new user",
 						Aliases: []string{"pi"},
 					},
+					&cli.StringFlag{
+						Name:  "email",
+						Usage: "the new user email address",
+					},
 				},
 			},
 			{
@@ -168,11 +179,152 @@ func adminCommand() *cli.Command {
 				},
 				Action: changeBucketOwner,
 			},
+			{
+				Name:  "snapshot-bucket",
+				Usage: "Creates a read-only snapshot of the bucket objects in the background",
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:     "bucket",
+						Usage:    "the bucket name to snapshot",
+						Required: true,
+						Aliases:  []string{"b"},
+					},
+					&cli.StringFlag{
+						Name:     "snapshot",
+						Usage:    "the snapshot bucket name to create",
+						Required: true,
+						Aliases:  []string{"s"},
+					},
+					&cli.BoolFlag{
+						Name:  "wait",
+						Usage: "wait for the snapshot objects to be created, instead of returning once the snapshot is started",
+					},
+				},
+				Action: snapshotBucket,
+			},
+			{
+				Name:  "snapshot-status",
+				Usage: "Shows the progress of a snapshot created in the background with snapshot-bucket",
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:     "snapshot",
+						Usage:    "the snapshot bucket name",
+						Required: true,
+						Aliases:  []string{"s"},
+					},
+				},
+				Action: snapshotStatus,
+			},
+			{
+				Name:  "scrub-bucket",
+				Usage: "Verifies the bucket object data against the stored checksums",
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:     "bucket",
+						Usage:    "the bucket name to scrub",
+						Required: true,
+						Aliases:  []string{"b"},
+					},
+				},
+				Action: scrubBucket,
+			},
+			{
+				Name:  "transition-object",
+				Usage: "Transitions the object to the Lustre HSM archive, repeat until the object is released",
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:     "bucket",
+						Usage:    "the object bucket name",
+						Required: true,
+						Aliases:  []string{"b"},
+					},
+					&cli.StringFlag{
+						Name:     "key",
+						Usage:    "the object key",
+						Required: true,
+						Aliases:  []string{"k"},
+					},
+					&cli.StringFlag{
+						Name:  "version-id",
+						Usage: "the object version id, the current version if not set",
+					},
+				},
+				Action: transitionObject,
+			},
+			{
+				Name:  "create-share-link",
+				Usage: "Creates a share link granting access to a bucket, prefix or object without credentials",
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:     "bucket",
+						Usage:    "the shared bucket name",
+						Required: true,
+						Aliases:  []string{"b"},
+					},
+					&cli.StringFlag{
+						Name:    "key",
+						Usage:   "the shared object key, or the shared prefix if it ends with '/'. The whole bucket is shared if omitted",
+						Aliases: []string{"k"},
+					},
+					&cli.StringSliceFlag{
+						Name:    "permission",
+						Usage:   "the link permissions: read, upload",
+						Value:   cli.NewStringSlice(string(auth.SharePermissionRead)),
+						Aliases: []string{"p"},
+					},
+					&cli.StringFlag{
+						Name:     "expires-in",
+						Usage:    "the link lifetime, e.g. 36h, 90d",
+						Required: true,
+						Aliases:  []string{"e"},
+					},
+					&cli.StringFlag{
+						Name:  "password",
+						Usage: "the optional link password",
+					},
+					&cli.IntFlag{
+						Name:  "download-limit",
+						Usage: "the maximum number of object downloads, unlimited if 0",
+					},
+					&cli.StringFlag{
+						Name:  "url",
+						Usage: "the gateway s3 endpoint url to build the link with, defaults to the admin endpoint url",
+					},
+				},
+				Action: createShareLink,
+			},
+			{
+				Name:   "list-share-links",
+				Usage:  "Lists all the share links",
+				Action: listShareLinks,
+			},
+			{
+				Name:  "revoke-share-link",
+				Usage: "Revokes a share link",
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:     "id",
+						Usage:    "the share link id",
+						Required: true,
+					},
+				},
+				Action: revokeShareLink,
+			},
 			{
 				Name:   "list-buckets",
 				Usage:  "Lists all the gateway buckets and owners.",
 				Action: listBuckets,
 			},
+			{
+				Name:   "get-config",
+				Usage:  "Shows the gateway configuration, requires the gateway admin port",
+				Action: getConfig,
+			},
+			{
+				Name:   "reload-config",
+				Usage:  "Reloads the gateway configuration file and shows the applied settings and the settings that require a restart, requires the gateway admin port",
+				Action: reloadConfig,
+			},
 			{
 				Name:   "create-bucket",
 				Usage:  "Create a new bucket with owner",
@@ -273,6 +425,12 @@ func adminCommand() *cli.Command {
 				Aliases:     []string{"ai"},
 				Destination: &allowInsecure,
 			},
+			&cli.BoolFlag{
+				Name:        "json-api",
+				Usage:       "use the versioned json admin api, served on the gateway admin port only",
+				EnvVars:     []string{"ADMIN_JSON_API"},
+				Destination: &adminJSONAPI,
+			},
 		},
 	}
 }
@@ -331,6 +489,19 @@ func createUser(ctx *cli.Context) error {
 		UserID:    userID,
 		GroupID:   groupID,
 		ProjectID: projectID,
+		Email:     ctx.String("email"),
+	}
+
+	if adminJSONAPI {
+		return adminV1Request(http.MethodPost, "/users", s3response.AdminUser{
+			Access:    acc.Access,
+			Secret:    acc.Secret,
+			Role:      string(acc.Role),
+			UserID:    acc.UserID,
+			GroupID:   acc.GroupID,
+			ProjectID: acc.ProjectID,
+			Email:     acc.Email,
+		}, nil)
 	}
 
 	accxml, err := xml.Marshal(acc)
@@ -385,6 +556,10 @@ func deleteUser(ctx *cli.Context) error {
 		return fmt.Errorf("invalid input parameter for the user access key")
 	}
 
+	if adminJSONAPI {
+		return adminV1Request(http.MethodDelete, "/users/"+url.PathEscape(access), nil, nil)
+	}
+
 	req, err := http.NewRequest(http.MethodPatch, fmt.Sprintf("%v/delete-user?access=%v", adminEndpoint, access), nil)
 	if err != nil {
 		return fmt.Errorf("failed to send the request: %w", err)
@@ -455,6 +630,14 @@ func updateUser(ctx *cli.Context) error {
 	if ctx.IsSet("project-id") {
 		props.ProjectID = &projectID
 	}
+	if ctx.IsSet("email") {
+		email := ctx.String("email")
+		props.Email = &email
+	}
+
+	if adminJSONAPI {
+		return adminV1Request(http.MethodPatch, "/users/"+url.PathEscape(access), props, nil)
+	}
 
 	propsxml, err := xml.Marshal(props)
 	if err != nil {
@@ -504,6 +687,28 @@ func listUsers(ctx *cli.Context) error {
 		return err
 	}
 
+	if adminJSONAPI {
+		var accs []auth.Account
+		err := adminV1List("/users", func(page []s3response.AdminUser) {
+			for _, usr := range page {
+				accs = append(accs, auth.Account{
+					Access:    usr.Access,
+					Role:      auth.Role(usr.Role),
+					UserID:    usr.UserID,
+					GroupID:   usr.GroupID,
+					ProjectID: usr.ProjectID,
+					Email:     usr.Email,
+				})
+			}
+		})
+		if err != nil {
+			return err
+		}
+
+		printAcctTable(accs)
+		return nil
+	}
+
 	req, err := http.NewRequest(http.MethodPatch, fmt.Sprintf("%v/list-users", adminEndpoint), nil)
 	if err != nil {
 		return fmt.Errorf("failed to send the request: %w", err)
@@ -821,6 +1026,11 @@ func changeBucketOwner(ctx *cli.Context) error {
 	}
 
 	bucket, owner := ctx.String("bucket"), ctx.String("owner")
+	if adminJSONAPI {
+		return adminV1Request(http.MethodPut, "/buckets/"+url.PathEscape(bucket)+"/owner",
+			s3response.AdminBucketOwner{Owner: owner}, nil)
+	}
+
 	req, err := http.NewRequest(http.MethodPatch, fmt.Sprintf("%v/change-bucket-owner/?bucket=%v&owner=%v", adminEndpoint, bucket, owner), nil)
 	if err != nil {
 		return fmt.Errorf("failed to send the request: %w", err)
@@ -858,25 +1068,153 @@ func changeBucketOwner(ctx *cli.Context) error {
 	return nil
 }
 
-func printBuckets(buckets []s3response.Bucket) {
-	w := new(tabwriter.Writer)
-	w.Init(os.Stdout, minwidth, tabwidth, padding, padchar, flags)
-	fmt.Fprintln(w, "Bucket\tOwner")
-	fmt.Fprintln(w, "-------\t----")
-	for _, acc := range buckets {
-		fmt.Fprintf(w, "%v\t%v\n", acc.Name, acc.Owner)
+func snapshotBucket(ctx *cli.Context) error {
+	adminAccess, adminSecret, err := getAdminCreds()
+	if err != nil {
+		return err
+	}
+
+	bucket, snapshot := ctx.String("bucket"), ctx.String("snapshot")
+	req, err := http.NewRequest(http.MethodPatch, fmt.Sprintf("%v/snapshot-bucket/?bucket=%v&snapshot=%v", adminEndpoint, bucket, snapshot), nil)
+	if err != nil {
+		return fmt.Errorf("failed to send the request: %w", err)
+	}
+
+	signer := v4.NewSigner()
+
+	hashedPayload := sha256.Sum256([]byte{})
+	hexPayload := hex.EncodeToString(hashedPayload[:])
+
+	req.Header.Set("X-Amz-Content-Sha256", hexPayload)
+
+	signErr := signer.SignHTTP(req.Context(), aws.Credentials{AccessKeyID: adminAccess, SecretAccessKey: adminSecret}, req, hexPayload, "s3", adminRegion, time.Now())
+	if signErr != nil {
+		return fmt.Errorf("failed to sign the request: %w", signErr)
+	}
+
+	client := initHTTPClient()
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send the request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode >= 400 {
+		return parseApiError(body)
+	}
+
+	if !ctx.Bool("wait") {
+		fmt.Printf("Snapshot %v of bucket %v started, use snapshot-status to get the progress\n", snapshot, bucket)
+		return nil
+	}
+
+	for {
+		status, err := getSnapshotStatus(snapshot)
+		if err != nil {
+			return err
+		}
+		if status.Status != s3response.SnapshotRunning {
+			printSnapshotStatus(status)
+			if status.Status == s3response.SnapshotFailed {
+				return fmt.Errorf("snapshot failed: %v", status.Error)
+			}
+			return nil
+		}
+		time.Sleep(snapshotPollInterval)
 	}
-	fmt.Fprintln(w)
-	w.Flush()
 }
 
-func listBuckets(ctx *cli.Context) error {
+// snapshotPollInterval is the interval the snapshot
+// status is polled at with snapshot-bucket --wait
+const snapshotPollInterval = 2 * time.Second
+
+func snapshotStatus(ctx *cli.Context) error {
+	status, err := getSnapshotStatus(ctx.String("snapshot"))
+	if err != nil {
+		return err
+	}
+
+	printSnapshotStatus(status)
+	return nil
+}
+
+func getSnapshotStatus(snapshot string) (s3response.SnapshotStatus, error) {
+	adminAccess, adminSecret, err := getAdminCreds()
+	if err != nil {
+		return s3response.SnapshotStatus{}, err
+	}
+
+	req, err := http.NewRequest(http.MethodPatch, fmt.Sprintf("%v/snapshot-bucket-status/?snapshot=%v", adminEndpoint, snapshot), nil)
+	if err != nil {
+		return s3response.SnapshotStatus{}, fmt.Errorf("failed to send the request: %w", err)
+	}
+
+	signer := v4.NewSigner()
+
+	hashedPayload := sha256.Sum256([]byte{})
+	hexPayload := hex.EncodeToString(hashedPayload[:])
+
+	req.Header.Set("X-Amz-Content-Sha256", hexPayload)
+
+	signErr := signer.SignHTTP(req.Context(), aws.Credentials{AccessKeyID: adminAccess, SecretAccessKey: adminSecret}, req, hexPayload, "s3", adminRegion, time.Now())
+	if signErr != nil {
+		return s3response.SnapshotStatus{}, fmt.Errorf("failed to sign the request: %w", signErr)
+	}
+
+	client := initHTTPClient()
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return s3response.SnapshotStatus{}, fmt.Errorf("failed to send the request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return s3response.SnapshotStatus{}, err
+	}
+
+	if resp.StatusCode >= 400 {
+		return s3response.SnapshotStatus{}, parseApiError(body)
+	}
+
+	var status s3response.SnapshotStatus
+	if err := xml.Unmarshal(body, &status); err != nil {
+		return s3response.SnapshotStatus{}, err
+	}
+
+	return status, nil
+}
+
+func printSnapshotStatus(status s3response.SnapshotStatus) {
+	fmt.Printf("Snapshot: %v\n", status.Snapshot)
+	fmt.Printf("Source bucket: %v\n", status.Source)
+	fmt.Printf("Status: %v\n", status.Status)
+	if status.Objects > 0 {
+		fmt.Printf("Objects: %v\n", status.Objects)
+	}
+	fmt.Printf("Started: %v\n", status.StartTime.Local().Format(time.RFC3339))
+	if !status.EndTime.IsZero() {
+		fmt.Printf("Duration: %v\n", status.EndTime.Sub(status.StartTime).Round(time.Millisecond))
+	}
+	if status.Error != "" {
+		fmt.Printf("Error: %v\n", status.Error)
+	}
+}
+
+func scrubBucket(ctx *cli.Context) error {
 	adminAccess, adminSecret, err := getAdminCreds()
 	if err != nil {
 		return err
 	}
 
-	req, err := http.NewRequest(http.MethodPatch, fmt.Sprintf("%v/list-buckets", adminEndpoint), nil)
+	req, err := http.NewRequest(http.MethodPatch, fmt.Sprintf("%v/scrub-bucket/?bucket=%v", adminEndpoint, ctx.String("bucket")), nil)
 	if err != nil {
 		return fmt.Errorf("failed to send the request: %w", err)
 	}
@@ -890,7 +1228,7 @@ func listBuckets(ctx *cli.Context) error {
 
 	signErr := signer.SignHTTP(req.Context(), aws.Credentials{AccessKeyID: adminAccess, SecretAccessKey: adminSecret}, req, hexPayload, "s3", adminRegion, time.Now())
 	if signErr != nil {
-		return fmt.Errorf("failed to sign the request: %w", err)
+		return fmt.Errorf("failed to sign the request: %w", signErr)
 	}
 
 	client := initHTTPClient()
@@ -910,23 +1248,509 @@ func listBuckets(ctx *cli.Context) error {
 		return parseApiError(body)
 	}
 
-	var result s3response.ListBucketsResult
-	if err := xml.Unmarshal(body, &result); err != nil {
+	var report s3response.ScrubReport
+	if err := xml.Unmarshal(body, &report); err != nil {
 		return err
 	}
 
-	printBuckets(result.Buckets)
+	printScrubReport(report)
 
 	return nil
 }
 
-func parseApiError(body []byte) error {
-	var apiErr smithy.GenericAPIError
-	err := xml.Unmarshal(body, &apiErr)
+func printScrubReport(report s3response.ScrubReport) {
+	fmt.Printf("Bucket: %v\n", report.Bucket)
+	fmt.Printf("Objects verified: %v (%v bytes)\n", report.Objects, report.Bytes)
+	fmt.Printf("Objects skipped: %v\n", report.Skipped)
+	fmt.Printf("Duration: %v\n", report.EndTime.Sub(report.StartTime).Round(time.Millisecond))
+	fmt.Println()
+
+	if len(report.Mismatches) == 0 {
+		return
+	}
+
+	w := new(tabwriter.Writer)
+	w.Init(os.Stdout, minwidth, tabwidth, padding, padchar, flags)
+	fmt.Fprintln(w, "Key\tVersionId\tAlgorithm\tExpected\tActual\tQuarantined")
+	fmt.Fprintln(w, "---\t---------\t---------\t--------\t------\t-----------")
+	for _, m := range report.Mismatches {
+		fmt.Fprintf(w, "%v\t%v\t%v\t%v\t%v\t%v\n", m.Key, m.VersionId, m.Algorithm, m.Expected, m.Actual, m.Quarantined)
+	}
+	fmt.Fprintln(w)
+	w.Flush()
+}
+
+func transitionObject(ctx *cli.Context) error {
+	adminAccess, adminSecret, err := getAdminCreds()
 	if err != nil {
-		apiErr.Code = "InternalServerError"
-		apiErr.Message = err.Error()
+		return err
 	}
 
-	return &apiErr
+	query := url.Values{}
+	query.Set("bucket", ctx.String("bucket"))
+	query.Set("key", ctx.String("key"))
+	if ctx.IsSet("version-id") {
+		query.Set("versionId", ctx.String("version-id"))
+	}
+
+	req, err := http.NewRequest(http.MethodPatch, fmt.Sprintf("%v/transition-object/?%v", adminEndpoint, query.Encode()), nil)
+	if err != nil {
+		return fmt.Errorf("failed to send the request: %w", err)
+	}
+
+	signer := v4.NewSigner()
+
+	hashedPayload := sha256.Sum256([]byte{})
+	hexPayload := hex.EncodeToString(hashedPayload[:])
+
+	req.Header.Set("X-Amz-Content-Sha256", hexPayload)
+
+	signErr := signer.SignHTTP(req.Context(), aws.Credentials{AccessKeyID: adminAccess, SecretAccessKey: adminSecret}, req, hexPayload, "s3", adminRegion, time.Now())
+	if signErr != nil {
+		return fmt.Errorf("failed to sign the request: %w", signErr)
+	}
+
+	client := initHTTPClient()
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send the request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return err
+		}
+		return parseApiError(body)
+	}
+
+	return nil
+}
+
+// parseShareExpiry parses the share link lifetime, a duration
+// with the additional 'd' days unit
+func parseShareExpiry(s string) (time.Duration, error) {
+	if days, found := strings.CutSuffix(s, "d"); found {
+		n, err := strconv.Atoi(days)
+		if err != nil || n <= 0 {
+			return 0, fmt.Errorf("invalid expires-in days: %v", s)
+		}
+		return time.Duration(n) * 24 * time.Hour, nil
+	}
+
+	d, err := time.ParseDuration(s)
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("invalid expires-in duration: %v", s)
+	}
+	return d, nil
+}
+
+func createShareLink(ctx *cli.Context) error {
+	adminAccess, adminSecret, err := getAdminCreds()
+	if err != nil {
+		return err
+	}
+
+	expiresIn, err := parseShareExpiry(ctx.String("expires-in"))
+	if err != nil {
+		return err
+	}
+
+	var perms []auth.SharePermission
+	for _, p := range ctx.StringSlice("permission") {
+		for _, perm := range strings.Split(p, ",") {
+			perms = append(perms, auth.SharePermission(strings.TrimSpace(perm)))
+		}
+	}
+
+	input, err := xml.Marshal(auth.ShareLink{
+		Bucket:        ctx.String("bucket"),
+		Key:           ctx.String("key"),
+		Permissions:   perms,
+		Expires:       time.Now().Add(expiresIn),
+		Password:      ctx.String("password"),
+		DownloadLimit: ctx.Int("download-limit"),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to parse share link data: %w", err)
+	}
+
+	req, err := http.NewRequest(http.MethodPatch, fmt.Sprintf("%v/create-share-link", adminEndpoint), bytes.NewBuffer(input))
+	if err != nil {
+		return fmt.Errorf("failed to send the request: %w", err)
+	}
+
+	signer := v4.NewSigner()
+
+	hashedPayload := sha256.Sum256(input)
+	hexPayload := hex.EncodeToString(hashedPayload[:])
+
+	req.Header.Set("X-Amz-Content-Sha256", hexPayload)
+
+	signErr := signer.SignHTTP(req.Context(), aws.Credentials{AccessKeyID: adminAccess, SecretAccessKey: adminSecret}, req, hexPayload, "s3", adminRegion, time.Now())
+	if signErr != nil {
+		return fmt.Errorf("failed to sign the request: %w", signErr)
+	}
+
+	client := initHTTPClient()
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send the request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode >= 400 {
+		return parseApiError(body)
+	}
+
+	var link auth.ShareLink
+	if err := xml.Unmarshal(body, &link); err != nil {
+		return err
+	}
+
+	endpoint := ctx.String("url")
+	if endpoint == "" {
+		endpoint = adminEndpoint
+	}
+
+	fmt.Printf("ID: %v\n", link.ID)
+	fmt.Printf("URL: %v/_share/%v\n", strings.TrimSuffix(endpoint, "/"), link.Token)
+	fmt.Printf("Expires: %v\n", link.Expires.Format(time.RFC3339))
+
+	return nil
+}
+
+func listShareLinks(ctx *cli.Context) error {
+	adminAccess, adminSecret, err := getAdminCreds()
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPatch, fmt.Sprintf("%v/list-share-links", adminEndpoint), nil)
+	if err != nil {
+		return fmt.Errorf("failed to send the request: %w", err)
+	}
+
+	signer := v4.NewSigner()
+
+	hashedPayload := sha256.Sum256([]byte{})
+	hexPayload := hex.EncodeToString(hashedPayload[:])
+
+	req.Header.Set("X-Amz-Content-Sha256", hexPayload)
+
+	signErr := signer.SignHTTP(req.Context(), aws.Credentials{AccessKeyID: adminAccess, SecretAccessKey: adminSecret}, req, hexPayload, "s3", adminRegion, time.Now())
+	if signErr != nil {
+		return fmt.Errorf("failed to sign the request: %w", signErr)
+	}
+
+	client := initHTTPClient()
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send the request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode >= 400 {
+		return parseApiError(body)
+	}
+
+	var result auth.ListShareLinksResult
+	if err := xml.Unmarshal(body, &result); err != nil {
+		return err
+	}
+
+	printShareLinks(result.ShareLinks)
+
+	return nil
+}
+
+func printShareLinks(links []auth.ShareLink) {
+	w := new(tabwriter.Writer)
+	w.Init(os.Stdout, minwidth, tabwidth, padding, padchar, flags)
+	fmt.Fprintln(w, "ID\tBucket\tKey\tPermissions\tExpires\tPassword\tDownloads\tCreator")
+	fmt.Fprintln(w, "--\t------\t---\t-----------\t-------\t--------\t---------\t-------")
+	for _, l := range links {
+		perms := make([]string, 0, len(l.Permissions))
+		for _, p := range l.Permissions {
+			perms = append(perms, string(p))
+		}
+		downloads := fmt.Sprint(l.Downloads)
+		if l.DownloadLimit > 0 {
+			downloads = fmt.Sprintf("%v/%v", l.Downloads, l.DownloadLimit)
+		}
+		fmt.Fprintf(w, "%v\t%v\t%v\t%v\t%v\t%v\t%v\t%v\n", l.ID, l.Bucket, l.Key,
+			strings.Join(perms, ","), l.Expires.Format(time.RFC3339), l.PasswordProtected, downloads, l.Creator)
+	}
+	fmt.Fprintln(w)
+	w.Flush()
+}
+
+func revokeShareLink(ctx *cli.Context) error {
+	adminAccess, adminSecret, err := getAdminCreds()
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPatch, fmt.Sprintf("%v/revoke-share-link?id=%v", adminEndpoint, ctx.String("id")), nil)
+	if err != nil {
+		return fmt.Errorf("failed to send the request: %w", err)
+	}
+
+	signer := v4.NewSigner()
+
+	hashedPayload := sha256.Sum256([]byte{})
+	hexPayload := hex.EncodeToString(hashedPayload[:])
+
+	req.Header.Set("X-Amz-Content-Sha256", hexPayload)
+
+	signErr := signer.SignHTTP(req.Context(), aws.Credentials{AccessKeyID: adminAccess, SecretAccessKey: adminSecret}, req, hexPayload, "s3", adminRegion, time.Now())
+	if signErr != nil {
+		return fmt.Errorf("failed to sign the request: %w", signErr)
+	}
+
+	client := initHTTPClient()
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send the request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode >= 400 {
+		return parseApiError(body)
+	}
+
+	return nil
+}
+
+func printBuckets(buckets []s3response.Bucket) {
+	w := new(tabwriter.Writer)
+	w.Init(os.Stdout, minwidth, tabwidth, padding, padchar, flags)
+	fmt.Fprintln(w, "Bucket\tOwner")
+	fmt.Fprintln(w, "-------\t----")
+	for _, acc := range buckets {
+		fmt.Fprintf(w, "%v\t%v\n", acc.Name, acc.Owner)
+	}
+	fmt.Fprintln(w)
+	w.Flush()
+}
+
+func listBuckets(ctx *cli.Context) error {
+	adminAccess, adminSecret, err := getAdminCreds()
+	if err != nil {
+		return err
+	}
+
+	if adminJSONAPI {
+		var buckets []s3response.Bucket
+		err := adminV1List("/buckets", func(page []s3response.Bucket) {
+			buckets = append(buckets, page...)
+		})
+		if err != nil {
+			return err
+		}
+
+		printBuckets(buckets)
+		return nil
+	}
+
+	req, err := http.NewRequest(http.MethodPatch, fmt.Sprintf("%v/list-buckets", adminEndpoint), nil)
+	if err != nil {
+		return fmt.Errorf("failed to send the request: %w", err)
+	}
+
+	signer := v4.NewSigner()
+
+	hashedPayload := sha256.Sum256([]byte{})
+	hexPayload := hex.EncodeToString(hashedPayload[:])
+
+	req.Header.Set("X-Amz-Content-Sha256", hexPayload)
+
+	signErr := signer.SignHTTP(req.Context(), aws.Credentials{AccessKeyID: adminAccess, SecretAccessKey: adminSecret}, req, hexPayload, "s3", adminRegion, time.Now())
+	if signErr != nil {
+		return fmt.Errorf("failed to sign the request: %w", err)
+	}
+
+	client := initHTTPClient()
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send the request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode >= 400 {
+		return parseApiError(body)
+	}
+
+	var result s3response.ListBucketsResult
+	if err := xml.Unmarshal(body, &result); err != nil {
+		return err
+	}
+
+	printBuckets(result.Buckets)
+
+	return nil
+}
+
+func parseApiError(body []byte) error {
+	var apiErr smithy.GenericAPIError
+	err := xml.Unmarshal(body, &apiErr)
+	if err != nil {
+		apiErr.Code = "InternalServerError"
+		apiErr.Message = err.Error()
+	}
+
+	return &apiErr
+}
+
+func getConfig(ctx *cli.Context) error {
+	var cfg s3response.AdminGatewayConfig
+	if err := adminV1Request(http.MethodGet, "/config", nil, &cfg); err != nil {
+		return err
+	}
+
+	b, err := json.MarshalIndent(cfg, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(string(b))
+	return nil
+}
+
+func reloadConfig(ctx *cli.Context) error {
+	var res s3response.AdminConfigReload
+	if err := adminV1Request(http.MethodPost, "/config/reload", nil, &res); err != nil {
+		return err
+	}
+
+	if len(res.Applied) == 0 {
+		fmt.Println("No settings changed")
+	} else {
+		fmt.Printf("Applied: %s\n", strings.Join(res.Applied, ", "))
+	}
+	if len(res.RestartRequired) > 0 {
+		fmt.Printf("Restart required: %s\n", strings.Join(res.RestartRequired, ", "))
+	}
+	return nil
+}
+
+// adminV1Request sends a signed admin json api request with the json
+// encoded input, and decodes the response body into output if set
+func adminV1Request(method, path string, input, output any) error {
+	adminAccess, adminSecret, err := getAdminCreds()
+	if err != nil {
+		return err
+	}
+
+	var payload []byte
+	if input != nil {
+		payload, err = json.Marshal(input)
+		if err != nil {
+			return fmt.Errorf("failed to parse request data: %w", err)
+		}
+	}
+
+	req, err := http.NewRequest(method, strings.TrimSuffix(adminEndpoint, "/")+"/admin/v1"+path, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("failed to send the request: %w", err)
+	}
+	if input != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	signer := v4.NewSigner()
+
+	hashedPayload := sha256.Sum256(payload)
+	hexPayload := hex.EncodeToString(hashedPayload[:])
+
+	req.Header.Set("X-Amz-Content-Sha256", hexPayload)
+
+	signErr := signer.SignHTTP(req.Context(), aws.Credentials{AccessKeyID: adminAccess, SecretAccessKey: adminSecret}, req, hexPayload, "s3", adminRegion, time.Now())
+	if signErr != nil {
+		return fmt.Errorf("failed to sign the request: %w", signErr)
+	}
+
+	client := initHTTPClient()
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send the request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode >= 400 {
+		return parseApiV1Error(resp.StatusCode, body)
+	}
+
+	if output == nil {
+		return nil
+	}
+	return json.Unmarshal(body, output)
+}
+
+// adminV1List gets all the pages of an admin json api list
+func adminV1List[T any](path string, fn func([]T)) error {
+	marker := ""
+	for {
+		var page s3response.AdminList[T]
+		err := adminV1Request(http.MethodGet, path+"?marker="+url.QueryEscape(marker), nil, &page)
+		if err != nil {
+			return err
+		}
+
+		fn(page.Items)
+
+		if page.NextMarker == "" {
+			return nil
+		}
+		marker = page.NextMarker
+	}
+}
+
+func parseApiV1Error(status int, body []byte) error {
+	var apiErr s3response.AdminError
+	err := json.Unmarshal(body, &apiErr)
+	if err != nil || apiErr.Error.Code == "" {
+		// the admin json api is not served on the S3 port
+		return &smithy.GenericAPIError{
+			Code:    http.StatusText(status),
+			Message: fmt.Sprintf("unexpected admin api response status %v, is the endpoint the gateway admin port?", status),
+		}
+	}
+
+	return &smithy.GenericAPIError{
+		Code:    apiErr.Error.Code,
+		Message: apiErr.Error.Message,
+	}
 }
@@ -0,0 +1,600 @@
+// Copyright 2026 Versity Software
+// This file is licensed under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/urfave/cli/v2"
+	"github.com/versity/versitygw/auth"
+	"github.com/versity/versitygw/debuglogger"
+	"github.com/versity/versitygw/metrics"
+	"github.com/versity/versitygw/s3api"
+	"github.com/versity/versitygw/s3event"
+	"github.com/versity/versitygw/s3log"
+	"github.com/versity/versitygw/s3response"
+	"gopkg.in/yaml.v3"
+)
+
+// reloadableFlags are the gateway flags applied to the running
+// gateway on a configuration file reload. The other changed flags
+// take effect after the gateway restart.
+var reloadableFlags = map[string]bool{
+	// access logs
+	"access-log":                 true,
+	"admin-access-log":           true,
+	"log-webhook-url":            true,
+	"log-webhook-batch-size":     true,
+	"log-webhook-flush-interval": true,
+	"log-webhook-queue-size":     true,
+	"log-webhook-retries":        true,
+	"log-webhook-gzip":           true,
+	"log-webhook-bearer-token":   true,
+	"log-webhook-hmac-secret":    true,
+	"log-webhook-spool-dir":      true,
+	"log-webhook-spool-max-size": true,
+	"log-syslog-url":             true,
+	"access-log-format":          true,
+	"access-log-max-size":        true,
+	"access-log-rotate-interval": true,
+	"access-log-max-backups":     true,
+	"access-log-compress":        true,
+	// event notifications
+	"event-kafka-url":            true,
+	"event-kafka-topic":          true,
+	"event-kafka-key":            true,
+	"event-nats-url":             true,
+	"event-nats-topic":           true,
+	"event-rabbitmq-url":         true,
+	"event-rabbitmq-exchange":    true,
+	"event-rabbitmq-routing-key": true,
+	"event-webhook-url":          true,
+	"event-filter":               true,
+	// metrics
+	"metrics-service-name":      true,
+	"metrics-statsd-servers":    true,
+	"metrics-dogstatsd-servers": true,
+	// request handling
+	"max-requests":       true,
+	"admin-max-requests": true,
+	"cors-allow-origin":  true,
+	"iam-cache-ttl":      true,
+	"readonly":           true,
+}
+
+// configFlags are the flags that can not be set in the configuration file
+var configFlags = map[string]bool{
+	"config":                true,
+	"config-watch-interval": true,
+	"version":               true,
+	"help":                  true,
+}
+
+// gatewayConfigFile is the configuration file of the gateway, it is
+// loaded before the gateway flags are read. The file is a YAML
+// mapping of the long gateway flag names to the flag values, the
+// flags set on the command line or in the environment take
+// precedence over the file settings:
+//
+//	port: [":7070", ":7071"]
+//	access-log: /var/log/versitygw/access.log
+//	max-requests: 5000
+//	readonly: false
+type gatewayConfigFile struct {
+	ctx  *cli.Context
+	path string
+	// flags maps the flag names and aliases to the flags
+	flags map[string]cli.Flag
+	// overridden are the flags set on the command line
+	// or in the environment
+	overridden map[string]bool
+	// defaults are the reloadable flag values before
+	// the file is applied
+	defaults map[string]string
+	// startup are the file settings applied on the startup
+	startup map[string][]string
+	// applied are the file settings currently applied
+	applied map[string][]string
+	modTime time.Time
+}
+
+// gwConfigFile is set when the gateway runs with a configuration file
+var gwConfigFile *gatewayConfigFile
+
+// loadConfigFile applies the configuration file settings to the
+// flags of the context that are not set otherwise
+func loadConfigFile(ctx *cli.Context, path string) (*gatewayConfigFile, error) {
+	cf := &gatewayConfigFile{
+		ctx:        ctx,
+		path:       path,
+		flags:      map[string]cli.Flag{},
+		overridden: map[string]bool{},
+		defaults:   map[string]string{},
+	}
+
+	for _, f := range ctx.App.Flags {
+		for _, name := range f.Names() {
+			cf.flags[name] = f
+		}
+
+		name := f.Names()[0]
+		if ctx.IsSet(name) {
+			cf.overridden[name] = true
+			continue
+		}
+		if reloadableFlags[name] {
+			cf.defaults[name] = fmt.Sprint(ctx.Value(name))
+		}
+	}
+
+	settings, modTime, err := cf.read()
+	if err != nil {
+		return nil, err
+	}
+
+	for name, values := range settings {
+		for _, v := range values {
+			err := ctx.Set(name, v)
+			if err != nil {
+				return nil, fmt.Errorf("config file %v: invalid %v value %q: %w",
+					path, name, v, err)
+			}
+		}
+	}
+
+	cf.startup = settings
+	cf.applied = settings
+	cf.modTime = modTime
+
+	return cf, nil
+}
+
+// read parses the configuration file settings, the settings of
+// the overridden flags are skipped
+func (cf *gatewayConfigFile) read() (map[string][]string, time.Time, error) {
+	fi, err := os.Stat(cf.path)
+	if err != nil {
+		return nil, time.Time{}, fmt.Errorf("stat config file: %w", err)
+	}
+
+	b, err := os.ReadFile(cf.path)
+	if err != nil {
+		return nil, time.Time{}, fmt.Errorf("read config file: %w", err)
+	}
+
+	var doc map[string]any
+	err = yaml.Unmarshal(b, &doc)
+	if err != nil {
+		return nil, time.Time{}, fmt.Errorf("parse config file %v: %w", cf.path, err)
+	}
+
+	settings := make(map[string][]string, len(doc))
+	for key, value := range doc {
+		f, ok := cf.flags[key]
+		if !ok || configFlags[key] {
+			return nil, time.Time{}, fmt.Errorf("config file %v: unknown setting %q", cf.path, key)
+		}
+		name := f.Names()[0]
+		if _, ok := settings[name]; ok {
+			return nil, time.Time{}, fmt.Errorf("config file %v: duplicate setting %q", cf.path, name)
+		}
+
+		values, err := configValues(value)
+		if err != nil {
+			return nil, time.Time{}, fmt.Errorf("config file %v: setting %q: %w", cf.path, key, err)
+		}
+		if _, isSlice := f.(*cli.StringSliceFlag); !isSlice && len(values) != 1 {
+			return nil, time.Time{}, fmt.Errorf("config file %v: setting %q expects a single value", cf.path, key)
+		}
+
+		if cf.overridden[name] {
+			continue
+		}
+		settings[name] = values
+	}
+
+	return settings, fi.ModTime(), nil
+}
+
+// configValues converts the YAML scalar or sequence to the flag values
+func configValues(value any) ([]string, error) {
+	switch v := value.(type) {
+	case nil:
+		return nil, fmt.Errorf("missing value")
+	case []any:
+		values := make([]string, 0, len(v))
+		for _, item := range v {
+			switch item.(type) {
+			case nil, []any, map[string]any:
+				return nil, fmt.Errorf("expected a list of scalar values")
+			}
+			values = append(values, fmt.Sprint(item))
+		}
+		return values, nil
+	case map[string]any:
+		return nil, fmt.Errorf("expected a scalar value or a list")
+	default:
+		return []string{fmt.Sprint(v)}, nil
+	}
+}
+
+// runtimeConfig holds the gateway settings that can change
+// while the gateway is running
+type runtimeConfig struct {
+	log              s3log.LogConfig
+	event            s3event.EventConfig
+	metrics          metrics.Config
+	maxRequests      int
+	adminMaxRequests int
+	corsAllowOrigin  string
+	iamCacheTTL      int
+	readonly         bool
+}
+
+// newRuntimeConfig validates and collects the reloadable
+// settings from the gateway flags
+func newRuntimeConfig() (runtimeConfig, error) {
+	if maxRequests < 1 {
+		return runtimeConfig{}, fmt.Errorf("max-requests must be positive")
+	}
+	if len(admPorts) > 0 && adminMaxRequests < 1 {
+		return runtimeConfig{}, fmt.Errorf("admin-max-requests must be positive")
+	}
+	if accessLogMaxSize < 0 {
+		return runtimeConfig{}, fmt.Errorf("access-log-max-size must not be negative")
+	}
+	if accessLogMaxBackups < 0 {
+		return runtimeConfig{}, fmt.Errorf("access-log-max-backups must not be negative")
+	}
+
+	var logFormat s3log.LogFormat
+	if accessLogFormat != "" || logSyslogURL == "" {
+		var err error
+		logFormat, err = s3log.ParseLogFormat(accessLogFormat)
+		if err != nil {
+			return runtimeConfig{}, err
+		}
+	}
+
+	return runtimeConfig{
+		log: s3log.LogConfig{
+			LogFile:      accessLog,
+			WebhookURL:   logWebhookURL,
+			SyslogURL:    logSyslogURL,
+			AdminLogFile: adminLogFile,
+			Format:       logFormat,
+			Rotate: s3log.RotateConfig{
+				MaxSize:    int64(accessLogMaxSize) * 1024 * 1024,
+				MaxAge:     accessLogRotateInterval,
+				MaxBackups: accessLogMaxBackups,
+				Compress:   accessLogCompress,
+			},
+			Webhook: s3log.WebhookConfig{
+				BatchSize:     logWebhookBatchSize,
+				FlushInterval: logWebhookFlushInterval,
+				QueueSize:     logWebhookQueueSize,
+				MaxRetries:    logWebhookRetries,
+				Gzip:          logWebhookGzip,
+				BearerToken:   logWebhookBearerToken,
+				HMACSecret:    logWebhookHMACSecret,
+				SpoolDir:      logWebhookSpoolDir,
+				SpoolMaxSize:  int64(logWebhookSpoolMaxSize) * 1024 * 1024,
+			},
+		},
+		event: s3event.EventConfig{
+			KafkaURL:             kafkaURL,
+			KafkaTopic:           kafkaTopic,
+			KafkaTopicKey:        kafkaKey,
+			NatsURL:              natsURL,
+			NatsTopic:            natsTopic,
+			RabbitmqURL:          rabbitmqURL,
+			RabbitmqExchange:     rabbitmqExchange,
+			RabbitmqRoutingKey:   rabbitmqRoutingKey,
+			WebhookURL:           eventWebhookURL,
+			FilterConfigFilePath: eventConfigFilePath,
+		},
+		metrics: metrics.Config{
+			ServiceName:      metricsService,
+			StatsdServers:    statsdServers,
+			DogStatsdServers: dogstatsServers,
+		},
+		maxRequests:      maxRequests,
+		adminMaxRequests: adminMaxRequests,
+		corsAllowOrigin:  corsAllowOrigin,
+		iamCacheTTL:      iamCacheTTL,
+		readonly:         readonly,
+	}, nil
+}
+
+// configReloader applies the changed configuration file settings
+// to the running gateway. The loggers, the event sender and the
+// metrics manager are replaced with the new ones, the other
+// settings are changed in place.
+type configReloader struct {
+	mu          sync.Mutex
+	ctx         context.Context
+	file        *gatewayConfigFile
+	current     runtimeConfig
+	base        s3response.AdminGatewayConfig
+	mm          *metrics.ReloadableManager
+	s3Logger    *s3log.ReloadableLogger
+	adminLogger *s3log.ReloadableLogger
+	evSender    *s3event.ReloadableEventSender
+	srv         *s3api.S3ApiServer
+	admSrv      *s3api.S3AdminServer
+	iam         auth.IAMService
+}
+
+// Config returns the current gateway configuration
+func (r *configReloader) Config() s3response.AdminGatewayConfig {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.config()
+}
+
+func (r *configReloader) config() s3response.AdminGatewayConfig {
+	cfg := r.base
+	cfg.ReadOnly = r.current.readonly
+	cfg.CORSAllowOrigin = r.current.corsAllowOrigin
+	cfg.MaxRequests = r.current.maxRequests
+	return cfg
+}
+
+// Reload re-reads the configuration file and applies the changed
+// reloadable settings. The changed settings that can not be applied
+// to the running gateway are reported as requiring a restart.
+func (r *configReloader) Reload() (s3response.AdminConfigReload, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	settings, modTime, err := r.file.read()
+	if err != nil {
+		return s3response.AdminConfigReload{}, err
+	}
+	r.file.modTime = modTime
+
+	var changed []string
+	for _, name := range changedSettings(r.file.applied, settings) {
+		if reloadableFlags[name] {
+			changed = append(changed, name)
+		}
+	}
+	var restart []string
+	for _, name := range changedSettings(r.file.startup, settings) {
+		if !reloadableFlags[name] {
+			restart = append(restart, name)
+		}
+	}
+
+	result := s3response.AdminConfigReload{
+		Applied:         []string{},
+		RestartRequired: restart,
+	}
+	if result.RestartRequired == nil {
+		result.RestartRequired = []string{}
+	}
+
+	if len(changed) > 0 {
+		err = r.apply(changed, settings)
+		if err != nil {
+			return s3response.AdminConfigReload{}, err
+		}
+		result.Applied = changed
+	}
+
+	r.file.applied = settings
+
+	result.Config = r.config()
+	return result, nil
+}
+
+// apply sets the changed flags and swaps the gateway components
+// using them. The flags are restored if any component fails
+// to initialize with the new settings.
+func (r *configReloader) apply(changed []string, settings map[string][]string) error {
+	saved := make(map[string]string, len(changed))
+	for _, name := range changed {
+		saved[name] = fmt.Sprint(r.file.ctx.Value(name))
+	}
+	restore := func() {
+		for name, v := range saved {
+			r.file.ctx.Set(name, v)
+		}
+	}
+
+	for _, name := range changed {
+		v, ok := r.file.defaults[name]
+		if values, found := settings[name]; found {
+			v, ok = values[0], true
+		}
+		if !ok {
+			continue
+		}
+		err := r.file.ctx.Set(name, v)
+		if err != nil {
+			restore()
+			return fmt.Errorf("config file %v: invalid %v value %q: %w",
+				r.file.path, name, v, err)
+		}
+	}
+
+	next, err := newRuntimeConfig()
+	if err != nil {
+		restore()
+		return err
+	}
+	if len(webuiPorts) > 0 && strings.TrimSpace(next.corsAllowOrigin) == "" {
+		next.corsAllowOrigin = "*"
+	}
+
+	// initialize all the new components before replacing
+	// any of the running ones
+	var mm metrics.Manager
+	swapMetrics := !reflect.DeepEqual(next.metrics, r.current.metrics)
+	if swapMetrics {
+		mm, err = metrics.NewManager(r.ctx, next.metrics)
+		if err != nil {
+			restore()
+			return fmt.Errorf("init metrics manager: %w", err)
+		}
+	}
+
+	var loggers *s3log.Loggers
+	swapLoggers := !reflect.DeepEqual(next.log, r.current.log)
+	if swapLoggers {
+		cfg := next.log
+		cfg.MetricsManager = r.mm
+		loggers, err = s3log.InitLogger(&cfg)
+		if err != nil {
+			if mm != nil {
+				mm.Close()
+			}
+			restore()
+			return fmt.Errorf("setup logger: %w", err)
+		}
+	}
+
+	var evSender s3event.S3EventSender
+	swapEvents := !reflect.DeepEqual(next.event, r.current.event)
+	if swapEvents {
+		evSender, err = s3event.InitEventSender(&next.event)
+		if err != nil {
+			if mm != nil {
+				mm.Close()
+			}
+			if loggers != nil {
+				shutdownLoggers(loggers)
+			}
+			restore()
+			return fmt.Errorf("init bucket event notifications: %w", err)
+		}
+	}
+
+	if swapMetrics {
+		r.mm.Swap(mm)
+	}
+	if swapLoggers {
+		err := r.s3Logger.Swap(loggers.S3Logger)
+		if err != nil {
+			debuglogger.InternalError(fmt.Errorf("shutdown replaced s3 logger: %w", err))
+		}
+		err = r.adminLogger.Swap(loggers.AdminLogger)
+		if err != nil {
+			debuglogger.InternalError(fmt.Errorf("shutdown replaced admin logger: %w", err))
+		}
+	}
+	if swapEvents {
+		err := r.evSender.Swap(evSender)
+		if err != nil {
+			debuglogger.InternalError(fmt.Errorf("close replaced event sender: %w", err))
+		}
+	}
+
+	r.srv.SetMaxRequests(next.maxRequests)
+	r.srv.Router.SetReadOnly(next.readonly)
+	r.srv.Router.SetCORSAllowOrigin(next.corsAllowOrigin)
+	if r.admSrv != nil {
+		r.admSrv.SetMaxRequests(next.adminMaxRequests)
+		r.admSrv.SetCORSAllowOrigin(next.corsAllowOrigin)
+	}
+	if c, ok := r.iam.(*auth.IAMCache); ok {
+		c.SetExpiration(time.Duration(next.iamCacheTTL) * time.Second)
+	}
+
+	r.current = next
+	return nil
+}
+
+// watch reloads the configuration file when its modification time
+// changes, until the context is canceled
+func (r *configReloader) watch(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-r.ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		fi, err := os.Stat(r.file.path)
+		if err != nil {
+			debuglogger.InternalError(fmt.Errorf("stat config file: %w", err))
+			continue
+		}
+
+		r.mu.Lock()
+		modified := !fi.ModTime().Equal(r.file.modTime)
+		r.mu.Unlock()
+		if modified {
+			r.reload()
+		}
+	}
+}
+
+// reload reloads the configuration file and prints the result
+func (r *configReloader) reload() {
+	result, err := r.Reload()
+	if err != nil {
+		debuglogger.InternalError(fmt.Errorf("config reload failed: %w", err))
+		return
+	}
+
+	if len(result.Applied) > 0 {
+		fmt.Printf("config reloaded (file: %s, applied: %s)\n",
+			r.file.path, strings.Join(result.Applied, ", "))
+	}
+	if len(result.RestartRequired) > 0 {
+		fmt.Fprintf(os.Stderr, "WARNING: config file settings changed that require a gateway restart: %s\n",
+			strings.Join(result.RestartRequired, ", "))
+	}
+}
+
+// changedSettings returns the sorted names of the settings
+// that differ between the old and new settings
+func changedSettings(old, new map[string][]string) []string {
+	var changed []string
+	for name, values := range new {
+		if !slices.Equal(old[name], values) {
+			changed = append(changed, name)
+		}
+	}
+	for name := range old {
+		if _, ok := new[name]; !ok {
+			changed = append(changed, name)
+		}
+	}
+	sort.Strings(changed)
+	return changed
+}
+
+func shutdownLoggers(loggers *s3log.Loggers) {
+	if loggers.S3Logger != nil {
+		loggers.S3Logger.Shutdown()
+	}
+	if loggers.AdminLogger != nil {
+		loggers.AdminLogger.Shutdown()
+	}
+}
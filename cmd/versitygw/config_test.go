@@ -0,0 +1,289 @@
+// Copyright 2026 Versity Software
+// This file is licensed under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/urfave/cli/v2"
+	"github.com/versity/versitygw/auth"
+	"github.com/versity/versitygw/backend"
+	"github.com/versity/versitygw/metrics"
+	"github.com/versity/versitygw/s3api"
+	"github.com/versity/versitygw/s3api/middlewares"
+	"github.com/versity/versitygw/s3event"
+	"github.com/versity/versitygw/s3log"
+)
+
+func writeConfigFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("write config file: %v", err)
+	}
+}
+
+// restoreFlagGlobals saves the globals set by the gateway flags and the
+// config reloads, and restores these once the test is done, so the later
+// tests of the package run with the default settings
+func restoreFlagGlobals(t *testing.T) {
+	t.Helper()
+
+	var saved []reflect.Value
+	var dests []reflect.Value
+	for _, f := range initFlags() {
+		dest := reflect.ValueOf(f).Elem().FieldByName("Destination")
+		if !dest.IsValid() || dest.IsNil() {
+			continue
+		}
+		v := reflect.New(dest.Elem().Type()).Elem()
+		v.Set(dest.Elem())
+		saved = append(saved, v)
+		dests = append(dests, dest.Elem())
+	}
+	savedPorts := ports
+
+	t.Cleanup(func() {
+		for i, dest := range dests {
+			dest.Set(saved[i])
+		}
+		ports = savedPorts
+	})
+}
+
+// runWithConfigFile parses the gateway flags of the args and
+// loads the configuration file
+func runWithConfigFile(t *testing.T, path string, args ...string) (*gatewayConfigFile, error) {
+	t.Helper()
+	restoreFlagGlobals(t)
+
+	var cf *gatewayConfigFile
+	var loadErr error
+	app := &cli.App{
+		Flags: initFlags(),
+		Action: func(ctx *cli.Context) error {
+			cf, loadErr = loadConfigFile(ctx, path)
+			if loadErr == nil {
+				ports = ctx.StringSlice("port")
+			}
+			return nil
+		},
+	}
+
+	err := app.Run(append([]string{"versitygw"}, args...))
+	if err != nil {
+		t.Fatalf("run app: %v", err)
+	}
+	return cf, loadErr
+}
+
+func TestLoadConfigFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "versitygw.yaml")
+	writeConfigFile(t, path, `
+port: [":7071", ":7072"]
+access: fileuser
+max-requests: 50
+readonly: true
+iam-cache-ttl: 30
+access-log-rotate-interval: 1h
+`)
+
+	cf, err := runWithConfigFile(t, path, "--access", "cliuser")
+	if err != nil {
+		t.Fatalf("load config file: %v", err)
+	}
+
+	if len(ports) != 2 || ports[0] != ":7071" || ports[1] != ":7072" {
+		t.Errorf("unexpected ports: %v", ports)
+	}
+	if rootUserAccess != "cliuser" {
+		t.Errorf("expected the command line access to take precedence, got %q", rootUserAccess)
+	}
+	if maxRequests != 50 {
+		t.Errorf("expected max-requests 50, got %d", maxRequests)
+	}
+	if !readonly {
+		t.Errorf("expected readonly to be set")
+	}
+	if iamCacheTTL != 30 {
+		t.Errorf("expected iam-cache-ttl 30, got %d", iamCacheTTL)
+	}
+	if accessLogRotateInterval.Hours() != 1 {
+		t.Errorf("expected access-log-rotate-interval 1h, got %v", accessLogRotateInterval)
+	}
+	if !cf.overridden["access"] {
+		t.Errorf("expected access to be overridden")
+	}
+	if _, ok := cf.applied["access"]; ok {
+		t.Errorf("expected the overridden setting not to be applied")
+	}
+	if cf.defaults["max-requests"] == "" {
+		t.Errorf("expected the max-requests default to be recorded")
+	}
+}
+
+func TestLoadConfigFile_Invalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{"unknown setting", "no-such-option: true\n"},
+		{"config setting", "config: other.yaml\n"},
+		{"list of single value setting", "region: [us-east-1, us-west-1]\n"},
+		{"mapping value", "region:\n  name: us-east-1\n"},
+		{"missing value", "region:\n"},
+		{"invalid value", "max-requests: many\n"},
+		{"malformed yaml", "region: [us-east-1\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "versitygw.yaml")
+			writeConfigFile(t, path, tt.content)
+
+			_, err := runWithConfigFile(t, path)
+			if err == nil {
+				t.Errorf("expected an error")
+			}
+		})
+	}
+}
+
+func TestChangedSettings(t *testing.T) {
+	changed := changedSettings(map[string][]string{
+		"port":         {":7070"},
+		"readonly":     {"true"},
+		"max-requests": {"10"},
+	}, map[string][]string{
+		"port":         {":7070", ":7071"},
+		"max-requests": {"10"},
+		"access-log":   {"access.log"},
+	})
+
+	expected := []string{"access-log", "port", "readonly"}
+	if len(changed) != len(expected) {
+		t.Fatalf("expected %v, got %v", expected, changed)
+	}
+	for i := range expected {
+		if changed[i] != expected[i] {
+			t.Fatalf("expected %v, got %v", expected, changed)
+		}
+	}
+}
+
+func TestConfigReloader_Reload(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "versitygw.yaml")
+	writeConfigFile(t, path, `
+port: [":7071"]
+max-requests: 50
+readonly: true
+cors-allow-origin: https://example.com
+`)
+
+	cf, err := runWithConfigFile(t, path)
+	if err != nil {
+		t.Fatalf("load config file: %v", err)
+	}
+
+	rc, err := newRuntimeConfig()
+	if err != nil {
+		t.Fatalf("runtime config: %v", err)
+	}
+
+	srv, err := s3api.New(backend.BackendUnsupported{}, middlewares.RootUserConfig{},
+		"us-east-1", &auth.IAMServiceInternal{}, nil, nil, nil, nil,
+		s3api.WithConcurrencyLimiter(100, maxRequests), s3api.WithQuiet())
+	if err != nil {
+		t.Fatalf("init gateway: %v", err)
+	}
+
+	r := &configReloader{
+		ctx:         context.Background(),
+		file:        cf,
+		current:     rc,
+		mm:          metrics.NewReloadableManager(nil),
+		s3Logger:    s3log.NewReloadableLogger(nil),
+		adminLogger: s3log.NewReloadableLogger(nil),
+		evSender:    s3event.NewReloadableEventSender(nil),
+		srv:         srv,
+	}
+	t.Cleanup(func() {
+		r.s3Logger.Shutdown()
+	})
+
+	accessLogPath := filepath.Join(dir, "access.log")
+	writeConfigFile(t, path, `
+port: [":7072"]
+max-requests: 60
+access-log: `+accessLogPath+`
+`)
+
+	result, err := r.Reload()
+	if err != nil {
+		t.Fatalf("reload: %v", err)
+	}
+
+	assertNames := func(name string, expected, got []string) {
+		t.Helper()
+		if len(expected) != len(got) {
+			t.Fatalf("expected %v %v, got %v", name, expected, got)
+		}
+		for i := range expected {
+			if expected[i] != got[i] {
+				t.Fatalf("expected %v %v, got %v", name, expected, got)
+			}
+		}
+	}
+
+	assertNames("applied", []string{"access-log", "cors-allow-origin", "max-requests", "readonly"}, result.Applied)
+	assertNames("restart required", []string{"port"}, result.RestartRequired)
+	if result.Config.ReadOnly || result.Config.CORSAllowOrigin != "" || result.Config.MaxRequests != 60 {
+		t.Errorf("unexpected reloaded config: %+v", result.Config)
+	}
+	if _, err := os.Stat(accessLogPath); err != nil {
+		t.Errorf("expected the access log to be created: %v", err)
+	}
+
+	// the pending restart is reported until the setting is reverted
+	result, err = r.Reload()
+	if err != nil {
+		t.Fatalf("reload: %v", err)
+	}
+	assertNames("applied", []string{}, result.Applied)
+	assertNames("restart required", []string{"port"}, result.RestartRequired)
+
+	// the invalid settings are not applied
+	writeConfigFile(t, path, `
+port: [":7071"]
+max-requests: 0
+`)
+	_, err = r.Reload()
+	if err == nil {
+		t.Fatalf("expected the invalid max-requests to fail the reload")
+	}
+	if maxRequests != 60 {
+		t.Errorf("expected max-requests to be restored to 60, got %d", maxRequests)
+	}
+	if accessLog != accessLogPath {
+		t.Errorf("expected access-log to be restored to %q, got %q", accessLogPath, accessLog)
+	}
+	if r.Config().MaxRequests != 60 {
+		t.Errorf("expected the config max-requests 60, got %d", r.Config().MaxRequests)
+	}
+}
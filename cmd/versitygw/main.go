@@ -25,6 +25,7 @@ import (
 	"os"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/urfave/cli/v2"
 	"github.com/versity/versitygw/auth"
@@ -36,6 +37,7 @@ import (
 	"github.com/versity/versitygw/s3api/utils"
 	"github.com/versity/versitygw/s3event"
 	"github.com/versity/versitygw/s3log"
+	"github.com/versity/versitygw/s3response"
 	"github.com/versity/versitygw/webui"
 )
 
@@ -58,7 +60,23 @@ var (
 	eventConfigFilePath                    string
 	logWebhookURL, accessLog               string
 	adminLogFile                           string
+	accessLogFormat, logSyslogURL          string
+	accessLogMaxSize, accessLogMaxBackups  int
+	accessLogRotateInterval                time.Duration
+	accessLogCompress                      bool
+	logWebhookBatchSize, logWebhookRetries int
+	logWebhookQueueSize                    int
+	logWebhookFlushInterval                time.Duration
+	logWebhookGzip                         bool
+	logWebhookBearerToken                  string
+	logWebhookHMACSecret                   string
+	logWebhookSpoolDir                     string
+	logWebhookSpoolMaxSize                 int
 	healthPath                             string
+	readinessPath                          string
+	readinessCacheTTL                      time.Duration
+	readinessTimeout                       time.Duration
+	shutdownDrain                          time.Duration
 	virtualDomain                          string
 	debug                                  bool
 	keepAlive                              bool
@@ -103,6 +121,14 @@ var (
 	webuiPathPrefix                        string
 	webuiS3Prefix                          string
 	disableACLs                            bool
+	sigV2                                  bool
+	clientCA, admClientCA                  string
+	clientCertRequired, clientCertIAM      bool
+	clientCertMap                          string
+	publicAccessBlock                      string
+	shareLinksDir                          string
+	configFile                             string
+	configWatchInterval                    time.Duration
 )
 
 var (
@@ -152,6 +178,16 @@ source code is hosted on GitHub at https://github.com/versity/versitygw, and
 documentation can be found in the GitHub wiki.`,
 		Copyright: "Copyright (c) 2023-2024 Versity Software",
 		Before: func(ctx *cli.Context) error {
+			// Apply the configuration file settings to the options
+			// not set on the command line or in the environment
+			if configFile != "" {
+				cf, err := loadConfigFile(ctx, configFile)
+				if err != nil {
+					return err
+				}
+				gwConfigFile = cf
+			}
+
 			// Initialize global variables from context (including default values)
 			ports = ctx.StringSlice("port")
 			webuiPorts = ctx.StringSlice("webui")
@@ -160,8 +196,8 @@ documentation can be found in the GitHub wiki.`,
 			webuiAdminGateways = ctx.StringSlice("webui-admin-gateways")
 			webuiPathPrefix = ctx.String("webui-path-prefix")
 
-			// Resolve relative UNIX socket paths to absolute before any backend
-			// (e.g. posix) can change the working directory via os.Chdir.
+			// Resolve relative UNIX socket paths to absolute, so the socket
+			// paths don't depend on the working directory of later lookups.
 			var err error
 			if ports, err = utils.AbsSocketPaths(ports); err != nil {
 				return err
@@ -195,6 +231,19 @@ func initFlags() []cli.Flag {
 				return nil
 			},
 		},
+		&cli.StringFlag{
+			Name:        "config",
+			Usage:       "YAML configuration file of the gateway options, the keys are the long option names (e.g. 'access-log: /var/log/vgw.log'); the command line and environment options take precedence over the file",
+			EnvVars:     []string{"VGW_CONFIG"},
+			Destination: &configFile,
+		},
+		&cli.DurationFlag{
+			Name:        "config-watch-interval",
+			Usage:       "how often the configuration file is checked for changes, 0 disables the watch (the file is also reloaded on SIGHUP and with the admin api)",
+			EnvVars:     []string{"VGW_CONFIG_WATCH_INTERVAL"},
+			Value:       10 * time.Second,
+			Destination: &configWatchInterval,
+		},
 		&cli.StringSliceFlag{
 			Name:    "port",
 			Usage:   "gateway listen address: <ip>:<port>, :<port>, /path/to/socket for file-backed UNIX sockets, or @name for Linux abstract namespace sockets (can be specified multiple times for listening on multiple addresses)",
@@ -337,6 +386,36 @@ func initFlags() []cli.Flag {
 			EnvVars:     []string{"VGW_ADMIN_CERT_KEY"},
 			Destination: &admKeyFile,
 		},
+		&cli.StringFlag{
+			Name:        "client-ca",
+			Usage:       "CA bundle file to verify the TLS client certificates (mutual TLS)",
+			EnvVars:     []string{"VGW_CLIENT_CA"},
+			Destination: &clientCA,
+		},
+		&cli.StringFlag{
+			Name:        "admin-client-ca",
+			Usage:       "CA bundle file to verify the TLS client certificates of the admin server (mutual TLS)",
+			EnvVars:     []string{"VGW_ADMIN_CLIENT_CA"},
+			Destination: &admClientCA,
+		},
+		&cli.BoolFlag{
+			Name:        "client-cert-required",
+			Usage:       "reject the TLS connections without a client certificate when a client CA is set",
+			EnvVars:     []string{"VGW_CLIENT_CERT_REQUIRED"},
+			Destination: &clientCertRequired,
+		},
+		&cli.StringFlag{
+			Name:        "client-cert-map",
+			Usage:       "file mapping the verified client certificates to the accounts for unsigned requests",
+			EnvVars:     []string{"VGW_CLIENT_CERT_MAP"},
+			Destination: &clientCertMap,
+		},
+		&cli.BoolFlag{
+			Name:        "client-cert-iam",
+			Usage:       "map the verified client certificates to the IAM accounts with the access of the certificate common name",
+			EnvVars:     []string{"VGW_CLIENT_CERT_IAM"},
+			Destination: &clientCertIAM,
+		},
 		&cli.BoolFlag{
 			Name:        "debug",
 			Usage:       "enable debug output",
@@ -377,6 +456,24 @@ func initFlags() []cli.Flag {
 			Destination: &disableACLs,
 			Aliases:     []string{"noacl"},
 		},
+		&cli.BoolFlag{
+			Name:        "sigv2",
+			Usage:       "enables the legacy AWS Signature Version 2 authentication",
+			EnvVars:     []string{"VGW_SIGV2"},
+			Destination: &sigV2,
+		},
+		&cli.StringFlag{
+			Name:        "public-access-block",
+			Usage:       "gateway wide block public access settings applied to all buckets: comma separated list of BlockPublicAcls, IgnorePublicAcls, BlockPublicPolicy, RestrictPublicBuckets, or 'all'",
+			EnvVars:     []string{"VGW_PUBLIC_ACCESS_BLOCK"},
+			Destination: &publicAccessBlock,
+		},
+		&cli.StringFlag{
+			Name:        "share-links-dir",
+			Usage:       "if defined, enables the admin issued share links stored within this directory",
+			EnvVars:     []string{"VGW_SHARE_LINKS_DIR"},
+			Destination: &shareLinksDir,
+		},
 		&cli.StringFlag{
 			Name:        "access-log",
 			Usage:       "enable server access logging to specified file",
@@ -395,6 +492,101 @@ func initFlags() []cli.Flag {
 			EnvVars:     []string{"WEBHOOK", "VGW_LOG_WEBHOOK_URL"},
 			Destination: &logWebhookURL,
 		},
+		&cli.IntFlag{
+			Name:        "log-webhook-batch-size",
+			Usage:       "max number of audit log entries sent per webhook request, the entries are sent as a JSON array when greater than 1",
+			EnvVars:     []string{"VGW_LOG_WEBHOOK_BATCH_SIZE"},
+			Value:       1,
+			Destination: &logWebhookBatchSize,
+		},
+		&cli.DurationFlag{
+			Name:        "log-webhook-flush-interval",
+			Usage:       "max time audit log entries are held before sending a partial webhook batch",
+			EnvVars:     []string{"VGW_LOG_WEBHOOK_FLUSH_INTERVAL"},
+			Value:       time.Second,
+			Destination: &logWebhookFlushInterval,
+		},
+		&cli.IntFlag{
+			Name:        "log-webhook-queue-size",
+			Usage:       "number of audit log entries buffered in memory for the webhook before new entries are dropped",
+			EnvVars:     []string{"VGW_LOG_WEBHOOK_QUEUE_SIZE"},
+			Value:       10000,
+			Destination: &logWebhookQueueSize,
+		},
+		&cli.IntFlag{
+			Name:        "log-webhook-retries",
+			Usage:       "number of retries with exponential backoff for failed webhook requests, 0 disables the retries",
+			EnvVars:     []string{"VGW_LOG_WEBHOOK_RETRIES"},
+			Value:       3,
+			Destination: &logWebhookRetries,
+		},
+		&cli.BoolFlag{
+			Name:        "log-webhook-gzip",
+			Usage:       "gzip compress the webhook audit log requests",
+			EnvVars:     []string{"VGW_LOG_WEBHOOK_GZIP"},
+			Destination: &logWebhookGzip,
+		},
+		&cli.StringFlag{
+			Name:        "log-webhook-bearer-token",
+			Usage:       "bearer token sent in the Authorization header of the webhook audit log requests",
+			EnvVars:     []string{"VGW_LOG_WEBHOOK_BEARER_TOKEN"},
+			Destination: &logWebhookBearerToken,
+		},
+		&cli.StringFlag{
+			Name:        "log-webhook-hmac-secret",
+			Usage:       "secret used to sign the webhook audit log requests with HMAC-SHA256 (X-Versitygw-Signature header)",
+			EnvVars:     []string{"VGW_LOG_WEBHOOK_HMAC_SECRET"},
+			Destination: &logWebhookHMACSecret,
+		},
+		&cli.StringFlag{
+			Name:        "log-webhook-spool-dir",
+			Usage:       "directory to spool the audit log batches to while the webhook is unreachable",
+			EnvVars:     []string{"VGW_LOG_WEBHOOK_SPOOL_DIR"},
+			Destination: &logWebhookSpoolDir,
+		},
+		&cli.IntFlag{
+			Name:        "log-webhook-spool-max-size",
+			Usage:       "max total size of the webhook audit log spool in megabytes (0 is unlimited)",
+			EnvVars:     []string{"VGW_LOG_WEBHOOK_SPOOL_MAX_SIZE"},
+			Value:       1024,
+			Destination: &logWebhookSpoolMaxSize,
+		},
+		&cli.StringFlag{
+			Name:        "log-syslog-url",
+			Usage:       "syslog server url to send the audit logs as RFC5424 messages, e.g. 'udp://host:514', 'tcp://host:601' or 'unix:///dev/log' with optional '?facility=local0&tag=versitygw' parameters",
+			EnvVars:     []string{"VGW_LOG_SYSLOG_URL"},
+			Destination: &logSyslogURL,
+		},
+		&cli.StringFlag{
+			Name:        "access-log-format",
+			Usage:       "server access log record format: 'text' (AWS server access log format) or 'json' (one JSON object per line), defaults to 'text' for files and 'json' for syslog",
+			EnvVars:     []string{"VGW_ACCESS_LOG_FORMAT"},
+			Destination: &accessLogFormat,
+		},
+		&cli.IntFlag{
+			Name:        "access-log-max-size",
+			Usage:       "rotate the access log files once they reach this size in megabytes (0 disables size based rotation)",
+			EnvVars:     []string{"VGW_ACCESS_LOG_MAX_SIZE"},
+			Destination: &accessLogMaxSize,
+		},
+		&cli.DurationFlag{
+			Name:        "access-log-rotate-interval",
+			Usage:       "rotate the access log files at this interval, e.g. '24h' (0 disables time based rotation)",
+			EnvVars:     []string{"VGW_ACCESS_LOG_ROTATE_INTERVAL"},
+			Destination: &accessLogRotateInterval,
+		},
+		&cli.IntFlag{
+			Name:        "access-log-max-backups",
+			Usage:       "number of rotated access log files to keep (0 keeps all)",
+			EnvVars:     []string{"VGW_ACCESS_LOG_MAX_BACKUPS"},
+			Destination: &accessLogMaxBackups,
+		},
+		&cli.BoolFlag{
+			Name:        "access-log-compress",
+			Usage:       "gzip compress the rotated access log files",
+			EnvVars:     []string{"VGW_ACCESS_LOG_COMPRESS"},
+			Destination: &accessLogCompress,
+		},
 		&cli.StringFlag{
 			Name:        "event-kafka-url",
 			Usage:       "kafka server url to send the bucket notifications.",
@@ -687,11 +879,38 @@ func initFlags() []cli.Flag {
 		},
 		&cli.StringFlag{
 			Name: "health",
-			Usage: `health check endpoint path. Health endpoint will be configured on GET http method: GET <health>
+			Usage: `health check (liveness) endpoint path. Health endpoint will be configured on GET http method: GET <health>
 					NOTICE: the path has to be specified with '/'. e.g /health`,
 			EnvVars:     []string{"VGW_HEALTH"},
 			Destination: &healthPath,
 		},
+		&cli.StringFlag{
+			Name: "readiness",
+			Usage: `readiness endpoint path. The endpoint checks the backend, IAM service and event sink,
+					and responds on GET with the JSON check results, 503 if not ready. e.g /ready`,
+			EnvVars:     []string{"VGW_READINESS"},
+			Destination: &readinessPath,
+		},
+		&cli.DurationFlag{
+			Name:        "readiness-cache-ttl",
+			Usage:       "how long the readiness check results are reused",
+			EnvVars:     []string{"VGW_READINESS_CACHE_TTL"},
+			Value:       5 * time.Second,
+			Destination: &readinessCacheTTL,
+		},
+		&cli.DurationFlag{
+			Name:        "readiness-timeout",
+			Usage:       "how long the readiness checks are waited for before reported failed",
+			EnvVars:     []string{"VGW_READINESS_TIMEOUT"},
+			Value:       2 * time.Second,
+			Destination: &readinessTimeout,
+		},
+		&cli.DurationFlag{
+			Name:        "shutdown-drain",
+			Usage:       "how long the readiness endpoint reports not ready on shutdown before the gateway stops serving requests",
+			EnvVars:     []string{"VGW_SHUTDOWN_DRAIN"},
+			Destination: &shutdownDrain,
+		},
 		&cli.BoolFlag{
 			Name:        "readonly",
 			Usage:       "allow only read operations across all the gateway",
@@ -847,6 +1066,31 @@ func runGateway(ctx context.Context, be backend.Backend) error {
 		opts = append(opts, s3api.WithCORSAllowOrigin(corsAllowOrigin))
 	}
 
+	if clientCA != "" && certFile == "" {
+		return fmt.Errorf("client CA specified without TLS cert file")
+	}
+	if admClientCA != "" && admCertFile == "" {
+		return fmt.Errorf("admin client CA specified without admin TLS cert file")
+	}
+
+	var certMapper auth.CertAccountMapper
+	if clientCertMap != "" && clientCertIAM {
+		return fmt.Errorf("client cert map and client cert IAM mapping are mutually exclusive")
+	}
+	if clientCertMap != "" || clientCertIAM {
+		if clientCA == "" && admClientCA == "" {
+			return fmt.Errorf("client certificate mapping specified without client CA")
+		}
+		certMapper = auth.CertCommonNameMapper{}
+		if clientCertMap != "" {
+			var err error
+			certMapper, err = auth.NewCertMappingFile(clientCertMap)
+			if err != nil {
+				return fmt.Errorf("tls: %w", err)
+			}
+		}
+	}
+
 	if certFile != "" || keyFile != "" {
 		if certFile == "" {
 			return fmt.Errorf("TLS key specified without cert file")
@@ -860,6 +1104,15 @@ func runGateway(ctx context.Context, be backend.Backend) error {
 		if err != nil {
 			return fmt.Errorf("tls: load certs: %v", err)
 		}
+		if clientCA != "" {
+			err = cs.SetClientCAs(clientCA, clientCertRequired)
+			if err != nil {
+				return fmt.Errorf("tls: %v", err)
+			}
+			if certMapper != nil {
+				opts = append(opts, s3api.WithClientCertAuth(certMapper))
+			}
+		}
 		opts = append(opts, s3api.WithTLS(cs))
 	}
 	if len(admPorts) == 0 {
@@ -871,6 +1124,14 @@ func runGateway(ctx context.Context, be backend.Backend) error {
 	if healthPath != "" {
 		opts = append(opts, s3api.WithHealth(healthPath))
 	}
+	if readinessPath != "" {
+		opts = append(opts, s3api.WithReadiness(s3api.ReadinessConfig{
+			Path:     readinessPath,
+			CacheTTL: readinessCacheTTL,
+			Timeout:  readinessTimeout,
+			Drain:    shutdownDrain,
+		}))
+	}
 	if readonly {
 		opts = append(opts, s3api.WithReadOnly())
 	}
@@ -883,6 +1144,24 @@ func runGateway(ctx context.Context, be backend.Backend) error {
 	if disableACLs {
 		opts = append(opts, s3api.WithDisableACL())
 	}
+	if sigV2 {
+		opts = append(opts, s3api.WithSigV2())
+	}
+	if publicAccessBlock != "" {
+		pab, err := auth.ParsePublicAccessBlockSettings(publicAccessBlock)
+		if err != nil {
+			return err
+		}
+		auth.SetDefaultPublicAccessBlock(pab)
+	}
+	var shareStore auth.ShareStore
+	if shareLinksDir != "" {
+		shareStore, err = auth.NewShareStoreInternal(shareLinksDir)
+		if err != nil {
+			return fmt.Errorf("init share links: %w", err)
+		}
+		opts = append(opts, s3api.WithShareLinks(shareStore))
+	}
 	if debug {
 		debuglogger.SetDebugEnabled()
 	}
@@ -941,40 +1220,58 @@ func runGateway(ctx context.Context, be backend.Backend) error {
 		return fmt.Errorf("setup iam: %w", err)
 	}
 
-	loggers, err := s3log.InitLogger(&s3log.LogConfig{
-		LogFile:      accessLog,
-		WebhookURL:   logWebhookURL,
-		AdminLogFile: adminLogFile,
-	})
+	rc, err := newRuntimeConfig()
 	if err != nil {
-		return fmt.Errorf("setup logger: %w", err)
+		return err
 	}
 
-	metricsManager, err := metrics.NewManager(ctx, metrics.Config{
-		ServiceName:      metricsService,
-		StatsdServers:    statsdServers,
-		DogStatsdServers: dogstatsServers,
-	})
+	metricsManager, err := metrics.NewManager(ctx, rc.metrics)
 	if err != nil {
 		return fmt.Errorf("init metrics manager: %w", err)
 	}
 
-	evSender, err := s3event.InitEventSender(&s3event.EventConfig{
-		KafkaURL:             kafkaURL,
-		KafkaTopic:           kafkaTopic,
-		KafkaTopicKey:        kafkaKey,
-		NatsURL:              natsURL,
-		NatsTopic:            natsTopic,
-		RabbitmqURL:          rabbitmqURL,
-		RabbitmqExchange:     rabbitmqExchange,
-		RabbitmqRoutingKey:   rabbitmqRoutingKey,
-		WebhookURL:           eventWebhookURL,
-		FilterConfigFilePath: eventConfigFilePath,
-	})
+	// the reloadable components are replaced with the new ones
+	// when the configuration file changes
+	var reloader *configReloader
+	if gwConfigFile != nil {
+		reloader = &configReloader{
+			ctx:     ctx,
+			file:    gwConfigFile,
+			current: rc,
+			mm:      metrics.NewReloadableManager(metricsManager),
+			iam:     iam,
+		}
+		metricsManager = reloader.mm
+	}
+
+	logConfig := rc.log
+	logConfig.MetricsManager = metricsManager
+	loggers, err := s3log.InitLogger(&logConfig)
+	if err != nil {
+		return fmt.Errorf("setup logger: %w", err)
+	}
+
+	evSender, err := s3event.InitEventSender(&rc.event)
 	if err != nil {
 		return fmt.Errorf("init bucket event notifications: %w", err)
 	}
 
+	if reloader != nil {
+		reloader.s3Logger = s3log.NewReloadableLogger(loggers.S3Logger)
+		reloader.adminLogger = s3log.NewReloadableLogger(loggers.AdminLogger)
+		reloader.evSender = s3event.NewReloadableEventSender(evSender)
+		loggers.S3Logger = reloader.s3Logger
+		loggers.AdminLogger = reloader.adminLogger
+		evSender = reloader.evSender
+	}
+
+	if sn, ok := be.(backend.ScrubNotifier); ok {
+		sn.SetScrubReporter(scrubReporter(metricsManager, evSender))
+	}
+	if cn, ok := be.(backend.ChangeNotifier); ok {
+		cn.SetChangeReporter(changeReporter(metricsManager, evSender))
+	}
+
 	if webuiS3Prefix != "" {
 		s3SSLEnabled := certFile != ""
 		s3AdmSSLEnabled := s3SSLEnabled
@@ -1034,6 +1331,29 @@ func runGateway(ctx context.Context, be backend.Backend) error {
 		return fmt.Errorf("init gateway: %v", err)
 	}
 
+	gwConfig := s3response.AdminGatewayConfig{
+		Backend:           be.String(),
+		Region:            region,
+		Ports:             ports,
+		AdminPorts:        admPorts,
+		TLS:               certFile != "",
+		ReadOnly:          readonly,
+		VirtualDomain:     virtualDomain,
+		HealthPath:        healthPath,
+		ReadinessPath:     readinessPath,
+		CORSAllowOrigin:   corsAllowOrigin,
+		DisableACLs:       disableACLs,
+		SigV2:             sigV2,
+		PublicAccessBlock: publicAccessBlock,
+		ShareLinks:        shareStore != nil,
+		MaxConnections:    maxConnections,
+		MaxRequests:       maxRequests,
+	}
+	if reloader != nil {
+		reloader.srv = srv
+		reloader.base = gwConfig
+	}
+
 	var admSrv *s3api.S3AdminServer
 
 	if len(admPorts) > 0 {
@@ -1071,6 +1391,15 @@ func runGateway(ctx context.Context, be backend.Backend) error {
 			if err != nil {
 				return fmt.Errorf("tls: load certs: %v", err)
 			}
+			if admClientCA != "" {
+				err = cs.SetClientCAs(admClientCA, clientCertRequired)
+				if err != nil {
+					return fmt.Errorf("tls: %v", err)
+				}
+				if certMapper != nil {
+					opts = append(opts, s3api.WithAdminClientCertAuth(certMapper))
+				}
+			}
 			opts = append(opts, s3api.WithAdminSrvTLS(cs))
 		}
 		if quiet {
@@ -1079,8 +1408,18 @@ func runGateway(ctx context.Context, be backend.Backend) error {
 		if debug {
 			opts = append(opts, s3api.WithAdminDebug())
 		}
+		if shareStore != nil {
+			opts = append(opts, s3api.WithAdminShareLinks(shareStore))
+		}
+		opts = append(opts, s3api.WithAdminGatewayConfig(gwConfig))
+		if reloader != nil {
+			opts = append(opts, s3api.WithAdminConfigReloader(reloader))
+		}
 
 		admSrv = s3api.NewAdminServer(be, middlewares.RootUserConfig{Access: rootUserAccess, Secret: rootUserSecret}, region, iam, loggers.AdminLogger, srv.Router.Ctrl, opts...)
+		if reloader != nil {
+			reloader.admSrv = admSrv
+		}
 	}
 
 	var webSrv *webui.Server
@@ -1217,6 +1556,10 @@ func runGateway(ctx context.Context, be backend.Backend) error {
 		go func() { c <- webSrv.ServeMultiPort(webuiPorts) }()
 	}
 
+	if reloader != nil && configWatchInterval > 0 {
+		go reloader.watch(configWatchInterval)
+	}
+
 	// for/select blocks until shutdown
 Loop:
 	for {
@@ -1226,6 +1569,9 @@ Loop:
 		case err = <-c:
 			break Loop
 		case <-sigHup:
+			if reloader != nil {
+				reloader.reload()
+			}
 			if loggers.S3Logger != nil {
 				err = loggers.S3Logger.HangUp()
 				if err != nil {
@@ -1248,6 +1594,14 @@ Loop:
 					fmt.Printf("srv cert reloaded (cert: %s, key: %s)\n", certFile, keyFile)
 				}
 			}
+			if clientCA != "" {
+				err = srv.CertStorage.SetClientCAs(clientCA, clientCertRequired)
+				if err != nil {
+					debuglogger.InternalError(fmt.Errorf("srv client CA reload failed: %w", err))
+				} else {
+					fmt.Printf("srv client CA reloaded (ca: %s)\n", clientCA)
+				}
+			}
 			if len(admPorts) > 0 && admCertFile != "" && admKeyFile != "" {
 				err = admSrv.CertStorage.SetCertificate(admCertFile, admKeyFile)
 				if err != nil {
@@ -1256,6 +1610,22 @@ Loop:
 					fmt.Printf("admSrv cert reloaded (cert: %s, key: %s)\n", admCertFile, admKeyFile)
 				}
 			}
+			if len(admPorts) > 0 && admClientCA != "" {
+				err = admSrv.CertStorage.SetClientCAs(admClientCA, clientCertRequired)
+				if err != nil {
+					debuglogger.InternalError(fmt.Errorf("admSrv client CA reload failed: %w", err))
+				} else {
+					fmt.Printf("admSrv client CA reloaded (ca: %s)\n", admClientCA)
+				}
+			}
+			if mf, ok := certMapper.(*auth.CertMappingFile); ok {
+				err = mf.Reload()
+				if err != nil {
+					debuglogger.InternalError(fmt.Errorf("client cert map reload failed: %w", err))
+				} else {
+					fmt.Printf("client cert map reloaded (file: %s)\n", clientCertMap)
+				}
+			}
 			if len(webuiPorts) > 0 && webTLSCert != "" && webTLSKey != "" {
 				err := webSrv.CertStorage.SetCertificate(webTLSCert, webTLSKey)
 				if err != nil {
@@ -1912,3 +2282,59 @@ func leftText(text string) string {
 	}
 	return text + strings.Repeat(" ", columnWidth-2-len(text))
 }
+
+// scrubReporter returns the function sending the metrics of the backend
+// data scrubs, and the events of the objects failing verification
+func scrubReporter(mm metrics.Manager, evs s3event.S3EventSender) func(s3response.ScrubReport) {
+	return func(report s3response.ScrubReport) {
+		if mm != nil {
+			tag := metrics.Tag{Key: "bucket", Value: report.Bucket}
+			mm.Add("scrub_objects", report.Objects, tag)
+			mm.Add("scrub_bytes", report.Bytes, tag)
+			mm.Add("scrub_skipped", report.Skipped, tag)
+			mm.Add("scrub_mismatches", int64(len(report.Mismatches)), tag)
+		}
+
+		if evs == nil {
+			return
+		}
+		for _, m := range report.Mismatches {
+			var versionId *string
+			if m.VersionId != "" {
+				versionId = &m.VersionId
+			}
+			evs.SendSystemEvent(report.Bucket, m.Key, s3event.EventMeta{
+				EventName:  s3event.EventObjectIntegrityFailed,
+				ObjectSize: m.Size,
+				VersionId:  versionId,
+			})
+		}
+	}
+}
+
+// changeReporter returns the function sending the metrics and the events
+// of the objects changed outside of the gateway
+func changeReporter(mm metrics.Manager, evs s3event.S3EventSender) func(s3response.ObjectChange) {
+	return func(change s3response.ObjectChange) {
+		if mm != nil {
+			tag := metrics.Tag{Key: "bucket", Value: change.Bucket}
+			mm.Add("external_changes", 1, tag)
+			mm.Add("external_bytes", change.SizeDelta, tag)
+		}
+
+		if evs == nil {
+			return
+		}
+		meta := s3event.EventMeta{
+			EventName:  s3event.EventObjectCreatedPut,
+			ObjectSize: change.Size,
+		}
+		if change.Removed {
+			meta.EventName = s3event.EventObjectRemovedDelete
+		} else {
+			etag := change.ETag
+			meta.ObjectETag = &etag
+		}
+		evs.SendSystemEvent(change.Bucket, change.Key, meta)
+	}
+}
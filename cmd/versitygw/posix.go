@@ -18,6 +18,8 @@ import (
 	"fmt"
 	"io/fs"
 	"math"
+	"path/filepath"
+	"time"
 
 	"github.com/urfave/cli/v2"
 	"github.com/versity/versitygw/backend/meta"
@@ -30,10 +32,18 @@ var (
 	versioningDir        string
 	dirPerms             uint
 	sidecar              string
+	kvmeta               string
 	nometa               bool
 	forceNoTmpFile       bool
 	forceNoCopyFileRange bool
 	actionsConcurrency   int
+	scrubInterval        time.Duration
+	scrubRate            int64
+	scrubQuarantine      bool
+	scrubReportBucket    string
+	listIndex            string
+	listIndexInterval    time.Duration
+	watchInterval        time.Duration
 )
 
 func posixCommand() *cli.Command {
@@ -90,6 +100,12 @@ will be translated into the file /mnt/fs/gwroot/mybucket/a/b/c/myobject`,
 				EnvVars:     []string{"VGW_META_SIDECAR"},
 				Destination: &sidecar,
 			},
+			&cli.StringFlag{
+				Name:        "kvmeta",
+				Usage:       "use provided key-value database file to store metadata",
+				EnvVars:     []string{"VGW_META_KV"},
+				Destination: &kvmeta,
+			},
 			&cli.IntFlag{
 				Name:        "concurrency",
 				Usage:       "maximum concurrent actions allowed",
@@ -115,6 +131,48 @@ will be translated into the file /mnt/fs/gwroot/mybucket/a/b/c/myobject`,
 				EnvVars:     []string{"VGW_DISABLE_COPY_FILE_RANGE"},
 				Destination: &forceNoCopyFileRange,
 			},
+			&cli.DurationFlag{
+				Name:        "scrub-interval",
+				Usage:       "enable the background verification of the object data against the stored checksums at the interval",
+				EnvVars:     []string{"VGW_SCRUB_INTERVAL"},
+				Destination: &scrubInterval,
+			},
+			&cli.Int64Flag{
+				Name:        "scrub-rate",
+				Usage:       "limit the object data read by the scrubber in bytes per second (0 is unlimited)",
+				EnvVars:     []string{"VGW_SCRUB_RATE"},
+				Destination: &scrubRate,
+			},
+			&cli.BoolFlag{
+				Name:        "scrub-quarantine",
+				Usage:       "move the objects failing the scrub verification out of the bucket namespace",
+				EnvVars:     []string{"VGW_SCRUB_QUARANTINE"},
+				Destination: &scrubQuarantine,
+			},
+			&cli.StringFlag{
+				Name:        "scrub-report-bucket",
+				Usage:       "store the scrub reports as objects in the bucket",
+				EnvVars:     []string{"VGW_SCRUB_REPORT_BUCKET"},
+				Destination: &scrubReportBucket,
+			},
+			&cli.StringFlag{
+				Name:        "list-index",
+				Usage:       "use provided key-value database file to index the bucket objects for the listings",
+				EnvVars:     []string{"VGW_LIST_INDEX"},
+				Destination: &listIndex,
+			},
+			&cli.DurationFlag{
+				Name:        "list-index-reconcile-interval",
+				Usage:       "reconcile the listing indexes with the filesystem at the interval to pick up changes made outside of the gateway",
+				EnvVars:     []string{"VGW_LIST_INDEX_RECONCILE_INTERVAL"},
+				Destination: &listIndexInterval,
+			},
+			&cli.DurationFlag{
+				Name:        "watch-interval",
+				Usage:       "detect the objects changed outside of the gateway, scanning the buckets at the interval, requires --list-index",
+				EnvVars:     []string{"VGW_WATCH_INTERVAL"},
+				Destination: &watchInterval,
+			},
 		},
 	}
 }
@@ -130,8 +188,14 @@ func runPosix(ctx *cli.Context) error {
 		return fmt.Errorf("invalid directory permissions: %d", dirPerms)
 	}
 
-	if nometa && sidecar != "" {
-		return fmt.Errorf("cannot use both nometa and sidecar metadata")
+	metaOpts := 0
+	for _, set := range []bool{nometa, sidecar != "", kvmeta != ""} {
+		if set {
+			metaOpts++
+		}
+	}
+	if metaOpts > 1 {
+		return fmt.Errorf("only one of nometa, sidecar or kvmeta metadata can be used")
 	}
 
 	if actionsConcurrency <= 0 {
@@ -148,6 +212,15 @@ func runPosix(ctx *cli.Context) error {
 		ForceNoCopyFileRange: forceNoCopyFileRange,
 		ValidateBucketNames:  disableStrictBucketNames,
 		Concurrency:          actionsConcurrency,
+		ScrubInterval:        scrubInterval,
+		ScrubRate:            scrubRate,
+		ScrubQuarantine:      scrubQuarantine,
+		ScrubReportBucket:    scrubReportBucket,
+
+		ListIndexPath:              listIndex,
+		ListIndexReconcileInterval: listIndexInterval,
+
+		WatchInterval: watchInterval,
 	}
 
 	var ms meta.MetadataStorer
@@ -159,6 +232,17 @@ func runPosix(ctx *cli.Context) error {
 		}
 		ms = sc
 		opts.SideCarDir = sidecar
+	case kvmeta != "":
+		kvpath, err := filepath.Abs(kvmeta)
+		if err != nil {
+			return fmt.Errorf("failed to resolve kvmeta path: %w", err)
+		}
+		kv, err := meta.NewKV(kvpath)
+		if err != nil {
+			return fmt.Errorf("failed to init kv metadata: %w", err)
+		}
+		ms = kv
+		opts.MetaDBPath = kvpath
 	case nometa:
 		ms = meta.NoMeta{}
 	default:
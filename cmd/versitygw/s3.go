@@ -32,6 +32,9 @@ var (
 	s3proxyDisableDataIntegrityCheck bool
 	s3proxySslSkipVerify             bool
 	s3proxyUsePathStyle              bool
+	s3proxyObjectLockFallback        bool
+	s3proxyCredentialPassthrough     bool
+	s3proxyCredentialMap             string
 	s3proxyDebug                     bool
 )
 
@@ -114,6 +117,26 @@ to an s3 storage backend service.`,
 				Value:       false,
 				Destination: &s3proxyUsePathStyle,
 			},
+			&cli.BoolFlag{
+				Name:        "object-lock-fallback",
+				Usage:       "store and enforce object lock in the gateway with the meta bucket, for s3 services without object lock support",
+				EnvVars:     []string{"VGW_S3_OBJECT_LOCK_FALLBACK"},
+				Value:       false,
+				Destination: &s3proxyObjectLockFallback,
+			},
+			&cli.BoolFlag{
+				Name:        "credential-passthrough",
+				Usage:       "sign the s3 service requests with the credentials of the requesting account",
+				EnvVars:     []string{"VGW_S3_CREDENTIAL_PASSTHROUGH"},
+				Value:       false,
+				Destination: &s3proxyCredentialPassthrough,
+			},
+			&cli.StringFlag{
+				Name:        "credential-map",
+				Usage:       "JSON file mapping gateway access keys to s3 service credentials for credential passthrough",
+				EnvVars:     []string{"VGW_S3_CREDENTIAL_MAP"},
+				Destination: &s3proxyCredentialMap,
+			},
 			&cli.BoolFlag{
 				Name:        "debug",
 				Usage:       "output extra debug tracing",
@@ -126,8 +149,22 @@ to an s3 storage backend service.`,
 }
 
 func runS3(ctx *cli.Context) error {
-	be, err := s3proxy.New(ctx.Context, s3proxyAccess, s3proxySecret, s3proxyEndpoint, s3proxyRegion,
-		s3proxyMetaBucket, s3proxyAnonymousCredentials, s3proxyDisableChecksum, s3proxyDisableDataIntegrityCheck, s3proxySslSkipVerify, s3proxyUsePathStyle, s3proxyDebug)
+	be, err := s3proxy.New(ctx.Context, s3proxy.Options{
+		Access:                    s3proxyAccess,
+		Secret:                    s3proxySecret,
+		Endpoint:                  s3proxyEndpoint,
+		Region:                    s3proxyRegion,
+		MetaBucket:                s3proxyMetaBucket,
+		CredentialMap:             s3proxyCredentialMap,
+		AnonymousCredentials:      s3proxyAnonymousCredentials,
+		DisableChecksum:           s3proxyDisableChecksum,
+		DisableDataIntegrityCheck: s3proxyDisableDataIntegrityCheck,
+		SslSkipVerify:             s3proxySslSkipVerify,
+		UsePathStyle:              s3proxyUsePathStyle,
+		ObjectLockFallback:        s3proxyObjectLockFallback,
+		CredentialPassthrough:     s3proxyCredentialPassthrough,
+		Debug:                     s3proxyDebug,
+	})
 	if err != nil {
 		return fmt.Errorf("init s3 backend: %w", err)
 	}
@@ -18,6 +18,8 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
+	"io/fs"
 	"os"
 	"path/filepath"
 	"strings"
@@ -51,6 +53,72 @@ func utilsCommand() *cli.Command {
 				Usage:   "Convert legacy X-Amz-Meta.* xattrs into user.metadata JSON and remove legacy keys.",
 				Action:  convertXattrMetadata,
 			},
+			{
+				Name:    "migrate-metadata",
+				Aliases: []string{"mm"},
+				Usage:   "Copy the posix backend metadata between the xattr, sidecar and kv metadata stores. The gateway must be stopped.",
+				Action:  migrateMetadata,
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:     "from",
+						Usage:    "source metadata store: xattr, sidecar or kv",
+						Required: true,
+					},
+					&cli.StringFlag{
+						Name:     "to",
+						Usage:    "destination metadata store: xattr, sidecar or kv",
+						Required: true,
+					},
+					&cli.StringFlag{
+						Name:  "src-sidecar",
+						Usage: "source sidecar metadata directory",
+					},
+					&cli.StringFlag{
+						Name:  "src-kvmeta",
+						Usage: "source key-value metadata database file",
+					},
+					&cli.StringFlag{
+						Name:  "dst-sidecar",
+						Usage: "destination sidecar metadata directory",
+					},
+					&cli.StringFlag{
+						Name:  "dst-kvmeta",
+						Usage: "destination key-value metadata database file",
+					},
+					&cli.StringFlag{
+						Name:  "versioning-dir",
+						Usage: "the gateway versioning directory, to also migrate the object versions metadata",
+					},
+					&cli.BoolFlag{
+						Name:  "delete-source",
+						Usage: "remove the source metadata after it has been copied",
+					},
+				},
+			},
+			{
+				Name:    "check-metadata",
+				Aliases: []string{"chm"},
+				Usage:   "Find orphaned sidecar or kv metadata of objects that no longer exist. The gateway must be stopped when using --fix.",
+				Action:  checkMetadata,
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:  "sidecar",
+						Usage: "sidecar metadata directory",
+					},
+					&cli.StringFlag{
+						Name:  "kvmeta",
+						Usage: "key-value metadata database file",
+					},
+					&cli.StringFlag{
+						Name:  "versioning-dir",
+						Usage: "the gateway versioning directory",
+					},
+					&cli.BoolFlag{
+						Name:  "fix",
+						Usage: "remove the orphaned metadata",
+					},
+				},
+			},
 		},
 	}
 }
@@ -224,3 +292,322 @@ func convertXattrMetadata(ctx *cli.Context) error {
 
 	return nil
 }
+
+const (
+	metaStoreXattr   = "xattr"
+	metaStoreSidecar = "sidecar"
+	metaStoreKV      = "kv"
+)
+
+// rootedXattr is the xattr metadata store accessing the object files
+// beneath the gateway root, closing the root with the metadata store.
+type rootedXattr struct {
+	meta.MetadataStorer
+	root *os.Root
+}
+
+func (r rootedXattr) Close() error {
+	return r.root.Close()
+}
+
+// openMetaStore opens the metadata store of the given kind, resolving
+// the relative metadata paths from the gateway root. The metadata store
+// must be closed by the caller with closeMetaStore.
+func openMetaStore(kind, absRoot, sidecarDir, kvPath string) (meta.MetadataStorer, error) {
+	switch kind {
+	case metaStoreXattr:
+		root, err := os.OpenRoot(absRoot)
+		if err != nil {
+			return nil, fmt.Errorf("open directory: %w", err)
+		}
+		return rootedXattr{
+			MetadataStorer: meta.WithRoot(meta.XattrMeta{}, root),
+			root:           root,
+		}, nil
+	case metaStoreSidecar:
+		if sidecarDir == "" {
+			return nil, fmt.Errorf("sidecar metadata directory is required")
+		}
+		dir, err := filepath.Abs(sidecarDir)
+		if err != nil {
+			return nil, fmt.Errorf("resolve sidecar directory: %w", err)
+		}
+		return meta.NewSideCar(dir)
+	case metaStoreKV:
+		if kvPath == "" {
+			return nil, fmt.Errorf("kv metadata database file is required")
+		}
+		path, err := filepath.Abs(kvPath)
+		if err != nil {
+			return nil, fmt.Errorf("resolve kv metadata path: %w", err)
+		}
+		return meta.NewKV(path)
+	default:
+		return nil, fmt.Errorf("invalid metadata store %q, must be one of %s, %s or %s",
+			kind, metaStoreXattr, metaStoreSidecar, metaStoreKV)
+	}
+}
+
+func closeMetaStore(ms meta.MetadataStorer) {
+	if c, ok := ms.(io.Closer); ok {
+		c.Close()
+	}
+}
+
+// metadataPaths calls fn for the paths of the gateway root and versioning
+// directory entries, with the same paths the posix backend uses for the
+// metadata: relative to the gateway root for the buckets and objects, and
+// absolute for the object versions.
+func metadataPaths(absRoot, versioningDir string, fn func(path string)) error {
+	walk := func(dir string, relative bool) error {
+		return filepath.WalkDir(dir, func(path string, d os.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if path == dir || d.Type()&os.ModeSymlink != 0 {
+				return nil
+			}
+			if relative {
+				path, err = filepath.Rel(dir, path)
+				if err != nil {
+					return err
+				}
+			}
+			fn(path)
+			return nil
+		})
+	}
+
+	err := walk(absRoot, true)
+	if err != nil {
+		return fmt.Errorf("walk directory: %w", err)
+	}
+
+	if versioningDir != "" {
+		err = walk(versioningDir, false)
+		if err != nil {
+			return fmt.Errorf("walk versioning directory: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func resolveDirArgs(ctx *cli.Context) (string, string, error) {
+	root := strings.TrimSpace(ctx.Args().First())
+	if root == "" {
+		return "", "", cli.Exit("missing directory: should be provided as command argument", 2)
+	}
+
+	absRoot, err := filepath.Abs(root)
+	if err != nil {
+		return "", "", fmt.Errorf("resolve directory: %w", err)
+	}
+
+	info, err := os.Stat(absRoot)
+	if err != nil {
+		return "", "", fmt.Errorf("stat directory: %w", err)
+	}
+	if !info.IsDir() {
+		return "", "", cli.Exit(fmt.Sprintf("not a directory: %s", absRoot), 2)
+	}
+
+	var absVersioningDir string
+	if dir := ctx.String("versioning-dir"); dir != "" {
+		absVersioningDir, err = filepath.Abs(dir)
+		if err != nil {
+			return "", "", fmt.Errorf("resolve versioning directory: %w", err)
+		}
+	}
+
+	return absRoot, absVersioningDir, nil
+}
+
+func migrateMetadata(ctx *cli.Context) error {
+	absRoot, versioningDir, err := resolveDirArgs(ctx)
+	if err != nil {
+		return err
+	}
+
+	from, to := ctx.String("from"), ctx.String("to")
+	if from == to && from == metaStoreXattr {
+		return cli.Exit("source and destination metadata stores are the same", 2)
+	}
+
+	src, err := openMetaStore(from, absRoot, ctx.String("src-sidecar"), ctx.String("src-kvmeta"))
+	if err != nil {
+		return fmt.Errorf("open source metadata: %w", err)
+	}
+	defer closeMetaStore(src)
+
+	if from == to && from == metaStoreKV {
+		// the kv database can only be opened once
+		srcPath, _ := filepath.Abs(ctx.String("src-kvmeta"))
+		dstPath, _ := filepath.Abs(ctx.String("dst-kvmeta"))
+		if srcPath == dstPath {
+			return cli.Exit("source and destination metadata stores are the same", 2)
+		}
+	}
+
+	dst, err := openMetaStore(to, absRoot, ctx.String("dst-sidecar"), ctx.String("dst-kvmeta"))
+	if err != nil {
+		return fmt.Errorf("open destination metadata: %w", err)
+	}
+	defer closeMetaStore(dst)
+
+	if from == metaStoreXattr || to == metaStoreXattr {
+		err = meta.XattrMeta{}.Test(absRoot)
+		if err != nil {
+			return err
+		}
+	}
+
+	deleteSource := ctx.Bool("delete-source")
+
+	var (
+		scanned  int
+		migrated int
+		errCount int
+	)
+
+	err = metadataPaths(absRoot, versioningDir, func(path string) {
+		scanned++
+
+		names, err := src.ListAttributes(path, "")
+		if err != nil {
+			errCount++
+			return
+		}
+		if len(names) == 0 {
+			return
+		}
+
+		attrs := make(map[string][]byte, len(names))
+		for _, name := range names {
+			b, err := src.RetrieveAttribute(nil, path, "", name)
+			if err != nil {
+				// don't migrate the partial metadata
+				errCount++
+				return
+			}
+			attrs[name] = b
+		}
+
+		err = meta.StoreAttributes(dst, nil, path, "", attrs)
+		if err != nil {
+			errCount++
+			return
+		}
+
+		// cleanup the source metadata only after successful
+		// write of the destination metadata
+		if deleteSource {
+			for _, name := range names {
+				err := src.DeleteAttribute(path, "", name)
+				if err != nil && !errors.Is(err, meta.ErrNoSuchKey) {
+					errCount++
+				}
+			}
+		}
+
+		migrated++
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf(
+		"metadata migration is finished:\n  directory: %s\n  from: %s\n  to: %s\n  scanned: %d\n  migrated: %d\n  errors: %d\n",
+		absRoot, from, to, scanned, migrated, errCount,
+	)
+
+	return nil
+}
+
+func checkMetadata(ctx *cli.Context) error {
+	absRoot, versioningDir, err := resolveDirArgs(ctx)
+	if err != nil {
+		return err
+	}
+
+	sidecarDir, kvPath := ctx.String("sidecar"), ctx.String("kvmeta")
+	var kind string
+	switch {
+	case sidecarDir != "" && kvPath != "":
+		return cli.Exit("only one of sidecar or kvmeta can be checked", 2)
+	case sidecarDir != "":
+		kind = metaStoreSidecar
+	case kvPath != "":
+		kind = metaStoreKV
+	default:
+		return cli.Exit("sidecar or kvmeta metadata store is required", 2)
+	}
+
+	ms, err := openMetaStore(kind, absRoot, sidecarDir, kvPath)
+	if err != nil {
+		return fmt.Errorf("open metadata: %w", err)
+	}
+	defer closeMetaStore(ms)
+
+	walker, ok := ms.(meta.PathWalker)
+	if !ok {
+		return fmt.Errorf("%s metadata can't be checked", kind)
+	}
+
+	fix := ctx.Bool("fix")
+
+	var (
+		checked  int
+		orphaned int
+		errCount int
+	)
+
+	err = walker.WalkPaths(func(path string) error {
+		checked++
+
+		_, err := os.Lstat(metadataObjectPath(absRoot, versioningDir, path))
+		if err == nil {
+			return nil
+		}
+		if !errors.Is(err, fs.ErrNotExist) {
+			errCount++
+			return nil
+		}
+
+		orphaned++
+		fmt.Println("orphaned metadata:", path)
+		if fix {
+			err := ms.DeleteAttributes(path, "")
+			if err != nil {
+				errCount++
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf(
+		"metadata check is finished:\n  directory: %s\n  checked: %d\n  orphaned: %d\n  fixed: %v\n  errors: %d\n",
+		absRoot, checked, orphaned, fix && orphaned > 0, errCount,
+	)
+
+	return nil
+}
+
+// metadataObjectPath returns the filesystem path of the metadata path.
+// The sidecar metadata of the absolute version paths is stored under
+// the relative path within the sidecar directory.
+func metadataObjectPath(absRoot, versioningDir, path string) string {
+	if filepath.IsAbs(path) {
+		return path
+	}
+	if versioningDir != "" {
+		abs := string(filepath.Separator) + path
+		if abs == versioningDir || strings.HasPrefix(abs, versioningDir+string(filepath.Separator)) {
+			return abs
+		}
+	}
+	return filepath.Join(absRoot, path)
+}
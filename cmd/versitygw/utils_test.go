@@ -0,0 +1,72 @@
+// Copyright 2026 Versity Software
+// This file is licensed under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestMetadataPaths(t *testing.T) {
+	root := t.TempDir()
+	versioningDir := t.TempDir()
+
+	for _, dir := range []string{
+		filepath.Join(root, "bucket", "dir"),
+		filepath.Join(versioningDir, "bucket", "obj"),
+	} {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			t.Fatalf("create directory: %v", err)
+		}
+	}
+	err := os.WriteFile(filepath.Join(root, "bucket", "dir", "obj"), nil, 0o644)
+	if err != nil {
+		t.Fatalf("create object: %v", err)
+	}
+	err = os.Symlink(root, filepath.Join(root, "bucket", "link"))
+	if err != nil {
+		t.Fatalf("create symlink: %v", err)
+	}
+
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("get working directory: %v", err)
+	}
+
+	var paths []string
+	err = metadataPaths(root, versioningDir, func(path string) {
+		paths = append(paths, path)
+	})
+	if err != nil {
+		t.Fatalf("metadata paths: %v", err)
+	}
+
+	want := []string{
+		"bucket",
+		filepath.Join("bucket", "dir"),
+		filepath.Join("bucket", "dir", "obj"),
+		filepath.Join(versioningDir, "bucket"),
+		filepath.Join(versioningDir, "bucket", "obj"),
+	}
+	if !slices.Equal(paths, want) {
+		t.Errorf("expected paths %v, got %v", want, paths)
+	}
+
+	if cwd, _ := os.Getwd(); cwd != wd {
+		t.Errorf("working directory changed from %v to %v", wd, cwd)
+	}
+}
@@ -126,6 +126,22 @@ var (
 	ActionAdminListUsers         = "admin_ListUsers"
 	ActionAdminListBuckets       = "admin_ListBuckets"
 	ActionAdminCreateBucket      = "admin_CreateBucket"
+	ActionAdminSnapshotBucket    = "admin_SnapshotBucket"
+	ActionAdminSnapshotStatus    = "admin_SnapshotStatus"
+	ActionAdminScrubBucket       = "admin_ScrubBucket"
+	ActionAdminTransitionObject  = "admin_TransitionObject"
+	ActionAdminCreateShareLink   = "admin_CreateShareLink"
+	ActionAdminListShareLinks    = "admin_ListShareLinks"
+	ActionAdminRevokeShareLink   = "admin_RevokeShareLink"
+	ActionAdminGetUser           = "admin_GetUser"
+	ActionAdminGetBucket         = "admin_GetBucket"
+	ActionAdminGetConfig         = "admin_GetConfig"
+	ActionAdminQuotas            = "admin_Quotas"
+	ActionAdminReloadConfig      = "admin_ReloadConfig"
+
+	// Share link actions
+	ActionShareGetObject = "share_GetObject"
+	ActionSharePutObject = "share_PutObject"
 )
 
 func init() {
@@ -504,4 +520,12 @@ func init() {
 		Name:    "GetBucketLocation",
 		Service: "s3",
 	}
+	ActionMap[ActionShareGetObject] = Action{
+		Name:    "GetObject",
+		Service: "share",
+	}
+	ActionMap[ActionSharePutObject] = Action{
+		Name:    "PutObject",
+		Service: "share",
+	}
 }
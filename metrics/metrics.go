@@ -44,6 +44,8 @@ type Tag struct {
 // Manager is the interface definition for metrics manager
 type Manager interface {
 	Send(ctx *fiber.Ctx, err error, action string, count int64, status int)
+	// Add adds value to the key for metrics not tied to a request
+	Add(key string, value int64, tags ...Tag)
 	Close()
 }
 
@@ -173,6 +175,11 @@ func (m *manager) Send(ctx *fiber.Ctx, err error, action string, count int64, st
 	}
 }
 
+// Add adds value to key
+func (m *manager) Add(key string, value int64, tags ...Tag) {
+	m.add(key, value, tags...)
+}
+
 // increment increments the key by one
 func (m *manager) increment(key string, tags ...Tag) {
 	m.add(key, 1, tags...)
@@ -0,0 +1,73 @@
+// Copyright 2026 Versity Software
+// This file is licensed under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package metrics
+
+import (
+	"sync"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// ReloadableManager is a metrics manager whose underlying manager
+// can be replaced while the gateway is serving requests. A nil
+// underlying manager drops the metrics.
+type ReloadableManager struct {
+	mu  sync.RWMutex
+	mgr Manager
+}
+
+var _ Manager = &ReloadableManager{}
+
+// NewReloadableManager wraps the manager, the manager may be nil
+func NewReloadableManager(mgr Manager) *ReloadableManager {
+	return &ReloadableManager{mgr: mgr}
+}
+
+// Swap replaces the underlying manager and closes the previous one
+func (m *ReloadableManager) Swap(mgr Manager) {
+	m.mu.Lock()
+	old := m.mgr
+	m.mgr = mgr
+	m.mu.Unlock()
+
+	if old != nil {
+		old.Close()
+	}
+}
+
+// Send sends the request metrics to the current manager
+func (m *ReloadableManager) Send(ctx *fiber.Ctx, err error, action string, count int64, status int) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if m.mgr != nil {
+		m.mgr.Send(ctx, err, action, count, status)
+	}
+}
+
+// Add adds the value to the key of the current manager
+func (m *ReloadableManager) Add(key string, value int64, tags ...Tag) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if m.mgr != nil {
+		m.mgr.Add(key, value, tags...)
+	}
+}
+
+// Close closes the current manager
+func (m *ReloadableManager) Close() {
+	m.Swap(nil)
+}
@@ -22,14 +22,19 @@ import (
 	"github.com/versity/versitygw/s3api/controllers"
 	"github.com/versity/versitygw/s3api/middlewares"
 	"github.com/versity/versitygw/s3log"
+	"github.com/versity/versitygw/s3response"
 )
 
 type S3AdminRouter struct {
-	s3api controllers.S3ApiController
+	s3api          controllers.S3ApiController
+	shareStore     auth.ShareStore
+	gatewayConfig  s3response.AdminGatewayConfig
+	configReloader controllers.ConfigReloader
 }
 
-func (ar *S3AdminRouter) Init(app *fiber.App, be backend.Backend, iam auth.IAMService, logger s3log.AuditLogger, root middlewares.RootUserConfig, region string, debug bool, corsAllowOrigin string) {
+func (ar *S3AdminRouter) Init(app *fiber.App, be backend.Backend, iam auth.IAMService, logger s3log.AuditLogger, root middlewares.RootUserConfig, region string, debug bool, cors *corsHandlers) {
 	ctrl := controllers.NewAdminController(iam, be, logger, ar.s3api)
+	shareCtrl := controllers.NewShareController(be, ar.shareStore, nil)
 	services := &controllers.Services{
 		Logger: logger,
 	}
@@ -39,11 +44,11 @@ func (ar *S3AdminRouter) Init(app *fiber.App, be backend.Backend, iam auth.IAMSe
 		controllers.ProcessHandlers(ctrl.CreateUser, metrics.ActionAdminCreateUser, services,
 			middlewares.VerifyV4Signature(root, iam, region, false, true, false),
 			middlewares.IsAdmin(metrics.ActionAdminCreateUser),
-			middlewares.ApplyDefaultCORS(corsAllowOrigin),
+			cors.applyDefault(),
 		))
 	app.Options("/create-user",
-		middlewares.ApplyDefaultCORSPreflight(corsAllowOrigin),
-		middlewares.ApplyDefaultCORS(corsAllowOrigin),
+		cors.applyDefaultPreflight(),
+		cors.applyDefault(),
 	)
 
 	// DeleteUsers admin api
@@ -51,11 +56,11 @@ func (ar *S3AdminRouter) Init(app *fiber.App, be backend.Backend, iam auth.IAMSe
 		controllers.ProcessHandlers(ctrl.DeleteUser, metrics.ActionAdminDeleteUser, services,
 			middlewares.VerifyV4Signature(root, iam, region, false, true, false),
 			middlewares.IsAdmin(metrics.ActionAdminDeleteUser),
-			middlewares.ApplyDefaultCORS(corsAllowOrigin),
+			cors.applyDefault(),
 		))
 	app.Options("/delete-user",
-		middlewares.ApplyDefaultCORSPreflight(corsAllowOrigin),
-		middlewares.ApplyDefaultCORS(corsAllowOrigin),
+		cors.applyDefaultPreflight(),
+		cors.applyDefault(),
 	)
 
 	// UpdateUser admin api
@@ -63,11 +68,11 @@ func (ar *S3AdminRouter) Init(app *fiber.App, be backend.Backend, iam auth.IAMSe
 		controllers.ProcessHandlers(ctrl.UpdateUser, metrics.ActionAdminUpdateUser, services,
 			middlewares.VerifyV4Signature(root, iam, region, false, true, false),
 			middlewares.IsAdmin(metrics.ActionAdminUpdateUser),
-			middlewares.ApplyDefaultCORS(corsAllowOrigin),
+			cors.applyDefault(),
 		))
 	app.Options("/update-user",
-		middlewares.ApplyDefaultCORSPreflight(corsAllowOrigin),
-		middlewares.ApplyDefaultCORS(corsAllowOrigin),
+		cors.applyDefaultPreflight(),
+		cors.applyDefault(),
 	)
 
 	// ListUsers admin api
@@ -75,11 +80,11 @@ func (ar *S3AdminRouter) Init(app *fiber.App, be backend.Backend, iam auth.IAMSe
 		controllers.ProcessHandlers(ctrl.ListUsers, metrics.ActionAdminListUsers, services,
 			middlewares.VerifyV4Signature(root, iam, region, false, true, false),
 			middlewares.IsAdmin(metrics.ActionAdminListUsers),
-			middlewares.ApplyDefaultCORS(corsAllowOrigin),
+			cors.applyDefault(),
 		))
 	app.Options("/list-users",
-		middlewares.ApplyDefaultCORSPreflight(corsAllowOrigin),
-		middlewares.ApplyDefaultCORS(corsAllowOrigin),
+		cors.applyDefaultPreflight(),
+		cors.applyDefault(),
 	)
 
 	// ChangeBucketOwner admin api
@@ -87,11 +92,59 @@ func (ar *S3AdminRouter) Init(app *fiber.App, be backend.Backend, iam auth.IAMSe
 		controllers.ProcessHandlers(ctrl.ChangeBucketOwner, metrics.ActionAdminChangeBucketOwner, services,
 			middlewares.VerifyV4Signature(root, iam, region, false, true, false),
 			middlewares.IsAdmin(metrics.ActionAdminChangeBucketOwner),
-			middlewares.ApplyDefaultCORS(corsAllowOrigin),
+			cors.applyDefault(),
 		))
 	app.Options("/change-bucket-owner",
-		middlewares.ApplyDefaultCORSPreflight(corsAllowOrigin),
-		middlewares.ApplyDefaultCORS(corsAllowOrigin),
+		cors.applyDefaultPreflight(),
+		cors.applyDefault(),
+	)
+
+	// SnapshotBucket admin api
+	app.Patch("/snapshot-bucket",
+		controllers.ProcessHandlers(ctrl.SnapshotBucket, metrics.ActionAdminSnapshotBucket, services,
+			middlewares.VerifyV4Signature(root, iam, region, false, true, false),
+			middlewares.IsAdmin(metrics.ActionAdminSnapshotBucket),
+			cors.applyDefault(),
+		))
+	app.Options("/snapshot-bucket",
+		cors.applyDefaultPreflight(),
+		cors.applyDefault(),
+	)
+
+	// SnapshotBucketStatus admin api
+	app.Patch("/snapshot-bucket-status",
+		controllers.ProcessHandlers(ctrl.SnapshotBucketStatus, metrics.ActionAdminSnapshotStatus, services,
+			middlewares.VerifyV4Signature(root, iam, region, false, true, false),
+			middlewares.IsAdmin(metrics.ActionAdminSnapshotStatus),
+			cors.applyDefault(),
+		))
+	app.Options("/snapshot-bucket-status",
+		cors.applyDefaultPreflight(),
+		cors.applyDefault(),
+	)
+
+	// ScrubBucket admin api
+	app.Patch("/scrub-bucket",
+		controllers.ProcessHandlers(ctrl.ScrubBucket, metrics.ActionAdminScrubBucket, services,
+			middlewares.VerifyV4Signature(root, iam, region, false, true, false),
+			middlewares.IsAdmin(metrics.ActionAdminScrubBucket),
+			cors.applyDefault(),
+		))
+	app.Options("/scrub-bucket",
+		cors.applyDefaultPreflight(),
+		cors.applyDefault(),
+	)
+
+	// TransitionObject admin api
+	app.Patch("/transition-object",
+		controllers.ProcessHandlers(ctrl.TransitionObject, metrics.ActionAdminTransitionObject, services,
+			middlewares.VerifyV4Signature(root, iam, region, false, true, false),
+			middlewares.IsAdmin(metrics.ActionAdminTransitionObject),
+			cors.applyDefault(),
+		))
+	app.Options("/transition-object",
+		cors.applyDefaultPreflight(),
+		cors.applyDefault(),
 	)
 
 	// ListBucketsAndOwners admin api
@@ -99,12 +152,88 @@ func (ar *S3AdminRouter) Init(app *fiber.App, be backend.Backend, iam auth.IAMSe
 		controllers.ProcessHandlers(ctrl.ListBuckets, metrics.ActionAdminListBuckets, services,
 			middlewares.VerifyV4Signature(root, iam, region, false, true, false),
 			middlewares.IsAdmin(metrics.ActionAdminListBuckets),
-			middlewares.ApplyDefaultCORS(corsAllowOrigin),
+			cors.applyDefault(),
 		))
 	app.Options("/list-buckets",
-		middlewares.ApplyDefaultCORSPreflight(corsAllowOrigin),
-		middlewares.ApplyDefaultCORS(corsAllowOrigin),
+		cors.applyDefaultPreflight(),
+		cors.applyDefault(),
+	)
+
+	// CreateShareLink admin api
+	app.Patch("/create-share-link",
+		controllers.ProcessHandlers(shareCtrl.CreateShareLink, metrics.ActionAdminCreateShareLink, services,
+			middlewares.VerifyV4Signature(root, iam, region, false, true, false),
+			middlewares.IsAdmin(metrics.ActionAdminCreateShareLink),
+			cors.applyDefault(),
+		))
+	app.Options("/create-share-link",
+		cors.applyDefaultPreflight(),
+		cors.applyDefault(),
+	)
+
+	// ListShareLinks admin api
+	app.Patch("/list-share-links",
+		controllers.ProcessHandlers(shareCtrl.ListShareLinks, metrics.ActionAdminListShareLinks, services,
+			middlewares.VerifyV4Signature(root, iam, region, false, true, false),
+			middlewares.IsAdmin(metrics.ActionAdminListShareLinks),
+			cors.applyDefault(),
+		))
+	app.Options("/list-share-links",
+		cors.applyDefaultPreflight(),
+		cors.applyDefault(),
+	)
+
+	// RevokeShareLink admin api
+	app.Patch("/revoke-share-link",
+		controllers.ProcessHandlers(shareCtrl.RevokeShareLink, metrics.ActionAdminRevokeShareLink, services,
+			middlewares.VerifyV4Signature(root, iam, region, false, true, false),
+			middlewares.IsAdmin(metrics.ActionAdminRevokeShareLink),
+			cors.applyDefault(),
+		))
+	app.Options("/revoke-share-link",
+		cors.applyDefaultPreflight(),
+		cors.applyDefault(),
+	)
+
+	// The versioned admin json api. It is only served on the admin
+	// listener, as the '/admin' path is a valid bucket on the S3 listener.
+	v1Ctrl := controllers.NewAdminV1Controller(ctrl, ar.gatewayConfig, ar.configReloader)
+	v1Handler := func(controller controllers.Controller, action string) fiber.Handler {
+		return controllers.ProcessJSONHandlers(controller, action, services,
+			middlewares.VerifyV4Signature(root, iam, region, false, true, false),
+			middlewares.IsAdmin(action),
+			cors.applyDefault(),
+		)
+	}
+	v1 := app.Group(controllers.AdminV1Prefix)
+
+	v1.Get("/openapi.json", func(ctx *fiber.Ctx) error {
+		ctx.Response().Header.SetContentType(fiber.MIMEApplicationJSON)
+		return ctx.Send(controllers.AdminOpenAPISpec)
+	})
+
+	v1.Get("/users", v1Handler(v1Ctrl.ListUsers, metrics.ActionAdminListUsers))
+	v1.Post("/users", v1Handler(v1Ctrl.CreateUser, metrics.ActionAdminCreateUser))
+	v1.Get("/users/:access", v1Handler(v1Ctrl.GetUser, metrics.ActionAdminGetUser))
+	v1.Patch("/users/:access", v1Handler(v1Ctrl.UpdateUser, metrics.ActionAdminUpdateUser))
+	v1.Delete("/users/:access", v1Handler(v1Ctrl.DeleteUser, metrics.ActionAdminDeleteUser))
+
+	v1.Get("/buckets", v1Handler(v1Ctrl.ListBuckets, metrics.ActionAdminListBuckets))
+	v1.Get("/buckets/:bucket", v1Handler(v1Ctrl.GetBucket, metrics.ActionAdminGetBucket))
+	v1.Put("/buckets/:bucket", v1Handler(v1Ctrl.CreateBucket, metrics.ActionAdminCreateBucket))
+	v1.Put("/buckets/:bucket/owner", v1Handler(v1Ctrl.ChangeBucketOwner, metrics.ActionAdminChangeBucketOwner))
+
+	v1.All("/quotas", v1Handler(v1Ctrl.Quotas, metrics.ActionAdminQuotas))
+	v1.All("/quotas/:access", v1Handler(v1Ctrl.Quotas, metrics.ActionAdminQuotas))
+
+	v1.Get("/config", v1Handler(v1Ctrl.GetConfig, metrics.ActionAdminGetConfig))
+	v1.Post("/config/reload", v1Handler(v1Ctrl.ReloadConfig, metrics.ActionAdminReloadConfig))
+
+	v1.Options("/*",
+		cors.applyDefaultPreflight(),
+		cors.applyDefault(),
 	)
+	v1.All("/*", v1Handler(v1Ctrl.NoSuchResource, metrics.ActionUndetected))
 
 	app.Patch("/:bucket/create",
 		controllers.ProcessHandlers(ctrl.CreateBucket, metrics.ActionAdminListBuckets, services,
@@ -112,7 +241,7 @@ func (ar *S3AdminRouter) Init(app *fiber.App, be backend.Backend, iam auth.IAMSe
 			middlewares.IsAdmin(metrics.ActionAdminCreateBucket),
 		))
 	app.Options("/:bucket/create",
-		middlewares.ApplyDefaultCORSPreflight(corsAllowOrigin),
-		middlewares.ApplyDefaultCORS(corsAllowOrigin),
+		cors.applyDefaultPreflight(),
+		cors.applyDefault(),
 	)
 }
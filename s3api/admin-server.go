@@ -28,6 +28,7 @@ import (
 	"github.com/versity/versitygw/s3api/middlewares"
 	"github.com/versity/versitygw/s3api/utils"
 	"github.com/versity/versitygw/s3log"
+	"github.com/versity/versitygw/s3response"
 )
 
 type S3AdminServer struct {
@@ -38,8 +39,11 @@ type S3AdminServer struct {
 	quiet           bool
 	debug           bool
 	corsAllowOrigin string
+	cors            *corsHandlers
 	maxConnections  int
 	maxRequests     int
+	limiter         *middlewares.Limiter
+	certMapper      auth.CertAccountMapper
 }
 
 func NewAdminServer(be backend.Backend, root middlewares.RootUserConfig, region string, iam auth.IAMService, l s3log.AuditLogger, ctrl controllers.S3ApiController, opts ...AdminOpt) *S3AdminServer {
@@ -79,16 +83,22 @@ func NewAdminServer(be backend.Backend, root middlewares.RootUserConfig, region
 	}
 
 	// initialize total requests cap limiter middleware
-	app.Use(middlewares.RateLimiter(server.maxRequests, nil, l))
+	server.limiter = middlewares.NewLimiter(server.maxRequests)
+	app.Use(server.limiter.Handler(nil, l))
 
 	app.Use(controllers.WrapMiddleware(middlewares.DecodeURL, l, nil))
 
+	if server.certMapper != nil {
+		app.Use(controllers.WrapMiddleware(middlewares.VerifyClientCertificate(root, iam, server.certMapper), l, nil))
+	}
+
 	// initialize the debug logger in debug mode
 	if debuglogger.IsDebugEnabled() {
 		app.Use(middlewares.DebugLogger())
 	}
 
-	server.router.Init(app, be, iam, l, root, region, server.debug, server.corsAllowOrigin)
+	server.cors = newCORSHandlers(be, server.corsAllowOrigin)
+	server.router.Init(app, be, iam, l, root, region, server.debug, server.cors)
 
 	return server
 }
@@ -99,6 +109,30 @@ func WithAdminSrvTLS(cs *utils.CertStorage) AdminOpt {
 	return func(s *S3AdminServer) { s.CertStorage = cs }
 }
 
+// WithAdminClientCertAuth authenticates the unsigned admin requests with
+// a verified mutual TLS client certificate as the account the certificate
+// maps to. The client CAs are set in the CertStorage of WithAdminSrvTLS.
+func WithAdminClientCertAuth(mapper auth.CertAccountMapper) AdminOpt {
+	return func(s *S3AdminServer) { s.certMapper = mapper }
+}
+
+// WithAdminShareLinks enables the share link admin apis for the store
+func WithAdminShareLinks(store auth.ShareStore) AdminOpt {
+	return func(s *S3AdminServer) { s.router.shareStore = store }
+}
+
+// WithAdminGatewayConfig sets the gateway configuration reported
+// by the admin json api
+func WithAdminGatewayConfig(cfg s3response.AdminGatewayConfig) AdminOpt {
+	return func(s *S3AdminServer) { s.router.gatewayConfig = cfg }
+}
+
+// WithAdminConfigReloader enables the configuration reload admin
+// api, the reloader also reports the current gateway configuration
+func WithAdminConfigReloader(r controllers.ConfigReloader) AdminOpt {
+	return func(s *S3AdminServer) { s.router.configReloader = r }
+}
+
 // WithQuiet silences default logging output
 func WithAdminQuiet() AdminOpt {
 	return func(s *S3AdminServer) { s.quiet = true }
@@ -124,6 +158,18 @@ func WithAdminConcurrencyLimiter(maxConnections, maxRequests int) AdminOpt {
 	}
 }
 
+// SetMaxRequests changes the hard limit for in-flight requests
+// of the running admin server
+func (sa *S3AdminServer) SetMaxRequests(maxRequests int) {
+	sa.limiter.SetLimit(maxRequests)
+}
+
+// SetCORSAllowOrigin changes the default allowed CORS origin
+// of the running admin server
+func (sa *S3AdminServer) SetCORSAllowOrigin(origin string) {
+	sa.cors.setOrigin(origin)
+}
+
 // ServeMultiPort creates listeners for multiple port specifications and serves
 // on all of them simultaneously. This supports listening on multiple ports and/or
 // addresses (e.g., [":8080", "localhost:8081"]).
@@ -140,7 +186,7 @@ func (sa *S3AdminServer) ServeMultiPort(ports []string) error {
 		var err error
 
 		if sa.CertStorage != nil {
-			ln, err = utils.NewMultiAddrTLSListener(sa.app.Config().Network, portSpec, sa.CertStorage.GetCertificate)
+			ln, err = utils.NewMultiAddrTLSConfigListener(sa.app.Config().Network, portSpec, sa.CertStorage.TLSConfig())
 		} else {
 			ln, err = utils.NewMultiAddrListener(sa.app.Config().Network, portSpec)
 		}
@@ -0,0 +1,534 @@
+// Copyright 2026 Versity Software
+// This file is licensed under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package controllers
+
+import (
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sort"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/versity/versitygw/auth"
+	"github.com/versity/versitygw/debuglogger"
+	"github.com/versity/versitygw/s3api/utils"
+	"github.com/versity/versitygw/s3err"
+	"github.com/versity/versitygw/s3log"
+	"github.com/versity/versitygw/s3response"
+)
+
+// AdminV1Prefix is the path prefix of the admin json api
+const AdminV1Prefix = "/admin/v1"
+
+const (
+	adminV1DefaultLimit = 1000
+	adminV1MaxLimit     = 1000
+)
+
+// AdminOpenAPISpec is the OpenAPI document of the admin json api
+//
+//go:embed admin-v1-openapi.json
+var AdminOpenAPISpec []byte
+
+// AdminV1Controller serves the versioned admin json api. The
+// handlers reuse the AdminController IAM and backend calls, only
+// the request and response encoding differ.
+type AdminV1Controller struct {
+	adm      AdminController
+	config   s3response.AdminGatewayConfig
+	reloader ConfigReloader
+}
+
+// ConfigReloader reloads the gateway configuration file
+type ConfigReloader interface {
+	// Config returns the current gateway configuration
+	Config() s3response.AdminGatewayConfig
+	// Reload applies the changed configuration file settings
+	// that can change while the gateway is running
+	Reload() (s3response.AdminConfigReload, error)
+}
+
+// NewAdminV1Controller creates the admin json api controller. The
+// reloader is optional, the gateway configuration is static without it.
+func NewAdminV1Controller(adm AdminController, config s3response.AdminGatewayConfig, reloader ConfigReloader) AdminV1Controller {
+	return AdminV1Controller{adm: adm, config: config, reloader: reloader}
+}
+
+// ListUsers lists the user accounts sorted by the access key
+func (c AdminV1Controller) ListUsers(ctx *fiber.Ctx) (*Response, error) {
+	limit, err := adminV1Limit(ctx)
+	if err != nil {
+		return &Response{
+			MetaOpts: &MetaOptions{},
+		}, err
+	}
+
+	accs, err := c.adm.iam.ListUserAccounts()
+	if err != nil {
+		return &Response{
+			MetaOpts: &MetaOptions{},
+		}, err
+	}
+
+	sort.Slice(accs, func(i, j int) bool { return accs[i].Access < accs[j].Access })
+
+	result := s3response.AdminList[s3response.AdminUser]{
+		Items: []s3response.AdminUser{},
+	}
+	marker := ctx.Query("marker")
+	for _, acc := range accs {
+		if acc.Access <= marker {
+			continue
+		}
+		if len(result.Items) == limit {
+			result.NextMarker = result.Items[limit-1].Access
+			break
+		}
+		result.Items = append(result.Items, adminV1User(acc))
+	}
+
+	return &Response{
+		Data:     result,
+		MetaOpts: &MetaOptions{},
+	}, nil
+}
+
+func (c AdminV1Controller) GetUser(ctx *fiber.Ctx) (*Response, error) {
+	acc, err := c.adm.iam.GetUserAccount(ctx.Params("access"))
+	if err != nil {
+		return &Response{
+			MetaOpts: &MetaOptions{},
+		}, adminV1IAMError(err)
+	}
+
+	return &Response{
+		Data:     adminV1User(acc),
+		MetaOpts: &MetaOptions{},
+	}, nil
+}
+
+func (c AdminV1Controller) CreateUser(ctx *fiber.Ctx) (*Response, error) {
+	var usr s3response.AdminUser
+	if err := json.Unmarshal(ctx.Body(), &usr); err != nil {
+		return &Response{
+			MetaOpts: &MetaOptions{},
+		}, s3err.GetAPIError(s3err.ErrAdminMalformedJSON)
+	}
+	if usr.Access == "" || usr.Secret == "" {
+		return &Response{
+			MetaOpts: &MetaOptions{},
+		}, s3err.GetAPIError(s3err.ErrAdminMalformedJSON)
+	}
+
+	acc := auth.Account{
+		Access:    usr.Access,
+		Secret:    usr.Secret,
+		Role:      auth.Role(usr.Role),
+		UserID:    usr.UserID,
+		GroupID:   usr.GroupID,
+		ProjectID: usr.ProjectID,
+		Email:     usr.Email,
+	}
+	if !acc.Role.IsValid() {
+		return &Response{
+			MetaOpts: &MetaOptions{},
+		}, s3err.GetAPIError(s3err.ErrAdminInvalidUserRole)
+	}
+
+	err := c.adm.iam.CreateAccount(acc)
+	if err != nil {
+		return &Response{
+			MetaOpts: &MetaOptions{},
+		}, adminV1IAMError(err)
+	}
+
+	return &Response{
+		Data: adminV1User(acc),
+		MetaOpts: &MetaOptions{
+			Status: http.StatusCreated,
+		},
+	}, nil
+}
+
+// UpdateUser updates the user properties set in the request body
+func (c AdminV1Controller) UpdateUser(ctx *fiber.Ctx) (*Response, error) {
+	access := ctx.Params("access")
+
+	var props auth.MutableProps
+	if err := json.Unmarshal(ctx.Body(), &props); err != nil {
+		return &Response{
+			MetaOpts: &MetaOptions{},
+		}, s3err.GetAPIError(s3err.ErrAdminMalformedJSON)
+	}
+	if err := props.Validate(); err != nil {
+		return &Response{
+			MetaOpts: &MetaOptions{},
+		}, err
+	}
+
+	err := c.adm.iam.UpdateUserAccount(access, props)
+	if err != nil {
+		return &Response{
+			MetaOpts: &MetaOptions{},
+		}, adminV1IAMError(err)
+	}
+
+	acc, err := c.adm.iam.GetUserAccount(access)
+	if err != nil {
+		return &Response{
+			MetaOpts: &MetaOptions{},
+		}, adminV1IAMError(err)
+	}
+
+	return &Response{
+		Data:     adminV1User(acc),
+		MetaOpts: &MetaOptions{},
+	}, nil
+}
+
+func (c AdminV1Controller) DeleteUser(ctx *fiber.Ctx) (*Response, error) {
+	access := ctx.Params("access")
+
+	// the IAM services don't fail deleting the missing users
+	_, err := c.adm.iam.GetUserAccount(access)
+	if err == nil {
+		err = c.adm.iam.DeleteUserAccount(access)
+	}
+	if err != nil {
+		return &Response{
+			MetaOpts: &MetaOptions{},
+		}, adminV1IAMError(err)
+	}
+
+	return &Response{
+		MetaOpts: &MetaOptions{
+			Status: http.StatusNoContent,
+		},
+	}, nil
+}
+
+// ListBuckets lists the buckets and their owners sorted by name
+func (c AdminV1Controller) ListBuckets(ctx *fiber.Ctx) (*Response, error) {
+	limit, err := adminV1Limit(ctx)
+	if err != nil {
+		return &Response{
+			MetaOpts: &MetaOptions{},
+		}, err
+	}
+
+	buckets, err := c.adm.be.ListBucketsAndOwners(ctx.Context())
+	if err != nil {
+		return &Response{
+			MetaOpts: &MetaOptions{},
+		}, err
+	}
+
+	sort.Slice(buckets, func(i, j int) bool { return buckets[i].Name < buckets[j].Name })
+
+	result := s3response.AdminList[s3response.Bucket]{
+		Items: []s3response.Bucket{},
+	}
+	marker := ctx.Query("marker")
+	for _, bucket := range buckets {
+		if bucket.Name <= marker {
+			continue
+		}
+		if len(result.Items) == limit {
+			result.NextMarker = result.Items[limit-1].Name
+			break
+		}
+		result.Items = append(result.Items, bucket)
+	}
+
+	return &Response{
+		Data:     result,
+		MetaOpts: &MetaOptions{},
+	}, nil
+}
+
+func (c AdminV1Controller) GetBucket(ctx *fiber.Ctx) (*Response, error) {
+	name := ctx.Params("bucket")
+
+	buckets, err := c.adm.be.ListBucketsAndOwners(ctx.Context())
+	if err != nil {
+		return &Response{
+			MetaOpts: &MetaOptions{},
+		}, err
+	}
+
+	for _, bucket := range buckets {
+		if bucket.Name == name {
+			return &Response{
+				Data: bucket,
+				MetaOpts: &MetaOptions{
+					BucketOwner: bucket.Owner,
+				},
+			}, nil
+		}
+	}
+
+	return &Response{
+		MetaOpts: &MetaOptions{},
+	}, s3err.GetAPIError(s3err.ErrNoSuchBucket)
+}
+
+// CreateBucket creates a bucket owned by the user in the request body.
+// The bucket ACL, object lock and ownership are set with the same
+// headers as in the S3 CreateBucket.
+func (c AdminV1Controller) CreateBucket(ctx *fiber.Ctx) (*Response, error) {
+	var input s3response.AdminBucketOwner
+	if err := json.Unmarshal(ctx.Body(), &input); err != nil {
+		return &Response{
+			MetaOpts: &MetaOptions{},
+		}, s3err.GetAPIError(s3err.ErrAdminMalformedJSON)
+	}
+	if input.Owner == "" {
+		return &Response{
+			MetaOpts: &MetaOptions{},
+		}, s3err.GetAPIError(s3err.ErrAdminMissingBucketOwner)
+	}
+
+	acc, err := c.adm.iam.GetUserAccount(input.Owner)
+	if err != nil {
+		return &Response{
+			MetaOpts: &MetaOptions{},
+		}, adminV1IAMError(err)
+	}
+
+	utils.ContextKeyBucketOwner.Set(ctx, acc)
+	// the S3 CreateBucket parses the body as the xml bucket configuration
+	ctx.Request().ResetBody()
+
+	_, err = c.adm.s3api.CreateBucket(ctx)
+	if err != nil {
+		return &Response{
+			MetaOpts: &MetaOptions{
+				BucketOwner: acc.Access,
+			},
+		}, err
+	}
+
+	return &Response{
+		Data: s3response.Bucket{
+			Name:  ctx.Params("bucket"),
+			Owner: acc.Access,
+		},
+		MetaOpts: &MetaOptions{
+			BucketOwner: acc.Access,
+			Status:      http.StatusCreated,
+		},
+	}, nil
+}
+
+func (c AdminV1Controller) ChangeBucketOwner(ctx *fiber.Ctx) (*Response, error) {
+	bucket := ctx.Params("bucket")
+
+	var input s3response.AdminBucketOwner
+	if err := json.Unmarshal(ctx.Body(), &input); err != nil {
+		return &Response{
+			MetaOpts: &MetaOptions{},
+		}, s3err.GetAPIError(s3err.ErrAdminMalformedJSON)
+	}
+	if input.Owner == "" {
+		return &Response{
+			MetaOpts: &MetaOptions{},
+		}, s3err.GetAPIError(s3err.ErrAdminMissingBucketOwner)
+	}
+
+	_, err := c.adm.iam.GetUserAccount(input.Owner)
+	if err != nil {
+		return &Response{
+			MetaOpts: &MetaOptions{},
+		}, adminV1IAMError(err)
+	}
+
+	err = c.adm.be.ChangeBucketOwner(ctx.Context(), bucket, input.Owner)
+	if err != nil {
+		return &Response{
+			MetaOpts: &MetaOptions{},
+		}, err
+	}
+
+	return &Response{
+		Data: s3response.Bucket{
+			Name:  bucket,
+			Owner: input.Owner,
+		},
+		MetaOpts: &MetaOptions{
+			BucketOwner: input.Owner,
+		},
+	}, nil
+}
+
+// Quotas rejects all the quota requests, the storage quotas
+// are enforced by the backend storage rather than the gateway
+func (c AdminV1Controller) Quotas(ctx *fiber.Ctx) (*Response, error) {
+	return &Response{
+		MetaOpts: &MetaOptions{},
+	}, s3err.GetAPIError(s3err.ErrAdminQuotasNotSupported)
+}
+
+func (c AdminV1Controller) GetConfig(ctx *fiber.Ctx) (*Response, error) {
+	config := c.config
+	if c.reloader != nil {
+		config = c.reloader.Config()
+	}
+
+	return &Response{
+		Data:     config,
+		MetaOpts: &MetaOptions{},
+	}, nil
+}
+
+// ReloadConfig reloads the gateway configuration file and reports
+// the applied settings and the settings that require a restart
+func (c AdminV1Controller) ReloadConfig(ctx *fiber.Ctx) (*Response, error) {
+	if c.reloader == nil {
+		return &Response{
+			MetaOpts: &MetaOptions{},
+		}, s3err.GetAPIError(s3err.ErrAdminConfigReloadNotEnabled)
+	}
+
+	result, err := c.reloader.Reload()
+	if err != nil {
+		return &Response{
+			MetaOpts: &MetaOptions{},
+		}, s3err.GetAdminInvalidConfigErr(err)
+	}
+
+	return &Response{
+		Data:     result,
+		MetaOpts: &MetaOptions{},
+	}, nil
+}
+
+// NoSuchResource is the fallback handler of the unknown admin api paths
+func (c AdminV1Controller) NoSuchResource(ctx *fiber.Ctx) (*Response, error) {
+	return &Response{
+		MetaOpts: &MetaOptions{},
+	}, s3err.GetAPIError(s3err.ErrAdminNoSuchResource)
+}
+
+func adminV1User(acc auth.Account) s3response.AdminUser {
+	return s3response.AdminUser{
+		Access:    acc.Access,
+		Role:      string(acc.Role),
+		UserID:    acc.UserID,
+		GroupID:   acc.GroupID,
+		ProjectID: acc.ProjectID,
+		Email:     acc.Email,
+	}
+}
+
+// adminV1IAMError converts the IAM service errors to api errors
+func adminV1IAMError(err error) error {
+	switch {
+	case errors.Is(err, auth.ErrNoSuchUser):
+		return s3err.GetAPIError(s3err.ErrAdminUserNotFound)
+	case errors.Is(err, auth.ErrUserExists):
+		return s3err.GetAPIError(s3err.ErrAdminUserExists)
+	}
+	return err
+}
+
+func adminV1Limit(ctx *fiber.Ctx) (int, error) {
+	str := ctx.Query("limit")
+	if str == "" {
+		return adminV1DefaultLimit, nil
+	}
+
+	limit, err := strconv.Atoi(str)
+	if err != nil || limit < 1 || limit > adminV1MaxLimit {
+		return 0, s3err.GetAPIError(s3err.ErrAdminInvalidLimit)
+	}
+	return limit, nil
+}
+
+// ProcessJSONHandlers is the ProcessHandlers counterpart of the
+// admin json api, which sends json responses and error bodies
+func ProcessJSONHandlers(controller Controller, s3action string, svc *Services, handlers ...fiber.Handler) fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		for _, handler := range handlers {
+			err := handler(ctx)
+			if err != nil {
+				return ProcessJSONController(ctx, func(ctx *fiber.Ctx) (*Response, error) {
+					return &Response{
+						MetaOpts: &MetaOptions{},
+					}, err
+				}, s3action, svc)
+			}
+		}
+
+		return ProcessJSONController(ctx, controller, s3action, svc)
+	}
+}
+
+// ProcessJSONController executes the given admin json api controller
+// and handles the metrics and access logs
+func ProcessJSONController(ctx *fiber.Ctx, controller Controller, s3action string, svc *Services) error {
+	response, err := controller(ctx)
+
+	SetResponseHeaders(ctx, response.Headers)
+
+	opts := response.MetaOpts
+	if opts == nil {
+		opts = &MetaOptions{}
+	}
+
+	var body []byte
+	if err == nil && response.Data != nil {
+		body, err = json.Marshal(response.Data)
+	}
+	if err != nil {
+		serr, ok := err.(s3err.APIError)
+		if !ok {
+			debuglogger.InternalError(err)
+			serr = s3err.GetAPIError(s3err.ErrInternalError)
+		}
+		opts.Status = serr.HTTPStatusCode
+		// marshalling the error body can't fail
+		body, _ = json.Marshal(s3response.AdminError{
+			Error: s3response.AdminErrorDetail{
+				Code:    serr.Code,
+				Message: serr.Description,
+				Status:  serr.HTTPStatusCode,
+			},
+		})
+	}
+
+	if svc.MetricsManager != nil {
+		svc.MetricsManager.Send(ctx, err, s3action, opts.ContentLength, opts.Status)
+	}
+	if svc.Logger != nil {
+		svc.Logger.Log(ctx, err, body, s3log.LogMeta{
+			Action:      s3action,
+			BucketOwner: opts.BucketOwner,
+		})
+	}
+
+	if opts.Status == 0 {
+		opts.Status = http.StatusOK
+	}
+	ctx.Status(opts.Status)
+
+	if body == nil {
+		return nil
+	}
+
+	ctx.Response().Header.SetContentType(fiber.MIMEApplicationJSON)
+	return ctx.Send(body)
+}
@@ -0,0 +1,353 @@
+// Copyright 2026 Versity Software
+// This file is licensed under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package controllers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/versity/versitygw/auth"
+	"github.com/versity/versitygw/s3err"
+	"github.com/versity/versitygw/s3response"
+)
+
+// testAdminV1App registers the admin json api routes without
+// the authentication middlewares
+func testAdminV1App(ctrl AdminV1Controller) *fiber.App {
+	app := fiber.New()
+	svc := &Services{}
+	v1 := app.Group(AdminV1Prefix)
+	v1.Get("/users", ProcessJSONHandlers(ctrl.ListUsers, "", svc))
+	v1.Post("/users", ProcessJSONHandlers(ctrl.CreateUser, "", svc))
+	v1.Get("/users/:access", ProcessJSONHandlers(ctrl.GetUser, "", svc))
+	v1.Patch("/users/:access", ProcessJSONHandlers(ctrl.UpdateUser, "", svc))
+	v1.Delete("/users/:access", ProcessJSONHandlers(ctrl.DeleteUser, "", svc))
+	v1.Get("/buckets", ProcessJSONHandlers(ctrl.ListBuckets, "", svc))
+	v1.Get("/buckets/:bucket", ProcessJSONHandlers(ctrl.GetBucket, "", svc))
+	v1.Put("/buckets/:bucket/owner", ProcessJSONHandlers(ctrl.ChangeBucketOwner, "", svc))
+	v1.All("/quotas", ProcessJSONHandlers(ctrl.Quotas, "", svc))
+	v1.Get("/config", ProcessJSONHandlers(ctrl.GetConfig, "", svc))
+	v1.Post("/config/reload", ProcessJSONHandlers(ctrl.ReloadConfig, "", svc))
+	v1.All("/*", ProcessJSONHandlers(ctrl.NoSuchResource, "", svc))
+	return app
+}
+
+func testAdminV1Request(t *testing.T, app *fiber.App, method, path string, body any) (int, []byte) {
+	t.Helper()
+
+	var payload []byte
+	if body != nil {
+		var err error
+		payload, err = json.Marshal(body)
+		require.NoError(t, err)
+	}
+
+	resp, err := app.Test(httptest.NewRequest(method, path, bytes.NewReader(payload)))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	b, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	if len(b) > 0 {
+		assert.Equal(t, fiber.MIMEApplicationJSON, resp.Header.Get("Content-Type"))
+	}
+
+	return resp.StatusCode, b
+}
+
+func assertAdminV1Error(t *testing.T, status int, body []byte, code s3err.ErrorCode) {
+	t.Helper()
+
+	apiErr := s3err.GetAPIError(code)
+	var res s3response.AdminError
+	require.NoError(t, json.Unmarshal(body, &res))
+	assert.Equal(t, apiErr.HTTPStatusCode, status)
+	assert.Equal(t, s3response.AdminErrorDetail{
+		Code:    apiErr.Code,
+		Message: apiErr.Description,
+		Status:  apiErr.HTTPStatusCode,
+	}, res.Error)
+}
+
+func TestAdminV1Controller_ListUsers(t *testing.T) {
+	iam := &IAMServiceMock{
+		ListUserAccountsFunc: func() ([]auth.Account, error) {
+			return []auth.Account{
+				{Access: "carol", Secret: "secret", Role: auth.RoleUser},
+				{Access: "alice", Secret: "secret", Role: auth.RoleAdmin},
+				{Access: "bob", Secret: "secret", Role: auth.RoleUserPlus},
+			}, nil
+		},
+	}
+	app := testAdminV1App(NewAdminV1Controller(AdminController{iam: iam}, s3response.AdminGatewayConfig{}, nil))
+
+	status, body := testAdminV1Request(t, app, http.MethodGet, "/admin/v1/users?limit=2", nil)
+	assert.Equal(t, http.StatusOK, status)
+
+	var page s3response.AdminList[s3response.AdminUser]
+	require.NoError(t, json.Unmarshal(body, &page))
+	assert.Equal(t, []s3response.AdminUser{
+		{Access: "alice", Role: "admin"},
+		{Access: "bob", Role: "userplus"},
+	}, page.Items)
+	assert.Equal(t, "bob", page.NextMarker)
+	assert.NotContains(t, string(body), "secret")
+
+	status, body = testAdminV1Request(t, app, http.MethodGet, "/admin/v1/users?limit=2&marker=bob", nil)
+	assert.Equal(t, http.StatusOK, status)
+
+	page = s3response.AdminList[s3response.AdminUser]{}
+	require.NoError(t, json.Unmarshal(body, &page))
+	assert.Equal(t, []s3response.AdminUser{{Access: "carol", Role: "user"}}, page.Items)
+	assert.Empty(t, page.NextMarker)
+
+	for _, limit := range []string{"0", "1001", "abc"} {
+		status, body = testAdminV1Request(t, app, http.MethodGet, "/admin/v1/users?limit="+limit, nil)
+		assertAdminV1Error(t, status, body, s3err.ErrAdminInvalidLimit)
+	}
+}
+
+func TestAdminV1Controller_Users(t *testing.T) {
+	users := map[string]auth.Account{}
+	iam := &IAMServiceMock{
+		CreateAccountFunc: func(account auth.Account) error {
+			if _, ok := users[account.Access]; ok {
+				return auth.ErrUserExists
+			}
+			users[account.Access] = account
+			return nil
+		},
+		GetUserAccountFunc: func(access string) (auth.Account, error) {
+			acc, ok := users[access]
+			if !ok {
+				return auth.Account{}, auth.ErrNoSuchUser
+			}
+			return acc, nil
+		},
+		UpdateUserAccountFunc: func(access string, props auth.MutableProps) error {
+			acc, ok := users[access]
+			if !ok {
+				return auth.ErrNoSuchUser
+			}
+			if props.Role != "" {
+				acc.Role = props.Role
+			}
+			users[access] = acc
+			return nil
+		},
+		DeleteUserAccountFunc: func(access string) error {
+			delete(users, access)
+			return nil
+		},
+	}
+	app := testAdminV1App(NewAdminV1Controller(AdminController{iam: iam}, s3response.AdminGatewayConfig{}, nil))
+
+	status, body := testAdminV1Request(t, app, http.MethodPost, "/admin/v1/users",
+		s3response.AdminUser{Access: "alice", Secret: "secret", Role: "user"})
+	assert.Equal(t, http.StatusCreated, status)
+	assert.JSONEq(t, `{"access":"alice","role":"user","userID":0,"groupID":0,"projectID":0}`, string(body))
+
+	status, body = testAdminV1Request(t, app, http.MethodPost, "/admin/v1/users",
+		s3response.AdminUser{Access: "alice", Secret: "secret", Role: "user"})
+	assertAdminV1Error(t, status, body, s3err.ErrAdminUserExists)
+
+	status, body = testAdminV1Request(t, app, http.MethodPost, "/admin/v1/users",
+		s3response.AdminUser{Access: "bob", Secret: "secret", Role: "invalid"})
+	assertAdminV1Error(t, status, body, s3err.ErrAdminInvalidUserRole)
+
+	status, body = testAdminV1Request(t, app, http.MethodPost, "/admin/v1/users", "not a user")
+	assertAdminV1Error(t, status, body, s3err.ErrAdminMalformedJSON)
+
+	status, body = testAdminV1Request(t, app, http.MethodPatch, "/admin/v1/users/alice",
+		auth.MutableProps{Role: auth.RoleUserPlus})
+	assert.Equal(t, http.StatusOK, status)
+	assert.JSONEq(t, `{"access":"alice","role":"userplus","userID":0,"groupID":0,"projectID":0}`, string(body))
+
+	status, body = testAdminV1Request(t, app, http.MethodGet, "/admin/v1/users/bob", nil)
+	assertAdminV1Error(t, status, body, s3err.ErrAdminUserNotFound)
+
+	status, body = testAdminV1Request(t, app, http.MethodDelete, "/admin/v1/users/alice", nil)
+	assert.Equal(t, http.StatusNoContent, status)
+	assert.Empty(t, body)
+
+	status, body = testAdminV1Request(t, app, http.MethodDelete, "/admin/v1/users/alice", nil)
+	assertAdminV1Error(t, status, body, s3err.ErrAdminUserNotFound)
+}
+
+func TestAdminV1Controller_Buckets(t *testing.T) {
+	buckets := []s3response.Bucket{
+		{Name: "bucket-b", Owner: "bob"},
+		{Name: "bucket-a", Owner: "alice"},
+	}
+	be := &BackendMock{
+		ListBucketsAndOwnersFunc: func(context.Context) ([]s3response.Bucket, error) {
+			return buckets, nil
+		},
+		ChangeBucketOwnerFunc: func(_ context.Context, bucket, owner string) error {
+			return nil
+		},
+	}
+	iam := &IAMServiceMock{
+		GetUserAccountFunc: func(access string) (auth.Account, error) {
+			if access != "alice" {
+				return auth.Account{}, auth.ErrNoSuchUser
+			}
+			return auth.Account{Access: access}, nil
+		},
+	}
+	app := testAdminV1App(NewAdminV1Controller(AdminController{iam: iam, be: be}, s3response.AdminGatewayConfig{}, nil))
+
+	status, body := testAdminV1Request(t, app, http.MethodGet, "/admin/v1/buckets?limit=1", nil)
+	assert.Equal(t, http.StatusOK, status)
+	assert.JSONEq(t, `{"items":[{"name":"bucket-a","owner":"alice"}],"nextMarker":"bucket-a"}`, string(body))
+
+	status, body = testAdminV1Request(t, app, http.MethodGet, "/admin/v1/buckets/bucket-b", nil)
+	assert.Equal(t, http.StatusOK, status)
+	assert.JSONEq(t, `{"name":"bucket-b","owner":"bob"}`, string(body))
+
+	status, body = testAdminV1Request(t, app, http.MethodGet, "/admin/v1/buckets/missing", nil)
+	assertAdminV1Error(t, status, body, s3err.ErrNoSuchBucket)
+
+	status, body = testAdminV1Request(t, app, http.MethodPut, "/admin/v1/buckets/bucket-b/owner",
+		s3response.AdminBucketOwner{Owner: "alice"})
+	assert.Equal(t, http.StatusOK, status)
+	assert.JSONEq(t, `{"name":"bucket-b","owner":"alice"}`, string(body))
+
+	status, body = testAdminV1Request(t, app, http.MethodPut, "/admin/v1/buckets/bucket-b/owner",
+		s3response.AdminBucketOwner{Owner: "carol"})
+	assertAdminV1Error(t, status, body, s3err.ErrAdminUserNotFound)
+
+	status, body = testAdminV1Request(t, app, http.MethodPut, "/admin/v1/buckets/bucket-b/owner",
+		s3response.AdminBucketOwner{})
+	assertAdminV1Error(t, status, body, s3err.ErrAdminMissingBucketOwner)
+}
+
+func TestAdminV1Controller_Misc(t *testing.T) {
+	app := testAdminV1App(NewAdminV1Controller(AdminController{}, s3response.AdminGatewayConfig{
+		Backend: "posix",
+		Region:  "us-east-1",
+	}, nil))
+
+	status, body := testAdminV1Request(t, app, http.MethodGet, "/admin/v1/config", nil)
+	assert.Equal(t, http.StatusOK, status)
+
+	var cfg s3response.AdminGatewayConfig
+	require.NoError(t, json.Unmarshal(body, &cfg))
+	assert.Equal(t, "posix", cfg.Backend)
+	assert.Equal(t, "us-east-1", cfg.Region)
+
+	status, body = testAdminV1Request(t, app, http.MethodPost, "/admin/v1/config/reload", nil)
+	assertAdminV1Error(t, status, body, s3err.ErrAdminConfigReloadNotEnabled)
+
+	status, body = testAdminV1Request(t, app, http.MethodPut, "/admin/v1/quotas", nil)
+	assertAdminV1Error(t, status, body, s3err.ErrAdminQuotasNotSupported)
+
+	status, body = testAdminV1Request(t, app, http.MethodGet, "/admin/v1/unknown", nil)
+	assertAdminV1Error(t, status, body, s3err.ErrAdminNoSuchResource)
+}
+
+type testConfigReloader struct {
+	config s3response.AdminGatewayConfig
+	err    error
+}
+
+func (r *testConfigReloader) Config() s3response.AdminGatewayConfig {
+	return r.config
+}
+
+func (r *testConfigReloader) Reload() (s3response.AdminConfigReload, error) {
+	if r.err != nil {
+		return s3response.AdminConfigReload{}, r.err
+	}
+	r.config.ReadOnly = true
+	return s3response.AdminConfigReload{
+		Applied:         []string{"readonly"},
+		RestartRequired: []string{"port"},
+		Config:          r.config,
+	}, nil
+}
+
+func TestAdminV1Controller_ReloadConfig(t *testing.T) {
+	reloader := &testConfigReloader{
+		config: s3response.AdminGatewayConfig{Backend: "posix"},
+	}
+	app := testAdminV1App(NewAdminV1Controller(AdminController{}, s3response.AdminGatewayConfig{}, reloader))
+
+	status, body := testAdminV1Request(t, app, http.MethodPost, "/admin/v1/config/reload", nil)
+	assert.Equal(t, http.StatusOK, status)
+
+	var res s3response.AdminConfigReload
+	require.NoError(t, json.Unmarshal(body, &res))
+	assert.Equal(t, []string{"readonly"}, res.Applied)
+	assert.Equal(t, []string{"port"}, res.RestartRequired)
+	assert.True(t, res.Config.ReadOnly)
+
+	// the config reflects the reloaded settings
+	status, body = testAdminV1Request(t, app, http.MethodGet, "/admin/v1/config", nil)
+	assert.Equal(t, http.StatusOK, status)
+
+	var cfg s3response.AdminGatewayConfig
+	require.NoError(t, json.Unmarshal(body, &cfg))
+	assert.Equal(t, "posix", cfg.Backend)
+	assert.True(t, cfg.ReadOnly)
+
+	reloader.err = io.ErrUnexpectedEOF
+	status, body = testAdminV1Request(t, app, http.MethodPost, "/admin/v1/config/reload", nil)
+	assert.Equal(t, http.StatusBadRequest, status)
+
+	var apiErr s3response.AdminError
+	require.NoError(t, json.Unmarshal(body, &apiErr))
+	assert.Equal(t, "XAdminInvalidConfig", apiErr.Error.Code)
+	assert.Contains(t, apiErr.Error.Message, io.ErrUnexpectedEOF.Error())
+}
+
+func TestProcessJSONHandlers(t *testing.T) {
+	app := fiber.New()
+	app.Get("/denied", ProcessJSONHandlers(func(*fiber.Ctx) (*Response, error) {
+		t.Error("controller called after the failed middleware")
+		return &Response{}, nil
+	}, "", &Services{}, func(*fiber.Ctx) error {
+		return s3err.GetAPIError(s3err.ErrAdminAccessDenied)
+	}))
+	app.Get("/internal", ProcessJSONHandlers(func(*fiber.Ctx) (*Response, error) {
+		return &Response{}, io.ErrUnexpectedEOF
+	}, "", &Services{}))
+
+	status, body := testAdminV1Request(t, app, http.MethodGet, "/denied", nil)
+	assertAdminV1Error(t, status, body, s3err.ErrAdminAccessDenied)
+
+	status, body = testAdminV1Request(t, app, http.MethodGet, "/internal", nil)
+	assertAdminV1Error(t, status, body, s3err.ErrInternalError)
+}
+
+func TestAdminOpenAPISpec(t *testing.T) {
+	var spec struct {
+		OpenAPI string         `json:"openapi"`
+		Paths   map[string]any `json:"paths"`
+	}
+	require.NoError(t, json.Unmarshal(AdminOpenAPISpec, &spec))
+	assert.Equal(t, "3.0.3", spec.OpenAPI)
+	for _, path := range []string{"/users", "/users/{access}", "/buckets", "/buckets/{bucket}", "/buckets/{bucket}/owner", "/quotas", "/config", "/config/reload"} {
+		assert.Contains(t, spec.Paths, path)
+	}
+}
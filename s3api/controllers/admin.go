@@ -153,6 +153,56 @@ func (c AdminController) ChangeBucketOwner(ctx *fiber.Ctx) (*Response, error) {
 	}, err
 }
 
+func (c AdminController) SnapshotBucket(ctx *fiber.Ctx) (*Response, error) {
+	bucket := ctx.Query("bucket")
+	snapshot := ctx.Query("snapshot")
+
+	err := c.be.SnapshotBucket(ctx.Context(), bucket, snapshot)
+	if err != nil {
+		return &Response{
+			MetaOpts: &MetaOptions{},
+		}, err
+	}
+
+	// the snapshot objects are added in the background
+	return &Response{
+		MetaOpts: &MetaOptions{
+			Status: http.StatusAccepted,
+		},
+	}, nil
+}
+
+func (c AdminController) SnapshotBucketStatus(ctx *fiber.Ctx) (*Response, error) {
+	snapshot := ctx.Query("snapshot")
+
+	status, err := c.be.SnapshotBucketStatus(ctx.Context(), snapshot)
+	return &Response{
+		Data:     status,
+		MetaOpts: &MetaOptions{},
+	}, err
+}
+
+func (c AdminController) ScrubBucket(ctx *fiber.Ctx) (*Response, error) {
+	bucket := ctx.Query("bucket")
+
+	report, err := c.be.ScrubBucket(ctx.Context(), bucket)
+	return &Response{
+		Data:     report,
+		MetaOpts: &MetaOptions{},
+	}, err
+}
+
+func (c AdminController) TransitionObject(ctx *fiber.Ctx) (*Response, error) {
+	bucket := ctx.Query("bucket")
+	key := ctx.Query("key")
+	versionId := ctx.Query("versionId")
+
+	err := c.be.TransitionObject(ctx.Context(), bucket, key, versionId)
+	return &Response{
+		MetaOpts: &MetaOptions{},
+	}, err
+}
+
 func (c AdminController) ListBuckets(ctx *fiber.Ctx) (*Response, error) {
 	buckets, err := c.be.ListBucketsAndOwners(ctx.Context())
 	return &Response{
@@ -513,6 +513,255 @@ func TestAdminController_ChangeBucketOwner(t *testing.T) {
 	}
 }
 
+func TestAdminController_SnapshotBucket(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  testInput
+		output testOutput
+	}{
+		{
+			name: "backend returns error",
+			input: testInput{
+				beErr: s3err.GetAPIError(s3err.ErrBucketAlreadyExists),
+			},
+			output: testOutput{
+				response: &Response{
+					MetaOpts: &MetaOptions{},
+				},
+				err: s3err.GetAPIError(s3err.ErrBucketAlreadyExists),
+			},
+		},
+		{
+			name: "successful response",
+			output: testOutput{
+				response: &Response{
+					MetaOpts: &MetaOptions{
+						Status: http.StatusAccepted,
+					},
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			be := &BackendMock{
+				SnapshotBucketFunc: func(contextMoqParam context.Context, bucket, snapshot string) error {
+					return tt.input.beErr
+				},
+			}
+
+			ctrl := AdminController{
+				be: be,
+			}
+
+			testController(
+				t,
+				ctrl.SnapshotBucket,
+				tt.output.response,
+				tt.output.err,
+				ctxInputs{},
+			)
+		})
+	}
+}
+
+func TestAdminController_SnapshotBucketStatus(t *testing.T) {
+	status := s3response.SnapshotStatus{
+		Snapshot: "snapshot",
+		Source:   "bucket",
+		Status:   s3response.SnapshotRunning,
+		Objects:  3,
+	}
+
+	tests := []struct {
+		name   string
+		input  testInput
+		output testOutput
+	}{
+		{
+			name: "backend returns error",
+			input: testInput{
+				beRes: s3response.SnapshotStatus{},
+				beErr: s3err.GetAPIError(s3err.ErrNoSuchBucket),
+			},
+			output: testOutput{
+				response: &Response{
+					Data:     s3response.SnapshotStatus{},
+					MetaOpts: &MetaOptions{},
+				},
+				err: s3err.GetAPIError(s3err.ErrNoSuchBucket),
+			},
+		},
+		{
+			name: "successful response",
+			input: testInput{
+				beRes: status,
+			},
+			output: testOutput{
+				response: &Response{
+					Data:     status,
+					MetaOpts: &MetaOptions{},
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			be := &BackendMock{
+				SnapshotBucketStatusFunc: func(contextMoqParam context.Context, snapshot string) (s3response.SnapshotStatus, error) {
+					return tt.input.beRes.(s3response.SnapshotStatus), tt.input.beErr
+				},
+			}
+
+			ctrl := AdminController{
+				be: be,
+			}
+
+			testController(
+				t,
+				ctrl.SnapshotBucketStatus,
+				tt.output.response,
+				tt.output.err,
+				ctxInputs{},
+			)
+		})
+	}
+}
+
+func TestAdminController_ScrubBucket(t *testing.T) {
+	report := s3response.ScrubReport{
+		Bucket:  "bucket",
+		Objects: 2,
+		Bytes:   10,
+		Mismatches: []s3response.ScrubMismatch{
+			{
+				Key:       "obj",
+				Size:      5,
+				Algorithm: "CRC32",
+				Expected:  "AAAAAA==",
+				Actual:    "AAAAAQ==",
+			},
+		},
+	}
+
+	tests := []struct {
+		name   string
+		input  testInput
+		output testOutput
+	}{
+		{
+			name: "backend returns error",
+			input: testInput{
+				beRes: s3response.ScrubReport{},
+				beErr: s3err.GetAPIError(s3err.ErrNoSuchBucket),
+			},
+			output: testOutput{
+				response: &Response{
+					Data:     s3response.ScrubReport{},
+					MetaOpts: &MetaOptions{},
+				},
+				err: s3err.GetAPIError(s3err.ErrNoSuchBucket),
+			},
+		},
+		{
+			name: "successful response",
+			input: testInput{
+				beRes: report,
+			},
+			output: testOutput{
+				response: &Response{
+					Data:     report,
+					MetaOpts: &MetaOptions{},
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			be := &BackendMock{
+				ScrubBucketFunc: func(contextMoqParam context.Context, bucket string) (s3response.ScrubReport, error) {
+					return tt.input.beRes.(s3response.ScrubReport), tt.input.beErr
+				},
+			}
+
+			ctrl := AdminController{
+				be: be,
+			}
+
+			testController(
+				t,
+				ctrl.ScrubBucket,
+				tt.output.response,
+				tt.output.err,
+				ctxInputs{},
+			)
+		})
+	}
+}
+
+func TestAdminController_TransitionObject(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  testInput
+		output testOutput
+	}{
+		{
+			name: "backend returns error",
+			input: testInput{
+				beErr: s3err.GetAPIError(s3err.ErrNotImplemented),
+			},
+			output: testOutput{
+				response: &Response{
+					MetaOpts: &MetaOptions{},
+				},
+				err: s3err.GetAPIError(s3err.ErrNotImplemented),
+			},
+		},
+		{
+			name:  "successful response",
+			input: testInput{},
+			output: testOutput{
+				response: &Response{
+					MetaOpts: &MetaOptions{},
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var bucket, object, versionId string
+			be := &BackendMock{
+				TransitionObjectFunc: func(contextMoqParam context.Context, b, o, v string) error {
+					bucket, object, versionId = b, o, v
+					return tt.input.beErr
+				},
+			}
+
+			ctrl := AdminController{
+				be: be,
+			}
+
+			testController(
+				t,
+				ctrl.TransitionObject,
+				tt.output.response,
+				tt.output.err,
+				ctxInputs{
+					queries: map[string]string{
+						"bucket":    "bucket",
+						"key":       "obj",
+						"versionId": "id",
+					},
+				},
+			)
+
+			assert.Equal(t, "bucket", bucket)
+			assert.Equal(t, "obj", object)
+			assert.Equal(t, "id", versionId)
+		})
+	}
+}
+
 func TestAdminController_ListBuckets(t *testing.T) {
 	res := []s3response.Bucket{
 		{
@@ -681,7 +930,7 @@ func TestAdminController_CreateBucket(t *testing.T) {
 				},
 			}
 
-			s3api := New(be, iam, nil, nil, nil, false, false, "")
+			s3api := New(be, iam, nil, nil, nil, nil, false, "")
 
 			ctrl := AdminController{
 				iam:   iam,
@@ -65,6 +65,9 @@ var _ backend.Backend = &BackendMock{}
 //			DeleteObjectsFunc: func(contextMoqParam context.Context, deleteObjectsInput *s3.DeleteObjectsInput) (s3response.DeleteResult, error) {
 //				panic("mock out the DeleteObjects method")
 //			},
+//			DeletePublicAccessBlockFunc: func(contextMoqParam context.Context, bucket string) error {
+//				panic("mock out the DeletePublicAccessBlock method")
+//			},
 //			GetBucketAclFunc: func(contextMoqParam context.Context, getBucketAclInput *s3.GetBucketAclInput) ([]byte, error) {
 //				panic("mock out the GetBucketAcl method")
 //			},
@@ -104,6 +107,9 @@ var _ backend.Backend = &BackendMock{}
 //			GetObjectTaggingFunc: func(contextMoqParam context.Context, bucket string, object string, versionId string) (map[string]string, error) {
 //				panic("mock out the GetObjectTagging method")
 //			},
+//			GetPublicAccessBlockFunc: func(contextMoqParam context.Context, bucket string) ([]byte, error) {
+//				panic("mock out the GetPublicAccessBlock method")
+//			},
 //			HeadBucketFunc: func(contextMoqParam context.Context, headBucketInput *s3.HeadBucketInput) (*s3.HeadBucketOutput, error) {
 //				panic("mock out the HeadBucket method")
 //			},
@@ -167,18 +173,33 @@ var _ backend.Backend = &BackendMock{}
 //			PutObjectTaggingFunc: func(contextMoqParam context.Context, bucket string, object string, versionId string, tags map[string]string) error {
 //				panic("mock out the PutObjectTagging method")
 //			},
+//			PutPublicAccessBlockFunc: func(contextMoqParam context.Context, bucket string, config []byte) error {
+//				panic("mock out the PutPublicAccessBlock method")
+//			},
 //			RestoreObjectFunc: func(contextMoqParam context.Context, restoreObjectInput *s3.RestoreObjectInput) error {
 //				panic("mock out the RestoreObject method")
 //			},
+//			ScrubBucketFunc: func(contextMoqParam context.Context, bucket string) (s3response.ScrubReport, error) {
+//				panic("mock out the ScrubBucket method")
+//			},
 //			SelectObjectContentFunc: func(ctx context.Context, input *s3.SelectObjectContentInput) func(w *bufio.Writer) {
 //				panic("mock out the SelectObjectContent method")
 //			},
 //			ShutdownFunc: func()  {
 //				panic("mock out the Shutdown method")
 //			},
+//			SnapshotBucketFunc: func(contextMoqParam context.Context, bucket string, snapshot string) error {
+//				panic("mock out the SnapshotBucket method")
+//			},
+//			SnapshotBucketStatusFunc: func(contextMoqParam context.Context, snapshot string) (s3response.SnapshotStatus, error) {
+//				panic("mock out the SnapshotBucketStatus method")
+//			},
 //			StringFunc: func() string {
 //				panic("mock out the String method")
 //			},
+//			TransitionObjectFunc: func(contextMoqParam context.Context, bucket string, object string, versionId string) error {
+//				panic("mock out the TransitionObject method")
+//			},
 //			UploadPartFunc: func(contextMoqParam context.Context, uploadPartInput *s3.UploadPartInput) (*s3.UploadPartOutput, error) {
 //				panic("mock out the UploadPart method")
 //			},
@@ -234,6 +255,9 @@ type BackendMock struct {
 	// DeleteObjectsFunc mocks the DeleteObjects method.
 	DeleteObjectsFunc func(contextMoqParam context.Context, deleteObjectsInput *s3.DeleteObjectsInput) (s3response.DeleteResult, error)
 
+	// DeletePublicAccessBlockFunc mocks the DeletePublicAccessBlock method.
+	DeletePublicAccessBlockFunc func(contextMoqParam context.Context, bucket string) error
+
 	// GetBucketAclFunc mocks the GetBucketAcl method.
 	GetBucketAclFunc func(contextMoqParam context.Context, getBucketAclInput *s3.GetBucketAclInput) ([]byte, error)
 
@@ -273,6 +297,9 @@ type BackendMock struct {
 	// GetObjectTaggingFunc mocks the GetObjectTagging method.
 	GetObjectTaggingFunc func(contextMoqParam context.Context, bucket string, object string, versionId string) (map[string]string, error)
 
+	// GetPublicAccessBlockFunc mocks the GetPublicAccessBlock method.
+	GetPublicAccessBlockFunc func(contextMoqParam context.Context, bucket string) ([]byte, error)
+
 	// HeadBucketFunc mocks the HeadBucket method.
 	HeadBucketFunc func(contextMoqParam context.Context, headBucketInput *s3.HeadBucketInput) (*s3.HeadBucketOutput, error)
 
@@ -336,18 +363,33 @@ type BackendMock struct {
 	// PutObjectTaggingFunc mocks the PutObjectTagging method.
 	PutObjectTaggingFunc func(contextMoqParam context.Context, bucket string, object string, versionId string, tags map[string]string) error
 
+	// PutPublicAccessBlockFunc mocks the PutPublicAccessBlock method.
+	PutPublicAccessBlockFunc func(contextMoqParam context.Context, bucket string, config []byte) error
+
 	// RestoreObjectFunc mocks the RestoreObject method.
 	RestoreObjectFunc func(contextMoqParam context.Context, restoreObjectInput *s3.RestoreObjectInput) error
 
+	// ScrubBucketFunc mocks the ScrubBucket method.
+	ScrubBucketFunc func(contextMoqParam context.Context, bucket string) (s3response.ScrubReport, error)
+
 	// SelectObjectContentFunc mocks the SelectObjectContent method.
 	SelectObjectContentFunc func(ctx context.Context, input *s3.SelectObjectContentInput) func(w *bufio.Writer)
 
 	// ShutdownFunc mocks the Shutdown method.
 	ShutdownFunc func()
 
+	// SnapshotBucketFunc mocks the SnapshotBucket method.
+	SnapshotBucketFunc func(contextMoqParam context.Context, bucket string, snapshot string) error
+
+	// SnapshotBucketStatusFunc mocks the SnapshotBucketStatus method.
+	SnapshotBucketStatusFunc func(contextMoqParam context.Context, snapshot string) (s3response.SnapshotStatus, error)
+
 	// StringFunc mocks the String method.
 	StringFunc func() string
 
+	// TransitionObjectFunc mocks the TransitionObject method.
+	TransitionObjectFunc func(contextMoqParam context.Context, bucket string, object string, versionId string) error
+
 	// UploadPartFunc mocks the UploadPart method.
 	UploadPartFunc func(contextMoqParam context.Context, uploadPartInput *s3.UploadPartInput) (*s3.UploadPartOutput, error)
 
@@ -462,6 +504,13 @@ type BackendMock struct {
 			// DeleteObjectsInput is the deleteObjectsInput argument value.
 			DeleteObjectsInput *s3.DeleteObjectsInput
 		}
+		// DeletePublicAccessBlock holds details about calls to the DeletePublicAccessBlock method.
+		DeletePublicAccessBlock []struct {
+			// ContextMoqParam is the contextMoqParam argument value.
+			ContextMoqParam context.Context
+			// Bucket is the bucket argument value.
+			Bucket string
+		}
 		// GetBucketAcl holds details about calls to the GetBucketAcl method.
 		GetBucketAcl []struct {
 			// ContextMoqParam is the contextMoqParam argument value.
@@ -565,6 +614,13 @@ type BackendMock struct {
 			// VersionId is the versionId argument value.
 			VersionId string
 		}
+		// GetPublicAccessBlock holds details about calls to the GetPublicAccessBlock method.
+		GetPublicAccessBlock []struct {
+			// ContextMoqParam is the contextMoqParam argument value.
+			ContextMoqParam context.Context
+			// Bucket is the bucket argument value.
+			Bucket string
+		}
 		// HeadBucket holds details about calls to the HeadBucket method.
 		HeadBucket []struct {
 			// ContextMoqParam is the contextMoqParam argument value.
@@ -742,6 +798,15 @@ type BackendMock struct {
 			// Tags is the tags argument value.
 			Tags map[string]string
 		}
+		// PutPublicAccessBlock holds details about calls to the PutPublicAccessBlock method.
+		PutPublicAccessBlock []struct {
+			// ContextMoqParam is the contextMoqParam argument value.
+			ContextMoqParam context.Context
+			// Bucket is the bucket argument value.
+			Bucket string
+			// Config is the config argument value.
+			Config []byte
+		}
 		// RestoreObject holds details about calls to the RestoreObject method.
 		RestoreObject []struct {
 			// ContextMoqParam is the contextMoqParam argument value.
@@ -749,6 +814,13 @@ type BackendMock struct {
 			// RestoreObjectInput is the restoreObjectInput argument value.
 			RestoreObjectInput *s3.RestoreObjectInput
 		}
+		// ScrubBucket holds details about calls to the ScrubBucket method.
+		ScrubBucket []struct {
+			// ContextMoqParam is the contextMoqParam argument value.
+			ContextMoqParam context.Context
+			// Bucket is the bucket argument value.
+			Bucket string
+		}
 		// SelectObjectContent holds details about calls to the SelectObjectContent method.
 		SelectObjectContent []struct {
 			// Ctx is the ctx argument value.
@@ -759,9 +831,36 @@ type BackendMock struct {
 		// Shutdown holds details about calls to the Shutdown method.
 		Shutdown []struct {
 		}
+		// SnapshotBucket holds details about calls to the SnapshotBucket method.
+		SnapshotBucket []struct {
+			// ContextMoqParam is the contextMoqParam argument value.
+			ContextMoqParam context.Context
+			// Bucket is the bucket argument value.
+			Bucket string
+			// Snapshot is the snapshot argument value.
+			Snapshot string
+		}
+		// SnapshotBucketStatus holds details about calls to the SnapshotBucketStatus method.
+		SnapshotBucketStatus []struct {
+			// ContextMoqParam is the contextMoqParam argument value.
+			ContextMoqParam context.Context
+			// Snapshot is the snapshot argument value.
+			Snapshot string
+		}
 		// String holds details about calls to the String method.
 		String []struct {
 		}
+		// TransitionObject holds details about calls to the TransitionObject method.
+		TransitionObject []struct {
+			// ContextMoqParam is the contextMoqParam argument value.
+			ContextMoqParam context.Context
+			// Bucket is the bucket argument value.
+			Bucket string
+			// Object is the object argument value.
+			Object string
+			// VersionId is the versionId argument value.
+			VersionId string
+		}
 		// UploadPart holds details about calls to the UploadPart method.
 		UploadPart []struct {
 			// ContextMoqParam is the contextMoqParam argument value.
@@ -791,6 +890,7 @@ type BackendMock struct {
 	lockDeleteObject                  sync.RWMutex
 	lockDeleteObjectTagging           sync.RWMutex
 	lockDeleteObjects                 sync.RWMutex
+	lockDeletePublicAccessBlock       sync.RWMutex
 	lockGetBucketAcl                  sync.RWMutex
 	lockGetBucketCors                 sync.RWMutex
 	lockGetBucketOwnershipControls    sync.RWMutex
@@ -804,6 +904,7 @@ type BackendMock struct {
 	lockGetObjectLockConfiguration    sync.RWMutex
 	lockGetObjectRetention            sync.RWMutex
 	lockGetObjectTagging              sync.RWMutex
+	lockGetPublicAccessBlock          sync.RWMutex
 	lockHeadBucket                    sync.RWMutex
 	lockHeadObject                    sync.RWMutex
 	lockListBuckets                   sync.RWMutex
@@ -825,10 +926,15 @@ type BackendMock struct {
 	lockPutObjectLockConfiguration    sync.RWMutex
 	lockPutObjectRetention            sync.RWMutex
 	lockPutObjectTagging              sync.RWMutex
+	lockPutPublicAccessBlock          sync.RWMutex
 	lockRestoreObject                 sync.RWMutex
+	lockScrubBucket                   sync.RWMutex
 	lockSelectObjectContent           sync.RWMutex
 	lockShutdown                      sync.RWMutex
+	lockSnapshotBucket                sync.RWMutex
+	lockSnapshotBucketStatus          sync.RWMutex
 	lockString                        sync.RWMutex
+	lockTransitionObject              sync.RWMutex
 	lockUploadPart                    sync.RWMutex
 	lockUploadPartCopy                sync.RWMutex
 }
@@ -1353,6 +1459,42 @@ func (mock *BackendMock) DeleteObjectsCalls() []struct {
 	return calls
 }
 
+// DeletePublicAccessBlock calls DeletePublicAccessBlockFunc.
+func (mock *BackendMock) DeletePublicAccessBlock(contextMoqParam context.Context, bucket string) error {
+	if mock.DeletePublicAccessBlockFunc == nil {
+		panic("BackendMock.DeletePublicAccessBlockFunc: method is nil but Backend.DeletePublicAccessBlock was just called")
+	}
+	callInfo := struct {
+		ContextMoqParam context.Context
+		Bucket          string
+	}{
+		ContextMoqParam: contextMoqParam,
+		Bucket:          bucket,
+	}
+	mock.lockDeletePublicAccessBlock.Lock()
+	mock.calls.DeletePublicAccessBlock = append(mock.calls.DeletePublicAccessBlock, callInfo)
+	mock.lockDeletePublicAccessBlock.Unlock()
+	return mock.DeletePublicAccessBlockFunc(contextMoqParam, bucket)
+}
+
+// DeletePublicAccessBlockCalls gets all the calls that were made to DeletePublicAccessBlock.
+// Check the length with:
+//
+//	len(mockedBackend.DeletePublicAccessBlockCalls())
+func (mock *BackendMock) DeletePublicAccessBlockCalls() []struct {
+	ContextMoqParam context.Context
+	Bucket          string
+} {
+	var calls []struct {
+		ContextMoqParam context.Context
+		Bucket          string
+	}
+	mock.lockDeletePublicAccessBlock.RLock()
+	calls = mock.calls.DeletePublicAccessBlock
+	mock.lockDeletePublicAccessBlock.RUnlock()
+	return calls
+}
+
 // GetBucketAcl calls GetBucketAclFunc.
 func (mock *BackendMock) GetBucketAcl(contextMoqParam context.Context, getBucketAclInput *s3.GetBucketAclInput) ([]byte, error) {
 	if mock.GetBucketAclFunc == nil {
@@ -1845,6 +1987,42 @@ func (mock *BackendMock) GetObjectTaggingCalls() []struct {
 	return calls
 }
 
+// GetPublicAccessBlock calls GetPublicAccessBlockFunc.
+func (mock *BackendMock) GetPublicAccessBlock(contextMoqParam context.Context, bucket string) ([]byte, error) {
+	if mock.GetPublicAccessBlockFunc == nil {
+		panic("BackendMock.GetPublicAccessBlockFunc: method is nil but Backend.GetPublicAccessBlock was just called")
+	}
+	callInfo := struct {
+		ContextMoqParam context.Context
+		Bucket          string
+	}{
+		ContextMoqParam: contextMoqParam,
+		Bucket:          bucket,
+	}
+	mock.lockGetPublicAccessBlock.Lock()
+	mock.calls.GetPublicAccessBlock = append(mock.calls.GetPublicAccessBlock, callInfo)
+	mock.lockGetPublicAccessBlock.Unlock()
+	return mock.GetPublicAccessBlockFunc(contextMoqParam, bucket)
+}
+
+// GetPublicAccessBlockCalls gets all the calls that were made to GetPublicAccessBlock.
+// Check the length with:
+//
+//	len(mockedBackend.GetPublicAccessBlockCalls())
+func (mock *BackendMock) GetPublicAccessBlockCalls() []struct {
+	ContextMoqParam context.Context
+	Bucket          string
+} {
+	var calls []struct {
+		ContextMoqParam context.Context
+		Bucket          string
+	}
+	mock.lockGetPublicAccessBlock.RLock()
+	calls = mock.calls.GetPublicAccessBlock
+	mock.lockGetPublicAccessBlock.RUnlock()
+	return calls
+}
+
 // HeadBucket calls HeadBucketFunc.
 func (mock *BackendMock) HeadBucket(contextMoqParam context.Context, headBucketInput *s3.HeadBucketInput) (*s3.HeadBucketOutput, error) {
 	if mock.HeadBucketFunc == nil {
@@ -2661,6 +2839,46 @@ func (mock *BackendMock) PutObjectTaggingCalls() []struct {
 	return calls
 }
 
+// PutPublicAccessBlock calls PutPublicAccessBlockFunc.
+func (mock *BackendMock) PutPublicAccessBlock(contextMoqParam context.Context, bucket string, config []byte) error {
+	if mock.PutPublicAccessBlockFunc == nil {
+		panic("BackendMock.PutPublicAccessBlockFunc: method is nil but Backend.PutPublicAccessBlock was just called")
+	}
+	callInfo := struct {
+		ContextMoqParam context.Context
+		Bucket          string
+		Config          []byte
+	}{
+		ContextMoqParam: contextMoqParam,
+		Bucket:          bucket,
+		Config:          config,
+	}
+	mock.lockPutPublicAccessBlock.Lock()
+	mock.calls.PutPublicAccessBlock = append(mock.calls.PutPublicAccessBlock, callInfo)
+	mock.lockPutPublicAccessBlock.Unlock()
+	return mock.PutPublicAccessBlockFunc(contextMoqParam, bucket, config)
+}
+
+// PutPublicAccessBlockCalls gets all the calls that were made to PutPublicAccessBlock.
+// Check the length with:
+//
+//	len(mockedBackend.PutPublicAccessBlockCalls())
+func (mock *BackendMock) PutPublicAccessBlockCalls() []struct {
+	ContextMoqParam context.Context
+	Bucket          string
+	Config          []byte
+} {
+	var calls []struct {
+		ContextMoqParam context.Context
+		Bucket          string
+		Config          []byte
+	}
+	mock.lockPutPublicAccessBlock.RLock()
+	calls = mock.calls.PutPublicAccessBlock
+	mock.lockPutPublicAccessBlock.RUnlock()
+	return calls
+}
+
 // RestoreObject calls RestoreObjectFunc.
 func (mock *BackendMock) RestoreObject(contextMoqParam context.Context, restoreObjectInput *s3.RestoreObjectInput) error {
 	if mock.RestoreObjectFunc == nil {
@@ -2697,6 +2915,42 @@ func (mock *BackendMock) RestoreObjectCalls() []struct {
 	return calls
 }
 
+// ScrubBucket calls ScrubBucketFunc.
+func (mock *BackendMock) ScrubBucket(contextMoqParam context.Context, bucket string) (s3response.ScrubReport, error) {
+	if mock.ScrubBucketFunc == nil {
+		panic("BackendMock.ScrubBucketFunc: method is nil but Backend.ScrubBucket was just called")
+	}
+	callInfo := struct {
+		ContextMoqParam context.Context
+		Bucket          string
+	}{
+		ContextMoqParam: contextMoqParam,
+		Bucket:          bucket,
+	}
+	mock.lockScrubBucket.Lock()
+	mock.calls.ScrubBucket = append(mock.calls.ScrubBucket, callInfo)
+	mock.lockScrubBucket.Unlock()
+	return mock.ScrubBucketFunc(contextMoqParam, bucket)
+}
+
+// ScrubBucketCalls gets all the calls that were made to ScrubBucket.
+// Check the length with:
+//
+//	len(mockedBackend.ScrubBucketCalls())
+func (mock *BackendMock) ScrubBucketCalls() []struct {
+	ContextMoqParam context.Context
+	Bucket          string
+} {
+	var calls []struct {
+		ContextMoqParam context.Context
+		Bucket          string
+	}
+	mock.lockScrubBucket.RLock()
+	calls = mock.calls.ScrubBucket
+	mock.lockScrubBucket.RUnlock()
+	return calls
+}
+
 // SelectObjectContent calls SelectObjectContentFunc.
 func (mock *BackendMock) SelectObjectContent(ctx context.Context, input *s3.SelectObjectContentInput) func(w *bufio.Writer) {
 	if mock.SelectObjectContentFunc == nil {
@@ -2760,6 +3014,82 @@ func (mock *BackendMock) ShutdownCalls() []struct {
 	return calls
 }
 
+// SnapshotBucket calls SnapshotBucketFunc.
+func (mock *BackendMock) SnapshotBucket(contextMoqParam context.Context, bucket string, snapshot string) error {
+	if mock.SnapshotBucketFunc == nil {
+		panic("BackendMock.SnapshotBucketFunc: method is nil but Backend.SnapshotBucket was just called")
+	}
+	callInfo := struct {
+		ContextMoqParam context.Context
+		Bucket          string
+		Snapshot        string
+	}{
+		ContextMoqParam: contextMoqParam,
+		Bucket:          bucket,
+		Snapshot:        snapshot,
+	}
+	mock.lockSnapshotBucket.Lock()
+	mock.calls.SnapshotBucket = append(mock.calls.SnapshotBucket, callInfo)
+	mock.lockSnapshotBucket.Unlock()
+	return mock.SnapshotBucketFunc(contextMoqParam, bucket, snapshot)
+}
+
+// SnapshotBucketCalls gets all the calls that were made to SnapshotBucket.
+// Check the length with:
+//
+//	len(mockedBackend.SnapshotBucketCalls())
+func (mock *BackendMock) SnapshotBucketCalls() []struct {
+	ContextMoqParam context.Context
+	Bucket          string
+	Snapshot        string
+} {
+	var calls []struct {
+		ContextMoqParam context.Context
+		Bucket          string
+		Snapshot        string
+	}
+	mock.lockSnapshotBucket.RLock()
+	calls = mock.calls.SnapshotBucket
+	mock.lockSnapshotBucket.RUnlock()
+	return calls
+}
+
+// SnapshotBucketStatus calls SnapshotBucketStatusFunc.
+func (mock *BackendMock) SnapshotBucketStatus(contextMoqParam context.Context, snapshot string) (s3response.SnapshotStatus, error) {
+	if mock.SnapshotBucketStatusFunc == nil {
+		panic("BackendMock.SnapshotBucketStatusFunc: method is nil but Backend.SnapshotBucketStatus was just called")
+	}
+	callInfo := struct {
+		ContextMoqParam context.Context
+		Snapshot        string
+	}{
+		ContextMoqParam: contextMoqParam,
+		Snapshot:        snapshot,
+	}
+	mock.lockSnapshotBucketStatus.Lock()
+	mock.calls.SnapshotBucketStatus = append(mock.calls.SnapshotBucketStatus, callInfo)
+	mock.lockSnapshotBucketStatus.Unlock()
+	return mock.SnapshotBucketStatusFunc(contextMoqParam, snapshot)
+}
+
+// SnapshotBucketStatusCalls gets all the calls that were made to SnapshotBucketStatus.
+// Check the length with:
+//
+//	len(mockedBackend.SnapshotBucketStatusCalls())
+func (mock *BackendMock) SnapshotBucketStatusCalls() []struct {
+	ContextMoqParam context.Context
+	Snapshot        string
+} {
+	var calls []struct {
+		ContextMoqParam context.Context
+		Snapshot        string
+	}
+	mock.lockSnapshotBucketStatus.RLock()
+	calls = mock.calls.SnapshotBucketStatus
+	mock.lockSnapshotBucketStatus.RUnlock()
+	return calls
+}
+
 // String calls StringFunc.
 func (mock *BackendMock) String() string {
 	if mock.StringFunc == nil {
@@ -2787,6 +3117,50 @@ func (mock *BackendMock) StringCalls() []struct {
 	return calls
 }
 
+// TransitionObject calls TransitionObjectFunc.
+func (mock *BackendMock) TransitionObject(contextMoqParam context.Context, bucket string, object string, versionId string) error {
+	if mock.TransitionObjectFunc == nil {
+		panic("BackendMock.TransitionObjectFunc: method is nil but Backend.TransitionObject was just called")
+	}
+	callInfo := struct {
+		ContextMoqParam context.Context
+		Bucket          string
+		Object          string
+		VersionId       string
+	}{
+		ContextMoqParam: contextMoqParam,
+		Bucket:          bucket,
+		Object:          object,
+		VersionId:       versionId,
+	}
+	mock.lockTransitionObject.Lock()
+	mock.calls.TransitionObject = append(mock.calls.TransitionObject, callInfo)
+	mock.lockTransitionObject.Unlock()
+	return mock.TransitionObjectFunc(contextMoqParam, bucket, object, versionId)
+}
+
+// TransitionObjectCalls gets all the calls that were made to TransitionObject.
+// Check the length with:
+//
+//	len(mockedBackend.TransitionObjectCalls())
+func (mock *BackendMock) TransitionObjectCalls() []struct {
+	ContextMoqParam context.Context
+	Bucket          string
+	Object          string
+	VersionId       string
+} {
+	var calls []struct {
+		ContextMoqParam context.Context
+		Bucket          string
+		Object          string
+		VersionId       string
+	}
+	mock.lockTransitionObject.RLock()
+	calls = mock.calls.TransitionObject
+	mock.lockTransitionObject.RUnlock()
+	return calls
+}
+
 // UploadPart calls UploadPartFunc.
 func (mock *BackendMock) UploadPart(contextMoqParam context.Context, uploadPartInput *s3.UploadPartInput) (*s3.UploadPartOutput, error) {
 	if mock.UploadPartFunc == nil {
@@ -20,6 +20,7 @@ import (
 	"net/http"
 	"sort"
 	"strings"
+	"sync/atomic"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/versity/versitygw/auth"
@@ -38,7 +39,7 @@ type S3ApiController struct {
 	logger        s3log.AuditLogger
 	evSender      s3event.S3EventSender
 	mm            metrics.Manager
-	readonly      bool
+	readonly      *atomic.Bool
 	disableACL    bool
 	virtualDomain string
 }
@@ -60,7 +61,7 @@ var (
 	xmlhdr = []byte(`<?xml version="1.0" encoding="UTF-8"?>` + "\n")
 )
 
-func New(be backend.Backend, iam auth.IAMService, logger s3log.AuditLogger, evs s3event.S3EventSender, mm metrics.Manager, readonly, disableACL bool, virtualDomain string) S3ApiController {
+func New(be backend.Backend, iam auth.IAMService, logger s3log.AuditLogger, evs s3event.S3EventSender, mm metrics.Manager, readonly *atomic.Bool, disableACL bool, virtualDomain string) S3ApiController {
 	return S3ApiController{
 		be:            be,
 		iam:           iam,
@@ -73,6 +74,12 @@ func New(be backend.Backend, iam auth.IAMService, logger s3log.AuditLogger, evs
 	}
 }
 
+// isReadonly reports whether the gateway is in read-only mode,
+// the mode can be changed on a configuration reload
+func (c S3ApiController) isReadonly() bool {
+	return c.readonly != nil && c.readonly.Load()
+}
+
 func (c S3ApiController) getAclHeaderValue(ctx *fiber.Ctx, key string, defaultValues ...string) string {
 	if c.disableACL {
 		return ""
@@ -81,6 +88,17 @@ func (c S3ApiController) getAclHeaderValue(ctx *fiber.Ctx, key string, defaultVa
 	return ctx.Get(key, defaultValues...)
 }
 
+// requestTags parses the 'x-amz-tagging' header value for the bucket
+// policy conditions. The invalid tagging is left to the backend to
+// reject, so the parsing error is ignored here.
+func requestTags(tagging string) map[string]string {
+	tags, err := backend.ParseObjectTags(tagging)
+	if err != nil {
+		return nil
+	}
+	return tags
+}
+
 // Returns MethodNotAllowed for unmatched routes
 func (c S3ApiController) HandleErrorRoute(err error) Controller {
 	return func(ctx *fiber.Ctx) (*Response, error) {
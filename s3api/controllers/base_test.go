@@ -264,14 +264,16 @@ func (m *mockAuditLogger) Shutdown() error
 type mockEvSender struct {
 }
 
-func (m *mockEvSender) SendEvent(_ *fiber.Ctx, _ s3event.EventMeta) {}
-func (m *mockEvSender) Close() error                                { return nil }
+func (m *mockEvSender) SendEvent(_ *fiber.Ctx, _ s3event.EventMeta)      {}
+func (m *mockEvSender) SendSystemEvent(_, _ string, _ s3event.EventMeta) {}
+func (m *mockEvSender) Close() error                                     { return nil }
 
 // mock metrics manager
 
 type mockMetricsManager struct{}
 
 func (m *mockMetricsManager) Send(_ *fiber.Ctx, _ error, _ string, _ int64, _ int) {}
+func (m *mockMetricsManager) Add(_ string, _ int64, _ ...metrics.Tag)              {}
 func (m *mockMetricsManager) Close()                                               {}
 
 func TestProcessController(t *testing.T) {
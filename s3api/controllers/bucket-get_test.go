@@ -425,6 +425,108 @@ func TestS3ApiController_GetBucketCors(t *testing.T) {
 	}
 }
 
+func TestS3ApiController_GetPublicAccessBlock(t *testing.T) {
+	cfg := auth.PublicAccessBlockConfiguration{
+		BlockPublicAcls:       true,
+		RestrictPublicBuckets: true,
+	}
+	beRes, err := xml.Marshal(cfg)
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name   string
+		input  testInput
+		output testOutput
+	}{
+		{
+			name: "verify access fails",
+			input: testInput{
+				locals: accessDeniedLocals,
+			},
+			output: testOutput{
+				response: &Response{
+					MetaOpts: &MetaOptions{
+						BucketOwner: "root",
+					},
+				},
+				err: s3err.GetAPIError(s3err.ErrAccessDenied),
+			},
+		},
+		{
+			name: "backend returns error",
+			input: testInput{
+				locals: defaultLocals,
+				beRes:  []byte{},
+				beErr:  s3err.GetAPIError(s3err.ErrNoSuchPublicAccessBlockConfiguration),
+			},
+			output: testOutput{
+				response: &Response{
+					MetaOpts: &MetaOptions{
+						BucketOwner: "root",
+					},
+				},
+				err: s3err.GetAPIError(s3err.ErrNoSuchPublicAccessBlockConfiguration),
+			},
+		},
+		{
+			name: "invalid data from backend",
+			input: testInput{
+				locals: defaultLocals,
+				beRes:  []byte("invalid_data"),
+			},
+			output: testOutput{
+				response: &Response{
+					Data: auth.PublicAccessBlockConfiguration{},
+					MetaOpts: &MetaOptions{
+						BucketOwner: "root",
+					},
+				},
+				err: errors.New("parse public access block:"),
+			},
+		},
+		{
+			name: "successful response",
+			input: testInput{
+				locals: defaultLocals,
+				beRes:  beRes,
+			},
+			output: testOutput{
+				response: &Response{
+					Data: cfg,
+					MetaOpts: &MetaOptions{
+						BucketOwner: "root",
+					},
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			be := &BackendMock{
+				GetPublicAccessBlockFunc: func(contextMoqParam context.Context, bucket string) ([]byte, error) {
+					return tt.input.beRes.([]byte), tt.input.beErr
+				},
+				GetBucketPolicyFunc: func(contextMoqParam context.Context, bucket string) ([]byte, error) {
+					return nil, s3err.GetAPIError(s3err.ErrAccessDenied)
+				},
+			}
+
+			ctrl := S3ApiController{
+				be: be,
+			}
+
+			testController(
+				t,
+				ctrl.GetPublicAccessBlock,
+				tt.output.response,
+				tt.output.err,
+				ctxInputs{
+					locals: tt.input.locals,
+				})
+		})
+	}
+}
+
 func TestS3ApiController_GetBucketPolicy(t *testing.T) {
 	tests := []struct {
 		name   string
@@ -521,6 +623,7 @@ func TestS3ApiController_GetBucketPolicyStatus(t *testing.T) {
 	}
 	`
 	isPublic := true
+	isNotPublic := false
 
 	tests := []struct {
 		name   string
@@ -595,6 +698,24 @@ func TestS3ApiController_GetBucketPolicyStatus(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "restricted by public access block",
+			input: testInput{
+				locals:        defaultLocals,
+				beRes:         []byte(mockResp),
+				extraMockResp: []byte("<PublicAccessBlockConfiguration><RestrictPublicBuckets>true</RestrictPublicBuckets></PublicAccessBlockConfiguration>"),
+			},
+			output: testOutput{
+				response: &Response{
+					Data: types.PolicyStatus{
+						IsPublic: &isNotPublic,
+					},
+					MetaOpts: &MetaOptions{
+						BucketOwner: "root",
+					},
+				},
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -602,6 +723,12 @@ func TestS3ApiController_GetBucketPolicyStatus(t *testing.T) {
 				GetBucketPolicyFunc: func(contextMoqParam context.Context, bucket string) ([]byte, error) {
 					return tt.input.beRes.([]byte), tt.input.beErr
 				},
+				GetPublicAccessBlockFunc: func(contextMoqParam context.Context, bucket string) ([]byte, error) {
+					if tt.input.extraMockResp == nil {
+						return nil, s3err.GetAPIError(s3err.ErrNoSuchPublicAccessBlockConfiguration)
+					}
+					return tt.input.extraMockResp.([]byte), nil
+				},
 			}
 
 			ctrl := S3ApiController{
@@ -34,7 +34,7 @@ func (c S3ApiController) HeadBucket(ctx *fiber.Ctx) (*Response, error) {
 
 	err := auth.VerifyAccess(ctx.Context(), c.be,
 		auth.AccessOptions{
-			Readonly:        c.readonly,
+			Readonly:        c.isReadonly(),
 			Acl:             parsedAcl,
 			AclPermission:   auth.PermissionRead,
 			IsRoot:          isRoot,
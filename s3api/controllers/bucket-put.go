@@ -39,7 +39,7 @@ func (c S3ApiController) PutBucketTagging(ctx *fiber.Ctx) (*Response, error) {
 	isPublicBucket := utils.ContextKeyPublicBucket.IsSet(ctx)
 
 	err := auth.VerifyAccess(ctx.Context(), c.be, auth.AccessOptions{
-		Readonly:        c.readonly,
+		Readonly:        c.isReadonly(),
 		Acl:             parsedAcl,
 		AclPermission:   auth.PermissionWrite,
 		IsRoot:          isRoot,
@@ -82,7 +82,7 @@ func (c S3ApiController) PutBucketOwnershipControls(ctx *fiber.Ctx) (*Response,
 	isRoot := utils.ContextKeyIsRoot.Get(ctx).(bool)
 
 	if err := auth.VerifyAccess(ctx.Context(), c.be, auth.AccessOptions{
-		Readonly:      c.readonly,
+		Readonly:      c.isReadonly(),
 		Acl:           parsedAcl,
 		AclPermission: auth.PermissionWrite,
 		IsRoot:        isRoot,
@@ -129,6 +129,57 @@ func (c S3ApiController) PutBucketOwnershipControls(ctx *fiber.Ctx) (*Response,
 	}, err
 }
 
+func (c S3ApiController) PutPublicAccessBlock(ctx *fiber.Ctx) (*Response, error) {
+	bucket := ctx.Params("bucket")
+	parsedAcl := utils.ContextKeyParsedAcl.Get(ctx).(auth.ACL)
+	acct := utils.ContextKeyAccount.Get(ctx).(auth.Account)
+	isRoot := utils.ContextKeyIsRoot.Get(ctx).(bool)
+
+	if err := auth.VerifyAccess(ctx.Context(), c.be, auth.AccessOptions{
+		Readonly:      c.isReadonly(),
+		Acl:           parsedAcl,
+		AclPermission: auth.PermissionWrite,
+		IsRoot:        isRoot,
+		Acc:           acct,
+		Bucket:        bucket,
+		Action:        auth.PutBucketPublicAccessBlockAction,
+		DisableACL:    c.disableACL,
+	}); err != nil {
+		return &Response{
+			MetaOpts: &MetaOptions{
+				BucketOwner: parsedAcl.Owner,
+			},
+		}, err
+	}
+
+	var cfg auth.PublicAccessBlockConfiguration
+	if err := xml.Unmarshal(ctx.Body(), &cfg); err != nil {
+		debuglogger.Logf("failed to unmarshal request body: %v", err)
+		return &Response{
+			MetaOpts: &MetaOptions{
+				BucketOwner: parsedAcl.Owner,
+			},
+		}, s3err.GetAPIError(s3err.ErrMalformedXML)
+	}
+
+	// store the parsed settings, not the request body
+	data, err := xml.Marshal(cfg)
+	if err != nil {
+		return &Response{
+			MetaOpts: &MetaOptions{
+				BucketOwner: parsedAcl.Owner,
+			},
+		}, err
+	}
+
+	err = c.be.PutPublicAccessBlock(ctx.Context(), bucket, data)
+	return &Response{
+		MetaOpts: &MetaOptions{
+			BucketOwner: parsedAcl.Owner,
+		},
+	}, err
+}
+
 func (c S3ApiController) PutBucketVersioning(ctx *fiber.Ctx) (*Response, error) {
 	bucket := ctx.Params("bucket")
 	parsedAcl := utils.ContextKeyParsedAcl.Get(ctx).(auth.ACL)
@@ -137,7 +188,7 @@ func (c S3ApiController) PutBucketVersioning(ctx *fiber.Ctx) (*Response, error)
 	isPublicBucket := utils.ContextKeyPublicBucket.IsSet(ctx)
 
 	err := auth.VerifyAccess(ctx.Context(), c.be, auth.AccessOptions{
-		Readonly:        c.readonly,
+		Readonly:        c.isReadonly(),
 		Acl:             parsedAcl,
 		AclPermission:   auth.PermissionWrite,
 		IsRoot:          isRoot,
@@ -192,7 +243,7 @@ func (c S3ApiController) PutObjectLockConfiguration(ctx *fiber.Ctx) (*Response,
 	isPublicBucket := utils.ContextKeyPublicBucket.IsSet(ctx)
 
 	if err := auth.VerifyAccess(ctx.Context(), c.be, auth.AccessOptions{
-		Readonly:        c.readonly,
+		Readonly:        c.isReadonly(),
 		Acl:             parsedAcl,
 		AclPermission:   auth.PermissionWrite,
 		IsRoot:          isRoot,
@@ -234,7 +285,7 @@ func (c S3ApiController) PutBucketCors(ctx *fiber.Ctx) (*Response, error) {
 	isPublicBucket := utils.ContextKeyPublicBucket.IsSet(ctx)
 
 	err := auth.VerifyAccess(ctx.Context(), c.be, auth.AccessOptions{
-		Readonly:        c.readonly,
+		Readonly:        c.isReadonly(),
 		Acl:             parsedAcl,
 		AclPermission:   auth.PermissionWrite,
 		IsRoot:          isRoot,
@@ -290,7 +341,7 @@ func (c S3ApiController) PutBucketPolicy(ctx *fiber.Ctx) (*Response, error) {
 	isRoot := utils.ContextKeyIsRoot.Get(ctx).(bool)
 
 	err := auth.VerifyAccess(ctx.Context(), c.be, auth.AccessOptions{
-		Readonly:      c.readonly,
+		Readonly:      c.isReadonly(),
 		Acl:           parsedAcl,
 		AclPermission: auth.PermissionWrite,
 		IsRoot:        isRoot,
@@ -316,6 +367,23 @@ func (c S3ApiController) PutBucketPolicy(ctx *fiber.Ctx) (*Response, error) {
 		}, err
 	}
 
+	pab, err := auth.GetPublicAccessBlock(ctx.Context(), c.be, bucket)
+	if err != nil {
+		return &Response{
+			MetaOpts: &MetaOptions{
+				BucketOwner: parsedAcl.Owner,
+			},
+		}, err
+	}
+	err = pab.VerifyPolicy(ctx.Body())
+	if err != nil {
+		return &Response{
+			MetaOpts: &MetaOptions{
+				BucketOwner: parsedAcl.Owner,
+			},
+		}, err
+	}
+
 	err = c.be.PutBucketPolicy(ctx.Context(), bucket, ctx.Body())
 	return &Response{
 		MetaOpts: &MetaOptions{
@@ -343,7 +411,7 @@ func (c S3ApiController) PutBucketAcl(ctx *fiber.Ctx) (*Response, error) {
 
 	err := auth.VerifyAccess(ctx.Context(), c.be,
 		auth.AccessOptions{
-			Readonly:      c.readonly,
+			Readonly:      c.isReadonly(),
 			Acl:           parsedAcl,
 			AclPermission: auth.PermissionWriteAcp,
 			IsRoot:        isRoot,
@@ -486,6 +554,23 @@ func (c S3ApiController) PutBucketAcl(ctx *fiber.Ctx) (*Response, error) {
 		}, err
 	}
 
+	pab, err := auth.GetPublicAccessBlock(ctx.Context(), c.be, bucket)
+	if err != nil {
+		return &Response{
+			MetaOpts: &MetaOptions{
+				BucketOwner: parsedAcl.Owner,
+			},
+		}, err
+	}
+	err = pab.VerifyACL(updAcl)
+	if err != nil {
+		return &Response{
+			MetaOpts: &MetaOptions{
+				BucketOwner: parsedAcl.Owner,
+			},
+		}, err
+	}
+
 	err = c.be.PutBucketAcl(ctx.Context(), bucket, updAcl)
 	return &Response{
 		MetaOpts: &MetaOptions{
@@ -506,7 +591,7 @@ func (c S3ApiController) CreateBucket(ctx *fiber.Ctx) (*Response, error) {
 	grants := grantFullControl + grantRead + grantReadACP + grantWrite + grantWriteACP
 	objectOwnership := types.ObjectOwnership(ctx.Get("X-Amz-Object-Ownership"))
 
-	if c.readonly {
+	if c.isReadonly() {
 		return &Response{
 			MetaOpts: &MetaOptions{},
 		}, s3err.GetAPIError(s3err.ErrAccessDenied)
@@ -640,6 +725,16 @@ func (c S3ApiController) CreateBucket(ctx *fiber.Ctx) (*Response, error) {
 		}, err
 	}
 
+	// the new buckets only have the gateway wide public access block settings
+	err = auth.DefaultPublicAccessBlock().VerifyACL(updAcl)
+	if err != nil {
+		return &Response{
+			MetaOpts: &MetaOptions{
+				BucketOwner: bucketOwner.Access,
+			},
+		}, err
+	}
+
 	err = c.be.CreateBucket(ctx.Context(), &s3.CreateBucketInput{
 		Bucket:                     &bucket,
 		ObjectOwnership:            objectOwnership,
@@ -582,6 +582,98 @@ func TestS3ApiController_PutBucketCors(t *testing.T) {
 	}
 }
 
+func TestS3ApiController_PutPublicAccessBlock(t *testing.T) {
+	validBody, err := xml.Marshal(auth.PublicAccessBlockConfiguration{
+		BlockPublicAcls:   true,
+		BlockPublicPolicy: true,
+	})
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name   string
+		input  testInput
+		output testOutput
+	}{
+		{
+			name: "verify access fails",
+			input: testInput{
+				locals: accessDeniedLocals,
+			},
+			output: testOutput{
+				response: &Response{
+					MetaOpts: &MetaOptions{
+						BucketOwner: "root",
+					},
+				},
+				err: s3err.GetAPIError(s3err.ErrAccessDenied),
+			},
+		},
+		{
+			name: "invalid request body",
+			input: testInput{
+				locals: defaultLocals,
+				body:   []byte("invalid_body"),
+			},
+			output: testOutput{
+				response: &Response{
+					MetaOpts: &MetaOptions{BucketOwner: "root"},
+				},
+				err: s3err.GetAPIError(s3err.ErrMalformedXML),
+			},
+		},
+		{
+			name: "backend error",
+			input: testInput{
+				locals: defaultLocals,
+				beErr:  s3err.GetAPIError(s3err.ErrNotImplemented),
+				body:   validBody,
+			},
+			output: testOutput{
+				response: &Response{
+					MetaOpts: &MetaOptions{BucketOwner: "root"},
+				},
+				err: s3err.GetAPIError(s3err.ErrNotImplemented),
+			},
+		},
+		{
+			name: "success",
+			input: testInput{
+				locals: defaultLocals,
+				body:   validBody,
+			},
+			output: testOutput{
+				response: &Response{
+					MetaOpts: &MetaOptions{
+						BucketOwner: "root",
+					},
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			be := &BackendMock{
+				PutPublicAccessBlockFunc: func(contextMoqParam context.Context, bucket string, config []byte) error {
+					return tt.input.beErr
+				},
+				GetBucketPolicyFunc: func(contextMoqParam context.Context, bucket string) ([]byte, error) {
+					return nil, s3err.GetAPIError(s3err.ErrAccessDenied)
+				},
+			}
+
+			ctrl := S3ApiController{
+				be: be,
+			}
+
+			testController(t, ctrl.PutPublicAccessBlock, tt.output.response, tt.output.err, ctxInputs{
+				locals: tt.input.locals,
+				body:   tt.input.body,
+			})
+		})
+	}
+}
+
 func TestS3ApiController_PutBucketPolicy(t *testing.T) {
 	validPolicyDocument :=
 		`{
@@ -649,6 +741,22 @@ func TestS3ApiController_PutBucketPolicy(t *testing.T) {
 				err: s3err.GetAPIError(s3err.ErrNoSuchBucket),
 			},
 		},
+		{
+			name: "public policy blocked",
+			input: testInput{
+				locals:        defaultLocals,
+				body:          []byte(validPolicyDocument),
+				extraMockResp: []byte("<PublicAccessBlockConfiguration><BlockPublicPolicy>true</BlockPublicPolicy></PublicAccessBlockConfiguration>"),
+			},
+			output: testOutput{
+				response: &Response{
+					MetaOpts: &MetaOptions{
+						BucketOwner: "root",
+					},
+				},
+				err: s3err.GetAPIError(s3err.ErrAccessDenied),
+			},
+		},
 		{
 			name: "success",
 			input: testInput{
@@ -675,6 +783,12 @@ func TestS3ApiController_PutBucketPolicy(t *testing.T) {
 				GetBucketPolicyFunc: func(contextMoqParam context.Context, bucket string) ([]byte, error) {
 					return nil, s3err.GetAPIError(s3err.ErrAccessDenied)
 				},
+				GetPublicAccessBlockFunc: func(contextMoqParam context.Context, bucket string) ([]byte, error) {
+					if tt.input.extraMockResp == nil {
+						return nil, s3err.GetAPIError(s3err.ErrNoSuchPublicAccessBlockConfiguration)
+					}
+					return tt.input.extraMockResp.([]byte), nil
+				},
 			}
 
 			ctrl := S3ApiController{
@@ -1196,6 +1310,9 @@ func TestS3ApiController_PutBucketAcl(t *testing.T) {
 				GetBucketPolicyFunc: func(contextMoqParam context.Context, bucket string) ([]byte, error) {
 					return nil, s3err.GetAPIError(s3err.ErrAccessDenied)
 				},
+				GetPublicAccessBlockFunc: func(contextMoqParam context.Context, bucket string) ([]byte, error) {
+					return nil, s3err.GetAPIError(s3err.ErrNoSuchPublicAccessBlockConfiguration)
+				},
 			}
 
 			ctrl := S3ApiController{
@@ -43,7 +43,7 @@ func (c S3ApiController) DeleteObjectTagging(ctx *fiber.Ctx) (*Response, error)
 
 	err := auth.VerifyAccess(ctx.Context(), c.be,
 		auth.AccessOptions{
-			Readonly:        c.readonly,
+			Readonly:        c.isReadonly(),
 			Acl:             parsedAcl,
 			AclPermission:   auth.PermissionWrite,
 			IsRoot:          isRoot,
@@ -96,7 +96,7 @@ func (c S3ApiController) AbortMultipartUpload(ctx *fiber.Ctx) (*Response, error)
 
 	err := auth.VerifyAccess(ctx.Context(), c.be,
 		auth.AccessOptions{
-			Readonly:        c.readonly,
+			Readonly:        c.isReadonly(),
 			Acl:             parsedAcl,
 			AclPermission:   auth.PermissionWrite,
 			IsRoot:          isRoot,
@@ -151,7 +151,7 @@ func (c S3ApiController) DeleteObject(ctx *fiber.Ctx) (*Response, error) {
 
 	err := auth.VerifyAccess(ctx.Context(), c.be,
 		auth.AccessOptions{
-			Readonly:        c.readonly,
+			Readonly:        c.isReadonly(),
 			Acl:             parsedAcl,
 			AclPermission:   auth.PermissionWrite,
 			IsRoot:          isRoot,
@@ -182,7 +182,7 @@ func (c S3ApiController) DeleteObject(ctx *fiber.Ctx) (*Response, error) {
 	err = auth.CheckObjectAccess(
 		ctx.Context(),
 		bucket,
-		acct.Access,
+		acct,
 		[]types.ObjectIdentifier{
 			{
 				Key:       &key,
@@ -204,12 +204,13 @@ func (c S3ApiController) DeleteObject(ctx *fiber.Ctx) (*Response, error) {
 
 	res, err := c.be.DeleteObject(ctx.Context(),
 		&s3.DeleteObjectInput{
-			Bucket:                  &bucket,
-			Key:                     &key,
-			VersionId:               &versionId,
-			IfMatch:                 ifMatch,
-			IfMatchLastModifiedTime: ifMatchLastModTime,
-			IfMatchSize:             ifMatchSize,
+			Bucket:                    &bucket,
+			Key:                       &key,
+			VersionId:                 &versionId,
+			IfMatch:                   ifMatch,
+			IfMatchLastModifiedTime:   ifMatchLastModTime,
+			IfMatchSize:               ifMatchSize,
+			BypassGovernanceRetention: &bypass,
 		})
 	if err != nil {
 		return &Response{
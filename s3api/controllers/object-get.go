@@ -18,6 +18,7 @@ import (
 	"fmt"
 	"math"
 	"net/http"
+	"strconv"
 	"strings"
 	"time"
 
@@ -46,7 +47,7 @@ func (c S3ApiController) GetObjectTagging(ctx *fiber.Ctx) (*Response, error) {
 	}
 
 	err := auth.VerifyAccess(ctx.Context(), c.be, auth.AccessOptions{
-		Readonly:        c.readonly,
+		Readonly:        c.isReadonly(),
 		Acl:             parsedAcl,
 		AclPermission:   auth.PermissionRead,
 		IsRoot:          isRoot,
@@ -113,7 +114,7 @@ func (c S3ApiController) GetObjectRetention(ctx *fiber.Ctx) (*Response, error) {
 	isPublicBucket := utils.ContextKeyPublicBucket.IsSet(ctx)
 
 	err := auth.VerifyAccess(ctx.Context(), c.be, auth.AccessOptions{
-		Readonly:        c.readonly,
+		Readonly:        c.isReadonly(),
 		Acl:             parsedAcl,
 		AclPermission:   auth.PermissionRead,
 		IsRoot:          isRoot,
@@ -170,7 +171,7 @@ func (c S3ApiController) GetObjectLegalHold(ctx *fiber.Ctx) (*Response, error) {
 	isPublicBucket := utils.ContextKeyPublicBucket.IsSet(ctx)
 
 	err := auth.VerifyAccess(ctx.Context(), c.be, auth.AccessOptions{
-		Readonly:        c.readonly,
+		Readonly:        c.isReadonly(),
 		Acl:             parsedAcl,
 		AclPermission:   auth.PermissionRead,
 		IsRoot:          isRoot,
@@ -217,7 +218,7 @@ func (c S3ApiController) GetObjectAcl(ctx *fiber.Ctx) (*Response, error) {
 	isPublicBucket := utils.ContextKeyPublicBucket.IsSet(ctx)
 
 	err := auth.VerifyAccess(ctx.Context(), c.be, auth.AccessOptions{
-		Readonly:        c.readonly,
+		Readonly:        c.isReadonly(),
 		Acl:             parsedAcl,
 		AclPermission:   auth.PermissionReadAcp,
 		IsRoot:          isRoot,
@@ -260,7 +261,7 @@ func (c S3ApiController) ListParts(ctx *fiber.Ctx) (*Response, error) {
 	isPublicBucket := utils.ContextKeyPublicBucket.IsSet(ctx)
 
 	err := auth.VerifyAccess(ctx.Context(), c.be, auth.AccessOptions{
-		Readonly:        c.readonly,
+		Readonly:        c.isReadonly(),
 		Acl:             parsedAcl,
 		AclPermission:   auth.PermissionRead,
 		IsRoot:          isRoot,
@@ -332,7 +333,7 @@ func (c S3ApiController) GetObjectAttributes(ctx *fiber.Ctx) (*Response, error)
 	}
 
 	err := auth.VerifyAccess(ctx.Context(), c.be, auth.AccessOptions{
-		Readonly:        c.readonly,
+		Readonly:        c.isReadonly(),
 		Acl:             parsedAcl,
 		AclPermission:   auth.PermissionRead,
 		IsRoot:          isRoot,
@@ -407,6 +408,13 @@ func (c S3ApiController) GetObjectAttributes(ctx *fiber.Ctx) (*Response, error)
 	if res.DeleteMarker != nil && *res.DeleteMarker {
 		headers["x-amz-delete-marker"] = utils.GetStringPtr("true")
 	}
+	if res.Layout != nil {
+		headers["x-vgw-stripe-count"] = utils.GetStringPtr(strconv.Itoa(res.Layout.StripeCount))
+		headers["x-vgw-stripe-size"] = utils.GetStringPtr(strconv.FormatInt(res.Layout.StripeSize, 10))
+		if res.Layout.Pool != "" {
+			headers["x-vgw-pool"] = &res.Layout.Pool
+		}
+	}
 
 	return &Response{
 		Headers: headers,
@@ -466,7 +474,7 @@ func (c S3ApiController) GetObject(ctx *fiber.Ctx) (*Response, error) {
 	}
 
 	err := auth.VerifyAccess(ctx.Context(), c.be, auth.AccessOptions{
-		Readonly:        c.readonly,
+		Readonly:        c.isReadonly(),
 		Acl:             parsedAcl,
 		AclPermission:   auth.PermissionRead,
 		IsRoot:          isRoot,
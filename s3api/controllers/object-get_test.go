@@ -700,6 +700,41 @@ func TestS3ApiController_GetObjectAttributes(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "successful response with layout",
+			input: testInput{
+				locals: defaultLocals,
+				beRes: s3response.GetObjectAttributesResponse{
+					LastModified: &lastModTime,
+					ETag:         &etag,
+					Layout: &s3response.ObjectLayout{
+						StripeCount: 4,
+						StripeSize:  1048576,
+						Pool:        "flash",
+					},
+				},
+				headers: map[string]string{
+					"X-Amz-Object-Attributes": "ETag",
+				},
+			},
+			output: testOutput{
+				response: &Response{
+					Headers: map[string]*string{
+						"x-amz-version-id":   nil,
+						"Last-Modified":      &timeFormatted,
+						"x-vgw-stripe-count": utils.GetStringPtr("4"),
+						"x-vgw-stripe-size":  utils.GetStringPtr("1048576"),
+						"x-vgw-pool":         utils.GetStringPtr("flash"),
+					},
+					Data: s3response.GetObjectAttributesResponse{
+						ETag: &etag,
+					},
+					MetaOpts: &MetaOptions{
+						BucketOwner: "root",
+					},
+				},
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
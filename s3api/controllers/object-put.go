@@ -16,6 +16,7 @@ package controllers
 
 import (
 	"bytes"
+	"context"
 	"encoding/xml"
 	"fmt"
 	"io"
@@ -26,6 +27,7 @@ import (
 	"github.com/aws/aws-sdk-go-v2/service/s3/types"
 	"github.com/gofiber/fiber/v2"
 	"github.com/versity/versitygw/auth"
+	"github.com/versity/versitygw/backend"
 	"github.com/versity/versitygw/debuglogger"
 	"github.com/versity/versitygw/s3api/utils"
 	"github.com/versity/versitygw/s3err"
@@ -47,8 +49,13 @@ func (c S3ApiController) PutObjectTagging(ctx *fiber.Ctx) (*Response, error) {
 		action = auth.PutObjectVersionTaggingAction
 	}
 
+	// the new tag set is parsed before the access check to be
+	// evaluated by the bucket policy conditions, the parsing
+	// error is returned only if the access is granted
+	tagging, tagErr := utils.ParseTagging(ctx.Body(), utils.TagLimitObject)
+
 	err := auth.VerifyAccess(ctx.Context(), c.be, auth.AccessOptions{
-		Readonly:        c.readonly,
+		Readonly:        c.isReadonly(),
 		Acl:             parsedAcl,
 		AclPermission:   auth.PermissionWrite,
 		IsRoot:          isRoot,
@@ -58,6 +65,7 @@ func (c S3ApiController) PutObjectTagging(ctx *fiber.Ctx) (*Response, error) {
 		Action:          action,
 		IsPublicRequest: IsBucketPublic,
 		DisableACL:      c.disableACL,
+		RequestTags:     tagging,
 	})
 	if err != nil {
 		return &Response{
@@ -76,13 +84,12 @@ func (c S3ApiController) PutObjectTagging(ctx *fiber.Ctx) (*Response, error) {
 		}, err
 	}
 
-	tagging, err := utils.ParseTagging(ctx.Body(), utils.TagLimitObject)
-	if err != nil {
+	if tagErr != nil {
 		return &Response{
 			MetaOpts: &MetaOptions{
 				BucketOwner: parsedAcl.Owner,
 			},
-		}, err
+		}, tagErr
 	}
 
 	err = c.be.PutObjectTagging(ctx.Context(), bucket, key, versionId, tagging)
@@ -108,7 +115,7 @@ func (c S3ApiController) PutObjectRetention(ctx *fiber.Ctx) (*Response, error) {
 	parsedAcl := utils.ContextKeyParsedAcl.Get(ctx).(auth.ACL)
 
 	err := auth.VerifyAccess(ctx.Context(), c.be, auth.AccessOptions{
-		Readonly:        c.readonly,
+		Readonly:        c.isReadonly(),
 		Acl:             parsedAcl,
 		AclPermission:   auth.PermissionWrite,
 		IsRoot:          isRoot,
@@ -147,7 +154,7 @@ func (c S3ApiController) PutObjectRetention(ctx *fiber.Ctx) (*Response, error) {
 	}
 
 	// check if the operation is allowed
-	err = auth.IsObjectLockRetentionPutAllowed(ctx.Context(), c.be, bucket, key, versionId, acct.Access, retention, bypass)
+	err = auth.IsObjectLockRetentionPutAllowed(ctx.Context(), c.be, bucket, key, versionId, acct, retention, bypass)
 	if err != nil {
 		return &Response{
 			MetaOpts: &MetaOptions{
@@ -166,7 +173,14 @@ func (c S3ApiController) PutObjectRetention(ctx *fiber.Ctx) (*Response, error) {
 		}, err
 	}
 
-	err = c.be.PutObjectRetention(ctx.Context(), bucket, key, versionId, data)
+	beCtx := context.Context(ctx.Context())
+	if bypass {
+		// let the backends forwarding the request know
+		// that the governance retention can be bypassed
+		beCtx = backend.WithBypassGovernance(beCtx)
+	}
+
+	err = c.be.PutObjectRetention(beCtx, bucket, key, versionId, data)
 	return &Response{
 		MetaOpts: &MetaOptions{
 			BucketOwner: parsedAcl.Owner,
@@ -184,7 +198,7 @@ func (c S3ApiController) PutObjectLegalHold(ctx *fiber.Ctx) (*Response, error) {
 	parsedAcl := utils.ContextKeyParsedAcl.Get(ctx).(auth.ACL)
 
 	err := auth.VerifyAccess(ctx.Context(), c.be, auth.AccessOptions{
-		Readonly:        c.readonly,
+		Readonly:        c.isReadonly(),
 		Acl:             parsedAcl,
 		AclPermission:   auth.PermissionWrite,
 		IsRoot:          isRoot,
@@ -263,7 +277,7 @@ func (c S3ApiController) UploadPart(ctx *fiber.Ctx) (*Response, error) {
 
 	err := auth.VerifyAccess(ctx.Context(), c.be,
 		auth.AccessOptions{
-			Readonly:        c.readonly,
+			Readonly:        c.isReadonly(),
 			Acl:             parsedAcl,
 			AclPermission:   auth.PermissionWrite,
 			IsRoot:          isRoot,
@@ -461,7 +475,7 @@ func (c S3ApiController) PutObjectAcl(ctx *fiber.Ctx) (*Response, error) {
 
 	err := auth.VerifyAccess(ctx.Context(), c.be,
 		auth.AccessOptions{
-			Readonly:      c.readonly,
+			Readonly:      c.isReadonly(),
 			Acl:           parsedAcl,
 			AclPermission: auth.PermissionWrite,
 			IsRoot:        isRoot,
@@ -478,6 +492,19 @@ func (c S3ApiController) PutObjectAcl(ctx *fiber.Ctx) (*Response, error) {
 		}, err
 	}
 
+	// the backends store the grant headers, so the email grantees
+	// are resolved to the granted accounts beforehand
+	for _, grant := range []*string{&grantFullControl, &grantRead, &grantReadACP, &grantWrite, &grantWriteACP} {
+		*grant, err = auth.ResolveGrantHeader(*grant, c.iam)
+		if err != nil {
+			return &Response{
+				MetaOpts: &MetaOptions{
+					BucketOwner: parsedAcl.Owner,
+				},
+			}, err
+		}
+	}
+
 	err = c.be.PutObjectAcl(ctx.Context(), &s3.PutObjectAclInput{
 		Bucket:           &bucket,
 		Key:              &key,
@@ -533,6 +560,7 @@ func (c S3ApiController) CopyObject(ctx *fiber.Ctx) (*Response, error) {
 			Bucket:        bucket,
 			Object:        key,
 			Action:        auth.PutObjectAction,
+			RequestTags:   requestTags(tagging),
 		})
 	if err != nil {
 		return &Response{
@@ -599,7 +627,7 @@ func (c S3ApiController) CopyObject(ctx *fiber.Ctx) (*Response, error) {
 
 	preconditionHdrs := utils.ParsePreconditionHeaders(ctx, utils.WithCopySource())
 
-	err = auth.CheckObjectAccess(ctx.Context(), bucket, acct.Access, []types.ObjectIdentifier{{Key: &key}}, true, false, c.be, true)
+	err = auth.CheckObjectAccess(ctx.Context(), bucket, acct, []types.ObjectIdentifier{{Key: &key}}, true, false, c.be, true)
 	if err != nil {
 		return &Response{
 			MetaOpts: &MetaOptions{
@@ -685,7 +713,7 @@ func (c S3ApiController) PutObject(ctx *fiber.Ctx) (*Response, error) {
 
 	err := auth.VerifyAccess(ctx.Context(), c.be,
 		auth.AccessOptions{
-			Readonly:        c.readonly,
+			Readonly:        c.isReadonly(),
 			Acl:             parsedAcl,
 			AclPermission:   auth.PermissionWrite,
 			IsRoot:          isRoot,
@@ -695,6 +723,7 @@ func (c S3ApiController) PutObject(ctx *fiber.Ctx) (*Response, error) {
 			Action:          auth.PutObjectAction,
 			IsPublicRequest: IsBucketPublic,
 			DisableACL:      c.disableACL,
+			RequestTags:     requestTags(tagging),
 		})
 	if err != nil {
 		return &Response{
@@ -714,7 +743,7 @@ func (c S3ApiController) PutObject(ctx *fiber.Ctx) (*Response, error) {
 		}, err
 	}
 
-	err = auth.CheckObjectAccess(ctx.Context(), bucket, acct.Access, []types.ObjectIdentifier{{Key: &key}}, true, IsBucketPublic, c.be, true)
+	err = auth.CheckObjectAccess(ctx.Context(), bucket, acct, []types.ObjectIdentifier{{Key: &key}}, true, IsBucketPublic, c.be, true)
 	if err != nil {
 		return &Response{
 			MetaOpts: &MetaOptions{
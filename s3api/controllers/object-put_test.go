@@ -832,6 +832,40 @@ func TestS3ApiController_PutObjectAcl(t *testing.T) {
 				err: s3err.GetAPIError(s3err.ErrAccessDenied),
 			},
 		},
+		{
+			name: "unresolvable email grantee",
+			input: testInput{
+				locals: defaultLocals,
+				headers: map[string]string{
+					"X-Amz-Grant-Read": `emailAddress="nobody@example.com"`,
+				},
+			},
+			output: testOutput{
+				response: &Response{
+					MetaOpts: &MetaOptions{
+						BucketOwner: "root",
+					},
+				},
+				err: s3err.GetAPIError(s3err.ErrUnresolvableGrantByEmailAddress),
+			},
+		},
+		{
+			name: "email grantee",
+			input: testInput{
+				locals: defaultLocals,
+				headers: map[string]string{
+					"X-Amz-Grant-Read": `emailAddress="user@example.com", uri="http://acs.amazonaws.com/groups/global/AuthenticatedUsers"`,
+				},
+			},
+			output: testOutput{
+				response: &Response{
+					MetaOpts: &MetaOptions{
+						BucketOwner: "root",
+						EventName:   s3event.EventObjectAclPut,
+					},
+				},
+			},
+		},
 		{
 			name: "backend returns error",
 			input: testInput{
@@ -867,6 +901,9 @@ func TestS3ApiController_PutObjectAcl(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			be := &BackendMock{
 				PutObjectAclFunc: func(contextMoqParam context.Context, putObjectAclInput *s3.PutObjectAclInput) error {
+					if grant := tt.input.headers["X-Amz-Grant-Read"]; grant != "" {
+						assert.Equal(t, `id="user", uri="http://acs.amazonaws.com/groups/global/AuthenticatedUsers"`, *putObjectAclInput.GrantRead)
+					}
 					return tt.input.beErr
 				},
 				GetBucketPolicyFunc: func(contextMoqParam context.Context, bucket string) ([]byte, error) {
@@ -874,8 +911,18 @@ func TestS3ApiController_PutObjectAcl(t *testing.T) {
 				},
 			}
 
+			iam := &IAMServiceMock{
+				ListUserAccountsFunc: func() ([]auth.Account, error) {
+					return []auth.Account{{Access: "user", Email: "user@example.com"}}, nil
+				},
+				GetUserAccountFunc: func(access string) (auth.Account, error) {
+					return auth.Account{Access: access}, nil
+				},
+			}
+
 			ctrl := S3ApiController{
-				be: be,
+				be:  be,
+				iam: iam,
 			}
 
 			testController(
@@ -884,7 +931,8 @@ func TestS3ApiController_PutObjectAcl(t *testing.T) {
 				tt.output.response,
 				tt.output.err,
 				ctxInputs{
-					locals: tt.input.locals,
+					locals:  tt.input.locals,
+					headers: tt.input.headers,
 				})
 		})
 	}
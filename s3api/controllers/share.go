@@ -0,0 +1,424 @@
+// Copyright 2026 Versity Software
+// This file is licensed under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package controllers
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/xml"
+	"errors"
+	"io"
+	"math"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/gofiber/fiber/v2"
+	"github.com/versity/versitygw/auth"
+	"github.com/versity/versitygw/backend"
+	"github.com/versity/versitygw/debuglogger"
+	"github.com/versity/versitygw/s3api/utils"
+	"github.com/versity/versitygw/s3err"
+	"github.com/versity/versitygw/s3response"
+)
+
+// SharePathPrefix is the S3 listener path the share links are served on.
+// Underscore is not allowed in bucket names, so the path never
+// conflicts with the bucket requests.
+const SharePathPrefix = "/_share"
+
+const sharePasswordHeader = "X-Vgw-Share-Password"
+
+// ShareController serves the share link requests and the
+// share link admin apis
+type ShareController struct {
+	be       backend.Backend
+	store    auth.ShareStore
+	readonly *atomic.Bool
+}
+
+func NewShareController(be backend.Backend, store auth.ShareStore, readonly *atomic.Bool) ShareController {
+	return ShareController{be: be, store: store, readonly: readonly}
+}
+
+// isReadonly reports whether the gateway is in read-only mode
+func (c ShareController) isReadonly() bool {
+	return c.readonly != nil && c.readonly.Load()
+}
+
+// CreateShareLink creates a new share link and returns
+// the link token, which is not retrievable later
+func (c ShareController) CreateShareLink(ctx *fiber.Ctx) (*Response, error) {
+	if c.store == nil {
+		return &Response{
+			MetaOpts: &MetaOptions{},
+		}, s3err.GetAPIError(s3err.ErrAdminShareLinksDisabled)
+	}
+	acct := utils.ContextKeyAccount.Get(ctx).(auth.Account)
+
+	var input auth.ShareLink
+	if err := xml.Unmarshal(ctx.Body(), &input); err != nil {
+		return &Response{
+			MetaOpts: &MetaOptions{},
+		}, s3err.GetAPIError(s3err.ErrMalformedXML)
+	}
+
+	link, err := auth.NewShareLink(input, acct.Access, time.Now())
+	if err != nil {
+		return &Response{
+			MetaOpts: &MetaOptions{},
+		}, err
+	}
+
+	_, err = c.be.HeadBucket(ctx.Context(), &s3.HeadBucketInput{Bucket: &link.Bucket})
+	if err != nil {
+		return &Response{
+			MetaOpts: &MetaOptions{},
+		}, err
+	}
+
+	err = c.store.CreateShareLink(link)
+	if err != nil {
+		return &Response{
+			MetaOpts: &MetaOptions{},
+		}, err
+	}
+
+	return &Response{
+		Data: link,
+		MetaOpts: &MetaOptions{
+			Status: http.StatusCreated,
+		},
+	}, nil
+}
+
+func (c ShareController) ListShareLinks(ctx *fiber.Ctx) (*Response, error) {
+	if c.store == nil {
+		return &Response{
+			MetaOpts: &MetaOptions{},
+		}, s3err.GetAPIError(s3err.ErrAdminShareLinksDisabled)
+	}
+	links, err := c.store.ListShareLinks()
+	if err != nil {
+		return &Response{
+			MetaOpts: &MetaOptions{},
+		}, err
+	}
+
+	result := auth.ListShareLinksResult{
+		ShareLinks: make([]auth.ShareLink, 0, len(links)),
+	}
+	for _, link := range links {
+		result.ShareLinks = append(result.ShareLinks, link.Public())
+	}
+
+	return &Response{
+		Data:     result,
+		MetaOpts: &MetaOptions{},
+	}, nil
+}
+
+func (c ShareController) RevokeShareLink(ctx *fiber.Ctx) (*Response, error) {
+	if c.store == nil {
+		return &Response{
+			MetaOpts: &MetaOptions{},
+		}, s3err.GetAPIError(s3err.ErrAdminShareLinksDisabled)
+	}
+	err := c.store.DeleteShareLink(ctx.Query("id"))
+	if errors.Is(err, auth.ErrNoSuchShareLink) {
+		err = s3err.GetAPIError(s3err.ErrAdminNoSuchShareLink)
+	}
+
+	return &Response{
+		MetaOpts: &MetaOptions{},
+	}, err
+}
+
+// GetSharedObject downloads the shared object, or lists
+// the shared prefix on the prefix links root path
+func (c ShareController) GetSharedObject(ctx *fiber.Ctx) (*Response, error) {
+	utils.ContextKeySkipResBodyLog.Set(ctx, true)
+
+	link, rel, err := c.authorize(ctx, auth.SharePermissionRead)
+	if err != nil {
+		return shareErrorResponse(link, err)
+	}
+
+	if link.IsPrefix() && rel == "" {
+		return c.listSharedPrefix(ctx, link)
+	}
+
+	key, ok := link.ObjectKey(rel)
+	if !ok {
+		return shareErrorResponse(link, s3err.GetAPIError(s3err.ErrAccessDenied))
+	}
+
+	acceptRange := ctx.Get("Range")
+	conditionalHeaders := utils.ParsePreconditionHeaders(ctx)
+
+	if ctx.Method() == http.MethodHead {
+		res, err := c.be.HeadObject(ctx.Context(), &s3.HeadObjectInput{
+			Bucket:            &link.Bucket,
+			Key:               &key,
+			Range:             &acceptRange,
+			IfMatch:           conditionalHeaders.IfMatch,
+			IfNoneMatch:       conditionalHeaders.IfNoneMatch,
+			IfModifiedSince:   conditionalHeaders.IfModSince,
+			IfUnmodifiedSince: conditionalHeaders.IfUnmodeSince,
+		})
+		if err != nil {
+			return shareErrorResponse(link, err)
+		}
+
+		return &Response{
+			Headers: map[string]*string{
+				"ETag":           res.ETag,
+				"Content-Length": utils.ConvertPtrToStringPtr(res.ContentLength),
+				"Content-Type":   res.ContentType,
+				"Last-Modified":  utils.FormatDatePtrToString(res.LastModified, timefmt),
+				"accept-ranges":  res.AcceptRanges,
+			},
+			MetaOpts: &MetaOptions{
+				BucketOwner: link.Creator,
+			},
+		}, nil
+	}
+
+	res, err := c.be.GetObject(ctx.Context(), &s3.GetObjectInput{
+		Bucket:            &link.Bucket,
+		Key:               &key,
+		Range:             &acceptRange,
+		IfMatch:           conditionalHeaders.IfMatch,
+		IfNoneMatch:       conditionalHeaders.IfNoneMatch,
+		IfModifiedSince:   conditionalHeaders.IfModSince,
+		IfUnmodifiedSince: conditionalHeaders.IfUnmodeSince,
+	})
+	if err != nil {
+		return shareErrorResponse(link, err)
+	}
+
+	// the download is counted before streaming, so the concurrent
+	// downloads can't exceed the link download limit
+	err = c.store.RecordDownload(link.ID)
+	if err != nil {
+		if res.Body != nil {
+			res.Body.Close()
+		}
+		if errors.Is(err, auth.ErrNoSuchShareLink) {
+			err = s3err.GetAPIError(s3err.ErrAccessDenied)
+		}
+		return shareErrorResponse(link, err)
+	}
+
+	status := http.StatusOK
+	if acceptRange != "" {
+		status = http.StatusPartialContent
+	}
+
+	if res.Body != nil {
+		contentLen := -1
+		if res.ContentLength != nil && *res.ContentLength <= int64(math.MaxInt) {
+			contentLen = int(*res.ContentLength)
+		}
+		utils.StreamResponseBody(ctx, res.Body, contentLen)
+	}
+
+	return &Response{
+		Headers: map[string]*string{
+			"ETag":                res.ETag,
+			"accept-ranges":       res.AcceptRanges,
+			"Content-Range":       res.ContentRange,
+			"Content-Disposition": res.ContentDisposition,
+			"Content-Encoding":    res.ContentEncoding,
+			"Content-Type":        res.ContentType,
+			"Last-Modified":       utils.FormatDatePtrToString(res.LastModified, timefmt),
+		},
+		MetaOpts: &MetaOptions{
+			BucketOwner:   link.Creator,
+			ContentLength: utils.GetInt64(res.ContentLength),
+			Status:        status,
+		},
+	}, nil
+}
+
+// listSharedPrefix lists the objects under the shared prefix with the
+// keys relative to the link path
+func (c ShareController) listSharedPrefix(ctx *fiber.Ctx, link auth.ShareLink) (*Response, error) {
+	prefix := link.Key + ctx.Query("prefix")
+	delimiter := ctx.Query("delimiter")
+	token := ctx.Query("continuation-token")
+	maxkeys := int32(ctx.QueryInt("max-keys", 1000))
+
+	res, err := c.be.ListObjectsV2(ctx.Context(), &s3.ListObjectsV2Input{
+		Bucket:            &link.Bucket,
+		Prefix:            &prefix,
+		Delimiter:         &delimiter,
+		ContinuationToken: &token,
+		MaxKeys:           &maxkeys,
+	})
+	if err != nil {
+		return shareErrorResponse(link, err)
+	}
+
+	trim := func(s *string) *string {
+		if s == nil {
+			return nil
+		}
+		rel := strings.TrimPrefix(*s, link.Key)
+		return &rel
+	}
+	res.Name = nil
+	res.Prefix = trim(res.Prefix)
+	for i := range res.Contents {
+		res.Contents[i].Key = trim(res.Contents[i].Key)
+		res.Contents[i].Owner = nil
+	}
+	for i := range res.CommonPrefixes {
+		res.CommonPrefixes[i].Prefix = trim(res.CommonPrefixes[i].Prefix)
+	}
+
+	return &Response{
+		Data: res,
+		MetaOpts: &MetaOptions{
+			BucketOwner: link.Creator,
+		},
+	}, nil
+}
+
+// PutSharedObject uploads an object through the links
+// with the upload permission
+func (c ShareController) PutSharedObject(ctx *fiber.Ctx) (*Response, error) {
+	link, rel, err := c.authorize(ctx, auth.SharePermissionUpload)
+	if err != nil {
+		return shareErrorResponse(link, err)
+	}
+	if c.isReadonly() {
+		return shareErrorResponse(link, s3err.GetAPIError(s3err.ErrAccessDenied))
+	}
+
+	key, ok := link.ObjectKey(rel)
+	if !ok {
+		return shareErrorResponse(link, s3err.GetAPIError(s3err.ErrAccessDenied))
+	}
+
+	contentLength := int64(ctx.Request().Header.ContentLength())
+	if contentLength < 0 {
+		return shareErrorResponse(link, s3err.GetAPIError(s3err.ErrMissingContentLength))
+	}
+	contentType := ctx.Get("Content-Type", defaultContentType)
+
+	var body io.Reader = ctx.Request().BodyStream()
+	if body == nil {
+		body = bytes.NewReader(ctx.Body())
+	}
+
+	res, err := c.be.PutObject(ctx.Context(), s3response.PutObjectInput{
+		Bucket:        &link.Bucket,
+		Key:           &key,
+		ContentLength: &contentLength,
+		ContentType:   &contentType,
+		Body:          body,
+	})
+	if err != nil {
+		return shareErrorResponse(link, err)
+	}
+
+	return &Response{
+		Headers: map[string]*string{
+			"ETag": &res.ETag,
+		},
+		MetaOpts: &MetaOptions{
+			BucketOwner:   link.Creator,
+			ContentLength: contentLength,
+			ObjectSize:    contentLength,
+		},
+	}, nil
+}
+
+// authorize finds the share link of the request token and checks
+// the link permission, expiry and password. Returns the path
+// relative to the link.
+func (c ShareController) authorize(ctx *fiber.Ctx, perm auth.SharePermission) (auth.ShareLink, string, error) {
+	token := ctx.Params("token")
+	rel := strings.TrimPrefix(ctx.Path(), SharePathPrefix+"/"+token)
+	rel = strings.TrimPrefix(rel, "/")
+
+	id, secret, ok := auth.ParseShareToken(token)
+	if !ok {
+		return auth.ShareLink{}, "", s3err.GetAPIError(s3err.ErrAccessDenied)
+	}
+
+	link, err := c.store.GetShareLink(id)
+	if errors.Is(err, auth.ErrNoSuchShareLink) {
+		debuglogger.Logf("share link %q not found", id)
+		return auth.ShareLink{}, "", s3err.GetAPIError(s3err.ErrAccessDenied)
+	}
+	if err != nil {
+		return auth.ShareLink{}, "", err
+	}
+	if !link.VerifySecret(secret) {
+		debuglogger.Logf("invalid share link %q secret", id)
+		return auth.ShareLink{}, "", s3err.GetAPIError(s3err.ErrAccessDenied)
+	}
+	if link.IsExpired(time.Now()) {
+		return link, "", s3err.GetAPIError(s3err.ErrShareLinkExpired)
+	}
+	if !link.Allows(perm) {
+		return link, "", s3err.GetAPIError(s3err.ErrAccessDenied)
+	}
+	if !link.VerifyPassword(sharePassword(ctx)) {
+		return link, "", s3err.GetAPIError(s3err.ErrShareLinkPasswordRequired)
+	}
+
+	return link, rel, nil
+}
+
+// sharePassword returns the link password from the password header,
+// or from the basic authentication password for the browsers
+func sharePassword(ctx *fiber.Ctx) string {
+	if pw := ctx.Get(sharePasswordHeader); pw != "" {
+		return pw
+	}
+
+	authz := ctx.Get("Authorization")
+	encoded, found := strings.CutPrefix(authz, "Basic ")
+	if !found {
+		return ""
+	}
+	decoded, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return ""
+	}
+	_, pw, _ := strings.Cut(string(decoded), ":")
+	return pw
+}
+
+func shareErrorResponse(link auth.ShareLink, err error) (*Response, error) {
+	var headers map[string]*string
+	if errors.Is(err, s3err.GetAPIError(s3err.ErrShareLinkPasswordRequired)) {
+		headers = map[string]*string{
+			"WWW-Authenticate": utils.GetStringPtr(`Basic realm="versitygw share link"`),
+		}
+	}
+
+	return &Response{
+		Headers: headers,
+		MetaOpts: &MetaOptions{
+			BucketOwner: link.Creator,
+		},
+	}, err
+}
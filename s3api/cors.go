@@ -0,0 +1,85 @@
+// Copyright 2026 Versity Software
+// This file is licensed under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package s3api
+
+import (
+	"sync/atomic"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/versity/versitygw/backend"
+	"github.com/versity/versitygw/s3api/middlewares"
+)
+
+// corsHandlers holds the CORS middlewares built for the default
+// allowed origin. The routes call the current middlewares, so the
+// origin can be changed without rebuilding the routes.
+type corsHandlers struct {
+	be       backend.Backend
+	handlers atomic.Pointer[corsHandlerSet]
+}
+
+type corsHandlerSet struct {
+	origin           string
+	defaultCORS      fiber.Handler
+	defaultPreflight fiber.Handler
+	bucketCORS       fiber.Handler
+	bucketPreflight  fiber.Handler
+}
+
+func newCORSHandlers(be backend.Backend, origin string) *corsHandlers {
+	c := &corsHandlers{be: be}
+	c.setOrigin(origin)
+	return c
+}
+
+// setOrigin rebuilds the middlewares for the new default origin
+func (c *corsHandlers) setOrigin(origin string) {
+	set := &corsHandlerSet{
+		origin:           origin,
+		defaultCORS:      middlewares.ApplyDefaultCORS(origin),
+		defaultPreflight: middlewares.ApplyDefaultCORSPreflight(origin),
+		bucketCORS:       middlewares.ApplyBucketCORS(c.be, origin),
+		bucketPreflight:  middlewares.ApplyBucketCORSPreflightFallback(c.be, origin),
+	}
+	c.handlers.Store(set)
+}
+
+func (c *corsHandlers) origin() string {
+	return c.handlers.Load().origin
+}
+
+func (c *corsHandlers) applyDefault() fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		return c.handlers.Load().defaultCORS(ctx)
+	}
+}
+
+func (c *corsHandlers) applyDefaultPreflight() fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		return c.handlers.Load().defaultPreflight(ctx)
+	}
+}
+
+func (c *corsHandlers) applyBucket() fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		return c.handlers.Load().bucketCORS(ctx)
+	}
+}
+
+func (c *corsHandlers) applyBucketPreflightFallback() fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		return c.handlers.Load().bucketPreflight(ctx)
+	}
+}
@@ -0,0 +1,226 @@
+// Copyright 2026 Versity Software
+// This file is licensed under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package s3api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/versity/versitygw/auth"
+	"github.com/versity/versitygw/backend"
+	"github.com/versity/versitygw/s3err"
+	"github.com/versity/versitygw/s3event"
+	"github.com/versity/versitygw/s3response"
+)
+
+const (
+	defaultReadinessTimeout = time.Second * 2
+
+	// readinessProbeAccess is the access key looked up to check the
+	// IAM service, the account isn't expected to exist
+	readinessProbeAccess = "vgw-readiness-probe"
+)
+
+// ReadinessCheck is a gateway component check run by the
+// readiness endpoint
+type ReadinessCheck struct {
+	Name  string
+	Check func(ctx context.Context) error
+}
+
+// ReadinessConfig is the readiness endpoint configuration
+type ReadinessConfig struct {
+	// Path is the GET readiness endpoint path
+	Path string
+	// CacheTTL is how long the check results are reused, so the
+	// frequent probes don't load the backend
+	CacheTTL time.Duration
+	// Timeout is how long the checks are waited for
+	Timeout time.Duration
+	// Drain is how long the endpoint reports not ready on the
+	// graceful shutdown before the server stops serving requests
+	Drain time.Duration
+	// Checks are the checks in addition to the backend, IAM and
+	// event sink checks
+	Checks []ReadinessCheck
+}
+
+// readiness serves the readiness endpoint. The checks are run
+// concurrently and bounded by the timeout. A check still running
+// from the previous probe (e.g. hung on an unresponsive mount) isn't
+// started again, and fails the probes until it returns.
+type readiness struct {
+	cfg      ReadinessConfig
+	draining atomic.Bool
+
+	mu     sync.Mutex
+	status s3response.ReadinessStatus
+	runs   []*readinessRun
+}
+
+// readinessRun is a single run of a readiness check
+type readinessRun struct {
+	done chan struct{}
+	err  error
+}
+
+func newReadiness(cfg ReadinessConfig) *readiness {
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = defaultReadinessTimeout
+	}
+	return &readiness{
+		cfg:  cfg,
+		runs: make([]*readinessRun, len(cfg.Checks)),
+	}
+}
+
+// readinessChecks returns the checks of the gateway components
+// supporting the health checks
+func readinessChecks(be backend.Backend, iam auth.IAMService, evs s3event.S3EventSender) []ReadinessCheck {
+	var checks []ReadinessCheck
+	if hc, ok := be.(backend.HealthChecker); ok {
+		checks = append(checks, ReadinessCheck{
+			Name:  "backend",
+			Check: hc.CheckHealth,
+		})
+	}
+	if iam != nil {
+		checks = append(checks, ReadinessCheck{
+			Name: "iam",
+			Check: func(context.Context) error {
+				_, err := iam.GetUserAccount(readinessProbeAccess)
+				if err == nil ||
+					errors.Is(err, auth.ErrNoSuchUser) ||
+					errors.Is(err, s3err.GetAPIError(s3err.ErrAdminUserNotFound)) {
+					return nil
+				}
+				return err
+			},
+		})
+	}
+	if hc, ok := evs.(s3event.HealthChecker); ok {
+		checks = append(checks, ReadinessCheck{
+			Name:  "events",
+			Check: hc.CheckHealth,
+		})
+	}
+	return checks
+}
+
+// handler responds with the component check results, the status
+// is 503 unless all the checks passed
+func (r *readiness) handler(ctx *fiber.Ctx) error {
+	status := r.check()
+	if status.Status != s3response.ReadinessReady {
+		ctx.Status(http.StatusServiceUnavailable)
+	}
+	return ctx.JSON(status)
+}
+
+// check returns the cached check results, or runs the checks
+// if the results expired
+func (r *readiness) check() s3response.ReadinessStatus {
+	if r.draining.Load() {
+		return s3response.ReadinessStatus{
+			Status:     s3response.ReadinessDraining,
+			Checked:    time.Now(),
+			Components: []s3response.ReadinessComponent{},
+		}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !r.status.Checked.IsZero() && time.Since(r.status.Checked) < r.cfg.CacheTTL {
+		return r.status
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), r.cfg.Timeout)
+	defer cancel()
+
+	for i, c := range r.cfg.Checks {
+		if run := r.runs[i]; run != nil && !run.finished() {
+			continue
+		}
+		r.runs[i] = startReadinessRun(c, r.cfg.Timeout)
+	}
+
+	status := s3response.ReadinessStatus{
+		Status:     s3response.ReadinessReady,
+		Components: make([]s3response.ReadinessComponent, 0, len(r.cfg.Checks)),
+	}
+	for i, c := range r.cfg.Checks {
+		var err error
+		select {
+		case <-r.runs[i].done:
+			err = r.runs[i].err
+		case <-ctx.Done():
+			err = fmt.Errorf("check timed out after %v", r.cfg.Timeout)
+		}
+
+		component := s3response.ReadinessComponent{
+			Name:   c.Name,
+			Status: s3response.ReadinessComponentOK,
+		}
+		if err != nil {
+			component.Status = s3response.ReadinessComponentFailed
+			component.Error = err.Error()
+			status.Status = s3response.ReadinessNotReady
+		}
+		status.Components = append(status.Components, component)
+	}
+	status.Checked = time.Now()
+
+	r.status = status
+	return status
+}
+
+// drain reports not ready for the drain period, so the load
+// balancers stop sending requests before the server shuts down
+func (r *readiness) drain() {
+	if r.draining.Swap(true) {
+		return
+	}
+	time.Sleep(r.cfg.Drain)
+}
+
+// startReadinessRun starts the check with the timeout context
+func startReadinessRun(c ReadinessCheck, timeout time.Duration) *readinessRun {
+	run := &readinessRun{
+		done: make(chan struct{}),
+	}
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+		run.err = c.Check(ctx)
+		close(run.done)
+	}()
+	return run
+}
+
+func (r *readinessRun) finished() bool {
+	select {
+	case <-r.done:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,201 @@
+// Copyright 2026 Versity Software
+// This file is licensed under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package s3api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/versity/versitygw/auth"
+	"github.com/versity/versitygw/backend"
+	"github.com/versity/versitygw/s3response"
+)
+
+type backendWithHealth struct {
+	backend.BackendUnsupported
+	err error
+}
+
+func (b backendWithHealth) CheckHealth(ctx context.Context) error {
+	return b.err
+}
+
+type failingIAM struct {
+	auth.IAMServiceSingle
+}
+
+func (failingIAM) GetUserAccount(access string) (auth.Account, error) {
+	return auth.Account{}, errors.New("connection refused")
+}
+
+func TestReadiness_Check(t *testing.T) {
+	var calls atomic.Int32
+	var failing atomic.Bool
+	r := newReadiness(ReadinessConfig{
+		CacheTTL: time.Hour,
+		Checks: []ReadinessCheck{
+			{Name: "ok", Check: func(context.Context) error { return nil }},
+			{Name: "flaky", Check: func(context.Context) error {
+				calls.Add(1)
+				if failing.Load() {
+					return errors.New("unavailable")
+				}
+				return nil
+			}},
+		},
+	})
+
+	status := r.check()
+	if status.Status != s3response.ReadinessReady {
+		t.Fatalf("expected ready, got %+v", status)
+	}
+	if len(status.Components) != 2 || status.Components[0].Name != "ok" ||
+		status.Components[1].Status != s3response.ReadinessComponentOK {
+		t.Fatalf("unexpected components: %+v", status.Components)
+	}
+
+	// the cached results are reused
+	failing.Store(true)
+	status = r.check()
+	if status.Status != s3response.ReadinessReady || calls.Load() != 1 {
+		t.Fatalf("expected the cached results, got %+v after %v calls", status, calls.Load())
+	}
+
+	r.cfg.CacheTTL = 0
+	status = r.check()
+	if status.Status != s3response.ReadinessNotReady {
+		t.Fatalf("expected not ready, got %+v", status)
+	}
+	if status.Components[0].Status != s3response.ReadinessComponentOK ||
+		status.Components[1].Status != s3response.ReadinessComponentFailed ||
+		status.Components[1].Error != "unavailable" {
+		t.Fatalf("unexpected components: %+v", status.Components)
+	}
+}
+
+func TestReadiness_CheckTimeout(t *testing.T) {
+	var calls atomic.Int32
+	release := make(chan struct{})
+	r := newReadiness(ReadinessConfig{
+		Timeout: 10 * time.Millisecond,
+		Checks: []ReadinessCheck{
+			{Name: "hung", Check: func(context.Context) error {
+				calls.Add(1)
+				<-release
+				return nil
+			}},
+		},
+	})
+
+	for range 2 {
+		status := r.check()
+		if status.Status != s3response.ReadinessNotReady ||
+			status.Components[0].Status != s3response.ReadinessComponentFailed {
+			t.Fatalf("expected the timed out check to fail, got %+v", status)
+		}
+	}
+	// the hung check isn't started again
+	if calls.Load() != 1 {
+		t.Fatalf("expected a single check run, got %v", calls.Load())
+	}
+
+	close(release)
+	r.cfg.Timeout = time.Second
+	status := r.check()
+	if status.Status != s3response.ReadinessReady {
+		t.Fatalf("expected ready after the check returned, got %+v", status)
+	}
+}
+
+func TestReadiness_Handler(t *testing.T) {
+	for _, tt := range []struct {
+		name       string
+		be         backend.Backend
+		iam        auth.IAMService
+		drain      bool
+		wantCode   int
+		wantStatus string
+	}{
+		{
+			name:       "ready",
+			be:         backendWithHealth{},
+			iam:        auth.IAMServiceSingle{},
+			wantCode:   http.StatusOK,
+			wantStatus: s3response.ReadinessReady,
+		},
+		{
+			name:       "backend failed",
+			be:         backendWithHealth{err: errors.New("stale file handle")},
+			iam:        auth.IAMServiceSingle{},
+			wantCode:   http.StatusServiceUnavailable,
+			wantStatus: s3response.ReadinessNotReady,
+		},
+		{
+			name:       "iam failed",
+			be:         backendWithHealth{},
+			iam:        failingIAM{},
+			wantCode:   http.StatusServiceUnavailable,
+			wantStatus: s3response.ReadinessNotReady,
+		},
+		{
+			name:       "draining",
+			be:         backendWithHealth{},
+			iam:        auth.IAMServiceSingle{},
+			drain:      true,
+			wantCode:   http.StatusServiceUnavailable,
+			wantStatus: s3response.ReadinessDraining,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newReadiness(ReadinessConfig{
+				Checks: readinessChecks(tt.be, tt.iam, nil),
+			})
+			if tt.drain {
+				r.drain()
+			}
+
+			app := fiber.New()
+			app.Get("/ready", r.handler)
+
+			resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/ready", nil))
+			if err != nil {
+				t.Fatalf("readiness request: %v", err)
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode != tt.wantCode {
+				t.Errorf("expected status code %v, got %v", tt.wantCode, resp.StatusCode)
+			}
+
+			var status s3response.ReadinessStatus
+			if err := json.NewDecoder(resp.Body).Decode(&status); err != nil {
+				t.Fatalf("decode readiness status: %v", err)
+			}
+			if status.Status != tt.wantStatus {
+				t.Errorf("expected status %q, got %q", tt.wantStatus, status.Status)
+			}
+			if !tt.drain && len(status.Components) != 2 {
+				t.Errorf("expected the backend and iam components, got %+v", status.Components)
+			}
+		})
+	}
+}
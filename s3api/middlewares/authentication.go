@@ -23,6 +23,7 @@ import (
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/versity/versitygw/auth"
+	"github.com/versity/versitygw/aws/signer/v4a"
 	"github.com/versity/versitygw/s3api/utils"
 	"github.com/versity/versitygw/s3err"
 )
@@ -46,7 +47,8 @@ func VerifyV4Signature(root RootUserConfig, iam auth.IAMService, region string,
 		if utils.ContextKeyPublicBucket.IsSet(ctx) {
 			return nil
 		}
-		// If ContextKeyAuthenticated is set in context locals, it means it was presigned url case
+		// If ContextKeyAuthenticated is set in context locals, it means it was presigned url
+		// or SigV2 case
 		if utils.ContextKeyAuthenticated.IsSet(ctx) {
 			return nil
 		}
@@ -79,7 +81,19 @@ func VerifyV4Signature(root RootUserConfig, iam auth.IAMService, region string,
 			return err
 		}
 
-		if authData.Region != region && !(allowDefaultRegion && authData.Region == defaultRegion) {
+		if authData.Algorithm == v4a.Algorithm {
+			// the SigV4A requests are signed for a set of regions
+			// instead of the region of the credential scope
+			regionSet := ctx.Get(v4a.AmzRegionSetKey)
+			if regionSet == "" {
+				return s3err.MalformedAuth.MissingRegionSet()
+			}
+			if !v4a.RegionSetContains(regionSet, region) && !(allowDefaultRegion && v4a.RegionSetContains(regionSet, defaultRegion)) {
+				return s3err.MalformedAuth.IncorrectRegionSet(region, regionSet)
+			}
+			authData.RegionSet = regionSet
+			authData.Region = region
+		} else if authData.Region != region && !(allowDefaultRegion && authData.Region == defaultRegion) {
 			return s3err.MalformedAuth.IncorrectRegion(region, authData.Region)
 		}
 
@@ -98,6 +112,7 @@ func VerifyV4Signature(root RootUserConfig, iam auth.IAMService, region string,
 		}
 
 		utils.ContextKeyAccount.Set(ctx, account)
+		utils.SetRequestAuth(ctx, authData.SignatureVersion(), utils.AuthTypeHeader)
 
 		var contentLength int64
 		contentLengthStr := ctx.Get("Content-Length")
@@ -0,0 +1,81 @@
+// Copyright 2026 Versity Software
+// This file is licensed under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package middlewares
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/versity/versitygw/auth"
+	"github.com/versity/versitygw/debuglogger"
+	"github.com/versity/versitygw/s3api/utils"
+	"github.com/versity/versitygw/s3err"
+)
+
+// VerifyClientCertificate authenticates the unsigned requests of the mutual
+// TLS connections with the account mapped to the verified client
+// certificate. The signed requests are left to the signature middlewares,
+// and the unsigned requests without a mapped certificate remain anonymous.
+// Like VerifyV2Signature, the authenticated requests are marked as such in
+// the context, so that the v4 signature middlewares of the routes skip them.
+func VerifyClientCertificate(root RootUserConfig, iam auth.IAMService, mapper auth.CertAccountMapper) fiber.Handler {
+	acct := accounts{root: root, iam: iam}
+
+	return func(ctx *fiber.Ctx) error {
+		if utils.ContextKeyAuthenticated.IsSet(ctx) {
+			return nil
+		}
+		if ctx.Get("Authorization") != "" || utils.IsPresignedURLAuth(ctx) || utils.IsSigV2QueryAuth(ctx) {
+			return nil
+		}
+
+		state := ctx.Context().TLSConnectionState()
+		if state == nil || len(state.VerifiedChains) == 0 || len(state.VerifiedChains[0]) == 0 {
+			return nil
+		}
+		cert := state.VerifiedChains[0][0]
+
+		access, err := mapper.MapCertificate(cert)
+		if errors.Is(err, auth.ErrNoCertMapping) {
+			debuglogger.Logf("no account mapped to the client certificate %q", cert.Subject)
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		account, err := acct.getAccount(access)
+		if err == auth.ErrNoSuchUser {
+			debuglogger.Logf("client certificate %q mapped to unknown account %q", cert.Subject, access)
+			return s3err.GetAPIError(s3err.ErrAccessDenied)
+		}
+		if err != nil {
+			return err
+		}
+
+		utils.ContextKeyAuthenticated.Set(ctx, true)
+		utils.ContextKeyIsRoot.Set(ctx, access == root.Access)
+		utils.ContextKeyAccount.Set(ctx, account)
+		utils.SetRequestAuth(ctx, "", utils.AuthTypeClientCert)
+
+		// the unsigned payload is not covered by the body readers, so the
+		// upload limit is checked here
+		if ctx.Request().Header.ContentLength() > maxObjSizeLimit {
+			return s3err.GetAPIError(s3err.ErrEntityTooLarge)
+		}
+
+		return nil
+	}
+}
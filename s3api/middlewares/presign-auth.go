@@ -35,6 +35,10 @@ func VerifyPresignedV4Signature(root RootUserConfig, iam auth.IAMService, region
 		if !utils.IsPresignedURLAuth(ctx) {
 			return nil
 		}
+		// The request is already authenticated with SigV2
+		if utils.ContextKeyAuthenticated.IsSet(ctx) {
+			return nil
+		}
 
 		if ctx.Request().URI().QueryArgs().Has("X-Amz-Security-Token") {
 			// OIDC Authorization with X-Amz-Security-Token is not supported
@@ -60,6 +64,7 @@ func VerifyPresignedV4Signature(root RootUserConfig, iam auth.IAMService, region
 			return err
 		}
 		utils.ContextKeyAccount.Set(ctx, account)
+		utils.SetRequestAuth(ctx, authData.SignatureVersion(), utils.AuthTypeQuery)
 
 		var contentLength int64
 		contentLengthStr := ctx.Get("Content-Length")
@@ -32,13 +32,19 @@ import (
 // access to anonymous requesters
 func AuthorizePublicBucketAccess(be backend.Backend, s3action string, policyPermission auth.Action, permission auth.Permission, region string, streamBody bool) fiber.Handler {
 	return func(ctx *fiber.Ctx) error {
+		// the requests already authenticated with SigV2 or a client
+		// certificate are not signed with SigV4, so the payload is
+		// handled the same as for the anonymous requests
+		if utils.ContextKeyAuthenticated.IsSet(ctx) {
+			return handleUnsignedPayload(ctx, streamBody)
+		}
 		// skip for authenticated requests
 		if utils.IsPresignedURLAuth(ctx) || ctx.Get("Authorization") != "" {
 			return nil
 		}
 
 		switch s3action {
-		case metrics.ActionListAllMyBuckets:
+		case metrics.ActionListAllMyBuckets, metrics.ActionPutPublicAccessBlock, metrics.ActionGetPublicAccessBlock, metrics.ActionDeletePublicAccessBlock:
 			return s3err.GetAPIError(s3err.ErrAccessDenied)
 		case metrics.ActionGetBucketOwnershipControls:
 			return s3err.GetAPIError(s3err.ErrAnonymousGetBucketOwnership)
@@ -71,60 +77,66 @@ func AuthorizePublicBucketAccess(be backend.Backend, s3action string, policyPerm
 		// as public access is granted
 		utils.ContextKeyPublicBucket.Set(ctx, true)
 
-		payloadHash := ctx.Get("X-Amz-Content-Sha256")
-		err = utils.IsAnonymousPayloadHashSupported(payloadHash)
-		if err != nil {
-			return err
-		}
+		return handleUnsignedPayload(ctx, streamBody)
+	}
+}
 
-		if streamBody {
-			if utils.IsUnsignedStreamingPayload(payloadHash) {
-				cLength, err := utils.ParseDecodedContentLength(ctx)
-				if err != nil {
-					return err
-				}
-				// stack an unsigned streaming payload reader
-				checksumType, err := utils.ExtractChecksumType(ctx)
-				if err != nil {
-					return err
-				}
-
-				wrapBodyReader(ctx, func(r io.Reader) io.Reader {
-					var cr io.Reader
-					cr, err = utils.NewUnsignedChunkReader(r, checksumType, cLength)
-					return cr
-				})
+// handleUnsignedPayload validates the payload of the requests not signed
+// with SigV4, and stacks the body readers of the streaming requests
+func handleUnsignedPayload(ctx *fiber.Ctx, streamBody bool) error {
+	payloadHash := ctx.Get("X-Amz-Content-Sha256")
+	err := utils.IsAnonymousPayloadHashSupported(payloadHash)
+	if err != nil {
+		return err
+	}
 
+	if streamBody {
+		if utils.IsUnsignedStreamingPayload(payloadHash) {
+			cLength, err := utils.ParseDecodedContentLength(ctx)
+			if err != nil {
 				return err
-			} else if utils.IsUnsignedPaylod(payloadHash) {
-				// for UNSIGNED-PAYLOD simply store the body reader in context locals
-				utils.ContextKeyBodyReader.Set(ctx, ctx.Request().BodyStream())
-				return nil
-			} else {
-				// stack a hash reader to calculated the payload sha256 hash
-				wrapBodyReader(ctx, func(r io.Reader) io.Reader {
-					var cr io.Reader
-					cr, err = utils.NewHashReader(r, payloadHash, utils.HashTypeSha256Hex)
-					return cr
-				})
-
+			}
+			// stack an unsigned streaming payload reader
+			checksumType, err := utils.ExtractChecksumType(ctx)
+			if err != nil {
 				return err
 			}
-		}
 
-		if payloadHash != "" {
-			// Calculate the hash of the request payload
-			hashedPayload := sha256.Sum256(ctx.Body())
-			hexPayload := hex.EncodeToString(hashedPayload[:])
+			wrapBodyReader(ctx, func(r io.Reader) io.Reader {
+				var cr io.Reader
+				cr, err = utils.NewUnsignedChunkReader(r, checksumType, cLength)
+				return cr
+			})
 
-			// Compare the calculated hash with the hash provided
-			if payloadHash != hexPayload {
-				return s3err.GetAPIError(s3err.ErrContentSHA256Mismatch)
-			}
+			return err
+		} else if utils.IsUnsignedPaylod(payloadHash) {
+			// for UNSIGNED-PAYLOD simply store the body reader in context locals
+			utils.ContextKeyBodyReader.Set(ctx, ctx.Request().BodyStream())
+			return nil
+		} else {
+			// stack a hash reader to calculated the payload sha256 hash
+			wrapBodyReader(ctx, func(r io.Reader) io.Reader {
+				var cr io.Reader
+				cr, err = utils.NewHashReader(r, payloadHash, utils.HashTypeSha256Hex)
+				return cr
+			})
+
+			return err
 		}
+	}
+
+	if payloadHash != "" {
+		// Calculate the hash of the request payload
+		hashedPayload := sha256.Sum256(ctx.Body())
+		hexPayload := hex.EncodeToString(hashedPayload[:])
 
-		return nil
+		// Compare the calculated hash with the hash provided
+		if payloadHash != hexPayload {
+			return s3err.GetAPIError(s3err.ErrContentSHA256Mismatch)
+		}
 	}
+
+	return nil
 }
 
 // parsePath extracts the bucket and object names from the path
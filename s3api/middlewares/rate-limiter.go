@@ -15,20 +15,63 @@
 package middlewares
 
 import (
+	"sync/atomic"
+
 	"github.com/gofiber/fiber/v2"
 	"github.com/versity/versitygw/metrics"
 	"github.com/versity/versitygw/s3err"
 	"github.com/versity/versitygw/s3log"
-	"golang.org/x/sync/semaphore"
 )
 
 // RateLimiter hard-limits the number of in-flight requests.
 // If the limit is reached, an immediate SlowDown error is returned
 func RateLimiter(limit int, mm metrics.Manager, logger s3log.AuditLogger) fiber.Handler {
-	sem := semaphore.NewWeighted(int64(limit))
+	return NewLimiter(limit).Handler(mm, logger)
+}
+
+// Limiter counts the in-flight requests against a limit that
+// can be changed while the requests are served
+type Limiter struct {
+	limit    atomic.Int64
+	inflight atomic.Int64
+}
+
+// NewLimiter creates a limiter of the in-flight requests
+func NewLimiter(limit int) *Limiter {
+	l := &Limiter{}
+	l.limit.Store(int64(limit))
+	return l
+}
+
+// SetLimit changes the limit. The requests already in flight
+// above a lowered limit are not interrupted.
+func (l *Limiter) SetLimit(limit int) {
+	l.limit.Store(int64(limit))
+}
+
+// Limit returns the current limit
+func (l *Limiter) Limit() int {
+	return int(l.limit.Load())
+}
+
+func (l *Limiter) tryAcquire() bool {
+	if l.inflight.Add(1) > l.limit.Load() {
+		l.inflight.Add(-1)
+		return false
+	}
+	return true
+}
+
+func (l *Limiter) release() {
+	l.inflight.Add(-1)
+}
 
+// Handler returns the middleware that hard-limits the number of
+// in-flight requests. If the limit is reached, an immediate
+// SlowDown error is returned
+func (l *Limiter) Handler(mm metrics.Manager, logger s3log.AuditLogger) fiber.Handler {
 	return func(ctx *fiber.Ctx) error {
-		if !sem.TryAcquire(1) {
+		if !l.tryAcquire() {
 			// limit reached
 			err := s3err.GetAPIError(s3err.ErrSlowDown)
 
@@ -44,7 +87,7 @@ func RateLimiter(limit int, mm metrics.Manager, logger s3log.AuditLogger) fiber.
 			ctx.Status(err.HTTPStatusCode)
 			return ctx.Send(s3err.GetAPIErrorResponse(err, "", "", ""))
 		}
-		defer sem.Release(1)
+		defer l.release()
 		return ctx.Next()
 	}
 }
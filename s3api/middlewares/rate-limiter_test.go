@@ -0,0 +1,64 @@
+// Copyright 2026 Versity Software
+// This file is licensed under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package middlewares
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLimiter(t *testing.T) {
+	l := NewLimiter(1)
+	assert.Equal(t, 1, l.Limit())
+
+	assert.True(t, l.tryAcquire())
+	assert.False(t, l.tryAcquire())
+
+	l.SetLimit(2)
+	assert.Equal(t, 2, l.Limit())
+	assert.True(t, l.tryAcquire())
+	assert.False(t, l.tryAcquire())
+
+	// lowering the limit keeps the in-flight requests
+	l.SetLimit(1)
+	l.release()
+	assert.False(t, l.tryAcquire())
+	l.release()
+	assert.True(t, l.tryAcquire())
+}
+
+func TestLimiter_Handler(t *testing.T) {
+	l := NewLimiter(0)
+
+	app := fiber.New()
+	app.Use(l.Handler(nil, nil))
+	app.Get("/", func(ctx *fiber.Ctx) error {
+		return ctx.SendStatus(http.StatusOK)
+	})
+
+	resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/", nil))
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+
+	l.SetLimit(1)
+	resp, err = app.Test(httptest.NewRequest(http.MethodGet, "/", nil))
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+}
@@ -0,0 +1,74 @@
+// Copyright 2026 Versity Software
+// This file is licensed under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package middlewares
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/versity/versitygw/auth"
+	"github.com/versity/versitygw/s3api/utils"
+	"github.com/versity/versitygw/s3err"
+)
+
+// VerifyV2Signature authenticates the requests signed with the legacy
+// AWS Signature Version 2, with either the Authorization header or the
+// query parameters. The authenticated requests are marked as such in the
+// context, so that the v4 signature middlewares of the routes skip them,
+// and the public bucket middleware only handles their unsigned payload.
+// The requests not signed with SigV2 are left to the route middlewares.
+func VerifyV2Signature(root RootUserConfig, iam auth.IAMService, virtualDomain string) fiber.Handler {
+	acct := accounts{root: root, iam: iam}
+
+	return func(ctx *fiber.Ctx) error {
+		if !utils.IsSigV2HeaderAuth(ctx) && !utils.IsSigV2QueryAuth(ctx) {
+			return nil
+		}
+		// the v4 presigned requests are validated by the route
+		// middlewares
+		if utils.IsPresignedURLAuth(ctx) {
+			return nil
+		}
+
+		utils.ContextKeyAuthenticated.Set(ctx, true)
+
+		authData, err := utils.ParseSigV2Authorization(ctx)
+		if err != nil {
+			return err
+		}
+
+		utils.ContextKeyIsRoot.Set(ctx, authData.Access == root.Access)
+
+		account, err := acct.getAccount(authData.Access)
+		if err == auth.ErrNoSuchUser {
+			return s3err.GetAPIError(s3err.ErrInvalidAccessKeyID)
+		}
+		if err != nil {
+			return err
+		}
+		utils.ContextKeyAccount.Set(ctx, account)
+		authType := utils.AuthTypeHeader
+		if utils.IsSigV2QueryAuth(ctx) {
+			authType = utils.AuthTypeQuery
+		}
+		utils.SetRequestAuth(ctx, utils.SignatureVersionV2, authType)
+
+		// the SigV2 signature doesn't cover the payload, so the upload
+		// limit is checked here instead of in the body readers
+		if ctx.Request().Header.ContentLength() > maxObjSizeLimit {
+			return s3err.GetAPIError(s3err.ErrEntityTooLarge)
+		}
+
+		return utils.CheckSigV2Signature(ctx, authData, account.Secret, virtualDomain)
+	}
+}
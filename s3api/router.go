@@ -15,6 +15,8 @@
 package s3api
 
 import (
+	"sync/atomic"
+
 	"github.com/gofiber/fiber/v2"
 	"github.com/versity/versitygw/auth"
 	"github.com/versity/versitygw/backend"
@@ -37,15 +39,20 @@ type S3ApiRouter struct {
 	mm              metrics.Manager
 	root            middlewares.RootUserConfig
 	Ctrl            controllers.S3ApiController
-	readonly        bool
+	readonly        atomic.Bool
 	disableACL      bool
 	region          string
 	virtualDomain   string
 	corsAllowOrigin string
+	cors            *corsHandlers
+	sigV2           bool
+	certMapper      auth.CertAccountMapper
+	shareStore      auth.ShareStore
 }
 
 func (sa *S3ApiRouter) Init() {
-	ctrl := controllers.New(sa.be, sa.iam, sa.logger, sa.evs, sa.mm, sa.readonly, sa.disableACL, sa.virtualDomain)
+	sa.cors = newCORSHandlers(sa.be, sa.corsAllowOrigin)
+	ctrl := controllers.New(sa.be, sa.iam, sa.logger, sa.evs, sa.mm, &sa.readonly, sa.disableACL, sa.virtualDomain)
 	sa.Ctrl = ctrl
 	adminServices := &controllers.Services{
 		Logger: sa.aLogger,
@@ -56,6 +63,20 @@ func (sa *S3ApiRouter) Init() {
 		sa.app.Use(middlewares.HostStyleParser(sa.virtualDomain))
 	}
 
+	// initialize the legacy SigV2 authentication middleware if enabled,
+	// the route middlewares skip the requests it authenticated
+	if sa.sigV2 {
+		sa.app.Use(controllers.WrapMiddleware(middlewares.VerifyV2Signature(sa.root, sa.iam, sa.virtualDomain), sa.logger, sa.mm))
+	}
+
+	// initialize the mutual TLS client certificate authentication
+	// middleware if enabled
+	if sa.certMapper != nil {
+		sa.app.Use(controllers.WrapMiddleware(middlewares.VerifyClientCertificate(sa.root, sa.iam, sa.certMapper), sa.logger, sa.mm))
+	}
+
+	shareController := controllers.NewShareController(sa.be, sa.shareStore, &sa.readonly)
+
 	if sa.WithAdmSrv {
 		adminController := controllers.NewAdminController(sa.iam, sa.be, sa.aLogger, ctrl)
 
@@ -64,11 +85,11 @@ func (sa *S3ApiRouter) Init() {
 			controllers.ProcessHandlers(adminController.CreateUser, metrics.ActionAdminCreateUser, adminServices,
 				middlewares.VerifyV4Signature(sa.root, sa.iam, sa.region, false, true, false),
 				middlewares.IsAdmin(metrics.ActionAdminCreateUser),
-				middlewares.ApplyDefaultCORS(sa.corsAllowOrigin),
+				sa.cors.applyDefault(),
 			))
 		sa.app.Options("/create-user",
-			middlewares.ApplyDefaultCORSPreflight(sa.corsAllowOrigin),
-			middlewares.ApplyDefaultCORS(sa.corsAllowOrigin),
+			sa.cors.applyDefaultPreflight(),
+			sa.cors.applyDefault(),
 		)
 
 		// DeleteUsers admin api
@@ -76,11 +97,11 @@ func (sa *S3ApiRouter) Init() {
 			controllers.ProcessHandlers(adminController.DeleteUser, metrics.ActionAdminDeleteUser, adminServices,
 				middlewares.VerifyV4Signature(sa.root, sa.iam, sa.region, false, true, false),
 				middlewares.IsAdmin(metrics.ActionAdminDeleteUser),
-				middlewares.ApplyDefaultCORS(sa.corsAllowOrigin),
+				sa.cors.applyDefault(),
 			))
 		sa.app.Options("/delete-user",
-			middlewares.ApplyDefaultCORSPreflight(sa.corsAllowOrigin),
-			middlewares.ApplyDefaultCORS(sa.corsAllowOrigin),
+			sa.cors.applyDefaultPreflight(),
+			sa.cors.applyDefault(),
 		)
 
 		// UpdateUser admin api
@@ -88,11 +109,11 @@ func (sa *S3ApiRouter) Init() {
 			controllers.ProcessHandlers(adminController.UpdateUser, metrics.ActionAdminUpdateUser, adminServices,
 				middlewares.VerifyV4Signature(sa.root, sa.iam, sa.region, false, true, false),
 				middlewares.IsAdmin(metrics.ActionAdminUpdateUser),
-				middlewares.ApplyDefaultCORS(sa.corsAllowOrigin),
+				sa.cors.applyDefault(),
 			))
 		sa.app.Options("/update-user",
-			middlewares.ApplyDefaultCORSPreflight(sa.corsAllowOrigin),
-			middlewares.ApplyDefaultCORS(sa.corsAllowOrigin),
+			sa.cors.applyDefaultPreflight(),
+			sa.cors.applyDefault(),
 		)
 
 		// ListUsers admin api
@@ -100,11 +121,11 @@ func (sa *S3ApiRouter) Init() {
 			controllers.ProcessHandlers(adminController.ListUsers, metrics.ActionAdminListUsers, adminServices,
 				middlewares.VerifyV4Signature(sa.root, sa.iam, sa.region, false, true, false),
 				middlewares.IsAdmin(metrics.ActionAdminListUsers),
-				middlewares.ApplyDefaultCORS(sa.corsAllowOrigin),
+				sa.cors.applyDefault(),
 			))
 		sa.app.Options("/list-users",
-			middlewares.ApplyDefaultCORSPreflight(sa.corsAllowOrigin),
-			middlewares.ApplyDefaultCORS(sa.corsAllowOrigin),
+			sa.cors.applyDefaultPreflight(),
+			sa.cors.applyDefault(),
 		)
 
 		// ChangeBucketOwner admin api
@@ -112,11 +133,59 @@ func (sa *S3ApiRouter) Init() {
 			controllers.ProcessHandlers(adminController.ChangeBucketOwner, metrics.ActionAdminChangeBucketOwner, adminServices,
 				middlewares.VerifyV4Signature(sa.root, sa.iam, sa.region, false, true, false),
 				middlewares.IsAdmin(metrics.ActionAdminChangeBucketOwner),
-				middlewares.ApplyDefaultCORS(sa.corsAllowOrigin),
+				sa.cors.applyDefault(),
 			))
 		sa.app.Options("/change-bucket-owner",
-			middlewares.ApplyDefaultCORSPreflight(sa.corsAllowOrigin),
-			middlewares.ApplyDefaultCORS(sa.corsAllowOrigin),
+			sa.cors.applyDefaultPreflight(),
+			sa.cors.applyDefault(),
+		)
+
+		// SnapshotBucket admin api
+		sa.app.Patch("/snapshot-bucket",
+			controllers.ProcessHandlers(adminController.SnapshotBucket, metrics.ActionAdminSnapshotBucket, adminServices,
+				middlewares.VerifyV4Signature(sa.root, sa.iam, sa.region, false, true, false),
+				middlewares.IsAdmin(metrics.ActionAdminSnapshotBucket),
+				sa.cors.applyDefault(),
+			))
+		sa.app.Options("/snapshot-bucket",
+			sa.cors.applyDefaultPreflight(),
+			sa.cors.applyDefault(),
+		)
+
+		// SnapshotBucketStatus admin api
+		sa.app.Patch("/snapshot-bucket-status",
+			controllers.ProcessHandlers(adminController.SnapshotBucketStatus, metrics.ActionAdminSnapshotStatus, adminServices,
+				middlewares.VerifyV4Signature(sa.root, sa.iam, sa.region, false, true, false),
+				middlewares.IsAdmin(metrics.ActionAdminSnapshotStatus),
+				sa.cors.applyDefault(),
+			))
+		sa.app.Options("/snapshot-bucket-status",
+			sa.cors.applyDefaultPreflight(),
+			sa.cors.applyDefault(),
+		)
+
+		// ScrubBucket admin api
+		sa.app.Patch("/scrub-bucket",
+			controllers.ProcessHandlers(adminController.ScrubBucket, metrics.ActionAdminScrubBucket, adminServices,
+				middlewares.VerifyV4Signature(sa.root, sa.iam, sa.region, false, true, false),
+				middlewares.IsAdmin(metrics.ActionAdminScrubBucket),
+				sa.cors.applyDefault(),
+			))
+		sa.app.Options("/scrub-bucket",
+			sa.cors.applyDefaultPreflight(),
+			sa.cors.applyDefault(),
+		)
+
+		// TransitionObject admin api
+		sa.app.Patch("/transition-object",
+			controllers.ProcessHandlers(adminController.TransitionObject, metrics.ActionAdminTransitionObject, adminServices,
+				middlewares.VerifyV4Signature(sa.root, sa.iam, sa.region, false, true, false),
+				middlewares.IsAdmin(metrics.ActionAdminTransitionObject),
+				sa.cors.applyDefault(),
+			))
+		sa.app.Options("/transition-object",
+			sa.cors.applyDefaultPreflight(),
+			sa.cors.applyDefault(),
 		)
 
 		// ListBucketsAndOwners admin api
@@ -124,11 +193,11 @@ func (sa *S3ApiRouter) Init() {
 			controllers.ProcessHandlers(adminController.ListBuckets, metrics.ActionAdminListBuckets, adminServices,
 				middlewares.VerifyV4Signature(sa.root, sa.iam, sa.region, false, true, false),
 				middlewares.IsAdmin(metrics.ActionAdminListBuckets),
-				middlewares.ApplyDefaultCORS(sa.corsAllowOrigin),
+				sa.cors.applyDefault(),
 			))
 		sa.app.Options("/list-buckets",
-			middlewares.ApplyDefaultCORSPreflight(sa.corsAllowOrigin),
-			middlewares.ApplyDefaultCORS(sa.corsAllowOrigin),
+			sa.cors.applyDefaultPreflight(),
+			sa.cors.applyDefault(),
 		)
 
 		// CreateBucket admin api
@@ -136,11 +205,47 @@ func (sa *S3ApiRouter) Init() {
 			controllers.ProcessHandlers(adminController.CreateBucket, metrics.ActionAdminCreateBucket, adminServices,
 				middlewares.VerifyV4Signature(sa.root, sa.iam, sa.region, false, true, false),
 				middlewares.IsAdmin(metrics.ActionAdminCreateBucket),
-				middlewares.ApplyDefaultCORS(sa.corsAllowOrigin),
+				sa.cors.applyDefault(),
 			))
 		sa.app.Options("/:bucket/create",
-			middlewares.ApplyDefaultCORSPreflight(sa.corsAllowOrigin),
-			middlewares.ApplyDefaultCORS(sa.corsAllowOrigin),
+			sa.cors.applyDefaultPreflight(),
+			sa.cors.applyDefault(),
+		)
+
+		// CreateShareLink admin api
+		sa.app.Patch("/create-share-link",
+			controllers.ProcessHandlers(shareController.CreateShareLink, metrics.ActionAdminCreateShareLink, adminServices,
+				middlewares.VerifyV4Signature(sa.root, sa.iam, sa.region, false, true, false),
+				middlewares.IsAdmin(metrics.ActionAdminCreateShareLink),
+				sa.cors.applyDefault(),
+			))
+		sa.app.Options("/create-share-link",
+			sa.cors.applyDefaultPreflight(),
+			sa.cors.applyDefault(),
+		)
+
+		// ListShareLinks admin api
+		sa.app.Patch("/list-share-links",
+			controllers.ProcessHandlers(shareController.ListShareLinks, metrics.ActionAdminListShareLinks, adminServices,
+				middlewares.VerifyV4Signature(sa.root, sa.iam, sa.region, false, true, false),
+				middlewares.IsAdmin(metrics.ActionAdminListShareLinks),
+				sa.cors.applyDefault(),
+			))
+		sa.app.Options("/list-share-links",
+			sa.cors.applyDefaultPreflight(),
+			sa.cors.applyDefault(),
+		)
+
+		// RevokeShareLink admin api
+		sa.app.Patch("/revoke-share-link",
+			controllers.ProcessHandlers(shareController.RevokeShareLink, metrics.ActionAdminRevokeShareLink, adminServices,
+				middlewares.VerifyV4Signature(sa.root, sa.iam, sa.region, false, true, false),
+				middlewares.IsAdmin(metrics.ActionAdminRevokeShareLink),
+				sa.cors.applyDefault(),
+			))
+		sa.app.Options("/revoke-share-link",
+			sa.cors.applyDefaultPreflight(),
+			sa.cors.applyDefault(),
 		)
 	}
 
@@ -150,6 +255,20 @@ func (sa *S3ApiRouter) Init() {
 		MetricsManager: sa.mm,
 	}
 
+	// Share link object download and upload, the share link
+	// token authenticates the requests
+	if sa.shareStore != nil {
+		shareRouter := sa.app.Group(controllers.SharePathPrefix + "/:token")
+		shareRouter.Get("",
+			controllers.ProcessHandlers(shareController.GetSharedObject, metrics.ActionShareGetObject, services))
+		shareRouter.Get("/*",
+			controllers.ProcessHandlers(shareController.GetSharedObject, metrics.ActionShareGetObject, services))
+		shareRouter.Put("",
+			controllers.ProcessHandlers(shareController.PutSharedObject, metrics.ActionSharePutObject, services))
+		shareRouter.Put("/*",
+			controllers.ProcessHandlers(shareController.PutSharedObject, metrics.ActionSharePutObject, services))
+	}
+
 	// ListBuckets action
 
 	// copy source is not allowed on '/'
@@ -158,7 +277,7 @@ func (sa *S3ApiRouter) Init() {
 			ctrl.HandleErrorRoute(s3err.GetAPIError(s3err.ErrCopySourceNotAllowed)),
 			metrics.ActionUndetected,
 			services,
-			middlewares.ApplyDefaultCORS(sa.corsAllowOrigin),
+			sa.cors.applyDefault(),
 		),
 	)
 
@@ -167,15 +286,15 @@ func (sa *S3ApiRouter) Init() {
 			ctrl.ListBuckets,
 			metrics.ActionListAllMyBuckets,
 			services,
-			middlewares.ApplyDefaultCORS(sa.corsAllowOrigin),
+			sa.cors.applyDefault(),
 			middlewares.AuthorizePublicBucketAccess(sa.be, metrics.ActionListAllMyBuckets, "", auth.PermissionRead, sa.region, false),
 			middlewares.VerifyPresignedV4Signature(sa.root, sa.iam, sa.region, false),
 			middlewares.VerifyV4Signature(sa.root, sa.iam, sa.region, false, true, true),
 		))
 
 	sa.app.Options("/",
-		middlewares.ApplyDefaultCORSPreflight(sa.corsAllowOrigin),
-		middlewares.ApplyDefaultCORS(sa.corsAllowOrigin),
+		sa.cors.applyDefaultPreflight(),
+		sa.cors.applyDefault(),
 	)
 
 	bucketRouter := sa.app.Group("/:bucket")
@@ -189,7 +308,7 @@ func (sa *S3ApiRouter) Init() {
 			metrics.ActionPutBucketTagging,
 			services,
 			middlewares.BucketObjectNameValidator(),
-			middlewares.ApplyBucketCORS(sa.be, sa.corsAllowOrigin),
+			sa.cors.applyBucket(),
 			middlewares.AuthorizePublicBucketAccess(sa.be, metrics.ActionPutBucketTagging, auth.PutBucketTaggingAction, auth.PermissionWrite, sa.region, false),
 			middlewares.VerifyPresignedV4Signature(sa.root, sa.iam, sa.region, false),
 			middlewares.VerifyV4Signature(sa.root, sa.iam, sa.region, false, true, false),
@@ -207,7 +326,7 @@ func (sa *S3ApiRouter) Init() {
 			middlewares.VerifyPresignedV4Signature(sa.root, sa.iam, sa.region, false),
 			middlewares.VerifyV4Signature(sa.root, sa.iam, sa.region, false, true, false),
 			middlewares.VerifyChecksums(false, true, false),
-			middlewares.ApplyBucketCORS(sa.be, sa.corsAllowOrigin),
+			sa.cors.applyBucket(),
 			middlewares.ParseAcl(sa.be),
 		))
 	bucketRouter.Put("",
@@ -221,7 +340,7 @@ func (sa *S3ApiRouter) Init() {
 			middlewares.VerifyPresignedV4Signature(sa.root, sa.iam, sa.region, false),
 			middlewares.VerifyV4Signature(sa.root, sa.iam, sa.region, false, true, false),
 			middlewares.VerifyChecksums(false, true, false),
-			middlewares.ApplyBucketCORS(sa.be, sa.corsAllowOrigin),
+			sa.cors.applyBucket(),
 			middlewares.ParseAcl(sa.be),
 		))
 	bucketRouter.Put("",
@@ -235,7 +354,7 @@ func (sa *S3ApiRouter) Init() {
 			middlewares.VerifyPresignedV4Signature(sa.root, sa.iam, sa.region, false),
 			middlewares.VerifyV4Signature(sa.root, sa.iam, sa.region, false, true, false),
 			middlewares.VerifyChecksums(false, true, true),
-			middlewares.ApplyBucketCORS(sa.be, sa.corsAllowOrigin),
+			sa.cors.applyBucket(),
 			middlewares.ParseAcl(sa.be),
 		))
 	bucketRouter.Put("",
@@ -249,7 +368,7 @@ func (sa *S3ApiRouter) Init() {
 			middlewares.VerifyPresignedV4Signature(sa.root, sa.iam, sa.region, false),
 			middlewares.VerifyV4Signature(sa.root, sa.iam, sa.region, false, true, false),
 			middlewares.VerifyChecksums(false, true, true),
-			middlewares.ApplyBucketCORS(sa.be, sa.corsAllowOrigin),
+			sa.cors.applyBucket(),
 			middlewares.ParseAcl(sa.be),
 		))
 	bucketRouter.Put("",
@@ -263,7 +382,7 @@ func (sa *S3ApiRouter) Init() {
 			middlewares.VerifyPresignedV4Signature(sa.root, sa.iam, sa.region, false),
 			middlewares.VerifyV4Signature(sa.root, sa.iam, sa.region, false, true, false),
 			middlewares.VerifyChecksums(false, false, false),
-			middlewares.ApplyBucketCORS(sa.be, sa.corsAllowOrigin),
+			sa.cors.applyBucket(),
 			middlewares.ParseAcl(sa.be),
 		))
 	bucketRouter.Put("",
@@ -277,7 +396,7 @@ func (sa *S3ApiRouter) Init() {
 			middlewares.VerifyPresignedV4Signature(sa.root, sa.iam, sa.region, false),
 			middlewares.VerifyV4Signature(sa.root, sa.iam, sa.region, false, true, false),
 			middlewares.VerifyChecksums(false, false, false),
-			middlewares.ApplyBucketCORS(sa.be, sa.corsAllowOrigin),
+			sa.cors.applyBucket(),
 			middlewares.ParseAcl(sa.be),
 		))
 	bucketRouter.Put("",
@@ -400,7 +519,7 @@ func (sa *S3ApiRouter) Init() {
 	bucketRouter.Put("",
 		middlewares.MatchQueryArgs("publicAccessBlock"),
 		controllers.ProcessHandlers(
-			ctrl.HandleErrorRoute(s3err.GetAPIError(s3err.ErrNotImplemented)),
+			ctrl.PutPublicAccessBlock,
 			metrics.ActionPutPublicAccessBlock,
 			services,
 			middlewares.BucketObjectNameValidator(),
@@ -459,7 +578,7 @@ func (sa *S3ApiRouter) Init() {
 			middlewares.VerifyPresignedV4Signature(sa.root, sa.iam, sa.region, false),
 			middlewares.VerifyV4Signature(sa.root, sa.iam, sa.region, false, true, false),
 			middlewares.VerifyChecksums(false, false, false),
-			middlewares.ApplyBucketCORS(sa.be, sa.corsAllowOrigin),
+			sa.cors.applyBucket(),
 		))
 
 	// HeadBucket action
@@ -478,7 +597,7 @@ func (sa *S3ApiRouter) Init() {
 			middlewares.AuthorizePublicBucketAccess(sa.be, metrics.ActionHeadBucket, auth.ListBucketAction, auth.PermissionRead, sa.region, false),
 			middlewares.VerifyPresignedV4Signature(sa.root, sa.iam, sa.region, false),
 			middlewares.VerifyV4Signature(sa.root, sa.iam, sa.region, false, false, false),
-			middlewares.ApplyBucketCORS(sa.be, sa.corsAllowOrigin),
+			sa.cors.applyBucket(),
 			middlewares.ParseAcl(sa.be),
 		))
 
@@ -499,7 +618,7 @@ func (sa *S3ApiRouter) Init() {
 			middlewares.AuthorizePublicBucketAccess(sa.be, metrics.ActionDeleteBucketTagging, auth.PutBucketTaggingAction, auth.PermissionWrite, sa.region, false),
 			middlewares.VerifyPresignedV4Signature(sa.root, sa.iam, sa.region, false),
 			middlewares.VerifyV4Signature(sa.root, sa.iam, sa.region, false, true, false),
-			middlewares.ApplyBucketCORS(sa.be, sa.corsAllowOrigin),
+			sa.cors.applyBucket(),
 			middlewares.ParseAcl(sa.be),
 		))
 	bucketRouter.Delete("",
@@ -512,7 +631,7 @@ func (sa *S3ApiRouter) Init() {
 			middlewares.AuthorizePublicBucketAccess(sa.be, metrics.ActionDeleteBucketOwnershipControls, auth.PutBucketOwnershipControlsAction, auth.PermissionWrite, sa.region, false),
 			middlewares.VerifyPresignedV4Signature(sa.root, sa.iam, sa.region, false),
 			middlewares.VerifyV4Signature(sa.root, sa.iam, sa.region, false, true, false),
-			middlewares.ApplyBucketCORS(sa.be, sa.corsAllowOrigin),
+			sa.cors.applyBucket(),
 			middlewares.ParseAcl(sa.be),
 		))
 	bucketRouter.Delete("",
@@ -525,7 +644,7 @@ func (sa *S3ApiRouter) Init() {
 			middlewares.AuthorizePublicBucketAccess(sa.be, metrics.ActionDeleteBucketPolicy, auth.PutBucketPolicyAction, auth.PermissionWrite, sa.region, false),
 			middlewares.VerifyPresignedV4Signature(sa.root, sa.iam, sa.region, false),
 			middlewares.VerifyV4Signature(sa.root, sa.iam, sa.region, false, true, false),
-			middlewares.ApplyBucketCORS(sa.be, sa.corsAllowOrigin),
+			sa.cors.applyBucket(),
 			middlewares.ParseAcl(sa.be),
 		))
 	bucketRouter.Delete("",
@@ -538,7 +657,7 @@ func (sa *S3ApiRouter) Init() {
 			middlewares.AuthorizePublicBucketAccess(sa.be, metrics.ActionDeleteBucketCors, auth.PutBucketCorsAction, auth.PermissionWrite, sa.region, false),
 			middlewares.VerifyPresignedV4Signature(sa.root, sa.iam, sa.region, false),
 			middlewares.VerifyV4Signature(sa.root, sa.iam, sa.region, false, true, false),
-			middlewares.ApplyBucketCORS(sa.be, sa.corsAllowOrigin),
+			sa.cors.applyBucket(),
 			middlewares.ParseAcl(sa.be),
 		))
 	bucketRouter.Delete("",
@@ -635,7 +754,7 @@ func (sa *S3ApiRouter) Init() {
 	bucketRouter.Delete("",
 		middlewares.MatchQueryArgs("publicAccessBlock"),
 		controllers.ProcessHandlers(
-			ctrl.HandleErrorRoute(s3err.GetAPIError(s3err.ErrNotImplemented)),
+			ctrl.DeletePublicAccessBlock,
 			metrics.ActionDeletePublicAccessBlock,
 			services,
 			middlewares.BucketObjectNameValidator(),
@@ -667,7 +786,7 @@ func (sa *S3ApiRouter) Init() {
 			middlewares.AuthorizePublicBucketAccess(sa.be, metrics.ActionDeleteBucket, auth.DeleteBucketAction, auth.PermissionWrite, sa.region, false),
 			middlewares.VerifyPresignedV4Signature(sa.root, sa.iam, sa.region, false),
 			middlewares.VerifyV4Signature(sa.root, sa.iam, sa.region, false, true, false),
-			middlewares.ApplyBucketCORS(sa.be, sa.corsAllowOrigin),
+			sa.cors.applyBucket(),
 			middlewares.ParseAcl(sa.be),
 		))
 
@@ -688,7 +807,7 @@ func (sa *S3ApiRouter) Init() {
 			middlewares.AuthorizePublicBucketAccess(sa.be, metrics.ActionGetBucketLocation, auth.GetBucketLocationAction, auth.PermissionRead, sa.region, false),
 			middlewares.VerifyPresignedV4Signature(sa.root, sa.iam, sa.region, false),
 			middlewares.VerifyV4Signature(sa.root, sa.iam, sa.region, false, true, false),
-			middlewares.ApplyBucketCORS(sa.be, sa.corsAllowOrigin),
+			sa.cors.applyBucket(),
 			middlewares.ParseAcl(sa.be),
 		),
 	)
@@ -702,7 +821,7 @@ func (sa *S3ApiRouter) Init() {
 			middlewares.AuthorizePublicBucketAccess(sa.be, metrics.ActionGetBucketTagging, auth.GetBucketTaggingAction, auth.PermissionRead, sa.region, false),
 			middlewares.VerifyPresignedV4Signature(sa.root, sa.iam, sa.region, false),
 			middlewares.VerifyV4Signature(sa.root, sa.iam, sa.region, false, true, false),
-			middlewares.ApplyBucketCORS(sa.be, sa.corsAllowOrigin),
+			sa.cors.applyBucket(),
 			middlewares.ParseAcl(sa.be),
 		))
 	bucketRouter.Get("",
@@ -715,7 +834,7 @@ func (sa *S3ApiRouter) Init() {
 			middlewares.AuthorizePublicBucketAccess(sa.be, metrics.ActionGetBucketOwnershipControls, auth.GetBucketOwnershipControlsAction, auth.PermissionRead, sa.region, false),
 			middlewares.VerifyPresignedV4Signature(sa.root, sa.iam, sa.region, false),
 			middlewares.VerifyV4Signature(sa.root, sa.iam, sa.region, false, true, false),
-			middlewares.ApplyBucketCORS(sa.be, sa.corsAllowOrigin),
+			sa.cors.applyBucket(),
 			middlewares.ParseAcl(sa.be),
 		))
 	bucketRouter.Get("",
@@ -728,7 +847,7 @@ func (sa *S3ApiRouter) Init() {
 			middlewares.AuthorizePublicBucketAccess(sa.be, metrics.ActionGetBucketVersioning, auth.GetBucketVersioningAction, auth.PermissionRead, sa.region, false),
 			middlewares.VerifyPresignedV4Signature(sa.root, sa.iam, sa.region, false),
 			middlewares.VerifyV4Signature(sa.root, sa.iam, sa.region, false, true, false),
-			middlewares.ApplyBucketCORS(sa.be, sa.corsAllowOrigin),
+			sa.cors.applyBucket(),
 			middlewares.ParseAcl(sa.be),
 		))
 	bucketRouter.Get("",
@@ -741,7 +860,7 @@ func (sa *S3ApiRouter) Init() {
 			middlewares.AuthorizePublicBucketAccess(sa.be, metrics.ActionGetBucketPolicy, auth.GetBucketPolicyAction, auth.PermissionRead, sa.region, false),
 			middlewares.VerifyPresignedV4Signature(sa.root, sa.iam, sa.region, false),
 			middlewares.VerifyV4Signature(sa.root, sa.iam, sa.region, false, true, false),
-			middlewares.ApplyBucketCORS(sa.be, sa.corsAllowOrigin),
+			sa.cors.applyBucket(),
 			middlewares.ParseAcl(sa.be),
 		))
 	bucketRouter.Get("",
@@ -754,7 +873,7 @@ func (sa *S3ApiRouter) Init() {
 			middlewares.AuthorizePublicBucketAccess(sa.be, metrics.ActionGetBucketCors, auth.GetBucketCorsAction, auth.PermissionRead, sa.region, false),
 			middlewares.VerifyPresignedV4Signature(sa.root, sa.iam, sa.region, false),
 			middlewares.VerifyV4Signature(sa.root, sa.iam, sa.region, false, true, false),
-			middlewares.ApplyBucketCORS(sa.be, sa.corsAllowOrigin),
+			sa.cors.applyBucket(),
 			middlewares.ParseAcl(sa.be),
 		))
 	bucketRouter.Get("",
@@ -767,7 +886,7 @@ func (sa *S3ApiRouter) Init() {
 			middlewares.AuthorizePublicBucketAccess(sa.be, metrics.ActionGetObjectLockConfiguration, auth.GetBucketObjectLockConfigurationAction, auth.PermissionRead, sa.region, false),
 			middlewares.VerifyPresignedV4Signature(sa.root, sa.iam, sa.region, false),
 			middlewares.VerifyV4Signature(sa.root, sa.iam, sa.region, false, true, false),
-			middlewares.ApplyBucketCORS(sa.be, sa.corsAllowOrigin),
+			sa.cors.applyBucket(),
 			middlewares.ParseAcl(sa.be),
 		))
 	bucketRouter.Get("",
@@ -780,7 +899,7 @@ func (sa *S3ApiRouter) Init() {
 			middlewares.AuthorizePublicBucketAccess(sa.be, metrics.ActionGetBucketAcl, auth.GetBucketAclAction, auth.PermissionReadAcp, sa.region, false),
 			middlewares.VerifyPresignedV4Signature(sa.root, sa.iam, sa.region, false),
 			middlewares.VerifyV4Signature(sa.root, sa.iam, sa.region, false, false, false),
-			middlewares.ApplyBucketCORS(sa.be, sa.corsAllowOrigin),
+			sa.cors.applyBucket(),
 			middlewares.ParseAcl(sa.be),
 		))
 	bucketRouter.Get("",
@@ -793,7 +912,7 @@ func (sa *S3ApiRouter) Init() {
 			middlewares.AuthorizePublicBucketAccess(sa.be, metrics.ActionListMultipartUploads, auth.ListBucketMultipartUploadsAction, auth.PermissionRead, sa.region, false),
 			middlewares.VerifyPresignedV4Signature(sa.root, sa.iam, sa.region, false),
 			middlewares.VerifyV4Signature(sa.root, sa.iam, sa.region, false, true, false),
-			middlewares.ApplyBucketCORS(sa.be, sa.corsAllowOrigin),
+			sa.cors.applyBucket(),
 			middlewares.ParseAcl(sa.be),
 		))
 	bucketRouter.Get("",
@@ -806,7 +925,7 @@ func (sa *S3ApiRouter) Init() {
 			middlewares.AuthorizePublicBucketAccess(sa.be, metrics.ActionListObjectVersions, auth.ListBucketVersionsAction, auth.PermissionRead, sa.region, false),
 			middlewares.VerifyPresignedV4Signature(sa.root, sa.iam, sa.region, false),
 			middlewares.VerifyV4Signature(sa.root, sa.iam, sa.region, false, true, false),
-			middlewares.ApplyBucketCORS(sa.be, sa.corsAllowOrigin),
+			sa.cors.applyBucket(),
 			middlewares.ParseAcl(sa.be),
 		))
 	bucketRouter.Get("",
@@ -819,7 +938,7 @@ func (sa *S3ApiRouter) Init() {
 			middlewares.AuthorizePublicBucketAccess(sa.be, metrics.ActionGetBucketPolicyStatus, auth.GetBucketPolicyStatusAction, auth.PermissionRead, sa.region, false),
 			middlewares.VerifyPresignedV4Signature(sa.root, sa.iam, sa.region, false),
 			middlewares.VerifyV4Signature(sa.root, sa.iam, sa.region, false, true, false),
-			middlewares.ApplyBucketCORS(sa.be, sa.corsAllowOrigin),
+			sa.cors.applyBucket(),
 			middlewares.ParseAcl(sa.be),
 		))
 	bucketRouter.Get("",
@@ -994,7 +1113,7 @@ func (sa *S3ApiRouter) Init() {
 	bucketRouter.Get("",
 		middlewares.MatchQueryArgs("publicAccessBlock"),
 		controllers.ProcessHandlers(
-			ctrl.HandleErrorRoute(s3err.GetAPIError(s3err.ErrNotImplemented)),
+			ctrl.GetPublicAccessBlock,
 			metrics.ActionGetPublicAccessBlock,
 			services,
 			middlewares.BucketObjectNameValidator(),
@@ -1053,7 +1172,7 @@ func (sa *S3ApiRouter) Init() {
 			middlewares.AuthorizePublicBucketAccess(sa.be, metrics.ActionListObjectsV2, auth.ListBucketAction, auth.PermissionRead, sa.region, false),
 			middlewares.VerifyPresignedV4Signature(sa.root, sa.iam, sa.region, false),
 			middlewares.VerifyV4Signature(sa.root, sa.iam, sa.region, false, true, false),
-			middlewares.ApplyBucketCORS(sa.be, sa.corsAllowOrigin),
+			sa.cors.applyBucket(),
 			middlewares.ParseAcl(sa.be),
 		))
 	bucketRouter.Get("",
@@ -1065,7 +1184,7 @@ func (sa *S3ApiRouter) Init() {
 			middlewares.AuthorizePublicBucketAccess(sa.be, metrics.ActionListObjects, auth.ListBucketAction, auth.PermissionRead, sa.region, false),
 			middlewares.VerifyPresignedV4Signature(sa.root, sa.iam, sa.region, false),
 			middlewares.VerifyV4Signature(sa.root, sa.iam, sa.region, false, true, false),
-			middlewares.ApplyBucketCORS(sa.be, sa.corsAllowOrigin),
+			sa.cors.applyBucket(),
 			middlewares.ParseAcl(sa.be),
 		))
 
@@ -1088,7 +1207,7 @@ func (sa *S3ApiRouter) Init() {
 			middlewares.VerifyPresignedV4Signature(sa.root, sa.iam, sa.region, false),
 			middlewares.VerifyV4Signature(sa.root, sa.iam, sa.region, false, true, false),
 			middlewares.VerifyChecksums(false, true, true),
-			middlewares.ApplyBucketCORS(sa.be, sa.corsAllowOrigin),
+			sa.cors.applyBucket(),
 			middlewares.ParseAcl(sa.be),
 		))
 
@@ -1108,7 +1227,7 @@ func (sa *S3ApiRouter) Init() {
 			middlewares.AuthorizePublicBucketAccess(sa.be, metrics.ActionHeadObject, auth.GetObjectAction, auth.PermissionRead, sa.region, false),
 			middlewares.VerifyPresignedV4Signature(sa.root, sa.iam, sa.region, false),
 			middlewares.VerifyV4Signature(sa.root, sa.iam, sa.region, false, false, false),
-			middlewares.ApplyBucketCORS(sa.be, sa.corsAllowOrigin),
+			sa.cors.applyBucket(),
 			middlewares.ParseAcl(sa.be),
 		))
 
@@ -1142,7 +1261,7 @@ func (sa *S3ApiRouter) Init() {
 			middlewares.AuthorizePublicBucketAccess(sa.be, metrics.ActionGetObjectTagging, auth.GetObjectTaggingAction, auth.PermissionRead, sa.region, false),
 			middlewares.VerifyPresignedV4Signature(sa.root, sa.iam, sa.region, false),
 			middlewares.VerifyV4Signature(sa.root, sa.iam, sa.region, false, true, false),
-			middlewares.ApplyBucketCORS(sa.be, sa.corsAllowOrigin),
+			sa.cors.applyBucket(),
 			middlewares.ParseAcl(sa.be),
 		))
 	objectRouter.Get("",
@@ -1155,7 +1274,7 @@ func (sa *S3ApiRouter) Init() {
 			middlewares.AuthorizePublicBucketAccess(sa.be, metrics.ActionGetObjectRetention, auth.GetObjectRetentionAction, auth.PermissionRead, sa.region, false),
 			middlewares.VerifyPresignedV4Signature(sa.root, sa.iam, sa.region, false),
 			middlewares.VerifyV4Signature(sa.root, sa.iam, sa.region, false, true, false),
-			middlewares.ApplyBucketCORS(sa.be, sa.corsAllowOrigin),
+			sa.cors.applyBucket(),
 			middlewares.ParseAcl(sa.be),
 		))
 	objectRouter.Get("",
@@ -1168,7 +1287,7 @@ func (sa *S3ApiRouter) Init() {
 			middlewares.AuthorizePublicBucketAccess(sa.be, metrics.ActionGetObjectLegalHold, auth.GetObjectLegalHoldAction, auth.PermissionRead, sa.region, false),
 			middlewares.VerifyPresignedV4Signature(sa.root, sa.iam, sa.region, false),
 			middlewares.VerifyV4Signature(sa.root, sa.iam, sa.region, false, true, false),
-			middlewares.ApplyBucketCORS(sa.be, sa.corsAllowOrigin),
+			sa.cors.applyBucket(),
 			middlewares.ParseAcl(sa.be),
 		))
 	objectRouter.Get("",
@@ -1181,7 +1300,7 @@ func (sa *S3ApiRouter) Init() {
 			middlewares.AuthorizePublicBucketAccess(sa.be, metrics.ActionGetObjectAcl, auth.GetObjectAclAction, auth.PermissionReadAcp, sa.region, false),
 			middlewares.VerifyPresignedV4Signature(sa.root, sa.iam, sa.region, false),
 			middlewares.VerifyV4Signature(sa.root, sa.iam, sa.region, false, true, false),
-			middlewares.ApplyBucketCORS(sa.be, sa.corsAllowOrigin),
+			sa.cors.applyBucket(),
 			middlewares.ParseAcl(sa.be),
 		))
 	objectRouter.Get("",
@@ -1194,7 +1313,7 @@ func (sa *S3ApiRouter) Init() {
 			middlewares.AuthorizePublicBucketAccess(sa.be, metrics.ActionGetObjectAttributes, auth.GetObjectAttributesAction, auth.PermissionRead, sa.region, false),
 			middlewares.VerifyPresignedV4Signature(sa.root, sa.iam, sa.region, false),
 			middlewares.VerifyV4Signature(sa.root, sa.iam, sa.region, false, true, false),
-			middlewares.ApplyBucketCORS(sa.be, sa.corsAllowOrigin),
+			sa.cors.applyBucket(),
 			middlewares.ParseAcl(sa.be),
 		))
 	objectRouter.Get("",
@@ -1207,7 +1326,7 @@ func (sa *S3ApiRouter) Init() {
 			middlewares.AuthorizePublicBucketAccess(sa.be, metrics.ActionListParts, auth.ListMultipartUploadPartsAction, auth.PermissionRead, sa.region, false),
 			middlewares.VerifyPresignedV4Signature(sa.root, sa.iam, sa.region, false),
 			middlewares.VerifyV4Signature(sa.root, sa.iam, sa.region, false, true, false),
-			middlewares.ApplyBucketCORS(sa.be, sa.corsAllowOrigin),
+			sa.cors.applyBucket(),
 			middlewares.ParseAcl(sa.be),
 		))
 	objectRouter.Get("",
@@ -1219,7 +1338,7 @@ func (sa *S3ApiRouter) Init() {
 			middlewares.AuthorizePublicBucketAccess(sa.be, metrics.ActionGetObject, auth.GetObjectAction, auth.PermissionRead, sa.region, false),
 			middlewares.VerifyPresignedV4Signature(sa.root, sa.iam, sa.region, false),
 			middlewares.VerifyV4Signature(sa.root, sa.iam, sa.region, false, true, false),
-			middlewares.ApplyBucketCORS(sa.be, sa.corsAllowOrigin),
+			sa.cors.applyBucket(),
 			middlewares.ParseAcl(sa.be),
 		))
 
@@ -1241,7 +1360,7 @@ func (sa *S3ApiRouter) Init() {
 			middlewares.AuthorizePublicBucketAccess(sa.be, metrics.ActionDeleteObjectTagging, auth.DeleteObjectTaggingAction, auth.PermissionWrite, sa.region, false),
 			middlewares.VerifyPresignedV4Signature(sa.root, sa.iam, sa.region, false),
 			middlewares.VerifyV4Signature(sa.root, sa.iam, sa.region, false, true, false),
-			middlewares.ApplyBucketCORS(sa.be, sa.corsAllowOrigin),
+			sa.cors.applyBucket(),
 			middlewares.ParseAcl(sa.be),
 		))
 	objectRouter.Delete("",
@@ -1254,7 +1373,7 @@ func (sa *S3ApiRouter) Init() {
 			middlewares.AuthorizePublicBucketAccess(sa.be, metrics.ActionAbortMultipartUpload, auth.AbortMultipartUploadAction, auth.PermissionWrite, sa.region, false),
 			middlewares.VerifyPresignedV4Signature(sa.root, sa.iam, sa.region, false),
 			middlewares.VerifyV4Signature(sa.root, sa.iam, sa.region, false, true, false),
-			middlewares.ApplyBucketCORS(sa.be, sa.corsAllowOrigin),
+			sa.cors.applyBucket(),
 			middlewares.ParseAcl(sa.be),
 		))
 	objectRouter.Delete("",
@@ -1266,7 +1385,7 @@ func (sa *S3ApiRouter) Init() {
 			middlewares.AuthorizePublicBucketAccess(sa.be, metrics.ActionDeleteObject, auth.DeleteObjectAction, auth.PermissionWrite, sa.region, false),
 			middlewares.VerifyPresignedV4Signature(sa.root, sa.iam, sa.region, false),
 			middlewares.VerifyV4Signature(sa.root, sa.iam, sa.region, false, true, false),
-			middlewares.ApplyBucketCORS(sa.be, sa.corsAllowOrigin),
+			sa.cors.applyBucket(),
 			middlewares.ParseAcl(sa.be),
 		))
 
@@ -1290,7 +1409,7 @@ func (sa *S3ApiRouter) Init() {
 			middlewares.VerifyPresignedV4Signature(sa.root, sa.iam, sa.region, false),
 			middlewares.VerifyV4Signature(sa.root, sa.iam, sa.region, false, true, false),
 			middlewares.VerifyChecksums(false, false, false),
-			middlewares.ApplyBucketCORS(sa.be, sa.corsAllowOrigin),
+			sa.cors.applyBucket(),
 			middlewares.ParseAcl(sa.be),
 		))
 	objectRouter.Post("",
@@ -1305,7 +1424,7 @@ func (sa *S3ApiRouter) Init() {
 			middlewares.VerifyPresignedV4Signature(sa.root, sa.iam, sa.region, false),
 			middlewares.VerifyV4Signature(sa.root, sa.iam, sa.region, false, true, false),
 			middlewares.VerifyChecksums(false, false, false),
-			middlewares.ApplyBucketCORS(sa.be, sa.corsAllowOrigin),
+			sa.cors.applyBucket(),
 			middlewares.ParseAcl(sa.be),
 		))
 	objectRouter.Post("",
@@ -1318,7 +1437,7 @@ func (sa *S3ApiRouter) Init() {
 			middlewares.AuthorizePublicBucketAccess(sa.be, metrics.ActionCompleteMultipartUpload, auth.PutObjectAction, auth.PermissionWrite, sa.region, false),
 			middlewares.VerifyPresignedV4Signature(sa.root, sa.iam, sa.region, false),
 			middlewares.VerifyV4Signature(sa.root, sa.iam, sa.region, false, true, false),
-			middlewares.ApplyBucketCORS(sa.be, sa.corsAllowOrigin),
+			sa.cors.applyBucket(),
 			middlewares.ParseAcl(sa.be),
 		))
 	objectRouter.Post("",
@@ -1331,7 +1450,7 @@ func (sa *S3ApiRouter) Init() {
 			middlewares.AuthorizePublicBucketAccess(sa.be, metrics.ActionCreateMultipartUpload, auth.PutObjectAction, auth.PermissionWrite, sa.region, false),
 			middlewares.VerifyPresignedV4Signature(sa.root, sa.iam, sa.region, false),
 			middlewares.VerifyV4Signature(sa.root, sa.iam, sa.region, false, true, false),
-			middlewares.ApplyBucketCORS(sa.be, sa.corsAllowOrigin),
+			sa.cors.applyBucket(),
 			middlewares.ParseAcl(sa.be),
 		))
 
@@ -1343,7 +1462,7 @@ func (sa *S3ApiRouter) Init() {
 			metrics.ActionPutObjectTagging,
 			services,
 			middlewares.BucketObjectNameValidator(),
-			middlewares.ApplyBucketCORS(sa.be, sa.corsAllowOrigin),
+			sa.cors.applyBucket(),
 			middlewares.AuthorizePublicBucketAccess(sa.be, metrics.ActionPutObjectTagging, auth.PutObjectTaggingAction, auth.PermissionWrite, sa.region, false),
 			middlewares.VerifyPresignedV4Signature(sa.root, sa.iam, sa.region, false),
 			middlewares.VerifyV4Signature(sa.root, sa.iam, sa.region, false, true, false),
@@ -1361,7 +1480,7 @@ func (sa *S3ApiRouter) Init() {
 			middlewares.VerifyPresignedV4Signature(sa.root, sa.iam, sa.region, false),
 			middlewares.VerifyV4Signature(sa.root, sa.iam, sa.region, false, true, false),
 			middlewares.VerifyChecksums(false, false, true),
-			middlewares.ApplyBucketCORS(sa.be, sa.corsAllowOrigin),
+			sa.cors.applyBucket(),
 			middlewares.ParseAcl(sa.be),
 		))
 	objectRouter.Put("",
@@ -1375,7 +1494,7 @@ func (sa *S3ApiRouter) Init() {
 			middlewares.VerifyPresignedV4Signature(sa.root, sa.iam, sa.region, false),
 			middlewares.VerifyV4Signature(sa.root, sa.iam, sa.region, false, true, false),
 			middlewares.VerifyChecksums(false, false, true),
-			middlewares.ApplyBucketCORS(sa.be, sa.corsAllowOrigin),
+			sa.cors.applyBucket(),
 			middlewares.ParseAcl(sa.be),
 		))
 	objectRouter.Put("",
@@ -1389,7 +1508,7 @@ func (sa *S3ApiRouter) Init() {
 			middlewares.VerifyPresignedV4Signature(sa.root, sa.iam, sa.region, false),
 			middlewares.VerifyV4Signature(sa.root, sa.iam, sa.region, false, true, false),
 			middlewares.VerifyChecksums(false, false, false),
-			middlewares.ApplyBucketCORS(sa.be, sa.corsAllowOrigin),
+			sa.cors.applyBucket(),
 			middlewares.ParseAcl(sa.be),
 		))
 	objectRouter.Put("",
@@ -1403,7 +1522,7 @@ func (sa *S3ApiRouter) Init() {
 			middlewares.AuthorizePublicBucketAccess(sa.be, metrics.ActionUploadPartCopy, auth.PutObjectAction, auth.PermissionWrite, sa.region, false),
 			middlewares.VerifyPresignedV4Signature(sa.root, sa.iam, sa.region, false),
 			middlewares.VerifyV4Signature(sa.root, sa.iam, sa.region, false, true, false),
-			middlewares.ApplyBucketCORS(sa.be, sa.corsAllowOrigin),
+			sa.cors.applyBucket(),
 			middlewares.ParseAcl(sa.be),
 		))
 	objectRouter.Put("",
@@ -1417,7 +1536,7 @@ func (sa *S3ApiRouter) Init() {
 			middlewares.VerifyPresignedV4Signature(sa.root, sa.iam, sa.region, true),
 			middlewares.VerifyV4Signature(sa.root, sa.iam, sa.region, true, true, false),
 			middlewares.VerifyChecksums(true, false, false),
-			middlewares.ApplyBucketCORS(sa.be, sa.corsAllowOrigin),
+			sa.cors.applyBucket(),
 			middlewares.ParseAcl(sa.be),
 		))
 
@@ -1439,7 +1558,7 @@ func (sa *S3ApiRouter) Init() {
 			metrics.ActionCopyObject,
 			services,
 			middlewares.BucketObjectNameValidator(),
-			middlewares.ApplyBucketCORS(sa.be, sa.corsAllowOrigin),
+			sa.cors.applyBucket(),
 			middlewares.AuthorizePublicBucketAccess(sa.be, metrics.ActionCopyObject, auth.PutObjectAction, auth.PermissionWrite, sa.region, false),
 			middlewares.VerifyPresignedV4Signature(sa.root, sa.iam, sa.region, false),
 			middlewares.VerifyV4Signature(sa.root, sa.iam, sa.region, false, true, false),
@@ -1451,7 +1570,7 @@ func (sa *S3ApiRouter) Init() {
 			metrics.ActionPutObject,
 			services,
 			middlewares.BucketObjectNameValidator(),
-			middlewares.ApplyBucketCORS(sa.be, sa.corsAllowOrigin),
+			sa.cors.applyBucket(),
 			middlewares.AuthorizePublicBucketAccess(sa.be, metrics.ActionPutObject, auth.PutObjectAction, auth.PermissionWrite, sa.region, true),
 			middlewares.VerifyPresignedV4Signature(sa.root, sa.iam, sa.region, true),
 			middlewares.VerifyV4Signature(sa.root, sa.iam, sa.region, true, true, false),
@@ -1460,7 +1579,7 @@ func (sa *S3ApiRouter) Init() {
 		))
 
 	sa.app.Options("/:bucket",
-		middlewares.ApplyBucketCORSPreflightFallback(sa.be, sa.corsAllowOrigin),
+		sa.cors.applyBucketPreflightFallback(),
 		controllers.ProcessHandlers(ctrl.CORSOptions, metrics.ActionOptions, services,
 			middlewares.BucketObjectNameValidator(),
 			middlewares.ParseAcl(sa.be),
@@ -1468,7 +1587,7 @@ func (sa *S3ApiRouter) Init() {
 	)
 
 	sa.app.Options("/:bucket/*",
-		middlewares.ApplyBucketCORSPreflightFallback(sa.be, sa.corsAllowOrigin),
+		sa.cors.applyBucketPreflightFallback(),
 		controllers.ProcessHandlers(ctrl.CORSOptions, metrics.ActionOptions, services,
 			middlewares.BucketObjectNameValidator(),
 			middlewares.ParseAcl(sa.be),
@@ -1478,3 +1597,15 @@ func (sa *S3ApiRouter) Init() {
 	// Return MethodNotAllowed for all the unmatched routes
 	sa.app.All("*", controllers.ProcessHandlers(ctrl.HandleErrorRoute(s3err.GetAPIError(s3err.ErrMethodNotAllowed)), metrics.ActionUndetected, services))
 }
+
+// SetReadOnly enables or disables the read-only mode of the
+// initialized router
+func (sa *S3ApiRouter) SetReadOnly(readonly bool) {
+	sa.readonly.Store(readonly)
+}
+
+// SetCORSAllowOrigin changes the default allowed CORS origin
+// of the initialized router
+func (sa *S3ApiRouter) SetCORSAllowOrigin(origin string) {
+	sa.cors.setOrigin(origin)
+}
@@ -320,3 +320,33 @@ func TestS3ApiRouter_OptionsWithBucketCORS_NoDuplicateHeaders(t *testing.T) {
 		t.Error("Access-Control-Allow-Headers header is empty")
 	}
 }
+
+func TestS3ApiRouter_SetCORSAllowOrigin(t *testing.T) {
+	app := fiber.New()
+	router := &S3ApiRouter{
+		app:             app,
+		be:              backend.BackendUnsupported{},
+		iam:             &auth.IAMServiceInternal{},
+		region:          "us-east-1",
+		corsAllowOrigin: "https://example.com",
+	}
+	router.Init()
+
+	for _, origin := range []string{"https://reloaded.example.com", ""} {
+		router.SetCORSAllowOrigin(origin)
+
+		req, err := http.NewRequest(http.MethodGet, "/", nil)
+		if err != nil {
+			t.Fatalf("new request: %v", err)
+		}
+
+		resp, err := app.Test(req)
+		if err != nil {
+			t.Fatalf("app.Test: %v", err)
+		}
+
+		if got := resp.Header.Get("Access-Control-Allow-Origin"); got != origin {
+			t.Fatalf("expected Access-Control-Allow-Origin %q, got %q", origin, got)
+		}
+	}
+}
@@ -50,8 +50,11 @@ type S3ApiServer struct {
 	quiet            bool
 	keepAlive        bool
 	health           string
+	readinessCfg     *ReadinessConfig
+	readiness        *readiness
 	maxConnections   int
 	maxRequests      int
+	limiter          *middlewares.Limiter
 	webuiMountPrefix string
 	webuiSrvCfg      *webui.ServerConfig
 }
@@ -122,6 +125,13 @@ func New(
 			return ctx.SendStatus(http.StatusOK)
 		})
 	}
+	// Set up readiness endpoint if specified
+	if server.readinessCfg != nil {
+		cfg := *server.readinessCfg
+		cfg.Checks = append(readinessChecks(be, iam, evs), cfg.Checks...)
+		server.readiness = newReadiness(cfg)
+		app.Get(cfg.Path, server.readiness.handler)
+	}
 
 	// Set up WebUI on the S3 port if configured
 	if server.webuiSrvCfg != nil {
@@ -129,7 +139,8 @@ func New(
 	}
 
 	// initialize total requests cap limiter middleware
-	app.Use(middlewares.RateLimiter(server.maxRequests, mm, l))
+	server.limiter = middlewares.NewLimiter(server.maxRequests)
+	app.Use(server.limiter.Handler(mm, l))
 
 	// initilaze the default value setter middleware
 	app.Use(middlewares.SetDefaultValues(root, region))
@@ -166,13 +177,21 @@ func WithQuiet() Option {
 	return func(s *S3ApiServer) { s.quiet = true }
 }
 
-// WithHealth sets up a GET health endpoint
+// WithHealth sets up a GET health endpoint, the endpoint only reports
+// the gateway is running (liveness)
 func WithHealth(health string) Option {
 	return func(s *S3ApiServer) { s.health = health }
 }
 
+// WithReadiness sets up a GET readiness endpoint checking the backend,
+// IAM service and event sink are usable, the endpoint reports not ready
+// during the graceful shutdown drain
+func WithReadiness(cfg ReadinessConfig) Option {
+	return func(s *S3ApiServer) { s.readinessCfg = &cfg }
+}
+
 func WithReadOnly() Option {
-	return func(s *S3ApiServer) { s.Router.readonly = true }
+	return func(s *S3ApiServer) { s.Router.readonly.Store(true) }
 }
 
 // WithHostStyle enabled host-style bucket addressing on the server
@@ -218,6 +237,31 @@ func WithDisableACL() Option {
 	return func(s *S3ApiServer) { s.Router.disableACL = true }
 }
 
+// WithSigV2 enables the legacy AWS Signature Version 2 authentication
+// with the Authorization header and the query parameters
+func WithSigV2() Option {
+	return func(s *S3ApiServer) { s.Router.sigV2 = true }
+}
+
+// WithClientCertAuth authenticates the unsigned requests with a verified
+// mutual TLS client certificate as the account the certificate maps to.
+// The client CAs are set in the CertStorage of WithTLS.
+func WithClientCertAuth(mapper auth.CertAccountMapper) Option {
+	return func(s *S3ApiServer) { s.Router.certMapper = mapper }
+}
+
+// WithShareLinks serves the share links of the store on the S3 listener
+// and enables the share link admin apis
+func WithShareLinks(store auth.ShareStore) Option {
+	return func(s *S3ApiServer) { s.Router.shareStore = store }
+}
+
+// SetMaxRequests changes the hard limit for in-flight requests
+// of the running server
+func (sa *S3ApiServer) SetMaxRequests(maxRequests int) {
+	sa.limiter.SetLimit(maxRequests)
+}
+
 // ServeMultiPort creates listeners for multiple port specifications and serves
 // on all of them simultaneously. This supports listening on multiple ports and/or
 // addresses (e.g., [":7070", "localhost:8080", "0.0.0.0:9090"]).
@@ -234,7 +278,7 @@ func (sa *S3ApiServer) ServeMultiPort(ports []string) error {
 		var err error
 
 		if sa.CertStorage != nil {
-			ln, err = utils.NewMultiAddrTLSListener(sa.app.Config().Network, portSpec, sa.CertStorage.GetCertificate)
+			ln, err = utils.NewMultiAddrTLSConfigListener(sa.app.Config().Network, portSpec, sa.CertStorage.TLSConfig())
 		} else {
 			ln, err = utils.NewMultiAddrListener(sa.app.Config().Network, portSpec)
 		}
@@ -255,8 +299,12 @@ func (sa *S3ApiServer) ServeMultiPort(ports []string) error {
 	return sa.app.Listener(finalListener)
 }
 
-// ShutDown gracefully shuts down the server with a context timeout
+// ShutDown gracefully shuts down the server with a context timeout,
+// after the readiness endpoint reported not ready for the drain period
 func (sa *S3ApiServer) ShutDown() error {
+	if sa.readiness != nil {
+		sa.readiness.drain()
+	}
 	return sa.app.ShutdownWithTimeout(shutDownDuration)
 }
 
@@ -157,7 +157,9 @@ func IsStreamingPayload(str string) bool {
 	pt := payloadType(str)
 	return pt == payloadTypeStreamingUnsignedTrailer ||
 		pt == payloadTypeStreamingSigned ||
-		pt == payloadTypeStreamingSignedTrailer
+		pt == payloadTypeStreamingSignedTrailer ||
+		pt == payloadTypeStreamingEcdsa ||
+		pt == payloadTypeStreamingEcdsaTrailer
 }
 
 // ParseDecodedContentLength extracts and validates the
@@ -200,16 +202,28 @@ func NewChunkReader(ctx *fiber.Ctx, r io.Reader, authdata AuthData, secret strin
 		return nil, err
 	}
 
+	// the chunk signatures are signed with the algorithm of the seed
+	// signature
+	switch contentSha256 {
+	case payloadTypeStreamingSigned, payloadTypeStreamingSignedTrailer:
+		if authdata.Algorithm != algoHMAC {
+			debuglogger.Logf("%v chunk encoding with %v authorization", contentSha256, authdata.Algorithm)
+			return nil, s3err.GetAPIError(s3err.ErrSignatureDoesNotMatch)
+		}
+	case payloadTypeStreamingEcdsa, payloadTypeStreamingEcdsaTrailer:
+		if authdata.Algorithm != algoECDSA {
+			debuglogger.Logf("%v chunk encoding with %v authorization", contentSha256, authdata.Algorithm)
+			return nil, s3err.GetAPIError(s3err.ErrSignatureDoesNotMatch)
+		}
+	}
+
 	switch contentSha256 {
 	case payloadTypeStreamingUnsignedTrailer:
 		return NewUnsignedChunkReader(r, checksumType, cLength)
-	case payloadTypeStreamingSignedTrailer:
+	case payloadTypeStreamingSignedTrailer, payloadTypeStreamingEcdsaTrailer:
 		return NewSignedChunkReader(r, authdata, secret, date, checksumType, true, cLength)
-	case payloadTypeStreamingSigned:
+	case payloadTypeStreamingSigned, payloadTypeStreamingEcdsa:
 		return NewSignedChunkReader(r, authdata, secret, date, "", false, cLength)
-	// return not supported for:
-	// - STREAMING-AWS4-ECDSA-P256-SHA256-PAYLOAD
-	// - STREAMING-AWS4-ECDSA-P256-SHA256-PAYLOAD-TRAILER
 	default:
 		debuglogger.Logf("unsupported chunk reader algorithm: %v", contentSha256)
 		return nil, getPayloadTypeNotSupportedErr(contentSha256)
@@ -14,7 +14,20 @@
 
 package utils
 
-import "testing"
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/versity/versitygw/aws/signer/v4a"
+	"github.com/versity/versitygw/s3err"
+)
 
 func TestIsValidSh256PayloadHeader(t *testing.T) {
 	tests := []struct {
@@ -41,3 +54,98 @@ func TestIsValidSh256PayloadHeader(t *testing.T) {
 		})
 	}
 }
+
+// sigV4AChunkedBody builds the STREAMING-AWS4-ECDSA-P256-SHA256-PAYLOAD-TRAILER
+// body of the chunks with the sha256 trailing checksum, the signatures are
+// padded to 144 characters as sent by the sdks
+func sigV4AChunkedBody(t *testing.T, access, secret, seedSig string, date time.Time, chunks []string) string {
+	key, err := v4a.DeriveKey(access, secret)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	prefix := func(algo string) string {
+		return algo + "\n" + date.Format(iso8601Format) + "\n" + v4a.BuildCredentialScope(date, "s3")
+	}
+	sign := func(strToSign string) string {
+		sig, err := v4a.SignString(key, strToSign)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return sig
+	}
+	pad := func(sig string) string {
+		return sig + strings.Repeat("*", 144-len(sig))
+	}
+
+	var b strings.Builder
+	prevSig := seedSig
+	csum := sha256.New()
+	for _, chunk := range append(chunks, "") {
+		hsh := sha256.Sum256([]byte(chunk))
+		sig := sign(fmt.Sprintf("%s\n%s\n%s\n%s", prefix(streamPayloadEcdsaAlgo), prevSig, zeroLenSig, hex.EncodeToString(hsh[:])))
+		fmt.Fprintf(&b, "%x;chunk-signature=%s\r\n%s", len(chunk), pad(sig), chunk)
+		if chunk != "" {
+			b.WriteString("\r\n")
+		}
+		csum.Write([]byte(chunk))
+		prevSig = sig
+	}
+
+	trailer := fmt.Sprintf("%s:%s\n", checksumTypeSha256, base64.StdEncoding.EncodeToString(csum.Sum(nil)))
+	hsh := sha256.Sum256([]byte(trailer))
+	trailerSig := sign(fmt.Sprintf("%s\n%s\n%s", prefix(streamPayloadEcdsaTrailerAlgo), prevSig, hex.EncodeToString(hsh[:])))
+	fmt.Fprintf(&b, "%s\r\n%s%s\r\n\r\n", strings.TrimSuffix(trailer, "\n"), trailerSignatureHeader, pad(trailerSig))
+
+	return b.String()
+}
+
+func TestSigV4AChunkReader(t *testing.T) {
+	access, secret := "access", "secret"
+	date := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	seedSig := "3045022100a1b2"
+	chunks := []string{strings.Repeat("a", 8192), strings.Repeat("b", 100)}
+	data := strings.Join(chunks, "")
+	body := sigV4AChunkedBody(t, access, secret, seedSig, date, chunks)
+
+	auth := AuthData{
+		Algorithm: algoECDSA,
+		Access:    access,
+		Signature: seedSig,
+		RegionSet: "*",
+	}
+
+	tests := []struct {
+		name   string
+		body   string
+		secret string
+		err    error
+	}{
+		{"valid", body, secret, nil},
+		{"wrong secret", body, "other", s3err.GetAPIError(s3err.ErrSignatureDoesNotMatch)},
+		{"modified data", strings.Replace(body, "bbb", "bcb", 1), secret, s3err.GetAPIError(s3err.ErrSignatureDoesNotMatch)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cr, err := NewSignedChunkReader(strings.NewReader(tt.body), auth, tt.secret, date, checksumTypeSha256, true, int64(len(data)))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := io.ReadAll(cr)
+			if tt.err != nil {
+				var apiErr s3err.APIError
+				if !errors.As(err, &apiErr) || apiErr.Code != tt.err.(s3err.APIError).Code {
+					t.Fatalf("expected error %v, got %v", tt.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != data {
+				t.Errorf("expected %v bytes of object data, got %v", len(data), len(got))
+			}
+		})
+	}
+}
@@ -15,6 +15,8 @@
 package utils
 
 import (
+	"context"
+
 	"github.com/gofiber/fiber/v2"
 )
 
@@ -24,19 +26,21 @@ import (
 type ContextKey string
 
 const (
-	ContextKeyRegion         ContextKey = "region"
-	ContextKeyStartTime      ContextKey = "start-time"
-	ContextKeyIsRoot         ContextKey = "is-root"
-	ContextKeyRootAccessKey  ContextKey = "root-access-key"
-	ContextKeyAccount        ContextKey = "account"
-	ContextKeyAuthenticated  ContextKey = "authenticated"
-	ContextKeyPublicBucket   ContextKey = "public-bucket"
-	ContextKeyParsedAcl      ContextKey = "parsed-acl"
-	ContextKeySkipResBodyLog ContextKey = "skip-res-body-log"
-	ContextKeyBodyReader     ContextKey = "body-reader"
-	ContextKeySkip           ContextKey = "__skip"
-	ContextKeyStack          ContextKey = "stack"
-	ContextKeyBucketOwner    ContextKey = "bucket-owner"
+	ContextKeyRegion           ContextKey = "region"
+	ContextKeyStartTime        ContextKey = "start-time"
+	ContextKeyIsRoot           ContextKey = "is-root"
+	ContextKeyRootAccessKey    ContextKey = "root-access-key"
+	ContextKeyAccount          ContextKey = "account"
+	ContextKeyAuthenticated    ContextKey = "authenticated"
+	ContextKeyPublicBucket     ContextKey = "public-bucket"
+	ContextKeyParsedAcl        ContextKey = "parsed-acl"
+	ContextKeySkipResBodyLog   ContextKey = "skip-res-body-log"
+	ContextKeyBodyReader       ContextKey = "body-reader"
+	ContextKeySkip             ContextKey = "__skip"
+	ContextKeyStack            ContextKey = "stack"
+	ContextKeyBucketOwner      ContextKey = "bucket-owner"
+	ContextKeySignatureVersion ContextKey = "signature-version"
+	ContextKeyAuthType         ContextKey = "authentication-type"
 )
 
 func (ck ContextKey) Values() []ContextKey {
@@ -52,6 +56,8 @@ func (ck ContextKey) Values() []ContextKey {
 		ContextKeySkipResBodyLog,
 		ContextKeyBodyReader,
 		ContextKeyBucketOwner,
+		ContextKeySignatureVersion,
+		ContextKeyAuthType,
 	}
 }
 
@@ -71,3 +77,9 @@ func (ck ContextKey) Delete(ctx *fiber.Ctx) {
 func (ck ContextKey) Get(ctx *fiber.Ctx) any {
 	return ctx.Locals(string(ck))
 }
+
+// Value returns the context local from the request context passed
+// to the backends
+func (ck ContextKey) Value(ctx context.Context) any {
+	return ctx.Value(string(ck))
+}
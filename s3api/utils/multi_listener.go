@@ -151,8 +151,8 @@ func IsUnixSocketPath(addr string) bool {
 // AbsSocketPaths converts any relative UNIX socket paths in addrs to absolute
 // paths using the current working directory. Non-socket addresses (TCP/IP) and
 // abstract sockets ("@name") are returned unchanged. This should be called
-// early in program startup — before any backend that calls os.Chdir — so that
-// relative paths are resolved against the shell's working directory.
+// early in program startup so that relative paths are resolved against the
+// shell's working directory.
 func AbsSocketPaths(addrs []string) ([]string, error) {
 	result := make([]string, len(addrs))
 	for i, addr := range addrs {
@@ -329,11 +329,15 @@ func NewMultiAddrListener(network, address string) (net.Listener, error) {
 //     socket file is removed before binding.
 //   - "@name" — Linux abstract namespace socket; no file is created or removed.
 func NewMultiAddrTLSListener(network, address string, getCertificateFunc func(*tls.ClientHelloInfo) (*tls.Certificate, error)) (net.Listener, error) {
-	config := &tls.Config{
+	return NewMultiAddrTLSConfigListener(network, address, &tls.Config{
 		MinVersion:     tls.VersionTLS12,
 		GetCertificate: getCertificateFunc,
-	}
+	})
+}
 
+// NewMultiAddrTLSConfigListener is NewMultiAddrTLSListener with the full
+// TLS config of the listeners, e.g. for the client certificate verification.
+func NewMultiAddrTLSConfigListener(network, address string, config *tls.Config) (net.Listener, error) {
 	if IsUnixSocketPath(address) {
 		if !isAbstractSocket(address) {
 			if err := removeStaleSocket(address); err != nil {
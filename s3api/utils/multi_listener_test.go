@@ -15,9 +15,18 @@
 package utils
 
 import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
 	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
 	"io"
+	"math/big"
 	"net"
+	"os"
+	"path/filepath"
 	"testing"
 	"time"
 )
@@ -387,6 +396,130 @@ func TestNewMultiAddrTLSListener(t *testing.T) {
 	}
 }
 
+func TestNewMultiAddrTLSConfigListenerClientAuth(t *testing.T) {
+	dir := t.TempDir()
+	certFile := filepath.Join(dir, "cert.pem")
+	keyFile := filepath.Join(dir, "key.pem")
+	caFile := filepath.Join(dir, "ca.pem")
+	if err := os.WriteFile(certFile, []byte(testCert), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(keyFile, []byte(testKey), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	// client CA and a client certificate signed by it
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	caTmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	caDER, err := x509.CreateCertificate(rand.Reader, caTmpl, caTmpl, &caKey.PublicKey, caKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	caPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: caDER})
+	if err := os.WriteFile(caFile, caPEM, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	clientKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	clientTmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: "node01"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	clientDER, err := x509.CreateCertificate(rand.Reader, clientTmpl, caTmpl, &clientKey.PublicKey, caKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	clientCert := tls.Certificate{Certificate: [][]byte{clientDER}, PrivateKey: clientKey}
+
+	cs := NewCertStorage()
+	if err := cs.SetCertificate(certFile, keyFile); err != nil {
+		t.Fatal(err)
+	}
+	if err := cs.SetClientCAs(certFile+".missing", false); err == nil {
+		t.Errorf("expected error for a missing CA file")
+	}
+	if err := cs.SetClientCAs(keyFile, false); err == nil {
+		t.Errorf("expected error for a CA file without certificates")
+	}
+
+	ln, err := NewMultiAddrTLSConfigListener("tcp", "127.0.0.1:0", cs.TLSConfig())
+	if err != nil {
+		t.Fatalf("NewMultiAddrTLSConfigListener() error = %v", err)
+	}
+	defer ln.Close()
+
+	// handshake returns the verified client certificate chains of the
+	// server side of the connection
+	handshake := func(certs []tls.Certificate) ([][]*x509.Certificate, error) {
+		go func() {
+			conn, err := tls.Dial("tcp", ln.Addr().String(), &tls.Config{
+				InsecureSkipVerify: true,
+				Certificates:       certs,
+			})
+			if err != nil {
+				return
+			}
+			// wait for the server to finish the handshake
+			io.ReadAll(conn)
+			conn.Close()
+		}()
+
+		conn, err := ln.Accept()
+		if err != nil {
+			t.Fatalf("Failed to accept TLS connection: %v", err)
+		}
+		defer conn.Close()
+		tlsConn := conn.(*tls.Conn)
+		if err := tlsConn.Handshake(); err != nil {
+			return nil, err
+		}
+		return tlsConn.ConnectionState().VerifiedChains, nil
+	}
+
+	// without client CAs the client certificates are not requested
+	chains, err := handshake([]tls.Certificate{clientCert})
+	if err != nil || len(chains) != 0 {
+		t.Errorf("expected no verified chains, got %v, %v", chains, err)
+	}
+
+	if err := cs.SetClientCAs(caFile, false); err != nil {
+		t.Fatalf("SetClientCAs() error = %v", err)
+	}
+	chains, err = handshake([]tls.Certificate{clientCert})
+	if err != nil || len(chains) == 0 || chains[0][0].Subject.CommonName != "node01" {
+		t.Errorf("expected verified client certificate, got %v, %v", chains, err)
+	}
+	chains, err = handshake(nil)
+	if err != nil || len(chains) != 0 {
+		t.Errorf("expected optional client certificate, got %v, %v", chains, err)
+	}
+
+	if err := cs.SetClientCAs(caFile, true); err != nil {
+		t.Fatalf("SetClientCAs() error = %v", err)
+	}
+	if _, err := handshake(nil); err == nil {
+		t.Errorf("expected handshake error without the required client certificate")
+	}
+}
+
 // Test certificate and key for TLS tests
 const testCert = `-----BEGIN CERTIFICATE-----
 MIIBhTCCASugAwIBAgIQIRi6zePL6mKjOipn+dNuaTAKBggqhkjOPQQDAjASMRAw
@@ -108,7 +108,7 @@ func Test_validateAlgorithm(t *testing.T) {
 	}{
 		{"empty", "", s3err.QueryAuthErrors.MissingRequiredParams()},
 		{"AWS4-HMAC-SHA256", "AWS4-HMAC-SHA256", nil},
-		{"AWS4-ECDSA-P256-SHA256", "AWS4-ECDSA-P256-SHA256", s3err.QueryAuthErrors.OnlyHMACSupported()},
+		{"AWS4-ECDSA-P256-SHA256", "AWS4-ECDSA-P256-SHA256", nil},
 		{"invalid", "invalid algo", s3err.QueryAuthErrors.UnsupportedAlgorithm()},
 	}
 	for _, tt := range tests {
@@ -17,6 +17,7 @@ package utils
 import (
 	"bufio"
 	"bytes"
+	"crypto/ecdsa"
 	"crypto/hmac"
 	"crypto/sha256"
 	"encoding/base64"
@@ -31,6 +32,7 @@ import (
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/versity/versitygw/aws/signer/v4a"
 	"github.com/versity/versitygw/debuglogger"
 	"github.com/versity/versitygw/s3err"
 )
@@ -47,6 +49,9 @@ const (
 	streamPayloadAlgo        = "AWS4-HMAC-SHA256-PAYLOAD"
 	streamPayloadTrailerAlgo = "AWS4-HMAC-SHA256-TRAILER"
 
+	streamPayloadEcdsaAlgo        = "AWS4-ECDSA-P256-SHA256-PAYLOAD"
+	streamPayloadEcdsaTrailerAlgo = "AWS4-ECDSA-P256-SHA256-TRAILER"
+
 	maxHeaderSize = 1024
 )
 
@@ -58,8 +63,11 @@ var (
 // ChunkReader reads from chunked upload request body, and returns
 // object data stream
 type ChunkReader struct {
-	r              io.Reader
-	signingKey     []byte
+	r          io.Reader
+	signingKey []byte
+	// ecdsaKey is the public key of the SigV4A chunk signatures,
+	// the HMAC signingKey is used when not set
+	ecdsaKey       *ecdsa.PublicKey
 	prevSig        string
 	parsedSig      string
 	chunkDataLeft  int64
@@ -83,10 +91,11 @@ type ChunkReader struct {
 // chunk metadata in stream. The headers are validated for proper signatures.
 // Reading from the chunk reader will read only the object data stream
 // without the chunk headers/trailers.
+// The chunks of the SigV4A authorized requests are verified with the
+// ECDSA key derived from the secret.
 func NewSignedChunkReader(r io.Reader, authdata AuthData, secret string, date time.Time, chType checksumType, requireTrailer bool, cLength int64) (io.Reader, error) {
 	chRdr := &ChunkReader{
-		r:          r,
-		signingKey: getSigningKey(secret, authdata.Region, date),
+		r: r,
 		// the authdata.Signature is validated in the auth-reader,
 		// so we can use that here without any other checks
 		prevSig:        authdata.Signature,
@@ -100,6 +109,17 @@ func NewSignedChunkReader(r io.Reader, authdata AuthData, secret string, date ti
 		cLength:        cLength,
 	}
 
+	if authdata.Algorithm == algoECDSA {
+		key, err := v4a.DeriveKey(authdata.Access, secret)
+		if err != nil {
+			debuglogger.Logf("failed to derive the ecdsa signing key: %v", err)
+			return nil, err
+		}
+		chRdr.ecdsaKey = &key.PublicKey
+	} else {
+		chRdr.signingKey = getSigningKey(secret, authdata.Region, date)
+	}
+
 	if chType != "" {
 		checksumHasher, err := getHasher(chType)
 		if err != nil {
@@ -173,6 +193,10 @@ func (cr *ChunkReader) getStringToSignPrefix(algo string) string {
 		cr.region,
 		awsS3Service,
 		awsV4Request)
+	if cr.ecdsaKey != nil {
+		// the SigV4A credential scope has no region
+		credentialScope = v4a.BuildCredentialScope(cr.date, awsS3Service)
+	}
 
 	return fmt.Sprintf("%s\n%s\n%s",
 		algo,
@@ -185,7 +209,11 @@ func (cr *ChunkReader) getStringToSignPrefix(algo string) string {
 // string to sign. For the first chunk, you use the seed-signature as the
 // previous signature.
 func (cr *ChunkReader) getChunkStringToSign() string {
-	prefix := cr.getStringToSignPrefix(streamPayloadAlgo)
+	algo := streamPayloadAlgo
+	if cr.ecdsaKey != nil {
+		algo = streamPayloadEcdsaAlgo
+	}
+	prefix := cr.getStringToSignPrefix(algo)
 	chunkHash := cr.chunkHash.Sum(nil)
 	strToSign := fmt.Sprintf("%s\n%s\n%s\n%s",
 		prefix,
@@ -203,7 +231,11 @@ func (cr *ChunkReader) getTrailerChunkStringToSign() string {
 	hsh := sha256.Sum256([]byte(trailer))
 	sig := hex.EncodeToString(hsh[:])
 
-	prefix := cr.getStringToSignPrefix(streamPayloadTrailerAlgo)
+	algo := streamPayloadTrailerAlgo
+	if cr.ecdsaKey != nil {
+		algo = streamPayloadEcdsaTrailerAlgo
+	}
+	prefix := cr.getStringToSignPrefix(algo)
 
 	strToSign := fmt.Sprintf("%s\n%s\n%s",
 		prefix,
@@ -220,6 +252,14 @@ func (cr *ChunkReader) getTrailerChunkStringToSign() string {
 // Calculates and validates the final chunk trailer signature
 func (cr *ChunkReader) verifyTrailerSignature() error {
 	strToSign := cr.getTrailerChunkStringToSign()
+	if cr.ecdsaKey != nil {
+		if !v4a.VerifyString(cr.ecdsaKey, strToSign, cr.trailerSig) {
+			debuglogger.Logf("incorrect trailing signature: %v", cr.trailerSig)
+			return s3err.GetAPIError(s3err.ErrSignatureDoesNotMatch)
+		}
+		return nil
+	}
+
 	sig := hex.EncodeToString(hmac256(cr.signingKey, []byte(strToSign)))
 
 	if sig != cr.trailerSig {
@@ -247,6 +287,19 @@ func (cr *ChunkReader) verifyChecksum() error {
 func (cr *ChunkReader) checkSignature() error {
 	sigstr := cr.getChunkStringToSign()
 	cr.chunkHash.Reset()
+
+	if cr.ecdsaKey != nil {
+		// the ECDSA signatures are randomized, the signature is
+		// verified with the public key and chains the next chunk
+		if !v4a.VerifyString(cr.ecdsaKey, sigstr, cr.parsedSig) {
+			debuglogger.Logf("incorrect signature: %v", cr.parsedSig)
+			return s3err.GetAPIError(s3err.ErrSignatureDoesNotMatch)
+		}
+		cr.prevSig = cr.parsedSig
+		cr.parsedSig = ""
+		return nil
+	}
+
 	cr.prevSig = hex.EncodeToString(hmac256(cr.signingKey, []byte(sigstr)))
 
 	if cr.prevSig != cr.parsedSig {
@@ -400,18 +453,12 @@ func (cr *ChunkReader) parseChunkHeaderBytes(header []byte) (int64, string, int,
 		debuglogger.Logf("failed to read 'chunk-signature=': %v", err)
 		return cr.handleRdrErr(err, header)
 	}
-	sig, err := readBytes(rdr, 64)
+	sig, err := cr.readSignature(rdr)
 	if err != nil {
 		debuglogger.Logf("failed to read the chunk signature: %v", err)
 		return cr.handleRdrErr(err, header)
 	}
 
-	err = readAndSkip(rdr, delimiter...)
-	if err != nil {
-		debuglogger.Logf("failed to read '\\r\\n' after chunk signature")
-		return cr.handleRdrErr(err, header)
-	}
-
 	// read and parse the final chunk trailer and checksum
 	if chunkSize == 0 {
 		if cr.requireTrailer {
@@ -458,18 +505,12 @@ func (cr *ChunkReader) parseChunkHeaderBytes(header []byte) (int64, string, int,
 				return 0, "", 0, s3err.GetAPIError(s3err.ErrIncompleteBody)
 			}
 
-			trailerSig, err := readBytes(rdr, 64)
+			trailerSig, err := cr.readSignature(rdr)
 			if err != nil {
 				debuglogger.Logf("failed to read trailing signature: %v", err)
 				return cr.handleRdrErr(err, header)
 			}
 
-			err = readAndSkip(rdr, delimiter...)
-			if err != nil {
-				debuglogger.Logf("failed to read '\\r\\n' after last chunk signature")
-				return cr.handleRdrErr(err, header)
-			}
-
 			cr.trailerSig = trailerSig
 			cr.parsedChecksum = checksum
 		}
@@ -503,6 +544,31 @@ func (cr *ChunkReader) parseChunkHeaderBytes(header []byte) (int64, string, int,
 	return chunkSize, sig, offset, nil
 }
 
+// readSignature reads the chunk or trailing signature followed by "\r\n".
+// The HMAC signatures are 64 hex characters, the SigV4A signatures are
+// of variable length and may be padded with '*'.
+func (cr *ChunkReader) readSignature(rdr *bufio.Reader) (string, error) {
+	if cr.ecdsaKey == nil {
+		sig, err := readBytes(rdr, 64)
+		if err != nil {
+			return "", err
+		}
+
+		return sig, readAndSkip(rdr, delimiter...)
+	}
+
+	sig, err := readAndTrim(rdr, '\r')
+	if err != nil {
+		return "", err
+	}
+	err = readAndSkip(rdr, '\n')
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimRight(sig, "*"), nil
+}
+
 // Stashes the header in cr.stash and returns "errskipHeader"
 func (cr *ChunkReader) stashAndSkipHeader(header []byte) (int64, string, int, error) {
 	cr.stash = make([]byte, len(header))
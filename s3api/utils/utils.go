@@ -17,6 +17,7 @@ package utils
 import (
 	"bytes"
 	"crypto/tls"
+	"crypto/x509"
 	"encoding/base64"
 	"encoding/xml"
 	"errors"
@@ -25,6 +26,7 @@ import (
 	"net"
 	"net/http"
 	"net/url"
+	"os"
 	"regexp"
 	"strconv"
 	"strings"
@@ -373,6 +375,7 @@ func FilterObjectAttributes(attrs map[s3response.ObjectAttributes]struct{}, outp
 	output.LastModified = nil
 	output.VersionId = nil
 	output.DeleteMarker = nil
+	output.Layout = nil
 
 	if _, ok := attrs[s3response.ObjectAttributesEtag]; !ok {
 		output.ETag = nil
@@ -1004,7 +1007,15 @@ func GenerateObjectLocation(ctx *fiber.Ctx, virtualDomain, bucket, object string
 }
 
 type CertStorage struct {
-	cert atomic.Pointer[tls.Certificate]
+	cert       atomic.Pointer[tls.Certificate]
+	clientAuth atomic.Pointer[clientAuthConfig]
+}
+
+// clientAuthConfig is the client certificate verification of the
+// mutual TLS connections
+type clientAuthConfig struct {
+	authType tls.ClientAuthType
+	pool     *x509.CertPool
 }
 
 func NewCertStorage() *CertStorage {
@@ -1026,6 +1037,60 @@ func (cs *CertStorage) SetCertificate(certFile string, keyFile string) error {
 	return nil
 }
 
+// SetClientCAs loads the PEM encoded CA bundle used to verify the client
+// certificates of the mutual TLS connections. The clients without a
+// certificate are still accepted unless required is set, the invalid
+// client certificates are always rejected.
+func (cs *CertStorage) SetClientCAs(caFile string, required bool) error {
+	data, err := os.ReadFile(caFile)
+	if err != nil {
+		return fmt.Errorf("unable to set client CAs: %w", err)
+	}
+
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(data) {
+		return fmt.Errorf("unable to set client CAs: no certificates found in %v", caFile)
+	}
+
+	authType := tls.VerifyClientCertIfGiven
+	if required {
+		authType = tls.RequireAndVerifyClientCert
+	}
+
+	cs.clientAuth.Store(&clientAuthConfig{
+		authType: authType,
+		pool:     pool,
+	})
+
+	return nil
+}
+
+// TLSConfig returns the server TLS config using the stored certificate,
+// and the client CAs when set. Both can be updated while the listeners
+// are running.
+func (cs *CertStorage) TLSConfig() *tls.Config {
+	return &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		GetCertificate:     cs.GetCertificate,
+		GetConfigForClient: cs.getConfigForClient,
+	}
+}
+
+func (cs *CertStorage) getConfigForClient(*tls.ClientHelloInfo) (*tls.Config, error) {
+	ca := cs.clientAuth.Load()
+	if ca == nil {
+		// use the listener config
+		return nil, nil
+	}
+
+	return &tls.Config{
+		MinVersion:     tls.VersionTLS12,
+		GetCertificate: cs.GetCertificate,
+		ClientAuth:     ca.authType,
+		ClientCAs:      ca.pool,
+	}, nil
+}
+
 func NewTLSListener(network string, address string, getCertificateFunc func(*tls.ClientHelloInfo) (*tls.Certificate, error)) (net.Listener, error) {
 	config := &tls.Config{
 		MinVersion:     tls.VersionTLS12,
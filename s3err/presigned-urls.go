@@ -29,23 +29,23 @@ func authQueryParamError(format string, args ...any) APIError {
 }
 
 var QueryAuthErrors = struct {
-	UnsupportedAlgorithm  func() APIError
-	MalformedCredential   func() APIError
-	IncorrectService      func(string) APIError
-	IncorrectRegion       func(expected, actual string) APIError
-	IncorrectTerminal     func(string) APIError
-	InvalidDateFormat     func(string) APIError
-	DateMismatch          func(expected, actual string) APIError
-	ExpiresTooLarge       func() APIError
-	ExpiresNegative       func() APIError
-	ExpiresNumber         func() APIError
-	MissingRequiredParams func() APIError
-	InvalidXAmzDateFormat func() APIError
-	RequestNotYetValid    func() APIError
-	RequestExpired        func() APIError
-	InvalidAccessKeyId    func() APIError
-	// a custom non-AWS error
-	OnlyHMACSupported         func() APIError
+	UnsupportedAlgorithm      func() APIError
+	MalformedCredential       func() APIError
+	MalformedCredentialV4A    func() APIError
+	IncorrectService          func(string) APIError
+	IncorrectRegion           func(expected, actual string) APIError
+	IncorrectRegionSet        func(expected, actual string) APIError
+	IncorrectTerminal         func(string) APIError
+	InvalidDateFormat         func(string) APIError
+	DateMismatch              func(expected, actual string) APIError
+	ExpiresTooLarge           func() APIError
+	ExpiresNegative           func() APIError
+	ExpiresNumber             func() APIError
+	MissingRequiredParams     func() APIError
+	InvalidXAmzDateFormat     func() APIError
+	RequestNotYetValid        func() APIError
+	RequestExpired            func() APIError
+	InvalidAccessKeyId        func() APIError
 	SecurityTokenNotSupported func() APIError
 }{
 
@@ -55,12 +55,18 @@ var QueryAuthErrors = struct {
 	MalformedCredential: func() APIError {
 		return authQueryParamError(`Error parsing the X-Amz-Credential parameter; the Credential is mal-formed; expecting "<YOUR-AKID>/YYYYMMDD/REGION/SERVICE/aws4_request".`)
 	},
+	MalformedCredentialV4A: func() APIError {
+		return authQueryParamError(`Error parsing the X-Amz-Credential parameter; the Credential is mal-formed; expecting "<YOUR-AKID>/YYYYMMDD/SERVICE/aws4_request".`)
+	},
 	IncorrectService: func(s string) APIError {
 		return authQueryParamError(`Error parsing the X-Amz-Credential parameter; incorrect service %q. This endpoint belongs to "s3".`, s)
 	},
 	IncorrectRegion: func(expected, actual string) APIError {
 		return authQueryParamError(`Error parsing the X-Amz-Credential parameter; the region %q is wrong; expecting %q`, actual, expected)
 	},
+	IncorrectRegionSet: func(expected, actual string) APIError {
+		return authQueryParamError(`Error parsing the X-Amz-Region-Set parameter; the region set %q does not contain the region %q`, actual, expected)
+	},
 	IncorrectTerminal: func(s string) APIError {
 		return authQueryParamError(`Error parsing the X-Amz-Credential parameter; incorrect terminal %q. This endpoint uses "aws4_request".`, s)
 	},
@@ -85,10 +91,6 @@ var QueryAuthErrors = struct {
 	InvalidXAmzDateFormat: func() APIError {
 		return authQueryParamError(`X-Amz-Date must be in the ISO8601 Long Format "yyyyMMdd'T'HHmmss'Z'"`)
 	},
-	// a custom non-AWS error
-	OnlyHMACSupported: func() APIError {
-		return authQueryParamError("X-Amz-Algorithm only supports \"AWS4-HMAC-SHA256\"")
-	},
 	SecurityTokenNotSupported: func() APIError {
 		return authQueryParamError("Authorization with X-Amz-Security-Token is not supported")
 	},
@@ -123,6 +123,9 @@ const (
 	ErrInvalidAccessKeyID
 	ErrRequestNotReadyYet
 	ErrMissingDateHeader
+	ErrInvalidSigV2AuthHeader
+	ErrMissingSigV2QueryParams
+	ErrInvalidSigV2Expires
 	ErrGetUploadsWithKey
 	ErrVersionsWithKey
 	ErrCopySourceNotAllowed
@@ -179,6 +182,8 @@ const (
 	ErrCORSForbidden
 	ErrMissingCORSOrigin
 	ErrCORSIsNotEnabled
+	ErrNoSuchPublicAccessBlockConfiguration
+	ErrUnresolvableGrantByEmailAddress
 	ErrNotModified
 	ErrInvalidLocationConstraint
 	ErrInvalidArgument
@@ -195,6 +200,8 @@ const (
 	ErrQuotaExceeded
 	ErrVersioningNotConfigured
 	ErrACLsDisabled
+	ErrShareLinkExpired
+	ErrShareLinkPasswordRequired
 
 	// Admin api errors
 	ErrAdminAccessDenied
@@ -204,6 +211,15 @@ const (
 	ErrAdminMissingUserAcess
 	ErrAdminMethodNotSupported
 	ErrAdminEmptyBucketOwnerHeader
+	ErrAdminNoSuchShareLink
+	ErrAdminInvalidShareLink
+	ErrAdminShareLinksDisabled
+	ErrAdminMalformedJSON
+	ErrAdminInvalidLimit
+	ErrAdminMissingBucketOwner
+	ErrAdminQuotasNotSupported
+	ErrAdminNoSuchResource
+	ErrAdminConfigReloadNotEnabled
 )
 
 var errorCodeResponse = map[ErrorCode]APIError{
@@ -527,6 +543,21 @@ var errorCodeResponse = map[ErrorCode]APIError{
 		Description:    "AWS authentication requires a valid Date or x-amz-date header.",
 		HTTPStatusCode: http.StatusBadRequest,
 	},
+	ErrInvalidSigV2AuthHeader: {
+		Code:           "InvalidArgument",
+		Description:    "AWS authorization header is invalid.  Expected AwsAccessKeyId:signature",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	ErrMissingSigV2QueryParams: {
+		Code:           "AccessDenied",
+		Description:    "Query-string authentication requires the Signature, Expires and AWSAccessKeyId parameters",
+		HTTPStatusCode: http.StatusForbidden,
+	},
+	ErrInvalidSigV2Expires: {
+		Code:           "AccessDenied",
+		Description:    "Invalid date (should be seconds since epoch)",
+		HTTPStatusCode: http.StatusForbidden,
+	},
 	ErrGetUploadsWithKey: {
 		Code:           "InvalidRequest",
 		Description:    "Key is not expected for the GET method ?uploads subresource",
@@ -807,6 +838,16 @@ var errorCodeResponse = map[ErrorCode]APIError{
 		Description:    "CORSResponse: CORS is not enabled for this bucket.",
 		HTTPStatusCode: http.StatusForbidden,
 	},
+	ErrNoSuchPublicAccessBlockConfiguration: {
+		Code:           "NoSuchPublicAccessBlockConfiguration",
+		Description:    "The public access block configuration was not found",
+		HTTPStatusCode: http.StatusNotFound,
+	},
+	ErrUnresolvableGrantByEmailAddress: {
+		Code:           "UnresolvableGrantByEmailAddress",
+		Description:    "The email address you provided does not match any account on record.",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
 	ErrNotModified: {
 		Code:           "NotModified",
 		Description:    "Not Modified",
@@ -880,6 +921,16 @@ var errorCodeResponse = map[ErrorCode]APIError{
 		Description:    "Access control lists are disabled at the gateway level",
 		HTTPStatusCode: http.StatusBadRequest,
 	},
+	ErrShareLinkExpired: {
+		Code:           "ExpiredShareLink",
+		Description:    "The share link has expired or reached its download limit.",
+		HTTPStatusCode: http.StatusForbidden,
+	},
+	ErrShareLinkPasswordRequired: {
+		Code:           "ShareLinkPasswordRequired",
+		Description:    "The share link is password protected and the provided password is missing or invalid.",
+		HTTPStatusCode: http.StatusUnauthorized,
+	},
 
 	// Admin api errors
 	ErrAdminAccessDenied: {
@@ -917,6 +968,51 @@ var errorCodeResponse = map[ErrorCode]APIError{
 		Description:    "The x-vgw-owner header specifying the new bucket owner access key id is either missing or empty",
 		HTTPStatusCode: http.StatusBadRequest,
 	},
+	ErrAdminNoSuchShareLink: {
+		Code:           "XAdminNoSuchShareLink",
+		Description:    "The specified share link does not exist.",
+		HTTPStatusCode: http.StatusNotFound,
+	},
+	ErrAdminInvalidShareLink: {
+		Code:           "XAdminInvalidRequest",
+		Description:    "The share link must specify an existing bucket, at least one of the read and upload permissions and a future expiration time.",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	ErrAdminShareLinksDisabled: {
+		Code:           "XAdminMethodNotSupported",
+		Description:    "The share links are not enabled on the gateway.",
+		HTTPStatusCode: http.StatusNotImplemented,
+	},
+	ErrAdminMalformedJSON: {
+		Code:           "XAdminMalformedJSON",
+		Description:    "The JSON you provided was not well-formed or did not validate against the admin API schema.",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	ErrAdminInvalidLimit: {
+		Code:           "XAdminInvalidRequest",
+		Description:    "The limit must be an integer between 1 and 1000.",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	ErrAdminMissingBucketOwner: {
+		Code:           "XAdminInvalidRequest",
+		Description:    "The bucket owner is not specified.",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	ErrAdminQuotasNotSupported: {
+		Code:           "XAdminMethodNotSupported",
+		Description:    "The gateway does not manage storage quotas, the quotas are enforced by the backend storage.",
+		HTTPStatusCode: http.StatusNotImplemented,
+	},
+	ErrAdminNoSuchResource: {
+		Code:           "XAdminNoSuchResource",
+		Description:    "The requested admin API resource does not exist.",
+		HTTPStatusCode: http.StatusNotFound,
+	},
+	ErrAdminConfigReloadNotEnabled: {
+		Code:           "XAdminInvalidRequest",
+		Description:    "The gateway is not running with a configuration file.",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
 }
 
 // GetAPIError provides API Error for input API error code.
@@ -1088,3 +1184,11 @@ func GetNegativeMaxLimiterErr(limiter string) APIError {
 		HTTPStatusCode: http.StatusBadRequest,
 	}
 }
+
+func GetAdminInvalidConfigErr(err error) APIError {
+	return APIError{
+		Code:           "XAdminInvalidConfig",
+		Description:    fmt.Sprintf("Failed to reload the configuration file: %v", err),
+		HTTPStatusCode: http.StatusBadRequest,
+	}
+}
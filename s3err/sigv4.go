@@ -29,17 +29,20 @@ func malformedAuthError(format string, args ...any) APIError {
 }
 
 var MalformedAuth = struct {
-	InvalidDateFormat    func(string) APIError
-	MalformedCredential  func() APIError
-	MissingCredential    func() APIError
-	MissingSignature     func() APIError
-	MissingSignedHeaders func() APIError
-	InvalidTerminal      func(string) APIError
-	IncorrectRegion      func(expected, actual string) APIError
-	IncorrectService     func(string) APIError
-	MalformedComponent   func(string) APIError
-	MissingComponents    func() APIError
-	DateMismatch         func() APIError
+	InvalidDateFormat      func(string) APIError
+	MalformedCredential    func() APIError
+	MalformedCredentialV4A func() APIError
+	MissingCredential      func() APIError
+	MissingSignature       func() APIError
+	MissingSignedHeaders   func() APIError
+	InvalidTerminal        func(string) APIError
+	IncorrectRegion        func(expected, actual string) APIError
+	MissingRegionSet       func() APIError
+	IncorrectRegionSet     func(expected, actual string) APIError
+	IncorrectService       func(string) APIError
+	MalformedComponent     func(string) APIError
+	MissingComponents      func() APIError
+	DateMismatch           func() APIError
 }{
 	InvalidDateFormat: func(s string) APIError {
 		return malformedAuthError("incorrect date format %q. This date in the credential must be in the format \"yyyyMMdd\".", s)
@@ -47,6 +50,9 @@ var MalformedAuth = struct {
 	MalformedCredential: func() APIError {
 		return malformedAuthError("the Credential is mal-formed; expecting \"<YOUR-AKID>/YYYYMMDD/REGION/SERVICE/aws4_request\".")
 	},
+	MalformedCredentialV4A: func() APIError {
+		return malformedAuthError("the Credential is mal-formed; expecting \"<YOUR-AKID>/YYYYMMDD/SERVICE/aws4_request\".")
+	},
 	MissingCredential: func() APIError {
 		return malformedAuthError("missing Credential.")
 	},
@@ -62,6 +68,12 @@ var MalformedAuth = struct {
 	IncorrectRegion: func(expected, actual string) APIError {
 		return malformedAuthError("the region %q is wrong; expecting %q", actual, expected)
 	},
+	MissingRegionSet: func() APIError {
+		return malformedAuthError("missing X-Amz-Region-Set.")
+	},
+	IncorrectRegionSet: func(expected, actual string) APIError {
+		return malformedAuthError("the region set %q does not contain the region %q", actual, expected)
+	},
 	IncorrectService: func(s string) APIError {
 		return malformedAuthError("incorrect service %q. This endpoint belongs to \"s3\".", s)
 	},
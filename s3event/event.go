@@ -15,6 +15,7 @@
 package s3event
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"strings"
@@ -27,9 +28,18 @@ import (
 
 type S3EventSender interface {
 	SendEvent(ctx *fiber.Ctx, meta EventMeta)
+	// SendSystemEvent sends the events not tied to a request, such as
+	// the events of the background tasks of the gateway
+	SendSystemEvent(bucket, object string, meta EventMeta)
 	Close() error
 }
 
+// HealthChecker is implemented by the event senders able to report
+// whether the event sink is still connected
+type HealthChecker interface {
+	CheckHealth(ctx context.Context) error
+}
+
 type EventMeta struct {
 	BucketOwner string
 	EventName   EventType
@@ -202,6 +212,43 @@ func createEventSchema(ctx *fiber.Ctx, meta EventMeta, configId ConfigurationId)
 	}
 }
 
+// systemPrincipalId is the principal of the events not tied to a request
+const systemPrincipalId = "versitygw"
+
+func createSystemEventSchema(bucket, object string, meta EventMeta, configId ConfigurationId) EventSchema {
+	return EventSchema{
+		Records: []EventRecord{
+			{
+				EventVersion: "2.2",
+				EventSource:  "aws:s3",
+				EventTime:    time.Now().Format(time.RFC3339),
+				EventName:    meta.EventName,
+				UserIdentity: EventUserIdentity{
+					PrincipalId: systemPrincipalId,
+				},
+				S3: EventS3Data{
+					S3SchemaVersion: "1.0",
+					ConfigurationId: configId,
+					Bucket: EventS3BucketData{
+						Name: bucket,
+						OwnerIdentity: EventUserIdentity{
+							PrincipalId: meta.BucketOwner,
+						},
+						Arn: fmt.Sprintf("arn:aws:s3:::%v", bucket),
+					},
+					Object: EventObjectData{
+						Key:       object,
+						Size:      meta.ObjectSize,
+						ETag:      meta.ObjectETag,
+						VersionId: meta.VersionId,
+						Sequencer: genSequencer(),
+					},
+				},
+			},
+		},
+	}
+}
+
 func generateTestEvent() ([]byte, error) {
 	msg := map[string]string{
 		"Service": "S3",
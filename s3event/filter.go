@@ -41,6 +41,7 @@ const (
 	EventObjectRestore              EventType = "s3:ObjectRestore:*" // ObjectRestore
 	EventObjectRestorePost          EventType = "s3:ObjectRestore:Post"
 	EventObjectRestoreCompleted     EventType = "s3:ObjectRestore:Completed"
+	EventObjectIntegrityFailed      EventType = "s3:ObjectIntegrity:Failed" // non AWS custom type for data scrub mismatches
 	// EventObjectRestorePost       EventType = "s3:ObjectRestore:Post"
 	// EventObjectRestoreDelete     EventType = "s3:ObjectRestore:Delete"
 )
@@ -66,6 +67,7 @@ var supportedEventFilters = map[EventType]struct{}{
 	EventObjectRestore:              {},
 	EventObjectRestorePost:          {},
 	EventObjectRestoreCompleted:     {},
+	EventObjectIntegrityFailed:      {},
 }
 
 type EventFilter map[EventType]bool
@@ -31,6 +31,7 @@ import (
 var sequencer = 0
 
 type Kafka struct {
+	url    string
 	key    string
 	writer *kafka.Writer
 	filter EventFilter
@@ -67,6 +68,7 @@ func InitKafkaEventService(url, topic, key string, filter EventFilter) (S3EventS
 	}
 
 	return &Kafka{
+		url:    url,
 		key:    key,
 		writer: w,
 		filter: filter,
@@ -107,10 +109,32 @@ func (ks *Kafka) SendEvent(ctx *fiber.Ctx, meta EventMeta) {
 	go ks.send(schema)
 }
 
+func (ks *Kafka) SendSystemEvent(bucket, object string, meta EventMeta) {
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+
+	if ks.filter != nil && !ks.filter.Filter(meta.EventName) {
+		return
+	}
+
+	schema := createSystemEventSchema(bucket, object, meta, ConfigurationIdWebhook)
+
+	go ks.send(schema)
+}
+
 func (ks *Kafka) Close() error {
 	return ks.writer.Close()
 }
 
+// CheckHealth checks the kafka broker accepts connections
+func (ks *Kafka) CheckHealth(ctx context.Context) error {
+	conn, err := kafka.DialContext(ctx, "tcp", ks.url)
+	if err != nil {
+		return fmt.Errorf("kafka connect: %w", err)
+	}
+	return conn.Close()
+}
+
 func (ks *Kafka) send(event EventSchema) {
 	eventBytes, err := json.Marshal(event)
 	if err != nil {
@@ -15,6 +15,7 @@
 package s3event
 
 import (
+	"context"
 	"encoding/json"
 	"encoding/xml"
 	"fmt"
@@ -94,11 +95,32 @@ func (ns *NatsEventSender) SendEvent(ctx *fiber.Ctx, meta EventMeta) {
 	go ns.send(schema)
 }
 
+func (ns *NatsEventSender) SendSystemEvent(bucket, object string, meta EventMeta) {
+	ns.mu.Lock()
+	defer ns.mu.Unlock()
+
+	if ns.filter != nil && !ns.filter.Filter(meta.EventName) {
+		return
+	}
+
+	schema := createSystemEventSchema(bucket, object, meta, ConfigurationIdWebhook)
+
+	go ns.send(schema)
+}
+
 func (ns *NatsEventSender) Close() error {
 	ns.client.Close()
 	return nil
 }
 
+// CheckHealth checks the nats client is connected to the server
+func (ns *NatsEventSender) CheckHealth(_ context.Context) error {
+	if !ns.client.IsConnected() {
+		return fmt.Errorf("nats connection %v", ns.client.Status())
+	}
+	return nil
+}
+
 func (ns *NatsEventSender) send(event EventSchema) {
 	eventBytes, err := json.Marshal(event)
 	if err != nil {
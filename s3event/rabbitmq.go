@@ -15,6 +15,7 @@
 package s3event
 
 import (
+	"context"
 	"encoding/json"
 	"encoding/xml"
 	"fmt"
@@ -115,6 +116,19 @@ func (rs *RabbitmqEventSender) SendEvent(ctx *fiber.Ctx, meta EventMeta) {
 	go rs.send(schema)
 }
 
+func (rs *RabbitmqEventSender) SendSystemEvent(bucket, object string, meta EventMeta) {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+
+	if rs.filter != nil && !rs.filter.Filter(meta.EventName) {
+		return
+	}
+
+	schema := createSystemEventSchema(bucket, object, meta, ConfigurationIdRabbitMQ)
+
+	go rs.send(schema)
+}
+
 func (rs *RabbitmqEventSender) Close() error {
 	var firstErr error
 	if rs.channel != nil {
@@ -130,6 +144,17 @@ func (rs *RabbitmqEventSender) Close() error {
 	return firstErr
 }
 
+// CheckHealth checks the rabbitmq connection and channel are open
+func (rs *RabbitmqEventSender) CheckHealth(_ context.Context) error {
+	if rs.conn.IsClosed() {
+		return fmt.Errorf("rabbitmq connection closed")
+	}
+	if rs.channel.IsClosed() {
+		return fmt.Errorf("rabbitmq channel closed")
+	}
+	return nil
+}
+
 func (rs *RabbitmqEventSender) send(event EventSchema) {
 	body, err := json.Marshal(event)
 	if err != nil {
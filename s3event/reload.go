@@ -0,0 +1,89 @@
+// Copyright 2026 Versity Software
+// This file is licensed under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package s3event
+
+import (
+	"context"
+	"sync"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// ReloadableEventSender is an event sender whose underlying sender
+// can be replaced while the gateway is serving requests. A nil
+// underlying sender drops the events.
+type ReloadableEventSender struct {
+	mu     sync.RWMutex
+	sender S3EventSender
+}
+
+var _ S3EventSender = &ReloadableEventSender{}
+var _ HealthChecker = &ReloadableEventSender{}
+
+// NewReloadableEventSender wraps the sender, the sender may be nil
+func NewReloadableEventSender(sender S3EventSender) *ReloadableEventSender {
+	return &ReloadableEventSender{sender: sender}
+}
+
+// Swap replaces the underlying sender and closes the previous
+// one after the in-flight events are sent
+func (s *ReloadableEventSender) Swap(sender S3EventSender) error {
+	s.mu.Lock()
+	old := s.sender
+	s.sender = sender
+	s.mu.Unlock()
+
+	if old == nil {
+		return nil
+	}
+	return old.Close()
+}
+
+// SendEvent sends the event to the current sender
+func (s *ReloadableEventSender) SendEvent(ctx *fiber.Ctx, meta EventMeta) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.sender != nil {
+		s.sender.SendEvent(ctx, meta)
+	}
+}
+
+// SendSystemEvent sends the system event to the current sender
+func (s *ReloadableEventSender) SendSystemEvent(bucket, object string, meta EventMeta) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.sender != nil {
+		s.sender.SendSystemEvent(bucket, object, meta)
+	}
+}
+
+// CheckHealth checks the current sender if it supports the
+// health checks, no sender is always healthy
+func (s *ReloadableEventSender) CheckHealth(ctx context.Context) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if hc, ok := s.sender.(HealthChecker); ok {
+		return hc.CheckHealth(ctx)
+	}
+	return nil
+}
+
+// Close closes the current sender
+func (s *ReloadableEventSender) Close() error {
+	return s.Swap(nil)
+}
@@ -0,0 +1,110 @@
+package s3event
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type testSender struct {
+	events   []string
+	closed   bool
+	closeErr error
+}
+
+func (s *testSender) SendEvent(ctx *fiber.Ctx, meta EventMeta) {
+	s.events = append(s.events, string(meta.EventName))
+}
+
+func (s *testSender) SendSystemEvent(bucket, object string, meta EventMeta) {
+	s.events = append(s.events, bucket+"/"+object)
+}
+
+func (s *testSender) Close() error {
+	s.closed = true
+	return s.closeErr
+}
+
+func TestReloadableEventSender(t *testing.T) {
+	rs := NewReloadableEventSender(nil)
+
+	// no sender, the events are dropped
+	rs.SendSystemEvent("bucket", "dropped", EventMeta{})
+	if err := rs.Swap(nil); err != nil {
+		t.Fatalf("swap nil sender: %v", err)
+	}
+
+	first := &testSender{}
+	if err := rs.Swap(first); err != nil {
+		t.Fatalf("swap first sender: %v", err)
+	}
+	rs.SendSystemEvent("bucket", "obj1", EventMeta{})
+
+	second := &testSender{}
+	if err := rs.Swap(second); err != nil {
+		t.Fatalf("swap second sender: %v", err)
+	}
+	if !first.closed {
+		t.Errorf("expected the replaced sender to be closed")
+	}
+	rs.SendSystemEvent("bucket", "obj2", EventMeta{})
+
+	if len(first.events) != 1 || first.events[0] != "bucket/obj1" {
+		t.Errorf("unexpected first sender events: %v", first.events)
+	}
+	if len(second.events) != 1 || second.events[0] != "bucket/obj2" {
+		t.Errorf("unexpected second sender events: %v", second.events)
+	}
+
+	second.closeErr = errors.New("close failed")
+	if err := rs.Close(); err == nil {
+		t.Errorf("expected the close error to be returned")
+	}
+	if !second.closed {
+		t.Errorf("expected the sender to be closed")
+	}
+
+	// closed wrapper drops the events
+	rs.SendSystemEvent("bucket", "obj3", EventMeta{})
+	if len(second.events) != 1 {
+		t.Errorf("unexpected events after close: %v", second.events)
+	}
+}
+
+type healthSender struct {
+	testSender
+	err error
+}
+
+func (s *healthSender) CheckHealth(_ context.Context) error {
+	return s.err
+}
+
+func TestReloadableEventSender_CheckHealth(t *testing.T) {
+	rs := NewReloadableEventSender(nil)
+	if err := rs.CheckHealth(context.Background()); err != nil {
+		t.Errorf("expected no sender to be healthy, got %v", err)
+	}
+
+	if err := rs.Swap(&testSender{}); err != nil {
+		t.Fatalf("swap sender: %v", err)
+	}
+	if err := rs.CheckHealth(context.Background()); err != nil {
+		t.Errorf("expected the sender without health checks to be healthy, got %v", err)
+	}
+
+	hs := &healthSender{err: errors.New("disconnected")}
+	if err := rs.Swap(hs); err != nil {
+		t.Fatalf("swap sender: %v", err)
+	}
+	if err := rs.CheckHealth(context.Background()); err == nil {
+		t.Errorf("expected the sender health check error")
+	}
+
+	hs.err = nil
+	if err := rs.CheckHealth(context.Background()); err != nil {
+		t.Errorf("expected the sender to be healthy, got %v", err)
+	}
+}
@@ -16,11 +16,13 @@ package s3event
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"encoding/xml"
 	"fmt"
 	"net"
 	"net/http"
+	"net/url"
 	"os"
 	"sync"
 	"time"
@@ -106,10 +108,47 @@ func (w *Webhook) SendEvent(ctx *fiber.Ctx, meta EventMeta) {
 	go w.send(schema)
 }
 
+func (w *Webhook) SendSystemEvent(bucket, object string, meta EventMeta) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.filter != nil && !w.filter.Filter(meta.EventName) {
+		return
+	}
+
+	schema := createSystemEventSchema(bucket, object, meta, ConfigurationIdWebhook)
+
+	go w.send(schema)
+}
+
 func (w *Webhook) Close() error {
 	return nil
 }
 
+// CheckHealth checks the webhook endpoint accepts connections
+func (w *Webhook) CheckHealth(ctx context.Context) error {
+	u, err := url.Parse(w.url)
+	if err != nil {
+		return fmt.Errorf("parse webhook url: %w", err)
+	}
+
+	host := u.Host
+	if u.Port() == "" {
+		port := "80"
+		if u.Scheme == "https" {
+			port = "443"
+		}
+		host = net.JoinHostPort(u.Hostname(), port)
+	}
+
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "tcp", host)
+	if err != nil {
+		return fmt.Errorf("webhook connect: %w", err)
+	}
+	return conn.Close()
+}
+
 func (w *Webhook) send(event EventSchema) {
 	eventBytes, err := json.Marshal(event)
 	if err != nil {
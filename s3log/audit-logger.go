@@ -17,12 +17,17 @@ package s3log
 import (
 	"crypto/tls"
 	"encoding/hex"
+	"encoding/json"
 	"fmt"
 	"math/rand"
 	"strings"
 	"time"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/versity/versitygw/auth"
+	"github.com/versity/versitygw/metrics"
+	"github.com/versity/versitygw/s3api/utils"
+	"github.com/versity/versitygw/s3err"
 )
 
 type AuditLogger interface {
@@ -41,7 +46,36 @@ type LogMeta struct {
 type LogConfig struct {
 	LogFile      string
 	WebhookURL   string
+	SyslogURL    string
 	AdminLogFile string
+	Format       LogFormat
+	Rotate       RotateConfig
+	Webhook      WebhookConfig
+	// MetricsManager receives the audit log delivery metrics, optional
+	MetricsManager metrics.Manager
+}
+
+// LogFormat selects the representation of the S3 access log records
+type LogFormat string
+
+const (
+	// LogFormatText is the AWS server access log space delimited format
+	LogFormatText LogFormat = "text"
+	// LogFormatJSON writes one JSON object (AuditRecord) per line
+	LogFormatJSON LogFormat = "json"
+)
+
+// ParseLogFormat validates the log format string, an empty
+// string defaults to the text format
+func ParseLogFormat(format string) (LogFormat, error) {
+	switch LogFormat(strings.ToLower(format)) {
+	case "", LogFormatText:
+		return LogFormatText, nil
+	case LogFormatJSON:
+		return LogFormatJSON, nil
+	default:
+		return "", fmt.Errorf("invalid log format %q, expected one of: text, json", format)
+	}
 }
 
 type LogFields struct {
@@ -73,6 +107,20 @@ type LogFields struct {
 	AclRequired        string
 }
 
+// AuditRecord is the JSON audit log record. It carries all of the
+// LogFields along with additional requester and header details that
+// don't fit into the AWS server access log format.
+type AuditRecord struct {
+	LogFields
+	Role            string            `json:",omitempty"`
+	UserID          int               `json:",omitempty"`
+	GroupID         int               `json:",omitempty"`
+	ProjectID       int               `json:",omitempty"`
+	Tenant          string            `json:",omitempty"`
+	RequestHeaders  map[string]string `json:",omitempty"`
+	ResponseHeaders map[string]string `json:",omitempty"`
+}
+
 type AdminLogFields struct {
 	Time               time.Time
 	RemoteIP           string
@@ -99,22 +147,35 @@ type Loggers struct {
 }
 
 func InitLogger(cfg *LogConfig) (*Loggers, error) {
-	if cfg.WebhookURL != "" && cfg.LogFile != "" {
-		return nil, fmt.Errorf("there should be specified one of the following: file, webhook")
+	var destinations int
+	for _, d := range []string{cfg.WebhookURL, cfg.LogFile, cfg.SyslogURL} {
+		if d != "" {
+			destinations++
+		}
+	}
+	if destinations > 1 {
+		return nil, fmt.Errorf("there should be specified one of the following: file, webhook, syslog")
 	}
 	loggers := new(Loggers)
 
 	switch {
+	case cfg.SyslogURL != "":
+		fmt.Printf("initializing S3 access logs with '%v' syslog url\n", cfg.SyslogURL)
+		l, err := InitSyslogLogger(cfg.SyslogURL, cfg.Format)
+		if err != nil {
+			return nil, err
+		}
+		loggers.S3Logger = l
 	case cfg.WebhookURL != "":
 		fmt.Printf("initializing S3 access logs with '%v' webhook url\n", cfg.WebhookURL)
-		l, err := InitWebhookLogger(cfg.WebhookURL)
+		l, err := InitWebhookLogger(cfg.WebhookURL, cfg.Webhook, cfg.MetricsManager)
 		if err != nil {
 			return nil, err
 		}
 		loggers.S3Logger = l
 	case cfg.LogFile != "":
 		fmt.Printf("initializing S3 access logs with '%v' file\n", cfg.LogFile)
-		l, err := InitFileLogger(cfg.LogFile)
+		l, err := InitFileLogger(cfg.LogFile, cfg.Format, cfg.Rotate)
 		if err != nil {
 			return nil, err
 		}
@@ -124,7 +185,7 @@ func InitLogger(cfg *LogConfig) (*Loggers, error) {
 
 	if cfg.AdminLogFile != "" {
 		fmt.Printf("initializing admin access logs with '%v' file\n", cfg.AdminLogFile)
-		l, err := InitAdminFileLogger(cfg.AdminLogFile)
+		l, err := InitAdminFileLogger(cfg.AdminLogFile, cfg.Rotate)
 		if err != nil {
 			return nil, err
 		}
@@ -135,6 +196,248 @@ func InitLogger(cfg *LogConfig) (*Loggers, error) {
 	return loggers, nil
 }
 
+// auditedRequestHeaders are the request headers included in the JSON
+// audit records, credentials and signatures are intentionally left out
+var auditedRequestHeaders = []string{
+	"Content-Type",
+	"Content-Length",
+	"Content-Md5",
+	"Range",
+	"If-Match",
+	"If-None-Match",
+	"If-Modified-Since",
+	"If-Unmodified-Since",
+	"X-Amz-Acl",
+	"X-Amz-Content-Sha256",
+	"X-Amz-Copy-Source",
+	"X-Amz-Date",
+	"X-Amz-Storage-Class",
+	"X-Amz-Checksum-Algorithm",
+	"X-Amz-Sdk-Checksum-Algorithm",
+	"X-Amz-Object-Lock-Mode",
+	"X-Amz-Object-Lock-Retain-Until-Date",
+	"X-Amz-Object-Lock-Legal-Hold",
+	"X-Amz-Bypass-Governance-Retention",
+	"X-Amz-Expected-Bucket-Owner",
+}
+
+// auditedResponseHeaders are the response headers included in the JSON
+// audit records
+var auditedResponseHeaders = []string{
+	"Content-Type",
+	"Content-Length",
+	"Content-Range",
+	"ETag",
+	"Last-Modified",
+	"X-Amz-Version-Id",
+	"X-Amz-Delete-Marker",
+	"X-Amz-Request-Id",
+	"X-Amz-Storage-Class",
+	"X-Amz-Restore",
+}
+
+// newAuditRecord collects the S3 access log fields of the request
+func newAuditRecord(ctx *fiber.Ctx, err error, body []byte, meta LogMeta) AuditRecord {
+	rec := AuditRecord{}
+	lf := &rec.LogFields
+
+	access := "-"
+	reqURI := ctx.OriginalURL()
+	path := strings.Split(ctx.Path(), "/")
+	var bucket, object string
+	if len(path) > 1 {
+		bucket, object = path[1], strings.Join(path[2:], "/")
+	}
+	errorCode := ""
+	httpStatus := 200
+	startTime, ok := utils.ContextKeyStartTime.Get(ctx).(time.Time)
+	if !ok {
+		startTime = time.Now()
+	}
+	tlsConnState := ctx.Context().TLSConnectionState()
+	if tlsConnState != nil {
+		lf.CipherSuite = tls.CipherSuiteName(tlsConnState.CipherSuite)
+		lf.TLSVersion = getTLSVersionName(tlsConnState.Version)
+	}
+
+	if err != nil {
+		serr, ok := err.(s3err.APIError)
+		if ok {
+			errorCode = serr.Code
+			httpStatus = serr.HTTPStatusCode
+		} else {
+			errorCode = err.Error()
+			httpStatus = 500
+		}
+	}
+
+	acct, ok := utils.ContextKeyAccount.Get(ctx).(auth.Account)
+	if ok {
+		access = acct.Access
+		rec.Role = string(acct.Role)
+		rec.UserID = acct.UserID
+		rec.GroupID = acct.GroupID
+		rec.ProjectID = acct.ProjectID
+		if acct.Access != "" {
+			rec.Tenant = auth.GetTenantID(acct.Access)
+		}
+	}
+
+	region, ok := utils.ContextKeyRegion.Get(ctx).(string)
+	if ok {
+		lf.HostHeader = fmt.Sprintf("s3.%v.amazonaws.com", region)
+	}
+
+	lf.BucketOwner = meta.BucketOwner
+	lf.Bucket = bucket
+	lf.Time = time.Now()
+	lf.RemoteIP = ctx.IP()
+	lf.Requester = access
+	lf.RequestID = genID()
+	lf.Operation = meta.Action
+	lf.Key = object
+	lf.RequestURI = reqURI
+	lf.HttpStatus = httpStatus
+	lf.ErrorCode = errorCode
+	lf.BytesSent = len(body)
+	lf.ObjectSize = meta.ObjectSize
+	lf.TotalTime = time.Since(startTime).Milliseconds()
+	lf.TurnAroundTime = time.Since(startTime).Milliseconds()
+	lf.Referer = ctx.Get("Referer")
+	lf.UserAgent = ctx.Get("User-Agent")
+	lf.VersionID = ctx.Query("versionId")
+	lf.HostID = ctx.Get("X-Amz-Id-2")
+	lf.SignatureVersion, lf.AuthenticationType = requestAuth(ctx)
+	lf.AccessPointARN = fmt.Sprintf("arn:aws:s3:::%v", strings.Join(path, "/"))
+	lf.AclRequired = "Yes"
+
+	for _, h := range auditedRequestHeaders {
+		if v := ctx.Get(h); v != "" {
+			if rec.RequestHeaders == nil {
+				rec.RequestHeaders = make(map[string]string)
+			}
+			rec.RequestHeaders[h] = v
+		}
+	}
+	for _, h := range auditedResponseHeaders {
+		if v := ctx.GetRespHeader(h); v != "" {
+			if rec.ResponseHeaders == nil {
+				rec.ResponseHeaders = make(map[string]string)
+			}
+			rec.ResponseHeaders[h] = v
+		}
+	}
+
+	return rec
+}
+
+// requestAuth returns the signature version and the authentication type
+// the request was authenticated with, these are empty for the anonymous
+// requests
+func requestAuth(ctx *fiber.Ctx) (string, string) {
+	sigVersion, _ := utils.ContextKeySignatureVersion.Get(ctx).(string)
+	authType, _ := utils.ContextKeyAuthType.Get(ctx).(string)
+	return sigVersion, authType
+}
+
+// formatText formats the log fields as an AWS server access log line
+func formatText(lf LogFields) string {
+	if lf.BucketOwner == "" {
+		lf.BucketOwner = "-"
+	}
+	if lf.Bucket == "" {
+		lf.Bucket = "-"
+	}
+	if lf.RemoteIP == "" {
+		lf.RemoteIP = "-"
+	}
+	if lf.Requester == "" {
+		lf.Requester = "-"
+	}
+	if lf.Operation == "" {
+		lf.Operation = "-"
+	}
+	if lf.Key == "" {
+		lf.Key = "-"
+	}
+	if lf.RequestURI == "" {
+		lf.RequestURI = "-"
+	}
+	if lf.ErrorCode == "" {
+		lf.ErrorCode = "-"
+	}
+	if lf.Referer == "" {
+		lf.Referer = "-"
+	}
+	if lf.UserAgent == "" {
+		lf.UserAgent = "-"
+	}
+	if lf.VersionID == "" {
+		lf.VersionID = "-"
+	}
+	if lf.HostID == "" {
+		lf.HostID = "-"
+	}
+	if lf.SignatureVersion == "" {
+		lf.SignatureVersion = "-"
+	}
+	if lf.CipherSuite == "" {
+		lf.CipherSuite = "-"
+	}
+	if lf.AuthenticationType == "" {
+		lf.AuthenticationType = "-"
+	}
+	if lf.HostHeader == "" {
+		lf.HostHeader = "-"
+	}
+	if lf.TLSVersion == "" {
+		lf.TLSVersion = "-"
+	}
+
+	return fmt.Sprintf("%v %v %v %v %v %v %v %v %v %v %v %v %v %v %v %v %v %v %v %v %v %v %v %v %v %v",
+		lf.BucketOwner,
+		lf.Bucket,
+		fmt.Sprintf("[%v]", lf.Time.Format(timeFormat)),
+		lf.RemoteIP,
+		lf.Requester,
+		lf.RequestID,
+		lf.Operation,
+		lf.Key,
+		lf.RequestURI,
+		lf.HttpStatus,
+		lf.ErrorCode,
+		lf.BytesSent,
+		lf.ObjectSize,
+		lf.TotalTime,
+		lf.TurnAroundTime,
+		lf.Referer,
+		lf.UserAgent,
+		lf.VersionID,
+		lf.HostID,
+		lf.SignatureVersion,
+		lf.CipherSuite,
+		lf.AuthenticationType,
+		lf.HostHeader,
+		lf.TLSVersion,
+		lf.AccessPointARN,
+		lf.AclRequired,
+	)
+}
+
+// formatRecord formats the audit record in the given format
+// without the trailing newline
+func formatRecord(rec AuditRecord, format LogFormat) (string, error) {
+	if format != LogFormatJSON {
+		return formatText(rec.LogFields), nil
+	}
+
+	b, err := json.Marshal(rec)
+	if err != nil {
+		return "", fmt.Errorf("marshal audit record: %w", err)
+	}
+	return string(b), nil
+}
+
 func genID() string {
 	src := rand.New(rand.NewSource(time.Now().UnixNano()))
 	b := make([]byte, 8)
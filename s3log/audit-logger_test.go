@@ -0,0 +1,113 @@
+// Copyright 2026 Versity Software
+// This file is licensed under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package s3log
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/versity/versitygw/auth"
+	"github.com/versity/versitygw/s3api/utils"
+)
+
+func TestNewAuditRecord_Auth(t *testing.T) {
+	tests := []struct {
+		name       string
+		setup      func(ctx *fiber.Ctx)
+		sigVersion string
+		authType   string
+		tenant     string
+	}{
+		{
+			name: "sigv4 header",
+			setup: func(ctx *fiber.Ctx) {
+				utils.ContextKeyAccount.Set(ctx, auth.Account{Access: "user1", ProjectID: 7})
+				utils.SetRequestAuth(ctx, utils.SignatureVersionV4, utils.AuthTypeHeader)
+			},
+			sigVersion: "SigV4",
+			authType:   "AuthHeader",
+			tenant:     auth.GetTenantID("user1"),
+		},
+		{
+			name: "sigv4a presigned",
+			setup: func(ctx *fiber.Ctx) {
+				utils.ContextKeyAccount.Set(ctx, auth.Account{Access: "user2"})
+				utils.SetRequestAuth(ctx, utils.SignatureVersionV4A, utils.AuthTypeQuery)
+			},
+			sigVersion: "SigV4A",
+			authType:   "QueryString",
+			tenant:     auth.GetTenantID("user2"),
+		},
+		{
+			name: "sigv2 header",
+			setup: func(ctx *fiber.Ctx) {
+				utils.ContextKeyAccount.Set(ctx, auth.Account{Access: "user3"})
+				utils.SetRequestAuth(ctx, utils.SignatureVersionV2, utils.AuthTypeHeader)
+			},
+			sigVersion: "SigV2",
+			authType:   "AuthHeader",
+			tenant:     auth.GetTenantID("user3"),
+		},
+		{
+			name: "anonymous",
+			setup: func(ctx *fiber.Ctx) {
+				utils.ContextKeyAccount.Set(ctx, auth.Account{})
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var rec AuditRecord
+			app := fiber.New()
+			app.Get("/*", func(ctx *fiber.Ctx) error {
+				tt.setup(ctx)
+				rec = newAuditRecord(ctx, nil, nil, LogMeta{Action: "GetObject"})
+				return nil
+			})
+			resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/bucket/object", nil))
+			if err != nil {
+				t.Fatalf("request: %v", err)
+			}
+			resp.Body.Close()
+
+			if rec.SignatureVersion != tt.sigVersion {
+				t.Errorf("expected signature version %q, got %q", tt.sigVersion, rec.SignatureVersion)
+			}
+			if rec.AuthenticationType != tt.authType {
+				t.Errorf("expected authentication type %q, got %q", tt.authType, rec.AuthenticationType)
+			}
+			if rec.Tenant != tt.tenant {
+				t.Errorf("expected tenant %q, got %q", tt.tenant, rec.Tenant)
+			}
+
+			// the missing text log fields are "-"
+			fields := strings.Fields(formatText(rec.LogFields))
+			if len(fields) != 27 {
+				t.Fatalf("unexpected text log line %q", formatText(rec.LogFields))
+			}
+			expected := tt.sigVersion
+			if expected == "" {
+				expected = "-"
+			}
+			if fields[20] != expected {
+				t.Errorf("expected the text signature version %q, got %q", expected, fields[20])
+			}
+		})
+	}
+}
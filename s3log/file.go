@@ -15,17 +15,11 @@
 package s3log
 
 import (
-	"crypto/tls"
 	"fmt"
 	"os"
-	"strings"
 	"sync"
-	"time"
 
 	"github.com/gofiber/fiber/v2"
-	"github.com/versity/versitygw/auth"
-	"github.com/versity/versitygw/s3api/utils"
-	"github.com/versity/versitygw/s3err"
 )
 
 const (
@@ -36,7 +30,8 @@ const (
 // FileLogger is a local file audit log
 type FileLogger struct {
 	logfile string
-	f       *os.File
+	format  LogFormat
+	w       *logWriter
 	gotErr  bool
 	mu      sync.Mutex
 }
@@ -44,15 +39,13 @@ type FileLogger struct {
 var _ AuditLogger = &FileLogger{}
 
 // InitFileLogger initializes audit logs to local file
-func InitFileLogger(logname string) (AuditLogger, error) {
-	f, err := os.OpenFile(logname, os.O_APPEND|os.O_CREATE|os.O_WRONLY, logFileMode)
+func InitFileLogger(logname string, format LogFormat, rotate RotateConfig) (AuditLogger, error) {
+	w, err := openLogWriter(logname, rotate)
 	if err != nil {
-		return nil, fmt.Errorf("open log: %w", err)
+		return nil, err
 	}
 
-	fmt.Fprintf(f, "log starts %v\n", time.Now())
-
-	return &FileLogger{logfile: logname, f: f}, nil
+	return &FileLogger{logfile: logname, format: format, w: w}, nil
 }
 
 // Log sends log message to file logger
@@ -64,152 +57,32 @@ func (f *FileLogger) Log(ctx *fiber.Ctx, err error, body []byte, meta LogMeta) {
 		return
 	}
 
-	lf := LogFields{}
-
-	access := "-"
-	reqURI := ctx.OriginalURL()
-	path := strings.Split(ctx.Path(), "/")
-	var bucket, object string
-	if len(path) > 1 {
-		bucket, object = path[1], strings.Join(path[2:], "/")
-	}
-	errorCode := ""
-	httpStatus := 200
-	startTime, ok := utils.ContextKeyStartTime.Get(ctx).(time.Time)
-	if !ok {
-		startTime = time.Now()
-	}
-	tlsConnState := ctx.Context().TLSConnectionState()
-	if tlsConnState != nil {
-		lf.CipherSuite = tls.CipherSuiteName(tlsConnState.CipherSuite)
-		lf.TLSVersion = getTLSVersionName(tlsConnState.Version)
-	}
+	rec := newAuditRecord(ctx, err, body, meta)
 
-	if err != nil {
-		serr, ok := err.(s3err.APIError)
-		if ok {
-			errorCode = serr.Code
-			httpStatus = serr.HTTPStatusCode
-		} else {
-			errorCode = err.Error()
-			httpStatus = 500
-		}
+	if f.format == LogFormatJSON {
+		f.writeRecord(rec)
+		return
 	}
 
-	acct, ok := utils.ContextKeyAccount.Get(ctx).(auth.Account)
-	if ok {
-		access = acct.Access
-	}
+	f.writeLog(rec.LogFields)
+}
 
-	region, ok := utils.ContextKeyRegion.Get(ctx).(string)
-	if ok {
-		lf.HostHeader = fmt.Sprintf("s3.%v.amazonaws.com", region)
+func (f *FileLogger) writeRecord(rec AuditRecord) {
+	log, err := formatRecord(rec, LogFormatJSON)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error formatting log record: %v\n", err)
+		return
 	}
 
-	lf.BucketOwner = meta.BucketOwner
-	lf.Bucket = bucket
-	lf.Time = time.Now()
-	lf.RemoteIP = ctx.IP()
-	lf.Requester = access
-	lf.RequestID = genID()
-	lf.Operation = meta.Action
-	lf.Key = object
-	lf.RequestURI = reqURI
-	lf.HttpStatus = httpStatus
-	lf.ErrorCode = errorCode
-	lf.BytesSent = len(body)
-	lf.ObjectSize = meta.ObjectSize
-	lf.TotalTime = time.Since(startTime).Milliseconds()
-	lf.TurnAroundTime = time.Since(startTime).Milliseconds()
-	lf.Referer = ctx.Get("Referer")
-	lf.UserAgent = ctx.Get("User-Agent")
-	lf.VersionID = ctx.Query("versionId")
-	lf.HostID = ctx.Get("X-Amz-Id-2")
-	lf.SignatureVersion = "SigV4"
-	lf.AuthenticationType = "AuthHeader"
-	lf.AccessPointARN = fmt.Sprintf("arn:aws:s3:::%v", strings.Join(path, "/"))
-	lf.AclRequired = "Yes"
-
-	f.writeLog(lf)
+	f.write(log + "\n")
 }
 
 func (f *FileLogger) writeLog(lf LogFields) {
-	if lf.BucketOwner == "" {
-		lf.BucketOwner = "-"
-	}
-	if lf.Bucket == "" {
-		lf.Bucket = "-"
-	}
-	if lf.RemoteIP == "" {
-		lf.RemoteIP = "-"
-	}
-	if lf.Requester == "" {
-		lf.Requester = "-"
-	}
-	if lf.Operation == "" {
-		lf.Operation = "-"
-	}
-	if lf.Key == "" {
-		lf.Key = "-"
-	}
-	if lf.RequestURI == "" {
-		lf.RequestURI = "-"
-	}
-	if lf.ErrorCode == "" {
-		lf.ErrorCode = "-"
-	}
-	if lf.Referer == "" {
-		lf.Referer = "-"
-	}
-	if lf.UserAgent == "" {
-		lf.UserAgent = "-"
-	}
-	if lf.VersionID == "" {
-		lf.VersionID = "-"
-	}
-	if lf.HostID == "" {
-		lf.HostID = "-"
-	}
-	if lf.CipherSuite == "" {
-		lf.CipherSuite = "-"
-	}
-	if lf.HostHeader == "" {
-		lf.HostHeader = "-"
-	}
-	if lf.TLSVersion == "" {
-		lf.TLSVersion = "-"
-	}
+	f.write(formatText(lf) + "\n")
+}
 
-	log := fmt.Sprintf("%v %v %v %v %v %v %v %v %v %v %v %v %v %v %v %v %v %v %v %v %v %v %v %v %v %v\n",
-		lf.BucketOwner,
-		lf.Bucket,
-		fmt.Sprintf("[%v]", lf.Time.Format(timeFormat)),
-		lf.RemoteIP,
-		lf.Requester,
-		lf.RequestID,
-		lf.Operation,
-		lf.Key,
-		lf.RequestURI,
-		lf.HttpStatus,
-		lf.ErrorCode,
-		lf.BytesSent,
-		lf.ObjectSize,
-		lf.TotalTime,
-		lf.TurnAroundTime,
-		lf.Referer,
-		lf.UserAgent,
-		lf.VersionID,
-		lf.HostID,
-		lf.SignatureVersion,
-		lf.CipherSuite,
-		lf.AuthenticationType,
-		lf.HostHeader,
-		lf.TLSVersion,
-		lf.AccessPointARN,
-		lf.AclRequired,
-	)
-
-	_, err := f.f.WriteString(log)
+func (f *FileLogger) write(log string) {
+	_, err := f.w.WriteString(log)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "error writing to log file: %v\n", err)
 		// TODO: do we need to terminate on log error?
@@ -221,22 +94,22 @@ func (f *FileLogger) writeLog(lf LogFields) {
 // HangUp closes current logfile handle and opens a new one
 // typically needed for log rotations
 func (f *FileLogger) HangUp() error {
-	err := f.f.Close()
-	if err != nil {
-		return fmt.Errorf("close log: %w", err)
-	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
 
-	f.f, err = os.OpenFile(f.logfile, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	err := f.w.Reopen()
 	if err != nil {
-		return fmt.Errorf("open log: %w", err)
+		return err
 	}
 
-	f.f.WriteString(fmt.Sprintf("log starts %v\n", time.Now()))
-
+	f.gotErr = false
 	return nil
 }
 
 // Shutdown closes logfile handle
 func (f *FileLogger) Shutdown() error {
-	return f.f.Close()
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.w.Close()
 }
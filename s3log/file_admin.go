@@ -17,7 +17,6 @@ package s3log
 import (
 	"crypto/tls"
 	"fmt"
-	"os"
 	"time"
 
 	"github.com/gofiber/fiber/v2"
@@ -33,15 +32,13 @@ type AdminFileLogger struct {
 var _ AuditLogger = &AdminFileLogger{}
 
 // InitFileLogger initializes audit logs to local file
-func InitAdminFileLogger(logname string) (AuditLogger, error) {
-	f, err := os.OpenFile(logname, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+func InitAdminFileLogger(logname string, rotate RotateConfig) (AuditLogger, error) {
+	w, err := openLogWriter(logname, rotate)
 	if err != nil {
-		return nil, fmt.Errorf("open log: %w", err)
+		return nil, err
 	}
 
-	f.WriteString(fmt.Sprintf("log starts %v\n", time.Now()))
-
-	return &AdminFileLogger{FileLogger: FileLogger{logfile: logname, f: f}}, nil
+	return &AdminFileLogger{FileLogger: FileLogger{logfile: logname, w: w}}, nil
 }
 
 // Log sends log message to file logger
@@ -90,8 +87,7 @@ func (f *AdminFileLogger) Log(ctx *fiber.Ctx, err error, body []byte, meta LogMe
 	lf.TurnAroundTime = time.Since(startTime).Milliseconds()
 	lf.Referer = ctx.Get("Referer")
 	lf.UserAgent = ctx.Get("User-Agent")
-	lf.SignatureVersion = "SigV4"
-	lf.AuthenticationType = "AuthHeader"
+	lf.SignatureVersion, lf.AuthenticationType = requestAuth(ctx)
 
 	f.writeLog(lf)
 }
@@ -118,14 +114,20 @@ func (f *AdminFileLogger) writeLog(lf AdminLogFields) {
 	if lf.UserAgent == "" {
 		lf.UserAgent = "-"
 	}
+	if lf.SignatureVersion == "" {
+		lf.SignatureVersion = "-"
+	}
 	if lf.CipherSuite == "" {
 		lf.CipherSuite = "-"
 	}
+	if lf.AuthenticationType == "" {
+		lf.AuthenticationType = "-"
+	}
 	if lf.TLSVersion == "" {
 		lf.TLSVersion = "-"
 	}
 
-	log := fmt.Sprintf("%v %v %v %v %v %v %v %v %v %v %v %v %v %v %v %v %v\n",
+	f.write(fmt.Sprintf("%v %v %v %v %v %v %v %v %v %v %v %v %v %v %v %v %v\n",
 		fmt.Sprintf("[%v]", lf.Time.Format(timeFormat)),
 		lf.RemoteIP,
 		lf.Requester,
@@ -143,13 +145,5 @@ func (f *AdminFileLogger) writeLog(lf AdminLogFields) {
 		lf.CipherSuite,
 		lf.AuthenticationType,
 		lf.TLSVersion,
-	)
-
-	_, err := f.f.WriteString(log)
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "error writing to log file: %v\n", err)
-		// TODO: do we need to terminate on log error?
-		// set err for now so that we don't spew errors
-		f.gotErr = true
-	}
+	))
 }
@@ -0,0 +1,76 @@
+// Copyright 2026 Versity Software
+// This file is licensed under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package s3log
+
+import (
+	"sync"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// ReloadableLogger is an audit logger whose underlying logger can be
+// replaced while the gateway is serving requests. A nil underlying
+// logger drops the log records.
+type ReloadableLogger struct {
+	mu     sync.RWMutex
+	logger AuditLogger
+}
+
+var _ AuditLogger = &ReloadableLogger{}
+
+// NewReloadableLogger wraps the logger, the logger may be nil
+func NewReloadableLogger(logger AuditLogger) *ReloadableLogger {
+	return &ReloadableLogger{logger: logger}
+}
+
+// Swap replaces the underlying logger and shuts down the
+// previous one after the in-flight records are logged
+func (l *ReloadableLogger) Swap(logger AuditLogger) error {
+	l.mu.Lock()
+	old := l.logger
+	l.logger = logger
+	l.mu.Unlock()
+
+	if old == nil {
+		return nil
+	}
+	return old.Shutdown()
+}
+
+// Log sends the record to the current logger
+func (l *ReloadableLogger) Log(ctx *fiber.Ctx, err error, body []byte, meta LogMeta) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	if l.logger != nil {
+		l.logger.Log(ctx, err, body, meta)
+	}
+}
+
+// HangUp reopens the current logger
+func (l *ReloadableLogger) HangUp() error {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	if l.logger == nil {
+		return nil
+	}
+	return l.logger.HangUp()
+}
+
+// Shutdown shuts down the current logger
+func (l *ReloadableLogger) Shutdown() error {
+	return l.Swap(nil)
+}
@@ -0,0 +1,283 @@
+// Copyright 2023 Versity Software
+// This file is licensed under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package s3log
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const rotateTimeFormat = "20060102T150405.000"
+
+// RotateConfig controls the built-in log file rotation. A zero value
+// disables built-in rotation, leaving rotation to external tools
+// that signal the gateway with SIGHUP.
+type RotateConfig struct {
+	// MaxSize rotates the log file once it grows beyond this many bytes
+	MaxSize int64
+	// MaxAge rotates the log file once it has been open this long
+	MaxAge time.Duration
+	// MaxBackups is the number of rotated files to keep, 0 keeps all
+	MaxBackups int
+	// Compress gzips the rotated log files
+	Compress bool
+}
+
+func (c RotateConfig) enabled() bool {
+	return c.MaxSize > 0 || c.MaxAge > 0
+}
+
+// logWriter is an append only log file that rotates itself based on
+// the RotateConfig. logWriter is not safe for concurrent use, callers
+// are expected to serialize the writes.
+type logWriter struct {
+	name   string
+	cfg    RotateConfig
+	f      *os.File
+	size   int64
+	opened time.Time
+	// rotated is the timestamp of the last rotated file
+	rotated time.Time
+
+	// wg tracks the background compression of the rotated files
+	wg sync.WaitGroup
+	// backupMu serializes the compression and pruning of the
+	// rotated files
+	backupMu sync.Mutex
+}
+
+func openLogWriter(name string, cfg RotateConfig) (*logWriter, error) {
+	w := &logWriter{name: name, cfg: cfg}
+	err := w.open()
+	if err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+func (w *logWriter) open() error {
+	f, err := os.OpenFile(w.name, os.O_APPEND|os.O_CREATE|os.O_WRONLY, logFileMode)
+	if err != nil {
+		return fmt.Errorf("open log: %w", err)
+	}
+
+	fi, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return fmt.Errorf("stat log: %w", err)
+	}
+
+	w.f = f
+	w.size = fi.Size()
+	w.opened = time.Now()
+
+	return w.writeRaw(fmt.Sprintf("log starts %v\n", w.opened))
+}
+
+func (w *logWriter) writeRaw(s string) error {
+	n, err := w.f.WriteString(s)
+	w.size += int64(n)
+	return err
+}
+
+// WriteString writes the log line, rotating the file first if
+// the rotation thresholds have been reached
+func (w *logWriter) WriteString(s string) (int, error) {
+	if w.needsRotate(int64(len(s))) {
+		err := w.rotate()
+		if err != nil {
+			// keep logging to the current file, the rotation is
+			// retried once the thresholds are reached again
+			fmt.Fprintf(os.Stderr, "%v\n", err)
+			w.size = 0
+			w.opened = time.Now()
+		}
+	}
+
+	n, err := w.f.WriteString(s)
+	w.size += int64(n)
+	return n, err
+}
+
+func (w *logWriter) needsRotate(next int64) bool {
+	if !w.cfg.enabled() {
+		return false
+	}
+	if w.cfg.MaxSize > 0 && w.size+next > w.cfg.MaxSize {
+		return true
+	}
+	if w.cfg.MaxAge > 0 && time.Since(w.opened) >= w.cfg.MaxAge {
+		return true
+	}
+	return false
+}
+
+// rotate renames the current log file with a timestamp suffix and
+// starts a new one. The rotated file is compressed and old backups
+// are pruned in the background. The current file is left open until
+// the new one is opened, so that it can still be written to if the
+// rotation fails.
+func (w *logWriter) rotate() error {
+	// the rotated file names have to be unique, even if the file
+	// is rotated more than once within the timestamp precision
+	stamp := time.Now().Truncate(time.Millisecond)
+	if !stamp.After(w.rotated) {
+		stamp = w.rotated.Add(time.Millisecond)
+	}
+
+	rotated := w.name + "." + stamp.Format(rotateTimeFormat)
+	err := os.Rename(w.name, rotated)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("rotate log: %w", err)
+	}
+	w.rotated = stamp
+
+	prev := w.f
+	err = w.open()
+	if w.f == prev {
+		// the new file could not be opened, move the current file
+		// back to the log file name
+		os.Rename(rotated, w.name)
+		return err
+	}
+	prev.Close()
+
+	w.wg.Add(1)
+	go func() {
+		defer w.wg.Done()
+		w.backupMu.Lock()
+		defer w.backupMu.Unlock()
+
+		if w.cfg.Compress {
+			err := compressLogFile(rotated)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "compress rotated log %v: %v\n", rotated, err)
+			}
+		}
+		err := pruneLogBackups(w.name, w.cfg.MaxBackups)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "prune rotated logs: %v\n", err)
+		}
+	}()
+
+	return err
+}
+
+// Reopen opens the log file again and closes the previous file handle,
+// typically needed after the file was rotated by an external tool
+func (w *logWriter) Reopen() error {
+	prev := w.f
+	err := w.open()
+	if w.f == prev {
+		return err
+	}
+	prev.Close()
+	return err
+}
+
+// Close closes the log file and waits for the pending compressions
+func (w *logWriter) Close() error {
+	err := w.f.Close()
+	w.wg.Wait()
+	return err
+}
+
+func compressLogFile(name string) error {
+	src, err := os.Open(name)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	dst, err := os.OpenFile(name+".gz", os.O_CREATE|os.O_TRUNC|os.O_WRONLY, logFileMode)
+	if err != nil {
+		return err
+	}
+
+	gz := gzip.NewWriter(dst)
+	_, err = io.Copy(gz, src)
+	if err == nil {
+		err = gz.Close()
+	}
+	if err == nil {
+		err = dst.Close()
+	} else {
+		dst.Close()
+	}
+	if err != nil {
+		os.Remove(name + ".gz")
+		return err
+	}
+
+	return os.Remove(name)
+}
+
+// pruneLogBackups removes the oldest rotated log files beyond max. A
+// rotated file interrupted while compressed might exist both uncompressed
+// and compressed, these are counted as a single backup.
+func pruneLogBackups(name string, max int) error {
+	if max <= 0 {
+		return nil
+	}
+
+	dir, base := filepath.Split(name)
+	if dir == "" {
+		dir = "."
+	}
+	ents, err := os.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+
+	// the backup files by the rotation timestamp
+	backups := make(map[string][]string)
+	for _, ent := range ents {
+		suffix, ok := strings.CutPrefix(ent.Name(), base+".")
+		if !ok {
+			continue
+		}
+		stamp := strings.TrimSuffix(suffix, ".gz")
+		if _, err := time.Parse(rotateTimeFormat, stamp); err != nil {
+			continue
+		}
+		backups[stamp] = append(backups[stamp], filepath.Join(dir, ent.Name()))
+	}
+	if len(backups) <= max {
+		return nil
+	}
+
+	// the timestamp suffix sorts lexically in chronological order
+	stamps := make([]string, 0, len(backups))
+	for stamp := range backups {
+		stamps = append(stamps, stamp)
+	}
+	sort.Strings(stamps)
+	for _, stamp := range stamps[:len(stamps)-max] {
+		for _, b := range backups[stamp] {
+			if err := os.Remove(b); err != nil && !os.IsNotExist(err) {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
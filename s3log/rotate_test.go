@@ -0,0 +1,247 @@
+// Copyright 2026 Versity Software
+// This file is licensed under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package s3log
+
+import (
+	"bufio"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// countLogLines counts the log lines of the file, without the
+// "log starts" header lines
+func countLogLines(t *testing.T, name string) int {
+	t.Helper()
+
+	f, err := os.Open(name)
+	if err != nil {
+		t.Fatalf("open %v: %v", name, err)
+	}
+	defer f.Close()
+
+	var r *bufio.Scanner
+	if strings.HasSuffix(name, ".gz") {
+		gz, err := gzip.NewReader(f)
+		if err != nil {
+			t.Fatalf("gzip %v: %v", name, err)
+		}
+		defer gz.Close()
+		r = bufio.NewScanner(gz)
+	} else {
+		r = bufio.NewScanner(f)
+	}
+
+	var n int
+	for r.Scan() {
+		if !strings.HasPrefix(r.Text(), "log starts") {
+			n++
+		}
+	}
+	return n
+}
+
+func TestLogWriter_Rotate(t *testing.T) {
+	dir := t.TempDir()
+	name := filepath.Join(dir, "access.log")
+
+	w, err := openLogWriter(name, RotateConfig{MaxSize: 256, Compress: true})
+	if err != nil {
+		t.Fatalf("open log writer: %v", err)
+	}
+
+	line := strings.Repeat("x", 63) + "\n"
+	const lines = 40
+	for range lines {
+		if _, err := w.WriteString(line); err != nil {
+			t.Fatalf("write: %v", err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+
+	ents, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("read dir: %v", err)
+	}
+
+	var total, backups int
+	for _, ent := range ents {
+		if ent.Name() != "access.log" {
+			if !strings.HasSuffix(ent.Name(), ".gz") {
+				t.Errorf("expected the rotated file %v to be compressed", ent.Name())
+			}
+			backups++
+		}
+		total += countLogLines(t, filepath.Join(dir, ent.Name()))
+	}
+	if backups < 2 {
+		t.Errorf("expected the log to be rotated, got %v backups", backups)
+	}
+	// the rotated files have unique names, so no lines are lost
+	if total != lines {
+		t.Errorf("expected %v log lines, got %v", lines, total)
+	}
+}
+
+func TestLogWriter_RotatePrune(t *testing.T) {
+	dir := t.TempDir()
+	name := filepath.Join(dir, "access.log")
+
+	w, err := openLogWriter(name, RotateConfig{MaxSize: 128, MaxBackups: 2})
+	if err != nil {
+		t.Fatalf("open log writer: %v", err)
+	}
+	line := strings.Repeat("x", 63) + "\n"
+	for range 20 {
+		if _, err := w.WriteString(line); err != nil {
+			t.Fatalf("write: %v", err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+
+	matches, err := filepath.Glob(name + ".*")
+	if err != nil {
+		t.Fatalf("glob: %v", err)
+	}
+	if len(matches) != 2 {
+		t.Errorf("expected 2 backups, got %v", matches)
+	}
+}
+
+func TestLogWriter_RotateFailed(t *testing.T) {
+	dir := t.TempDir()
+	name := filepath.Join(dir, "access.log")
+
+	w, err := openLogWriter(name, RotateConfig{MaxSize: 128})
+	if err != nil {
+		t.Fatalf("open log writer: %v", err)
+	}
+
+	// the rotated file name collides with a non-empty directory,
+	// so the rename fails
+	w.rotated = time.Now().Add(time.Hour).Truncate(time.Millisecond)
+	rotated := name + "." + w.rotated.Add(time.Millisecond).Format(rotateTimeFormat)
+	if err := os.MkdirAll(filepath.Join(rotated, "x"), 0755); err != nil {
+		t.Fatalf("create %v: %v", rotated, err)
+	}
+
+	line := strings.Repeat("x", 63) + "\n"
+	for range 4 {
+		if _, err := w.WriteString(line); err != nil {
+			t.Fatalf("write: %v", err)
+		}
+	}
+	if n := countLogLines(t, name); n != 4 {
+		t.Errorf("expected 4 log lines in the current file, got %v", n)
+	}
+
+	// the rotation is retried once the rename succeeds
+	if err := os.RemoveAll(rotated); err != nil {
+		t.Fatalf("remove %v: %v", rotated, err)
+	}
+	for range 4 {
+		if _, err := w.WriteString(line); err != nil {
+			t.Fatalf("write: %v", err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+
+	fi, err := os.Stat(rotated)
+	if err != nil {
+		t.Fatalf("expected the log to be rotated: %v", err)
+	}
+	if !fi.Mode().IsRegular() {
+		t.Errorf("expected the rotated log %v to be a file", rotated)
+	}
+	ents, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("read dir: %v", err)
+	}
+	var total int
+	for _, ent := range ents {
+		total += countLogLines(t, filepath.Join(dir, ent.Name()))
+	}
+	if total != 8 {
+		t.Errorf("expected 8 log lines, got %v", total)
+	}
+}
+
+func TestPruneLogBackups(t *testing.T) {
+	dir := t.TempDir()
+	name := filepath.Join(dir, "access.log")
+
+	stamp := func(d time.Duration) string {
+		base := time.Date(2026, 1, 2, 3, 4, 5, 0, time.Local)
+		return base.Add(d).Format(rotateTimeFormat)
+	}
+
+	files := []string{
+		"access.log",
+		// interrupted compression, both files are the same backup
+		"access.log." + stamp(0),
+		"access.log." + stamp(0) + ".gz",
+		"access.log." + stamp(time.Second) + ".gz",
+		// compression in progress of the newest backup
+		"access.log." + stamp(2*time.Second),
+		"access.log." + stamp(2*time.Second) + ".gz",
+		"access.log." + stamp(3*time.Second) + ".gz",
+		// not backups
+		"access.log.old",
+		"other.log." + stamp(0),
+	}
+	for _, f := range files {
+		if err := os.WriteFile(filepath.Join(dir, f), nil, 0644); err != nil {
+			t.Fatalf("create %v: %v", f, err)
+		}
+	}
+
+	if err := pruneLogBackups(name, 2); err != nil {
+		t.Fatalf("prune: %v", err)
+	}
+
+	expected := map[string]bool{
+		"access.log":                                 true,
+		"access.log." + stamp(2*time.Second):         true,
+		"access.log." + stamp(2*time.Second) + ".gz": true,
+		"access.log." + stamp(3*time.Second) + ".gz": true,
+		"access.log.old":                             true,
+		"other.log." + stamp(0):                      true,
+	}
+	ents, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("read dir: %v", err)
+	}
+	if len(ents) != len(expected) {
+		var names []string
+		for _, ent := range ents {
+			names = append(names, ent.Name())
+		}
+		t.Fatalf("expected %v files, got %v", len(expected), names)
+	}
+	for _, ent := range ents {
+		if !expected[ent.Name()] {
+			t.Errorf("unexpected file %v", ent.Name())
+		}
+	}
+}
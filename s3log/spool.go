@@ -0,0 +1,170 @@
+// Copyright 2023 Versity Software
+// This file is licensed under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package s3log
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const spoolFileSuffix = ".json"
+
+var errSpoolFull = errors.New("spool is full")
+
+// logSpool is a bounded on disk queue of undelivered log batches.
+// Each batch is stored in its own file named
+// <unix nano timestamp>-<record count>.json so that the batches are
+// replayed in order, including after a gateway restart.
+type logSpool struct {
+	mu      sync.Mutex
+	dir     string
+	maxSize int64
+	size    int64
+	entries []spoolEntry
+}
+
+type spoolEntry struct {
+	path    string
+	size    int64
+	records int
+}
+
+func newLogSpool(dir string, maxSize int64) (*logSpool, error) {
+	err := os.MkdirAll(dir, 0700)
+	if err != nil {
+		return nil, fmt.Errorf("create spool dir: %w", err)
+	}
+
+	s := &logSpool{dir: dir, maxSize: maxSize}
+
+	ents, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("read spool dir: %w", err)
+	}
+	for _, ent := range ents {
+		if !ent.Type().IsRegular() || !strings.HasSuffix(ent.Name(), spoolFileSuffix) {
+			continue
+		}
+		_, records, ok := parseSpoolName(ent.Name())
+		if !ok {
+			continue
+		}
+		fi, err := ent.Info()
+		if err != nil {
+			continue
+		}
+		s.entries = append(s.entries, spoolEntry{
+			path:    filepath.Join(dir, ent.Name()),
+			size:    fi.Size(),
+			records: records,
+		})
+		s.size += fi.Size()
+	}
+
+	// the zero padded timestamps sort lexically in chronological order
+	sort.Slice(s.entries, func(i, j int) bool {
+		return s.entries[i].path < s.entries[j].path
+	})
+
+	return s, nil
+}
+
+func parseSpoolName(name string) (int64, int, bool) {
+	ts, records, ok := strings.Cut(strings.TrimSuffix(name, spoolFileSuffix), "-")
+	if !ok {
+		return 0, 0, false
+	}
+	t, err := strconv.ParseInt(ts, 10, 64)
+	if err != nil {
+		return 0, 0, false
+	}
+	n, err := strconv.Atoi(records)
+	if err != nil {
+		return 0, 0, false
+	}
+	return t, n, true
+}
+
+// store writes the batch payload to the spool, new batches are
+// rejected once the spool max size is reached
+func (s *logSpool) store(payload []byte, records int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.maxSize > 0 && s.size+int64(len(payload)) > s.maxSize {
+		return errSpoolFull
+	}
+
+	name := fmt.Sprintf("%020d-%d%s", time.Now().UnixNano(), records, spoolFileSuffix)
+	path := filepath.Join(s.dir, name)
+	tmp := path + ".tmp"
+
+	err := os.WriteFile(tmp, payload, 0600)
+	if err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	err = os.Rename(tmp, path)
+	if err != nil {
+		os.Remove(tmp)
+		return err
+	}
+
+	s.entries = append(s.entries, spoolEntry{
+		path:    path,
+		size:    int64(len(payload)),
+		records: records,
+	})
+	s.size += int64(len(payload))
+
+	return nil
+}
+
+// oldest returns the oldest spooled batch
+func (s *logSpool) oldest() (spoolEntry, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(s.entries) == 0 {
+		return spoolEntry{}, false
+	}
+	return s.entries[0], true
+}
+
+// remove deletes the spooled batch
+func (s *logSpool) remove(entry spoolEntry) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i, e := range s.entries {
+		if e.path == entry.path {
+			s.entries = append(s.entries[:i], s.entries[i+1:]...)
+			s.size -= e.size
+			break
+		}
+	}
+
+	err := os.Remove(entry.path)
+	if err != nil && !os.IsNotExist(err) {
+		fmt.Fprintf(os.Stderr, "error removing spooled webhook log: %v\n", err)
+	}
+}
@@ -0,0 +1,361 @@
+// Copyright 2023 Versity Software
+// This file is licensed under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package s3log
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+const (
+	syslogDialTimeout  = 3 * time.Second
+	syslogWriteTimeout = 3 * time.Second
+	syslogDefaultTag   = "versitygw"
+	// RFC5424 limits the APP-NAME to 48 and the MSGID to 32 characters
+	syslogMaxAppName = 48
+	syslogMaxMsgID   = 32
+
+	// syslogQueueSize is the number of messages buffered while the
+	// syslog server is slow or unreachable, new messages are dropped
+	// once the queue is full
+	syslogQueueSize  = 10000
+	syslogMinBackoff = 250 * time.Millisecond
+	syslogMaxBackoff = 30 * time.Second
+
+	syslogSeverityWarning = 4
+	syslogSeverityInfo    = 6
+)
+
+var syslogFacilities = map[string]int{
+	"kern":     0,
+	"user":     1,
+	"daemon":   3,
+	"auth":     4,
+	"syslog":   5,
+	"authpriv": 10,
+	"local0":   16,
+	"local1":   17,
+	"local2":   18,
+	"local3":   19,
+	"local4":   20,
+	"local5":   21,
+	"local6":   22,
+	"local7":   23,
+}
+
+// SyslogLogger sends the audit logs as RFC5424 syslog messages. The
+// messages are queued and sent in the background, so an unreachable
+// syslog server doesn't stall the requests.
+type SyslogLogger struct {
+	network  string
+	addr     string
+	format   LogFormat
+	hostname string
+	tag      string
+	facility int
+
+	// conn and datagram are only used by the sending goroutine
+	conn     net.Conn
+	datagram bool
+
+	queue chan string
+	// hup requests the sending goroutine to reconnect
+	hup chan struct{}
+	// done interrupts the reconnect backoff on shutdown
+	done    chan struct{}
+	wg      sync.WaitGroup
+	dropped atomic.Int64
+
+	// closeMu guards sending on the queue against Shutdown
+	closeMu sync.RWMutex
+	closed  bool
+}
+
+var _ AuditLogger = &SyslogLogger{}
+
+// InitSyslogLogger initializes audit logs to a syslog server.
+// The url is of the form:
+//
+//	udp://host:514
+//	tcp://host:601
+//	unix:///dev/log
+//
+// optionally followed by the "facility" (default local0) and
+// "tag" (default versitygw) query parameters.
+// The records are sent in JSON format unless another format is requested.
+func InitSyslogLogger(rawURL string, format LogFormat) (AuditLogger, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("parse syslog url: %w", err)
+	}
+
+	sl := &SyslogLogger{
+		format:   format,
+		tag:      syslogDefaultTag,
+		facility: syslogFacilities["local0"],
+		queue:    make(chan string, syslogQueueSize),
+		hup:      make(chan struct{}, 1),
+		done:     make(chan struct{}),
+	}
+	if sl.format == "" {
+		sl.format = LogFormatJSON
+	}
+
+	switch u.Scheme {
+	case "udp", "tcp":
+		if u.Host == "" {
+			return nil, fmt.Errorf("missing syslog host in url %q", rawURL)
+		}
+		sl.network, sl.addr = u.Scheme, u.Host
+	case "unix":
+		if u.Path == "" {
+			return nil, fmt.Errorf("missing syslog socket path in url %q", rawURL)
+		}
+		sl.network, sl.addr = "unix", u.Path
+	default:
+		return nil, fmt.Errorf("unsupported syslog url scheme %q, expected one of: udp, tcp, unix", u.Scheme)
+	}
+
+	if tag := u.Query().Get("tag"); tag != "" {
+		sl.tag = tag
+	}
+	if len(sl.tag) > syslogMaxAppName {
+		sl.tag = sl.tag[:syslogMaxAppName]
+	}
+	if facility := u.Query().Get("facility"); facility != "" {
+		f, ok := syslogFacilities[strings.ToLower(facility)]
+		if !ok {
+			return nil, fmt.Errorf("invalid syslog facility %q", facility)
+		}
+		sl.facility = f
+	}
+
+	sl.hostname, err = os.Hostname()
+	if err != nil || sl.hostname == "" {
+		sl.hostname = "-"
+	}
+
+	err = sl.connect()
+	if err != nil {
+		return nil, fmt.Errorf("connect syslog: %w", err)
+	}
+
+	sl.wg.Add(1)
+	go sl.run()
+
+	return sl, nil
+}
+
+// connect dials the syslog server. For unix sockets the datagram
+// socket type is tried first, as used by most local syslog daemons.
+func (sl *SyslogLogger) connect() error {
+	sl.disconnect()
+
+	if sl.network == "unix" {
+		var err error
+		for _, network := range []string{"unixgram", "unix"} {
+			var conn net.Conn
+			conn, err = net.DialTimeout(network, sl.addr, syslogDialTimeout)
+			if err == nil {
+				sl.conn = conn
+				sl.datagram = network == "unixgram"
+				return nil
+			}
+		}
+		return err
+	}
+
+	conn, err := net.DialTimeout(sl.network, sl.addr, syslogDialTimeout)
+	if err != nil {
+		return err
+	}
+	sl.conn = conn
+	sl.datagram = sl.network == "udp"
+	return nil
+}
+
+// Log queues the log message to be sent to the syslog server
+func (sl *SyslogLogger) Log(ctx *fiber.Ctx, err error, body []byte, meta LogMeta) {
+	rec := newAuditRecord(ctx, err, body, meta)
+
+	msg, ferr := formatRecord(rec, sl.format)
+	if ferr != nil {
+		fmt.Fprintf(os.Stderr, "error formatting log record: %v\n", ferr)
+		return
+	}
+
+	severity := syslogSeverityInfo
+	if rec.HttpStatus >= 400 {
+		severity = syslogSeverityWarning
+	}
+
+	sl.enqueue(sl.formatMessage(severity, rec, msg))
+}
+
+func (sl *SyslogLogger) enqueue(msg string) {
+	sl.closeMu.RLock()
+	defer sl.closeMu.RUnlock()
+
+	if sl.closed {
+		return
+	}
+
+	select {
+	case sl.queue <- msg:
+	default:
+		// the queue is full, the syslog server can't keep up
+		sl.dropped.Add(1)
+	}
+}
+
+// run sends the queued messages until the logger is shut down
+func (sl *SyslogLogger) run() {
+	defer sl.wg.Done()
+
+	for msg := range sl.queue {
+		if sl.send(msg) {
+			continue
+		}
+		// shutting down with the syslog server unreachable
+		sl.dropped.Add(1)
+		for range sl.queue {
+			sl.dropped.Add(1)
+		}
+	}
+	sl.disconnect()
+}
+
+// formatMessage builds the RFC5424 message:
+// <PRI>VERSION TIMESTAMP HOSTNAME APP-NAME PROCID MSGID STRUCTURED-DATA MSG
+func (sl *SyslogLogger) formatMessage(severity int, rec AuditRecord, msg string) string {
+	msgID := rec.Operation
+	if msgID == "" {
+		msgID = "-"
+	}
+	if len(msgID) > syslogMaxMsgID {
+		msgID = msgID[:syslogMaxMsgID]
+	}
+
+	return fmt.Sprintf("<%d>1 %s %s %s %d %s - %s",
+		sl.facility*8+severity,
+		rec.Time.UTC().Format(time.RFC3339Nano),
+		sl.hostname,
+		sl.tag,
+		os.Getpid(),
+		msgID,
+		msg,
+	)
+}
+
+// send writes the message to the syslog server. The lost connections
+// are redialed with exponential backoff, while the new messages wait
+// in the queue. Returns false if the message couldn't be sent before
+// the logger was shut down.
+func (sl *SyslogLogger) send(msg string) bool {
+	backoff := syslogMinBackoff
+	var reported bool
+	for {
+		select {
+		case <-sl.hup:
+			sl.disconnect()
+		default:
+		}
+
+		var err error
+		if sl.conn == nil {
+			err = sl.connect()
+		}
+		if err == nil {
+			sl.conn.SetWriteDeadline(time.Now().Add(syslogWriteTimeout))
+			_, err = sl.conn.Write(sl.frame(msg))
+			if err == nil {
+				if reported {
+					fmt.Fprintf(os.Stderr, "syslog connection restored\n")
+				}
+				if n := sl.dropped.Swap(0); n > 0 {
+					fmt.Fprintf(os.Stderr, "dropped %v syslog audit logs\n", n)
+				}
+				return true
+			}
+			sl.disconnect()
+		}
+
+		if !reported {
+			fmt.Fprintf(os.Stderr, "error sending syslog log, retrying: %v\n", err)
+			reported = true
+		}
+
+		select {
+		case <-sl.done:
+			return false
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, syslogMaxBackoff)
+	}
+}
+
+func (sl *SyslogLogger) disconnect() {
+	if sl.conn != nil {
+		sl.conn.Close()
+		sl.conn = nil
+	}
+}
+
+// frame applies the RFC6587 octet counting framing for stream
+// connections, datagrams carry exactly one message
+func (sl *SyslogLogger) frame(msg string) []byte {
+	if sl.datagram {
+		return []byte(msg)
+	}
+	return []byte(fmt.Sprintf("%d %s", len(msg), msg))
+}
+
+// HangUp requests to reconnect to the syslog server before
+// sending the next message
+func (sl *SyslogLogger) HangUp() error {
+	select {
+	case sl.hup <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// Shutdown sends the queued messages and closes the syslog connection.
+// The messages are dropped if the syslog server is unreachable.
+func (sl *SyslogLogger) Shutdown() error {
+	sl.closeMu.Lock()
+	if sl.closed {
+		sl.closeMu.Unlock()
+		return nil
+	}
+	sl.closed = true
+	close(sl.queue)
+	close(sl.done)
+	sl.closeMu.Unlock()
+
+	sl.wg.Wait()
+	if n := sl.dropped.Swap(0); n > 0 {
+		fmt.Fprintf(os.Stderr, "dropped %v syslog audit logs\n", n)
+	}
+	return nil
+}
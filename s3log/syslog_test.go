@@ -0,0 +1,247 @@
+// Copyright 2026 Versity Software
+// This file is licensed under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package s3log
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// logRequest runs a request through a fiber app calling the logger
+func logRequest(t *testing.T, l AuditLogger, path string, meta LogMeta) {
+	t.Helper()
+
+	app := fiber.New()
+	app.Get("/*", func(ctx *fiber.Ctx) error {
+		l.Log(ctx, nil, nil, meta)
+		return nil
+	})
+	resp, err := app.Test(httptest.NewRequest(http.MethodGet, path, nil))
+	if err != nil {
+		t.Fatalf("request: %v", err)
+	}
+	resp.Body.Close()
+}
+
+// readSyslogFrame reads the RFC6587 octet counting "<len> <msg>" frame
+func readSyslogFrame(t *testing.T, r *bufio.Reader) string {
+	t.Helper()
+
+	size, err := r.ReadString(' ')
+	if err != nil {
+		t.Fatalf("read frame length: %v", err)
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(size))
+	if err != nil {
+		t.Fatalf("invalid frame length %q", size)
+	}
+	msg := make([]byte, n)
+	if _, err := io.ReadFull(r, msg); err != nil {
+		t.Fatalf("read frame: %v", err)
+	}
+	return string(msg)
+}
+
+func TestSyslogLogger_FormatMessage(t *testing.T) {
+	sl := &SyslogLogger{
+		hostname: "gw1",
+		tag:      "versitygw",
+		facility: syslogFacilities["local0"],
+	}
+
+	rec := AuditRecord{}
+	rec.Time = time.Date(2026, 3, 4, 5, 6, 7, 8000, time.FixedZone("", 3600))
+	rec.Operation = "GetObject"
+
+	msg := sl.formatMessage(syslogSeverityInfo, rec, `{"a":1}`)
+	expected := fmt.Sprintf("<134>1 2026-03-04T04:06:07.000008Z gw1 versitygw %v GetObject - {\"a\":1}", os.Getpid())
+	if msg != expected {
+		t.Errorf("expected %q, got %q", expected, msg)
+	}
+
+	// the missing MSGID is the nil value, and the long ones are truncated
+	rec.Operation = ""
+	if msg := sl.formatMessage(syslogSeverityWarning, rec, "m"); !strings.Contains(msg, " - - m") ||
+		!strings.HasPrefix(msg, "<132>1 ") {
+		t.Errorf("unexpected message %q", msg)
+	}
+	rec.Operation = strings.Repeat("o", 40)
+	if msg := sl.formatMessage(syslogSeverityInfo, rec, "m"); !strings.Contains(msg, " "+strings.Repeat("o", syslogMaxMsgID)+" - m") {
+		t.Errorf("expected the MSGID to be truncated, got %q", msg)
+	}
+}
+
+func TestSyslogLogger_UDP(t *testing.T) {
+	pc, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	defer pc.Close()
+
+	l, err := InitSyslogLogger("udp://"+pc.LocalAddr().String()+"?facility=local1&tag=vgwtest", LogFormatJSON)
+	if err != nil {
+		t.Fatalf("init syslog logger: %v", err)
+	}
+	defer l.Shutdown()
+
+	logRequest(t, l, "/bucket/object", LogMeta{Action: "GetObject"})
+
+	pc.SetReadDeadline(time.Now().Add(5 * time.Second))
+	buf := make([]byte, 64*1024)
+	n, _, err := pc.ReadFrom(buf)
+	if err != nil {
+		t.Fatalf("read syslog message: %v", err)
+	}
+	msg := string(buf[:n])
+
+	re := regexp.MustCompile(`^<142>1 \S+ \S+ vgwtest \d+ GetObject - (\{.*\})$`)
+	m := re.FindStringSubmatch(msg)
+	if m == nil {
+		t.Fatalf("unexpected syslog message %q", msg)
+	}
+	var rec AuditRecord
+	if err := json.Unmarshal([]byte(m[1]), &rec); err != nil {
+		t.Fatalf("unmarshal record: %v", err)
+	}
+	if rec.Bucket != "bucket" || rec.Key != "object" {
+		t.Errorf("unexpected record %+v", rec)
+	}
+}
+
+func TestSyslogLogger_TCPFraming(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	defer ln.Close()
+
+	l, err := InitSyslogLogger("tcp://"+ln.Addr().String(), LogFormatText)
+	if err != nil {
+		t.Fatalf("init syslog logger: %v", err)
+	}
+	defer l.Shutdown()
+
+	conn, err := ln.Accept()
+	if err != nil {
+		t.Fatalf("accept: %v", err)
+	}
+	defer conn.Close()
+
+	logRequest(t, l, "/bucket/a", LogMeta{Action: "GetObject"})
+	logRequest(t, l, "/bucket/b", LogMeta{Action: "PutObject"})
+
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	r := bufio.NewReader(conn)
+	for _, op := range []string{"GetObject", "PutObject"} {
+		msg := readSyslogFrame(t, r)
+		if !strings.Contains(msg, " "+op+" - ") {
+			t.Errorf("expected the %v message, got %q", op, msg)
+		}
+	}
+}
+
+func TestSyslogLogger_ServerDown(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+
+	l, err := InitSyslogLogger("tcp://"+ln.Addr().String(), LogFormatText)
+	if err != nil {
+		t.Fatalf("init syslog logger: %v", err)
+	}
+	conn, err := ln.Accept()
+	if err != nil {
+		t.Fatalf("accept: %v", err)
+	}
+
+	// the syslog server goes away
+	conn.Close()
+	ln.Close()
+
+	// the requests are not stalled by the unreachable syslog server
+	start := time.Now()
+	for i := range 200 {
+		logRequest(t, l, fmt.Sprintf("/bucket/obj%v", i), LogMeta{Action: "GetObject"})
+	}
+	if d := time.Since(start); d > 2*time.Second {
+		t.Errorf("logging blocked for %v with the syslog server down", d)
+	}
+
+	// the shutdown doesn't wait for the server to come back
+	done := make(chan error, 1)
+	go func() { done <- l.Shutdown() }()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("shutdown: %v", err)
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatalf("shutdown blocked with the syslog server down")
+	}
+
+	// no logs are queued after the shutdown
+	logRequest(t, l, "/bucket/late", LogMeta{Action: "GetObject"})
+}
+
+func TestSyslogLogger_Reconnect(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	defer ln.Close()
+
+	l, err := InitSyslogLogger("tcp://"+ln.Addr().String(), LogFormatText)
+	if err != nil {
+		t.Fatalf("init syslog logger: %v", err)
+	}
+	defer l.Shutdown()
+
+	first, err := ln.Accept()
+	if err != nil {
+		t.Fatalf("accept: %v", err)
+	}
+	first.Close()
+
+	// the reconnect is requested on HangUp, the message is sent
+	// on the new connection
+	l.HangUp()
+	logRequest(t, l, "/bucket/object", LogMeta{Action: "GetObject"})
+
+	conn, err := ln.Accept()
+	if err != nil {
+		t.Fatalf("accept: %v", err)
+	}
+	defer conn.Close()
+
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	msg := readSyslogFrame(t, bufio.NewReader(conn))
+	if !strings.Contains(msg, " GetObject - ") {
+		t.Errorf("unexpected message %q", msg)
+	}
+}
@@ -16,140 +16,363 @@ package s3log
 
 import (
 	"bytes"
-	"crypto/tls"
+	"compress/gzip"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"net"
 	"net/http"
 	"os"
-	"strings"
+	"strconv"
 	"sync"
 	"time"
 
 	"github.com/gofiber/fiber/v2"
-	"github.com/versity/versitygw/auth"
-	"github.com/versity/versitygw/s3api/utils"
-	"github.com/versity/versitygw/s3err"
+	"github.com/versity/versitygw/metrics"
 )
 
+const (
+	defaultWebhookBatchSize     = 1
+	defaultWebhookFlushInterval = time.Second
+	defaultWebhookQueueSize     = 10000
+	defaultWebhookMaxRetries    = 3
+	webhookRequestTimeout       = 5 * time.Second
+	webhookMinBackoff           = 250 * time.Millisecond
+	webhookMaxBackoff           = 5 * time.Second
+
+	// WebhookSignatureHeader carries "sha256=<hex>", the hex encoded
+	// HMAC-SHA256 of "<timestamp>.<body>", when a webhook HMAC secret
+	// is configured
+	WebhookSignatureHeader = "X-Versitygw-Signature"
+	// WebhookTimestampHeader is the unix time the request was signed at
+	WebhookTimestampHeader = "X-Versitygw-Timestamp"
+
+	metricAuditLogSent    = "audit_log_sent_count"
+	metricAuditLogDropped = "audit_log_dropped_count"
+	metricAuditLogSpooled = "audit_log_spooled_count"
+	metricAuditLogRetried = "audit_log_retry_count"
+)
+
+// WebhookConfig controls the delivery of the webhook audit logs.
+// The zero value sends each log entry in its own request.
+type WebhookConfig struct {
+	// BatchSize is the max number of log entries sent per request. With
+	// the batch size of 1 each request is a single JSON object, otherwise
+	// the requests are JSON arrays of the log entries.
+	BatchSize int
+	// FlushInterval is the max time a log entry waits for a batch to fill
+	FlushInterval time.Duration
+	// QueueSize is the number of log entries buffered in memory before
+	// new entries are dropped
+	QueueSize int
+	// MaxRetries is the number of retries of a failed request, 0 disables
+	// the retries and the default number of retries is used if negative
+	MaxRetries int
+	// Gzip compresses the request bodies
+	Gzip bool
+	// BearerToken is sent in the Authorization header when set
+	BearerToken string
+	// HMACSecret signs the request bodies when set
+	HMACSecret string
+	// SpoolDir stores the batches that could not be delivered, these
+	// are resent once the webhook is reachable again
+	SpoolDir string
+	// SpoolMaxSize is the max total size of the spooled batches in bytes,
+	// 0 means unlimited
+	SpoolMaxSize int64
+}
+
 // WebhookLogger is a webhook URL audit log
 type WebhookLogger struct {
-	mu  sync.Mutex
-	url string
+	url    string
+	cfg    WebhookConfig
+	client *http.Client
+	mm     metrics.Manager
+
+	queue chan AuditRecord
+	wg    sync.WaitGroup
+
+	// closeMu guards sending on the queue against Shutdown
+	closeMu sync.RWMutex
+	closed  bool
+
+	spool *logSpool
 }
 
 var _ AuditLogger = &WebhookLogger{}
 
+// errWebhookRejected is returned for requests that the webhook
+// refused and that won't succeed on retry
+var errWebhookRejected = errors.New("webhook rejected request")
+
 // InitWebhookLogger initializes audit logs to webhook URL
-func InitWebhookLogger(url string) (AuditLogger, error) {
+func InitWebhookLogger(url string, cfg WebhookConfig, mm metrics.Manager) (AuditLogger, error) {
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = defaultWebhookBatchSize
+	}
+	if cfg.FlushInterval <= 0 {
+		cfg.FlushInterval = defaultWebhookFlushInterval
+	}
+	if cfg.QueueSize <= 0 {
+		cfg.QueueSize = defaultWebhookQueueSize
+	}
+	if cfg.MaxRetries < 0 {
+		cfg.MaxRetries = defaultWebhookMaxRetries
+	}
+
 	client := &http.Client{
 		Timeout: 3 * time.Second,
 	}
-	_, err := client.Post(url, "application/json", nil)
+	resp, err := client.Post(url, "application/json", nil)
 	if err != nil {
 		if err, ok := err.(net.Error); ok && !err.Timeout() {
 			return nil, fmt.Errorf("unreachable webhook url: %w", err)
 		}
+	} else {
+		io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
 	}
-	return &WebhookLogger{
+
+	wl := &WebhookLogger{
 		url: url,
-	}, nil
+		cfg: cfg,
+		client: &http.Client{
+			Timeout: webhookRequestTimeout,
+			Transport: &http.Transport{
+				Proxy:               http.ProxyFromEnvironment,
+				MaxIdleConns:        4,
+				MaxIdleConnsPerHost: 4,
+				IdleConnTimeout:     90 * time.Second,
+			},
+		},
+		mm:    mm,
+		queue: make(chan AuditRecord, cfg.QueueSize),
+	}
+
+	if cfg.SpoolDir != "" {
+		wl.spool, err = newLogSpool(cfg.SpoolDir, cfg.SpoolMaxSize)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	wl.wg.Add(1)
+	go wl.run()
+
+	return wl, nil
 }
 
-// Log sends log message to webhook
+// Log queues the log message to be sent to the webhook
 func (wl *WebhookLogger) Log(ctx *fiber.Ctx, err error, body []byte, meta LogMeta) {
-	wl.mu.Lock()
-	defer wl.mu.Unlock()
+	rec := newAuditRecord(ctx, err, body, meta)
 
-	lf := LogFields{}
+	wl.closeMu.RLock()
+	defer wl.closeMu.RUnlock()
+
+	if wl.closed {
+		return
+	}
 
-	access := "-"
-	reqURI := ctx.OriginalURL()
-	path := strings.Split(ctx.Path(), "/")
-	var bucket, object string
-	if len(path) > 1 {
-		bucket, object = path[1], strings.Join(path[2:], "/")
+	select {
+	case wl.queue <- rec:
+	default:
+		// the queue is full, the webhook can't keep up
+		wl.count(metricAuditLogDropped, 1)
 	}
-	errorCode := ""
-	httpStatus := 200
-	startTime, ok := utils.ContextKeyStartTime.Get(ctx).(time.Time)
-	if !ok {
-		startTime = time.Now()
+}
+
+// run batches the queued log entries and sends them to the webhook
+func (wl *WebhookLogger) run() {
+	defer wl.wg.Done()
+
+	ticker := time.NewTicker(wl.cfg.FlushInterval)
+	defer ticker.Stop()
+
+	batch := make([]AuditRecord, 0, wl.cfg.BatchSize)
+	for {
+		select {
+		case rec, ok := <-wl.queue:
+			if !ok {
+				wl.flush(batch)
+				return
+			}
+			batch = append(batch, rec)
+			if len(batch) >= wl.cfg.BatchSize {
+				wl.flush(batch)
+				batch = batch[:0]
+			}
+		case <-ticker.C:
+			if len(batch) > 0 {
+				wl.flush(batch)
+				batch = batch[:0]
+			} else {
+				wl.replaySpool()
+			}
+		}
 	}
-	tlsConnState := ctx.Context().TLSConnectionState()
-	if tlsConnState != nil {
-		lf.CipherSuite = tls.CipherSuiteName(tlsConnState.CipherSuite)
-		lf.TLSVersion = getTLSVersionName(tlsConnState.Version)
+}
+
+// flush sends the batch, spooling it to disk if it can't be delivered
+func (wl *WebhookLogger) flush(batch []AuditRecord) {
+	if len(batch) == 0 {
+		return
 	}
 
+	var payload []byte
+	var err error
+	if wl.cfg.BatchSize == 1 {
+		payload, err = json.Marshal(batch[0])
+	} else {
+		payload, err = json.Marshal(batch)
+	}
 	if err != nil {
-		serr, ok := err.(s3err.APIError)
-		if ok {
-			errorCode = serr.Code
-			httpStatus = serr.HTTPStatusCode
-		} else {
-			errorCode = err.Error()
-			httpStatus = 500
+		fmt.Fprintf(os.Stderr, "failed to parse the log data: %v\n", err)
+		wl.count(metricAuditLogDropped, int64(len(batch)))
+		return
+	}
+
+	err = wl.send(payload)
+	if err == nil {
+		wl.count(metricAuditLogSent, int64(len(batch)))
+		wl.replaySpool()
+		return
+	}
+
+	fmt.Fprintf(os.Stderr, "error sending webhook log: %v\n", err)
+	if wl.spool == nil || errors.Is(err, errWebhookRejected) {
+		wl.count(metricAuditLogDropped, int64(len(batch)))
+		return
+	}
+
+	err = wl.spool.store(payload, len(batch))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error spooling webhook log: %v\n", err)
+		wl.count(metricAuditLogDropped, int64(len(batch)))
+		return
+	}
+	wl.count(metricAuditLogSpooled, int64(len(batch)))
+}
+
+// replaySpool resends the spooled batches oldest first, and stops at
+// the first failure
+func (wl *WebhookLogger) replaySpool() {
+	if wl.spool == nil {
+		return
+	}
+
+	for {
+		entry, ok := wl.spool.oldest()
+		if !ok {
+			return
+		}
+
+		payload, err := os.ReadFile(entry.path)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "error reading spooled webhook log: %v\n", err)
+			wl.spool.remove(entry)
+			wl.count(metricAuditLogDropped, int64(entry.records))
+			continue
+		}
+
+		err = wl.send(payload)
+		if err != nil && !errors.Is(err, errWebhookRejected) {
+			return
+		}
+		wl.spool.remove(entry)
+		if err != nil {
+			wl.count(metricAuditLogDropped, int64(entry.records))
+			continue
+		}
+		wl.count(metricAuditLogSent, int64(entry.records))
+	}
+}
+
+// send posts the payload to the webhook, retrying with exponential
+// backoff on network errors and server side failures
+func (wl *WebhookLogger) send(payload []byte) error {
+	body := payload
+	if wl.cfg.Gzip {
+		var buf bytes.Buffer
+		zw := gzip.NewWriter(&buf)
+		zw.Write(payload)
+		err := zw.Close()
+		if err != nil {
+			return fmt.Errorf("compress payload: %w", err)
+		}
+		body = buf.Bytes()
+	}
+
+	backoff := webhookMinBackoff
+	var err error
+	for attempt := 0; attempt <= wl.cfg.MaxRetries; attempt++ {
+		if attempt > 0 {
+			wl.count(metricAuditLogRetried, 1)
+			time.Sleep(backoff)
+			backoff = min(backoff*2, webhookMaxBackoff)
+		}
+
+		err = wl.post(body)
+		if err == nil || errors.Is(err, errWebhookRejected) {
+			return err
 		}
 	}
 
-	acct, ok := utils.ContextKeyAccount.Get(ctx).(auth.Account)
-	if ok {
-		access = acct.Access
-	}
-
-	lf.BucketOwner = meta.BucketOwner
-	lf.Bucket = bucket
-	lf.Time = time.Now()
-	lf.RemoteIP = ctx.IP()
-	lf.Requester = access
-	lf.RequestID = genID()
-	lf.Operation = meta.Action
-	lf.Key = object
-	lf.RequestURI = reqURI
-	lf.HttpStatus = httpStatus
-	lf.ErrorCode = errorCode
-	lf.BytesSent = len(body)
-	lf.ObjectSize = meta.ObjectSize
-	lf.TotalTime = time.Since(startTime).Milliseconds()
-	lf.TurnAroundTime = time.Since(startTime).Milliseconds()
-	lf.Referer = ctx.Get("Referer")
-	lf.UserAgent = ctx.Get("User-Agent")
-	lf.VersionID = ctx.Query("versionId")
-	lf.HostID = ctx.Get("X-Amz-Id-2")
-	lf.SignatureVersion = "SigV4"
-	lf.AuthenticationType = "AuthHeader"
-	lf.HostHeader = fmt.Sprintf("s3.%v.amazonaws.com", utils.ContextKeyRegion.Get(ctx).(string))
-	lf.AccessPointARN = fmt.Sprintf("arn:aws:s3:::%v", strings.Join(path, "/"))
-	lf.AclRequired = "Yes"
-
-	wl.sendLog(lf)
+	return err
 }
 
-func (wl *WebhookLogger) sendLog(lf LogFields) {
-	jsonLog, err := json.Marshal(lf)
+func (wl *WebhookLogger) post(body []byte) error {
+	req, err := http.NewRequest(http.MethodPost, wl.url, bytes.NewReader(body))
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "failed to parse the log data: %v\n", err.Error())
+		return fmt.Errorf("%w: %v", errWebhookRejected, err)
+	}
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	if wl.cfg.Gzip {
+		req.Header.Set("Content-Encoding", "gzip")
+	}
+	if wl.cfg.BearerToken != "" {
+		req.Header.Set("Authorization", "Bearer "+wl.cfg.BearerToken)
+	}
+	if wl.cfg.HMACSecret != "" {
+		ts := strconv.FormatInt(time.Now().Unix(), 10)
+		req.Header.Set(WebhookTimestampHeader, ts)
+		req.Header.Set(WebhookSignatureHeader, signWebhookBody(wl.cfg.HMACSecret, ts, body))
 	}
 
-	req, err := http.NewRequest(http.MethodPost, wl.url, bytes.NewReader(jsonLog))
+	resp, err := wl.client.Do(req)
 	if err != nil {
-		fmt.Fprintln(os.Stderr, err)
+		return err
 	}
-	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+
+	switch {
+	case resp.StatusCode < 300:
+		return nil
+	case resp.StatusCode == http.StatusTooManyRequests,
+		resp.StatusCode == http.StatusRequestTimeout,
+		resp.StatusCode >= 500:
+		return fmt.Errorf("webhook responded with status %v", resp.StatusCode)
+	default:
+		return fmt.Errorf("%w: status %v", errWebhookRejected, resp.StatusCode)
+	}
+}
 
-	go makeRequest(req)
+// signWebhookBody returns the "sha256=<hex>" HMAC signature of the body
+func signWebhookBody(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
 }
 
-func makeRequest(req *http.Request) {
-	client := &http.Client{
-		Timeout: 1 * time.Second,
-	}
-	_, err := client.Do(req)
-	if err != nil {
-		if err, ok := err.(net.Error); ok && !err.Timeout() {
-			fmt.Fprintf(os.Stderr, "error sending webhook log: %v\n", err)
-		}
+func (wl *WebhookLogger) count(key string, value int64) {
+	if wl.mm != nil && value > 0 {
+		wl.mm.Add(key, value)
 	}
 }
 
@@ -158,7 +381,17 @@ func (wl *WebhookLogger) HangUp() error {
 	return nil
 }
 
-// Shutdown does nothing for webhooks
+// Shutdown flushes the queued log entries and stops the webhook logger
 func (wl *WebhookLogger) Shutdown() error {
+	wl.closeMu.Lock()
+	if wl.closed {
+		wl.closeMu.Unlock()
+		return nil
+	}
+	wl.closed = true
+	close(wl.queue)
+	wl.closeMu.Unlock()
+
+	wl.wg.Wait()
 	return nil
 }
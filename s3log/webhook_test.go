@@ -0,0 +1,294 @@
+// Copyright 2026 Versity Software
+// This file is licensed under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package s3log
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// webhookReceiver records the request bodies posted to the test webhook
+type webhookReceiver struct {
+	mu     sync.Mutex
+	bodies [][]byte
+	// status is the response status, 0 responds with 200
+	status atomic.Int32
+	// attempts is the number of requests with a body received
+	attempts atomic.Int32
+}
+
+func (wr *webhookReceiver) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	if len(body) == 0 {
+		// the init probe has no body
+		return
+	}
+	wr.attempts.Add(1)
+	if status := wr.status.Load(); status != 0 {
+		w.WriteHeader(int(status))
+		return
+	}
+	wr.mu.Lock()
+	wr.bodies = append(wr.bodies, body)
+	wr.mu.Unlock()
+}
+
+func (wr *webhookReceiver) received() [][]byte {
+	wr.mu.Lock()
+	defer wr.mu.Unlock()
+	return append([][]byte(nil), wr.bodies...)
+}
+
+// waitFor polls cond until it is true or the test times out
+func waitFor(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %v", what)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func spooledFiles(t *testing.T, dir string) int {
+	t.Helper()
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("read spool dir: %v", err)
+	}
+	return len(entries)
+}
+
+func TestWebhookLogger_SingleObject(t *testing.T) {
+	wr := &webhookReceiver{}
+	srv := httptest.NewServer(wr)
+	defer srv.Close()
+
+	l, err := InitWebhookLogger(srv.URL, WebhookConfig{}, nil)
+	if err != nil {
+		t.Fatalf("init webhook logger: %v", err)
+	}
+
+	for _, path := range []string{"/bucket/a", "/bucket/b", "/bucket/c"} {
+		logRequest(t, l, path, LogMeta{Action: "GetObject"})
+	}
+	l.Shutdown()
+
+	bodies := wr.received()
+	if len(bodies) != 3 {
+		t.Fatalf("expected 3 requests, got %v", len(bodies))
+	}
+	for _, body := range bodies {
+		var rec AuditRecord
+		if err := json.Unmarshal(body, &rec); err != nil {
+			t.Fatalf("expected a JSON object, got %s: %v", body, err)
+		}
+		if rec.Operation != "GetObject" {
+			t.Errorf("expected operation GetObject, got %q", rec.Operation)
+		}
+	}
+}
+
+func TestWebhookLogger_Batch(t *testing.T) {
+	wr := &webhookReceiver{}
+	srv := httptest.NewServer(wr)
+	defer srv.Close()
+
+	l, err := InitWebhookLogger(srv.URL, WebhookConfig{
+		BatchSize:     2,
+		FlushInterval: time.Hour,
+	}, nil)
+	if err != nil {
+		t.Fatalf("init webhook logger: %v", err)
+	}
+
+	for _, path := range []string{"/bucket/a", "/bucket/b", "/bucket/c"} {
+		logRequest(t, l, path, LogMeta{Action: "PutObject"})
+	}
+	// the partial batch is flushed on shutdown
+	l.Shutdown()
+
+	bodies := wr.received()
+	if len(bodies) != 2 {
+		t.Fatalf("expected 2 requests, got %v", len(bodies))
+	}
+	for i, want := range []int{2, 1} {
+		var recs []AuditRecord
+		if err := json.Unmarshal(bodies[i], &recs); err != nil {
+			t.Fatalf("expected a JSON array, got %s: %v", bodies[i], err)
+		}
+		if len(recs) != want {
+			t.Errorf("request %v: expected %v entries, got %v", i, want, len(recs))
+		}
+	}
+}
+
+func TestWebhookLogger_Signature(t *testing.T) {
+	const secret = "secret"
+
+	var sigErr atomic.Value
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		ts := r.Header.Get(WebhookTimestampHeader)
+		sig := r.Header.Get(WebhookSignatureHeader)
+		if len(body) == 0 {
+			// the init probe is not signed
+			return
+		}
+
+		mac := hmac.New(sha256.New, []byte(secret))
+		mac.Write([]byte(ts + "."))
+		mac.Write(body)
+		want := "sha256=" + hex.EncodeToString(mac.Sum(nil))
+		if sig != want {
+			sigErr.Store("expected signature " + want + ", got " + sig)
+		}
+	}))
+	defer srv.Close()
+
+	l, err := InitWebhookLogger(srv.URL, WebhookConfig{HMACSecret: secret}, nil)
+	if err != nil {
+		t.Fatalf("init webhook logger: %v", err)
+	}
+	logRequest(t, l, "/bucket/a", LogMeta{Action: "GetObject"})
+	l.Shutdown()
+
+	if msg := sigErr.Load(); msg != nil {
+		t.Error(msg)
+	}
+}
+
+func TestWebhookLogger_SpoolReplay(t *testing.T) {
+	wr := &webhookReceiver{}
+	srv := httptest.NewServer(wr)
+	defer srv.Close()
+
+	dir := t.TempDir()
+	cfg := WebhookConfig{
+		FlushInterval: 20 * time.Millisecond,
+		MaxRetries:    1,
+		SpoolDir:      dir,
+	}
+
+	wr.status.Store(http.StatusServiceUnavailable)
+	l, err := InitWebhookLogger(srv.URL, cfg, nil)
+	if err != nil {
+		t.Fatalf("init webhook logger: %v", err)
+	}
+	logRequest(t, l, "/bucket/a", LogMeta{Action: "GetObject"})
+	logRequest(t, l, "/bucket/b", LogMeta{Action: "PutObject"})
+	l.Shutdown()
+
+	if n := spooledFiles(t, dir); n != 2 {
+		t.Fatalf("expected 2 spooled entries, got %v", n)
+	}
+
+	// a new logger replays the spool oldest first once the webhook is back
+	wr.status.Store(0)
+	l, err = InitWebhookLogger(srv.URL, cfg, nil)
+	if err != nil {
+		t.Fatalf("init webhook logger: %v", err)
+	}
+	defer l.Shutdown()
+
+	waitFor(t, "spool replay", func() bool {
+		return len(wr.received()) == 2
+	})
+
+	bodies := wr.received()
+	for i, want := range []string{"GetObject", "PutObject"} {
+		var rec AuditRecord
+		if err := json.Unmarshal(bodies[i], &rec); err != nil {
+			t.Fatalf("invalid replayed entry %s: %v", bodies[i], err)
+		}
+		if rec.Operation != want {
+			t.Errorf("replay %v: expected operation %v, got %q", i, want, rec.Operation)
+		}
+	}
+	waitFor(t, "spool cleanup", func() bool {
+		return spooledFiles(t, dir) == 0
+	})
+}
+
+func TestWebhookLogger_Retries(t *testing.T) {
+	tests := []struct {
+		retries  int
+		attempts int32
+	}{
+		{0, 1},
+		{1, 2},
+		{-1, defaultWebhookMaxRetries + 1},
+	}
+	for _, tt := range tests {
+		t.Run(strconv.Itoa(tt.retries), func(t *testing.T) {
+			wr := &webhookReceiver{}
+			srv := httptest.NewServer(wr)
+			defer srv.Close()
+
+			wr.status.Store(http.StatusServiceUnavailable)
+			l, err := InitWebhookLogger(srv.URL, WebhookConfig{MaxRetries: tt.retries}, nil)
+			if err != nil {
+				t.Fatalf("init webhook logger: %v", err)
+			}
+			logRequest(t, l, "/bucket/a", LogMeta{Action: "GetObject"})
+			l.Shutdown()
+
+			if n := wr.attempts.Load(); n != tt.attempts {
+				t.Errorf("expected %v attempts, got %v", tt.attempts, n)
+			}
+		})
+	}
+}
+
+func TestWebhookLogger_RejectedNotSpooled(t *testing.T) {
+	wr := &webhookReceiver{}
+	srv := httptest.NewServer(wr)
+	defer srv.Close()
+
+	dir := t.TempDir()
+	wr.status.Store(http.StatusBadRequest)
+	l, err := InitWebhookLogger(srv.URL, WebhookConfig{SpoolDir: dir}, nil)
+	if err != nil {
+		t.Fatalf("init webhook logger: %v", err)
+	}
+	logRequest(t, l, "/bucket/a", LogMeta{Action: "GetObject"})
+	l.Shutdown()
+
+	if n := spooledFiles(t, dir); n != 0 {
+		t.Errorf("expected rejected entries to be dropped, got %v spooled", n)
+	}
+}
+
+func TestSignWebhookBody(t *testing.T) {
+	sig := signWebhookBody("key", "1700000000", []byte(`{"a":1}`))
+	if !strings.HasPrefix(sig, "sha256=") || len(sig) != len("sha256=")+64 {
+		t.Errorf("unexpected signature format %q", sig)
+	}
+}
@@ -122,6 +122,17 @@ type GetObjectAttributesResponse struct {
 	VersionId    *string
 	LastModified *time.Time
 	DeleteMarker *bool
+	// Layout is the filesystem data layout of the object, returned
+	// in the response headers by the backends reporting it
+	Layout *ObjectLayout
+}
+
+// ObjectLayout is the striping of the object data over the storage
+// targets of a parallel filesystem
+type ObjectLayout struct {
+	StripeCount int
+	StripeSize  int64
+	Pool        string
 }
 
 type ObjectParts struct {
@@ -712,6 +723,64 @@ type ListBucketsResult struct {
 	Buckets []Bucket
 }
 
+// ScrubReport is the result of a data integrity scrub of a bucket
+type ScrubReport struct {
+	Bucket     string          `json:"bucket"`
+	StartTime  time.Time       `json:"startTime"`
+	EndTime    time.Time       `json:"endTime"`
+	Objects    int64           `json:"objects"`
+	Bytes      int64           `json:"bytes"`
+	Skipped    int64           `json:"skipped"`
+	Mismatches []ScrubMismatch `json:"mismatches" xml:"Mismatch"`
+}
+
+// ScrubMismatch is an object with data not matching the stored
+// checksum or etag
+type ScrubMismatch struct {
+	Key         string `json:"key"`
+	VersionId   string `json:"versionId,omitempty" xml:",omitempty"`
+	Size        int64  `json:"size"`
+	Algorithm   string `json:"algorithm"`
+	Expected    string `json:"expected"`
+	Actual      string `json:"actual"`
+	Quarantined bool   `json:"quarantined"`
+}
+
+// SnapshotStatus is the progress of the background creation of a
+// bucket snapshot
+type SnapshotStatus struct {
+	Snapshot  string    `json:"snapshot"`
+	Source    string    `json:"source"`
+	Status    string    `json:"status"`
+	Objects   int64     `json:"objects"`
+	StartTime time.Time `json:"startTime"`
+	EndTime   time.Time `json:"endTime"`
+	Error     string    `json:"error,omitempty" xml:",omitempty"`
+}
+
+const (
+	// SnapshotRunning is the status of the snapshots being created
+	SnapshotRunning = "running"
+	// SnapshotComplete is the status of the created snapshots
+	SnapshotComplete = "complete"
+	// SnapshotFailed is the status of the snapshots that failed to be
+	// created, the partially created snapshot bucket is removed
+	SnapshotFailed = "failed"
+)
+
+// ObjectChange is an object created, modified or removed outside of the
+// gateway, e.g. written directly to the backend filesystem
+type ObjectChange struct {
+	Bucket  string
+	Key     string
+	Removed bool
+	// Size is the object size, or the size of the removed object
+	Size int64
+	// SizeDelta is the change of the bytes used by the object
+	SizeDelta int64
+	ETag      string
+}
+
 type Checksum struct {
 	Algorithm types.ChecksumAlgorithm
 	Type      types.ChecksumType
@@ -733,3 +802,102 @@ type CreateBucketConfiguration struct {
 	LocationConstraint *string
 	TagSet             []types.Tag `xml:"Tags>Tag"`
 }
+
+// AdminList is a page of the admin json api list results. NextMarker
+// is set when the list is truncated and is passed as the marker
+// query parameter to get the next page.
+type AdminList[T any] struct {
+	Items      []T    `json:"items"`
+	NextMarker string `json:"nextMarker,omitempty"`
+}
+
+// AdminError is the admin json api error response body
+type AdminError struct {
+	Error AdminErrorDetail `json:"error"`
+}
+
+type AdminErrorDetail struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+	Status  int    `json:"status"`
+}
+
+// AdminUser is the admin json api user account. The secret access
+// key is accepted on creation, but never returned.
+type AdminUser struct {
+	Access    string `json:"access"`
+	Secret    string `json:"secret,omitempty"`
+	Role      string `json:"role"`
+	UserID    int    `json:"userID"`
+	GroupID   int    `json:"groupID"`
+	ProjectID int    `json:"projectID"`
+	Email     string `json:"email,omitempty"`
+}
+
+// AdminBucketOwner is the admin json api bucket owner change request
+type AdminBucketOwner struct {
+	Owner string `json:"owner"`
+}
+
+// AdminGatewayConfig is the gateway configuration reported by the
+// admin json api. It never includes any credentials.
+type AdminGatewayConfig struct {
+	Backend           string   `json:"backend"`
+	Region            string   `json:"region"`
+	Ports             []string `json:"ports"`
+	AdminPorts        []string `json:"adminPorts,omitempty"`
+	TLS               bool     `json:"tls"`
+	ReadOnly          bool     `json:"readOnly"`
+	VirtualDomain     string   `json:"virtualDomain,omitempty"`
+	HealthPath        string   `json:"healthPath,omitempty"`
+	ReadinessPath     string   `json:"readinessPath,omitempty"`
+	CORSAllowOrigin   string   `json:"corsAllowOrigin,omitempty"`
+	DisableACLs       bool     `json:"disableACLs"`
+	SigV2             bool     `json:"sigV2"`
+	PublicAccessBlock string   `json:"publicAccessBlock,omitempty"`
+	ShareLinks        bool     `json:"shareLinks"`
+	MaxConnections    int      `json:"maxConnections"`
+	MaxRequests       int      `json:"maxRequests"`
+}
+
+// AdminConfigReload is the admin json api configuration reload result
+type AdminConfigReload struct {
+	// Applied lists the changed settings applied to the running gateway
+	Applied []string `json:"applied"`
+	// RestartRequired lists the changed settings that take effect
+	// only after the gateway restart
+	RestartRequired []string           `json:"restartRequired"`
+	Config          AdminGatewayConfig `json:"config"`
+}
+
+const (
+	// ReadinessReady is the readiness status of the gateway
+	// ready to serve requests
+	ReadinessReady = "ready"
+	// ReadinessNotReady is the readiness status of the gateway
+	// with failed component checks
+	ReadinessNotReady = "not ready"
+	// ReadinessDraining is the readiness status of the gateway
+	// shutting down
+	ReadinessDraining = "draining"
+
+	// ReadinessComponentOK is the status of the passed component check
+	ReadinessComponentOK = "ok"
+	// ReadinessComponentFailed is the status of the failed or
+	// timed out component check
+	ReadinessComponentFailed = "failed"
+)
+
+// ReadinessStatus is the gateway readiness endpoint response
+type ReadinessStatus struct {
+	Status     string               `json:"status"`
+	Checked    time.Time            `json:"checked"`
+	Components []ReadinessComponent `json:"components"`
+}
+
+// ReadinessComponent is the check result of a gateway component
+type ReadinessComponent struct {
+	Name   string `json:"name"`
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
@@ -0,0 +1,82 @@
+// Copyright 2026 Versity Software
+// This file is licensed under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package integration
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/versity/versitygw/s3err"
+)
+
+func GetPublicAccessBlock_non_existing_bucket(s *S3Conf) error {
+	testName := "GetPublicAccessBlock_non_existing_bucket"
+	return actionHandler(s, testName, func(s3client *s3.Client, bucket string) error {
+		ctx, cancel := context.WithTimeout(context.Background(), shortTimeout)
+		_, err := s3client.GetPublicAccessBlock(ctx, &s3.GetPublicAccessBlockInput{
+			Bucket: getPtr("non-existing-bucket"),
+		})
+		cancel()
+		return checkApiErr(err, s3err.GetAPIError(s3err.ErrNoSuchBucket))
+	})
+}
+
+func GetPublicAccessBlock_no_such_configuration(s *S3Conf) error {
+	testName := "GetPublicAccessBlock_no_such_configuration"
+	return actionHandler(s, testName, func(s3client *s3.Client, bucket string) error {
+		ctx, cancel := context.WithTimeout(context.Background(), shortTimeout)
+		_, err := s3client.GetPublicAccessBlock(ctx, &s3.GetPublicAccessBlockInput{
+			Bucket: &bucket,
+		})
+		cancel()
+		return checkApiErr(err, s3err.GetAPIError(s3err.ErrNoSuchPublicAccessBlockConfiguration))
+	})
+}
+
+func GetPublicAccessBlock_success(s *S3Conf) error {
+	testName := "GetPublicAccessBlock_success"
+	return actionHandler(s, testName, func(s3client *s3.Client, bucket string) error {
+		err := putPublicAccessBlock(s3client, bucket, &types.PublicAccessBlockConfiguration{
+			BlockPublicAcls:       getPtr(true),
+			RestrictPublicBuckets: getPtr(true),
+		})
+		if err != nil {
+			return err
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), shortTimeout)
+		res, err := s3client.GetPublicAccessBlock(ctx, &s3.GetPublicAccessBlockInput{
+			Bucket: &bucket,
+		})
+		cancel()
+		if err != nil {
+			return err
+		}
+
+		cfg := res.PublicAccessBlockConfiguration
+		if cfg == nil {
+			return fmt.Errorf("expected non nil public access block configuration")
+		}
+		isSet := func(b *bool) bool { return b != nil && *b }
+		if !isSet(cfg.BlockPublicAcls) || isSet(cfg.IgnorePublicAcls) ||
+			isSet(cfg.BlockPublicPolicy) || !isSet(cfg.RestrictPublicBuckets) {
+			return fmt.Errorf("expected BlockPublicAcls and RestrictPublicBuckets to be set, instead got %+v", *cfg)
+		}
+
+		return nil
+	})
+}
@@ -510,3 +510,51 @@ func PutBucketAcl_success_grants(s *S3Conf) error {
 		return nil
 	}, withOwnership(types.ObjectOwnershipBucketOwnerPreferred))
 }
+
+func PutBucketAcl_success_canned_authenticated_read(s *S3Conf) error {
+	testName := "PutBucketAcl_success_canned_authenticated_read"
+	return actionHandler(s, testName, func(s3client *s3.Client, bucket string) error {
+		testuser := getUser("user")
+		err := createUsers(s, []user{testuser})
+		if err != nil {
+			return err
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), shortTimeout)
+		_, err = s3client.PutBucketAcl(ctx, &s3.PutBucketAclInput{
+			Bucket: &bucket,
+			ACL:    types.BucketCannedACLAuthenticatedRead,
+		})
+		cancel()
+		if err != nil {
+			return err
+		}
+
+		userClient := s.getUserClient(testuser)
+
+		_, err = putObjects(userClient, []string{"my-obj"}, bucket)
+		if err := checkApiErr(err, s3err.GetAPIError(s3err.ErrAccessDenied)); err != nil {
+			return err
+		}
+
+		ctx, cancel = context.WithTimeout(context.Background(), shortTimeout)
+		_, err = userClient.ListObjects(ctx, &s3.ListObjectsInput{
+			Bucket: &bucket,
+		})
+		cancel()
+		return err
+	}, withOwnership(types.ObjectOwnershipBucketOwnerPreferred))
+}
+
+func PutBucketAcl_unresolvable_email_grantee(s *S3Conf) error {
+	testName := "PutBucketAcl_unresolvable_email_grantee"
+	return actionHandler(s, testName, func(s3client *s3.Client, bucket string) error {
+		ctx, cancel := context.WithTimeout(context.Background(), shortTimeout)
+		_, err := s3client.PutBucketAcl(ctx, &s3.PutBucketAclInput{
+			Bucket:    &bucket,
+			GrantRead: getPtr(`emailAddress="nobody@example.com"`),
+		})
+		cancel()
+		return checkApiErr(err, s3err.GetAPIError(s3err.ErrUnresolvableGrantByEmailAddress))
+	}, withOwnership(types.ObjectOwnershipBucketOwnerPreferred))
+}
@@ -0,0 +1,86 @@
+// Copyright 2026 Versity Software
+// This file is licensed under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package integration
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/versity/versitygw/s3err"
+)
+
+func PutPublicAccessBlock_non_existing_bucket(s *S3Conf) error {
+	testName := "PutPublicAccessBlock_non_existing_bucket"
+	return actionHandler(s, testName, func(s3client *s3.Client, bucket string) error {
+		err := putPublicAccessBlock(s3client, "non-existing-bucket", &types.PublicAccessBlockConfiguration{
+			BlockPublicPolicy: getPtr(true),
+		})
+		return checkApiErr(err, s3err.GetAPIError(s3err.ErrNoSuchBucket))
+	})
+}
+
+func PutPublicAccessBlock_block_public_policy(s *S3Conf) error {
+	testName := "PutPublicAccessBlock_block_public_policy"
+	return actionHandler(s, testName, func(s3client *s3.Client, bucket string) error {
+		err := putPublicAccessBlock(s3client, bucket, &types.PublicAccessBlockConfiguration{
+			BlockPublicPolicy: getPtr(true),
+		})
+		if err != nil {
+			return err
+		}
+
+		resource := fmt.Sprintf(`"arn:aws:s3:::%v/*"`, bucket)
+		err = putBucketPolicy(s3client, bucket, genPolicyDoc("Allow", `"*"`, `"s3:GetObject"`, resource))
+		if err := checkApiErr(err, s3err.GetAPIError(s3err.ErrAccessDenied)); err != nil {
+			return err
+		}
+
+		// the policies not granting public access are allowed
+		return putBucketPolicy(s3client, bucket, genPolicyDoc("Deny", `"*"`, `"s3:GetObject"`, resource))
+	})
+}
+
+func PutPublicAccessBlock_block_public_acls(s *S3Conf) error {
+	testName := "PutPublicAccessBlock_block_public_acls"
+	return actionHandler(s, testName, func(s3client *s3.Client, bucket string) error {
+		err := putPublicAccessBlock(s3client, bucket, &types.PublicAccessBlockConfiguration{
+			BlockPublicAcls: getPtr(true),
+		})
+		if err != nil {
+			return err
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), shortTimeout)
+		_, err = s3client.PutBucketAcl(ctx, &s3.PutBucketAclInput{
+			Bucket: &bucket,
+			ACL:    types.BucketCannedACLPublicRead,
+		})
+		cancel()
+		if err := checkApiErr(err, s3err.GetAPIError(s3err.ErrAccessDenied)); err != nil {
+			return err
+		}
+
+		// the ACLs not granting public access are allowed
+		ctx, cancel = context.WithTimeout(context.Background(), shortTimeout)
+		_, err = s3client.PutBucketAcl(ctx, &s3.PutBucketAclInput{
+			Bucket: &bucket,
+			ACL:    types.BucketCannedACLPrivate,
+		})
+		cancel()
+		return err
+	}, withOwnership(types.ObjectOwnershipBucketOwnerPreferred))
+}
@@ -44,7 +44,7 @@ func TestAuthentication(ts *TestState) {
 func TestPresignedAuthentication(ts *TestState) {
 	ts.Run(PresignedAuth_security_token_not_supported)
 	ts.Run(PresignedAuth_unsupported_algorithm)
-	ts.Run(PresignedAuth_ECDSA_not_supported)
+	ts.Run(PresignedAuth_ECDSA_malformed_credential)
 	ts.Run(PresignedAuth_missing_signature_query_param)
 	ts.Run(PresignedAuth_missing_credentials_query_param)
 	ts.Run(PresignedAuth_malformed_creds_invalid_parts)
@@ -550,6 +550,8 @@ func TestPutBucketAcl(ts *TestState) {
 	ts.Run(PutBucketAcl_success_grants)
 	ts.Run(PutBucketAcl_success_canned_acl)
 	ts.Run(PutBucketAcl_success_acp)
+	ts.Run(PutBucketAcl_success_canned_authenticated_read)
+	ts.Run(PutBucketAcl_unresolvable_email_grantee)
 }
 
 func TestGetBucketAcl(ts *TestState) {
@@ -628,6 +630,23 @@ func TestDeleteBucketCors(ts *TestState) {
 	ts.Run(DeleteBucketCors_success)
 }
 
+func TestPutPublicAccessBlock(ts *TestState) {
+	ts.Run(PutPublicAccessBlock_non_existing_bucket)
+	ts.Run(PutPublicAccessBlock_block_public_policy)
+	ts.Run(PutPublicAccessBlock_block_public_acls)
+}
+
+func TestGetPublicAccessBlock(ts *TestState) {
+	ts.Run(GetPublicAccessBlock_non_existing_bucket)
+	ts.Run(GetPublicAccessBlock_no_such_configuration)
+	ts.Run(GetPublicAccessBlock_success)
+}
+
+func TestDeletePublicAccessBlock(ts *TestState) {
+	ts.Run(DeletePublicAccessBlock_non_existing_bucket)
+	ts.Run(DeletePublicAccessBlock_success)
+}
+
 func TestPreflightOPTIONSEndpoint(ts *TestState) {
 	ts.Run(PreflightOPTIONS_non_existing_bucket)
 	ts.Run(PreflightOPTIONS_missing_origin)
@@ -743,10 +762,6 @@ func TestNotImplementedActions(ts *TestState) {
 	ts.Run(PutBucketReplication_not_implemented)
 	ts.Run(GetBucketReplication_not_implemented)
 	ts.Run(DeleteBucketReplication_not_implemented)
-	// bucket public access block actions
-	ts.Run(PutPublicAccessBlock_not_implemented)
-	ts.Run(GetPublicAccessBlock_not_implemented)
-	ts.Run(DeletePublicAccessBlock_not_implemented)
 	// bucket notification actions
 	ts.Run(PutBucketNotificationConfiguratio_not_implemented)
 	ts.Run(GetBucketNotificationConfiguratio_not_implemented)
@@ -827,6 +842,9 @@ func TestFullFlow(ts *TestState) {
 	TestPutBucketCors(ts)
 	TestGetBucketCors(ts)
 	TestDeleteBucketCors(ts)
+	TestPutPublicAccessBlock(ts)
+	TestGetPublicAccessBlock(ts)
+	TestDeletePublicAccessBlock(ts)
 	TestPreflightOPTIONSEndpoint(ts)
 	TestPutObjectLockConfiguration(ts)
 	TestGetObjectLockConfiguration(ts)
@@ -1223,7 +1241,7 @@ func GetIntTests() IntTests {
 		"Authentication_with_expect_header":                                        Authentication_with_expect_header,
 		"PresignedAuth_security_token_not_supported":                               PresignedAuth_security_token_not_supported,
 		"PresignedAuth_unsupported_algorithm":                                      PresignedAuth_unsupported_algorithm,
-		"PresignedAuth_ECDSA_not_supported":                                        PresignedAuth_ECDSA_not_supported,
+		"PresignedAuth_ECDSA_malformed_credential":                                 PresignedAuth_ECDSA_malformed_credential,
 		"PresignedAuth_missing_signature_query_param":                              PresignedAuth_missing_signature_query_param,
 		"PresignedAuth_missing_credentials_query_param":                            PresignedAuth_missing_credentials_query_param,
 		"PresignedAuth_malformed_creds_invalid_parts":                              PresignedAuth_malformed_creds_invalid_parts,
@@ -1579,6 +1597,8 @@ func GetIntTests() IntTests {
 		"PutBucketAcl_success_access_denied":                                       PutBucketAcl_success_access_denied,
 		"PutBucketAcl_success_grants":                                              PutBucketAcl_success_grants,
 		"PutBucketAcl_success_canned_acl":                                          PutBucketAcl_success_canned_acl,
+		"PutBucketAcl_success_canned_authenticated_read":                           PutBucketAcl_success_canned_authenticated_read,
+		"PutBucketAcl_unresolvable_email_grantee":                                  PutBucketAcl_unresolvable_email_grantee,
 		"PutBucketAcl_success_acp":                                                 PutBucketAcl_success_acp,
 		"GetBucketAcl_non_existing_bucket":                                         GetBucketAcl_non_existing_bucket,
 		"GetBucketAcl_translation_canned_public_read":                              GetBucketAcl_translation_canned_public_read,
@@ -1631,6 +1651,14 @@ func GetIntTests() IntTests {
 		"GetBucketCors_success":                                                    GetBucketCors_success,
 		"DeleteBucketCors_non_existing_bucket":                                     DeleteBucketCors_non_existing_bucket,
 		"DeleteBucketCors_success":                                                 DeleteBucketCors_success,
+		"PutPublicAccessBlock_non_existing_bucket":                                 PutPublicAccessBlock_non_existing_bucket,
+		"PutPublicAccessBlock_block_public_policy":                                 PutPublicAccessBlock_block_public_policy,
+		"PutPublicAccessBlock_block_public_acls":                                   PutPublicAccessBlock_block_public_acls,
+		"GetPublicAccessBlock_non_existing_bucket":                                 GetPublicAccessBlock_non_existing_bucket,
+		"GetPublicAccessBlock_no_such_configuration":                               GetPublicAccessBlock_no_such_configuration,
+		"GetPublicAccessBlock_success":                                             GetPublicAccessBlock_success,
+		"DeletePublicAccessBlock_non_existing_bucket":                              DeletePublicAccessBlock_non_existing_bucket,
+		"DeletePublicAccessBlock_success":                                          DeletePublicAccessBlock_success,
 		"PutBucketCors_success":                                                    PutBucketCors_success,
 		"PreflightOPTIONS_non_existing_bucket":                                     PreflightOPTIONS_non_existing_bucket,
 		"PreflightOPTIONS_missing_origin":                                          PreflightOPTIONS_missing_origin,
@@ -1711,9 +1739,6 @@ func GetIntTests() IntTests {
 		"PutBucketReplication_not_implemented":                                     PutBucketReplication_not_implemented,
 		"GetBucketReplication_not_implemented":                                     GetBucketReplication_not_implemented,
 		"DeleteBucketReplication_not_implemented":                                  DeleteBucketReplication_not_implemented,
-		"PutPublicAccessBlock_not_implemented":                                     PutPublicAccessBlock_not_implemented,
-		"GetPublicAccessBlock_not_implemented":                                     GetPublicAccessBlock_not_implemented,
-		"DeletePublicAccessBlock_not_implemented":                                  DeletePublicAccessBlock_not_implemented,
 		"PutBucketNotificationConfiguratio_not_implemented":                        PutBucketNotificationConfiguratio_not_implemented,
 		"GetBucketNotificationConfiguratio_not_implemented":                        GetBucketNotificationConfiguratio_not_implemented,
 		"PutBucketAccelerateConfiguration_not_implemented":                         PutBucketAccelerateConfiguration_not_implemented,
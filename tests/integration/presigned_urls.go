@@ -79,8 +79,8 @@ func PresignedAuth_unsupported_algorithm(s *S3Conf) error {
 	})
 }
 
-func PresignedAuth_ECDSA_not_supported(s *S3Conf) error {
-	testName := "PresignedAuth_ECDSA_not_supported"
+func PresignedAuth_ECDSA_malformed_credential(s *S3Conf) error {
+	testName := "PresignedAuth_ECDSA_malformed_credential"
 	return presignedAuthHandler(s, testName, func(client *s3.PresignClient, bucket string) error {
 		ctx, cancel := context.WithTimeout(context.Background(), shortTimeout)
 		v4req, err := client.PresignDeleteBucket(ctx, &s3.DeleteBucketInput{Bucket: &bucket})
@@ -101,7 +101,7 @@ func PresignedAuth_ECDSA_not_supported(s *S3Conf) error {
 			return err
 		}
 
-		return checkHTTPResponseApiErr(resp, s3err.QueryAuthErrors.OnlyHMACSupported())
+		return checkHTTPResponseApiErr(resp, s3err.QueryAuthErrors.MalformedCredentialV4A())
 	})
 }
 
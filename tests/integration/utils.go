@@ -2043,6 +2043,16 @@ type mpinfo struct {
 	parts    []types.CompletedPart
 }
 
+func putPublicAccessBlock(client *s3.Client, bucket string, cfg *types.PublicAccessBlockConfiguration) error {
+	ctx, cancel := context.WithTimeout(context.Background(), shortTimeout)
+	_, err := client.PutPublicAccessBlock(ctx, &s3.PutPublicAccessBlockInput{
+		Bucket:                         &bucket,
+		PublicAccessBlockConfiguration: cfg,
+	})
+	cancel()
+	return err
+}
+
 func putBucketPolicy(client *s3.Client, bucket, policy string) error {
 	ctx, cancel := context.WithTimeout(context.Background(), shortTimeout)
 	_, err := client.PutBucketPolicy(ctx, &s3.PutBucketPolicyInput{